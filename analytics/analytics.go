@@ -0,0 +1,66 @@
+package analytics
+
+import (
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/clock"
+)
+
+// DefaultTop is the number of triggers in each top list of report if other is not specified.
+const DefaultTop = 10
+
+// Options describe the scope of report.
+type Options struct {
+	// From and To are bounds of report time range in unix seconds.
+	From int64
+	To   int64
+	// Top is the maximum number of triggers in each top list.
+	Top int
+	// TeamID limits report to triggers and contacts of the team, all triggers and contacts are used if it is empty.
+	TeamID string
+}
+
+// Report contains statistics of triggers and notifications over time range.
+type Report struct {
+	From          int64                `json:"from" example:"1590741878" format:"int64"`
+	To            int64                `json:"to" example:"1591346678" format:"int64"`
+	TeamID        string               `json:"team_id,omitempty"`
+	Triggers      *TriggersReport      `json:"triggers"`
+	Notifications *NotificationsReport `json:"notifications"`
+}
+
+// Builder calculates reports from trigger events and notification history stored in database.
+// Trigger events are kept for 30 days and notification history for notification_history_ttl,
+// so older parts of time range are not taken into account.
+type Builder struct {
+	database moira.Database
+	clock    moira.Clock
+}
+
+// NewBuilder creates report builder.
+func NewBuilder(database moira.Database) *Builder {
+	return &Builder{
+		database: database,
+		clock:    clock.NewSystemClock(),
+	}
+}
+
+// BuildReport calculates both triggers and notifications reports.
+func (builder *Builder) BuildReport(options Options) (*Report, error) {
+	triggers, err := builder.BuildTriggersReport(options)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications, err := builder.BuildNotificationsReport(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{
+		From:          options.From,
+		To:            options.To,
+		TeamID:        options.TeamID,
+		Triggers:      triggers,
+		Notifications: notifications,
+	}, nil
+}
@@ -0,0 +1,137 @@
+package analytics
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"gopkg.in/gomail.v2"
+)
+
+// MailConfig contains settings of SMTP server and recipients of reports.
+type MailConfig struct {
+	From        string
+	To          []string
+	SMTPHello   string
+	SMTPHost    string
+	SMTPPort    int
+	SMTPUser    string
+	SMTPPass    string
+	InsecureTLS bool
+	// FrontURI is used to build links to triggers.
+	FrontURI string
+}
+
+// MailSink sends reports by email.
+type MailSink struct {
+	config      MailConfig
+	dialAndSend func(message *gomail.Message) error
+}
+
+// NewMailSink creates MailSink.
+func NewMailSink(config MailConfig) *MailSink {
+	if config.SMTPUser == "" {
+		config.SMTPUser = config.From
+	}
+
+	sink := &MailSink{config: config}
+	sink.dialAndSend = sink.sendMessage
+	return sink
+}
+
+// Send renders report as html and sends it to all recipients.
+func (sink *MailSink) Send(report *Report) error {
+	var body bytes.Buffer
+	if err := mailTemplate.Execute(&body, mailTemplateData{Report: report, FrontURI: strings.TrimSuffix(sink.config.FrontURI, "/")}); err != nil {
+		return fmt.Errorf("failed to render analytics report: %w", err)
+	}
+
+	message := gomail.NewMessage()
+	message.SetHeader("From", sink.config.From)
+	message.SetHeader("To", sink.config.To...)
+	message.SetHeader("Subject", fmt.Sprintf("Moira alerting report %s - %s", formatDate(report.From), formatDate(report.To)))
+	message.SetBody("text/html", body.String())
+
+	return sink.dialAndSend(message)
+}
+
+func (sink *MailSink) sendMessage(message *gomail.Message) error {
+	dialer := gomail.Dialer{
+		Host:      sink.config.SMTPHost,
+		Port:      sink.config.SMTPPort,
+		LocalName: sink.config.SMTPHello,
+		TLSConfig: &tls.Config{
+			InsecureSkipVerify: sink.config.InsecureTLS,
+			ServerName:         sink.config.SMTPHost,
+		},
+	}
+	if sink.config.SMTPPass != "" {
+		dialer.Auth = smtp.PlainAuth("", sink.config.SMTPUser, sink.config.SMTPPass, sink.config.SMTPHost)
+	}
+	if err := dialer.DialAndSend(message); err != nil {
+		return fmt.Errorf("failed to send analytics report by email: %w", err)
+	}
+	return nil
+}
+
+type mailTemplateData struct {
+	*Report
+	FrontURI string
+}
+
+func formatDate(timestamp int64) string {
+	return time.Unix(timestamp, 0).UTC().Format("2006-01-02 15:04")
+}
+
+func formatDuration(seconds int64) string {
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+var mailTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date":     formatDate,
+	"duration": formatDuration,
+}).Parse(`<html><body>
+<h2>Moira alerting report {{ date .From }} - {{ date .To }} UTC</h2>
+{{ with .Triggers }}
+<h3>Triggers</h3>
+<p>Triggers: {{ .Triggers }}, events: {{ .Events }}, problems: {{ .Problems }}, recoveries: {{ .Recoveries }}, mean time to recovery: {{ duration .MTTR }}</p>
+{{ if .StateDurations }}<table border="1" cellpadding="4"><tr><th>State</th><th>Time</th></tr>
+{{ range $state, $duration := .StateDurations }}<tr><td>{{ $state }}</td><td>{{ duration $duration }}</td></tr>
+{{ end }}</table>{{ end }}
+{{ if .Noisiest }}<h4>Noisiest triggers</h4>
+<table border="1" cellpadding="4"><tr><th>Trigger</th><th>Events</th><th>Problems</th><th>MTTR</th></tr>
+{{ range .Noisiest }}<tr><td><a href="{{ $.FrontURI }}/trigger/{{ .TriggerID }}">{{ .Name }}</a></td><td>{{ .Events }}</td><td>{{ .Problems }}</td><td>{{ duration .MTTR }}</td></tr>
+{{ end }}</table>{{ end }}
+{{ if .Flapping }}<h4>Flapping triggers</h4>
+<table border="1" cellpadding="4"><tr><th>Trigger</th><th>Problems</th><th>Recoveries</th><th>MTTR</th></tr>
+{{ range .Flapping }}<tr><td><a href="{{ $.FrontURI }}/trigger/{{ .TriggerID }}">{{ .Name }}</a></td><td>{{ .Problems }}</td><td>{{ .Recoveries }}</td><td>{{ duration .MTTR }}</td></tr>
+{{ end }}</table>{{ end }}
+{{ if .SlowestRecovery }}<h4>Slowest recovery</h4>
+<table border="1" cellpadding="4"><tr><th>Trigger</th><th>MTTR</th><th>Recoveries</th></tr>
+{{ range .SlowestRecovery }}<tr><td><a href="{{ $.FrontURI }}/trigger/{{ .TriggerID }}">{{ .Name }}</a></td><td>{{ duration .MTTR }}</td><td>{{ .Recoveries }}</td></tr>
+{{ end }}</table>{{ end }}
+{{ if .NeverFired }}<h4>Triggers which never fired ({{ len .NeverFired }})</h4>
+<ul>{{ range .NeverFired }}<li><a href="{{ $.FrontURI }}/trigger/{{ .TriggerID }}">{{ .Name }}</a></li>
+{{ end }}</ul>{{ end }}
+{{ end }}
+{{ with .Notifications }}
+<h3>Notifications</h3>
+<p>Notifications sent: {{ .Total }}</p>
+{{ if .Senders }}<table border="1" cellpadding="4"><tr><th>Sender</th><th>Notifications</th></tr>
+{{ range $sender, $count := .Senders }}<tr><td>{{ $sender }}</td><td>{{ $count }}</td></tr>
+{{ end }}</table>{{ end }}
+{{ if .Teams }}<h4>Teams</h4>
+<table border="1" cellpadding="4"><tr><th>Team</th><th>Notifications</th></tr>
+{{ range $team, $count := .Teams }}<tr><td>{{ $team }}</td><td>{{ $count }}</td></tr>
+{{ end }}</table>{{ end }}
+{{ if .Contacts }}<h4>Contacts</h4>
+<table border="1" cellpadding="4"><tr><th>Contact</th><th>Type</th><th>Owner</th><th>Notifications</th></tr>
+{{ range .Contacts }}<tr><td>{{ if .Name }}{{ .Name }}{{ else }}{{ .ContactID }}{{ end }}</td><td>{{ .Type }}</td><td>{{ if .TeamID }}{{ .TeamID }}{{ else }}{{ .User }}{{ end }}</td><td>{{ .Notifications }}</td></tr>
+{{ end }}</table>{{ end }}
+{{ end }}
+</body></html>
+`))
@@ -0,0 +1,117 @@
+package analytics
+
+import (
+	"sort"
+
+	"github.com/moira-alert/moira"
+)
+
+// ContactStats contains the number of notifications sent to contact.
+type ContactStats struct {
+	ContactID     string `json:"contact_id" example:"1dd38765-c5be-418d-81fa-7a5f879c2315"`
+	Type          string `json:"type" example:"mail"`
+	Name          string `json:"name,omitempty" example:"Mail Alerts"`
+	User          string `json:"user,omitempty"`
+	TeamID        string `json:"team_id,omitempty"`
+	Notifications int    `json:"notifications" example:"42"`
+}
+
+// NotificationsReport contains statistics of notifications sent over time range.
+type NotificationsReport struct {
+	From  int64 `json:"from" example:"1590741878" format:"int64"`
+	To    int64 `json:"to" example:"1591346678" format:"int64"`
+	Total int   `json:"total" example:"420"`
+	// Contacts are sorted by the number of notifications, contacts without notifications are omitted.
+	Contacts []ContactStats `json:"contacts"`
+	// Teams contains the number of notifications sent to contacts of each team.
+	Teams map[string]int `json:"teams"`
+	// Senders contains the number of notifications sent by each contact type.
+	Senders map[string]int `json:"senders"`
+}
+
+// BuildNotificationsReport counts notifications sent to contacts.
+func (builder *Builder) BuildNotificationsReport(options Options) (*NotificationsReport, error) {
+	contacts, err := builder.getContacts(options.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := builder.database.GetNotificationsHistory(options.From, options.To)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &NotificationsReport{
+		From:     options.From,
+		To:       options.To,
+		Contacts: make([]ContactStats, 0),
+		Teams:    make(map[string]int),
+		Senders:  make(map[string]int),
+	}
+
+	contactsStats := make(map[string]*ContactStats)
+	for _, item := range history {
+		contact, ok := contacts[item.ContactID]
+		if !ok && options.TeamID != "" {
+			continue
+		}
+
+		stats, ok := contactsStats[item.ContactID]
+		if !ok {
+			stats = &ContactStats{ContactID: item.ContactID}
+			if contact != nil {
+				stats.Type = contact.Type
+				stats.Name = contact.Name
+				stats.User = contact.User
+				stats.TeamID = contact.Team
+			}
+			contactsStats[item.ContactID] = stats
+		}
+
+		stats.Notifications++
+		report.Total++
+		if stats.TeamID != "" {
+			report.Teams[stats.TeamID]++
+		}
+		if stats.Type != "" {
+			report.Senders[stats.Type]++
+		}
+	}
+
+	for _, stats := range contactsStats {
+		report.Contacts = append(report.Contacts, *stats)
+	}
+	sort.Slice(report.Contacts, func(i, j int) bool {
+		if report.Contacts[i].Notifications != report.Contacts[j].Notifications {
+			return report.Contacts[i].Notifications > report.Contacts[j].Notifications
+		}
+		return report.Contacts[i].ContactID < report.Contacts[j].ContactID
+	})
+	return report, nil
+}
+
+func (builder *Builder) getContacts(teamID string) (map[string]*moira.ContactData, error) {
+	var contacts []*moira.ContactData
+	if teamID != "" {
+		contactIDs, err := builder.database.GetTeamContactIDs(teamID)
+		if err != nil {
+			return nil, err
+		}
+		if contacts, err = builder.database.GetContacts(contactIDs); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if contacts, err = builder.database.GetAllContacts(); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make(map[string]*moira.ContactData, len(contacts))
+	for _, contact := range contacts {
+		if contact != nil {
+			result[contact.ID] = contact
+		}
+	}
+	return result, nil
+}
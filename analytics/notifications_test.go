@@ -0,0 +1,59 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBuildNotificationsReport(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	builder := NewBuilder(dataBase)
+
+	userContact := &moira.ContactData{ID: "user-contact", Type: "mail", User: "user"}
+	teamContact := &moira.ContactData{ID: "team-contact", Type: "slack", Name: "Alerts", Team: "team"}
+	history := []*moira.NotificationEventHistoryItem{
+		{ContactID: "user-contact", TriggerID: "trigger"},
+		{ContactID: "team-contact", TriggerID: "trigger"},
+		{ContactID: "team-contact", TriggerID: "trigger"},
+		{ContactID: "removed", TriggerID: "trigger"},
+	}
+
+	Convey("Test build notifications report", t, func() {
+		Convey("All contacts", func() {
+			dataBase.EXPECT().GetAllContacts().Return([]*moira.ContactData{userContact, teamContact, nil}, nil)
+			dataBase.EXPECT().GetNotificationsHistory(int64(1000), int64(2000)).Return(history, nil)
+
+			report, err := builder.BuildNotificationsReport(Options{From: 1000, To: 2000})
+			So(err, ShouldBeNil)
+			So(report, ShouldResemble, &NotificationsReport{
+				From:  1000,
+				To:    2000,
+				Total: 4,
+				Contacts: []ContactStats{
+					{ContactID: "team-contact", Type: "slack", Name: "Alerts", TeamID: "team", Notifications: 2},
+					{ContactID: "removed", Notifications: 1},
+					{ContactID: "user-contact", Type: "mail", User: "user", Notifications: 1},
+				},
+				Teams:   map[string]int{"team": 2},
+				Senders: map[string]int{"slack": 2, "mail": 1},
+			})
+		})
+
+		Convey("Team contacts", func() {
+			dataBase.EXPECT().GetTeamContactIDs("team").Return([]string{"team-contact"}, nil)
+			dataBase.EXPECT().GetContacts([]string{"team-contact"}).Return([]*moira.ContactData{teamContact}, nil)
+			dataBase.EXPECT().GetNotificationsHistory(int64(1000), int64(2000)).Return(history, nil)
+
+			report, err := builder.BuildNotificationsReport(Options{From: 1000, To: 2000, TeamID: "team"})
+			So(err, ShouldBeNil)
+			So(report.Total, ShouldEqual, 2)
+			So(report.Contacts, ShouldHaveLength, 1)
+		})
+	})
+}
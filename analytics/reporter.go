@@ -0,0 +1,153 @@
+package analytics
+
+import (
+	"time"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/clock"
+	w "github.com/moira-alert/moira/worker"
+)
+
+const (
+	reporterLockName      = "moira-analytics-reporter"
+	reporterLockTTL       = time.Second * 15
+	reporterCheckInterval = time.Minute
+)
+
+// Sink is a destination scheduled reports are sent to.
+type Sink interface {
+	Send(report *Report) error
+}
+
+// ReporterConfig contains schedule of analytics reports.
+type ReporterConfig struct {
+	Enabled bool
+	// Weekday, Hour and Location define the moment report is sent at every week.
+	Weekday  time.Weekday
+	Hour     int
+	Location *time.Location
+	// Period is the time range before the moment report is sent that is covered by report.
+	Period time.Duration
+	// Top is the maximum number of triggers in each top list.
+	Top int
+}
+
+// Reporter sends analytics report to sinks once a week.
+// Time of the last sent report is saved to database, so report is sent once even if notifier is restarted.
+type Reporter struct {
+	logger   moira.Logger
+	database moira.Database
+	builder  *Builder
+	clock    moira.Clock
+	config   ReporterConfig
+	sinks    []Sink
+	tomb     tomb.Tomb
+}
+
+// NewReporter creates Reporter which sends reports to given sinks.
+func NewReporter(database moira.Database, logger moira.Logger, config ReporterConfig, sinks ...Sink) *Reporter {
+	return &Reporter{
+		logger:   logger,
+		database: database,
+		builder:  NewBuilder(database),
+		clock:    clock.NewSystemClock(),
+		config:   config,
+		sinks:    sinks,
+	}
+}
+
+// Start reporter worker.
+func (reporter *Reporter) Start() {
+	reporter.tomb.Go(func() error {
+		w.NewWorker(
+			"Moira Analytics Reporter",
+			reporter.logger,
+			reporter.database.NewLock(reporterLockName, reporterLockTTL),
+			reporter.reportSender,
+		).Run(reporter.tomb.Dying())
+		return nil
+	})
+}
+
+// Stop reporter worker and wait for finish.
+func (reporter *Reporter) Stop() error {
+	reporter.tomb.Kill(nil)
+	return reporter.tomb.Wait()
+}
+
+func (reporter *Reporter) reportSender(stop <-chan struct{}) error {
+	reporter.logger.Info().Msg("Moira Analytics Reporter started")
+
+	checkTicker := time.NewTicker(reporterCheckInterval)
+	defer checkTicker.Stop()
+
+	for {
+		reporter.checkReport()
+
+		select {
+		case <-stop:
+			reporter.logger.Info().Msg("Moira Analytics Reporter stopped")
+			return nil
+		case <-checkTicker.C:
+		}
+	}
+}
+
+// checkReport sends report if it was not sent at the last scheduled moment.
+func (reporter *Reporter) checkReport() {
+	scheduledAt := reporter.getLastScheduledTime(reporter.clock.Now()).Unix()
+	sentAt, err := reporter.database.GetAnalyticsReportSentAt()
+	if err != nil {
+		reporter.logger.Error().
+			Error(err).
+			Msg("Failed to get time of the last analytics report")
+		return
+	}
+	if sentAt >= scheduledAt {
+		return
+	}
+
+	report, err := reporter.builder.BuildReport(Options{
+		From: scheduledAt - int64(reporter.config.Period.Seconds()),
+		To:   scheduledAt,
+		Top:  reporter.config.Top,
+	})
+	if err != nil {
+		reporter.logger.Error().
+			Error(err).
+			Msg("Failed to build analytics report")
+		return
+	}
+
+	// Report is not resent if some sinks failed, otherwise other sinks would get it again
+	for _, sink := range reporter.sinks {
+		if err := sink.Send(report); err != nil {
+			reporter.logger.Error().
+				Error(err).
+				Msg("Failed to send analytics report")
+		}
+	}
+
+	if err := reporter.database.SetAnalyticsReportSentAt(scheduledAt); err != nil {
+		reporter.logger.Error().
+			Error(err).
+			Msg("Failed to save time of the last analytics report")
+	}
+}
+
+// getLastScheduledTime returns the latest moment before now that matches report schedule.
+func (reporter *Reporter) getLastScheduledTime(now time.Time) time.Time {
+	location := reporter.config.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	now = now.In(location)
+	scheduled := time.Date(now.Year(), now.Month(), now.Day(), reporter.config.Hour, 0, 0, 0, location)
+	for scheduled.Weekday() != reporter.config.Weekday || scheduled.After(now) {
+		scheduled = scheduled.AddDate(0, 0, -1)
+	}
+	return scheduled
+}
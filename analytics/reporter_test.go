@@ -0,0 +1,92 @@
+package analytics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_clock "github.com/moira-alert/moira/mock/clock"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testSink struct {
+	reports []*Report
+	err     error
+}
+
+func (sink *testSink) Send(report *Report) error {
+	sink.reports = append(sink.reports, report)
+	return sink.err
+}
+
+func TestGetLastScheduledTime(t *testing.T) {
+	Convey("Test get last scheduled time", t, func() {
+		location, _ := time.LoadLocation("Europe/Berlin")
+		reporter := &Reporter{config: ReporterConfig{Weekday: time.Monday, Hour: 9, Location: location}}
+
+		Convey("Before scheduled hour report of previous week is expected", func() {
+			now := time.Date(2023, 6, 12, 8, 0, 0, 0, location)
+			So(reporter.getLastScheduledTime(now), ShouldEqual, time.Date(2023, 6, 5, 9, 0, 0, 0, location))
+		})
+
+		Convey("After scheduled hour report of the same day is expected", func() {
+			now := time.Date(2023, 6, 12, 9, 30, 0, 0, location)
+			So(reporter.getLastScheduledTime(now), ShouldEqual, time.Date(2023, 6, 12, 9, 0, 0, 0, location))
+		})
+
+		Convey("In the middle of week report of last monday is expected", func() {
+			now := time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+			So(reporter.getLastScheduledTime(now), ShouldEqual, time.Date(2023, 6, 12, 9, 0, 0, 0, location))
+		})
+	})
+}
+
+func TestCheckReport(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	systemClock := mock_clock.NewMockClock(mockCtrl)
+	logger, _ := logging.GetLogger("Analytics")
+
+	now := time.Date(2023, 6, 12, 10, 0, 0, 0, time.UTC)
+	scheduledAt := time.Date(2023, 6, 12, 9, 0, 0, 0, time.UTC).Unix()
+	period := 7 * 24 * time.Hour
+	systemClock.EXPECT().Now().Return(now).AnyTimes()
+
+	Convey("Test check report", t, func() {
+		sink := &testSink{err: fmt.Errorf("error")}
+		reporter := NewReporter(dataBase, logger, ReporterConfig{Weekday: time.Monday, Hour: 9, Period: period, Top: 5}, sink)
+		reporter.clock = systemClock
+		reporter.builder.clock = systemClock
+
+		Convey("Report is sent once", func() {
+			dataBase.EXPECT().GetAnalyticsReportSentAt().Return(scheduledAt-int64(period.Seconds()), nil)
+			dataBase.EXPECT().GetAllTriggerIDs().Return([]string{}, nil)
+			dataBase.EXPECT().GetTriggerChecks([]string{}).Return([]*moira.TriggerCheck{}, nil)
+			dataBase.EXPECT().GetAllContacts().Return([]*moira.ContactData{}, nil)
+			dataBase.EXPECT().GetNotificationsHistory(scheduledAt-int64(period.Seconds()), scheduledAt).Return(nil, nil)
+			dataBase.EXPECT().SetAnalyticsReportSentAt(scheduledAt).Return(nil)
+
+			reporter.checkReport()
+			So(sink.reports, ShouldHaveLength, 1)
+			So(sink.reports[0].From, ShouldEqual, scheduledAt-int64(period.Seconds()))
+			So(sink.reports[0].To, ShouldEqual, scheduledAt)
+
+			dataBase.EXPECT().GetAnalyticsReportSentAt().Return(scheduledAt, nil)
+			reporter.checkReport()
+			So(sink.reports, ShouldHaveLength, 1)
+		})
+
+		Convey("Report is not sent if it can not be built", func() {
+			dataBase.EXPECT().GetAnalyticsReportSentAt().Return(int64(0), nil)
+			dataBase.EXPECT().GetAllTriggerIDs().Return(nil, fmt.Errorf("error"))
+
+			reporter.checkReport()
+			So(sink.reports, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,76 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/gomail.v2"
+)
+
+var testReport = &Report{
+	From: 1686441600,
+	To:   1687046400,
+	Triggers: &TriggersReport{
+		Triggers:       1,
+		StateDurations: map[moira.State]int64{moira.StateERROR: 60},
+		Noisiest:       []TriggerStats{{TriggerInfo: TriggerInfo{TriggerID: "trigger", Name: "Disk <full>"}, Events: 3}},
+		NeverFired:     []TriggerInfo{},
+	},
+	Notifications: &NotificationsReport{
+		Total:    1,
+		Contacts: []ContactStats{{ContactID: "contact", Type: "mail", Notifications: 1}},
+		Senders:  map[string]int{"mail": 1},
+	},
+}
+
+func TestWebhookSink(t *testing.T) {
+	Convey("Test webhook sink", t, func() {
+		var received Report
+		status := http.StatusOK
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			json.NewDecoder(request.Body).Decode(&received) //nolint
+			writer.WriteHeader(status)
+		}))
+		defer server.Close()
+		sink := NewWebhookSink(server.URL, time.Second)
+
+		Convey("Report is sent", func() {
+			So(sink.Send(testReport), ShouldBeNil)
+			So(received.Triggers.Noisiest, ShouldHaveLength, 1)
+			So(received.Notifications.Total, ShouldEqual, 1)
+		})
+
+		Convey("Bad status is error", func() {
+			status = http.StatusBadGateway
+			So(sink.Send(testReport).Error(), ShouldEqual, "analytics webhook responded with status 502")
+		})
+	})
+}
+
+func TestMailSink(t *testing.T) {
+	Convey("Test mail sink", t, func() {
+		var sent *gomail.Message
+		sink := NewMailSink(MailConfig{From: "moira@example.com", To: []string{"sre@example.com"}, FrontURI: "https://moira.example.com/"})
+		sink.dialAndSend = func(message *gomail.Message) error {
+			sent = message
+			return nil
+		}
+
+		So(sink.Send(testReport), ShouldBeNil)
+		So(sent.GetHeader("To"), ShouldResemble, []string{"sre@example.com"})
+		So(sent.GetHeader("Subject"), ShouldResemble, []string{"Moira alerting report 2023-06-11 00:00 - 2023-06-18 00:00"})
+
+		var body bytes.Buffer
+		err := mailTemplate.Execute(&body, mailTemplateData{Report: testReport, FrontURI: "https://moira.example.com"})
+		So(err, ShouldBeNil)
+		content := body.String()
+		So(content, ShouldContainSubstring, `<a href="https://moira.example.com/trigger/trigger">Disk &lt;full&gt;</a>`)
+		So(content, ShouldContainSubstring, "<td>ERROR</td><td>1m0s</td>")
+	})
+}
@@ -0,0 +1,294 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/moira-alert/moira"
+)
+
+// TriggerInfo identifies trigger in report.
+type TriggerInfo struct {
+	TriggerID string `json:"trigger_id" example:"292516ed-4924-4154-a62c-ebe312431fce"`
+	Name      string `json:"name" example:"Not enough disk space left"`
+	TeamID    string `json:"team_id,omitempty"`
+}
+
+// TriggerStats contains statistics of trigger over report time range.
+type TriggerStats struct {
+	TriggerInfo
+	// Events is the number of state changes of trigger metrics.
+	Events int `json:"events" example:"42"`
+	// Problems is the number of times trigger state changed from OK to bad one.
+	Problems int `json:"problems" example:"5"`
+	// Recoveries is the number of times trigger state returned to OK after problem started within time range.
+	Recoveries int `json:"recoveries" example:"4"`
+	// MTTR is mean time to recovery in seconds.
+	MTTR int64 `json:"mttr" example:"900" format:"int64"`
+	// StateDurations contains seconds trigger spent in each state.
+	StateDurations map[moira.State]int64 `json:"state_durations"`
+
+	recoveryTime int64
+}
+
+// TriggersReport contains statistics of triggers over time range.
+type TriggersReport struct {
+	From int64 `json:"from" example:"1590741878" format:"int64"`
+	To   int64 `json:"to" example:"1591346678" format:"int64"`
+	// Triggers is the number of triggers in report.
+	Triggers   int   `json:"triggers" example:"100"`
+	Events     int   `json:"events" example:"420"`
+	Problems   int   `json:"problems" example:"50"`
+	Recoveries int   `json:"recoveries" example:"40"`
+	MTTR       int64 `json:"mttr" example:"900" format:"int64"`
+	// StateDurations contains seconds all triggers spent in each state.
+	StateDurations map[moira.State]int64 `json:"state_durations"`
+	// Noisiest are triggers with the most events.
+	Noisiest []TriggerStats `json:"noisiest"`
+	// Flapping are triggers which most often changed state from OK to bad one.
+	Flapping []TriggerStats `json:"flapping"`
+	// SlowestRecovery are triggers with the biggest mean time to recovery.
+	SlowestRecovery []TriggerStats `json:"slowest_recovery"`
+	// NeverFired are triggers which were in OK state during the whole time range.
+	NeverFired []TriggerInfo `json:"never_fired"`
+}
+
+var statesSeverity = map[moira.State]int{
+	moira.StateOK:        1,
+	moira.StateWARN:      2,
+	moira.StateERROR:     3,
+	moira.StateNODATA:    4,
+	moira.StateEXCEPTION: 5,
+}
+
+// BuildTriggersReport calculates statistics of trigger events.
+// Trigger state at any moment is the worst state of its metrics,
+// metrics which had no events in time range are considered to be in the state of the last check.
+func (builder *Builder) BuildTriggersReport(options Options) (*TriggersReport, error) {
+	triggerIDs, err := builder.getTriggerIDs(options.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	checks, err := builder.database.GetTriggerChecks(triggerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := builder.clock.Now().Unix()
+	stats := make([]TriggerStats, 0, len(checks))
+	for _, check := range checks {
+		if check == nil {
+			continue
+		}
+		// Events after the end of time range are used to find states of metrics at its end
+		events, err := builder.database.GetNotificationEventsByTime(check.ID, options.From, max(options.To, now))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get events of trigger %s: %w", check.ID, err)
+		}
+		stats = append(stats, getTriggerStats(check, events, options.From, options.To))
+	}
+
+	return newTriggersReport(stats, options), nil
+}
+
+func (builder *Builder) getTriggerIDs(teamID string) ([]string, error) {
+	if teamID != "" {
+		return builder.database.GetTeamTriggerIDs(teamID)
+	}
+	return builder.database.GetAllTriggerIDs()
+}
+
+func newTriggersReport(stats []TriggerStats, options Options) *TriggersReport {
+	report := &TriggersReport{
+		From:           options.From,
+		To:             options.To,
+		Triggers:       len(stats),
+		StateDurations: make(map[moira.State]int64),
+		NeverFired:     make([]TriggerInfo, 0),
+	}
+
+	var recoveryTime int64
+	for _, triggerStats := range stats {
+		report.Events += triggerStats.Events
+		report.Problems += triggerStats.Problems
+		report.Recoveries += triggerStats.Recoveries
+		recoveryTime += triggerStats.recoveryTime
+		for state, duration := range triggerStats.StateDurations {
+			report.StateDurations[state] += duration
+		}
+		if triggerStats.Events == 0 && isAlwaysOK(triggerStats.StateDurations) {
+			report.NeverFired = append(report.NeverFired, triggerStats.TriggerInfo)
+		}
+	}
+	if report.Recoveries > 0 {
+		report.MTTR = recoveryTime / int64(report.Recoveries)
+	}
+	sort.Slice(report.NeverFired, func(i, j int) bool {
+		return report.NeverFired[i].Name < report.NeverFired[j].Name
+	})
+
+	top := options.Top
+	if top <= 0 {
+		top = DefaultTop
+	}
+	report.Noisiest = getTopTriggers(stats, top, func(stats *TriggerStats) int64 { return int64(stats.Events) })
+	report.Flapping = getTopTriggers(stats, top, func(stats *TriggerStats) int64 { return int64(stats.Problems) })
+	report.SlowestRecovery = getTopTriggers(stats, top, func(stats *TriggerStats) int64 { return stats.MTTR })
+	return report
+}
+
+// getTopTriggers returns at most top triggers with the biggest positive values, triggers with equal values are sorted by name.
+func getTopTriggers(stats []TriggerStats, top int, value func(stats *TriggerStats) int64) []TriggerStats {
+	result := make([]TriggerStats, 0)
+	for i := range stats {
+		if value(&stats[i]) > 0 {
+			result = append(result, stats[i])
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if value(&result[i]) != value(&result[j]) {
+			return value(&result[i]) > value(&result[j])
+		}
+		return result[i].Name < result[j].Name
+	})
+	if len(result) > top {
+		result = result[:top]
+	}
+	return result
+}
+
+func isAlwaysOK(durations map[moira.State]int64) bool {
+	for state, duration := range durations {
+		if state != moira.StateOK && duration > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+type metricKey struct {
+	metric       string
+	triggerEvent bool
+}
+
+// triggerTimeline accumulates durations of trigger states and its problems while events are applied in time order.
+type triggerTimeline struct {
+	stats        *TriggerStats
+	metrics      map[metricKey]moira.State
+	state        moira.State
+	since        int64
+	problemSince int64
+}
+
+func getTriggerStats(check *moira.TriggerCheck, events []*moira.NotificationEvent, from, to int64) TriggerStats {
+	stats := TriggerStats{
+		TriggerInfo: TriggerInfo{
+			TriggerID: check.ID,
+			Name:      check.Name,
+			TeamID:    check.TeamID,
+		},
+		StateDurations: make(map[moira.State]int64),
+	}
+
+	timeline := &triggerTimeline{
+		stats:   &stats,
+		metrics: getInitialStates(check, events),
+		since:   from,
+	}
+	timeline.state = timeline.getWorstState()
+
+	for _, event := range events {
+		if event.State == moira.StateTEST || event.Timestamp < from {
+			continue
+		}
+		if event.Timestamp > to {
+			break
+		}
+		stats.Events++
+		timeline.metrics[getMetricKey(event)] = event.State
+		timeline.changeState(timeline.getWorstState(), event.Timestamp)
+	}
+	timeline.changeState("", to)
+
+	if stats.Recoveries > 0 {
+		stats.MTTR = stats.recoveryTime / int64(stats.Recoveries)
+	}
+	return stats
+}
+
+// getInitialStates returns states of trigger metrics at the beginning of time range.
+func getInitialStates(check *moira.TriggerCheck, events []*moira.NotificationEvent) map[metricKey]moira.State {
+	states := make(map[metricKey]moira.State)
+	for _, event := range events {
+		if event.State == moira.StateTEST {
+			continue
+		}
+		key := getMetricKey(event)
+		if _, ok := states[key]; !ok {
+			states[key] = event.OldState
+		}
+	}
+
+	for metric, metricState := range check.LastCheck.Metrics {
+		key := metricKey{metric: metric}
+		if _, ok := states[key]; !ok {
+			states[key] = metricState.State
+		}
+	}
+
+	triggerKey := metricKey{triggerEvent: true}
+	if _, ok := states[triggerKey]; !ok && check.LastCheck.State != "" {
+		states[triggerKey] = check.LastCheck.State
+	}
+	return states
+}
+
+func getMetricKey(event *moira.NotificationEvent) metricKey {
+	if event.IsTriggerEvent {
+		return metricKey{triggerEvent: true}
+	}
+	return metricKey{metric: event.Metric}
+}
+
+// getWorstState returns the most severe state of trigger metrics, empty state is returned if it is unknown.
+func (timeline *triggerTimeline) getWorstState() moira.State {
+	var worst moira.State
+	for _, state := range timeline.metrics {
+		if statesSeverity[state] > statesSeverity[worst] {
+			worst = state
+		}
+	}
+	return worst
+}
+
+// changeState accounts time spent in current state and problem transitions.
+// Empty state means trigger state is unknown, it ends timeline when time range is over.
+func (timeline *triggerTimeline) changeState(state moira.State, timestamp int64) {
+	if state == timeline.state && state != "" {
+		return
+	}
+	if timeline.state != "" {
+		timeline.stats.StateDurations[timeline.state] += timestamp - timeline.since
+	}
+	timeline.since = timestamp
+	if state == "" {
+		return
+	}
+
+	wasProblem := timeline.state != "" && timeline.state != moira.StateOK
+	isProblem := state != moira.StateOK
+	switch {
+	case !wasProblem && isProblem:
+		timeline.stats.Problems++
+		timeline.problemSince = timestamp
+	case wasProblem && !isProblem:
+		if timeline.problemSince > 0 {
+			timeline.stats.Recoveries++
+			timeline.stats.recoveryTime += timestamp - timeline.problemSince
+		}
+		timeline.problemSince = 0
+	}
+	timeline.state = state
+}
@@ -0,0 +1,108 @@
+package analytics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	mock_clock "github.com/moira-alert/moira/mock/clock"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBuildTriggersReport(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	systemClock := mock_clock.NewMockClock(mockCtrl)
+	systemClock.EXPECT().Now().Return(time.Unix(3000, 0)).AnyTimes()
+
+	builder := NewBuilder(dataBase)
+	builder.clock = systemClock
+
+	okMetrics := map[string]moira.MetricState{"m1": {State: moira.StateOK}, "m2": {State: moira.StateOK}}
+	checks := []*moira.TriggerCheck{
+		{Trigger: moira.Trigger{ID: "flapping", Name: "Flapping", TeamID: "team"}, LastCheck: moira.CheckData{State: moira.StateOK, Metrics: okMetrics}},
+		{Trigger: moira.Trigger{ID: "broken", Name: "Broken"}, LastCheck: moira.CheckData{State: moira.StateOK, Metrics: map[string]moira.MetricState{"m": {State: moira.StateERROR}}}},
+		{Trigger: moira.Trigger{ID: "quiet", Name: "Quiet"}, LastCheck: moira.CheckData{State: moira.StateOK, Metrics: okMetrics}},
+		nil,
+		{Trigger: moira.Trigger{ID: "recovered", Name: "Recovered"}, LastCheck: moira.CheckData{State: moira.StateOK, Metrics: okMetrics}},
+	}
+	events := map[string][]*moira.NotificationEvent{
+		"flapping": {
+			{Timestamp: 1100, Metric: "m1", OldState: moira.StateOK, State: moira.StateERROR},
+			{Timestamp: 1300, Metric: "m1", OldState: moira.StateERROR, State: moira.StateOK},
+			{Timestamp: 1400, State: moira.StateTEST},
+			{Timestamp: 1500, Metric: "m2", OldState: moira.StateOK, State: moira.StateWARN},
+			{Timestamp: 1600, Metric: "m2", OldState: moira.StateWARN, State: moira.StateOK},
+			{Timestamp: 2500, Metric: "m1", OldState: moira.StateOK, State: moira.StateNODATA},
+		},
+		"recovered": {
+			{Timestamp: 1200, Metric: "m1", OldState: moira.StateERROR, State: moira.StateOK},
+		},
+	}
+
+	Convey("Test build triggers report", t, func() {
+		dataBase.EXPECT().GetAllTriggerIDs().Return([]string{"flapping", "broken", "quiet", "removed", "recovered"}, nil)
+		dataBase.EXPECT().GetTriggerChecks([]string{"flapping", "broken", "quiet", "removed", "recovered"}).Return(checks, nil)
+		for _, triggerID := range []string{"flapping", "broken", "quiet", "recovered"} {
+			dataBase.EXPECT().GetNotificationEventsByTime(triggerID, int64(1000), int64(3000)).Return(events[triggerID], nil)
+		}
+
+		report, err := builder.BuildTriggersReport(Options{From: 1000, To: 2000, Top: 10})
+		So(err, ShouldBeNil)
+
+		flapping := TriggerStats{
+			TriggerInfo:    TriggerInfo{TriggerID: "flapping", Name: "Flapping", TeamID: "team"},
+			Events:         4,
+			Problems:       2,
+			Recoveries:     2,
+			MTTR:           150,
+			StateDurations: map[moira.State]int64{moira.StateOK: 700, moira.StateERROR: 200, moira.StateWARN: 100},
+			recoveryTime:   300,
+		}
+		recovered := TriggerStats{
+			TriggerInfo:    TriggerInfo{TriggerID: "recovered", Name: "Recovered"},
+			Events:         1,
+			StateDurations: map[moira.State]int64{moira.StateOK: 800, moira.StateERROR: 200},
+		}
+
+		So(report.Triggers, ShouldEqual, 4)
+		So(report.Events, ShouldEqual, 5)
+		So(report.Problems, ShouldEqual, 2)
+		So(report.Recoveries, ShouldEqual, 2)
+		So(report.MTTR, ShouldEqual, 150)
+		So(report.StateDurations, ShouldResemble, map[moira.State]int64{moira.StateOK: 2500, moira.StateERROR: 1400, moira.StateWARN: 100})
+		So(report.Noisiest, ShouldResemble, []TriggerStats{flapping, recovered})
+		So(report.Flapping, ShouldResemble, []TriggerStats{flapping})
+		So(report.SlowestRecovery, ShouldResemble, []TriggerStats{flapping})
+		So(report.NeverFired, ShouldResemble, []TriggerInfo{{TriggerID: "quiet", Name: "Quiet"}})
+	})
+
+	Convey("Test build team triggers report", t, func() {
+		dataBase.EXPECT().GetTeamTriggerIDs("team").Return([]string{"flapping"}, nil)
+		dataBase.EXPECT().GetTriggerChecks([]string{"flapping"}).Return(checks[:1], nil)
+		dataBase.EXPECT().GetNotificationEventsByTime("flapping", int64(1000), int64(3000)).Return(nil, fmt.Errorf("error"))
+
+		_, err := builder.BuildTriggersReport(Options{From: 1000, To: 2000, TeamID: "team"})
+		So(err, ShouldResemble, fmt.Errorf("failed to get events of trigger flapping: %w", fmt.Errorf("error")))
+	})
+}
+
+func TestGetTopTriggers(t *testing.T) {
+	Convey("Test get top triggers", t, func() {
+		stats := []TriggerStats{
+			{TriggerInfo: TriggerInfo{Name: "b"}, Events: 2},
+			{TriggerInfo: TriggerInfo{Name: "a"}, Events: 2},
+			{TriggerInfo: TriggerInfo{Name: "c"}, Events: 5},
+			{TriggerInfo: TriggerInfo{Name: "d"}},
+		}
+
+		top := getTopTriggers(stats, 2, func(stats *TriggerStats) int64 { return int64(stats.Events) })
+		So(top, ShouldHaveLength, 2)
+		So(top[0].Name, ShouldEqual, "c")
+		So(top[1].Name, ShouldEqual, "a")
+	})
+}
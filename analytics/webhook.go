@@ -0,0 +1,51 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookSink sends reports to the given url with POST request.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink creates WebhookSink with given request timeout.
+func NewWebhookSink(url string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send sends report as json, any response status except 2xx is considered as error.
+func (sink *WebhookSink) Send(report *Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analytics report: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, sink.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create analytics webhook request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := sink.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to send analytics report: %w", err)
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body) //nolint:errcheck
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("analytics webhook responded with status %d", response.StatusCode)
+	}
+	return nil
+}
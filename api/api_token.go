@@ -0,0 +1,58 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	apiTokenPrefix     = "moira_"
+	apiTokenSeparator  = "."
+	apiTokenSecretSize = 32
+)
+
+// NewAPITokenSecret generates random secret part of api token.
+func NewAPITokenSecret() (string, error) {
+	bytes := make([]byte, apiTokenSecretSize)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// FormatAPIToken joins token ID and secret into the value clients send as bearer token.
+func FormatAPIToken(tokenID, secret string) string {
+	return apiTokenPrefix + tokenID + apiTokenSeparator + secret
+}
+
+// IsAPIToken returns true if bearer token is moira api token and not the one issued by identity provider.
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, apiTokenPrefix)
+}
+
+// ParseAPIToken splits bearer token into token ID and secret.
+func ParseAPIToken(token string) (tokenID, secret string, ok bool) {
+	if !IsAPIToken(token) {
+		return "", "", false
+	}
+	tokenID, secret, ok = strings.Cut(strings.TrimPrefix(token, apiTokenPrefix), apiTokenSeparator)
+	if !ok || tokenID == "" || secret == "" {
+		return "", "", false
+	}
+	return tokenID, secret, true
+}
+
+// HashAPITokenSecret returns hash of token secret, only hashes are stored in database.
+func HashAPITokenSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// IsAPITokenSecretValid compares secret with stored hash in constant time.
+func IsAPITokenSecretValid(secret, secretHash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPITokenSecret(secret)), []byte(secretHash)) == 1
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAPIToken(t *testing.T) {
+	Convey("Test api token helpers", t, func() {
+		secret, err := NewAPITokenSecret()
+		So(err, ShouldBeNil)
+		So(secret, ShouldNotBeEmpty)
+
+		token := FormatAPIToken("tokenID", secret)
+		So(IsAPIToken(token), ShouldBeTrue)
+
+		Convey("Token is parsed", func() {
+			tokenID, parsedSecret, ok := ParseAPIToken(token)
+			So(ok, ShouldBeTrue)
+			So(tokenID, ShouldEqual, "tokenID")
+			So(parsedSecret, ShouldEqual, secret)
+		})
+
+		Convey("Malformed tokens are not parsed", func() {
+			for _, malformed := range []string{"tokenID." + secret, "moira_tokenID", "moira_." + secret, "moira_tokenID."} {
+				_, _, ok := ParseAPIToken(malformed)
+				So(ok, ShouldBeFalse)
+			}
+		})
+
+		Convey("Secret is checked by hash", func() {
+			hash := HashAPITokenSecret(secret)
+			So(hash, ShouldNotEqual, secret)
+			So(IsAPITokenSecretValid(secret, hash), ShouldBeTrue)
+			So(IsAPITokenSecretValid(secret+"x", hash), ShouldBeFalse)
+		})
+	})
+}
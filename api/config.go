@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/exporter"
+	"github.com/moira-alert/moira/chatops"
 )
 
 // WebContact is container for web ui contact validation.
@@ -38,6 +40,37 @@ type Config struct {
 	MetricsTTL    map[moira.ClusterKey]time.Duration
 	Flags         FeatureFlags
 	Authorization Authorization
+	// PrometheusExporter contains configuration of trigger states exposition in Prometheus format.
+	PrometheusExporter exporter.Config
+	// NotificationPreview contains configuration of notifications rendering without delivery.
+	NotificationPreview NotificationPreview
+	// ImageServer serves images saved by notifier to filesystem image store, it is nil if images are not served.
+	ImageServer ImageServer
+	// SlackChatOps contains configuration of Slack slash command endpoint.
+	SlackChatOps SlackChatOps
+}
+
+// SlackChatOps contains configuration of Slack slash command endpoint.
+type SlackChatOps struct {
+	Enabled bool
+	// SigningSecret of Slack app is used to verify slash command requests.
+	SigningSecret string
+	// Commands executes slash commands on behalf of moira users mapped to Slack users.
+	Commands *chatops.Commands
+}
+
+// ImageServer returns images by parameters of signed links to them.
+type ImageServer interface {
+	GetImage(imageID string, expires int64, signature string) ([]byte, moira.PlotFormat, error)
+}
+
+// NotificationPreview contains configuration of notifications rendering without delivery.
+type NotificationPreview struct {
+	Enabled bool
+	// Builders render notifications the same way as senders do, keys are contact types.
+	Builders map[string]moira.MessageBuilder
+	// Location is used to render time on plots.
+	Location *time.Location
 }
 
 // Authorization contains authorization configuration.
@@ -45,6 +78,34 @@ type Authorization struct {
 	AdminList           map[string]struct{}
 	Enabled             bool
 	AllowedContactTypes map[string]struct{}
+	Authentication      Authentication
+}
+
+// TokenVerifier checks bearer token and returns login and groups of the user it was issued to.
+type TokenVerifier interface {
+	Verify(token string) (login string, groups []string, err error)
+}
+
+// Authentication contains configuration of the way users are identified.
+type Authentication struct {
+	// TokenVerifier is used to check bearer tokens, nil if only x-webauth-user header is trusted.
+	TokenVerifier TokenVerifier
+	// HeaderFallback allows to identify user by x-webauth-user header if request has no bearer token.
+	HeaderFallback bool
+	// AdminGroups contains groups which members are considered to be admins.
+	AdminGroups map[string]struct{}
+	// TeamGroups maps groups to IDs of teams their members are added to, groups are authoritative for membership in these teams.
+	TeamGroups map[string][]string
+}
+
+// IsTokenEnabled returns true if users can be identified by bearer token.
+func (auth *Authorization) IsTokenEnabled() bool {
+	return auth.Authentication.TokenVerifier != nil
+}
+
+// IsHeaderEnabled returns true if x-webauth-user header can be trusted.
+func (auth *Authorization) IsHeaderEnabled() bool {
+	return !auth.IsTokenEnabled() || auth.Authentication.HeaderFallback
 }
 
 // IsEnabled returns true if auth is enabled and false otherwise.
@@ -61,6 +122,45 @@ func (auth *Authorization) IsAdmin(login string) bool {
 	return ok
 }
 
+// IsAdminGroup checks whether members of any of given groups are considered administrators.
+func (auth *Authorization) IsAdminGroup(groups []string) bool {
+	for _, group := range groups {
+		if _, ok := auth.Authentication.AdminGroups[group]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GetGroupsTeams returns IDs of teams members of given groups should be added to.
+func (auth *Authorization) GetGroupsTeams(groups []string) []string {
+	teams := make([]string, 0)
+	unique := make(map[string]struct{})
+	for _, group := range groups {
+		for _, teamID := range auth.Authentication.TeamGroups[group] {
+			if _, ok := unique[teamID]; ok {
+				continue
+			}
+			unique[teamID] = struct{}{}
+			teams = append(teams, teamID)
+		}
+	}
+	return teams
+}
+
+// WithAdmin returns copy of authorization configuration in which given user is considered an administrator.
+func (auth *Authorization) WithAdmin(login string) *Authorization {
+	adminList := make(map[string]struct{}, len(auth.AdminList)+1)
+	for admin := range auth.AdminList {
+		adminList[admin] = struct{}{}
+	}
+	adminList[login] = struct{}{}
+
+	result := *auth
+	result.AdminList = adminList
+	return &result
+}
+
 // WebConfig is container for web ui configuration parameters.
 type WebConfig struct {
 	SupportEmail         string                `json:"supportEmail,omitempty" example:"opensource@skbkontur.com"`
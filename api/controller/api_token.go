@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetUserAPITokens gets personal api tokens of user.
+func GetUserAPITokens(dataBase moira.Database, userLogin string) (*dto.APITokenList, *api.ErrorResponse) {
+	tokenIDs, err := dataBase.GetUserAPITokenIDs(userLogin)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return getAPITokenList(dataBase, tokenIDs)
+}
+
+// GetTeamAPITokens gets api tokens of team.
+func GetTeamAPITokens(dataBase moira.Database, teamID string) (*dto.APITokenList, *api.ErrorResponse) {
+	tokenIDs, err := dataBase.GetTeamAPITokenIDs(teamID)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return getAPITokenList(dataBase, tokenIDs)
+}
+
+func getAPITokenList(dataBase moira.Database, tokenIDs []string) (*dto.APITokenList, *api.ErrorResponse) {
+	tokens, err := dataBase.GetAPITokens(tokenIDs)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	now := time.Now()
+	list := &dto.APITokenList{
+		List: make([]dto.APIToken, 0, len(tokens)),
+	}
+	for _, token := range tokens {
+		if token != nil {
+			list.List = append(list.List, dto.NewAPIToken(*token, now))
+		}
+	}
+	sort.Slice(list.List, func(i, j int) bool {
+		return list.List[i].CreatedAt > list.List[j].CreatedAt
+	})
+	return list, nil
+}
+
+// CreateAPIToken creates api token owned by team if teamID is set or by user otherwise.
+// Token value is returned only once, database keeps only hash of its secret.
+func CreateAPIToken(
+	dataBase moira.Database,
+	auth *api.Authorization,
+	token *dto.APIToken,
+	userLogin, teamID string,
+) (*dto.CreatedAPIToken, *api.ErrorResponse) {
+	if err := checkAPITokenScopes(dataBase, auth, token.Scopes, userLogin, teamID); err != nil {
+		return nil, err
+	}
+
+	tokenID, err := uuid.NewV4()
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	secret, err := api.NewAPITokenSecret()
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	now := time.Now()
+	apiToken := moira.APIToken{
+		ID:         tokenID.String(),
+		Name:       token.Name,
+		Scopes:     token.Scopes,
+		CreatedBy:  userLogin,
+		CreatedAt:  now.Unix(),
+		ExpiresAt:  token.ExpiresAt,
+		SecretHash: api.HashAPITokenSecret(secret),
+	}
+	if teamID != "" {
+		apiToken.TeamID = teamID
+	} else {
+		apiToken.User = userLogin
+	}
+
+	if err = dataBase.SaveAPIToken(&apiToken); err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	return &dto.CreatedAPIToken{
+		APIToken: dto.NewAPIToken(apiToken, now),
+		Token:    api.FormatAPIToken(apiToken.ID, secret),
+	}, nil
+}
+
+// UpdateAPIToken changes name, scopes and expiration time of api token, the value of token stays the same.
+func UpdateAPIToken(
+	dataBase moira.Database,
+	auth *api.Authorization,
+	existing moira.APIToken,
+	token *dto.APIToken,
+	userLogin string,
+) (*dto.APIToken, *api.ErrorResponse) {
+	if err := checkAPITokenScopes(dataBase, auth, token.Scopes, userLogin, existing.TeamID); err != nil {
+		return nil, err
+	}
+
+	existing.Name = token.Name
+	existing.Scopes = token.Scopes
+	existing.ExpiresAt = token.ExpiresAt
+	if err := dataBase.SaveAPIToken(&existing); err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	result := dto.NewAPIToken(existing, time.Now())
+	return &result, nil
+}
+
+// RemoveAPIToken deletes api token, requests with it are rejected right after that.
+func RemoveAPIToken(dataBase moira.Database, tokenID string) *api.ErrorResponse {
+	if err := dataBase.RemoveAPIToken(tokenID); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
+
+// CheckUserPermissionsForAPIToken checks that api token exists and is owned by team if teamID is set or by user otherwise.
+func CheckUserPermissionsForAPIToken(dataBase moira.Database, tokenID, userLogin, teamID string) (moira.APIToken, *api.ErrorResponse) {
+	token, err := dataBase.GetAPIToken(tokenID)
+	if err != nil {
+		if errors.Is(err, database.ErrNil) {
+			return moira.APIToken{}, api.ErrorNotFound(fmt.Sprintf("api token with ID '%s' does not exists", tokenID))
+		}
+		return moira.APIToken{}, api.ErrorInternalServer(err)
+	}
+
+	if teamID != "" && token.TeamID == teamID {
+		return token, nil
+	}
+	if teamID == "" && token.TeamID == "" && token.User == userLogin {
+		return token, nil
+	}
+	return moira.APIToken{}, api.ErrorForbidden("you are not permitted to manipulate with this api token")
+}
+
+// checkAPITokenScopes checks that owner of the token is allowed to get given team scopes.
+// Team tokens can manage only their own team, personal tokens can manage only teams user is a member of.
+func checkAPITokenScopes(
+	dataBase moira.Database,
+	auth *api.Authorization,
+	scopes []moira.APITokenScope,
+	userLogin, teamID string,
+) *api.ErrorResponse {
+	for _, scope := range scopes {
+		scopeTeamID, isTeamScope := strings.CutPrefix(string(scope), moira.APITokenScopeTeamPrefix)
+		if !isTeamScope {
+			continue
+		}
+
+		if teamID != "" {
+			if scopeTeamID != teamID {
+				return api.ErrorInvalidRequest(fmt.Errorf("team token can manage only its own team, got scope '%s'", scope))
+			}
+			continue
+		}
+
+		if auth.IsAdmin(userLogin) {
+			continue
+		}
+
+		isMember, err := dataBase.IsTeamContainUser(scopeTeamID, userLogin)
+		if err != nil {
+			return api.ErrorInternalServer(err)
+		}
+		if !isMember {
+			return api.ErrorForbidden(fmt.Sprintf("you are not a member of team '%s'", scopeTeamID))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCreateAPIToken(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	auth := &api.Authorization{Enabled: true, AdminList: map[string]struct{}{"admin": {}}}
+
+	Convey("Create api token", t, func() {
+		Convey("Personal token", func() {
+			token := &dto.APIToken{Name: "ci", Scopes: []moira.APITokenScope{moira.APITokenScopeTriggers, moira.TeamScope("team")}}
+			var saved *moira.APIToken
+			dataBase.EXPECT().IsTeamContainUser("team", "john").Return(true, nil)
+			dataBase.EXPECT().SaveAPIToken(gomock.Any()).Do(func(token *moira.APIToken) { saved = token }).Return(nil)
+
+			created, err := CreateAPIToken(dataBase, auth, token, "john", "")
+			So(err, ShouldBeNil)
+			So(saved.User, ShouldEqual, "john")
+			So(saved.TeamID, ShouldBeEmpty)
+			So(saved.CreatedBy, ShouldEqual, "john")
+			So(created.ID, ShouldEqual, saved.ID)
+			So(created.Scopes, ShouldResemble, token.Scopes)
+
+			tokenID, secret, ok := api.ParseAPIToken(created.Token)
+			So(ok, ShouldBeTrue)
+			So(tokenID, ShouldEqual, saved.ID)
+			So(api.IsAPITokenSecretValid(secret, saved.SecretHash), ShouldBeTrue)
+		})
+
+		Convey("Team token", func() {
+			token := &dto.APIToken{Name: "ci", Scopes: []moira.APITokenScope{moira.TeamScope("team")}}
+			var saved *moira.APIToken
+			dataBase.EXPECT().SaveAPIToken(gomock.Any()).Do(func(token *moira.APIToken) { saved = token }).Return(nil)
+
+			_, err := CreateAPIToken(dataBase, auth, token, "john", "team")
+			So(err, ShouldBeNil)
+			So(saved.User, ShouldBeEmpty)
+			So(saved.TeamID, ShouldEqual, "team")
+		})
+
+		Convey("Team token can not manage other team", func() {
+			token := &dto.APIToken{Name: "ci", Scopes: []moira.APITokenScope{moira.TeamScope("other")}}
+			_, err := CreateAPIToken(dataBase, auth, token, "john", "team")
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("team token can manage only its own team, got scope 'team:other'")))
+		})
+
+		Convey("Personal token can not manage team of other users", func() {
+			token := &dto.APIToken{Name: "ci", Scopes: []moira.APITokenScope{moira.TeamScope("team")}}
+			dataBase.EXPECT().IsTeamContainUser("team", "john").Return(false, nil)
+			_, err := CreateAPIToken(dataBase, auth, token, "john", "")
+			So(err, ShouldResemble, api.ErrorForbidden("you are not a member of team 'team'"))
+		})
+
+		Convey("Admin token can manage any team", func() {
+			token := &dto.APIToken{Name: "ci", Scopes: []moira.APITokenScope{moira.TeamScope("team")}}
+			dataBase.EXPECT().SaveAPIToken(gomock.Any()).Return(nil)
+			_, err := CreateAPIToken(dataBase, auth, token, "admin", "")
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestGetUserAPITokens(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Get user api tokens", t, func() {
+		Convey("Newest tokens go first, hashes are not returned", func() {
+			expired := int64(1)
+			dataBase.EXPECT().GetUserAPITokenIDs("john").Return([]string{"old", "removed", "new"}, nil)
+			dataBase.EXPECT().GetAPITokens([]string{"old", "removed", "new"}).Return([]*moira.APIToken{
+				{ID: "old", User: "john", CreatedAt: 1, ExpiresAt: &expired, SecretHash: "hash"},
+				nil,
+				{ID: "new", User: "john", CreatedAt: 2, SecretHash: "hash"},
+			}, nil)
+
+			list, err := GetUserAPITokens(dataBase, "john")
+			So(err, ShouldBeNil)
+			So(list, ShouldResemble, &dto.APITokenList{List: []dto.APIToken{
+				{ID: "new", User: "john", CreatedAt: 2},
+				{ID: "old", User: "john", CreatedAt: 1, ExpiresAt: &expired, Expired: true},
+			}})
+		})
+
+		Convey("Error", func() {
+			expected := fmt.Errorf("oooops! Can not get api tokens")
+			dataBase.EXPECT().GetUserAPITokenIDs("john").Return(nil, expected)
+			list, err := GetUserAPITokens(dataBase, "john")
+			So(err, ShouldResemble, api.ErrorInternalServer(expected))
+			So(list, ShouldBeNil)
+		})
+	})
+}
+
+func TestCheckUserPermissionsForAPIToken(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	personal := moira.APIToken{ID: "personal", User: "john"}
+	team := moira.APIToken{ID: "team", TeamID: "team"}
+
+	Convey("Check permissions for api token", t, func() {
+		Convey("Owner of personal token", func() {
+			dataBase.EXPECT().GetAPIToken("personal").Return(personal, nil)
+			token, err := CheckUserPermissionsForAPIToken(dataBase, "personal", "john", "")
+			So(err, ShouldBeNil)
+			So(token, ShouldResemble, personal)
+		})
+
+		Convey("Other user", func() {
+			dataBase.EXPECT().GetAPIToken("personal").Return(personal, nil)
+			_, err := CheckUserPermissionsForAPIToken(dataBase, "personal", "jane", "")
+			So(err, ShouldResemble, api.ErrorForbidden("you are not permitted to manipulate with this api token"))
+		})
+
+		Convey("Team token from team route", func() {
+			dataBase.EXPECT().GetAPIToken("team").Return(team, nil)
+			token, err := CheckUserPermissionsForAPIToken(dataBase, "team", "john", "team")
+			So(err, ShouldBeNil)
+			So(token, ShouldResemble, team)
+		})
+
+		Convey("Team token from user route", func() {
+			dataBase.EXPECT().GetAPIToken("team").Return(team, nil)
+			_, err := CheckUserPermissionsForAPIToken(dataBase, "team", "john", "")
+			So(err, ShouldResemble, api.ErrorForbidden("you are not permitted to manipulate with this api token"))
+		})
+
+		Convey("Unknown token", func() {
+			dataBase.EXPECT().GetAPIToken("unknown").Return(moira.APIToken{}, database.ErrNil)
+			_, err := CheckUserPermissionsForAPIToken(dataBase, "unknown", "john", "")
+			So(err, ShouldResemble, api.ErrorNotFound("api token with ID 'unknown' does not exists"))
+		})
+	})
+}
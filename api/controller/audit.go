@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+)
+
+// GetAuditRecords gets audit records matched by filter, the newest records go first.
+func GetAuditRecords(dataBase moira.Database, filter moira.AuditRecordsFilter) (*dto.AuditRecordsList, *api.ErrorResponse) {
+	records, err := dataBase.GetAuditRecords(filter)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	list := &dto.AuditRecordsList{
+		List: make([]moira.AuditRecord, 0, len(records)),
+	}
+	for _, record := range records {
+		if record != nil {
+			list.List = append(list.List, *record)
+		}
+	}
+	return list, nil
+}
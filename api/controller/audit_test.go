@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetAuditRecords(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	filter := moira.AuditRecordsFilter{Actor: "user", Limit: 10}
+
+	Convey("Get audit records", t, func() {
+		Convey("Success", func() {
+			records := []*moira.AuditRecord{
+				{Timestamp: 2, Actor: "user", Action: moira.AuditActionDelete, ObjectType: moira.AuditObjectTag, ObjectID: "tag"},
+				{Timestamp: 1, Actor: "user", Action: moira.AuditActionCreate, ObjectType: moira.AuditObjectTag, ObjectID: "tag"},
+			}
+			dataBase.EXPECT().GetAuditRecords(filter).Return(records, nil)
+			list, err := GetAuditRecords(dataBase, filter)
+			So(err, ShouldBeNil)
+			So(list, ShouldResemble, &dto.AuditRecordsList{List: []moira.AuditRecord{*records[0], *records[1]}})
+		})
+
+		Convey("Empty list", func() {
+			dataBase.EXPECT().GetAuditRecords(filter).Return(nil, nil)
+			list, err := GetAuditRecords(dataBase, filter)
+			So(err, ShouldBeNil)
+			So(list, ShouldResemble, &dto.AuditRecordsList{List: []moira.AuditRecord{}})
+		})
+
+		Convey("Error", func() {
+			expected := fmt.Errorf("oooops! Can not get audit records")
+			dataBase.EXPECT().GetAuditRecords(filter).Return(nil, expected)
+			list, err := GetAuditRecords(dataBase, filter)
+			So(err, ShouldResemble, api.ErrorInternalServer(expected))
+			So(list, ShouldBeNil)
+		})
+	})
+}
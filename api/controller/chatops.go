@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/chatops"
+)
+
+// ExecuteChatOpsCommand executes command sent from chat on behalf of moira user mapped to chat user.
+func ExecuteChatOpsCommand(commands *chatops.Commands, request chatops.Request) (chatops.Response, *api.ErrorResponse) {
+	response, err := commands.Execute(request)
+	if err != nil {
+		return chatops.Response{}, api.ErrorInternalServer(err)
+	}
+	return response, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/dto"
 	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/templating"
 )
 
 // ErrNotAllowedContactType means that this type of contact is not allowed to be created.
@@ -25,7 +26,13 @@ func GetAllContacts(database moira.Database) (*dto.ContactList, *api.ErrorRespon
 		return nil, api.ErrorInternalServer(err)
 	}
 	contactsList := dto.ContactList{
-		List: contacts,
+		List: make([]*dto.ContactData, 0, len(contacts)),
+	}
+	for _, contact := range contacts {
+		if contact != nil {
+			contactData := dto.NewContactData(*contact)
+			contactsList.List = append(contactsList.List, &contactData)
+		}
 	}
 	return &contactsList, nil
 }
@@ -38,12 +45,15 @@ func GetContactById(database moira.Database, contactID string) (*dto.Contact, *a
 	}
 
 	contactToReturn := &dto.Contact{
-		ID:     contact.ID,
-		Name:   contact.Name,
-		User:   contact.User,
-		TeamID: contact.Team,
-		Type:   contact.Type,
-		Value:  contact.Value,
+		ID:               contact.ID,
+		Name:             contact.Name,
+		User:             contact.User,
+		TeamID:           contact.Team,
+		Type:             contact.Type,
+		Value:            contact.Value,
+		MessageTemplate:  contact.MessageTemplate,
+		HasSigningSecret: contact.SigningSecret != "",
+		Availability:     contact.Availability,
 	}
 
 	return contactToReturn, nil
@@ -71,12 +81,18 @@ func CreateContact(
 	}
 
 	contactData := moira.ContactData{
-		ID:    contact.ID,
-		Name:  contact.Name,
-		User:  contact.User,
-		Team:  teamID,
-		Type:  contact.Type,
-		Value: contact.Value,
+		ID:              contact.ID,
+		Name:            contact.Name,
+		User:            contact.User,
+		Team:            teamID,
+		Type:            contact.Type,
+		Value:           contact.Value,
+		MessageTemplate: contact.MessageTemplate,
+		SigningSecret:   contact.SigningSecret,
+		Availability:    contact.Availability,
+	}
+	if err := checkBackupContact(dataBase, contactData); err != nil {
+		return err
 	}
 	if contactData.ID == "" {
 		uuid4, err := uuid.NewV4()
@@ -100,6 +116,8 @@ func CreateContact(
 	contact.User = contactData.User
 	contact.ID = contactData.ID
 	contact.TeamID = contactData.Team
+	contact.HasSigningSecret = contactData.SigningSecret != ""
+	contact.SigningSecret = ""
 	return nil
 }
 
@@ -117,15 +135,48 @@ func UpdateContact(
 	contactData.Type = contactDTO.Type
 	contactData.Value = contactDTO.Value
 	contactData.Name = contactDTO.Name
+	contactData.MessageTemplate = contactDTO.MessageTemplate
+	if contactDTO.SigningSecret != "" || !contactDTO.HasSigningSecret {
+		contactData.SigningSecret = contactDTO.SigningSecret
+	}
+	contactData.Availability = contactDTO.Availability
+	if err := checkBackupContact(dataBase, contactData); err != nil {
+		return contactDTO, err
+	}
 	if err := dataBase.SaveContact(&contactData); err != nil {
 		return contactDTO, api.ErrorInternalServer(err)
 	}
 	contactDTO.User = contactData.User
 	contactDTO.TeamID = contactData.Team
 	contactDTO.ID = contactData.ID
+	contactDTO.HasSigningSecret = contactData.SigningSecret != ""
+	contactDTO.SigningSecret = ""
 	return contactDTO, nil
 }
 
+// ValidateContactMessageTemplate checks that message template can be populated with sample notification data.
+func ValidateContactMessageTemplate(request *dto.MessageTemplateRequest) *dto.MessageTemplateValidation {
+	if err := templating.ValidateMessageTemplate(request.Template); err != nil {
+		return &dto.MessageTemplateValidation{Valid: false, Error: err.Error()}
+	}
+	return &dto.MessageTemplateValidation{Valid: true}
+}
+
+// PreviewContactMessageTemplate populates message template with sample notification data.
+func PreviewContactMessageTemplate(request *dto.MessageTemplateRequest) (*dto.MessageTemplatePreview, *api.ErrorResponse) {
+	data := templating.SampleMessageData()
+	populater := templating.NewMessagePopulater(data)
+	if request.HTML {
+		populater = templating.NewHTMLMessagePopulater(data)
+	}
+
+	message, err := populater.Populate(request.Template)
+	if err != nil {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("failed to populate message template: %w", err))
+	}
+	return &dto.MessageTemplatePreview{Message: message}, nil
+}
+
 // RemoveContact deletes notification contact for current user and remove contactID from all subscriptions.
 func RemoveContact(database moira.Database, contactID string, userLogin string, teamID string) *api.ErrorResponse { //nolint:gocyclo
 	subscriptionIDs := make([]string, 0)
@@ -239,6 +290,47 @@ func CheckUserPermissionsForContact(
 	return moira.ContactData{}, api.ErrorForbidden("you are not permitted")
 }
 
+// SetContactDoNotDisturb turns Do Not Disturb of contact on or off, other availability settings are kept.
+func SetContactDoNotDisturb(dataBase moira.Database, contactData moira.ContactData, dnd *dto.ContactDoNotDisturb) *api.ErrorResponse {
+	availability := moira.ContactAvailability{}
+	if contactData.Availability != nil {
+		availability = *contactData.Availability
+	}
+	availability.DoNotDisturb = dnd.Enabled
+	availability.DoNotDisturbUntil = 0
+	if dnd.Enabled {
+		availability.DoNotDisturbUntil = dnd.Until
+	}
+	contactData.Availability = &availability
+	if err := dataBase.SaveContact(&contactData); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
+
+// checkBackupContact checks that backup contact exists and belongs to the same user or team as contact.
+func checkBackupContact(dataBase moira.Database, contactData moira.ContactData) *api.ErrorResponse {
+	if contactData.Availability == nil || contactData.Availability.BackupContact == "" {
+		return nil
+	}
+	backupID := contactData.Availability.BackupContact
+	if backupID == contactData.ID {
+		return api.ErrorInvalidRequest(fmt.Errorf("contact can not be backup contact of itself"))
+	}
+
+	backup, err := dataBase.GetContact(backupID)
+	if err != nil {
+		if errors.Is(err, database.ErrNil) {
+			return api.ErrorInvalidRequest(fmt.Errorf("backup contact with ID '%s' does not exists", backupID))
+		}
+		return api.ErrorInternalServer(err)
+	}
+	if backup.User != contactData.User || backup.Team != contactData.Team {
+		return api.ErrorInvalidRequest(fmt.Errorf("backup contact must belong to the same user or team as contact"))
+	}
+	return nil
+}
+
 func isContactExists(dataBase moira.Database, contactID string) (bool, error) {
 	_, err := dataBase.GetContact(contactID)
 	if errors.Is(err, database.ErrNil) {
@@ -39,23 +39,29 @@ func TestGetAllContacts(t *testing.T) {
 				Value: "good@mail.com",
 			},
 			{
-				ID:    uuid.Must(uuid.NewV4()).String(),
-				Type:  "pushover",
-				User:  "user2",
-				Value: "ggg1",
+				ID:            uuid.Must(uuid.NewV4()).String(),
+				Type:          "webhook",
+				User:          "user2",
+				Value:         "https://example.com",
+				SigningSecret: "secret",
 			},
 		}
 		dataBase.EXPECT().GetAllContacts().Return(contacts, nil)
 		actual, err := GetAllContacts(dataBase)
 		So(err, ShouldBeNil)
-		So(actual, ShouldResemble, &dto.ContactList{List: contacts})
+		contactWithoutSecret := *contacts[1]
+		contactWithoutSecret.SigningSecret = ""
+		So(actual, ShouldResemble, &dto.ContactList{List: []*dto.ContactData{
+			{ContactData: *contacts[0]},
+			{ContactData: contactWithoutSecret, HasSigningSecret: true},
+		}})
 	})
 
 	Convey("No contacts", t, func() {
 		dataBase.EXPECT().GetAllContacts().Return(make([]*moira.ContactData, 0), nil)
 		contacts, err := GetAllContacts(dataBase)
 		So(err, ShouldBeNil)
-		So(contacts, ShouldResemble, &dto.ContactList{List: make([]*moira.ContactData, 0)})
+		So(contacts, ShouldResemble, &dto.ContactList{List: make([]*dto.ContactData, 0)})
 	})
 }
 
@@ -71,6 +77,8 @@ func TestGetContactById(t *testing.T) {
 			Type:  "slack",
 			User:  "awesome_moira_user",
 			Value: "awesome_moira_user@gmail.com",
+
+			SigningSecret: "secret",
 		}
 
 		dataBase.EXPECT().GetContact(contact.ID).Return(contact, nil)
@@ -84,6 +92,8 @@ func TestGetContactById(t *testing.T) {
 				Type:  contact.Type,
 				User:  contact.User,
 				Value: contact.Value,
+
+				HasSigningSecret: true,
 			})
 	})
 
@@ -567,6 +577,42 @@ func TestUpdateContact(t *testing.T) {
 		})
 	})
 
+	Convey("Signing secret", t, func() {
+		contactID := uuid.Must(uuid.NewV4()).String()
+		existing := moira.ContactData{ID: contactID, User: userLogin, Type: contactType, Value: contactValue, SigningSecret: "old"}
+		contactDTO := dto.Contact{Type: contactType, Value: contactValue}
+
+		Convey("New signing secret is saved and not returned", func() {
+			contactDTO.SigningSecret = "new"
+			expected := existing
+			expected.SigningSecret = "new"
+			dataBase.EXPECT().SaveContact(&expected).Return(nil)
+			actual, err := UpdateContact(dataBase, auth, contactDTO, existing)
+			So(err, ShouldBeNil)
+			So(actual.SigningSecret, ShouldBeEmpty)
+			So(actual.HasSigningSecret, ShouldBeTrue)
+		})
+
+		Convey("Signing secret is kept if it is not given", func() {
+			contactDTO.HasSigningSecret = true
+			expected := existing
+			dataBase.EXPECT().SaveContact(&expected).Return(nil)
+			actual, err := UpdateContact(dataBase, auth, contactDTO, existing)
+			So(err, ShouldBeNil)
+			So(actual.SigningSecret, ShouldBeEmpty)
+			So(actual.HasSigningSecret, ShouldBeTrue)
+		})
+
+		Convey("Signing secret is removed", func() {
+			expected := existing
+			expected.SigningSecret = ""
+			dataBase.EXPECT().SaveContact(&expected).Return(nil)
+			actual, err := UpdateContact(dataBase, auth, contactDTO, existing)
+			So(err, ShouldBeNil)
+			So(actual.HasSigningSecret, ShouldBeFalse)
+		})
+	})
+
 	Convey("Team update", t, func() {
 		Convey("Success", func() {
 			contactDTO := dto.Contact{
@@ -609,6 +655,85 @@ func TestUpdateContact(t *testing.T) {
 	})
 }
 
+func TestContactAvailability(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	defer mockCtrl.Finish()
+
+	const (
+		userLogin = "user"
+		contactID = "contact-id"
+		backupID  = "backup-id"
+	)
+
+	auth := &api.Authorization{}
+
+	Convey("Update contact with backup contact", t, func() {
+		contactDTO := dto.Contact{
+			Value:        "some@mail.com",
+			Type:         "mail",
+			Availability: &moira.ContactAvailability{DoNotDisturb: true, BackupContact: backupID},
+		}
+
+		Convey("Backup contact of the same user", func() {
+			dataBase.EXPECT().GetContact(backupID).Return(moira.ContactData{ID: backupID, User: userLogin}, nil)
+			dataBase.EXPECT().SaveContact(gomock.Any()).Return(nil)
+			_, err := UpdateContact(dataBase, auth, contactDTO, moira.ContactData{ID: contactID, User: userLogin})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Backup contact of another user", func() {
+			dataBase.EXPECT().GetContact(backupID).Return(moira.ContactData{ID: backupID, User: "another"}, nil)
+			_, err := UpdateContact(dataBase, auth, contactDTO, moira.ContactData{ID: contactID, User: userLogin})
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("backup contact must belong to the same user or team as contact")))
+		})
+
+		Convey("Missing backup contact", func() {
+			dataBase.EXPECT().GetContact(backupID).Return(moira.ContactData{}, database.ErrNil)
+			_, err := UpdateContact(dataBase, auth, contactDTO, moira.ContactData{ID: contactID, User: userLogin})
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("backup contact with ID '%s' does not exists", backupID)))
+		})
+
+		Convey("Contact is backup contact of itself", func() {
+			contactDTO.Availability = &moira.ContactAvailability{BackupContact: contactID}
+			_, err := UpdateContact(dataBase, auth, contactDTO, moira.ContactData{ID: contactID, User: userLogin})
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("contact can not be backup contact of itself")))
+		})
+	})
+
+	Convey("Set Do Not Disturb", t, func() {
+		quietHours := &moira.QuietHours{StartOffset: 1320, EndOffset: 480}
+		contact := moira.ContactData{ID: contactID, User: userLogin, Availability: &moira.ContactAvailability{QuietHours: quietHours}}
+
+		Convey("Turn on keeps other availability settings", func() {
+			expected := contact
+			expected.Availability = &moira.ContactAvailability{QuietHours: quietHours, DoNotDisturb: true, DoNotDisturbUntil: 1590741878}
+			dataBase.EXPECT().SaveContact(&expected).Return(nil)
+			err := SetContactDoNotDisturb(dataBase, contact, &dto.ContactDoNotDisturb{Enabled: true, Until: 1590741878})
+			So(err, ShouldBeNil)
+			So(contact.Availability.DoNotDisturb, ShouldBeFalse)
+		})
+
+		Convey("Turn off", func() {
+			contact.Availability = &moira.ContactAvailability{DoNotDisturb: true, DoNotDisturbUntil: 1590741878}
+			expected := contact
+			expected.Availability = &moira.ContactAvailability{}
+			dataBase.EXPECT().SaveContact(&expected).Return(nil)
+			err := SetContactDoNotDisturb(dataBase, contact, &dto.ContactDoNotDisturb{Enabled: false})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Contact without availability settings", func() {
+			contact.Availability = nil
+			expected := contact
+			expected.Availability = &moira.ContactAvailability{DoNotDisturb: true}
+			dataBase.EXPECT().SaveContact(&expected).Return(nil)
+			err := SetContactDoNotDisturb(dataBase, contact, &dto.ContactDoNotDisturb{Enabled: true})
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
 func TestIsAllowedContactType(t *testing.T) {
 	const (
 		admin                 = "admin"
@@ -662,6 +787,34 @@ func TestIsAllowedContactType(t *testing.T) {
 	})
 }
 
+func TestPreviewContactMessageTemplate(t *testing.T) {
+	Convey("Test preview contact message template", t, func() {
+		Convey("Plain text template does not escape data", func() {
+			preview, err := PreviewContactMessageTemplate(&dto.MessageTemplateRequest{
+				Template: "{{ range .Events }}{{ .Metric }} > {{ $.Trigger.ErrorValue }}{{ end }}",
+			})
+			So(err, ShouldBeNil)
+			So(preview, ShouldResemble, &dto.MessageTemplatePreview{Message: "server.hdd.used_percent > 95"})
+		})
+
+		Convey("Html template escapes data", func() {
+			preview, err := PreviewContactMessageTemplate(&dto.MessageTemplateRequest{
+				Template: "<b>{{ .Trigger.Name }}</b>{{ printf \"%s > %v\" .Trigger.Description .Trigger.ErrorValue }}",
+				HTML:     true,
+			})
+			So(err, ShouldBeNil)
+			So(preview, ShouldResemble, &dto.MessageTemplatePreview{Message: "<b>Not enough disk space left</b>Check the size of /var/log &gt; 95"})
+		})
+
+		Convey("Template that can't be populated returns error", func() {
+			preview, err := PreviewContactMessageTemplate(&dto.MessageTemplateRequest{Template: "{{ .Unknown }}"})
+			So(err, ShouldNotBeNil)
+			So(err.HTTPStatusCode, ShouldEqual, http.StatusBadRequest)
+			So(preview, ShouldBeNil)
+		})
+	})
+}
+
 func TestRemoveContact(t *testing.T) {
 	const userLogin = "user"
 	const teamID = "team"
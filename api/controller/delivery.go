@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetDeliveryAttempts gets delivery attempts matched by filter, the newest attempts go first.
+func GetDeliveryAttempts(dataBase moira.Database, filter moira.DeliveryAttemptsFilter) (*dto.DeliveryAttemptsList, *api.ErrorResponse) {
+	attempts, err := dataBase.GetDeliveryAttempts(filter)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	list := &dto.DeliveryAttemptsList{
+		List: make([]moira.DeliveryAttempt, 0, len(attempts)),
+	}
+	for _, attempt := range attempts {
+		if attempt != nil {
+			list.List = append(list.List, *attempt)
+		}
+	}
+	return list, nil
+}
+
+// GetDeadLetters gets dead letters saved in given time range, the newest letters go first.
+func GetDeadLetters(dataBase moira.Database, from, to int64) (*dto.DeadLettersList, *api.ErrorResponse) {
+	letters, err := dataBase.GetDeadLetters(from, to)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	list := &dto.DeadLettersList{
+		List: make([]dto.DeadLetter, 0, len(letters)),
+	}
+	for _, letter := range letters {
+		if letter != nil {
+			list.List = append(list.List, dto.NewDeadLetter(*letter))
+		}
+	}
+	return list, nil
+}
+
+// GetDeadLetter gets dead letter by id.
+func GetDeadLetter(dataBase moira.Database, deadLetterID string) (moira.DeadLetter, *api.ErrorResponse) {
+	letter, err := dataBase.GetDeadLetter(deadLetterID)
+	if err != nil {
+		if errors.Is(err, database.ErrNil) {
+			return letter, api.ErrorNotFound(fmt.Sprintf("dead letter with ID = '%s' does not exists", deadLetterID))
+		}
+		return letter, api.ErrorInternalServer(err)
+	}
+	return letter, nil
+}
+
+// RemoveDeadLetter deletes dead letter by id, its notifications are not sent.
+func RemoveDeadLetter(dataBase moira.Database, deadLetterID string) *api.ErrorResponse {
+	if err := dataBase.RemoveDeadLetter(deadLetterID); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
+
+// ReplayDeadLetter schedules notifications of dead letter to be sent to given contact and deletes the letter.
+// Notifications are sent to the original contact if contactID is empty.
+func ReplayDeadLetter(dataBase moira.Database, letter moira.DeadLetter, contactID string) (*dto.DeadLetterReplay, *api.ErrorResponse) {
+	if contactID == "" {
+		contactID = letter.Contact.ID
+	}
+	if contactID == "" {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("dead letter has no original contact, contact_id must be set"))
+	}
+
+	contact, err := dataBase.GetContact(contactID)
+	if err != nil {
+		if errors.Is(err, database.ErrNil) {
+			return nil, api.ErrorNotFound(fmt.Sprintf("contact with ID '%s' does not exists", contactID))
+		}
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	now := time.Now().Unix()
+	notifications := letter.ToScheduledNotifications(contact, now)
+	if err = dataBase.AddNotifications(notifications, now); err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	if err = dataBase.RemoveDeadLetter(letter.ID); err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	return &dto.DeadLetterReplay{
+		DeadLetterID:       letter.ID,
+		ContactID:          contact.ID,
+		NotificationsCount: len(notifications),
+	}, nil
+}
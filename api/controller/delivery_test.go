@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetDeliveryAttempts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	filter := moira.DeliveryAttemptsFilter{ContactID: "contact", Limit: 10}
+
+	Convey("Get delivery attempts", t, func() {
+		Convey("Success", func() {
+			attempts := []*moira.DeliveryAttempt{
+				{Timestamp: 2, ContactID: "contact", Status: moira.DeliveryStatusOK},
+				{Timestamp: 1, ContactID: "contact", Status: moira.DeliveryStatusFailed},
+			}
+			dataBase.EXPECT().GetDeliveryAttempts(filter).Return(attempts, nil)
+			list, err := GetDeliveryAttempts(dataBase, filter)
+			So(err, ShouldBeNil)
+			So(list, ShouldResemble, &dto.DeliveryAttemptsList{List: []moira.DeliveryAttempt{*attempts[0], *attempts[1]}})
+		})
+
+		Convey("Error", func() {
+			expected := fmt.Errorf("oooops! Can not get delivery attempts")
+			dataBase.EXPECT().GetDeliveryAttempts(filter).Return(nil, expected)
+			list, err := GetDeliveryAttempts(dataBase, filter)
+			So(err, ShouldResemble, api.ErrorInternalServer(expected))
+			So(list, ShouldBeNil)
+		})
+	})
+}
+
+func TestGetDeadLetters(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Get dead letters", t, func() {
+		Convey("Success", func() {
+			letters := []*moira.DeadLetter{
+				{ID: "letter-2", Timestamp: 2, Contact: moira.ContactData{ID: "contact", SigningSecret: "secret"}},
+				{ID: "letter-1", Timestamp: 1},
+			}
+			dataBase.EXPECT().GetDeadLetters(int64(1), int64(3)).Return(letters, nil)
+			list, err := GetDeadLetters(dataBase, 1, 3)
+			So(err, ShouldBeNil)
+			So(list, ShouldResemble, &dto.DeadLettersList{List: []dto.DeadLetter{
+				{
+					DeadLetter: moira.DeadLetter{ID: "letter-2", Timestamp: 2, Contact: moira.ContactData{ID: "contact"}},
+					Contact:    dto.ContactData{ContactData: moira.ContactData{ID: "contact"}, HasSigningSecret: true},
+				},
+				{DeadLetter: *letters[1]},
+			}})
+		})
+
+		Convey("Get unknown letter", func() {
+			dataBase.EXPECT().GetDeadLetter("letter").Return(moira.DeadLetter{}, database.ErrNil)
+			_, err := GetDeadLetter(dataBase, "letter")
+			So(err, ShouldResemble, api.ErrorNotFound("dead letter with ID = 'letter' does not exists"))
+		})
+	})
+}
+
+func TestReplayDeadLetter(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	letter := moira.DeadLetter{
+		ID:      "letter",
+		Events:  []moira.NotificationEvent{{Metric: "first"}, {Metric: "second"}},
+		Trigger: moira.TriggerData{ID: "trigger"},
+		Contact: moira.ContactData{ID: "original", Type: "mail", Value: "old@example.com"},
+	}
+	original := moira.ContactData{ID: "original", Type: "mail", Value: "new@example.com"}
+	other := moira.ContactData{ID: "other", Type: "slack", Value: "#alerts"}
+
+	Convey("Replay dead letter", t, func() {
+		Convey("To the original contact", func() {
+			dataBase.EXPECT().GetContact("original").Return(original, nil)
+			dataBase.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).DoAndReturn(func(notifications []*moira.ScheduledNotification, _ int64) error {
+				So(notifications, ShouldHaveLength, 2)
+				So(notifications[0].Contact, ShouldResemble, original)
+				return nil
+			})
+			dataBase.EXPECT().RemoveDeadLetter("letter").Return(nil)
+
+			replay, err := ReplayDeadLetter(dataBase, letter, "")
+			So(err, ShouldBeNil)
+			So(replay, ShouldResemble, &dto.DeadLetterReplay{DeadLetterID: "letter", ContactID: "original", NotificationsCount: 2})
+		})
+
+		Convey("To another contact", func() {
+			dataBase.EXPECT().GetContact("other").Return(other, nil)
+			dataBase.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).DoAndReturn(func(notifications []*moira.ScheduledNotification, _ int64) error {
+				So(notifications[1].Contact, ShouldResemble, other)
+				So(notifications[1].Event.ContactID, ShouldEqual, "other")
+				return nil
+			})
+			dataBase.EXPECT().RemoveDeadLetter("letter").Return(nil)
+
+			replay, err := ReplayDeadLetter(dataBase, letter, "other")
+			So(err, ShouldBeNil)
+			So(replay.ContactID, ShouldEqual, "other")
+		})
+
+		Convey("Unknown contact", func() {
+			dataBase.EXPECT().GetContact("other").Return(moira.ContactData{}, database.ErrNil)
+
+			replay, err := ReplayDeadLetter(dataBase, letter, "other")
+			So(err, ShouldResemble, api.ErrorNotFound("contact with ID 'other' does not exists"))
+			So(replay, ShouldBeNil)
+		})
+
+		Convey("Letter without contact", func() {
+			replay, err := ReplayDeadLetter(dataBase, moira.DeadLetter{ID: "letter"}, "")
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("dead letter has no original contact, contact_id must be set")))
+			So(replay, ShouldBeNil)
+		})
+
+		Convey("Letter is kept if notifications are not saved", func() {
+			expected := fmt.Errorf("oooops! Can not add notifications")
+			dataBase.EXPECT().GetContact("original").Return(original, nil)
+			dataBase.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).Return(expected)
+
+			replay, err := ReplayDeadLetter(dataBase, letter, "")
+			So(err, ShouldResemble, api.ErrorInternalServer(expected))
+			So(replay, ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,342 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+const (
+	eventsStreamReadCount = 100
+	// eventsStreamTriggersCacheTTL is a period after which changed tags and teams of triggers are taken into account by filter.
+	eventsStreamTriggersCacheTTL = time.Minute
+	// eventsStreamSubscriptionBuffer is a count of reads subscription can lag behind stream,
+	// lagging subscription is closed and its client continues reading from last received event after reconnection.
+	eventsStreamSubscriptionBuffer = 100
+)
+
+var eventsStreamIDPattern = regexp.MustCompile(`^\d+-\d+$`)
+
+var errEventsStreamSubscriptionLags = errors.New("subscription lags behind events stream")
+
+// EventsStream reads events stream with one reader and fans out events to all subscriptions,
+// so count of database requests does not depend on count of clients. Stream is read only while it has subscriptions.
+type EventsStream struct {
+	database     moira.Database
+	pollInterval time.Duration
+	triggers     *eventsStreamTriggers
+
+	mutex         sync.Mutex
+	running       bool
+	cursor        string
+	subscriptions map[*EventsStreamSubscription]struct{}
+}
+
+// EventsStreamSubscription receives events of EventsStream which match its filter.
+type EventsStreamSubscription struct {
+	// Backlog holds matching events added after last event id of client and before subscription.
+	Backlog []dto.EventsStreamEvent
+
+	filter     dto.EventsStreamFilter
+	triggerIDs map[string]bool
+	// skipUntil is the last event id of client which is ahead of stream, events up to it are not sent again.
+	skipUntil string
+	events    chan []dto.EventsStreamEvent
+	err       error
+}
+
+// NewEventsStream creates events stream which reads new events every pollInterval.
+func NewEventsStream(dataBase moira.Database, pollInterval time.Duration) *EventsStream {
+	return &EventsStream{
+		database:      dataBase,
+		pollInterval:  pollInterval,
+		triggers:      &eventsStreamTriggers{database: dataBase, triggers: make(map[string]*moira.Trigger)},
+		subscriptions: make(map[*EventsStreamSubscription]struct{}),
+	}
+}
+
+// Subscribe returns subscription to events which match filter and are added after subscription.
+// If lastEventID is given, events added after it and before subscription are returned in Backlog of subscription.
+func (stream *EventsStream) Subscribe(filter dto.EventsStreamFilter, lastEventID string) (*EventsStreamSubscription, *api.ErrorResponse) {
+	if lastEventID != "" && !eventsStreamIDPattern.MatchString(lastEventID) {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("invalid last event id: %s", lastEventID))
+	}
+
+	triggerIDs := make(map[string]bool, len(filter.TriggerIDs))
+	for _, triggerID := range filter.TriggerIDs {
+		triggerIDs[triggerID] = true
+	}
+	subscription := &EventsStreamSubscription{
+		filter:     filter,
+		triggerIDs: triggerIDs,
+		events:     make(chan []dto.EventsStreamEvent, eventsStreamSubscriptionBuffer),
+	}
+
+	stream.mutex.Lock()
+	if !stream.running {
+		cursor, err := stream.database.GetNotificationEventsStreamLastID()
+		if err != nil {
+			stream.mutex.Unlock()
+			return nil, api.ErrorInternalServer(err)
+		}
+		stream.cursor = cursor
+		stream.running = true
+		go stream.run()
+	}
+	streamCursor := stream.cursor
+	if lastEventID != "" && compareEventsStreamIDs(lastEventID, streamCursor) > 0 {
+		subscription.skipUntil = lastEventID
+	}
+	stream.subscriptions[subscription] = struct{}{}
+	stream.mutex.Unlock()
+
+	if lastEventID == "" || compareEventsStreamIDs(lastEventID, streamCursor) >= 0 {
+		return subscription, nil
+	}
+
+	entries, _, err := stream.read(lastEventID, streamCursor)
+	if err == nil {
+		subscription.Backlog, err = stream.filter(subscription, entries)
+	}
+	if err != nil {
+		stream.Unsubscribe(subscription)
+		return nil, api.ErrorInternalServer(err)
+	}
+	return subscription, nil
+}
+
+// Unsubscribe stops sending events to subscription.
+func (stream *EventsStream) Unsubscribe(subscription *EventsStreamSubscription) {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+	delete(stream.subscriptions, subscription)
+}
+
+// Events returns channel of matching events, it is closed if stream closes subscription, Err returns the reason.
+func (subscription *EventsStreamSubscription) Events() <-chan []dto.EventsStreamEvent {
+	return subscription.events
+}
+
+// Err returns the reason subscription was closed by stream.
+func (subscription *EventsStreamSubscription) Err() error {
+	return subscription.err
+}
+
+func (stream *EventsStream) run() {
+	ticker := time.NewTicker(stream.pollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !stream.poll() {
+			return
+		}
+	}
+}
+
+// poll reads events added since previous poll and sends them to subscriptions.
+// False is returned if stream has no subscriptions anymore and stops reading.
+func (stream *EventsStream) poll() bool {
+	stream.mutex.Lock()
+	if len(stream.subscriptions) == 0 {
+		stream.running = false
+		stream.mutex.Unlock()
+		return false
+	}
+	cursor := stream.cursor
+	stream.mutex.Unlock()
+
+	entries, lastID, err := stream.read(cursor, "")
+
+	stream.mutex.Lock()
+	if err != nil {
+		for subscription := range stream.subscriptions {
+			stream.close(subscription, err)
+		}
+		stream.running = false
+		stream.mutex.Unlock()
+		return false
+	}
+	// Cursor is moved past skipped undecodable events too, so they do not stop reading of stream.
+	stream.cursor = lastID
+	if len(entries) == 0 {
+		stream.mutex.Unlock()
+		return true
+	}
+	// Subscriptions are taken with new cursor at once, so subscription made later receives these events in backlog.
+	subscriptions := make([]*EventsStreamSubscription, 0, len(stream.subscriptions))
+	for subscription := range stream.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	stream.mutex.Unlock()
+
+	for _, subscription := range subscriptions {
+		events, err := stream.filter(subscription, entries)
+		if err != nil {
+			stream.closeSubscription(subscription, err)
+			continue
+		}
+		if len(events) == 0 {
+			continue
+		}
+		select {
+		case subscription.events <- events:
+		default:
+			stream.closeSubscription(subscription, errEventsStreamSubscriptionLags)
+		}
+	}
+	return true
+}
+
+func (stream *EventsStream) closeSubscription(subscription *EventsStreamSubscription, err error) {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+	if _, ok := stream.subscriptions[subscription]; ok {
+		stream.close(subscription, err)
+	}
+}
+
+// close must be called with locked mutex, only stream goroutine closes subscriptions.
+func (stream *EventsStream) close(subscription *EventsStreamSubscription, err error) {
+	delete(stream.subscriptions, subscription)
+	subscription.err = err
+	close(subscription.events)
+}
+
+// read returns all entries added to stream after cursor, entries after until are not returned if it is given.
+// ID of the last read entry is returned to continue reading after it, it is the cursor if nothing is read.
+func (stream *EventsStream) read(cursor, until string) ([]moira.NotificationEventsStreamEntry, string, error) {
+	result := make([]moira.NotificationEventsStreamEntry, 0)
+	for {
+		entries, lastID, err := stream.database.GetNotificationEventsStream(cursor, eventsStreamReadCount)
+		if err != nil {
+			return nil, "", err
+		}
+
+		for _, entry := range entries {
+			if until != "" && compareEventsStreamIDs(entry.ID, until) > 0 {
+				return result, cursor, nil
+			}
+			result = append(result, entry)
+			cursor = entry.ID
+		}
+		if lastID != "" {
+			cursor = lastID
+		}
+
+		if len(entries) < eventsStreamReadCount {
+			return result, cursor, nil
+		}
+	}
+}
+
+func (stream *EventsStream) filter(subscription *EventsStreamSubscription, entries []moira.NotificationEventsStreamEntry) ([]dto.EventsStreamEvent, error) {
+	events := make([]dto.EventsStreamEvent, 0)
+	for _, entry := range entries {
+		if subscription.skipUntil != "" && compareEventsStreamIDs(entry.ID, subscription.skipUntil) <= 0 {
+			continue
+		}
+		matches, err := stream.matches(subscription, &entry.Event)
+		if err != nil {
+			return nil, err
+		}
+		if !matches {
+			continue
+		}
+
+		eventType := dto.EventsStreamEventMetric
+		if entry.Event.IsTriggerEvent {
+			eventType = dto.EventsStreamEventTrigger
+		}
+		events = append(events, dto.EventsStreamEvent{ID: entry.ID, Type: eventType, Event: entry.Event})
+	}
+	return events, nil
+}
+
+func (stream *EventsStream) matches(subscription *EventsStreamSubscription, event *moira.NotificationEvent) (bool, error) {
+	if len(subscription.triggerIDs) > 0 && !subscription.triggerIDs[event.TriggerID] {
+		return false, nil
+	}
+	if len(subscription.filter.Tags) == 0 && subscription.filter.TeamID == "" {
+		return true, nil
+	}
+
+	trigger, err := stream.triggers.get(event.TriggerID)
+	if err != nil || trigger == nil {
+		return false, err
+	}
+	if subscription.filter.TeamID != "" && trigger.TeamID != subscription.filter.TeamID {
+		return false, nil
+	}
+	return moira.Subset(subscription.filter.Tags, trigger.Tags), nil
+}
+
+// eventsStreamTriggers caches triggers to match events by tags and team, cache is shared by all subscriptions.
+type eventsStreamTriggers struct {
+	database moira.Database
+	mutex    sync.Mutex
+	triggers map[string]*moira.Trigger
+	cleanAt  time.Time
+}
+
+// get returns cached trigger, nil is returned for removed trigger.
+func (cache *eventsStreamTriggers) get(triggerID string) (*moira.Trigger, error) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if time.Now().After(cache.cleanAt) {
+		cache.triggers = make(map[string]*moira.Trigger)
+		cache.cleanAt = time.Now().Add(eventsStreamTriggersCacheTTL)
+	}
+	if trigger, ok := cache.triggers[triggerID]; ok {
+		return trigger, nil
+	}
+
+	trigger, err := cache.database.GetTrigger(triggerID)
+	if err != nil && !errors.Is(err, database.ErrNil) {
+		return nil, err
+	}
+
+	var result *moira.Trigger
+	if err == nil {
+		result = &trigger
+	}
+	cache.triggers[triggerID] = result
+	return result, nil
+}
+
+// compareEventsStreamIDs compares IDs of events stream entries, IDs consist of timestamp and sequence number.
+func compareEventsStreamIDs(first, second string) int {
+	firstTimestamp, firstSequence := parseEventsStreamID(first)
+	secondTimestamp, secondSequence := parseEventsStreamID(second)
+	if firstTimestamp != secondTimestamp {
+		return compareUint(firstTimestamp, secondTimestamp)
+	}
+	return compareUint(firstSequence, secondSequence)
+}
+
+func parseEventsStreamID(id string) (uint64, uint64) {
+	timestamp, sequence, _ := strings.Cut(id, "-")
+	parsedTimestamp, _ := strconv.ParseUint(timestamp, 10, 64)
+	parsedSequence, _ := strconv.ParseUint(sequence, 10, 64)
+	return parsedTimestamp, parsedSequence
+}
+
+func compareUint(first, second uint64) int {
+	switch {
+	case first < second:
+		return -1
+	case first > second:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEventsStream(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	defer mockCtrl.Finish()
+
+	metricEvent := moira.NotificationEvent{TriggerID: "trigger1", Metric: "my.metric", State: moira.StateERROR, OldState: moira.StateOK}
+	triggerEvent := moira.NotificationEvent{TriggerID: "trigger2", IsTriggerEvent: true, State: moira.StateOK, OldState: moira.StateERROR}
+	removedTriggerEvent := moira.NotificationEvent{TriggerID: "removed", State: moira.StateOK, OldState: moira.StateERROR}
+	entries := []moira.NotificationEventsStreamEntry{
+		{ID: "1-0", Event: metricEvent},
+		{ID: "2-0", Event: triggerEvent},
+		{ID: "3-0", Event: removedTriggerEvent},
+	}
+
+	receive := func(subscription *EventsStreamSubscription) ([]dto.EventsStreamEvent, bool) {
+		select {
+		case events, ok := <-subscription.Events():
+			return events, ok
+		case <-time.After(time.Second):
+			return nil, false
+		}
+	}
+
+	Convey("Test events stream", t, func() {
+		Convey("Subscription without last event id receives events added after it", func() {
+			stream := NewEventsStream(dataBase, time.Millisecond)
+			dataBase.EXPECT().GetNotificationEventsStreamLastID().Return("5-0", nil)
+			dataBase.EXPECT().GetNotificationEventsStream("5-0", int64(eventsStreamReadCount)).Return([]moira.NotificationEventsStreamEntry{{ID: "6-0", Event: metricEvent}}, "6-0", nil)
+			dataBase.EXPECT().GetNotificationEventsStream("6-0", int64(eventsStreamReadCount)).Return(nil, "", nil).AnyTimes()
+
+			subscription, err := stream.Subscribe(dto.EventsStreamFilter{}, "")
+			So(err, ShouldBeNil)
+			defer stream.Unsubscribe(subscription)
+			So(subscription.Backlog, ShouldBeEmpty)
+
+			events, ok := receive(subscription)
+			So(ok, ShouldBeTrue)
+			So(events, ShouldResemble, []dto.EventsStreamEvent{{ID: "6-0", Type: dto.EventsStreamEventMetric, Event: metricEvent}})
+		})
+
+		Convey("Invalid last event id", func() {
+			stream := NewEventsStream(dataBase, time.Hour)
+			_, err := stream.Subscribe(dto.EventsStreamFilter{}, "last")
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("invalid last event id: last")))
+		})
+
+		Convey("Events after last event id are returned in backlog", func() {
+			stream := NewEventsStream(dataBase, time.Hour)
+			dataBase.EXPECT().GetNotificationEventsStreamLastID().Return("3-0", nil)
+			dataBase.EXPECT().GetNotificationEventsStream("0-0", int64(eventsStreamReadCount)).Return(entries, "3-0", nil)
+
+			subscription, err := stream.Subscribe(dto.EventsStreamFilter{}, "0-0")
+			So(err, ShouldBeNil)
+			So(subscription.Backlog, ShouldResemble, []dto.EventsStreamEvent{
+				{ID: "1-0", Type: dto.EventsStreamEventMetric, Event: metricEvent},
+				{ID: "2-0", Type: dto.EventsStreamEventTrigger, Event: triggerEvent},
+				{ID: "3-0", Type: dto.EventsStreamEventMetric, Event: removedTriggerEvent},
+			})
+		})
+
+		Convey("Backlog does not include events read by stream after subscription", func() {
+			stream := NewEventsStream(dataBase, time.Hour)
+			dataBase.EXPECT().GetNotificationEventsStreamLastID().Return("2-0", nil)
+			dataBase.EXPECT().GetNotificationEventsStream("0-0", int64(eventsStreamReadCount)).Return(entries, "3-0", nil)
+
+			subscription, err := stream.Subscribe(dto.EventsStreamFilter{}, "0-0")
+			So(err, ShouldBeNil)
+			So(subscription.Backlog, ShouldHaveLength, 2)
+			So(subscription.Backlog[1].ID, ShouldEqual, "2-0")
+		})
+
+		Convey("Events client already has are not sent again", func() {
+			stream := NewEventsStream(dataBase, time.Millisecond)
+			dataBase.EXPECT().GetNotificationEventsStreamLastID().Return("2-0", nil)
+			dataBase.EXPECT().GetNotificationEventsStream("2-0", int64(eventsStreamReadCount)).Return([]moira.NotificationEventsStreamEntry{
+				{ID: "3-0", Event: removedTriggerEvent},
+				{ID: "4-0", Event: metricEvent},
+			}, "4-0", nil)
+			dataBase.EXPECT().GetNotificationEventsStream("4-0", int64(eventsStreamReadCount)).Return(nil, "", nil).AnyTimes()
+
+			subscription, err := stream.Subscribe(dto.EventsStreamFilter{}, "3-0")
+			So(err, ShouldBeNil)
+			defer stream.Unsubscribe(subscription)
+			So(subscription.Backlog, ShouldBeEmpty)
+
+			events, ok := receive(subscription)
+			So(ok, ShouldBeTrue)
+			So(events, ShouldResemble, []dto.EventsStreamEvent{{ID: "4-0", Type: dto.EventsStreamEventMetric, Event: metricEvent}})
+		})
+
+		Convey("Events are filtered by trigger ids", func() {
+			stream := NewEventsStream(dataBase, time.Hour)
+			dataBase.EXPECT().GetNotificationEventsStreamLastID().Return("3-0", nil)
+			dataBase.EXPECT().GetNotificationEventsStream("0-0", int64(eventsStreamReadCount)).Return(entries, "3-0", nil)
+
+			subscription, err := stream.Subscribe(dto.EventsStreamFilter{TriggerIDs: []string{"trigger2"}}, "0-0")
+			So(err, ShouldBeNil)
+			So(subscription.Backlog, ShouldHaveLength, 1)
+			So(subscription.Backlog[0].ID, ShouldEqual, "2-0")
+		})
+
+		Convey("Events are filtered by tags and team of cached triggers", func() {
+			stream := NewEventsStream(dataBase, time.Hour)
+			dataBase.EXPECT().GetNotificationEventsStreamLastID().Return("4-0", nil)
+			dataBase.EXPECT().GetNotificationEventsStream("0-0", int64(eventsStreamReadCount)).Return(append(entries, moira.NotificationEventsStreamEntry{ID: "4-0", Event: metricEvent}), "4-0", nil)
+			dataBase.EXPECT().GetTrigger("trigger1").Return(moira.Trigger{ID: "trigger1", Tags: []string{"db", "cpu"}, TeamID: "team"}, nil)
+			dataBase.EXPECT().GetTrigger("trigger2").Return(moira.Trigger{ID: "trigger2", Tags: []string{"db"}}, nil)
+			dataBase.EXPECT().GetTrigger("removed").Return(moira.Trigger{}, database.ErrNil)
+
+			subscription, err := stream.Subscribe(dto.EventsStreamFilter{Tags: []string{"db"}, TeamID: "team"}, "0-0")
+			So(err, ShouldBeNil)
+			So(subscription.Backlog, ShouldHaveLength, 2)
+			So(subscription.Backlog[0].ID, ShouldEqual, "1-0")
+			So(subscription.Backlog[1].ID, ShouldEqual, "4-0")
+		})
+
+		Convey("Stream is read once for all subscriptions", func() {
+			stream := NewEventsStream(dataBase, 50*time.Millisecond)
+			dataBase.EXPECT().GetNotificationEventsStreamLastID().Return("0-0", nil)
+			dataBase.EXPECT().GetNotificationEventsStream("0-0", int64(eventsStreamReadCount)).Return(entries, "3-0", nil).Times(1)
+			dataBase.EXPECT().GetNotificationEventsStream("3-0", int64(eventsStreamReadCount)).Return(nil, "", nil).AnyTimes()
+
+			all, err := stream.Subscribe(dto.EventsStreamFilter{}, "")
+			So(err, ShouldBeNil)
+			defer stream.Unsubscribe(all)
+			filtered, err := stream.Subscribe(dto.EventsStreamFilter{TriggerIDs: []string{"trigger2"}}, "")
+			So(err, ShouldBeNil)
+			defer stream.Unsubscribe(filtered)
+
+			events, ok := receive(all)
+			So(ok, ShouldBeTrue)
+			So(events, ShouldHaveLength, 3)
+			events, ok = receive(filtered)
+			So(ok, ShouldBeTrue)
+			So(events, ShouldResemble, []dto.EventsStreamEvent{{ID: "2-0", Type: dto.EventsStreamEventTrigger, Event: triggerEvent}})
+		})
+
+		Convey("Stream continues after skipped undecodable events", func() {
+			stream := NewEventsStream(dataBase, time.Millisecond)
+			dataBase.EXPECT().GetNotificationEventsStreamLastID().Return("0-0", nil)
+			dataBase.EXPECT().GetNotificationEventsStream("0-0", int64(eventsStreamReadCount)).Return(nil, "1-0", nil)
+			dataBase.EXPECT().GetNotificationEventsStream("1-0", int64(eventsStreamReadCount)).Return([]moira.NotificationEventsStreamEntry{{ID: "2-0", Event: triggerEvent}}, "2-0", nil)
+			dataBase.EXPECT().GetNotificationEventsStream("2-0", int64(eventsStreamReadCount)).Return(nil, "", nil).AnyTimes()
+
+			subscription, err := stream.Subscribe(dto.EventsStreamFilter{}, "")
+			So(err, ShouldBeNil)
+			defer stream.Unsubscribe(subscription)
+
+			events, ok := receive(subscription)
+			So(ok, ShouldBeTrue)
+			So(events, ShouldResemble, []dto.EventsStreamEvent{{ID: "2-0", Type: dto.EventsStreamEventTrigger, Event: triggerEvent}})
+		})
+
+		Convey("Database error closes subscriptions", func() {
+			stream := NewEventsStream(dataBase, time.Millisecond)
+			dataBase.EXPECT().GetNotificationEventsStreamLastID().Return("0-0", nil)
+			dataBase.EXPECT().GetNotificationEventsStream("0-0", int64(eventsStreamReadCount)).Return(nil, "", fmt.Errorf("error"))
+
+			subscription, err := stream.Subscribe(dto.EventsStreamFilter{}, "")
+			So(err, ShouldBeNil)
+			_, ok := receive(subscription)
+			So(ok, ShouldBeFalse)
+			So(subscription.Err(), ShouldResemble, fmt.Errorf("error"))
+		})
+	})
+}
+
+func TestCompareEventsStreamIDs(t *testing.T) {
+	Convey("IDs are compared by timestamp and then by sequence number", t, func() {
+		So(compareEventsStreamIDs("1-0", "1-0"), ShouldEqual, 0)
+		So(compareEventsStreamIDs("2-0", "10-0"), ShouldEqual, -1)
+		So(compareEventsStreamIDs("10-2", "10-1"), ShouldEqual, 1)
+	})
+}
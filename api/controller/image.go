@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/image_store/filesystem"
+)
+
+// GetImage gets image stored by notifier if link to it is signed and not expired.
+func GetImage(imageServer api.ImageServer, imageID string, expires int64, signature string) ([]byte, moira.PlotFormat, *api.ErrorResponse) {
+	image, format, err := imageServer.GetImage(imageID, expires, signature)
+	switch {
+	case err == nil:
+		return image, format, nil
+	case errors.Is(err, filesystem.ErrInvalidSignature), errors.Is(err, filesystem.ErrLinkExpired):
+		return nil, "", api.ErrorForbidden(err.Error())
+	case errors.Is(err, filesystem.ErrImageNotFound):
+		return nil, "", api.ErrorNotFound(err.Error())
+	default:
+		return nil, "", api.ErrorInternalServer(err)
+	}
+}
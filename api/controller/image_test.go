@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/image_store/filesystem"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testImageServer struct {
+	image []byte
+	err   error
+}
+
+func (server *testImageServer) GetImage(string, int64, string) ([]byte, moira.PlotFormat, error) {
+	return server.image, moira.GetPlotFormat(server.image), server.err
+}
+
+func TestGetImage(t *testing.T) {
+	Convey("Get image", t, func() {
+		Convey("Image found", func() {
+			image := []byte("\x89PNG\r\n\x1a\n")
+			actual, format, err := GetImage(&testImageServer{image: image}, "id.png", 1, "signature")
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, image)
+			So(format, ShouldEqual, moira.PlotFormatPNG)
+		})
+
+		Convey("Link is expired", func() {
+			_, _, err := GetImage(&testImageServer{err: filesystem.ErrLinkExpired}, "id.png", 1, "signature")
+			So(err.HTTPStatusCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Signature is invalid", func() {
+			_, _, err := GetImage(&testImageServer{err: filesystem.ErrInvalidSignature}, "id.png", 1, "signature")
+			So(err.HTTPStatusCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Image is removed", func() {
+			_, _, err := GetImage(&testImageServer{err: filesystem.ErrImageNotFound}, "id.png", 1, "signature")
+			So(err.HTTPStatusCode, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Image can't be read", func() {
+			_, _, err := GetImage(&testImageServer{err: errors.New("disk error")}, "id.png", 1, "signature")
+			So(err.HTTPStatusCode, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
@@ -13,9 +13,12 @@ func GetNotifications(database moira.Database, start int64, end int64) (*dto.Not
 		return nil, api.ErrorInternalServer(err)
 	}
 	notificationsList := dto.NotificationsList{
-		List:  notifications,
+		List:  make([]*dto.ScheduledNotification, 0, len(notifications)),
 		Total: total,
 	}
+	for _, notification := range notifications {
+		notificationsList.List = append(notificationsList.List, dto.NewScheduledNotification(*notification))
+	}
 	return &notificationsList, nil
 }
 
@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/moira-alert/go-chart"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/moira-alert/moira/plotting"
+)
+
+const (
+	// previewPlotTimeRange is time range of plots before the first event.
+	previewPlotTimeRange = 30 * time.Minute
+	// previewPlotTimeShift is time range of plots after the last event.
+	previewPlotTimeShift = time.Minute
+	// previewSampleMetric is metric name of sample event used when trigger has no targets.
+	previewSampleMetric = "server.hdd.used_percent"
+)
+
+// BuildNotificationPreview renders notification the way sender of given contact type delivers it, nothing is sent.
+func BuildNotificationPreview(
+	dataBase moira.Database,
+	metricSourceProvider *metricSource.SourceProvider,
+	preview api.NotificationPreview,
+	request *dto.NotificationPreviewRequest,
+) (*dto.NotificationPreview, *api.ErrorResponse) {
+	builder, ok := preview.Builders[request.ContactType]
+	if !ok {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("notification preview is not supported for contact type %s", request.ContactType))
+	}
+
+	trigger, errorResponse := getNotificationPreviewTrigger(dataBase, request)
+	if errorResponse != nil {
+		return nil, errorResponse
+	}
+
+	events, errorResponse := getNotificationPreviewEvents(dataBase, trigger, request)
+	if errorResponse != nil {
+		return nil, errorResponse
+	}
+
+	triggerData := moira.TriggerData{
+		ID:            trigger.ID,
+		Name:          trigger.Name,
+		Desc:          moira.UseString(trigger.Desc),
+		Targets:       trigger.Targets,
+		WarnValue:     moira.UseFloat64(trigger.WarnValue),
+		ErrorValue:    moira.UseFloat64(trigger.ErrorValue),
+		IsRemote:      trigger.TriggerSource == moira.GraphiteRemote,
+		TriggerSource: trigger.TriggerSource,
+		ClusterId:     trigger.ClusterId,
+		Tags:          trigger.Tags,
+	}
+	if err := triggerData.PopulatedDescription(events); err != nil {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("failed to populate trigger description: %w", err))
+	}
+
+	contact := moira.ContactData{
+		Type:            request.ContactType,
+		Value:           request.ContactValue,
+		MessageTemplate: request.MessageTemplate,
+	}
+
+	var plots [][]byte
+	if request.Plotting != nil && request.Plotting.Enabled && request.TriggerID != "" {
+		var err error
+		plots, err = buildNotificationPreviewPlots(dataBase, metricSourceProvider, trigger, events, *request.Plotting, preview.Location)
+		if err != nil {
+			return nil, api.ErrorInternalServer(fmt.Errorf("failed to build plots: %w", err))
+		}
+	}
+
+	payload, err := builder.BuildMessage(events, contact, triggerData, plots, request.Throttled)
+	if err != nil {
+		return nil, api.ErrorInternalServer(fmt.Errorf("failed to build message: %w", err))
+	}
+
+	return &dto.NotificationPreview{
+		ContactType:         request.ContactType,
+		NotificationPayload: payload,
+		Plots:               plots,
+	}, nil
+}
+
+func getNotificationPreviewTrigger(dataBase moira.Database, request *dto.NotificationPreviewRequest) (*moira.Trigger, *api.ErrorResponse) {
+	if request.Trigger != nil {
+		return request.Trigger.ToMoiraTrigger(), nil
+	}
+
+	trigger, err := dataBase.GetTrigger(request.TriggerID)
+	if err != nil {
+		if errors.Is(err, database.ErrNil) {
+			return nil, api.ErrorNotFound(fmt.Sprintf("trigger with ID = '%s' does not exists", request.TriggerID))
+		}
+		return nil, api.ErrorInternalServer(err)
+	}
+	return &trigger, nil
+}
+
+func getNotificationPreviewEvents(dataBase moira.Database, trigger *moira.Trigger, request *dto.NotificationPreviewRequest) (moira.NotificationEvents, *api.ErrorResponse) {
+	if len(request.Events) > 0 {
+		return request.Events, nil
+	}
+
+	if request.From == 0 && request.To == 0 {
+		return moira.NotificationEvents{getNotificationPreviewSampleEvent(trigger)}, nil
+	}
+
+	to := request.To
+	if to == 0 {
+		to = time.Now().Unix()
+	}
+	historyEvents, err := dataBase.GetNotificationEventsByTime(trigger.ID, request.From, to)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	if len(historyEvents) == 0 {
+		return nil, api.ErrorNotFound(fmt.Sprintf("trigger with ID = '%s' has no events in given time range", trigger.ID))
+	}
+
+	events := make(moira.NotificationEvents, 0, len(historyEvents))
+	for _, event := range historyEvents {
+		events = append(events, *event)
+	}
+	return events, nil
+}
+
+func getNotificationPreviewSampleEvent(trigger *moira.Trigger) moira.NotificationEvent {
+	metric := previewSampleMetric
+	if len(trigger.Targets) > 0 {
+		metric = trigger.Targets[0]
+	}
+
+	value := moira.UseFloat64(trigger.ErrorValue)
+	return moira.NotificationEvent{
+		Timestamp: time.Now().Unix(),
+		Metric:    metric,
+		Values:    map[string]float64{"t1": value},
+		State:     moira.StateERROR,
+		OldState:  moira.StateWARN,
+		TriggerID: trigger.ID,
+	}
+}
+
+func buildNotificationPreviewPlots(
+	dataBase moira.Database,
+	metricSourceProvider *metricSource.SourceProvider,
+	trigger *moira.Trigger,
+	events moira.NotificationEvents,
+	plottingData moira.PlottingData,
+	location *time.Location,
+) ([][]byte, error) {
+	timeRange := previewPlotTimeRange
+	if plottingData.TimeRange > 0 {
+		timeRange = time.Duration(plottingData.TimeRange) * time.Second
+	}
+	from := time.Unix(events[0].Timestamp, 0).Add(-timeRange).Unix()
+	to := time.Unix(events[len(events)-1].Timestamp, 0).Add(previewPlotTimeShift).Unix()
+
+	metricsData, _, err := GetTriggerEvaluationResult(dataBase, metricSourceProvider, from, to, trigger.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	plotTemplate, err := plotting.GetPlotTemplate(plottingData.Theme, location)
+	if err != nil {
+		return nil, err
+	}
+	plotTemplate = plotTemplate.WithSize(plottingData.Width, plottingData.Height)
+
+	eventMetrics := make(map[string]bool, len(events))
+	for _, event := range events {
+		eventMetrics[event.Metric] = true
+	}
+	for targetName, metrics := range metricsData {
+		metrics = filterNotificationPreviewMetrics(metrics, eventMetrics)
+		if len(metrics) == 0 {
+			delete(metricsData, targetName)
+			continue
+		}
+		metricsData[targetName] = metrics
+	}
+
+	annotations := plotting.GetEventsAnnotations(events)
+	renderables := make([]chart.Chart, 0, len(metricsData))
+	if plottingData.Overlay && len(metricsData) > 0 {
+		renderable, err := plotTemplate.GetOverlayRenderable(trigger, metricsData, annotations)
+		if err != nil {
+			return nil, err
+		}
+		renderables = append(renderables, renderable)
+	} else {
+		for targetName, metrics := range metricsData {
+			renderable, err := plotTemplate.GetRenderableWithAnnotations(targetName, trigger, metrics, annotations)
+			if err != nil {
+				return nil, err
+			}
+			renderables = append(renderables, renderable)
+		}
+	}
+
+	plots := make([][]byte, 0, len(renderables))
+	for _, renderable := range renderables {
+		buff := bytes.NewBuffer(make([]byte, 0))
+		if err = plotting.Render(renderable, plottingData.Format, buff); err != nil {
+			return nil, err
+		}
+		plots = append(plots, buff.Bytes())
+	}
+	return plots, nil
+}
+
+// filterNotificationPreviewMetrics keeps metrics of events the same way notifier does, single metric of target is always kept.
+func filterNotificationPreviewMetrics(metrics []metricSource.MetricData, eventMetrics map[string]bool) []metricSource.MetricData {
+	if len(metrics) <= 1 {
+		return metrics
+	}
+	filtered := make([]metricSource.MetricData, 0, len(metrics))
+	for _, metricData := range metrics {
+		if eventMetrics[metricData.Name] {
+			filtered = append(filtered, metricData)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBuildNotificationPreview(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	builder := mock_moira_alert.NewMockMessageBuilder(mockCtrl)
+	preview := api.NotificationPreview{
+		Enabled:  true,
+		Builders: map[string]moira.MessageBuilder{"slack": builder},
+	}
+
+	triggerID := "trigger-id"
+	errorValue := 95.0
+	desc := "Metric {{ (index .Events 0).Metric }} is broken"
+	trigger := moira.Trigger{
+		ID:         triggerID,
+		Name:       "Not enough disk space left",
+		Desc:       &desc,
+		Targets:    []string{"server.hdd.used_percent"},
+		ErrorValue: &errorValue,
+		Tags:       []string{"server"},
+	}
+	events := moira.NotificationEvents{{Metric: "server.hdd.used_percent", State: moira.StateERROR, OldState: moira.StateOK, Timestamp: 100}}
+	payload := moira.NotificationPayload{ContentType: "text/markdown", Body: "ERROR"}
+
+	Convey("Test BuildNotificationPreview", t, func() {
+		Convey("Unsupported contact type", func() {
+			request := &dto.NotificationPreviewRequest{TriggerID: triggerID, ContactType: "mail"}
+			actual, err := BuildNotificationPreview(dataBase, nil, preview, request)
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("notification preview is not supported for contact type mail")))
+			So(actual, ShouldBeNil)
+		})
+
+		Convey("Trigger does not exist", func() {
+			request := &dto.NotificationPreviewRequest{TriggerID: triggerID, ContactType: "slack"}
+			dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{}, database.ErrNil)
+			actual, err := BuildNotificationPreview(dataBase, nil, preview, request)
+			So(err, ShouldResemble, api.ErrorNotFound(fmt.Sprintf("trigger with ID = '%s' does not exists", triggerID)))
+			So(actual, ShouldBeNil)
+		})
+
+		Convey("Events from request", func() {
+			request := &dto.NotificationPreviewRequest{
+				TriggerID:    triggerID,
+				ContactType:  "slack",
+				ContactValue: "#alerts",
+				Events:       events,
+				Throttled:    true,
+			}
+			dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
+			builder.EXPECT().BuildMessage(
+				events,
+				moira.ContactData{Type: "slack", Value: "#alerts"},
+				moira.TriggerData{
+					ID:         triggerID,
+					Name:       trigger.Name,
+					Desc:       "Metric server.hdd.used_percent is broken",
+					Targets:    trigger.Targets,
+					ErrorValue: errorValue,
+					Tags:       trigger.Tags,
+				},
+				nil,
+				true,
+			).Return(payload, nil)
+
+			actual, err := BuildNotificationPreview(dataBase, nil, preview, request)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, &dto.NotificationPreview{ContactType: "slack", NotificationPayload: payload})
+		})
+
+		Convey("Events from history", func() {
+			request := &dto.NotificationPreviewRequest{TriggerID: triggerID, ContactType: "slack", From: 50, To: 150}
+			dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
+			dataBase.EXPECT().GetNotificationEventsByTime(triggerID, int64(50), int64(150)).Return([]*moira.NotificationEvent{&events[0]}, nil)
+			builder.EXPECT().BuildMessage(events, gomock.Any(), gomock.Any(), nil, false).Return(payload, nil)
+
+			actual, err := BuildNotificationPreview(dataBase, nil, preview, request)
+			So(err, ShouldBeNil)
+			So(actual.Body, ShouldEqual, "ERROR")
+		})
+
+		Convey("No events in history", func() {
+			request := &dto.NotificationPreviewRequest{TriggerID: triggerID, ContactType: "slack", From: 50, To: 150}
+			dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
+			dataBase.EXPECT().GetNotificationEventsByTime(triggerID, int64(50), int64(150)).Return([]*moira.NotificationEvent{}, nil)
+
+			actual, err := BuildNotificationPreview(dataBase, nil, preview, request)
+			So(err, ShouldResemble, api.ErrorNotFound(fmt.Sprintf("trigger with ID = '%s' has no events in given time range", triggerID)))
+			So(actual, ShouldBeNil)
+		})
+
+		Convey("Sample event for trigger from request", func() {
+			request := &dto.NotificationPreviewRequest{
+				Trigger:     &dto.TriggerModel{Name: "New trigger", Targets: []string{"server.cpu"}, ErrorValue: &errorValue},
+				ContactType: "slack",
+			}
+			var actualEvents moira.NotificationEvents
+			builder.EXPECT().BuildMessage(gomock.Any(), gomock.Any(), gomock.Any(), nil, false).DoAndReturn(
+				func(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+					actualEvents = events
+					return payload, nil
+				})
+
+			actual, err := BuildNotificationPreview(dataBase, nil, preview, request)
+			So(err, ShouldBeNil)
+			So(actual.Body, ShouldEqual, "ERROR")
+			So(actualEvents, ShouldHaveLength, 1)
+			So(actualEvents[0].Metric, ShouldEqual, "server.cpu")
+			So(actualEvents[0].State, ShouldEqual, moira.StateERROR)
+			So(actualEvents[0].Values, ShouldResemble, map[string]float64{"t1": errorValue})
+		})
+
+		Convey("Builder error", func() {
+			request := &dto.NotificationPreviewRequest{TriggerID: triggerID, ContactType: "slack", Events: events}
+			expected := fmt.Errorf("oooops! Can not build message")
+			dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
+			builder.EXPECT().BuildMessage(events, gomock.Any(), gomock.Any(), nil, false).Return(moira.NotificationPayload{}, expected)
+
+			actual, err := BuildNotificationPreview(dataBase, nil, preview, request)
+			So(err, ShouldResemble, api.ErrorInternalServer(fmt.Errorf("failed to build message: %w", expected)))
+			So(actual, ShouldBeNil)
+		})
+	})
+}
@@ -20,12 +20,24 @@ func TestGetNotifications(t *testing.T) {
 	var end int64 = 33
 
 	Convey("Has notifications", t, func() {
-		notifications := []*moira.ScheduledNotification{{Timestamp: 123, SendFail: 6}, {Timestamp: 321, SendFail: 1}}
+		notifications := []*moira.ScheduledNotification{
+			{Timestamp: 123, SendFail: 6, Contact: moira.ContactData{ID: "contact", SigningSecret: "secret"}},
+			{Timestamp: 321, SendFail: 1},
+		}
 		var total int64 = 666
 		dataBase.EXPECT().GetNotifications(start, end).Return(notifications, total, nil)
 		list, err := GetNotifications(dataBase, start, end)
 		So(err, ShouldBeNil)
-		So(list, ShouldResemble, &dto.NotificationsList{List: notifications, Total: total})
+		So(list, ShouldResemble, &dto.NotificationsList{
+			List: []*dto.ScheduledNotification{
+				{
+					ScheduledNotification: moira.ScheduledNotification{Timestamp: 123, SendFail: 6, Contact: moira.ContactData{ID: "contact"}},
+					Contact:               dto.ContactData{ContactData: moira.ContactData{ID: "contact"}, HasSigningSecret: true},
+				},
+				{ScheduledNotification: *notifications[1]},
+			},
+			Total: total,
+		})
 	})
 
 	Convey("Test error", t, func() {
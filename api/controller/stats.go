@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/analytics"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+)
+
+// GetTriggersAnalytics calculates noisiest and flapping triggers, time spent in states and mean time to recovery.
+func GetTriggersAnalytics(dataBase moira.Database, options analytics.Options) (*dto.TriggersAnalytics, *api.ErrorResponse) {
+	if err := validateAnalyticsOptions(options); err != nil {
+		return nil, err
+	}
+
+	report, err := analytics.NewBuilder(dataBase).BuildTriggersReport(options)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return &dto.TriggersAnalytics{TriggersReport: *report}, nil
+}
+
+// GetNotificationsAnalytics counts notifications sent per contact, team and sender.
+func GetNotificationsAnalytics(dataBase moira.Database, options analytics.Options) (*dto.NotificationsAnalytics, *api.ErrorResponse) {
+	if err := validateAnalyticsOptions(options); err != nil {
+		return nil, err
+	}
+
+	report, err := analytics.NewBuilder(dataBase).BuildNotificationsReport(options)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return &dto.NotificationsAnalytics{NotificationsReport: *report}, nil
+}
+
+func validateAnalyticsOptions(options analytics.Options) *api.ErrorResponse {
+	if options.From >= options.To {
+		return api.ErrorInvalidRequest(fmt.Errorf("from must be less than to"))
+	}
+	if options.Top < 0 {
+		return api.ErrorInvalidRequest(fmt.Errorf("top must not be negative"))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/analytics"
+	"github.com/moira-alert/moira/api"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetTriggersAnalytics(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Test get triggers analytics", t, func() {
+		Convey("Invalid time range", func() {
+			_, err := GetTriggersAnalytics(dataBase, analytics.Options{From: 2000, To: 1000})
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("from must be less than to")))
+		})
+
+		Convey("Database error", func() {
+			dataBase.EXPECT().GetAllTriggerIDs().Return(nil, fmt.Errorf("error"))
+			_, err := GetTriggersAnalytics(dataBase, analytics.Options{From: 1000, To: 2000})
+			So(err, ShouldResemble, api.ErrorInternalServer(fmt.Errorf("error")))
+		})
+
+		Convey("Success", func() {
+			dataBase.EXPECT().GetAllTriggerIDs().Return([]string{}, nil)
+			dataBase.EXPECT().GetTriggerChecks([]string{}).Return([]*moira.TriggerCheck{}, nil)
+			report, err := GetTriggersAnalytics(dataBase, analytics.Options{From: 1000, To: 2000})
+			So(err, ShouldBeNil)
+			So(report.From, ShouldEqual, 1000)
+			So(report.NeverFired, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestGetNotificationsAnalytics(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Test get notifications analytics", t, func() {
+		dataBase.EXPECT().GetAllContacts().Return([]*moira.ContactData{{ID: "contact", Type: "mail"}}, nil)
+		dataBase.EXPECT().GetNotificationsHistory(int64(1000), int64(2000)).Return([]*moira.NotificationEventHistoryItem{{ContactID: "contact"}}, nil)
+
+		report, err := GetNotificationsAnalytics(dataBase, analytics.Options{From: 1000, To: 2000})
+		So(err, ShouldBeNil)
+		So(report.Total, ShouldEqual, 1)
+		So(report.Senders, ShouldResemble, map[string]int{"mail": 1})
+	})
+}
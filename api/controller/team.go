@@ -3,6 +3,7 @@ package controller
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/go-redis/redis/v8"
@@ -64,6 +65,11 @@ func CreateTeam(dataBase moira.Database, team dto.TeamModel, userID string) (dto
 		return dto.SaveTeamResponse{}, api.ErrorInternalServer(fmt.Errorf("cannot save team users: %w", err))
 	}
 
+	err = dataBase.SaveTeamUserRoles(teamID, map[string]moira.TeamRole{userID: moira.TeamRoleOwner})
+	if err != nil {
+		return dto.SaveTeamResponse{}, api.ErrorInternalServer(fmt.Errorf("cannot save team user roles: %w", err))
+	}
+
 	return dto.SaveTeamResponse{ID: teamID}, nil
 }
 
@@ -112,12 +118,71 @@ func GetTeamUsers(dataBase moira.Database, teamID string) (dto.TeamMembers, *api
 		return dto.TeamMembers{}, api.ErrorInternalServer(fmt.Errorf("cannot get team users from database: %w", err))
 	}
 
+	roles, apiErr := getTeamUserRoles(dataBase, teamID, users)
+	if apiErr != nil {
+		return dto.TeamMembers{}, apiErr
+	}
+
 	result := dto.TeamMembers{
 		Usernames: users,
+		Roles:     roles,
 	}
 	return result, nil
 }
 
+// getTeamUserRoles returns roles of given team members.
+// Teams created before roles were introduced have no stored roles, all their members are owners.
+// Members of other teams without stored role are editors.
+func getTeamUserRoles(dataBase moira.Database, teamID string, users []string) (map[string]moira.TeamRole, *api.ErrorResponse) {
+	storedRoles, err := dataBase.GetTeamUserRoles(teamID)
+	if err != nil {
+		return nil, api.ErrorInternalServer(fmt.Errorf("cannot get team user roles from database: %w", err))
+	}
+
+	roles := make(map[string]moira.TeamRole, len(users))
+	for _, userID := range users {
+		roles[userID] = moira.GetTeamUserRole(storedRoles, userID)
+	}
+	return roles, nil
+}
+
+// getFinalTeamUserRoles returns roles of team members after the change of team members.
+// New members become editors, unless the team had no members, then they become owners.
+// It fails if no owner is left in the team.
+func getFinalTeamUserRoles(dataBase moira.Database, teamID string, existingUsers, finalUsers []string) (map[string]moira.TeamRole, *api.ErrorResponse) {
+	existingRoles, apiErr := getTeamUserRoles(dataBase, teamID, existingUsers)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	newMemberRole := moira.TeamRoleEditor
+	if len(existingUsers) == 0 {
+		newMemberRole = moira.TeamRoleOwner
+	}
+
+	roles := make(map[string]moira.TeamRole, len(finalUsers))
+	for _, userID := range finalUsers {
+		if role, ok := existingRoles[userID]; ok {
+			roles[userID] = role
+		} else {
+			roles[userID] = newMemberRole
+		}
+	}
+	if !hasTeamOwner(roles) {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("cannot remove last owner of team"))
+	}
+	return roles, nil
+}
+
+func hasTeamOwner(roles map[string]moira.TeamRole) bool {
+	for _, role := range roles {
+		if role == moira.TeamRoleOwner {
+			return true
+		}
+	}
+	return false
+}
+
 func fillCurrentUsersTeamsMap(dataBase moira.Database, existingUsers []string) (map[string][]string, *api.ErrorResponse) {
 	result := map[string][]string{}
 	for _, userID := range existingUsers {
@@ -215,13 +280,24 @@ func SetTeamUsers(dataBase moira.Database, teamID string, allUsers []string) (dt
 		return dto.TeamMembers{}, apiError
 	}
 
+	roles, apiError := getFinalTeamUserRoles(dataBase, teamID, existingUsers, allUsers)
+	if apiError != nil {
+		return dto.TeamMembers{}, apiError
+	}
+
 	err = dataBase.SaveTeamsAndUsers(teamID, allUsers, teamsMap)
 	if err != nil {
 		api.ErrorInternalServer(fmt.Errorf("cannot save users for team: %s %w", teamID, err))
 	}
 
+	err = dataBase.SaveTeamUserRoles(teamID, roles)
+	if err != nil {
+		return dto.TeamMembers{}, api.ErrorInternalServer(fmt.Errorf("cannot save team user roles: %w", err))
+	}
+
 	result := dto.TeamMembers{
 		Usernames: allUsers,
+		Roles:     roles,
 	}
 	return result, nil
 }
@@ -280,13 +356,24 @@ func AddTeamUsers(dataBase moira.Database, teamID string, newUsers []string) (dt
 		finalUsers = append(finalUsers, userID)
 	}
 
+	roles, apiErr := getFinalTeamUserRoles(dataBase, teamID, existingUsers, finalUsers)
+	if apiErr != nil {
+		return dto.TeamMembers{}, apiErr
+	}
+
 	err = dataBase.SaveTeamsAndUsers(teamID, finalUsers, teamsMap)
 	if err != nil {
 		api.ErrorInternalServer(fmt.Errorf("cannot save users for team: %s %w", teamID, err))
 	}
 
+	err = dataBase.SaveTeamUserRoles(teamID, roles)
+	if err != nil {
+		return dto.TeamMembers{}, api.ErrorInternalServer(fmt.Errorf("cannot save team user roles: %w", err))
+	}
+
 	result := dto.TeamMembers{
 		Usernames: finalUsers,
+		Roles:     roles,
 	}
 	return result, nil
 }
@@ -323,6 +410,20 @@ func DeleteTeam(dataBase moira.Database, teamID, userLogin string) (dto.SaveTeam
 	if len(teamSubscriptions) > 0 {
 		return dto.SaveTeamResponse{}, api.ErrorInvalidRequest(fmt.Errorf("cannot delete team: team have subscriptions: %s", strings.Join(teamSubscriptions, ", ")))
 	}
+	teamTokens, err := dataBase.GetTeamAPITokenIDs(teamID)
+	if err != nil {
+		return dto.SaveTeamResponse{}, api.ErrorInternalServer(fmt.Errorf("cannot get team api tokens: %w", err))
+	}
+	if len(teamTokens) > 0 {
+		return dto.SaveTeamResponse{}, api.ErrorInvalidRequest(fmt.Errorf("cannot delete team: team have api tokens: %s", strings.Join(teamTokens, ", ")))
+	}
+	teamTriggers, err := dataBase.GetTeamTriggerIDs(teamID)
+	if err != nil {
+		return dto.SaveTeamResponse{}, api.ErrorInternalServer(fmt.Errorf("cannot get team triggers: %w", err))
+	}
+	if len(teamTriggers) > 0 {
+		return dto.SaveTeamResponse{}, api.ErrorInvalidRequest(fmt.Errorf("cannot delete team: team have triggers: %s", strings.Join(teamTriggers, ", ")))
+	}
 	err = dataBase.DeleteTeam(teamID, userLogin)
 	if err != nil {
 		return dto.SaveTeamResponse{}, api.ErrorInternalServer(fmt.Errorf("cannot delete team: %w", err))
@@ -376,17 +477,57 @@ func DeleteTeamUser(dataBase moira.Database, teamID string, removeUserID string)
 		teamsMap[userID] = userTeams
 	}
 
+	roles, apiErr := getFinalTeamUserRoles(dataBase, teamID, existingUsers, finalUsers)
+	if apiErr != nil {
+		return dto.TeamMembers{}, apiErr
+	}
+
 	err = dataBase.SaveTeamsAndUsers(teamID, finalUsers, teamsMap)
 	if err != nil {
 		api.ErrorInternalServer(fmt.Errorf("cannot save users for team: %s %w", teamID, err))
 	}
 
+	err = dataBase.SaveTeamUserRoles(teamID, roles)
+	if err != nil {
+		return dto.TeamMembers{}, api.ErrorInternalServer(fmt.Errorf("cannot save team user roles: %w", err))
+	}
+
 	result := dto.TeamMembers{
 		Usernames: finalUsers,
+		Roles:     roles,
 	}
 	return result, nil
 }
 
+// SetTeamUserRole is a controller function that changes role of team member.
+func SetTeamUserRole(dataBase moira.Database, teamID, userID string, role moira.TeamRole) (dto.TeamMembers, *api.ErrorResponse) {
+	users, err := dataBase.GetTeamUsers(teamID)
+	if err != nil {
+		return dto.TeamMembers{}, api.ErrorInternalServer(fmt.Errorf("cannot get team users from database: %w", err))
+	}
+	if !slices.Contains(users, userID) {
+		return dto.TeamMembers{}, api.ErrorNotFound(fmt.Sprintf("user that you specified not found in this team: %s", userID))
+	}
+
+	roles, apiErr := getTeamUserRoles(dataBase, teamID, users)
+	if apiErr != nil {
+		return dto.TeamMembers{}, apiErr
+	}
+	roles[userID] = role
+	if !hasTeamOwner(roles) {
+		return dto.TeamMembers{}, api.ErrorInvalidRequest(fmt.Errorf("cannot remove last owner of team"))
+	}
+
+	if err = dataBase.SaveTeamUserRoles(teamID, roles); err != nil {
+		return dto.TeamMembers{}, api.ErrorInternalServer(fmt.Errorf("cannot save team user roles: %w", err))
+	}
+
+	return dto.TeamMembers{
+		Usernames: users,
+		Roles:     roles,
+	}, nil
+}
+
 func removeUserTeam(teams []string, teamID string) ([]string, error) {
 	for i, currentTeamID := range teams {
 		if teamID == currentTeamID {
@@ -398,10 +539,12 @@ func removeUserTeam(teams []string, teamID string) ([]string, error) {
 	return []string{}, fmt.Errorf("cannot find team in user teams: %s", teamID)
 }
 
+// CheckUserPermissionsForTeam checks that team exists and user is its member with role that includes the required one.
 func CheckUserPermissionsForTeam(
 	dataBase moira.Database,
 	teamID, userID string,
 	auth *api.Authorization,
+	requiredRole moira.TeamRole,
 ) *api.ErrorResponse {
 	if auth.IsAdmin(userID) {
 		return nil
@@ -422,6 +565,14 @@ func CheckUserPermissionsForTeam(
 	if !userIsTeamMember {
 		return api.ErrorForbidden("you are not permitted to manipulate with this team")
 	}
+
+	roles, apiErr := getTeamUserRoles(dataBase, teamID, []string{userID})
+	if apiErr != nil {
+		return apiErr
+	}
+	if !roles[userID].Includes(requiredRole) {
+		return api.ErrorForbidden(fmt.Sprintf("you need %s role in this team to do this", requiredRole))
+	}
 	return nil
 }
 
@@ -429,7 +580,7 @@ func CheckUserPermissionsForTeam(
 func GetTeamSettings(database moira.Database, teamID string) (dto.TeamSettings, *api.ErrorResponse) {
 	teamSettings := dto.TeamSettings{
 		TeamID:        teamID,
-		Contacts:      make([]moira.ContactData, 0),
+		Contacts:      make([]dto.ContactData, 0),
 		Subscriptions: make([]moira.SubscriptionData, 0),
 	}
 
@@ -458,8 +609,24 @@ func GetTeamSettings(database moira.Database, teamID string) (dto.TeamSettings,
 	}
 	for _, contact := range contacts {
 		if contact != nil {
-			teamSettings.Contacts = append(teamSettings.Contacts, *contact)
+			teamSettings.Contacts = append(teamSettings.Contacts, dto.NewContactData(*contact))
 		}
 	}
 	return teamSettings, nil
 }
+
+// GetTeamTriggers gets triggers owned by team.
+func GetTeamTriggers(dataBase moira.Database, teamID string) (*dto.TriggersList, *api.ErrorResponse) {
+	triggerIDs, err := dataBase.GetTeamTriggerIDs(teamID)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	triggerChecks, err := getTriggerChecks(dataBase, triggerIDs)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return &dto.TriggersList{
+		List: triggerChecks,
+	}, nil
+}
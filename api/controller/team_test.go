@@ -33,6 +33,7 @@ func TestCreateTeam(t *testing.T) {
 			})
 			dataBase.EXPECT().GetUserTeams(user).Return([]string{ID}, nil)
 			dataBase.EXPECT().SaveTeamsAndUsers(gomock.Any(), []string{user}, gomock.Any()).Return(nil)
+			dataBase.EXPECT().SaveTeamUserRoles(gomock.Any(), map[string]moira.TeamRole{user: moira.TeamRoleOwner}).Return(nil)
 			response, err := CreateTeam(dataBase, team, user)
 			So(response.ID, ShouldResemble, ID)
 			So(err, ShouldBeNil)
@@ -45,6 +46,7 @@ func TestCreateTeam(t *testing.T) {
 			dataBase.EXPECT().SaveTeam(teamID, team.ToMoiraTeam()).Return(nil)
 			dataBase.EXPECT().GetUserTeams(user).Return([]string{}, nil)
 			dataBase.EXPECT().SaveTeamsAndUsers(teamID, []string{user}, map[string][]string{user: {teamID}}).Return(nil)
+			dataBase.EXPECT().SaveTeamUserRoles(teamID, map[string]moira.TeamRole{user: moira.TeamRoleOwner}).Return(nil)
 			response, err := CreateTeam(dataBase, team, user)
 			So(response.ID, ShouldResemble, teamID)
 			So(err, ShouldBeNil)
@@ -60,6 +62,7 @@ func TestCreateTeam(t *testing.T) {
 			})
 			dataBase.EXPECT().GetUserTeams(user).Return([]string{ID}, nil)
 			dataBase.EXPECT().SaveTeamsAndUsers(gomock.Any(), []string{user}, gomock.Any()).Return(nil)
+			dataBase.EXPECT().SaveTeamUserRoles(gomock.Any(), map[string]moira.TeamRole{user: moira.TeamRoleOwner}).Return(nil)
 			response, err := CreateTeam(dataBase, team, user)
 			So(response.ID, ShouldResemble, ID)
 			So(err, ShouldBeNil)
@@ -100,6 +103,8 @@ func TestDeleteTeam(t *testing.T) {
 				dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{userID}, nil),
 				dataBase.EXPECT().GetTeamContactIDs(teamID).Return([]string{}, nil),
 				dataBase.EXPECT().GetTeamSubscriptionIDs(teamID).Return([]string{}, nil),
+				dataBase.EXPECT().GetTeamAPITokenIDs(teamID).Return([]string{}, nil),
+				dataBase.EXPECT().GetTeamTriggerIDs(teamID).Return([]string{}, nil),
 				dataBase.EXPECT().DeleteTeam(teamID, userID).Return(nil),
 			)
 			response, err := DeleteTeam(dataBase, teamID, userID)
@@ -107,6 +112,31 @@ func TestDeleteTeam(t *testing.T) {
 			So(response, ShouldResemble, dto.SaveTeamResponse{ID: teamID})
 		})
 
+		Convey("team have triggers", func() {
+			gomock.InOrder(
+				dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{userID}, nil),
+				dataBase.EXPECT().GetTeamContactIDs(teamID).Return([]string{}, nil),
+				dataBase.EXPECT().GetTeamSubscriptionIDs(teamID).Return([]string{}, nil),
+				dataBase.EXPECT().GetTeamAPITokenIDs(teamID).Return([]string{}, nil),
+				dataBase.EXPECT().GetTeamTriggerIDs(teamID).Return([]string{"triggerID"}, nil),
+			)
+			response, err := DeleteTeam(dataBase, teamID, userID)
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("cannot delete team: team have triggers: triggerID")))
+			So(response, ShouldResemble, dto.SaveTeamResponse{})
+		})
+
+		Convey("team have api tokens", func() {
+			gomock.InOrder(
+				dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{userID}, nil),
+				dataBase.EXPECT().GetTeamContactIDs(teamID).Return([]string{}, nil),
+				dataBase.EXPECT().GetTeamSubscriptionIDs(teamID).Return([]string{}, nil),
+				dataBase.EXPECT().GetTeamAPITokenIDs(teamID).Return([]string{"tokenID"}, nil),
+			)
+			response, err := DeleteTeam(dataBase, teamID, userID)
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("cannot delete team: team have api tokens: tokenID")))
+			So(response, ShouldResemble, dto.SaveTeamResponse{})
+		})
+
 		Convey("team have subscriptions", func() {
 			gomock.InOrder(
 				dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{userID}, nil),
@@ -253,8 +283,23 @@ func TestGetTeamUsers(t *testing.T) {
 
 		Convey("get successfully", func() {
 			dataBase.EXPECT().GetTeamUsers(teamID).Return(users, nil)
+			dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{"userID1": moira.TeamRoleOwner}, nil)
+			response, err := GetTeamUsers(dataBase, teamID)
+			So(response, ShouldResemble, dto.TeamMembers{
+				Usernames: users,
+				Roles:     map[string]moira.TeamRole{"userID1": moira.TeamRoleOwner, "userID2": moira.TeamRoleEditor},
+			})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("members of team without stored roles are owners", func() {
+			dataBase.EXPECT().GetTeamUsers(teamID).Return(users, nil)
+			dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{}, nil)
 			response, err := GetTeamUsers(dataBase, teamID)
-			So(response, ShouldResemble, dto.TeamMembers{Usernames: users})
+			So(response, ShouldResemble, dto.TeamMembers{
+				Usernames: users,
+				Roles:     map[string]moira.TeamRole{"userID1": moira.TeamRoleOwner, "userID2": moira.TeamRoleOwner},
+			})
 			So(err, ShouldBeNil)
 		})
 
@@ -287,11 +332,17 @@ func TestAddTeamUsers(t *testing.T) {
 		const userID3 = "userID3"
 
 		Convey("add successfully", func() {
+			roles := map[string]moira.TeamRole{
+				userID:  moira.TeamRoleOwner,
+				userID2: moira.TeamRoleViewer,
+				userID3: moira.TeamRoleEditor,
+			}
 			gomock.InOrder(
 				dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{userID, userID2}, nil),
 				dataBase.EXPECT().GetUserTeams(userID).Return([]string{teamID}, nil),
 				dataBase.EXPECT().GetUserTeams(userID2).Return([]string{teamID}, nil),
 				dataBase.EXPECT().GetUserTeams(userID3).Return([]string{}, nil),
+				dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{userID: moira.TeamRoleOwner, userID2: moira.TeamRoleViewer}, nil),
 				dataBase.EXPECT().SaveTeamsAndUsers(teamID,
 					[]string{userID, userID2, userID3},
 					map[string][]string{
@@ -300,9 +351,10 @@ func TestAddTeamUsers(t *testing.T) {
 						userID3: {teamID},
 					},
 				).Return(nil),
+				dataBase.EXPECT().SaveTeamUserRoles(teamID, roles).Return(nil),
 			)
 			response, err := AddTeamUsers(dataBase, teamID, []string{userID3})
-			So(response, ShouldResemble, dto.TeamMembers{Usernames: []string{userID, userID2, userID3}})
+			So(response, ShouldResemble, dto.TeamMembers{Usernames: []string{userID, userID2, userID3}, Roles: roles})
 			So(err, ShouldBeNil)
 		})
 
@@ -383,21 +435,35 @@ func TestDeleteTeamUser(t *testing.T) {
 		const userID3 = "userID3"
 
 		Convey("user exists", func() {
+			roles := map[string]moira.TeamRole{userID2: moira.TeamRoleOwner, userID3: moira.TeamRoleOwner}
 			gomock.InOrder(
 				dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{userID, userID2, userID3}, nil),
 				dataBase.EXPECT().GetUserTeams(userID).Return([]string{teamID, "team2"}, nil),
 				dataBase.EXPECT().GetUserTeams(userID2).Return([]string{teamID}, nil),
 				dataBase.EXPECT().GetUserTeams(userID3).Return([]string{teamID}, nil),
+				dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{}, nil),
 				dataBase.EXPECT().SaveTeamsAndUsers(teamID, []string{userID2, userID3}, map[string][]string{
 					userID:  {"team2"},
 					userID2: {teamID},
 					userID3: {teamID},
 				}).Return(nil),
+				dataBase.EXPECT().SaveTeamUserRoles(teamID, roles).Return(nil),
 			)
 			reply, err := DeleteTeamUser(dataBase, teamID, userID)
-			So(reply, ShouldResemble, dto.TeamMembers{Usernames: []string{userID2, userID3}})
+			So(reply, ShouldResemble, dto.TeamMembers{Usernames: []string{userID2, userID3}, Roles: roles})
 			So(err, ShouldBeNil)
 		})
+		Convey("removal of last owner", func() {
+			gomock.InOrder(
+				dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{userID, userID2}, nil),
+				dataBase.EXPECT().GetUserTeams(userID).Return([]string{teamID}, nil),
+				dataBase.EXPECT().GetUserTeams(userID2).Return([]string{teamID}, nil),
+				dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{userID: moira.TeamRoleOwner}, nil),
+			)
+			reply, err := DeleteTeamUser(dataBase, teamID, userID)
+			So(reply, ShouldResemble, dto.TeamMembers{})
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("cannot remove last owner of team")))
+		})
 		Convey("team does not have any users", func() {
 			dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{}, database.ErrNil)
 			reply, err := DeleteTeamUser(dataBase, teamID, userID)
@@ -571,19 +637,33 @@ func TestSetTeamUsers(t *testing.T) {
 		Convey("Set to empty team", func() {
 			dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{}, nil)
 			dataBase.EXPECT().GetUserTeams(userID1).Return(nil, database.ErrNil)
+			dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{}, nil)
 			dataBase.EXPECT().SaveTeamsAndUsers(teamID, []string{userID1}, map[string][]string{userID1: {teamID}})
+			dataBase.EXPECT().SaveTeamUserRoles(teamID, map[string]moira.TeamRole{userID1: moira.TeamRoleOwner})
 			actual, err := SetTeamUsers(dataBase, teamID, []string{userID1})
 			So(err, ShouldBeNil)
-			So(actual, ShouldResemble, dto.TeamMembers{Usernames: []string{userID1}})
+			So(actual, ShouldResemble, dto.TeamMembers{Usernames: []string{userID1}, Roles: map[string]moira.TeamRole{userID1: moira.TeamRoleOwner}})
 		})
 		Convey("Set to team with members", func() {
+			roles := map[string]moira.TeamRole{userID1: moira.TeamRoleOwner, userID2: moira.TeamRoleEditor}
 			dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{userID1}, nil)
 			dataBase.EXPECT().GetUserTeams(userID1).Return([]string{teamID}, nil)
 			dataBase.EXPECT().GetUserTeams(userID2).Return(nil, database.ErrNil)
+			dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{userID1: moira.TeamRoleOwner}, nil)
 			dataBase.EXPECT().SaveTeamsAndUsers(teamID, []string{userID1, userID2}, map[string][]string{userID1: {teamID}, userID2: {teamID}})
+			dataBase.EXPECT().SaveTeamUserRoles(teamID, roles)
 			actual, err := SetTeamUsers(dataBase, teamID, []string{userID1, userID2})
 			So(err, ShouldBeNil)
-			So(actual, ShouldResemble, dto.TeamMembers{Usernames: []string{userID1, userID2}})
+			So(actual, ShouldResemble, dto.TeamMembers{Usernames: []string{userID1, userID2}, Roles: roles})
+		})
+		Convey("Set without owner", func() {
+			dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{userID1}, nil)
+			dataBase.EXPECT().GetUserTeams(userID1).Return([]string{teamID}, nil)
+			dataBase.EXPECT().GetUserTeams(userID2).Return(nil, database.ErrNil)
+			dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{userID1: moira.TeamRoleOwner}, nil)
+			actual, err := SetTeamUsers(dataBase, teamID, []string{userID2})
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("cannot remove last owner of team")))
+			So(actual, ShouldResemble, dto.TeamMembers{})
 		})
 	})
 }
@@ -601,33 +681,120 @@ func TestCheckUserPermissionsForTeam(t *testing.T) {
 		Convey("user in team", func() {
 			dataBase.EXPECT().GetTeam(teamID).Return(moira.Team{}, nil)
 			dataBase.EXPECT().IsTeamContainUser(teamID, userID).Return(true, nil)
-			err := CheckUserPermissionsForTeam(dataBase, teamID, userID, auth)
+			dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{userID: moira.TeamRoleEditor}, nil)
+			err := CheckUserPermissionsForTeam(dataBase, teamID, userID, auth, moira.TeamRoleEditor)
 			So(err, ShouldBeNil)
 		})
+		Convey("user role is not enough", func() {
+			dataBase.EXPECT().GetTeam(teamID).Return(moira.Team{}, nil)
+			dataBase.EXPECT().IsTeamContainUser(teamID, userID).Return(true, nil)
+			dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{userID: moira.TeamRoleViewer}, nil)
+			err := CheckUserPermissionsForTeam(dataBase, teamID, userID, auth, moira.TeamRoleEditor)
+			So(err, ShouldResemble, api.ErrorForbidden("you need editor role in this team to do this"))
+		})
+		Convey("user without stored role in team with roles is editor", func() {
+			dataBase.EXPECT().GetTeam(teamID).Return(moira.Team{}, nil)
+			dataBase.EXPECT().IsTeamContainUser(teamID, userID).Return(true, nil)
+			dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{"owner": moira.TeamRoleOwner}, nil)
+			err := CheckUserPermissionsForTeam(dataBase, teamID, userID, auth, moira.TeamRoleOwner)
+			So(err, ShouldResemble, api.ErrorForbidden("you need owner role in this team to do this"))
+		})
 		Convey("user is not in team", func() {
 			dataBase.EXPECT().GetTeam(teamID).Return(moira.Team{}, nil)
 			dataBase.EXPECT().IsTeamContainUser(teamID, userID).Return(false, nil)
-			err := CheckUserPermissionsForTeam(dataBase, teamID, userID, auth)
+			err := CheckUserPermissionsForTeam(dataBase, teamID, userID, auth, moira.TeamRoleViewer)
 			So(err, ShouldResemble, api.ErrorForbidden("you are not permitted to manipulate with this team"))
 		})
 		Convey("error while checking user", func() {
 			returnErr := errors.New("returning error")
 			dataBase.EXPECT().GetTeam(teamID).Return(moira.Team{}, nil)
 			dataBase.EXPECT().IsTeamContainUser(teamID, userID).Return(false, returnErr)
-			err := CheckUserPermissionsForTeam(dataBase, teamID, userID, auth)
+			err := CheckUserPermissionsForTeam(dataBase, teamID, userID, auth, moira.TeamRoleViewer)
 			So(err, ShouldResemble, api.ErrorInternalServer(returnErr))
 		})
 		Convey("error while getting team", func() {
 			returnErr := errors.New("returning error")
 			dataBase.EXPECT().GetTeam(teamID).Return(moira.Team{}, returnErr)
-			err := CheckUserPermissionsForTeam(dataBase, teamID, userID, auth)
+			err := CheckUserPermissionsForTeam(dataBase, teamID, userID, auth, moira.TeamRoleViewer)
 			So(err, ShouldResemble, api.ErrorInternalServer(returnErr))
 		})
 		Convey("team is not exist", func() {
 			dataBase.EXPECT().GetTeam(teamID).Return(moira.Team{}, database.ErrNil)
-			err := CheckUserPermissionsForTeam(dataBase, teamID, userID, auth)
+			err := CheckUserPermissionsForTeam(dataBase, teamID, userID, auth, moira.TeamRoleViewer)
 			So(err, ShouldResemble, api.ErrorNotFound("team with ID 'testTeam' does not exists"))
 		})
+		Convey("admin is allowed to do anything", func() {
+			adminAuth := &api.Authorization{Enabled: true, AdminList: map[string]struct{}{userID: {}}}
+			err := CheckUserPermissionsForTeam(dataBase, teamID, userID, adminAuth, moira.TeamRoleOwner)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestSetTeamUserRole(t *testing.T) {
+	const teamID = "testTeam"
+	const userID1 = "userID1"
+	const userID2 = "userID2"
+
+	Convey("SetTeamUserRole", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+		Convey("change successfully", func() {
+			roles := map[string]moira.TeamRole{userID1: moira.TeamRoleOwner, userID2: moira.TeamRoleViewer}
+			dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{userID1, userID2}, nil)
+			dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{userID1: moira.TeamRoleOwner}, nil)
+			dataBase.EXPECT().SaveTeamUserRoles(teamID, roles).Return(nil)
+			response, err := SetTeamUserRole(dataBase, teamID, userID2, moira.TeamRoleViewer)
+			So(err, ShouldBeNil)
+			So(response, ShouldResemble, dto.TeamMembers{Usernames: []string{userID1, userID2}, Roles: roles})
+		})
+		Convey("team without stored roles keeps other members owners", func() {
+			roles := map[string]moira.TeamRole{userID1: moira.TeamRoleOwner, userID2: moira.TeamRoleEditor}
+			dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{userID1, userID2}, nil)
+			dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{}, nil)
+			dataBase.EXPECT().SaveTeamUserRoles(teamID, roles).Return(nil)
+			_, err := SetTeamUserRole(dataBase, teamID, userID2, moira.TeamRoleEditor)
+			So(err, ShouldBeNil)
+		})
+		Convey("last owner", func() {
+			dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{userID1, userID2}, nil)
+			dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{userID1: moira.TeamRoleOwner}, nil)
+			_, err := SetTeamUserRole(dataBase, teamID, userID1, moira.TeamRoleEditor)
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("cannot remove last owner of team")))
+		})
+		Convey("user is not in team", func() {
+			dataBase.EXPECT().GetTeamUsers(teamID).Return([]string{userID1}, nil)
+			_, err := SetTeamUserRole(dataBase, teamID, userID2, moira.TeamRoleEditor)
+			So(err, ShouldResemble, api.ErrorNotFound("user that you specified not found in this team: userID2"))
+		})
+	})
+}
+
+func TestGetTeamTriggers(t *testing.T) {
+	const teamID = "testTeam"
+
+	Convey("GetTeamTriggers", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+		Convey("get successfully", func() {
+			triggerCheck := moira.TriggerCheck{Trigger: moira.Trigger{ID: "triggerID", TeamID: teamID}}
+			dataBase.EXPECT().GetTeamTriggerIDs(teamID).Return([]string{"triggerID", "removedID"}, nil)
+			dataBase.EXPECT().GetTriggerChecks([]string{"triggerID", "removedID"}).Return([]*moira.TriggerCheck{&triggerCheck, nil}, nil)
+			response, err := GetTeamTriggers(dataBase, teamID)
+			So(err, ShouldBeNil)
+			So(response, ShouldResemble, &dto.TriggersList{List: []moira.TriggerCheck{triggerCheck}})
+		})
+		Convey("database error", func() {
+			returnErr := errors.New("returning error")
+			dataBase.EXPECT().GetTeamTriggerIDs(teamID).Return(nil, returnErr)
+			response, err := GetTeamTriggers(dataBase, teamID)
+			So(err, ShouldResemble, api.ErrorInternalServer(returnErr))
+			So(response, ShouldBeNil)
+		})
 	})
 }
 
@@ -650,7 +817,7 @@ func TestGetTeamSettings(t *testing.T) {
 		So(err, ShouldBeNil)
 		So(settings, ShouldResemble, dto.TeamSettings{
 			TeamID:        teamID,
-			Contacts:      []moira.ContactData{*contacts[0], *contacts[1]},
+			Contacts:      []dto.ContactData{dto.NewContactData(*contacts[0]), dto.NewContactData(*contacts[1])},
 			Subscriptions: []moira.SubscriptionData{*subscriptions[0], *subscriptions[1]},
 		})
 	})
@@ -664,7 +831,7 @@ func TestGetTeamSettings(t *testing.T) {
 		So(err, ShouldBeNil)
 		So(settings, ShouldResemble, dto.TeamSettings{
 			TeamID:        teamID,
-			Contacts:      make([]moira.ContactData, 0),
+			Contacts:      make([]dto.ContactData, 0),
 			Subscriptions: make([]moira.SubscriptionData, 0),
 		})
 	})
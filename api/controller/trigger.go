@@ -95,9 +95,9 @@ func GetTrigger(dataBase moira.Database, triggerID string) (*dto.Trigger, *api.E
 	return &triggerResponse, nil
 }
 
-// RemoveTrigger deletes trigger by given triggerID.
-func RemoveTrigger(database moira.Database, triggerID string) *api.ErrorResponse {
-	if err := database.RemoveTrigger(triggerID); err != nil {
+// RemoveTrigger deletes trigger by given triggerID on behalf of given user.
+func RemoveTrigger(database moira.Database, triggerID, userLogin string) *api.ErrorResponse {
+	if err := database.RemoveTrigger(triggerID, userLogin); err != nil {
 		return api.ErrorInternalServer(err)
 	}
 	return nil
@@ -197,3 +197,33 @@ func GetTriggerDump(database moira.Database, logger moira.Logger, triggerID stri
 		Metrics:   metrics,
 	}, nil
 }
+
+// CheckUserPermissionsForTrigger checks that user is allowed to change existing trigger.
+// Triggers owned by team can be changed only by its editors or by api token of the team.
+func CheckUserPermissionsForTrigger(
+	dataBase moira.Database,
+	triggerID, userLogin, tokenTeamID string,
+	auth *api.Authorization,
+) *api.ErrorResponse {
+	trigger, err := dataBase.GetTrigger(triggerID)
+	if err != nil {
+		if errors.Is(err, database.ErrNil) {
+			// Absence of trigger is reported by the handlers
+			return nil
+		}
+		return api.ErrorInternalServer(err)
+	}
+	return CheckUserPermissionsForTriggerTeam(dataBase, trigger.TeamID, userLogin, tokenTeamID, auth)
+}
+
+// CheckUserPermissionsForTriggerTeam checks that user is allowed to make team the owner of trigger.
+func CheckUserPermissionsForTriggerTeam(
+	dataBase moira.Database,
+	teamID, userLogin, tokenTeamID string,
+	auth *api.Authorization,
+) *api.ErrorResponse {
+	if teamID == "" || teamID == tokenTeamID {
+		return nil
+	}
+	return CheckUserPermissionsForTeam(dataBase, teamID, userLogin, auth, moira.TeamRoleEditor)
+}
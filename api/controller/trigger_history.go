@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetTriggerHistory gets saved versions of trigger, the newest versions go first.
+func GetTriggerHistory(dataBase moira.Database, triggerID string) (*dto.TriggerHistoryList, *api.ErrorResponse) {
+	history, err := dataBase.GetTriggerHistory(triggerID)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	return newTriggerHistoryList(history), nil
+}
+
+// GetDeletedTriggers gets the last saved versions of recently deleted triggers.
+// Administrators get all deleted triggers, other users get triggers they created or deleted and triggers of their teams.
+func GetDeletedTriggers(dataBase moira.Database, userLogin, tokenTeamID string, auth *api.Authorization) (*dto.TriggerHistoryList, *api.ErrorResponse) {
+	deleted, err := dataBase.GetDeletedTriggers()
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	if !auth.IsEnabled() || auth.IsAdmin(userLogin) {
+		return newTriggerHistoryList(deleted), nil
+	}
+
+	userTeams, err := dataBase.GetUserTeams(userLogin)
+	if err != nil && !errors.Is(err, database.ErrNil) {
+		return nil, api.ErrorInternalServer(err)
+	}
+	teams := make(map[string]bool, len(userTeams)+1)
+	for _, teamID := range userTeams {
+		teams[teamID] = true
+	}
+	if tokenTeamID != "" {
+		teams[tokenTeamID] = true
+	}
+
+	visible := make([]*moira.TriggerHistoryItem, 0, len(deleted))
+	for _, item := range deleted {
+		if item != nil && isDeletedTriggerVisible(item, userLogin, teams) {
+			visible = append(visible, item)
+		}
+	}
+	return newTriggerHistoryList(visible), nil
+}
+
+func isDeletedTriggerVisible(item *moira.TriggerHistoryItem, userLogin string, teams map[string]bool) bool {
+	if item.Trigger.TeamID != "" {
+		return teams[item.Trigger.TeamID]
+	}
+	return item.User == userLogin || item.Trigger.CreatedBy == userLogin
+}
+
+// GetTriggerHistoryDiff compares given trigger version with compareTo version.
+// If compareTo is 0, then given version is compared with the previous one.
+func GetTriggerHistoryDiff(dataBase moira.Database, triggerID string, version, compareTo int64) (*dto.TriggerHistoryDiff, *api.ErrorResponse) {
+	history, err := dataBase.GetTriggerHistory(triggerID)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	current, previous := findTriggerVersions(history, version, compareTo)
+	if current == nil {
+		return nil, api.ErrorNotFound(fmt.Sprintf("version %d of trigger with ID = '%s' does not exists", version, triggerID))
+	}
+	if previous == nil && compareTo != 0 {
+		return nil, api.ErrorNotFound(fmt.Sprintf("version %d of trigger with ID = '%s' does not exists", compareTo, triggerID))
+	}
+
+	diff := &dto.TriggerHistoryDiff{
+		TriggerID: triggerID,
+		Version:   current.Version,
+	}
+
+	// The first version is compared with empty trigger
+	previousTrigger := moira.Trigger{}
+	if previous != nil {
+		diff.CompareTo = previous.Version
+		previousTrigger = previous.Trigger
+	}
+
+	diff.Changes, err = getTriggerChanges(&previousTrigger, &current.Trigger)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	return diff, nil
+}
+
+// GetTriggerHistoryVersion gets trigger as it was saved in given version.
+func GetTriggerHistoryVersion(dataBase moira.Database, triggerID string, version int64) (*dto.Trigger, *api.ErrorResponse) {
+	item, err := dataBase.GetTriggerHistoryItem(triggerID, version)
+	if err != nil {
+		if errors.Is(err, database.ErrNil) {
+			return nil, api.ErrorNotFound(fmt.Sprintf("version %d of trigger with ID = '%s' does not exists", version, triggerID))
+		}
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	return &dto.Trigger{TriggerModel: dto.CreateTriggerModel(&item.Trigger)}, nil
+}
+
+// RestoreTrigger saves trigger from history. Trigger may be already deleted.
+func RestoreTrigger(dataBase moira.Database, trigger *dto.TriggerModel, triggerID string, timeSeriesNames map[string]bool) (*dto.SaveTriggerResponse, *api.ErrorResponse) {
+	response, err := saveTrigger(dataBase, trigger.ToMoiraTrigger(), triggerID, timeSeriesNames)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Message = "trigger restored"
+	return response, nil
+}
+
+func newTriggerHistoryList(items []*moira.TriggerHistoryItem) *dto.TriggerHistoryList {
+	list := &dto.TriggerHistoryList{
+		List: make([]moira.TriggerHistoryItem, 0, len(items)),
+	}
+	for _, item := range items {
+		if item != nil {
+			list.List = append(list.List, *item)
+		}
+	}
+	return list
+}
+
+// findTriggerVersions returns history item of given version and the item it should be compared with.
+// History items are expected to be sorted from the newest to the oldest.
+func findTriggerVersions(history []*moira.TriggerHistoryItem, version, compareTo int64) (current, previous *moira.TriggerHistoryItem) {
+	for i, item := range history {
+		if item.Version == version {
+			current = item
+			if compareTo == 0 && i+1 < len(history) {
+				previous = history[i+1]
+			}
+		}
+		if compareTo != 0 && item.Version == compareTo {
+			previous = item
+		}
+	}
+	return current, previous
+}
+
+// getTriggerChanges compares triggers by their json fields and returns changed fields sorted by name.
+func getTriggerChanges(oldTrigger, newTrigger *moira.Trigger) ([]dto.TriggerFieldChange, error) {
+	oldFields, err := getTriggerFields(oldTrigger)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := getTriggerFields(newTrigger)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldNames := make(map[string]bool, len(oldFields)+len(newFields))
+	for field := range oldFields {
+		fieldNames[field] = true
+	}
+	for field := range newFields {
+		fieldNames[field] = true
+	}
+
+	changes := make([]dto.TriggerFieldChange, 0)
+	for field := range fieldNames {
+		oldValue, newValue := oldFields[field], newFields[field]
+		if string(oldValue) == string(newValue) {
+			continue
+		}
+
+		change := dto.TriggerFieldChange{Field: field}
+		if err = unmarshalFieldValue(oldValue, &change.Old); err != nil {
+			return nil, err
+		}
+		if err = unmarshalFieldValue(newValue, &change.New); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes, nil
+}
+
+func getTriggerFields(trigger *moira.Trigger) (map[string]json.RawMessage, error) {
+	bytes, err := json.Marshal(trigger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal trigger: %w", err)
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(bytes, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal trigger fields: %w", err)
+	}
+	return fields, nil
+}
+
+func unmarshalFieldValue(raw json.RawMessage, value *interface{}) error {
+	if raw == nil {
+		return nil
+	}
+	if err := json.Unmarshal(raw, value); err != nil {
+		return fmt.Errorf("failed to unmarshal trigger field: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetTriggerHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	triggerID := "triggerID-0000000000001"
+
+	Convey("Get trigger history", t, func() {
+		Convey("Success", func() {
+			history := []*moira.TriggerHistoryItem{
+				{Version: 2, Action: moira.TriggerHistoryActionUpdate, Trigger: moira.Trigger{ID: triggerID}},
+				{Version: 1, Action: moira.TriggerHistoryActionCreate, Trigger: moira.Trigger{ID: triggerID}},
+			}
+			dataBase.EXPECT().GetTriggerHistory(triggerID).Return(history, nil)
+			list, err := GetTriggerHistory(dataBase, triggerID)
+			So(err, ShouldBeNil)
+			So(list, ShouldResemble, &dto.TriggerHistoryList{List: []moira.TriggerHistoryItem{*history[0], *history[1]}})
+		})
+
+		Convey("Empty history", func() {
+			dataBase.EXPECT().GetTriggerHistory(triggerID).Return(nil, nil)
+			list, err := GetTriggerHistory(dataBase, triggerID)
+			So(err, ShouldBeNil)
+			So(list, ShouldResemble, &dto.TriggerHistoryList{List: []moira.TriggerHistoryItem{}})
+		})
+
+		Convey("Database error", func() {
+			expected := fmt.Errorf("oops")
+			dataBase.EXPECT().GetTriggerHistory(triggerID).Return(nil, expected)
+			list, err := GetTriggerHistory(dataBase, triggerID)
+			So(err, ShouldResemble, api.ErrorInternalServer(expected))
+			So(list, ShouldBeNil)
+		})
+	})
+}
+
+func TestGetDeletedTriggers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Get deleted triggers", t, func() {
+		Convey("Success", func() {
+			deleted := []*moira.TriggerHistoryItem{
+				{Version: 3, Action: moira.TriggerHistoryActionDelete, Trigger: moira.Trigger{ID: "triggerID-0000000000001"}},
+			}
+			dataBase.EXPECT().GetDeletedTriggers().Return(deleted, nil)
+			list, err := GetDeletedTriggers(dataBase, "user", "", &api.Authorization{})
+			So(err, ShouldBeNil)
+			So(list, ShouldResemble, &dto.TriggerHistoryList{List: []moira.TriggerHistoryItem{*deleted[0]}})
+		})
+
+		Convey("Non-admin user gets own triggers and triggers of own teams", func() {
+			auth := &api.Authorization{Enabled: true, AdminList: map[string]struct{}{"admin": {}}}
+			deleted := []*moira.TriggerHistoryItem{
+				{Version: 3, User: "user", Trigger: moira.Trigger{ID: "deleted-by-user"}},
+				{Version: 2, User: "other", Trigger: moira.Trigger{ID: "created-by-user", CreatedBy: "user"}},
+				{Version: 4, User: "other", Trigger: moira.Trigger{ID: "of-user-team", TeamID: "team-1"}},
+				{Version: 5, User: "user", Trigger: moira.Trigger{ID: "of-other-team", TeamID: "team-2"}},
+				{Version: 6, User: "other", Trigger: moira.Trigger{ID: "of-other-user", CreatedBy: "other"}},
+			}
+
+			dataBase.EXPECT().GetDeletedTriggers().Return(deleted, nil)
+			dataBase.EXPECT().GetUserTeams("user").Return([]string{"team-1"}, nil)
+			list, err := GetDeletedTriggers(dataBase, "user", "", auth)
+			So(err, ShouldBeNil)
+			So(list, ShouldResemble, &dto.TriggerHistoryList{List: []moira.TriggerHistoryItem{*deleted[0], *deleted[1], *deleted[2]}})
+
+			dataBase.EXPECT().GetDeletedTriggers().Return(deleted, nil)
+			list, err = GetDeletedTriggers(dataBase, "admin", "", auth)
+			So(err, ShouldBeNil)
+			So(list.List, ShouldHaveLength, len(deleted))
+		})
+
+		Convey("Database error", func() {
+			expected := fmt.Errorf("oops")
+			dataBase.EXPECT().GetDeletedTriggers().Return(nil, expected)
+			list, err := GetDeletedTriggers(dataBase, "user", "", &api.Authorization{})
+			So(err, ShouldResemble, api.ErrorInternalServer(expected))
+			So(list, ShouldBeNil)
+		})
+	})
+}
+
+func TestGetTriggerHistoryDiff(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	triggerID := "triggerID-0000000000001"
+	warnValue := float64(10)
+
+	history := []*moira.TriggerHistoryItem{
+		{Version: 3, Trigger: moira.Trigger{ID: triggerID, Name: "third", Tags: []string{"tag"}, WarnValue: &warnValue}},
+		{Version: 2, Trigger: moira.Trigger{ID: triggerID, Name: "second", Tags: []string{"tag"}}},
+		{Version: 1, Trigger: moira.Trigger{ID: triggerID, Name: "first", Tags: []string{"tag"}}},
+	}
+
+	Convey("Get trigger history diff", t, func() {
+		Convey("Compare with previous version", func() {
+			dataBase.EXPECT().GetTriggerHistory(triggerID).Return(history, nil)
+			diff, err := GetTriggerHistoryDiff(dataBase, triggerID, 3, 0)
+			So(err, ShouldBeNil)
+			So(diff, ShouldResemble, &dto.TriggerHistoryDiff{
+				TriggerID: triggerID,
+				Version:   3,
+				CompareTo: 2,
+				Changes: []dto.TriggerFieldChange{
+					{Field: "name", Old: "second", New: "third"},
+					{Field: "warn_value", Old: nil, New: float64(10)},
+				},
+			})
+		})
+
+		Convey("Compare with given version", func() {
+			dataBase.EXPECT().GetTriggerHistory(triggerID).Return(history, nil)
+			diff, err := GetTriggerHistoryDiff(dataBase, triggerID, 1, 2)
+			So(err, ShouldBeNil)
+			So(diff.CompareTo, ShouldEqual, 2)
+			So(diff.Changes, ShouldResemble, []dto.TriggerFieldChange{{Field: "name", Old: "second", New: "first"}})
+		})
+
+		Convey("First version is compared with empty trigger", func() {
+			dataBase.EXPECT().GetTriggerHistory(triggerID).Return(history, nil)
+			diff, err := GetTriggerHistoryDiff(dataBase, triggerID, 1, 0)
+			So(err, ShouldBeNil)
+			So(diff.CompareTo, ShouldEqual, 0)
+			So(diff.Changes, ShouldContain, dto.TriggerFieldChange{Field: "name", Old: "", New: "first"})
+		})
+
+		Convey("Unknown version", func() {
+			dataBase.EXPECT().GetTriggerHistory(triggerID).Return(history, nil)
+			diff, err := GetTriggerHistoryDiff(dataBase, triggerID, 4, 0)
+			So(err, ShouldResemble, api.ErrorNotFound(fmt.Sprintf("version 4 of trigger with ID = '%s' does not exists", triggerID)))
+			So(diff, ShouldBeNil)
+		})
+
+		Convey("Unknown version to compare with", func() {
+			dataBase.EXPECT().GetTriggerHistory(triggerID).Return(history, nil)
+			diff, err := GetTriggerHistoryDiff(dataBase, triggerID, 3, 5)
+			So(err, ShouldResemble, api.ErrorNotFound(fmt.Sprintf("version 5 of trigger with ID = '%s' does not exists", triggerID)))
+			So(diff, ShouldBeNil)
+		})
+	})
+}
+
+func TestGetTriggerHistoryVersion(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	triggerID := "triggerID-0000000000001"
+
+	Convey("Get trigger history version", t, func() {
+		Convey("Success", func() {
+			trigger := moira.Trigger{ID: triggerID, Name: "name"}
+			dataBase.EXPECT().GetTriggerHistoryItem(triggerID, int64(1)).Return(moira.TriggerHistoryItem{Version: 1, Trigger: trigger}, nil)
+			actual, err := GetTriggerHistoryVersion(dataBase, triggerID, 1)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, &dto.Trigger{TriggerModel: dto.CreateTriggerModel(&trigger)})
+		})
+
+		Convey("Version does not exist", func() {
+			dataBase.EXPECT().GetTriggerHistoryItem(triggerID, int64(1)).Return(moira.TriggerHistoryItem{}, database.ErrNil)
+			actual, err := GetTriggerHistoryVersion(dataBase, triggerID, 1)
+			So(err, ShouldResemble, api.ErrorNotFound(fmt.Sprintf("version 1 of trigger with ID = '%s' does not exists", triggerID)))
+			So(actual, ShouldBeNil)
+		})
+	})
+}
+
+func TestRestoreTrigger(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Restore deleted trigger", t, func() {
+		triggerModel := dto.TriggerModel{ID: "triggerID-0000000000001"}
+		trigger := triggerModel.ToMoiraTrigger()
+		dataBase.EXPECT().AcquireTriggerCheckLock(triggerModel.ID, 30)
+		dataBase.EXPECT().DeleteTriggerCheckLock(triggerModel.ID)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerModel.ID).Return(moira.CheckData{}, database.ErrNil)
+		dataBase.EXPECT().SetTriggerLastCheck(triggerModel.ID, gomock.Any(), trigger.ClusterKey()).Return(nil)
+		dataBase.EXPECT().SaveTrigger(triggerModel.ID, trigger).Return(nil)
+		resp, err := RestoreTrigger(dataBase, &triggerModel, triggerModel.ID, make(map[string]bool))
+		So(err, ShouldBeNil)
+		So(resp.Message, ShouldEqual, "trigger restored")
+	})
+}
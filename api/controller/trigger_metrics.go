@@ -122,3 +122,26 @@ func deleteTriggerMetrics(dataBase moira.Database, metricName string, triggerID
 
 	return nil
 }
+
+// GetTriggerMetricTimeline returns intervals of metric states within time range.
+func GetTriggerMetricTimeline(dataBase moira.Database, triggerID, metric string, from, to int64) (*dto.MetricStateTimeline, *api.ErrorResponse) {
+	if _, err := dataBase.GetTrigger(triggerID); err != nil {
+		if errors.Is(err, database.ErrNil) {
+			return nil, api.ErrorNotFound(fmt.Sprintf("trigger with ID = '%s' does not exists", triggerID))
+		}
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	points, err := dataBase.GetMetricStateTimeline(triggerID, metric, from, to)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	return &dto.MetricStateTimeline{
+		TriggerID: triggerID,
+		Metric:    metric,
+		From:      from,
+		To:        to,
+		Intervals: moira.GetMetricStateIntervals(points, from, to),
+	}, nil
+}
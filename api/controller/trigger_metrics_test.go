@@ -338,3 +338,51 @@ func TestGetTriggerMetrics(t *testing.T) {
 		So(triggerMetrics, ShouldBeNil)
 	})
 }
+
+func TestGetTriggerMetricTimeline(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	triggerID := uuid.Must(uuid.NewV4()).String()
+	metric := "super.puper.metric"
+
+	var from int64 = 100
+	var to int64 = 200
+
+	Convey("Trigger does not exist", t, func() {
+		dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{}, database.ErrNil)
+		timeline, err := GetTriggerMetricTimeline(dataBase, triggerID, metric, from, to)
+		So(err, ShouldResemble, api.ErrorNotFound(fmt.Sprintf("trigger with ID = '%s' does not exists", triggerID)))
+		So(timeline, ShouldBeNil)
+	})
+
+	Convey("Failed to get timeline", t, func() {
+		expectedErr := fmt.Errorf("oops")
+		dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID}, nil)
+		dataBase.EXPECT().GetMetricStateTimeline(triggerID, metric, from, to).Return(nil, expectedErr)
+		timeline, err := GetTriggerMetricTimeline(dataBase, triggerID, metric, from, to)
+		So(err, ShouldResemble, api.ErrorInternalServer(expectedErr))
+		So(timeline, ShouldBeNil)
+	})
+
+	Convey("Timeline points are converted to intervals", t, func() {
+		dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID}, nil)
+		dataBase.EXPECT().GetMetricStateTimeline(triggerID, metric, from, to).Return([]*moira.MetricStatePoint{
+			{Timestamp: 90, State: moira.StateOK},
+			{Timestamp: 150, State: moira.StateERROR, Suppressed: true, Maintenance: 170},
+		}, nil)
+		timeline, err := GetTriggerMetricTimeline(dataBase, triggerID, metric, from, to)
+		So(err, ShouldBeNil)
+		So(timeline, ShouldResemble, &dto.MetricStateTimeline{
+			TriggerID: triggerID,
+			Metric:    metric,
+			From:      from,
+			To:        to,
+			Intervals: []moira.MetricStateInterval{
+				{From: 100, To: 150, State: moira.StateOK},
+				{From: 150, To: 170, State: moira.StateERROR, Suppressed: true, Maintenance: true},
+				{From: 170, To: 200, State: moira.StateERROR, Suppressed: true},
+			},
+		})
+	})
+}
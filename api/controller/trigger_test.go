@@ -303,22 +303,22 @@ func TestRemoveTrigger(t *testing.T) {
 	triggerID := uuid.Must(uuid.NewV4()).String()
 
 	Convey("Success", t, func() {
-		dataBase.EXPECT().RemoveTrigger(triggerID).Return(nil)
-		err := RemoveTrigger(dataBase, triggerID)
+		dataBase.EXPECT().RemoveTrigger(triggerID, "user").Return(nil)
+		err := RemoveTrigger(dataBase, triggerID, "user")
 		So(err, ShouldBeNil)
 	})
 
 	Convey("Error remove trigger", t, func() {
 		expected := fmt.Errorf("oooops! Error delete")
-		dataBase.EXPECT().RemoveTrigger(triggerID).Return(expected)
-		err := RemoveTrigger(dataBase, triggerID)
+		dataBase.EXPECT().RemoveTrigger(triggerID, "user").Return(expected)
+		err := RemoveTrigger(dataBase, triggerID, "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 	})
 
 	Convey("Error remove last check", t, func() {
 		expected := fmt.Errorf("oooops! Error delete")
-		dataBase.EXPECT().RemoveTrigger(triggerID).Return(expected)
-		err := RemoveTrigger(dataBase, triggerID)
+		dataBase.EXPECT().RemoveTrigger(triggerID, "user").Return(expected)
+		err := RemoveTrigger(dataBase, triggerID, "user")
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 	})
 }
@@ -492,3 +492,50 @@ func TestSetTriggerMaintenance(t *testing.T) {
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 	})
 }
+
+func TestCheckUserPermissionsForTrigger(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	auth := &api.Authorization{}
+	const triggerID = "triggerID"
+	const teamID = "teamID"
+	const userLogin = "user"
+
+	Convey("Check user permissions for trigger", t, func() {
+		Convey("Trigger without team", func() {
+			dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID}, nil)
+			err := CheckUserPermissionsForTrigger(dataBase, triggerID, userLogin, "", auth)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Trigger does not exist", func() {
+			dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{}, database.ErrNil)
+			err := CheckUserPermissionsForTrigger(dataBase, triggerID, userLogin, "", auth)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Trigger of team and user is viewer", func() {
+			dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID, TeamID: teamID}, nil)
+			dataBase.EXPECT().GetTeam(teamID).Return(moira.Team{ID: teamID}, nil)
+			dataBase.EXPECT().IsTeamContainUser(teamID, userLogin).Return(true, nil)
+			dataBase.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{userLogin: moira.TeamRoleViewer}, nil)
+			err := CheckUserPermissionsForTrigger(dataBase, triggerID, userLogin, "", auth)
+			So(err, ShouldResemble, api.ErrorForbidden("you need editor role in this team to do this"))
+		})
+
+		Convey("Trigger of team and user is not a member", func() {
+			dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID, TeamID: teamID}, nil)
+			dataBase.EXPECT().GetTeam(teamID).Return(moira.Team{ID: teamID}, nil)
+			dataBase.EXPECT().IsTeamContainUser(teamID, userLogin).Return(false, nil)
+			err := CheckUserPermissionsForTrigger(dataBase, triggerID, userLogin, "", auth)
+			So(err, ShouldResemble, api.ErrorForbidden("you are not permitted to manipulate with this team"))
+		})
+
+		Convey("Trigger of team and api token of the team", func() {
+			dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID, TeamID: teamID}, nil)
+			err := CheckUserPermissionsForTrigger(dataBase, triggerID, "team:teamID/ci", teamID, auth)
+			So(err, ShouldBeNil)
+		})
+	})
+}
@@ -88,6 +88,10 @@ func SearchTriggers(database moira.Database, searcher moira.Searcher, options mo
 		}
 		searchResults, total, err = searcher.SearchTriggers(options)
 		if err != nil {
+			var queryErr moira.ErrInvalidSearchQuery
+			if errors.As(err, &queryErr) {
+				return nil, api.ErrorInvalidRequest(err)
+			}
 			return nil, api.ErrorInternalServer(err)
 		}
 	}
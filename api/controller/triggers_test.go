@@ -574,6 +574,14 @@ func TestSearchTriggers(t *testing.T) {
 			So(list, ShouldBeNil)
 		})
 
+		Convey("Invalid search query from searcher", func() {
+			searcherError := moira.ErrInvalidSearchQuery{}
+			mockIndex.EXPECT().SearchTriggers(searchOptions).Return(make([]*moira.SearchResult, 0), int64(0), searcherError)
+			list, err := SearchTriggers(mockDatabase, mockIndex, searchOptions)
+			So(err, ShouldResemble, api.ErrorInvalidRequest(searcherError))
+			So(list, ShouldBeNil)
+		})
+
 		Convey("Error from database", func() {
 			searchOptions.Size = 50
 			searcherError := fmt.Errorf("very bad request")
@@ -14,7 +14,7 @@ func GetUserSettings(database moira.Database, userLogin string, auth *api.Author
 			AuthEnabled: auth.IsEnabled(),
 			Role:        dto.GetRole(userLogin, auth),
 		},
-		Contacts:      make([]moira.ContactData, 0),
+		Contacts:      make([]dto.ContactData, 0),
 		Subscriptions: make([]moira.SubscriptionData, 0),
 	}
 
@@ -43,7 +43,7 @@ func GetUserSettings(database moira.Database, userLogin string, auth *api.Author
 	}
 	for _, contact := range contacts {
 		if contact != nil {
-			userSettings.Contacts = append(userSettings.Contacts, *contact)
+			userSettings.Contacts = append(userSettings.Contacts, dto.NewContactData(*contact))
 		}
 	}
 	return userSettings, nil
@@ -33,7 +33,7 @@ func TestGetUserSettings(t *testing.T) {
 		So(err, ShouldBeNil)
 		So(settings, ShouldResemble, &dto.UserSettings{
 			User:          dto.User{Login: login},
-			Contacts:      []moira.ContactData{*contacts[0], *contacts[1]},
+			Contacts:      []dto.ContactData{dto.NewContactData(*contacts[0]), dto.NewContactData(*contacts[1])},
 			Subscriptions: []moira.SubscriptionData{*subscriptions[0], *subscriptions[1]},
 		})
 	})
@@ -47,7 +47,7 @@ func TestGetUserSettings(t *testing.T) {
 		So(err, ShouldBeNil)
 		So(settings, ShouldResemble, &dto.UserSettings{
 			User:          dto.User{Login: login},
-			Contacts:      make([]moira.ContactData, 0),
+			Contacts:      make([]dto.ContactData, 0),
 			Subscriptions: make([]moira.SubscriptionData, 0),
 		})
 	})
@@ -65,7 +65,7 @@ func TestGetUserSettings(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(settings, ShouldResemble, &dto.UserSettings{
 				User:          dto.User{Login: login, Role: dto.RoleUser, AuthEnabled: true},
-				Contacts:      make([]moira.ContactData, 0),
+				Contacts:      make([]dto.ContactData, 0),
 				Subscriptions: make([]moira.SubscriptionData, 0),
 			})
 		})
@@ -79,7 +79,7 @@ func TestGetUserSettings(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(settings, ShouldResemble, &dto.UserSettings{
 				User:          dto.User{Login: adminLogin, Role: dto.RoleAdmin, AuthEnabled: true},
-				Contacts:      make([]moira.ContactData, 0),
+				Contacts:      make([]dto.ContactData, 0),
 				Subscriptions: make([]moira.SubscriptionData, 0),
 			})
 		})
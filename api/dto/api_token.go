@@ -0,0 +1,101 @@
+package dto
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/moira-alert/moira"
+)
+
+const apiTokenNameLimit = 100
+
+// APIToken is a structure that represents api token in HTTP transfer. Token secret and its hash are never returned.
+type APIToken struct {
+	ID        string                `json:"id" example:"8e4f2b7c-5a0f-4f8a-9d3e-2b1c6a7d9e0f"`
+	Name      string                `json:"name" example:"ci-pipeline"`
+	User      string                `json:"user,omitempty" example:"john"`
+	TeamID    string                `json:"team_id,omitempty" example:"d5d98eb3-ee18-4f75-9364-244f67e23b54"`
+	Scopes    []moira.APITokenScope `json:"scopes" example:"read,triggers"`
+	CreatedBy string                `json:"created_by" example:"john"`
+	CreatedAt int64                 `json:"created_at" example:"1590741878" format:"int64"`
+	ExpiresAt *int64                `json:"expires_at,omitempty" example:"1622277878" format:"int64" extensions:"x-nullable"`
+	Expired   bool                  `json:"expired" example:"false"`
+}
+
+// NewAPIToken creates APIToken from moira.APIToken.
+func NewAPIToken(token moira.APIToken, now time.Time) APIToken {
+	return APIToken{
+		ID:        token.ID,
+		Name:      token.Name,
+		User:      token.User,
+		TeamID:    token.TeamID,
+		Scopes:    token.Scopes,
+		CreatedBy: token.CreatedBy,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+		Expired:   token.IsExpired(now),
+	}
+}
+
+// Bind checks that name, scopes and expiration time of token are valid.
+func (token *APIToken) Bind(request *http.Request) error {
+	token.Name = strings.TrimSpace(token.Name)
+	if token.Name == "" {
+		return fmt.Errorf("token name cannot be empty")
+	}
+	if utf8.RuneCountInString(token.Name) > apiTokenNameLimit {
+		return fmt.Errorf("token name cannot be longer than %d characters", apiTokenNameLimit)
+	}
+
+	if len(token.Scopes) == 0 {
+		return fmt.Errorf("token scopes cannot be empty")
+	}
+	for _, scope := range token.Scopes {
+		if !isValidAPITokenScope(scope) {
+			return fmt.Errorf("unknown token scope '%s'", scope)
+		}
+	}
+
+	if token.ExpiresAt != nil && *token.ExpiresAt <= time.Now().Unix() {
+		return fmt.Errorf("token expiration time must be in the future")
+	}
+	return nil
+}
+
+func isValidAPITokenScope(scope moira.APITokenScope) bool {
+	switch scope {
+	case moira.APITokenScopeRead, moira.APITokenScopeTriggers:
+		return true
+	}
+	teamID, found := strings.CutPrefix(string(scope), moira.APITokenScopeTeamPrefix)
+	return found && teamID != ""
+}
+
+// Render is a function that implements chi Renderer interface for APIToken.
+func (*APIToken) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// CreatedAPIToken contains the value of created token, it is shown only once.
+type CreatedAPIToken struct {
+	APIToken
+	Token string `json:"token" example:"moira_8e4f2b7c-5a0f-4f8a-9d3e-2b1c6a7d9e0f.dGhpcyBpcyBub3QgYSByZWFsIHNlY3JldA"`
+}
+
+// Render is a function that implements chi Renderer interface for CreatedAPIToken.
+func (*CreatedAPIToken) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// APITokenList is a list of api tokens of user or team.
+type APITokenList struct {
+	List []APIToken `json:"list"`
+}
+
+// Render is a function that implements chi Renderer interface for APITokenList.
+func (*APITokenList) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
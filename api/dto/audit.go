@@ -0,0 +1,16 @@
+// nolint
+package dto
+
+import (
+	"net/http"
+
+	"github.com/moira-alert/moira"
+)
+
+type AuditRecordsList struct {
+	List []moira.AuditRecord `json:"list"`
+}
+
+func (*AuditRecordsList) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
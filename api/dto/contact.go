@@ -4,18 +4,36 @@ package dto
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/templating"
 )
 
 type ContactList struct {
-	List []*moira.ContactData `json:"list"`
+	List []*ContactData `json:"list"`
 }
 
 func (*ContactList) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+// ContactData is a contact rendered by api. Signing secret of contact is never rendered, only the flag that it is set.
+type ContactData struct {
+	moira.ContactData
+	HasSigningSecret bool `json:"has_signing_secret" example:"true"`
+}
+
+// NewContactData creates ContactData from moira.ContactData without its signing secret.
+func NewContactData(contact moira.ContactData) ContactData {
+	hasSigningSecret := contact.SigningSecret != ""
+	contact.SigningSecret = ""
+	return ContactData{
+		ContactData:      contact,
+		HasSigningSecret: hasSigningSecret,
+	}
+}
+
 type Contact struct {
 	Type   string `json:"type" example:"mail"`
 	Name   string `json:"name,omitempty" example:"Mail Alerts"`
@@ -23,6 +41,16 @@ type Contact struct {
 	ID     string `json:"id,omitempty" example:"1dd38765-c5be-418d-81fa-7a5f879c2315"`
 	User   string `json:"user,omitempty" example:""`
 	TeamID string `json:"team_id,omitempty"`
+	// MessageTemplate is a Go template of notification message, it overrides message template of sender
+	MessageTemplate string `json:"message_template,omitempty" example:"{{ .State }} {{ .Trigger.Name }}"`
+	// SigningSecret is a key of HMAC signature of requests sent to contact, it overrides signing secret of sender.
+	// It is write-only and is never returned, see HasSigningSecret.
+	SigningSecret string `json:"signing_secret,omitempty" example:"b9f3c2d1e0"`
+	// HasSigningSecret is true if contact has signing secret. On update contact keeps its signing secret
+	// if new one is not given and HasSigningSecret is true, otherwise signing secret is removed.
+	HasSigningSecret bool `json:"has_signing_secret" example:"true"`
+	// Availability describes quiet hours, vacations and Do Not Disturb of contact owner
+	Availability *moira.ContactAvailability `json:"availability,omitempty"`
 }
 
 func (*Contact) Render(w http.ResponseWriter, r *http.Request) error {
@@ -36,5 +64,66 @@ func (contact *Contact) Bind(r *http.Request) error {
 	if contact.Value == "" {
 		return fmt.Errorf("contact value of type %s can not be empty", contact.Type)
 	}
+	if contact.MessageTemplate != "" {
+		if err := templating.ValidateMessageTemplate(contact.MessageTemplate); err != nil {
+			return fmt.Errorf("invalid message template: %w", err)
+		}
+	}
+	if err := contact.Availability.Validate(); err != nil {
+		return fmt.Errorf("invalid availability: %w", err)
+	}
+	if contact.Availability != nil && contact.ID != "" && contact.Availability.BackupContact == contact.ID {
+		return fmt.Errorf("contact can not be backup contact of itself")
+	}
+	return nil
+}
+
+type ContactDoNotDisturb struct {
+	Enabled bool `json:"enabled" example:"true"`
+	// Until is the unix timestamp Do Not Disturb is turned off at, it lasts until it is turned off manually if it is not set
+	Until int64 `json:"until,omitempty" example:"1590741878" format:"int64"`
+}
+
+func (*ContactDoNotDisturb) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (dnd *ContactDoNotDisturb) Bind(r *http.Request) error {
+	if dnd.Until < 0 {
+		return fmt.Errorf("until must not be negative")
+	}
+	if dnd.Until != 0 && dnd.Until <= time.Now().Unix() {
+		return fmt.Errorf("until must be in the future")
+	}
+	return nil
+}
+
+type MessageTemplateRequest struct {
+	Template string `json:"template" example:"{{ .State }} {{ .Trigger.Name }}"`
+	// HTML enables escaping of populated data as it is done for mail messages
+	HTML bool `json:"html,omitempty" example:"false"`
+}
+
+func (request *MessageTemplateRequest) Bind(r *http.Request) error {
+	if request.Template == "" {
+		return fmt.Errorf("template can not be empty")
+	}
+	return nil
+}
+
+type MessageTemplateValidation struct {
+	Valid bool   `json:"valid" example:"false"`
+	Error string `json:"error,omitempty" example:"template: populate-template:1: unexpected \"}\" in operand"`
+}
+
+func (*MessageTemplateValidation) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+type MessageTemplatePreview struct {
+	Message string `json:"message" example:"ERROR Not enough disk space left"`
+}
+
+func (*MessageTemplatePreview) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
@@ -0,0 +1,64 @@
+// nolint
+package dto
+
+import (
+	"net/http"
+
+	"github.com/moira-alert/moira"
+)
+
+type DeliveryAttemptsList struct {
+	List []moira.DeliveryAttempt `json:"list"`
+}
+
+func (*DeliveryAttemptsList) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+type DeadLettersList struct {
+	List []DeadLetter `json:"list"`
+}
+
+func (*DeadLettersList) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+// DeadLetter is a dead letter rendered by api, signing secret of its contact is not rendered.
+type DeadLetter struct {
+	moira.DeadLetter
+	Contact ContactData `json:"contact"`
+}
+
+// NewDeadLetter creates DeadLetter from moira.DeadLetter without signing secret of its contact.
+func NewDeadLetter(letter moira.DeadLetter) DeadLetter {
+	contact := NewContactData(letter.Contact)
+	letter.Contact = contact.ContactData
+	return DeadLetter{
+		DeadLetter: letter,
+		Contact:    contact,
+	}
+}
+
+func (*DeadLetter) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+// DeadLetterReplayRequest is a request to send undelivered notifications again.
+// Notifications are sent to the original contact if contact_id is empty.
+type DeadLetterReplayRequest struct {
+	ContactID string `json:"contact_id,omitempty" example:"1dd38765-c5be-418d-81fa-7a5f879c2315"`
+}
+
+func (*DeadLetterReplayRequest) Bind(*http.Request) error {
+	return nil
+}
+
+type DeadLetterReplay struct {
+	DeadLetterID       string `json:"dead_letter_id" example:"5b1d4c2e-8f0a-4e3b-9c7d-6a2f1e0b3d4c"`
+	ContactID          string `json:"contact_id" example:"1dd38765-c5be-418d-81fa-7a5f879c2315"`
+	NotificationsCount int    `json:"notifications_count" example:"2"`
+}
+
+func (*DeadLetterReplay) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
@@ -17,3 +17,25 @@ type EventsList struct {
 func (*EventsList) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
+
+// EventsStreamFilter selects events of events stream. Events of triggers with all given tags,
+// of given team and with one of given trigger IDs are selected, empty filter selects all events.
+type EventsStreamFilter struct {
+	Tags       []string
+	TeamID     string
+	TriggerIDs []string
+}
+
+// EventsStreamEvent is a notification event sent to events stream client.
+// ID is used by client as Last-Event-ID to continue reading stream after reconnection.
+type EventsStreamEvent struct {
+	ID    string
+	Type  string
+	Event moira.NotificationEvent
+}
+
+// Events stream event types.
+const (
+	EventsStreamEventTrigger = "trigger"
+	EventsStreamEventMetric  = "metric"
+)
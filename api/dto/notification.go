@@ -2,20 +2,38 @@
 package dto
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/templating"
 )
 
 type NotificationsList struct {
-	Total int64                          `json:"total" example:"0" format:"int64"`
-	List  []*moira.ScheduledNotification `json:"list"`
+	Total int64                    `json:"total" example:"0" format:"int64"`
+	List  []*ScheduledNotification `json:"list"`
 }
 
 func (*NotificationsList) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+// ScheduledNotification is a notification rendered by api, signing secret of its contact is not rendered.
+type ScheduledNotification struct {
+	moira.ScheduledNotification
+	Contact ContactData `json:"contact"`
+}
+
+// NewScheduledNotification creates ScheduledNotification from moira.ScheduledNotification without signing secret of its contact.
+func NewScheduledNotification(notification moira.ScheduledNotification) *ScheduledNotification {
+	contact := NewContactData(notification.Contact)
+	notification.Contact = contact.ContactData
+	return &ScheduledNotification{
+		ScheduledNotification: notification,
+		Contact:               contact,
+	}
+}
+
 type NotificationDeleteResponse struct {
 	Result int64 `json:"result" example:"0" format:"int64"`
 }
@@ -23,3 +41,63 @@ type NotificationDeleteResponse struct {
 func (*NotificationDeleteResponse) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
+
+// NotificationPreviewRequest describes notification which should be rendered without delivery.
+type NotificationPreviewRequest struct {
+	// ID of saved trigger, either trigger_id or trigger must be set
+	TriggerID string `json:"trigger_id,omitempty" example:"bcba82f5-48cf-44c0-b7d6-e1d32c64a88c"`
+	// Trigger which is not saved yet
+	Trigger *TriggerModel `json:"trigger,omitempty" extensions:"x-nullable"`
+	// Type of contact, sender configured for this contact type renders the notification
+	ContactType string `json:"contact_type" example:"slack"`
+	// Value of contact, e.g. channel or email
+	ContactValue string `json:"contact_value,omitempty" example:"#alerts"`
+	// User-defined message template of contact
+	MessageTemplate string `json:"message_template,omitempty" example:"{{ .State }} {{ .Trigger.Name }}"`
+	// Events to render, if empty then events of saved trigger from history are used if from or to are set, otherwise sample event is used
+	Events []moira.NotificationEvent `json:"events,omitempty"`
+	// Start of events history range
+	From int64 `json:"from,omitempty" example:"1590741878" format:"int64"`
+	// End of events history range, default is now
+	To int64 `json:"to,omitempty" example:"1590745478" format:"int64"`
+	// If true, notification is rendered as throttled
+	Throttled bool `json:"throttled,omitempty" example:"false"`
+	// Plots of saved trigger are rendered if plotting is enabled
+	Plotting *moira.PlottingData `json:"plotting,omitempty" extensions:"x-nullable"`
+}
+
+func (request *NotificationPreviewRequest) Bind(r *http.Request) error {
+	if request.ContactType == "" {
+		return fmt.Errorf("contact_type can not be empty")
+	}
+	if request.TriggerID == "" && request.Trigger == nil {
+		return fmt.Errorf("trigger_id or trigger must be set")
+	}
+	if request.TriggerID != "" && request.Trigger != nil {
+		return fmt.Errorf("only one of trigger_id and trigger can be set")
+	}
+	if (request.From != 0 || request.To != 0) && request.TriggerID == "" {
+		return fmt.Errorf("events history can be used only with trigger_id")
+	}
+	if request.MessageTemplate != "" {
+		if err := templating.ValidateMessageTemplate(request.MessageTemplate); err != nil {
+			return fmt.Errorf("invalid message template: %w", err)
+		}
+	}
+	if request.Plotting != nil {
+		return request.Plotting.Validate()
+	}
+	return nil
+}
+
+// NotificationPreview is a notification rendered the way sender delivers it.
+type NotificationPreview struct {
+	ContactType string `json:"contact_type" example:"slack"`
+	moira.NotificationPayload
+	// Images of trigger plots, PNG or SVG depending on plotting settings
+	Plots [][]byte `json:"plots,omitempty"`
+}
+
+func (*NotificationPreview) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
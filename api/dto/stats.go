@@ -0,0 +1,24 @@
+// nolint
+package dto
+
+import (
+	"net/http"
+
+	"github.com/moira-alert/moira/analytics"
+)
+
+type TriggersAnalytics struct {
+	analytics.TriggersReport
+}
+
+func (*TriggersAnalytics) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+type NotificationsAnalytics struct {
+	analytics.NotificationsReport
+}
+
+func (*NotificationsAnalytics) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
@@ -63,6 +63,9 @@ func (subscription *Subscription) Bind(request *http.Request) error {
 	if len(subscription.Contacts) == 0 {
 		return fmt.Errorf("subscription must have contacts")
 	}
+	if err := subscription.Plotting.Validate(); err != nil {
+		return err
+	}
 	return subscription.checkContacts(request)
 }
 
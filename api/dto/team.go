@@ -80,6 +80,8 @@ func (UserTeams) Render(w http.ResponseWriter, r *http.Request) error {
 // TeamMembers is a structure that represents a team members in HTTP transfer.
 type TeamMembers struct {
 	Usernames []string `json:"usernames" example:"anonymous"`
+	// Roles of team members, it is ignored in requests, use role endpoint to change it
+	Roles map[string]moira.TeamRole `json:"roles,omitempty" example:"anonymous:owner"`
 }
 
 // Bind is a method that implements Binder interface from chi and checks that validity of data in request.
@@ -95,9 +97,22 @@ func (TeamMembers) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+// TeamUserRole is a structure that represents a role of team member in HTTP transfer.
+type TeamUserRole struct {
+	Role moira.TeamRole `json:"role" example:"editor" enums:"owner,editor,viewer"`
+}
+
+// Bind is a method that implements Binder interface from chi and checks that validity of data in request.
+func (r TeamUserRole) Bind(request *http.Request) error {
+	if !r.Role.IsValid() {
+		return fmt.Errorf("unknown team role '%s', should be one of: %s, %s, %s", r.Role, moira.TeamRoleOwner, moira.TeamRoleEditor, moira.TeamRoleViewer)
+	}
+	return nil
+}
+
 type TeamSettings struct {
 	TeamID        string                   `json:"team_id" example:"d5d98eb3-ee18-4f75-9364-244f67e23b54"`
-	Contacts      []moira.ContactData      `json:"contacts"`
+	Contacts      []ContactData            `json:"contacts"`
 	Subscriptions []moira.SubscriptionData `json:"subscriptions"`
 }
 
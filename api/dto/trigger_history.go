@@ -0,0 +1,34 @@
+// nolint
+package dto
+
+import (
+	"net/http"
+
+	"github.com/moira-alert/moira"
+)
+
+type TriggerHistoryList struct {
+	List []moira.TriggerHistoryItem `json:"list"`
+}
+
+func (*TriggerHistoryList) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+// TriggerFieldChange describes the change of single trigger field between two versions.
+type TriggerFieldChange struct {
+	Field string      `json:"field" example:"name"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type TriggerHistoryDiff struct {
+	TriggerID string               `json:"trigger_id" example:"292516ed-4924-4154-a62c-ebe312431fce"`
+	Version   int64                `json:"version" example:"3" format:"int64"`
+	CompareTo int64                `json:"compare_to" example:"2" format:"int64"`
+	Changes   []TriggerFieldChange `json:"changes"`
+}
+
+func (*TriggerHistoryDiff) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
@@ -87,6 +87,8 @@ type TriggerModel struct {
 	CreatedBy string `json:"created_by"`
 	// Username who updated trigger
 	UpdatedBy string `json:"updated_by"`
+	// ID of the team that owns trigger, only editors of the team can change owned trigger
+	TeamID string `json:"team_id,omitempty" example:"d5d98eb3-ee18-4f75-9364-244f67e23b54"`
 }
 
 // ClusterKey returns cluster key composed of trigger source and cluster id associated with the trigger.
@@ -115,6 +117,7 @@ func (model *TriggerModel) ToMoiraTrigger() *moira.Trigger {
 		MuteNewMetrics: model.MuteNewMetrics,
 		AloneMetrics:   model.AloneMetrics,
 		UpdatedBy:      model.UpdatedBy,
+		TeamID:         model.TeamID,
 	}
 }
 
@@ -143,6 +146,7 @@ func CreateTriggerModel(trigger *moira.Trigger) TriggerModel {
 		UpdatedAt:      getDateTime(trigger.UpdatedAt),
 		CreatedBy:      trigger.CreatedBy,
 		UpdatedBy:      trigger.UpdatedBy,
+		TeamID:         trigger.TeamID,
 	}
 }
 
@@ -446,6 +450,19 @@ func (*TriggerMetrics) Render(http.ResponseWriter, *http.Request) error {
 	return nil
 }
 
+// MetricStateTimeline contains intervals of metric states within time range.
+type MetricStateTimeline struct {
+	TriggerID string                      `json:"trigger_id" example:"bcba82f5-48cf-44c0-b7d6-e1d32c64a88c"`
+	Metric    string                      `json:"metric" example:"DevOps.my_server.hdd.freespace_mbytes"`
+	From      int64                       `json:"from" example:"1590741878" format:"int64"`
+	To        int64                       `json:"to" example:"1590828278" format:"int64"`
+	Intervals []moira.MetricStateInterval `json:"intervals"`
+}
+
+func (*MetricStateTimeline) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
 type PatternMetrics struct {
 	Pattern    string                          `json:"pattern"`
 	Metrics    map[string][]*moira.MetricValue `json:"metrics"`
@@ -10,7 +10,7 @@ import (
 
 type UserSettings struct {
 	User
-	Contacts      []moira.ContactData      `json:"contacts"`
+	Contacts      []ContactData            `json:"contacts"`
 	Subscriptions []moira.SubscriptionData `json:"subscriptions"`
 }
 
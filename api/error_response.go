@@ -61,6 +61,15 @@ func ErrorNotFound(errorText string) *ErrorResponse {
 	}
 }
 
+// ErrorUnauthorized return 401 with given error text.
+func ErrorUnauthorized(errorText string) *ErrorResponse {
+	return &ErrorResponse{
+		HTTPStatusCode: http.StatusUnauthorized,
+		StatusText:     "Unauthorized",
+		ErrorText:      errorText,
+	}
+}
+
 // ErrorForbidden return 403 with given error text.
 func ErrorForbidden(errorText string) *ErrorResponse {
 	return &ErrorResponse{
@@ -107,6 +116,11 @@ type ErrorNotFoundExample struct {
 	ErrorText  string `json:"error" example:"resource with ID '66741a8c-c2ba-4357-a2c9-ee78e0e7' does not exist"`
 }
 
+type ErrorUnauthorizedExample struct {
+	StatusText string `json:"status" example:"Unauthorized"`
+	ErrorText  string `json:"error" example:"bearer token is expired"`
+}
+
 type ErrorForbiddenExample struct {
 	StatusText string `json:"status" example:"Forbidden"`
 	ErrorText  string `json:"error" example:"you cannot access this resource"`
@@ -0,0 +1,261 @@
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "moira"
+
+// Trigger labels which can be added to trigger series in addition to trigger_id.
+const (
+	LabelName          = "name"
+	LabelTeamID        = "team_id"
+	LabelTags          = "tags"
+	LabelTriggerSource = "trigger_source"
+	LabelClusterID     = "cluster_id"
+)
+
+var triggerLabelValues = map[string]func(trigger *moira.Trigger) string{
+	LabelName:          func(trigger *moira.Trigger) string { return trigger.Name },
+	LabelTeamID:        func(trigger *moira.Trigger) string { return trigger.TeamID },
+	LabelTags:          func(trigger *moira.Trigger) string { return joinTags(trigger.Tags) },
+	LabelTriggerSource: func(trigger *moira.Trigger) string { return trigger.TriggerSource.FillInIfNotSet(false).String() },
+	LabelClusterID:     func(trigger *moira.Trigger) string { return trigger.ClusterId.FillInIfNotSet().String() },
+}
+
+// Config contains configuration of trigger states exporter.
+type Config struct {
+	// Enabled is true if trigger states are exposed in Prometheus format.
+	Enabled bool
+	// CacheTTL is a period during which trigger checks read from database are reused between scrapes.
+	CacheTTL time.Duration
+	// TriggerLabels are labels added to trigger series in addition to trigger_id.
+	TriggerLabels []string
+	// TagSeries is true if moira_trigger_tag_info series are exposed for each tag of each trigger.
+	TagSeries bool
+	// MetricStates is true if series are exposed for each metric of each trigger.
+	MetricStates bool
+}
+
+// Validate checks that all trigger labels are known and not repeated.
+func (config Config) Validate() error {
+	unique := make(map[string]struct{}, len(config.TriggerLabels))
+	for _, label := range config.TriggerLabels {
+		if _, ok := triggerLabelValues[label]; !ok {
+			return fmt.Errorf("unknown trigger label: %s", label)
+		}
+		if _, ok := unique[label]; ok {
+			return fmt.Errorf("trigger label is repeated: %s", label)
+		}
+		unique[label] = struct{}{}
+	}
+	return nil
+}
+
+// NewHandler returns handler which exposes trigger states in Prometheus text format.
+func NewHandler(database moira.Database, logger moira.Logger, config Config) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewCollector(database, logger, config))
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Collector collects trigger and metric states from last checks of all triggers.
+type Collector struct {
+	database moira.Database
+	logger   moira.Logger
+	config   Config
+	now      func() time.Time
+
+	triggerState        *prometheus.Desc
+	triggerScore        *prometheus.Desc
+	triggerMaintenance  *prometheus.Desc
+	triggerLastCheckAge *prometheus.Desc
+	triggerTagInfo      *prometheus.Desc
+	metricState         *prometheus.Desc
+	metricMaintenance   *prometheus.Desc
+	metricLastCheckAge  *prometheus.Desc
+	scrapeErrorsTotal   prometheus.Counter
+	lastUpdateDuration  prometheus.Gauge
+
+	mutex     sync.Mutex
+	checks    []*moira.TriggerCheck
+	updatedAt time.Time
+}
+
+// NewCollector creates collector of trigger states. Config is expected to be validated.
+func NewCollector(database moira.Database, logger moira.Logger, config Config) *Collector {
+	triggerLabels := append([]string{"trigger_id"}, config.TriggerLabels...)
+	metricLabels := []string{"trigger_id", "metric"}
+
+	return &Collector{
+		database: database,
+		logger:   logger,
+		config:   config,
+		now:      time.Now,
+
+		triggerState: prometheus.NewDesc(prometheus.BuildFQName(namespace, "trigger", "state"),
+			"Current state of trigger, value is always 1.", append(triggerLabels, "state"), nil),
+		triggerScore: prometheus.NewDesc(prometheus.BuildFQName(namespace, "trigger", "score"),
+			"Score of trigger, the more metrics are in bad states the higher it is.", triggerLabels, nil),
+		triggerMaintenance: prometheus.NewDesc(prometheus.BuildFQName(namespace, "trigger", "maintenance_until_seconds"),
+			"Unix time until which trigger is in maintenance.", triggerLabels, nil),
+		triggerLastCheckAge: prometheus.NewDesc(prometheus.BuildFQName(namespace, "trigger", "last_check_age_seconds"),
+			"Seconds since last check of trigger.", triggerLabels, nil),
+		triggerTagInfo: prometheus.NewDesc(prometheus.BuildFQName(namespace, "trigger", "tag_info"),
+			"Tag of trigger, value is always 1.", []string{"trigger_id", "tag"}, nil),
+		metricState: prometheus.NewDesc(prometheus.BuildFQName(namespace, "metric", "state"),
+			"Current state of trigger metric, value is always 1.", append(metricLabels, "state"), nil),
+		metricMaintenance: prometheus.NewDesc(prometheus.BuildFQName(namespace, "metric", "maintenance_until_seconds"),
+			"Unix time until which trigger metric is in maintenance.", metricLabels, nil),
+		metricLastCheckAge: prometheus.NewDesc(prometheus.BuildFQName(namespace, "metric", "last_check_age_seconds"),
+			"Seconds since last check of trigger metric.", metricLabels, nil),
+		scrapeErrorsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "exporter",
+			Name:      "errors_total",
+			Help:      "Number of failed reads of trigger checks from database.",
+		}),
+		lastUpdateDuration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "exporter",
+			Name:      "last_update_duration_seconds",
+			Help:      "Duration of last read of trigger checks from database.",
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (collector *Collector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- collector.triggerState
+	descs <- collector.triggerScore
+	descs <- collector.triggerMaintenance
+	descs <- collector.triggerLastCheckAge
+	if collector.config.TagSeries {
+		descs <- collector.triggerTagInfo
+	}
+	if collector.config.MetricStates {
+		descs <- collector.metricState
+		descs <- collector.metricMaintenance
+		descs <- collector.metricLastCheckAge
+	}
+	collector.scrapeErrorsTotal.Describe(descs)
+	collector.lastUpdateDuration.Describe(descs)
+}
+
+// Collect implements prometheus.Collector.
+// Trigger checks are cached for CacheTTL, stale checks are exposed if database can not be read.
+func (collector *Collector) Collect(metrics chan<- prometheus.Metric) {
+	checks, err := collector.getChecks()
+	if err != nil {
+		metrics <- prometheus.NewInvalidMetric(collector.triggerState, err)
+	}
+
+	now := collector.now().Unix()
+	for _, check := range checks {
+		collector.collectTrigger(metrics, check, now)
+	}
+
+	collector.scrapeErrorsTotal.Collect(metrics)
+	collector.lastUpdateDuration.Collect(metrics)
+}
+
+func (collector *Collector) getChecks() ([]*moira.TriggerCheck, error) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	if collector.checks != nil && collector.now().Sub(collector.updatedAt) < collector.config.CacheTTL {
+		return collector.checks, nil
+	}
+
+	startedAt := collector.now()
+	checks, err := collector.readChecks()
+	if err != nil {
+		collector.scrapeErrorsTotal.Inc()
+		collector.logger.Error().
+			Error(err).
+			Msg("Failed to read trigger checks for exporter")
+		if collector.checks != nil {
+			return collector.checks, nil
+		}
+		return nil, err
+	}
+
+	collector.lastUpdateDuration.Set(collector.now().Sub(startedAt).Seconds())
+	collector.checks = checks
+	collector.updatedAt = collector.now()
+	return checks, nil
+}
+
+func (collector *Collector) readChecks() ([]*moira.TriggerCheck, error) {
+	triggerIDs, err := collector.database.GetAllTriggerIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	checks, err := collector.database.GetTriggerChecks(triggerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*moira.TriggerCheck, 0, len(checks))
+	for _, check := range checks {
+		if check != nil {
+			result = append(result, check)
+		}
+	}
+	return result, nil
+}
+
+func (collector *Collector) collectTrigger(metrics chan<- prometheus.Metric, check *moira.TriggerCheck, now int64) {
+	labels := make([]string, 0, len(collector.config.TriggerLabels)+1)
+	labels = append(labels, check.ID)
+	for _, label := range collector.config.TriggerLabels {
+		labels = append(labels, triggerLabelValues[label](&check.Trigger))
+	}
+
+	lastCheck := &check.LastCheck
+	if lastCheck.State != "" {
+		metrics <- prometheus.MustNewConstMetric(collector.triggerState, prometheus.GaugeValue, 1, append(labels, string(lastCheck.State))...)
+	}
+	metrics <- prometheus.MustNewConstMetric(collector.triggerScore, prometheus.GaugeValue, float64(lastCheck.Score), labels...)
+	if lastCheck.Maintenance > 0 {
+		metrics <- prometheus.MustNewConstMetric(collector.triggerMaintenance, prometheus.GaugeValue, float64(lastCheck.Maintenance), labels...)
+	}
+	if lastCheck.Timestamp > 0 {
+		metrics <- prometheus.MustNewConstMetric(collector.triggerLastCheckAge, prometheus.GaugeValue, float64(now-lastCheck.Timestamp), labels...)
+	}
+
+	if collector.config.TagSeries {
+		for _, tag := range check.Tags {
+			metrics <- prometheus.MustNewConstMetric(collector.triggerTagInfo, prometheus.GaugeValue, 1, check.ID, tag)
+		}
+	}
+
+	if collector.config.MetricStates {
+		for metric, state := range lastCheck.Metrics {
+			metrics <- prometheus.MustNewConstMetric(collector.metricState, prometheus.GaugeValue, 1, check.ID, metric, string(state.State))
+			if state.Maintenance > 0 {
+				metrics <- prometheus.MustNewConstMetric(collector.metricMaintenance, prometheus.GaugeValue, float64(state.Maintenance), check.ID, metric)
+			}
+			if state.Timestamp > 0 {
+				metrics <- prometheus.MustNewConstMetric(collector.metricLastCheckAge, prometheus.GaugeValue, float64(now-state.Timestamp), check.ID, metric)
+			}
+		}
+	}
+}
+
+func joinTags(tags []string) string {
+	sorted := make([]string, len(tags))
+	copy(sorted, tags)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
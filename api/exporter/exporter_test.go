@@ -0,0 +1,142 @@
+package exporter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConfigValidate(t *testing.T) {
+	Convey("Test config validate", t, func() {
+		So(Config{TriggerLabels: []string{LabelName, LabelTags}}.Validate(), ShouldBeNil)
+		So(Config{TriggerLabels: []string{"unknown"}}.Validate(), ShouldResemble, fmt.Errorf("unknown trigger label: unknown"))
+		So(Config{TriggerLabels: []string{LabelName, LabelName}}.Validate(), ShouldResemble, fmt.Errorf("trigger label is repeated: name"))
+	})
+}
+
+func TestCollector(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	database := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Exporter")
+
+	now := time.Unix(1000, 0)
+	checks := []*moira.TriggerCheck{
+		{
+			Trigger: moira.Trigger{ID: "trigger1", Name: "Disk", Tags: []string{"disk", "db"}, TeamID: "team"},
+			LastCheck: moira.CheckData{
+				State:       moira.StateERROR,
+				Score:       100,
+				Timestamp:   940,
+				Maintenance: 2000,
+				Metrics: map[string]moira.MetricState{
+					"db.disk": {State: moira.StateERROR, Timestamp: 940, Maintenance: 1500},
+				},
+			},
+		},
+		nil,
+		{Trigger: moira.Trigger{ID: "trigger2", Name: "New"}},
+	}
+
+	Convey("Test collector", t, func() {
+		Convey("Trigger series with configured labels", func() {
+			database.EXPECT().GetAllTriggerIDs().Return([]string{"trigger1", "removed", "trigger2"}, nil)
+			database.EXPECT().GetTriggerChecks([]string{"trigger1", "removed", "trigger2"}).Return(checks, nil)
+			collector := NewCollector(database, logger, Config{CacheTTL: time.Minute, TriggerLabels: []string{LabelName, LabelTags}})
+			collector.now = func() time.Time { return now }
+
+			expected := `
+# HELP moira_trigger_last_check_age_seconds Seconds since last check of trigger.
+# TYPE moira_trigger_last_check_age_seconds gauge
+moira_trigger_last_check_age_seconds{name="Disk",tags="db,disk",trigger_id="trigger1"} 60
+# HELP moira_trigger_maintenance_until_seconds Unix time until which trigger is in maintenance.
+# TYPE moira_trigger_maintenance_until_seconds gauge
+moira_trigger_maintenance_until_seconds{name="Disk",tags="db,disk",trigger_id="trigger1"} 2000
+# HELP moira_trigger_score Score of trigger, the more metrics are in bad states the higher it is.
+# TYPE moira_trigger_score gauge
+moira_trigger_score{name="Disk",tags="db,disk",trigger_id="trigger1"} 100
+moira_trigger_score{name="New",tags="",trigger_id="trigger2"} 0
+# HELP moira_trigger_state Current state of trigger, value is always 1.
+# TYPE moira_trigger_state gauge
+moira_trigger_state{name="Disk",state="ERROR",tags="db,disk",trigger_id="trigger1"} 1
+`
+			So(testutil.CollectAndCompare(collector, strings.NewReader(expected),
+				"moira_trigger_state", "moira_trigger_score", "moira_trigger_maintenance_until_seconds", "moira_trigger_last_check_age_seconds"), ShouldBeNil)
+
+			Convey("Checks are cached", func() {
+				So(testutil.CollectAndCount(collector, "moira_trigger_score"), ShouldEqual, 2)
+			})
+		})
+
+		Convey("Trigger source and cluster of triggers saved without them are filled in", func() {
+			legacyChecks := []*moira.TriggerCheck{
+				{Trigger: moira.Trigger{ID: "local"}},
+				{Trigger: moira.Trigger{ID: "prometheus", TriggerSource: moira.PrometheusRemote, ClusterId: "staging"}},
+			}
+			database.EXPECT().GetAllTriggerIDs().Return([]string{"local", "prometheus"}, nil)
+			database.EXPECT().GetTriggerChecks([]string{"local", "prometheus"}).Return(legacyChecks, nil)
+			collector := NewCollector(database, logger, Config{TriggerLabels: []string{LabelTriggerSource, LabelClusterID}})
+
+			expected := `
+# HELP moira_trigger_score Score of trigger, the more metrics are in bad states the higher it is.
+# TYPE moira_trigger_score gauge
+moira_trigger_score{cluster_id="default",trigger_id="local",trigger_source="graphite_local"} 0
+moira_trigger_score{cluster_id="staging",trigger_id="prometheus",trigger_source="prometheus_remote"} 0
+`
+			So(testutil.CollectAndCompare(collector, strings.NewReader(expected), "moira_trigger_score"), ShouldBeNil)
+		})
+
+		Convey("Tag and metric series", func() {
+			database.EXPECT().GetAllTriggerIDs().Return([]string{"trigger1"}, nil)
+			database.EXPECT().GetTriggerChecks([]string{"trigger1"}).Return(checks[:1], nil)
+			collector := NewCollector(database, logger, Config{TagSeries: true, MetricStates: true})
+			collector.now = func() time.Time { return now }
+
+			expected := `
+# HELP moira_metric_last_check_age_seconds Seconds since last check of trigger metric.
+# TYPE moira_metric_last_check_age_seconds gauge
+moira_metric_last_check_age_seconds{metric="db.disk",trigger_id="trigger1"} 60
+# HELP moira_metric_maintenance_until_seconds Unix time until which trigger metric is in maintenance.
+# TYPE moira_metric_maintenance_until_seconds gauge
+moira_metric_maintenance_until_seconds{metric="db.disk",trigger_id="trigger1"} 1500
+# HELP moira_metric_state Current state of trigger metric, value is always 1.
+# TYPE moira_metric_state gauge
+moira_metric_state{metric="db.disk",state="ERROR",trigger_id="trigger1"} 1
+# HELP moira_trigger_tag_info Tag of trigger, value is always 1.
+# TYPE moira_trigger_tag_info gauge
+moira_trigger_tag_info{tag="db",trigger_id="trigger1"} 1
+moira_trigger_tag_info{tag="disk",trigger_id="trigger1"} 1
+`
+			So(testutil.CollectAndCompare(collector, strings.NewReader(expected),
+				"moira_trigger_tag_info", "moira_metric_state", "moira_metric_maintenance_until_seconds", "moira_metric_last_check_age_seconds"), ShouldBeNil)
+		})
+
+		Convey("Stale checks are exposed on database error", func() {
+			gomock.InOrder(
+				database.EXPECT().GetAllTriggerIDs().Return([]string{"trigger1"}, nil),
+				database.EXPECT().GetTriggerChecks([]string{"trigger1"}).Return(checks[:1], nil),
+				database.EXPECT().GetAllTriggerIDs().Return(nil, fmt.Errorf("error")),
+			)
+			collector := NewCollector(database, logger, Config{})
+
+			So(testutil.CollectAndCount(collector, "moira_trigger_score"), ShouldEqual, 1)
+			So(testutil.CollectAndCount(collector, "moira_trigger_score"), ShouldEqual, 1)
+			So(testutil.ToFloat64(collector.scrapeErrorsTotal), ShouldEqual, 1)
+		})
+
+		Convey("Database error without cached checks", func() {
+			database.EXPECT().GetAllTriggerIDs().Return(nil, fmt.Errorf("error"))
+			collector := NewCollector(database, logger, Config{})
+
+			So(testutil.CollectAndCompare(collector, strings.NewReader(""), "moira_trigger_score"), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+func userAPITokens(router chi.Router) {
+	router.Get("/", getUserAPITokens)
+	router.Post("/", createAPIToken)
+	apiToken(router)
+}
+
+func teamAPITokens(router chi.Router) {
+	router.Get("/", getTeamAPITokens)
+	router.Post("/", createAPIToken)
+	apiToken(router)
+}
+
+func apiToken(router chi.Router) {
+	router.Route("/{tokenId}", func(router chi.Router) {
+		router.Use(middleware.APITokenIDContext)
+		router.Use(apiTokenFilter)
+		router.Get("/", getAPIToken)
+		router.Put("/", updateAPIToken)
+		router.Delete("/", removeAPIToken)
+	})
+}
+
+// apiTokenFilter is middleware that checks that api token is owned by user or by team from the request.
+func apiTokenFilter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		tokenID := middleware.GetAPITokenID(request)
+		userLogin := middleware.GetLogin(request)
+		teamID := middleware.GetTeamID(request)
+		token, err := controller.CheckUserPermissionsForAPIToken(database, tokenID, userLogin, teamID)
+		if err != nil {
+			render.Render(writer, request, err) //nolint:errcheck
+			return
+		}
+		ctx := context.WithValue(request.Context(), apiTokenKey, token)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get personal api tokens of the user
+//	@id			get-user-api-tokens
+//	@tags		user
+//	@produce	json
+//	@success	200	{object}	dto.APITokenList				"Api tokens fetched successfully"
+//	@failure	422	{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500	{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/user/tokens [get]
+func getUserAPITokens(writer http.ResponseWriter, request *http.Request) {
+	tokens, err := controller.GetUserAPITokens(database, middleware.GetLogin(request))
+	if err != nil {
+		render.Render(writer, request, err) //nolint:errcheck
+		return
+	}
+
+	if err := render.Render(writer, request, tokens); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
+		return
+	}
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get api tokens of the team
+//	@id			get-team-api-tokens
+//	@tags		team
+//	@produce	json
+//	@param		teamID	path		string							true	"ID of the team"	default(d5d98eb3-ee18-4f75-9364-244f67e23b54)
+//	@success	200		{object}	dto.APITokenList				"Api tokens fetched successfully"
+//	@failure	403		{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure	404		{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	422		{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500		{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/teams/{teamID}/tokens [get]
+func getTeamAPITokens(writer http.ResponseWriter, request *http.Request) {
+	tokens, err := controller.GetTeamAPITokens(database, middleware.GetTeamID(request))
+	if err != nil {
+		render.Render(writer, request, err) //nolint:errcheck
+		return
+	}
+
+	if err := render.Render(writer, request, tokens); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
+		return
+	}
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary		Create a new api token
+//	@description	Token value is returned only once. Personal token acts on behalf of the user, team token acts as service account of the team.
+//	@id				create-api-token
+//	@tags			user
+//	@accept			json
+//	@produce		json
+//	@param			teamID	path		string							true	"ID of the team"	default(d5d98eb3-ee18-4f75-9364-244f67e23b54)
+//	@param			token	body		dto.APIToken					true	"Api token name, scopes and expiration time"
+//	@success		200		{object}	dto.CreatedAPIToken				"Api token created successfully"
+//	@failure		400		{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure		403		{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure		422		{object}	api.ErrorRenderExample			"Render error"
+//	@failure		500		{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router			/user/tokens [post]
+//	@router			/teams/{teamID}/tokens [post]
+func createAPIToken(writer http.ResponseWriter, request *http.Request) {
+	token := &dto.APIToken{}
+	if err := render.Bind(request, token); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint:errcheck
+		return
+	}
+
+	userLogin := middleware.GetLogin(request)
+	auth := middleware.GetAuth(request)
+	created, err := controller.CreateAPIToken(database, auth, token, userLogin, middleware.GetTeamID(request))
+	if err != nil {
+		render.Render(writer, request, err) //nolint:errcheck
+		return
+	}
+	recordAudit(request, moira.AuditActionCreate, moira.AuditObjectAPIToken, created.ID, nil, created.APIToken)
+
+	if err := render.Render(writer, request, created); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
+		return
+	}
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get api token by ID
+//	@id			get-api-token
+//	@tags		user
+//	@produce	json
+//	@param		teamID	path		string							true	"ID of the team"	default(d5d98eb3-ee18-4f75-9364-244f67e23b54)
+//	@param		tokenID	path		string							true	"ID of the api token"	default(8e4f2b7c-5a0f-4f8a-9d3e-2b1c6a7d9e0f)
+//	@success	200		{object}	dto.APIToken					"Api token fetched successfully"
+//	@failure	403		{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure	404		{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	422		{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500		{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/user/tokens/{tokenID} [get]
+//	@router		/teams/{teamID}/tokens/{tokenID} [get]
+func getAPIToken(writer http.ResponseWriter, request *http.Request) {
+	token := request.Context().Value(apiTokenKey).(moira.APIToken)
+	response := dto.NewAPIToken(token, time.Now())
+	if err := render.Render(writer, request, &response); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
+		return
+	}
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Update name, scopes and expiration time of api token
+//	@id			update-api-token
+//	@tags		user
+//	@accept		json
+//	@produce	json
+//	@param		teamID	path		string							true	"ID of the team"	default(d5d98eb3-ee18-4f75-9364-244f67e23b54)
+//	@param		tokenID	path		string							true	"ID of the api token"	default(8e4f2b7c-5a0f-4f8a-9d3e-2b1c6a7d9e0f)
+//	@param		token	body		dto.APIToken					true	"Api token name, scopes and expiration time"
+//	@success	200		{object}	dto.APIToken					"Api token updated successfully"
+//	@failure	400		{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	403		{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure	404		{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	422		{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500		{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/user/tokens/{tokenID} [put]
+//	@router		/teams/{teamID}/tokens/{tokenID} [put]
+func updateAPIToken(writer http.ResponseWriter, request *http.Request) {
+	token := &dto.APIToken{}
+	if err := render.Bind(request, token); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint:errcheck
+		return
+	}
+
+	existing := request.Context().Value(apiTokenKey).(moira.APIToken)
+	userLogin := middleware.GetLogin(request)
+	auth := middleware.GetAuth(request)
+	updated, err := controller.UpdateAPIToken(database, auth, existing, token, userLogin)
+	if err != nil {
+		render.Render(writer, request, err) //nolint:errcheck
+		return
+	}
+	recordAudit(request, moira.AuditActionUpdate, moira.AuditObjectAPIToken, existing.ID, dto.NewAPIToken(existing, time.Now()), updated)
+
+	if err := render.Render(writer, request, updated); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
+		return
+	}
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Delete api token
+//	@id			remove-api-token
+//	@tags		user
+//	@param		teamID	path	string	true	"ID of the team"	default(d5d98eb3-ee18-4f75-9364-244f67e23b54)
+//	@param		tokenID	path	string	true	"ID of the api token"	default(8e4f2b7c-5a0f-4f8a-9d3e-2b1c6a7d9e0f)
+//	@success	200		"Api token was deleted"
+//	@failure	403		{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure	404		{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	500		{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/user/tokens/{tokenID} [delete]
+//	@router		/teams/{teamID}/tokens/{tokenID} [delete]
+func removeAPIToken(writer http.ResponseWriter, request *http.Request) {
+	token := request.Context().Value(apiTokenKey).(moira.APIToken)
+	if err := controller.RemoveAPIToken(database, token.ID); err != nil {
+		render.Render(writer, request, err) //nolint:errcheck
+		return
+	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectAPIToken, token.ID, dto.NewAPIToken(token, time.Now()), nil)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/go-graphite/carbonapi/date"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+const defaultAuditRecordsLimit = 100
+
+func audit(router chi.Router) {
+	router.Use(middleware.AdminOnlyMiddleware())
+	router.With(readScope, middleware.DateRange("-1week", "now")).Get("/", getAuditRecords)
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get audit log of configuration changes
+//	@id			get-audit-records
+//	@tags		audit
+//	@produce	json
+//	@param		from		query		string							false	"Start time of the time range"	default(-1week)
+//	@param		to			query		string							false	"End time of the time range"	default(now)
+//	@param		actor		query		string							false	"Login of user who made the change"
+//	@param		action		query		string							false	"Kind of change"	Enums(create, update, delete)
+//	@param		object_type	query		string							false	"Type of changed object"	example(contact)
+//	@param		object_id	query		string							false	"ID of changed object"
+//	@param		limit		query		integer							false	"Max count of records"	default(100)
+//	@success	200			{object}	dto.AuditRecordsList			"Audit records, the newest records go first"
+//	@failure	400			{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	403			{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure	422			{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500			{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/audit [get]
+func getAuditRecords(writer http.ResponseWriter, request *http.Request) {
+	filter, errResponse := getAuditRecordsFilter(request)
+	if errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint
+		return
+	}
+
+	records, errResponse := controller.GetAuditRecords(database, filter)
+	if errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint
+		return
+	}
+
+	if err := render.Render(writer, request, records); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+		return
+	}
+}
+
+func getAuditRecordsFilter(request *http.Request) (moira.AuditRecordsFilter, *api.ErrorResponse) {
+	fromStr := middleware.GetFromStr(request)
+	from := date.DateParamToEpoch(fromStr, "UTC", 0, time.UTC)
+	if from == 0 {
+		return moira.AuditRecordsFilter{}, api.ErrorInvalidRequest(fmt.Errorf("can not parse from: %s", fromStr))
+	}
+
+	toStr := middleware.GetToStr(request)
+	to := date.DateParamToEpoch(toStr, "UTC", 0, time.UTC)
+	if to == 0 {
+		return moira.AuditRecordsFilter{}, api.ErrorInvalidRequest(fmt.Errorf("can not parse to: %s", toStr))
+	}
+
+	urlValues := request.URL.Query()
+	filter := moira.AuditRecordsFilter{
+		Actor:      urlValues.Get("actor"),
+		Action:     moira.AuditAction(urlValues.Get("action")),
+		ObjectType: moira.AuditObjectType(urlValues.Get("object_type")),
+		ObjectID:   urlValues.Get("object_id"),
+		From:       from,
+		To:         to,
+		Limit:      defaultAuditRecordsLimit,
+	}
+
+	if limitStr := urlValues.Get("limit"); limitStr != "" {
+		limit, err := strconv.ParseInt(limitStr, 10, 64)
+		if err != nil || limit <= 0 {
+			return moira.AuditRecordsFilter{}, api.ErrorInvalidRequest(fmt.Errorf("invalid limit: %s", limitStr))
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
+
+// recordAudit saves the change of object made by user from request.
+// Before and after are states of object, nil is used when object didn't exist.
+func recordAudit(request *http.Request, action moira.AuditAction, objectType moira.AuditObjectType, objectID string, before, after interface{}) {
+	tokenID := ""
+	if token := middleware.GetAPIToken(request); token != nil {
+		tokenID = token.ID
+	}
+	auditRecorder.Record(middleware.GetLogin(request), tokenID, action, objectType, objectID, before, after)
+}
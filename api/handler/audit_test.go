@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/middleware"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetAuditRecords(t *testing.T) {
+	Convey("Test get audit records", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		database = mockDb
+
+		newRequest := func(query string) *http.Request {
+			testRequest := httptest.NewRequest(http.MethodGet, "/audit"+query, nil)
+			ctx := middleware.SetContextValueForTest(testRequest.Context(), "from", "1675166400")
+			ctx = middleware.SetContextValueForTest(ctx, "to", "1675170000")
+			return testRequest.WithContext(ctx)
+		}
+
+		Convey("Filter is built from query", func() {
+			mockDb.EXPECT().GetAuditRecords(moira.AuditRecordsFilter{
+				Actor:      "user",
+				Action:     moira.AuditActionDelete,
+				ObjectType: moira.AuditObjectContact,
+				ObjectID:   "contact",
+				From:       1675166400,
+				To:         1675170000,
+				Limit:      10,
+			}).Return([]*moira.AuditRecord{}, nil)
+
+			responseWriter := httptest.NewRecorder()
+			getAuditRecords(responseWriter, newRequest("?actor=user&action=delete&object_type=contact&object_id=contact&limit=10"))
+			So(responseWriter.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Default limit is used", func() {
+			mockDb.EXPECT().GetAuditRecords(moira.AuditRecordsFilter{
+				From:  1675166400,
+				To:    1675170000,
+				Limit: defaultAuditRecordsLimit,
+			}).Return([]*moira.AuditRecord{}, nil)
+
+			responseWriter := httptest.NewRecorder()
+			getAuditRecords(responseWriter, newRequest(""))
+			So(responseWriter.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Invalid limit", func() {
+			responseWriter := httptest.NewRecorder()
+			getAuditRecords(responseWriter, newRequest("?limit=-1"))
+			So(responseWriter.Code, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/render"
+
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/middleware"
+	"github.com/moira-alert/moira/chatops"
+)
+
+// maxSlackCommandSize limits the size of slash command request body.
+const maxSlackCommandSize = 1 << 16
+
+// nolint: gofmt,goimports
+//
+//	@summary	Execute Slack slash command
+//	@description	Requests are sent by Slack and authenticated by signature of Slack app, Slack users are mapped to moira logins by api config.
+//	@id			execute-slack-command
+//	@tags		chatops
+//	@accept		x-www-form-urlencoded
+//	@produce	json
+//	@param		text		formData	string	false	"Command with arguments without leading slash"	default(status backend)
+//	@param		user_id		formData	string	true	"ID of Slack user who sent command"				default(U012ABCDEF)
+//	@success	200	{object}	chatops.SlackResponse			"Command executed"
+//	@failure	400	{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	401	{object}	api.ErrorUnauthorizedExample	"Request is not signed by Slack"
+//	@router		/chatops/slack [post]
+func executeSlackCommand(config api.SlackChatOps) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(request.Body, maxSlackCommandSize))
+		if err != nil {
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("failed to read request: %w", err))) //nolint:errcheck
+			return
+		}
+		if err = chatops.VerifySlackRequest(config.SigningSecret, request.Header, body, time.Now()); err != nil {
+			render.Render(writer, request, api.ErrorUnauthorized(err.Error())) //nolint:errcheck
+			return
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("failed to parse request: %w", err))) //nolint:errcheck
+			return
+		}
+
+		response, errorResponse := controller.ExecuteChatOpsCommand(config.Commands, chatops.ParseSlackCommand(form))
+		if errorResponse != nil {
+			// Slack shows only successful responses to user, so failure is reported in text.
+			middleware.GetLoggerEntry(request).Error().
+				Error(errorResponse.Err).
+				Msg("Failed to execute Slack command")
+			response = chatops.Response{Text: "Failed to execute command, please try again later."}
+		}
+
+		render.JSON(writer, request, chatops.NewSlackResponse(response))
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/chatops"
+	"github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExecuteSlackCommand(t *testing.T) {
+	Convey("Execute Slack slash command", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		logger, _ := zerolog_adapter.GetLogger("Test")
+		signingSecret := "secret"
+		config := &api.Config{
+			Authorization: api.Authorization{Enabled: true},
+			SlackChatOps: api.SlackChatOps{
+				Enabled:       true,
+				SigningSecret: signingSecret,
+				Commands:      chatops.NewCommands(chatops.Config{Users: map[string]string{"U123": "user"}}, mockDb, nil, nil, logger, nil),
+			},
+		}
+		handler := NewHandler(mockDb, logger, nil, config, nil, &api.WebConfig{}, nil)
+		responseWriter := httptest.NewRecorder()
+		body := "text=help&user_id=U123"
+
+		Convey("With valid signature and without authentication", func() {
+			testRequest := httptest.NewRequest(http.MethodPost, "/api/chatops/slack", strings.NewReader(body))
+			timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+			mac := hmac.New(sha256.New, []byte(signingSecret))
+			mac.Write([]byte("v0:" + timestamp + ":" + body))
+			testRequest.Header.Set("X-Slack-Request-Timestamp", timestamp)
+			testRequest.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+
+			handler.ServeHTTP(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+			var slackResponse chatops.SlackResponse
+			So(json.NewDecoder(response.Body).Decode(&slackResponse), ShouldBeNil)
+			So(slackResponse.ResponseType, ShouldEqual, "ephemeral")
+			So(slackResponse.Text, ShouldStartWith, "Available commands:")
+		})
+
+		Convey("Without signature", func() {
+			testRequest := httptest.NewRequest(http.MethodPost, "/api/chatops/slack", strings.NewReader(body))
+
+			handler.ServeHTTP(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusUnauthorized)
+		})
+	})
+}
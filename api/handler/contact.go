@@ -15,15 +15,18 @@ import (
 )
 
 func contact(router chi.Router) {
-	router.With(middleware.AdminOnlyMiddleware()).Get("/", getAllContacts)
+	router.With(middleware.AdminOnlyMiddleware(), readScope).Get("/", getAllContacts)
 	router.Put("/", createNewContact)
+	router.Post("/template/validate", validateContactMessageTemplate)
+	router.Post("/template/preview", previewContactMessageTemplate)
 	router.Route("/{contactId}", func(router chi.Router) {
 		router.Use(middleware.ContactContext)
 		router.Use(contactFilter)
-		router.Get("/", getContactById)
+		router.With(readScope).Get("/", getContactById)
 		router.Put("/", updateContact)
 		router.Delete("/", removeContact)
 		router.Post("/test", sendTestContactNotification)
+		router.Put("/do-not-disturb", setContactDoNotDisturb)
 	})
 }
 
@@ -106,6 +109,7 @@ func createNewContact(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, err) //nolint
 		return
 	}
+	recordAudit(request, moira.AuditActionCreate, moira.AuditObjectContact, contact.ID, nil, contact)
 
 	if err := render.Render(writer, request, contact); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) //nolint
@@ -113,7 +117,63 @@ func createNewContact(writer http.ResponseWriter, request *http.Request) {
 	}
 }
 
+// nolint: gofmt,goimports
+//
+//	@summary	Validates message template of contact
+//	@id			validate-contact-message-template
+//	@tags		contact
+//	@accept		json
+//	@produce	json
+//	@param		template	body		dto.MessageTemplateRequest		true	"Message template"
+//	@success	200			{object}	dto.MessageTemplateValidation	"Template validation result"
+//	@failure	400			{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	422			{object}	api.ErrorRenderExample			"Render error"
+//	@router		/contact/template/validate [post]
+func validateContactMessageTemplate(writer http.ResponseWriter, request *http.Request) {
+	templateRequest := &dto.MessageTemplateRequest{}
+	if err := render.Bind(request, templateRequest); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
+		return
+	}
+
+	validation := controller.ValidateContactMessageTemplate(templateRequest)
+	if err := render.Render(writer, request, validation); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+	}
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Populates message template of contact with sample notification data
+//	@id			preview-contact-message-template
+//	@tags		contact
+//	@accept		json
+//	@produce	json
+//	@param		template	body		dto.MessageTemplateRequest		true	"Message template"
+//	@success	200			{object}	dto.MessageTemplatePreview		"Populated message"
+//	@failure	400			{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	422			{object}	api.ErrorRenderExample			"Render error"
+//	@router		/contact/template/preview [post]
+func previewContactMessageTemplate(writer http.ResponseWriter, request *http.Request) {
+	templateRequest := &dto.MessageTemplateRequest{}
+	if err := render.Bind(request, templateRequest); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
+		return
+	}
+
+	preview, apiErr := controller.PreviewContactMessageTemplate(templateRequest)
+	if apiErr != nil {
+		render.Render(writer, request, apiErr) //nolint
+		return
+	}
+
+	if err := render.Render(writer, request, preview); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+	}
+}
+
 // contactFilter is middleware for check contact existence and user permissions.
+// Contacts of team can be changed only by editors of the team.
 func contactFilter(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		contactID := middleware.GetContactID(request)
@@ -124,6 +184,12 @@ func contactFilter(next http.Handler) http.Handler {
 			render.Render(writer, request, err) //nolint
 			return
 		}
+		if contactData.Team != "" && !isReadRequest(request) {
+			if err = controller.CheckUserPermissionsForTeam(database, contactData.Team, userLogin, auth, moira.TeamRoleEditor); err != nil {
+				render.Render(writer, request, err) //nolint
+				return
+			}
+		}
 		ctx := context.WithValue(request.Context(), contactKey, contactData)
 		next.ServeHTTP(writer, request.WithContext(ctx))
 	})
@@ -161,6 +227,7 @@ func updateContact(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, err) //nolint
 		return
 	}
+	recordAudit(request, moira.AuditActionUpdate, moira.AuditObjectContact, contactData.ID, dto.NewContactData(contactData), contactDTO)
 
 	if err := render.Render(writer, request, &contactDTO); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) //nolint
@@ -186,7 +253,9 @@ func removeContact(writer http.ResponseWriter, request *http.Request) {
 	err := controller.RemoveContact(database, contactData.ID, contactData.User, contactData.Team)
 	if err != nil {
 		render.Render(writer, request, err) //nolint
+		return
 	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectContact, contactData.ID, dto.NewContactData(contactData), nil)
 }
 
 // nolint: gofmt,goimports
@@ -209,3 +278,38 @@ func sendTestContactNotification(writer http.ResponseWriter, request *http.Reque
 		render.Render(writer, request, err) //nolint
 	}
 }
+
+// nolint: gofmt,goimports
+//
+//	@summary	Turns Do Not Disturb of contact on or off, non-critical notifications are delayed or redirected to backup contact while it is on
+//	@id			set-contact-do-not-disturb
+//	@accept		json
+//	@produce	json
+//	@param		contactID	path		string							true	"ID of the contact"	default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
+//	@param		dnd			body		dto.ContactDoNotDisturb			true	"Do Not Disturb settings"
+//	@success	200			{object}	dto.ContactDoNotDisturb			"Do Not Disturb is set"
+//	@failure	400			{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	403			{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure	404			{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	422			{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500			{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/contact/{contactID}/do-not-disturb [put]
+//	@tags		contact
+func setContactDoNotDisturb(writer http.ResponseWriter, request *http.Request) {
+	dnd := &dto.ContactDoNotDisturb{}
+	if err := render.Bind(request, dnd); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
+		return
+	}
+
+	contactData := request.Context().Value(contactKey).(moira.ContactData)
+	if err := controller.SetContactDoNotDisturb(database, contactData, dnd); err != nil {
+		render.Render(writer, request, err) //nolint
+		return
+	}
+	recordAudit(request, moira.AuditActionUpdate, moira.AuditObjectContact, contactData.ID, contactData.Availability, dnd)
+
+	if err := render.Render(writer, request, dnd); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+	}
+}
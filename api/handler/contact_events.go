@@ -20,7 +20,7 @@ func contactEvents(router chi.Router) {
 	router.Route("/{contactId}/events", func(router chi.Router) {
 		router.Use(middleware.ContactContext)
 		router.Use(contactFilter)
-		router.With(middleware.DateRange("-3hour", "now")).Get("/", getContactByIdWithEvents)
+		router.With(readScope, middleware.DateRange("-3hour", "now")).Get("/", getContactByIdWithEvents)
 	})
 }
 
@@ -52,13 +52,15 @@ func TestGetAllContacts(t *testing.T) {
 			database = mockDb
 
 			expected := &dto.ContactList{
-				List: []*moira.ContactData{
+				List: []*dto.ContactData{
 					{
-						ID:    defaultContact,
-						Type:  "mail",
-						Value: "moira@skbkontur.ru",
-						User:  defaultLogin,
-						Team:  "",
+						ContactData: moira.ContactData{
+							ID:    defaultContact,
+							Type:  "mail",
+							Value: "moira@skbkontur.ru",
+							User:  defaultLogin,
+							Team:  "",
+						},
 					},
 				},
 			}
@@ -383,6 +385,117 @@ func TestCreateNewContact(t *testing.T) {
 			So(actual, ShouldResemble, expected)
 			So(response.StatusCode, ShouldEqual, http.StatusInternalServerError)
 		})
+
+		Convey("Trying to create a contact with invalid message template", func() {
+			newContactDto.MessageTemplate = "{{ .Trigger.Name "
+			defer func() {
+				newContactDto.MessageTemplate = ""
+			}()
+
+			jsonContact, err := json.Marshal(newContactDto)
+			So(err, ShouldBeNil)
+
+			testRequest := httptest.NewRequest(http.MethodPut, "/contact", bytes.NewBuffer(jsonContact))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), LoginKey, login))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "auth", auth))
+			testRequest.Header.Add("content-type", "application/json")
+
+			createNewContact(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			contentBytes, err := io.ReadAll(response.Body)
+			So(err, ShouldBeNil)
+			actual := &api.ErrorResponse{}
+			err = json.Unmarshal(contentBytes, actual)
+			So(err, ShouldBeNil)
+
+			So(actual.ErrorText, ShouldStartWith, "invalid message template")
+			So(response.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
+
+func TestValidateContactMessageTemplate(t *testing.T) {
+	Convey("Test validate contact message template", t, func() {
+		responseWriter := httptest.NewRecorder()
+
+		Convey("Valid template", func() {
+			testRequest := httptest.NewRequest(http.MethodPost, "/contact/template/validate", bytes.NewBufferString(`{"template": "{{ .Trigger.Name }}"}`))
+			testRequest.Header.Add("content-type", "application/json")
+
+			validateContactMessageTemplate(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			actual := &dto.MessageTemplateValidation{}
+			err := json.NewDecoder(response.Body).Decode(actual)
+			So(err, ShouldBeNil)
+
+			So(actual, ShouldResemble, &dto.MessageTemplateValidation{Valid: true})
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Invalid template", func() {
+			testRequest := httptest.NewRequest(http.MethodPost, "/contact/template/validate", bytes.NewBufferString(`{"template": "{{ .Unknown }}"}`))
+			testRequest.Header.Add("content-type", "application/json")
+
+			validateContactMessageTemplate(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			actual := &dto.MessageTemplateValidation{}
+			err := json.NewDecoder(response.Body).Decode(actual)
+			So(err, ShouldBeNil)
+
+			So(actual.Valid, ShouldBeFalse)
+			So(actual.Error, ShouldNotBeEmpty)
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Empty template", func() {
+			testRequest := httptest.NewRequest(http.MethodPost, "/contact/template/validate", bytes.NewBufferString(`{}`))
+			testRequest.Header.Add("content-type", "application/json")
+
+			validateContactMessageTemplate(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
+
+func TestPreviewContactMessageTemplate(t *testing.T) {
+	Convey("Test preview contact message template", t, func() {
+		responseWriter := httptest.NewRecorder()
+
+		Convey("Template is populated with sample data", func() {
+			testRequest := httptest.NewRequest(http.MethodPost, "/contact/template/preview", bytes.NewBufferString(`{"template": "{{ .State }} {{ .Trigger.Name }}"}`))
+			testRequest.Header.Add("content-type", "application/json")
+
+			previewContactMessageTemplate(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			actual := &dto.MessageTemplatePreview{}
+			err := json.NewDecoder(response.Body).Decode(actual)
+			So(err, ShouldBeNil)
+
+			So(actual, ShouldResemble, &dto.MessageTemplatePreview{Message: "ERROR Not enough disk space left"})
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Template that can't be populated", func() {
+			testRequest := httptest.NewRequest(http.MethodPost, "/contact/template/preview", bytes.NewBufferString(`{"template": "{{ .Unknown }}"}`))
+			testRequest.Header.Add("content-type", "application/json")
+
+			previewContactMessageTemplate(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
 	})
 }
 
@@ -834,3 +947,52 @@ func TestSendTestContactNotification(t *testing.T) {
 		})
 	})
 }
+
+func TestSetContactDoNotDisturb(t *testing.T) {
+	Convey("Test set contact Do Not Disturb", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		responseWriter := httptest.NewRecorder()
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		database = mockDb
+
+		contact := moira.ContactData{
+			ID:    defaultContact,
+			Type:  "mail",
+			Value: "moira@skbkontur.ru",
+			User:  defaultLogin,
+		}
+
+		Convey("Successfully turned on", func() {
+			expected := contact
+			expected.Availability = &moira.ContactAvailability{DoNotDisturb: true}
+			mockDb.EXPECT().SaveContact(&expected).Return(nil).Times(1)
+
+			testRequest := httptest.NewRequest(http.MethodPut, "/contact/"+defaultContact+"/do-not-disturb", bytes.NewBufferString(`{"enabled":true}`))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), ContactKey, contact))
+			testRequest.Header.Add("content-type", "application/json")
+
+			setContactDoNotDisturb(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			contentBytes, err := io.ReadAll(response.Body)
+			So(err, ShouldBeNil)
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+			So(string(contentBytes), ShouldEqual, "{\"enabled\":true}\n")
+		})
+
+		Convey("End in the past is invalid", func() {
+			testRequest := httptest.NewRequest(http.MethodPut, "/contact/"+defaultContact+"/do-not-disturb", bytes.NewBufferString(`{"enabled":true,"until":1590741878}`))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), ContactKey, contact))
+			testRequest.Header.Add("content-type", "application/json")
+
+			setContactDoNotDisturb(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/go-graphite/carbonapi/date"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+const defaultDeliveryAttemptsLimit = 100
+
+func delivery(router chi.Router) {
+	router.Use(middleware.AdminOnlyMiddleware())
+	router.With(readScope, middleware.DateRange("-1day", "now")).Get("/", getDeliveryAttempts)
+}
+
+func contactDeliveries(router chi.Router) {
+	router.Route("/{contactId}/deliveries", func(router chi.Router) {
+		router.Use(middleware.ContactContext)
+		router.Use(contactFilter)
+		router.With(readScope, middleware.DateRange("-1day", "now")).Get("/", getContactDeliveryAttempts)
+	})
+}
+
+func deadLetter(router chi.Router) {
+	router.Use(middleware.AdminOnlyMiddleware())
+	router.With(readScope, middleware.DateRange("-1week", "now")).Get("/", getDeadLetters)
+	router.Route("/{deadLetterId}", func(router chi.Router) {
+		router.Use(middleware.DeadLetterIDContext)
+		router.Use(deadLetterContext)
+		router.With(readScope).Get("/", getDeadLetter)
+		router.Delete("/", removeDeadLetter)
+		router.Post("/replay", replayDeadLetter)
+	})
+}
+
+// deadLetterContext is middleware that gets dead letter by id from the request and sets it to request context.
+func deadLetterContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		letter, err := controller.GetDeadLetter(database, middleware.GetDeadLetterID(request))
+		if err != nil {
+			render.Render(writer, request, err) //nolint:errcheck
+			return
+		}
+		ctx := context.WithValue(request.Context(), deadLetterKey, letter)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get log of notification delivery attempts
+//	@id			get-delivery-attempts
+//	@tags		delivery
+//	@produce	json
+//	@param		from		query		string							false	"Start time of the time range"	default(-1day)
+//	@param		to			query		string							false	"End time of the time range"	default(now)
+//	@param		contact_id	query		string							false	"ID of contact notifications were sent to"
+//	@param		trigger_id	query		string							false	"ID of trigger notifications were sent for"
+//	@param		status		query		string							false	"Result of delivery attempt"	Enums(ok, failed, dropped)
+//	@param		limit		query		integer							false	"Max count of attempts"			default(100)
+//	@success	200			{object}	dto.DeliveryAttemptsList		"Delivery attempts, the newest attempts go first"
+//	@failure	400			{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	403			{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure	422			{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500			{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/delivery [get]
+func getDeliveryAttempts(writer http.ResponseWriter, request *http.Request) {
+	filter, errResponse := getDeliveryAttemptsFilter(request)
+	if errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint:errcheck
+		return
+	}
+
+	renderDeliveryAttempts(writer, request, filter)
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get log of notification delivery attempts to contact
+//	@id			get-contact-delivery-attempts
+//	@tags		contact
+//	@produce	json
+//	@param		contactID	path		string							true	"Contact ID"					default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
+//	@param		from		query		string							false	"Start time of the time range"	default(-1day)
+//	@param		to			query		string							false	"End time of the time range"	default(now)
+//	@param		trigger_id	query		string							false	"ID of trigger notifications were sent for"
+//	@param		status		query		string							false	"Result of delivery attempt"	Enums(ok, failed, dropped)
+//	@param		limit		query		integer							false	"Max count of attempts"			default(100)
+//	@success	200			{object}	dto.DeliveryAttemptsList		"Delivery attempts, the newest attempts go first"
+//	@failure	400			{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	403			{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure	404			{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	422			{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500			{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/contact/{contactID}/deliveries [get]
+func getContactDeliveryAttempts(writer http.ResponseWriter, request *http.Request) {
+	filter, errResponse := getDeliveryAttemptsFilter(request)
+	if errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint:errcheck
+		return
+	}
+	filter.ContactID = request.Context().Value(contactKey).(moira.ContactData).ID
+
+	renderDeliveryAttempts(writer, request, filter)
+}
+
+func renderDeliveryAttempts(writer http.ResponseWriter, request *http.Request, filter moira.DeliveryAttemptsFilter) {
+	attempts, errResponse := controller.GetDeliveryAttempts(database, filter)
+	if errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint:errcheck
+		return
+	}
+
+	if err := render.Render(writer, request, attempts); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
+		return
+	}
+}
+
+func getDeliveryAttemptsFilter(request *http.Request) (moira.DeliveryAttemptsFilter, *api.ErrorResponse) {
+	from, to, errResponse := getDateRange(request)
+	if errResponse != nil {
+		return moira.DeliveryAttemptsFilter{}, errResponse
+	}
+
+	urlValues := request.URL.Query()
+	filter := moira.DeliveryAttemptsFilter{
+		ContactID: urlValues.Get("contact_id"),
+		TriggerID: urlValues.Get("trigger_id"),
+		Status:    moira.DeliveryStatus(urlValues.Get("status")),
+		From:      from,
+		To:        to,
+		Limit:     defaultDeliveryAttemptsLimit,
+	}
+
+	if limitStr := urlValues.Get("limit"); limitStr != "" {
+		limit, err := strconv.ParseInt(limitStr, 10, 64)
+		if err != nil || limit <= 0 {
+			return moira.DeliveryAttemptsFilter{}, api.ErrorInvalidRequest(fmt.Errorf("invalid limit: %s", limitStr))
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
+
+func getDateRange(request *http.Request) (from, to int64, errResponse *api.ErrorResponse) {
+	fromStr := middleware.GetFromStr(request)
+	from = date.DateParamToEpoch(fromStr, "UTC", 0, time.UTC)
+	if from == 0 {
+		return 0, 0, api.ErrorInvalidRequest(fmt.Errorf("can not parse from: %s", fromStr))
+	}
+
+	toStr := middleware.GetToStr(request)
+	to = date.DateParamToEpoch(toStr, "UTC", 0, time.UTC)
+	if to == 0 {
+		return 0, 0, api.ErrorInvalidRequest(fmt.Errorf("can not parse to: %s", toStr))
+	}
+
+	return from, to, nil
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get notifications notifier stopped trying to deliver
+//	@id			get-dead-letters
+//	@tags		delivery
+//	@produce	json
+//	@param		from	query		string							false	"Start time of the time range"	default(-1week)
+//	@param		to		query		string							false	"End time of the time range"	default(now)
+//	@success	200		{object}	dto.DeadLettersList				"Dead letters, the newest letters go first"
+//	@failure	400		{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	403		{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure	422		{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500		{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/dead-letter [get]
+func getDeadLetters(writer http.ResponseWriter, request *http.Request) {
+	from, to, errResponse := getDateRange(request)
+	if errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint:errcheck
+		return
+	}
+
+	letters, errResponse := controller.GetDeadLetters(database, from, to)
+	if errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint:errcheck
+		return
+	}
+
+	if err := render.Render(writer, request, letters); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
+		return
+	}
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get dead letter by ID
+//	@id			get-dead-letter
+//	@tags		delivery
+//	@produce	json
+//	@param		deadLetterID	path		string							true	"ID of dead letter"	default(5b1d4c2e-8f0a-4e3b-9c7d-6a2f1e0b3d4c)
+//	@success	200				{object}	dto.DeadLetter					"Dead letter fetched successfully"
+//	@failure	400				{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	403				{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure	404				{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	422				{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500				{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/dead-letter/{deadLetterID} [get]
+func getDeadLetter(writer http.ResponseWriter, request *http.Request) {
+	letter := request.Context().Value(deadLetterKey).(moira.DeadLetter)
+	response := dto.NewDeadLetter(letter)
+	if err := render.Render(writer, request, &response); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
+		return
+	}
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Delete dead letter without sending its notifications
+//	@id			remove-dead-letter
+//	@tags		delivery
+//	@param		deadLetterID	path	string	true	"ID of dead letter"	default(5b1d4c2e-8f0a-4e3b-9c7d-6a2f1e0b3d4c)
+//	@success	200	"Dead letter was deleted"
+//	@failure	400	{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	403	{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure	404	{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	500	{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/dead-letter/{deadLetterID} [delete]
+func removeDeadLetter(writer http.ResponseWriter, request *http.Request) {
+	letter := request.Context().Value(deadLetterKey).(moira.DeadLetter)
+	if err := controller.RemoveDeadLetter(database, letter.ID); err != nil {
+		render.Render(writer, request, err) //nolint:errcheck
+		return
+	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectDeadLetter, letter.ID, dto.NewDeadLetter(letter), nil)
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Send notifications of dead letter again
+//	@description	Notifications are scheduled to be sent to the original contact or to the contact from request body, then the letter is deleted
+//	@id			replay-dead-letter
+//	@tags		delivery
+//	@accept		json
+//	@produce	json
+//	@param		deadLetterID	path		string							true	"ID of dead letter"	default(5b1d4c2e-8f0a-4e3b-9c7d-6a2f1e0b3d4c)
+//	@param		request			body		dto.DeadLetterReplayRequest		false	"Contact to send notifications to"
+//	@success	200				{object}	dto.DeadLetterReplay			"Notifications were scheduled"
+//	@failure	400				{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	403				{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure	404				{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	422				{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500				{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/dead-letter/{deadLetterID}/replay [post]
+func replayDeadLetter(writer http.ResponseWriter, request *http.Request) {
+	replayRequest := &dto.DeadLetterReplayRequest{}
+	if request.ContentLength != 0 {
+		if err := render.Bind(request, replayRequest); err != nil {
+			render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint:errcheck
+			return
+		}
+	}
+
+	letter := request.Context().Value(deadLetterKey).(moira.DeadLetter)
+	replay, errResponse := controller.ReplayDeadLetter(database, letter, replayRequest.ContactID)
+	if errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint:errcheck
+		return
+	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectDeadLetter, letter.ID, dto.NewDeadLetter(letter), replay)
+
+	if err := render.Render(writer, request, replay); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
+		return
+	}
+}
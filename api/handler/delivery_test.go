@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/middleware"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetDeliveryAttempts(t *testing.T) {
+	Convey("Test get delivery attempts", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		database = mockDb
+
+		newRequest := func(query string) *http.Request {
+			testRequest := httptest.NewRequest(http.MethodGet, "/delivery"+query, nil)
+			ctx := middleware.SetContextValueForTest(testRequest.Context(), "from", "1675166400")
+			ctx = middleware.SetContextValueForTest(ctx, "to", "1675170000")
+			return testRequest.WithContext(ctx)
+		}
+
+		Convey("Filter is built from query", func() {
+			mockDb.EXPECT().GetDeliveryAttempts(moira.DeliveryAttemptsFilter{
+				ContactID: "contact",
+				TriggerID: "trigger",
+				Status:    moira.DeliveryStatusDropped,
+				From:      1675166400,
+				To:        1675170000,
+				Limit:     10,
+			}).Return([]*moira.DeliveryAttempt{}, nil)
+
+			responseWriter := httptest.NewRecorder()
+			getDeliveryAttempts(responseWriter, newRequest("?contact_id=contact&trigger_id=trigger&status=dropped&limit=10"))
+			So(responseWriter.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Contact attempts are filtered by contact from path", func() {
+			mockDb.EXPECT().GetDeliveryAttempts(moira.DeliveryAttemptsFilter{
+				ContactID: "owned",
+				From:      1675166400,
+				To:        1675170000,
+				Limit:     defaultDeliveryAttemptsLimit,
+			}).Return([]*moira.DeliveryAttempt{}, nil)
+
+			testRequest := newRequest("?contact_id=other")
+			testRequest = testRequest.WithContext(context.WithValue(testRequest.Context(), contactKey, moira.ContactData{ID: "owned"}))
+			responseWriter := httptest.NewRecorder()
+			getContactDeliveryAttempts(responseWriter, testRequest)
+			So(responseWriter.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Invalid limit", func() {
+			responseWriter := httptest.NewRecorder()
+			getDeliveryAttempts(responseWriter, newRequest("?limit=0"))
+			So(responseWriter.Code, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
+
+func TestReplayDeadLetter(t *testing.T) {
+	Convey("Test replay dead letter", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		database = mockDb
+
+		letter := moira.DeadLetter{
+			ID:      "letter",
+			Events:  []moira.NotificationEvent{{Metric: "metric"}},
+			Contact: moira.ContactData{ID: "original"},
+		}
+		newRequest := func(body string) *http.Request {
+			testRequest := httptest.NewRequest(http.MethodPost, "/dead-letter/letter/replay", strings.NewReader(body))
+			testRequest.Header.Add("content-type", "application/json")
+			return testRequest.WithContext(context.WithValue(testRequest.Context(), deadLetterKey, letter))
+		}
+
+		Convey("Without body letter is replayed to the original contact", func() {
+			mockDb.EXPECT().GetContact("original").Return(moira.ContactData{ID: "original"}, nil)
+			mockDb.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).Return(nil)
+			mockDb.EXPECT().RemoveDeadLetter("letter").Return(nil)
+
+			responseWriter := httptest.NewRecorder()
+			replayDeadLetter(responseWriter, newRequest(""))
+			So(responseWriter.Code, ShouldEqual, http.StatusOK)
+			So(responseWriter.Body.String(), ShouldContainSubstring, `"contact_id":"original"`)
+		})
+
+		Convey("Letter is replayed to contact from body", func() {
+			mockDb.EXPECT().GetContact("other").Return(moira.ContactData{ID: "other"}, nil)
+			mockDb.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).Return(nil)
+			mockDb.EXPECT().RemoveDeadLetter("letter").Return(nil)
+
+			responseWriter := httptest.NewRecorder()
+			replayDeadLetter(responseWriter, newRequest(`{"contact_id":"other"}`))
+			So(responseWriter.Code, ShouldEqual, http.StatusOK)
+			So(responseWriter.Body.String(), ShouldContainSubstring, `"contact_id":"other"`)
+		})
+	})
+}
+
+func TestGetDeadLetter(t *testing.T) {
+	Convey("Test get dead letter", t, func() {
+		letter := moira.DeadLetter{
+			ID:      "letter",
+			Contact: moira.ContactData{ID: "contact", Type: "webhook", SigningSecret: "secret"},
+		}
+		testRequest := httptest.NewRequest(http.MethodGet, "/dead-letter/letter", nil)
+		testRequest = testRequest.WithContext(context.WithValue(testRequest.Context(), deadLetterKey, letter))
+
+		Convey("Signing secret of contact is not rendered", func() {
+			responseWriter := httptest.NewRecorder()
+			getDeadLetter(responseWriter, testRequest)
+			So(responseWriter.Code, ShouldEqual, http.StatusOK)
+			So(responseWriter.Body.String(), ShouldNotContainSubstring, `"signing_secret"`)
+			So(responseWriter.Body.String(), ShouldNotContainSubstring, `:"secret"`)
+			So(responseWriter.Body.String(), ShouldContainSubstring, `"has_signing_secret":true`)
+		})
+	})
+}
@@ -5,13 +5,14 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/controller"
 	"github.com/moira-alert/moira/api/middleware"
 )
 
 func event(router chi.Router) {
-	router.With(middleware.TriggerContext, middleware.Paginate(0, 100)).Get("/{triggerId}", getEventsList)
+	router.With(triggersReadScope, middleware.TriggerContext, middleware.Paginate(0, 100)).Get("/{triggerId}", getEventsList)
 	router.With(middleware.AdminOnlyMiddleware()).Delete("/all", deleteAllEvents)
 }
 
@@ -56,5 +57,7 @@ func getEventsList(writer http.ResponseWriter, request *http.Request) {
 func deleteAllEvents(writer http.ResponseWriter, request *http.Request) {
 	if errorResponse := controller.DeleteAllEvents(database); errorResponse != nil {
 		render.Render(writer, request, errorResponse) //nolint
+		return
 	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectEvents, "", nil, nil)
 }
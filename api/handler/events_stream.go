@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+const eventsStreamPollInterval = time.Second
+
+var eventsStreamHeartbeatInterval = 15 * time.Second
+
+// eventsStreamSource reads events stream once for all clients.
+var eventsStreamSource *controller.EventsStream
+
+// eventsStreamRetry is a reconnection delay in milliseconds suggested to events stream clients.
+const eventsStreamRetry = 3000
+
+func eventsStream(router chi.Router) {
+	router.With(teamFilterReadScope).Get("/stream", streamEvents)
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary		Stream trigger and metric state changes
+//	@description	Server-Sent Events stream of notification events as they are created by checker.
+//	@description	Each event has type `trigger` or `metric`, its data is a notification event in JSON and its id is a cursor.
+//	@description	Send the id in `Last-Event-ID` header or `lastEventId` query parameter to continue reading stream after reconnection.
+//	@description	For example, `/api/events/stream?tags[0]=db&team=d5d98eb3-ee18-4f75-9364-244f67e23b54`
+//	@description	Events of the team are streamed only to its members and to api tokens of the team.
+//	@id				stream-events
+//	@tags			event
+//	@produce		text/event-stream
+//	@param			Last-Event-ID	header		string							false	"ID of the last received event"
+//	@param			lastEventId		query		string							false	"ID of the last received event"	default(1590741878000-0)
+//	@param			tags			query		[]string						false	"Tags which triggers of events must have"
+//	@param			team			query		string							false	"ID of the team which triggers of events must belong to"
+//	@param			triggers		query		[]string						false	"IDs of triggers of events"
+//	@success		200				{object}	moira.NotificationEvent			"Stream of events"
+//	@failure		400				{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure		403				{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure		404				{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure		500				{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router			/events/stream [get]
+func streamEvents(writer http.ResponseWriter, request *http.Request) {
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		render.Render(writer, request, api.ErrorInternalServer(fmt.Errorf("streaming is not supported"))) //nolint
+		return
+	}
+
+	filter := dto.EventsStreamFilter{
+		Tags:       getRequestTags(request),
+		TeamID:     request.FormValue("team"),
+		TriggerIDs: getRequestList(request, "triggers"),
+	}
+	lastEventID := request.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = request.FormValue("lastEventId")
+	}
+
+	if filter.TeamID != "" {
+		if errorResponse := checkTeamPermissions(request, filter.TeamID, moira.TeamRoleViewer); errorResponse != nil {
+			render.Render(writer, request, errorResponse) //nolint
+			return
+		}
+	}
+
+	subscription, errorResponse := eventsStreamSource.Subscribe(filter, lastEventID)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+	defer eventsStreamSource.Unsubscribe(subscription)
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.Header().Set("Connection", "keep-alive")
+	writer.Header().Set("X-Accel-Buffering", "no")
+	writer.WriteHeader(http.StatusOK)
+	fmt.Fprintf(writer, "retry: %d\n\n", eventsStreamRetry)
+	if err := writeStreamEvents(writer, subscription.Backlog); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventsStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-request.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(writer, ": heartbeat\n\n")
+			flusher.Flush()
+		case events, ok := <-subscription.Events():
+			if !ok {
+				middleware.GetLoggerEntry(request).Warning().
+					Error(subscription.Err()).
+					Msg("Events stream subscription is closed")
+				return
+			}
+			if err := writeStreamEvents(writer, events); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeStreamEvents(writer http.ResponseWriter, events []dto.EventsStreamEvent) error {
+	for _, event := range events {
+		data, err := json.Marshal(event.Event)
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintf(writer, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
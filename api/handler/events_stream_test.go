@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/controller"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStreamEvents(t *testing.T) {
+	Convey("Test stream events", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		database = mockDb
+		eventsStreamSource = controller.NewEventsStream(mockDb, time.Millisecond)
+
+		Convey("Events after last event id are sent", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			mockDb.EXPECT().GetNotificationEventsStreamLastID().Return("3-0", nil)
+			mockDb.EXPECT().GetNotificationEventsStream("1-0", gomock.Any()).Return([]moira.NotificationEventsStreamEntry{
+				{ID: "2-0", Event: moira.NotificationEvent{TriggerID: "trigger", Metric: "my.metric", State: moira.StateERROR, OldState: moira.StateOK}},
+				{ID: "3-0", Event: moira.NotificationEvent{TriggerID: "trigger", IsTriggerEvent: true, State: moira.StateOK, OldState: moira.StateERROR}},
+			}, "3-0", nil)
+			mockDb.EXPECT().GetNotificationEventsStream("3-0", gomock.Any()).DoAndReturn(func(string, int64) ([]moira.NotificationEventsStreamEntry, string, error) {
+				cancel()
+				return nil, "", nil
+			}).AnyTimes()
+
+			request := httptest.NewRequest(http.MethodGet, "/api/events/stream?triggers[0]=trigger", nil).WithContext(ctx)
+			request.Header.Set("Last-Event-ID", "1-0")
+			responseWriter := httptest.NewRecorder()
+
+			streamEvents(responseWriter, request)
+
+			So(responseWriter.Code, ShouldEqual, http.StatusOK)
+			So(responseWriter.Header().Get("Content-Type"), ShouldEqual, "text/event-stream")
+			So(responseWriter.Body.String(), ShouldEqual, "retry: 3000\n\n"+
+				"id: 2-0\nevent: metric\n"+
+				`data: {"timestamp":0,"metric":"my.metric","state":"ERROR","trigger_id":"trigger","old_state":"OK","event_message":null}`+"\n\n"+
+				"id: 3-0\nevent: trigger\n"+
+				`data: {"trigger_event":true,"timestamp":0,"metric":"","state":"OK","trigger_id":"trigger","old_state":"ERROR","event_message":null}`+"\n\n")
+		})
+
+		Convey("Invalid last event id", func() {
+			request := httptest.NewRequest(http.MethodGet, "/api/events/stream?lastEventId=last", nil)
+			responseWriter := httptest.NewRecorder()
+
+			streamEvents(responseWriter, request)
+
+			So(responseWriter.Code, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
@@ -12,19 +12,24 @@ import (
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
 	moiramiddle "github.com/moira-alert/moira/api/middleware"
+	moiraAudit "github.com/moira-alert/moira/audit"
 
 	_ "github.com/moira-alert/moira/docs" // docs is generated by Swag CLI, you have to import it.
 )
 
 var (
-	database    moira.Database
-	searchIndex moira.Searcher
+	database      moira.Database
+	searchIndex   moira.Searcher
+	auditRecorder *moiraAudit.Recorder
 )
 
 const (
 	contactKey      moiramiddle.ContextKey = "contact"
 	subscriptionKey moiramiddle.ContextKey = "subscription"
+	apiTokenKey     moiramiddle.ContextKey = "apiToken"
+	deadLetterKey   moiramiddle.ContextKey = "deadLetter"
 )
 
 // NewHandler creates new api handler request uris based on github.com/go-chi/chi.
@@ -35,9 +40,12 @@ func NewHandler(
 	apiConfig *api.Config,
 	metricSourceProvider *metricSource.SourceProvider,
 	webConfig *api.WebConfig,
+	recorder *moiraAudit.Recorder,
 ) http.Handler {
 	database = db
 	searchIndex = index
+	auditRecorder = recorder
+	eventsStreamSource = controller.NewEventsStream(db, eventsStreamPollInterval)
 	router := chi.NewRouter()
 	router.Use(render.SetContentType(render.ContentTypeJSON))
 	router.Use(moiramiddle.UserContext)
@@ -56,6 +64,15 @@ func NewHandler(
 	//	@license.name		MIT
 	//	@BasePath			/api
 	//
+	//	@tag.name			audit
+	//	@tag.description	View log of configuration changes made by users. Available for administrators only
+	//
+	//	@tag.name			delivery
+	//	@tag.description	View log of notification delivery attempts and replay undelivered notifications. Available for administrators only
+	//
+	//	@tag.name			chatops
+	//	@tag.description	Execute commands sent from chats, e.g. Slack slash commands
+	//
 	//	@tag.name			contact
 	//	@tag.description	APIs for working with Moira contacts. For more details, see <https://moira.readthedocs.io/en/latest/installation/webhooks_scripts.html#contact/>
 	//
@@ -65,6 +82,9 @@ func NewHandler(
 	//	@tag.name			event
 	//	@tag.description	APIs for interacting with notification events. See <https://moira.readthedocs.io/en/latest/user_guide/trigger_page.html#event-history/> for details
 	//
+	//	@tag.name			image
+	//	@tag.description	Get plot images attached to notifications by filesystem image store
+	//
 	//	@tag.name			health
 	//	@tag.description	interact with Moira states/health status. See <https://moira.readthedocs.io/en/latest/user_guide/selfstate.html#self-state-monitor/> for details
 	//
@@ -74,6 +94,9 @@ func NewHandler(
 	//	@tag.name			pattern
 	//	@tag.description	APIs for interacting with graphite patterns in Moira. See <https://moira.readthedocs.io/en/latest/development/architecture.html#pattern/>
 	//
+	//	@tag.name			stats
+	//	@tag.description	Alerting analytics: noisy triggers, recovery times and sent notifications
+	//
 	//	@tag.name			subscription
 	//	@tag.description	APIs for managing a user's subscription(s). See <https://moira.readthedocs.io/en/latest/development/architecture.html#subscription/> to learn about Moira subscriptions
 	//
@@ -94,13 +117,21 @@ func NewHandler(
 	//
 	//	@tag.name			user
 	//	@tag.description	APIs for interacting with Moira users
-	router.Route("/api", func(router chi.Router) {
+	if apiConfig.ImageServer != nil {
+		router.Get("/api/image/{imageId}", getImage(apiConfig.ImageServer))
+	}
+	if apiConfig.SlackChatOps.Enabled {
+		router.Post("/api/chatops/slack", executeSlackCommand(apiConfig.SlackChatOps))
+	}
+	router.Route("/api", func(apiRouter chi.Router) {
+		router := scopedRouter{apiRouter}
 		router.Use(moiramiddle.DatabaseContext(database))
 		router.Use(moiramiddle.AuthorizationContext(&apiConfig.Authorization))
+		router.Use(moiramiddle.Authentication)
 		router.Route("/health", health)
 		router.Route("/", func(router chi.Router) {
 			router.Use(moiramiddle.ReadOnlyMiddleware(apiConfig))
-			router.Get("/config", getWebConfig(webConfig))
+			router.With(readScope).Get("/config", getWebConfig(webConfig))
 			router.Route("/user", user)
 			router.With(moiramiddle.Triggers(
 				apiConfig.MetricsTTL,
@@ -108,14 +139,23 @@ func NewHandler(
 			router.Route("/tag", tag)
 			router.Route("/pattern", pattern)
 			router.Route("/event", event)
+			router.Route("/events", eventsStream)
 			router.Route("/subscription", subscription)
-			router.Route("/notification", notification)
+			router.Route("/notification", notification(metricSourceProvider, apiConfig.NotificationPreview))
 			router.Route("/teams", teams)
+			router.Route("/audit", audit)
+			router.Route("/delivery", delivery)
+			router.Route("/dead-letter", deadLetter)
+			router.Route("/stats", stats)
+			if apiConfig.PrometheusExporter.Enabled {
+				router.With(readScope).Get("/prometheus/metrics", getPrometheusMetrics(log, apiConfig.PrometheusExporter))
+			}
 			router.Route("/contact", func(router chi.Router) {
 				contact(router)
 				contactEvents(router)
+				contactDeliveries(router)
 			})
-			router.Get("/swagger/*", httpSwagger.Handler(
+			router.With(readScope).Get("/swagger/*", httpSwagger.Handler(
 				httpSwagger.URL("/api/swagger/doc.json"),
 			))
 		})
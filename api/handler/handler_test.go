@@ -33,7 +33,7 @@ func TestReadonlyMode(t *testing.T) {
 			SupportEmail: "test",
 			Contacts:     []api.WebContact{},
 		}
-		handler := NewHandler(mockDb, logger, nil, config, nil, webConfig)
+		handler := NewHandler(mockDb, logger, nil, config, nil, webConfig, nil)
 
 		Convey("Get notifier health", func() {
 			mockDb.EXPECT().GetNotifierState().Return("OK", nil).Times(1)
@@ -58,6 +58,7 @@ func TestReadonlyMode(t *testing.T) {
 		})
 
 		Convey("Put notifier health", func() {
+			mockDb.EXPECT().GetNotifierState().Return("OK", nil).Times(1)
 			mockDb.EXPECT().SetNotifierState("OK").Return(nil).Times(1)
 
 			state := &dto.NotifierState{
@@ -127,7 +128,7 @@ func TestAdminOnly(t *testing.T) {
 		SupportEmail: "test",
 		Contacts:     []api.WebContact{},
 	}
-	handler := NewHandler(mockDb, logger, nil, config, nil, webConfig)
+	handler := NewHandler(mockDb, logger, nil, config, nil, webConfig, nil)
 
 	Convey("Get all contacts", t, func() {
 		Convey("For non-admin", func() {
@@ -204,3 +205,92 @@ func TestAdminOnly(t *testing.T) {
 		})
 	})
 }
+
+func TestAPITokenScopes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+	database = mockDb
+
+	logger, _ := zerolog_adapter.GetLogger("Test")
+	config := &api.Config{Authorization: api.Authorization{Enabled: true, AdminList: map[string]struct{}{}}}
+	webConfig := &api.WebConfig{
+		SupportEmail: "test",
+		Contacts:     []api.WebContact{},
+	}
+	handler := NewHandler(mockDb, logger, nil, config, nil, webConfig, nil)
+
+	const secret = "secret"
+	readToken := moira.APIToken{
+		ID:         "read",
+		User:       "john",
+		Scopes:     []moira.APITokenScope{moira.APITokenScopeRead},
+		SecretHash: api.HashAPITokenSecret(secret),
+	}
+	triggersToken := moira.APIToken{
+		ID:         "triggers",
+		User:       "john",
+		Scopes:     []moira.APITokenScope{moira.APITokenScopeTriggers},
+		SecretHash: api.HashAPITokenSecret(secret),
+	}
+	teamToken := moira.APIToken{
+		ID:         "team",
+		Name:       "ci",
+		TeamID:     "team1",
+		Scopes:     []moira.APITokenScope{moira.TeamScope("team1")},
+		SecretHash: api.HashAPITokenSecret(secret),
+	}
+
+	performRequest := func(token moira.APIToken, method, path string) int {
+		mockDb.EXPECT().GetAPIToken(token.ID).Return(token, nil)
+		testRequest := httptest.NewRequest(method, path, nil)
+		testRequest.Header.Set("Authorization", "Bearer "+api.FormatAPIToken(token.ID, secret))
+
+		responseWriter := httptest.NewRecorder()
+		handler.ServeHTTP(responseWriter, testRequest)
+
+		response := responseWriter.Result()
+		defer response.Body.Close()
+		return response.StatusCode
+	}
+
+	Convey("Api token scopes", t, func() {
+		Convey("Read scope allows to read", func() {
+			So(performRequest(readToken, http.MethodGet, "/api/user"), ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Reading requires read scope", func() {
+			So(performRequest(triggersToken, http.MethodGet, "/api/user"), ShouldEqual, http.StatusForbidden)
+			So(performRequest(teamToken, http.MethodGet, "/api/user"), ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Read scope does not allow to change triggers", func() {
+			So(performRequest(readToken, http.MethodPut, "/api/trigger"), ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Team scope allows to read the team", func() {
+			mockDb.EXPECT().GetTeam("team1").Return(moira.Team{ID: "team1", Name: "team"}, nil)
+			So(performRequest(teamToken, http.MethodGet, "/api/teams/team1"), ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Team scope does not allow to change other team", func() {
+			So(performRequest(teamToken, http.MethodPatch, "/api/teams/team2"), ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Events of team are streamed only to members and tokens of the team", func() {
+			mockDb.EXPECT().GetTeam("team2").Return(moira.Team{ID: "team2", Name: "other"}, nil)
+			mockDb.EXPECT().IsTeamContainUser("team2", "john").Return(false, nil)
+			So(performRequest(readToken, http.MethodGet, "/api/events/stream?team=team2"), ShouldEqual, http.StatusForbidden)
+			So(performRequest(teamToken, http.MethodGet, "/api/events/stream?team=team2"), ShouldEqual, http.StatusForbidden)
+			So(performRequest(teamToken, http.MethodGet, "/api/events/stream"), ShouldEqual, http.StatusForbidden)
+			So(performRequest(teamToken, http.MethodGet, "/api/events/stream?team=team1&lastEventId=last"), ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Routes without declared scope are denied", func() {
+			So(performRequest(readToken, http.MethodPut, "/api/contact"), ShouldEqual, http.StatusForbidden)
+			So(performRequest(readToken, http.MethodGet, "/api/user/tokens"), ShouldEqual, http.StatusForbidden)
+			So(performRequest(teamToken, http.MethodGet, "/api/teams/team1/tokens"), ShouldEqual, http.StatusForbidden)
+		})
+	})
+}
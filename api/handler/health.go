@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/controller"
 	"github.com/moira-alert/moira/api/dto"
@@ -12,7 +13,7 @@ import (
 )
 
 func health(router chi.Router) {
-	router.Get("/notifier", getNotifierState)
+	router.With(readScope).Get("/notifier", getNotifierState)
 
 	router.With(middleware.AdminOnlyMiddleware()).
 		Put("/notifier", setNotifierState)
@@ -59,10 +60,12 @@ func setNotifierState(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
+	oldState, _ := controller.GetNotifierState(database)
 	if err := controller.UpdateNotifierState(database, state); err != nil {
 		render.Render(writer, request, err) //nolint
 		return
 	}
+	recordAudit(request, moira.AuditActionUpdate, moira.AuditObjectNotifierState, "", oldState, state)
 
 	if err := render.Render(writer, request, state); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) //nolint
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+)
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get plot image by signed link
+//	@description	Links to images are generated by notifier with filesystem image store and expire, authentication is not required.
+//	@id			get-image
+//	@tags		image
+//	@produce	png
+//	@produce	image/svg+xml
+//	@param		imageId		path	string	true	"ID of the image"				default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c.png)
+//	@param		expires		query	integer	true	"Unix time the link expires at"	default(1700000000)
+//	@param		signature	query	string	true	"Signature of the link"
+//	@success	200	"Image fetched successfully"
+//	@failure	400	{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	403	{object}	api.ErrorForbiddenExample		"Link is expired or has invalid signature"
+//	@failure	404	{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	500	{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/image/{imageId} [get]
+func getImage(imageServer api.ImageServer) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		expires, err := strconv.ParseInt(request.URL.Query().Get("expires"), 10, 64)
+		if err != nil {
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("invalid expires param: %w", err))) //nolint:errcheck
+			return
+		}
+
+		image, format, errorResponse := controller.GetImage(imageServer, chi.URLParam(request, "imageId"), expires, request.URL.Query().Get("signature"))
+		if errorResponse != nil {
+			render.Render(writer, request, errorResponse) //nolint:errcheck
+			return
+		}
+
+		setPlotHeaders(writer, format)
+		writer.Write(image) //nolint:errcheck
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/image_store/filesystem"
+	"github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetImage(t *testing.T) {
+	Convey("Get image by signed link", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		logger, _ := zerolog_adapter.GetLogger("Test")
+
+		imageStore := &filesystem.ImageStore{}
+		err := imageStore.Init(filesystem.Config{
+			Path:       t.TempDir(),
+			URL:        "http://moira/api/image",
+			SigningKey: "key",
+		})
+		So(err, ShouldBeNil)
+		image := []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)
+		link, err := imageStore.StoreImage(image)
+		So(err, ShouldBeNil)
+		parsed, err := url.Parse(link)
+		So(err, ShouldBeNil)
+
+		config := &api.Config{
+			Authorization: api.Authorization{Enabled: true},
+			ImageServer:   imageStore,
+		}
+		handler := NewHandler(mockDb, logger, nil, config, nil, &api.WebConfig{}, nil)
+		responseWriter := httptest.NewRecorder()
+
+		Convey("With valid link and without authentication", func() {
+			testRequest := httptest.NewRequest(http.MethodGet, parsed.RequestURI(), nil)
+
+			handler.ServeHTTP(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			contentBytes, _ := io.ReadAll(response.Body)
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+			So(response.Header.Get("Content-Type"), ShouldEqual, "image/svg+xml")
+			So(response.Header.Get("Content-Security-Policy"), ShouldEqual, "default-src 'none'; style-src 'unsafe-inline'")
+			So(response.Header.Get("Content-Disposition"), ShouldEqual, "attachment")
+			So(contentBytes, ShouldResemble, image)
+		})
+
+		Convey("With invalid signature", func() {
+			query := parsed.Query()
+			query.Set("signature", "invalid")
+			testRequest := httptest.NewRequest(http.MethodGet, parsed.Path+"?"+query.Encode(), nil)
+
+			handler.ServeHTTP(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Without expires param", func() {
+			testRequest := httptest.NewRequest(http.MethodGet, parsed.Path, nil)
+
+			handler.ServeHTTP(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
@@ -8,19 +8,27 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
 	"github.com/moira-alert/moira/api/middleware"
+	metricSource "github.com/moira-alert/moira/metric_source"
 )
 
-func notification(router chi.Router) {
-	router.Get("/", getNotification)
+func notification(metricSourceProvider *metricSource.SourceProvider, preview api.NotificationPreview) func(chi.Router) {
+	return func(router chi.Router) {
+		router.With(readScope).Get("/", getNotification)
+		if preview.Enabled {
+			router.Post("/preview", previewNotification(metricSourceProvider, preview))
+		}
 
-	router.Route("/", func(r chi.Router) {
-		r.Use(middleware.AdminOnlyMiddleware())
-		r.Delete("/", deleteNotification)
-		r.Delete("/all", deleteAllNotifications)
-	})
+		router.Route("/", func(r chi.Router) {
+			r.Use(middleware.AdminOnlyMiddleware())
+			r.Delete("/", deleteNotification)
+			r.Delete("/all", deleteAllNotifications)
+		})
+	}
 }
 
 // nolint: gofmt,goimports
@@ -95,6 +103,7 @@ func deleteNotification(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, errorResponse) //nolint
 		return
 	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectNotification, notificationKey, nil, nil)
 
 	if err := render.Render(writer, request, notifications); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) //nolint
@@ -114,5 +123,41 @@ func deleteNotification(writer http.ResponseWriter, request *http.Request) {
 func deleteAllNotifications(writer http.ResponseWriter, request *http.Request) {
 	if errorResponse := controller.DeleteAllNotifications(database); errorResponse != nil {
 		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectNotification, "", nil, nil)
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Render notification the way sender delivers it without sending
+//	@id			preview-notification
+//	@tags		notification
+//	@accept		json
+//	@produce	json
+//	@param		request	body		dto.NotificationPreviewRequest	true	"Trigger, events and contact type of rendered notification"
+//	@success	200		{object}	dto.NotificationPreview			"Notification rendered successfully"
+//	@failure	400		{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	404		{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	422		{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500		{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/notification/preview [post]
+func previewNotification(metricSourceProvider *metricSource.SourceProvider, preview api.NotificationPreview) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		previewRequest := &dto.NotificationPreviewRequest{}
+		if err := render.Bind(request, previewRequest); err != nil {
+			render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
+			return
+		}
+
+		notificationPreview, errorResponse := controller.BuildNotificationPreview(database, metricSourceProvider, preview, previewRequest)
+		if errorResponse != nil {
+			render.Render(writer, request, errorResponse) //nolint
+			return
+		}
+
+		if err := render.Render(writer, request, notificationPreview); err != nil {
+			render.Render(writer, request, api.ErrorRender(err)) //nolint
+		}
 	}
 }
@@ -1,14 +1,21 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
 	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/moira-alert/moira/senders/slack"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -36,6 +43,36 @@ func TestGetNotifications(t *testing.T) {
 			}
 		})
 
+		Convey("without signing secret of contact", func() {
+			notification := &moira.ScheduledNotification{
+				Timestamp: 123,
+				Contact:   moira.ContactData{ID: "contact", Type: "webhook", Value: "https://example.com", SigningSecret: "secret"},
+			}
+			mockDb.EXPECT().GetNotifications(gomock.Any(), gomock.Any()).Return([]*moira.ScheduledNotification{notification}, int64(1), nil)
+			database = mockDb
+
+			testRequest := httptest.NewRequest(http.MethodGet, "/notifications", nil)
+
+			getNotification(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			contentBytes, _ := io.ReadAll(response.Body)
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+			So(string(contentBytes), ShouldNotContainSubstring, `"signing_secret"`)
+			So(string(contentBytes), ShouldNotContainSubstring, `:"secret"`)
+
+			var actual struct {
+				List []struct {
+					Contact map[string]interface{} `json:"contact"`
+				} `json:"list"`
+			}
+			So(json.Unmarshal(contentBytes, &actual), ShouldBeNil)
+			So(actual.List, ShouldHaveLength, 1)
+			So(actual.List[0].Contact["id"], ShouldEqual, "contact")
+			So(actual.List[0].Contact["has_signing_secret"], ShouldEqual, true)
+		})
+
 		Convey("with the wrong url query string", func() {
 			testRequest := httptest.NewRequest(http.MethodGet, "/notifications?start=test%&end=100", nil)
 
@@ -113,3 +150,76 @@ func TestDeleteNotifications(t *testing.T) {
 		})
 	})
 }
+
+func TestPreviewNotification(t *testing.T) {
+	Convey("Test preview notification", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		responseWriter := httptest.NewRecorder()
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		database = mockDb
+
+		logger, _ := logging.GetLogger("Test")
+		slackBuilder := &slack.Sender{}
+		err := slackBuilder.InitMessageBuilder(map[string]interface{}{"front_uri": "http://moira.example.com"}, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+		preview := api.NotificationPreview{
+			Enabled:  true,
+			Builders: map[string]moira.MessageBuilder{"slack": slackBuilder},
+		}
+
+		Convey("Notification is rendered by sender of contact type", func() {
+			mockDb.EXPECT().GetTrigger("trigger-id").Return(moira.Trigger{
+				ID:      "trigger-id",
+				Name:    "Not enough disk space left",
+				Targets: []string{"server.hdd.used_percent"},
+				Tags:    []string{"server"},
+			}, nil)
+			body := `{"trigger_id": "trigger-id", "contact_type": "slack", "contact_value": "#alerts", "message_template": "{{ .State }} {{ .Trigger.Name }}"}`
+			testRequest := httptest.NewRequest(http.MethodPost, "/notification/preview", bytes.NewBufferString(body))
+			testRequest.Header.Add("content-type", "application/json")
+
+			previewNotification(nil, preview)(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			actual := &dto.NotificationPreview{}
+			err := json.NewDecoder(response.Body).Decode(actual)
+			So(err, ShouldBeNil)
+
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+			So(actual, ShouldResemble, &dto.NotificationPreview{
+				ContactType: "slack",
+				NotificationPayload: moira.NotificationPayload{
+					ContentType: "text/markdown",
+					Body:        "ERROR Not enough disk space left",
+				},
+			})
+		})
+
+		Convey("Request without trigger", func() {
+			testRequest := httptest.NewRequest(http.MethodPost, "/notification/preview", bytes.NewBufferString(`{"contact_type": "slack"}`))
+			testRequest.Header.Add("content-type", "application/json")
+
+			previewNotification(nil, preview)(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			contentBytes, _ := io.ReadAll(response.Body)
+			So(string(contentBytes), ShouldEqual, `{"status":"Invalid request","error":"trigger_id or trigger must be set"}
+`)
+			So(response.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Contact type without builder", func() {
+			testRequest := httptest.NewRequest(http.MethodPost, "/notification/preview", bytes.NewBufferString(`{"trigger_id": "trigger-id", "contact_type": "mail"}`))
+			testRequest.Header.Add("content-type", "application/json")
+
+			previewNotification(nil, preview)(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/controller"
 	"github.com/moira-alert/moira/api/middleware"
@@ -14,7 +15,7 @@ import (
 func pattern(router chi.Router) {
 	router.Use(middleware.AdminOnlyMiddleware())
 
-	router.Get("/", getAllPatterns)
+	router.With(readScope).Get("/", getAllPatterns)
 	router.Delete("/{pattern}", deletePattern)
 }
 
@@ -62,5 +63,7 @@ func deletePattern(writer http.ResponseWriter, request *http.Request) {
 	err := controller.DeletePattern(database, pattern)
 	if err != nil {
 		render.Render(writer, request, err) //nolint
+		return
 	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectPattern, pattern, nil, nil)
 }
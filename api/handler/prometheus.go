@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/exporter"
+)
+
+// nolint: gofmt,goimports
+//
+//	@summary		Get trigger states in Prometheus format
+//	@description	Exposes states, scores, maintenance and last check age of all triggers in Prometheus text exposition format.
+//	@description	Available only if prometheus exporter is enabled in api configuration. Series are cached between scrapes.
+//	@id				get-prometheus-metrics
+//	@tags			trigger
+//	@produce		plain
+//	@success		200	{string}	string							"Trigger states in Prometheus text format"
+//	@failure		500	{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router			/prometheus/metrics [get]
+func getPrometheusMetrics(logger moira.Logger, config exporter.Config) http.HandlerFunc {
+	return exporter.NewHandler(database, logger, config).ServeHTTP
+}
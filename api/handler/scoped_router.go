@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+// scopedRouter is chi.Router which wraps every endpoint with middleware.ScopeChecked,
+// so endpoint can be used with api token only if its route declares required scope with middleware.RequireScope.
+type scopedRouter struct {
+	chi.Router
+}
+
+func (router scopedRouter) With(middlewares ...func(http.Handler) http.Handler) chi.Router {
+	return scopedRouter{router.Router.With(middlewares...)}
+}
+
+func (router scopedRouter) Group(fn func(r chi.Router)) chi.Router {
+	return scopedRouter{router.Router.Group(func(r chi.Router) {
+		fn(scopedRouter{r})
+	})}
+}
+
+func (router scopedRouter) Route(pattern string, fn func(r chi.Router)) chi.Router {
+	return scopedRouter{router.Router.Route(pattern, func(r chi.Router) {
+		fn(scopedRouter{r})
+	})}
+}
+
+func (router scopedRouter) Mount(pattern string, h http.Handler) {
+	router.Router.Mount(pattern, middleware.ScopeChecked(h))
+}
+
+func (router scopedRouter) Handle(pattern string, h http.Handler) {
+	router.Router.Handle(pattern, middleware.ScopeChecked(h))
+}
+
+func (router scopedRouter) HandleFunc(pattern string, h http.HandlerFunc) {
+	router.Handle(pattern, h)
+}
+
+func (router scopedRouter) Method(method, pattern string, h http.Handler) {
+	router.Router.Method(method, pattern, middleware.ScopeChecked(h))
+}
+
+func (router scopedRouter) MethodFunc(method, pattern string, h http.HandlerFunc) {
+	router.Method(method, pattern, h)
+}
+
+func (router scopedRouter) Connect(pattern string, h http.HandlerFunc) {
+	router.Method(http.MethodConnect, pattern, h)
+}
+
+func (router scopedRouter) Delete(pattern string, h http.HandlerFunc) {
+	router.Method(http.MethodDelete, pattern, h)
+}
+
+func (router scopedRouter) Get(pattern string, h http.HandlerFunc) {
+	router.Method(http.MethodGet, pattern, h)
+}
+
+func (router scopedRouter) Head(pattern string, h http.HandlerFunc) {
+	router.Method(http.MethodHead, pattern, h)
+}
+
+func (router scopedRouter) Options(pattern string, h http.HandlerFunc) {
+	router.Method(http.MethodOptions, pattern, h)
+}
+
+func (router scopedRouter) Patch(pattern string, h http.HandlerFunc) {
+	router.Method(http.MethodPatch, pattern, h)
+}
+
+func (router scopedRouter) Post(pattern string, h http.HandlerFunc) {
+	router.Method(http.MethodPost, pattern, h)
+}
+
+func (router scopedRouter) Put(pattern string, h http.HandlerFunc) {
+	router.Method(http.MethodPut, pattern, h)
+}
+
+func (router scopedRouter) Trace(pattern string, h http.HandlerFunc) {
+	router.Method(http.MethodTrace, pattern, h)
+}
+
+var (
+	// readScope allows api tokens with read scope.
+	readScope = middleware.RequireScope(moira.APITokenScopeRead)
+	// triggersReadScope allows api tokens with read or triggers scope.
+	triggersReadScope = middleware.RequireScope(moira.APITokenScopeRead, moira.APITokenScopeTriggers)
+	// triggersScope allows api tokens with triggers scope.
+	triggersScope = middleware.RequireScope(moira.APITokenScopeTriggers)
+	// teamReadScope allows api tokens with read scope or scope of team from request.
+	teamReadScope = middleware.RequireTeamScope(moira.APITokenScopeRead)
+	// teamScope allows api tokens with scope of team from request.
+	teamScope = middleware.RequireTeamScope()
+	// teamFilterReadScope allows api tokens with read scope or scope of team from team query parameter.
+	teamFilterReadScope = middleware.RequireTeamFilterScope(moira.APITokenScopeRead)
+)
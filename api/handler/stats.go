@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/go-graphite/carbonapi/date"
+
+	"github.com/moira-alert/moira/analytics"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+func stats(router chi.Router) {
+	router.Use(readScope)
+	router.Use(middleware.DateRange("-1week", "now"))
+	router.Get("/triggers", getTriggersAnalytics)
+	router.With(middleware.AdminOnlyMiddleware()).Get("/notifications", getNotificationsAnalytics)
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary		Get statistics of trigger events
+//	@description	Noisiest, flapping and slowest to recover triggers, time spent in each state, mean time to recovery and triggers which never fired.
+//	@description	Trigger events are kept for 30 days, so older part of time range is not taken into account.
+//	@id				get-triggers-analytics
+//	@tags			stats
+//	@produce		json
+//	@param			from	query		string							false	"Start time of the time range"				default(-1week)
+//	@param			to		query		string							false	"End time of the time range"				default(now)
+//	@param			top		query		integer							false	"Max number of triggers in each top list"	default(10)
+//	@param			team	query		string							false	"ID of the team which triggers are analyzed"
+//	@success		200		{object}	dto.TriggersAnalytics			"Statistics calculated successfully"
+//	@failure		400		{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure		422		{object}	api.ErrorRenderExample			"Render error"
+//	@failure		500		{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router			/stats/triggers [get]
+func getTriggersAnalytics(writer http.ResponseWriter, request *http.Request) {
+	options, errorResponse := getAnalyticsOptions(request)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+
+	report, errorResponse := controller.GetTriggersAnalytics(database, options)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+
+	if err := render.Render(writer, request, report); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+	}
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary		Get statistics of sent notifications
+//	@description	Number of notifications sent to each contact, contacts of each team and by each sender.
+//	@description	Notification history is kept for notification_history_ttl, so older part of time range is not taken into account.
+//	@id				get-notifications-analytics
+//	@tags			stats
+//	@produce		json
+//	@param			from	query		string							false	"Start time of the time range"	default(-1week)
+//	@param			to		query		string							false	"End time of the time range"	default(now)
+//	@param			team	query		string							false	"ID of the team which contacts are analyzed"
+//	@success		200		{object}	dto.NotificationsAnalytics		"Statistics calculated successfully"
+//	@failure		400		{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure		403		{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure		422		{object}	api.ErrorRenderExample			"Render error"
+//	@failure		500		{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router			/stats/notifications [get]
+func getNotificationsAnalytics(writer http.ResponseWriter, request *http.Request) {
+	options, errorResponse := getAnalyticsOptions(request)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+
+	report, errorResponse := controller.GetNotificationsAnalytics(database, options)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+
+	if err := render.Render(writer, request, report); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+	}
+}
+
+func getAnalyticsOptions(request *http.Request) (analytics.Options, *api.ErrorResponse) {
+	fromStr := middleware.GetFromStr(request)
+	from := date.DateParamToEpoch(fromStr, "UTC", 0, time.UTC)
+	if from == 0 {
+		return analytics.Options{}, api.ErrorInvalidRequest(fmt.Errorf("can not parse from: %s", fromStr))
+	}
+
+	toStr := middleware.GetToStr(request)
+	to := date.DateParamToEpoch(toStr, "UTC", 0, time.UTC)
+	if to == 0 {
+		return analytics.Options{}, api.ErrorInvalidRequest(fmt.Errorf("can not parse to: %s", toStr))
+	}
+
+	urlValues := request.URL.Query()
+	options := analytics.Options{
+		From:   from,
+		To:     to,
+		Top:    analytics.DefaultTop,
+		TeamID: urlValues.Get("team"),
+	}
+
+	if topStr := urlValues.Get("top"); topStr != "" {
+		top, err := strconv.Atoi(topStr)
+		if err != nil || top <= 0 {
+			return analytics.Options{}, api.ErrorInvalidRequest(fmt.Errorf("invalid top: %s", topStr))
+		}
+		options.Top = top
+	}
+
+	return options, nil
+}
@@ -15,12 +15,12 @@ import (
 )
 
 func subscription(router chi.Router) {
-	router.Get("/", getUserSubscriptions)
+	router.With(readScope).Get("/", getUserSubscriptions)
 	router.Put("/", createSubscription)
 	router.Route("/{subscriptionId}", func(router chi.Router) {
 		router.Use(middleware.SubscriptionContext)
 		router.Use(subscriptionFilter)
-		router.Get("/", getSubscription)
+		router.With(readScope).Get("/", getSubscription)
 		router.Put("/", updateSubscription)
 		router.Delete("/", removeSubscription)
 		router.Put("/test", sendTestNotification)
@@ -83,6 +83,7 @@ func createSubscription(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, err) //nolint
 		return
 	}
+	recordAudit(request, moira.AuditActionCreate, moira.AuditObjectSubscription, subscription.ID, nil, subscription)
 	if err := render.Render(writer, request, subscription); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) //nolint
 		return
@@ -90,6 +91,7 @@ func createSubscription(writer http.ResponseWriter, request *http.Request) {
 }
 
 // subscriptionFilter is middleware for check subscription existence and user permissions.
+// Subscriptions of team can be changed only by editors of the team.
 func subscriptionFilter(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		subscriptionID := middleware.GetSubscriptionID(request)
@@ -100,6 +102,12 @@ func subscriptionFilter(next http.Handler) http.Handler {
 			render.Render(writer, request, err) //nolint
 			return
 		}
+		if subscriptionData.TeamID != "" && !isReadRequest(request) {
+			if err = controller.CheckUserPermissionsForTeam(database, subscriptionData.TeamID, userLogin, auth, moira.TeamRoleEditor); err != nil {
+				render.Render(writer, request, err) //nolint
+				return
+			}
+		}
 		ctx := context.WithValue(request.Context(), subscriptionKey, subscriptionData)
 		next.ServeHTTP(writer, request.WithContext(ctx))
 	})
@@ -171,6 +179,7 @@ func updateSubscription(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, err) //nolint
 		return
 	}
+	recordAudit(request, moira.AuditActionUpdate, moira.AuditObjectSubscription, subscriptionData.ID, subscriptionData, subscription)
 	if err := render.Render(writer, request, subscription); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) //nolint
 		return
@@ -193,7 +202,10 @@ func removeSubscription(writer http.ResponseWriter, request *http.Request) {
 	subscriptionID := middleware.GetSubscriptionID(request)
 	if err := controller.RemoveSubscription(database, subscriptionID); err != nil {
 		render.Render(writer, request, err) //nolint
+		return
 	}
+	subscriptionData, _ := request.Context().Value(subscriptionKey).(moira.SubscriptionData)
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectSubscription, subscriptionID, subscriptionData, nil)
 }
 
 // nolint: gofmt,goimports
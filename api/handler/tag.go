@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/controller"
 	"github.com/moira-alert/moira/api/dto"
@@ -13,8 +14,8 @@ import (
 
 func tag(router chi.Router) {
 	router.Post("/", createTags)
-	router.Get("/", getAllTags)
-	router.Get("/stats", getAllTagsAndSubscriptions)
+	router.With(readScope).Get("/", getAllTags)
+	router.With(readScope).Get("/stats", getAllTagsAndSubscriptions)
 	router.Route("/{tag}", func(router chi.Router) {
 		router.Use(middleware.TagContext)
 		router.Use(middleware.AdminOnlyMiddleware())
@@ -67,6 +68,10 @@ func createTags(writer http.ResponseWriter, request *http.Request) {
 
 	if err := controller.CreateTags(database, &tags); err != nil {
 		render.Render(writer, request, err) //nolint
+		return
+	}
+	for _, tag := range tags.TagNames {
+		recordAudit(request, moira.AuditActionCreate, moira.AuditObjectTag, tag, nil, nil)
 	}
 }
 
@@ -112,6 +117,7 @@ func removeTag(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, err) //nolint
 		return
 	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectTag, tagName, nil, nil)
 	if err := render.Render(writer, request, response); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) //nolint
 		return
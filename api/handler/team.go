@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/controller"
 	"github.com/moira-alert/moira/api/dto"
@@ -12,39 +13,69 @@ import (
 )
 
 func teams(router chi.Router) {
-	router.Get("/", getAllTeams)
+	router.With(readScope).Get("/", getAllTeams)
 	router.Post("/", createTeam)
 	router.Route("/{teamId}", func(router chi.Router) {
 		router.Use(middleware.TeamContext)
-		router.Use(usersFilterForTeams)
-		router.Get("/", getTeam)
-		router.Patch("/", updateTeam)
-		router.Delete("/", deleteTeam)
+		viewers := router.With(teamReadScope, usersFilterForTeams(moira.TeamRoleViewer))
+		editors := router.With(teamScope, usersFilterForTeams(moira.TeamRoleEditor))
+		owners := router.With(teamScope, usersFilterForTeams(moira.TeamRoleOwner))
+		viewers.Get("/", getTeam)
+		owners.Patch("/", updateTeam)
+		owners.Delete("/", deleteTeam)
 		router.Route("/users", func(router chi.Router) {
-			router.Get("/", getTeamUsers)
-			router.Put("/", setTeamUsers)
-			router.Post("/", addTeamUsers)
-			router.With(middleware.TeamUserIDContext).Delete("/{teamUserId}", deleteTeamUser)
+			router.With(teamReadScope, usersFilterForTeams(moira.TeamRoleViewer)).Get("/", getTeamUsers)
+			router.Group(func(router chi.Router) {
+				router.Use(teamScope)
+				router.Use(usersFilterForTeams(moira.TeamRoleOwner))
+				router.Put("/", setTeamUsers)
+				router.Post("/", addTeamUsers)
+				router.With(middleware.TeamUserIDContext).Delete("/{teamUserId}", deleteTeamUser)
+				router.With(middleware.TeamUserIDContext).Put("/{teamUserId}/role", setTeamUserRole)
+			})
 		})
-		router.Get("/settings", getTeamSettings)
-		router.Route("/subscriptions", teamSubscription)
-		router.Route("/contacts", teamContact)
+		viewers.Get("/settings", getTeamSettings)
+		viewers.Get("/triggers", getTeamTriggers)
+		editors.Route("/subscriptions", teamSubscription)
+		editors.Route("/contacts", teamContact)
+		// Api tokens can not be used to manage api tokens, so tokens routes do not require any scope
+		router.With(usersFilterForTeams(moira.TeamRoleOwner)).Route("/tokens", teamAPITokens)
 	})
 }
 
-// usersFilterForTeams is middleware that checks that user exists in this.
-func usersFilterForTeams(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-		userLogin := middleware.GetLogin(request)
-		teamID := middleware.GetTeamID(request)
-		auth := middleware.GetAuth(request)
-		err := controller.CheckUserPermissionsForTeam(database, teamID, userLogin, auth)
-		if err != nil {
-			render.Render(writer, request, err) //nolint
-			return
-		}
-		next.ServeHTTP(writer, request)
-	})
+// usersFilterForTeams is middleware that checks that user exists in this team with at least given role
+// or request is made with token of this team.
+func usersFilterForTeams(role moira.TeamRole) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			err := checkTeamPermissions(request, middleware.GetTeamID(request), role)
+			if err != nil {
+				render.Render(writer, request, err) //nolint
+				return
+			}
+			next.ServeHTTP(writer, request)
+		})
+	}
+}
+
+// checkTeamPermissions checks that user exists in team with at least given role or request is made with token of team.
+func checkTeamPermissions(request *http.Request, teamID string, role moira.TeamRole) *api.ErrorResponse {
+	if token := middleware.GetAPIToken(request); token != nil && token.TeamID == teamID {
+		return nil
+	}
+
+	userLogin := middleware.GetLogin(request)
+	auth := middleware.GetAuth(request)
+	return controller.CheckUserPermissionsForTeam(database, teamID, userLogin, auth, role)
+}
+
+// isReadRequest returns true if request does not change anything, team viewers are allowed to make such requests.
+func isReadRequest(request *http.Request) bool {
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
 }
 
 // nolint: gofmt,goimports
@@ -73,6 +104,7 @@ func createTeam(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, apiErr) //nolint:errcheck
 		return
 	}
+	recordAudit(request, moira.AuditActionCreate, moira.AuditObjectTeam, response.ID, nil, team)
 	if err := render.Render(writer, request, response); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
 		return
@@ -157,11 +189,13 @@ func updateTeam(writer http.ResponseWriter, request *http.Request) {
 
 	teamID := middleware.GetTeamID(request)
 
+	oldTeam, _ := controller.GetTeam(database, teamID)
 	response, apiErr := controller.UpdateTeam(database, teamID, team)
 	if apiErr != nil {
 		render.Render(writer, request, apiErr) //nolint:errcheck
 		return
 	}
+	recordAudit(request, moira.AuditActionUpdate, moira.AuditObjectTeam, teamID, oldTeam, team)
 	if err := render.Render(writer, request, response); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
 		return
@@ -186,11 +220,13 @@ func deleteTeam(writer http.ResponseWriter, request *http.Request) {
 	userLogin := middleware.GetLogin(request)
 	teamID := middleware.GetTeamID(request)
 
+	oldTeam, _ := controller.GetTeam(database, teamID)
 	response, apiErr := controller.DeleteTeam(database, teamID, userLogin)
 	if apiErr != nil {
 		render.Render(writer, request, apiErr) //nolint:errcheck
 		return
 	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectTeam, teamID, oldTeam, nil)
 	if err := render.Render(writer, request, response); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
 		return
@@ -251,11 +287,13 @@ func setTeamUsers(writer http.ResponseWriter, request *http.Request) {
 
 	teamID := middleware.GetTeamID(request)
 
+	oldMembers, _ := controller.GetTeamUsers(database, teamID)
 	response, apiErr := controller.SetTeamUsers(database, teamID, members.Usernames)
 	if apiErr != nil {
 		render.Render(writer, request, apiErr) // nolint:errcheck
 		return
 	}
+	recordAudit(request, moira.AuditActionUpdate, moira.AuditObjectTeamUsers, teamID, oldMembers, response)
 
 	if err := render.Render(writer, request, response); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) // nolint:errcheck
@@ -288,11 +326,13 @@ func addTeamUsers(writer http.ResponseWriter, request *http.Request) {
 	}
 	teamID := middleware.GetTeamID(request)
 
+	oldMembers, _ := controller.GetTeamUsers(database, teamID)
 	response, apiErr := controller.AddTeamUsers(database, teamID, members.Usernames)
 	if apiErr != nil {
 		render.Render(writer, request, apiErr) // nolint:errcheck
 		return
 	}
+	recordAudit(request, moira.AuditActionUpdate, moira.AuditObjectTeamUsers, teamID, oldMembers, response)
 
 	if err := render.Render(writer, request, response); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) // nolint:errcheck
@@ -319,11 +359,13 @@ func deleteTeamUser(writer http.ResponseWriter, request *http.Request) {
 	teamID := middleware.GetTeamID(request)
 	userID := middleware.GetTeamUserID(request)
 
+	oldMembers, _ := controller.GetTeamUsers(database, teamID)
 	response, err := controller.DeleteTeamUser(database, teamID, userID)
 	if err != nil {
 		render.Render(writer, request, err) // nolint:errcheck
 		return
 	}
+	recordAudit(request, moira.AuditActionUpdate, moira.AuditObjectTeamUsers, teamID, oldMembers, response)
 
 	if err := render.Render(writer, request, response); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) // nolint:errcheck
@@ -331,6 +373,48 @@ func deleteTeamUser(writer http.ResponseWriter, request *http.Request) {
 	}
 }
 
+// nolint: gofmt,goimports
+//
+//	@summary		Change role of team member
+//	@description	Owner can manage the team, its members and api tokens, editor can change contacts, subscriptions and triggers of the team, viewer can only read them.
+//	@id				set-team-user-role
+//	@tags			team
+//	@accept			json
+//	@produce		json
+//	@param			teamID		path		string							true	"ID of the team"										default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
+//	@param			teamUserID	path		string							true	"User login in methods related to teams manipulation"	default(anonymous)
+//	@param			role		body		dto.TeamUserRole				true	"New role of team member"
+//	@success		200			{object}	dto.TeamMembers					"Role changed successfully"
+//	@failure		400			{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure		403			{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure		404			{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure		422			{object}	api.ErrorRenderExample			"Render error"
+//	@failure		500			{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router			/teams/{teamID}/users/{teamUserID}/role [put]
+func setTeamUserRole(writer http.ResponseWriter, request *http.Request) {
+	role := dto.TeamUserRole{}
+	if err := render.Bind(request, &role); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint:errcheck
+		return
+	}
+
+	teamID := middleware.GetTeamID(request)
+	userID := middleware.GetTeamUserID(request)
+
+	oldMembers, _ := controller.GetTeamUsers(database, teamID)
+	response, err := controller.SetTeamUserRole(database, teamID, userID, role.Role)
+	if err != nil {
+		render.Render(writer, request, err) //nolint:errcheck
+		return
+	}
+	recordAudit(request, moira.AuditActionUpdate, moira.AuditObjectTeamUsers, teamID, oldMembers, response)
+
+	if err := render.Render(writer, request, response); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
+		return
+	}
+}
+
 // nolint: gofmt,goimports
 //
 //	@summary	Get team settings
@@ -357,3 +441,30 @@ func getTeamSettings(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 }
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get triggers owned by the team
+//	@id			get-team-triggers
+//	@tags		team
+//	@produce	json
+//	@param		teamID	path		string							true	"ID of the team"	default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
+//	@success	200		{object}	dto.TriggersList				"Team triggers fetched successfully"
+//	@failure	403		{object}	api.ErrorForbiddenExample		"Forbidden"
+//	@failure	404		{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	422		{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500		{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/teams/{teamID}/triggers [get]
+func getTeamTriggers(writer http.ResponseWriter, request *http.Request) {
+	teamID := middleware.GetTeamID(request)
+	triggers, err := controller.GetTeamTriggers(database, teamID)
+	if err != nil {
+		render.Render(writer, request, err) //nolint:errcheck
+		return
+	}
+
+	if err := render.Render(writer, request, triggers); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
+		return
+	}
+}
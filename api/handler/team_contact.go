@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/controller"
 	"github.com/moira-alert/moira/api/dto"
@@ -45,6 +46,7 @@ func createNewTeamContact(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, err) //nolint:errcheck
 		return
 	}
+	recordAudit(request, moira.AuditActionCreate, moira.AuditObjectContact, contact.ID, nil, contact)
 
 	if err := render.Render(writer, request, contact); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/controller"
 	"github.com/moira-alert/moira/api/dto"
@@ -51,6 +52,7 @@ func createTeamSubscription(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, err) //nolint:errcheck
 		return
 	}
+	recordAudit(request, moira.AuditActionCreate, moira.AuditObjectSubscription, subscription.ID, nil, subscription)
 	if err := render.Render(writer, request, subscription); err != nil {
 		render.Render(writer, request, api.ErrorRender(err)) //nolint:errcheck
 		return
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/middleware"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUsersFilterForTeams(t *testing.T) {
+	Convey("Test users filter for teams", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		database = mockDb
+
+		const teamID = "teamID"
+		const userLogin = "user"
+		handler := usersFilterForTeams(moira.TeamRoleEditor)(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+
+		newRequest := func(token *moira.APIToken) *http.Request {
+			testRequest := httptest.NewRequest(http.MethodPost, "/teams/teamID/contacts", nil)
+			ctx := middleware.SetContextValueForTest(testRequest.Context(), "teamID", teamID)
+			ctx = middleware.SetContextValueForTest(ctx, "login", userLogin)
+			ctx = middleware.SetContextValueForTest(ctx, "auth", &api.Authorization{})
+			if token != nil {
+				ctx = middleware.SetContextValueForTest(ctx, "apiToken", token)
+			}
+			return testRequest.WithContext(ctx)
+		}
+
+		Convey("Editor is allowed", func() {
+			mockDb.EXPECT().GetTeam(teamID).Return(moira.Team{ID: teamID}, nil)
+			mockDb.EXPECT().IsTeamContainUser(teamID, userLogin).Return(true, nil)
+			mockDb.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{userLogin: moira.TeamRoleEditor}, nil)
+
+			responseWriter := httptest.NewRecorder()
+			handler.ServeHTTP(responseWriter, newRequest(nil))
+			So(responseWriter.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Viewer is forbidden", func() {
+			mockDb.EXPECT().GetTeam(teamID).Return(moira.Team{ID: teamID}, nil)
+			mockDb.EXPECT().IsTeamContainUser(teamID, userLogin).Return(true, nil)
+			mockDb.EXPECT().GetTeamUserRoles(teamID).Return(map[string]moira.TeamRole{userLogin: moira.TeamRoleViewer}, nil)
+
+			responseWriter := httptest.NewRecorder()
+			handler.ServeHTTP(responseWriter, newRequest(nil))
+			So(responseWriter.Code, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Api token of the team is allowed", func() {
+			responseWriter := httptest.NewRecorder()
+			handler.ServeHTTP(responseWriter, newRequest(&moira.APIToken{ID: "token", TeamID: teamID}))
+			So(responseWriter.Code, ShouldEqual, http.StatusOK)
+		})
+	})
+}
+
+func TestTriggerTeamFilter(t *testing.T) {
+	Convey("Test trigger team filter", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		database = mockDb
+
+		const teamID = "teamID"
+		const triggerID = "triggerID"
+		const userLogin = "user"
+		handler := triggerTeamFilter(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+
+		newRequest := func(method string) *http.Request {
+			testRequest := httptest.NewRequest(method, "/trigger/triggerID", nil)
+			ctx := middleware.SetContextValueForTest(testRequest.Context(), "triggerID", triggerID)
+			ctx = middleware.SetContextValueForTest(ctx, "login", userLogin)
+			ctx = middleware.SetContextValueForTest(ctx, "auth", &api.Authorization{})
+			return testRequest.WithContext(ctx)
+		}
+
+		Convey("Anyone can read trigger", func() {
+			responseWriter := httptest.NewRecorder()
+			handler.ServeHTTP(responseWriter, newRequest(http.MethodGet))
+			So(responseWriter.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Trigger without team can be changed by anyone", func() {
+			mockDb.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID}, nil)
+
+			responseWriter := httptest.NewRecorder()
+			handler.ServeHTTP(responseWriter, newRequest(http.MethodDelete))
+			So(responseWriter.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Trigger of team can not be changed by user outside of the team", func() {
+			mockDb.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{ID: triggerID, TeamID: teamID}, nil)
+			mockDb.EXPECT().GetTeam(teamID).Return(moira.Team{ID: teamID}, nil)
+			mockDb.EXPECT().IsTeamContainUser(teamID, userLogin).Return(false, nil)
+
+			responseWriter := httptest.NewRecorder()
+			handler.ServeHTTP(responseWriter, newRequest(http.MethodPut))
+			So(responseWriter.Code, ShouldEqual, http.StatusForbidden)
+		})
+	})
+}
@@ -16,18 +16,57 @@ import (
 
 func trigger(router chi.Router) {
 	router.Use(middleware.TriggerContext)
-	router.Put("/", updateTrigger)
-	router.With(middleware.TriggerContext, middleware.Populate(false)).Get("/", getTrigger)
-	router.Delete("/", removeTrigger)
-	router.Get("/state", getTriggerState)
+	router.Use(triggerTeamFilter)
+	router.With(triggersScope).Put("/", updateTrigger)
+	router.With(triggersReadScope, middleware.TriggerContext, middleware.Populate(false)).Get("/", getTrigger)
+	router.With(triggersScope).Delete("/", removeTrigger)
+	router.With(triggersReadScope).Get("/state", getTriggerState)
 	router.Route("/throttling", func(router chi.Router) {
-		router.Get("/", getTriggerThrottling)
-		router.Delete("/", deleteThrottling)
+		router.With(triggersReadScope).Get("/", getTriggerThrottling)
+		router.With(triggersScope).Delete("/", deleteThrottling)
 	})
 	router.Route("/metrics", triggerMetrics)
-	router.Put("/setMaintenance", setTriggerMaintenance)
-	router.With(middleware.DateRange("-1hour", "now")).With(middleware.TargetName("t1")).Get("/render", renderTrigger)
-	router.Get("/dump", triggerDump)
+	router.With(triggersScope).Put("/setMaintenance", setTriggerMaintenance)
+	router.With(triggersReadScope, middleware.DateRange("-1hour", "now")).With(middleware.TargetName("t1")).Get("/render", renderTrigger)
+	router.With(triggersReadScope).Get("/dump", triggerDump)
+	router.Route("/history", triggerHistory)
+}
+
+// triggerTeamFilter is middleware that allows to change trigger owned by team only to editors of the team.
+func triggerTeamFilter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if isReadRequest(request) {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		triggerID := middleware.GetTriggerID(request)
+		userLogin := middleware.GetLogin(request)
+		auth := middleware.GetAuth(request)
+		err := controller.CheckUserPermissionsForTrigger(database, triggerID, userLogin, getAPITokenTeamID(request), auth)
+		if err != nil {
+			render.Render(writer, request, err) //nolint
+			return
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
+
+// checkTriggerTeamPermissions checks that user from the request is allowed to make team the owner of trigger.
+func checkTriggerTeamPermissions(request *http.Request, teamID string) *api.ErrorResponse {
+	if teamID == "" {
+		return nil
+	}
+	userLogin := middleware.GetLogin(request)
+	auth := middleware.GetAuth(request)
+	return controller.CheckUserPermissionsForTriggerTeam(database, teamID, userLogin, getAPITokenTeamID(request), auth)
+}
+
+func getAPITokenTeamID(request *http.Request) string {
+	if token := middleware.GetAPIToken(request); token != nil {
+		return token.TeamID
+	}
+	return ""
 }
 
 // nolint: gofmt,goimports
@@ -41,6 +80,7 @@ func trigger(router chi.Router) {
 //	@param		body		body		dto.Trigger								true	"Trigger data"
 //	@success	200			{object}	dto.SaveTriggerResponse					"Updated trigger"
 //	@failure	400			{object}	api.ErrorInvalidRequestExample			"Bad request from client"
+//	@failure	403			{object}	api.ErrorForbiddenExample				"Forbidden"
 //	@failure	404			{object}	api.ErrorNotFoundExample				"Resource not found"
 //	@failure	422			{object}	api.ErrorRenderExample					"Render error"
 //	@failure	500			{object}	api.ErrorInternalServerExample			"Internal server error"
@@ -55,6 +95,11 @@ func updateTrigger(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
+	if err = checkTriggerTeamPermissions(request, trigger.TeamID); err != nil {
+		render.Render(writer, request, err) //nolint
+		return
+	}
+
 	var problems []dto.TreeOfProblems
 	if needValidate(request) {
 		problems, err = validateTargets(request, trigger)
@@ -70,11 +115,13 @@ func updateTrigger(writer http.ResponseWriter, request *http.Request) {
 	}
 
 	timeSeriesNames := middleware.GetTimeSeriesNames(request)
+	oldTrigger, _ := controller.GetTrigger(database, triggerID)
 	response, err := controller.UpdateTrigger(database, &trigger.TriggerModel, triggerID, timeSeriesNames)
 	if err != nil {
 		render.Render(writer, request, err) //nolint
 		return
 	}
+	recordAudit(request, moira.AuditActionUpdate, moira.AuditObjectTrigger, triggerID, oldTrigger, &trigger.TriggerModel)
 
 	if problems != nil {
 		response.CheckResult.Targets = problems
@@ -130,15 +177,19 @@ func writeErrorSaveResponse(writer http.ResponseWriter, request *http.Request, t
 //	@tags		trigger
 //	@param		triggerID	path	string	true	"Trigger ID"	default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
 //	@success	200			"Successfully removed"
+//	@failure	403			{object}	api.ErrorForbiddenExample		"Forbidden"
 //	@failure	404			{object}	api.ErrorNotFoundExample		"Resource not found"
 //	@failure	500			{object}	api.ErrorInternalServerExample	"Internal server error"
 //	@router		/trigger/{triggerID} [delete]
 func removeTrigger(writer http.ResponseWriter, request *http.Request) {
 	triggerID := middleware.GetTriggerID(request)
-	err := controller.RemoveTrigger(database, triggerID)
+	oldTrigger, _ := controller.GetTrigger(database, triggerID)
+	err := controller.RemoveTrigger(database, triggerID, middleware.GetLogin(request))
 	if err != nil {
 		render.Render(writer, request, err) //nolint
+		return
 	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectTrigger, triggerID, oldTrigger, nil)
 }
 
 // nolint: gofmt,goimports
@@ -253,7 +304,9 @@ func deleteThrottling(writer http.ResponseWriter, request *http.Request) {
 	err := controller.DeleteTriggerThrottling(database, triggerID)
 	if err != nil {
 		render.Render(writer, request, err) //nolint
+		return
 	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectTriggerThrottling, triggerID, nil, nil)
 }
 
 // nolint: gofmt,goimports
@@ -282,7 +335,9 @@ func setTriggerMaintenance(writer http.ResponseWriter, request *http.Request) {
 	err := controller.SetTriggerMaintenance(database, triggerID, triggerMaintenance, userLogin, timeCallMaintenance)
 	if err != nil {
 		render.Render(writer, request, err) //nolint
+		return
 	}
+	recordAudit(request, moira.AuditActionUpdate, moira.AuditObjectTriggerMaintenance, triggerID, nil, triggerMaintenance)
 }
 
 // nolint: gofmt,goimports
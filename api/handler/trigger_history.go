@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+func triggerHistory(router chi.Router) {
+	router.With(triggersReadScope).Get("/", getTriggerHistory)
+	router.With(triggersReadScope).Get("/{version}/diff", getTriggerHistoryDiff)
+	router.With(triggersScope).Post("/{version}/restore", restoreTrigger)
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get trigger change history
+//	@id			get-trigger-history
+//	@tags		trigger
+//	@produce	json
+//	@param		triggerID	path		string							true	"Trigger ID"	default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
+//	@success	200			{object}	dto.TriggerHistoryList			"Trigger versions, the newest versions go first"
+//	@failure	422			{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500			{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/trigger/{triggerID}/history [get]
+func getTriggerHistory(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+
+	history, err := controller.GetTriggerHistory(database, triggerID)
+	if err != nil {
+		render.Render(writer, request, err) //nolint
+		return
+	}
+
+	if err := render.Render(writer, request, history); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+		return
+	}
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get changes between trigger versions
+//	@id			get-trigger-history-diff
+//	@tags		trigger
+//	@produce	json
+//	@param		triggerID	path		string							true	"Trigger ID"						default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
+//	@param		version		path		integer							true	"Trigger version"					default(2)
+//	@param		compare_to	query		integer							false	"Version to compare with, previous version by default"
+//	@success	200			{object}	dto.TriggerHistoryDiff			"Changed trigger fields"
+//	@failure	400			{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	404			{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	422			{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500			{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/trigger/{triggerID}/history/{version}/diff [get]
+func getTriggerHistoryDiff(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+
+	version, errResponse := getTriggerVersion(request)
+	if errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint
+		return
+	}
+
+	var compareTo int64
+	if compareToStr := request.URL.Query().Get("compare_to"); compareToStr != "" {
+		var err error
+		compareTo, err = strconv.ParseInt(compareToStr, 10, 64)
+		if err != nil || compareTo <= 0 {
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("invalid compare_to version: %s", compareToStr))) //nolint
+			return
+		}
+	}
+
+	diff, errResponse := controller.GetTriggerHistoryDiff(database, triggerID, version, compareTo)
+	if errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint
+		return
+	}
+
+	if err := render.Render(writer, request, diff); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+		return
+	}
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Restore trigger from given version, deleted triggers can be restored too
+//	@id			restore-trigger
+//	@tags		trigger
+//	@produce	json
+//	@param		triggerID	path		string									true	"Trigger ID"		default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
+//	@param		version		path		integer									true	"Trigger version"	default(2)
+//	@success	200			{object}	dto.SaveTriggerResponse					"Trigger restored"
+//	@failure	400			{object}	api.ErrorInvalidRequestExample			"Bad request from client"
+//	@failure	403			{object}	api.ErrorForbiddenExample				"Forbidden"
+//	@failure	404			{object}	api.ErrorNotFoundExample				"Resource not found"
+//	@failure	422			{object}	api.ErrorRenderExample					"Render error"
+//	@failure	500			{object}	api.ErrorInternalServerExample			"Internal server error"
+//	@failure	503			{object}	api.ErrorRemoteServerUnavailableExample	"Remote server unavailable"
+//	@router		/trigger/{triggerID}/history/{version}/restore [post]
+func restoreTrigger(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+
+	version, errResponse := getTriggerVersion(request)
+	if errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint
+		return
+	}
+
+	trigger, errResponse := controller.GetTriggerHistoryVersion(database, triggerID, version)
+	if errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint
+		return
+	}
+
+	// Trigger from history is validated the same way as trigger from request body
+	if err := trigger.Bind(request); err != nil {
+		render.Render(writer, request, getTriggerBindErrorResponse(request, err)) //nolint
+		return
+	}
+	trigger.UpdatedBy = middleware.GetLogin(request)
+
+	if errResponse = checkTriggerTeamPermissions(request, trigger.TeamID); errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint
+		return
+	}
+
+	timeSeriesNames := middleware.GetTimeSeriesNames(request)
+	oldTrigger, _ := controller.GetTrigger(database, triggerID)
+	response, errResponse := controller.RestoreTrigger(database, &trigger.TriggerModel, triggerID, timeSeriesNames)
+	if errResponse != nil {
+		render.Render(writer, request, errResponse) //nolint
+		return
+	}
+
+	action := moira.AuditActionUpdate
+	if oldTrigger == nil {
+		action = moira.AuditActionCreate
+	}
+	recordAudit(request, action, moira.AuditObjectTrigger, triggerID, oldTrigger, &trigger.TriggerModel)
+
+	if err := render.Render(writer, request, response); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+		return
+	}
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get recently deleted triggers, non-admin users get triggers they created or deleted and triggers of their teams
+//	@id			get-deleted-triggers
+//	@tags		trigger
+//	@produce	json
+//	@success	200	{object}	dto.TriggerHistoryList			"Last versions of deleted triggers"
+//	@failure	422	{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500	{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/trigger/deleted [get]
+func getDeletedTriggers(writer http.ResponseWriter, request *http.Request) {
+	deleted, err := controller.GetDeletedTriggers(database, middleware.GetLogin(request), getAPITokenTeamID(request), middleware.GetAuth(request))
+	if err != nil {
+		render.Render(writer, request, err) //nolint
+		return
+	}
+
+	if err := render.Render(writer, request, deleted); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+		return
+	}
+}
+
+func getTriggerVersion(request *http.Request) (int64, *api.ErrorResponse) {
+	versionStr := chi.URLParam(request, "version")
+	version, err := strconv.ParseInt(versionStr, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, api.ErrorInvalidRequest(fmt.Errorf("invalid trigger version: %s", versionStr))
+	}
+	return version, nil
+}
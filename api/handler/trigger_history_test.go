@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetTriggerHistoryDiff(t *testing.T) {
+	Convey("Test get trigger history diff", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		responseWriter := httptest.NewRecorder()
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		database = mockDb
+
+		triggerID := "triggerID-0000000000001"
+		history := []*moira.TriggerHistoryItem{
+			{Version: 2, Trigger: moira.Trigger{ID: triggerID, Name: "new"}},
+			{Version: 1, Trigger: moira.Trigger{ID: triggerID, Name: "old"}},
+		}
+
+		newRequest := func(version, query string) *http.Request {
+			testRequest := httptest.NewRequest(http.MethodGet, "/trigger/"+triggerID+"/history/"+version+"/diff"+query, nil)
+			routeContext := chi.NewRouteContext()
+			routeContext.URLParams.Add("version", version)
+			ctx := context.WithValue(testRequest.Context(), chi.RouteCtxKey, routeContext)
+			ctx = middleware.SetContextValueForTest(ctx, triggerIDKey, triggerID)
+			return testRequest.WithContext(ctx)
+		}
+
+		Convey("Success", func() {
+			mockDb.EXPECT().GetTriggerHistory(triggerID).Return(history, nil)
+
+			getTriggerHistoryDiff(responseWriter, newRequest("2", ""))
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+
+			contentBytes, _ := io.ReadAll(response.Body)
+			actual := dto.TriggerHistoryDiff{}
+			err := json.Unmarshal(contentBytes, &actual)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, dto.TriggerHistoryDiff{
+				TriggerID: triggerID,
+				Version:   2,
+				CompareTo: 1,
+				Changes:   []dto.TriggerFieldChange{{Field: "name", Old: "old", New: "new"}},
+			})
+		})
+
+		Convey("Invalid version", func() {
+			getTriggerHistoryDiff(responseWriter, newRequest("first", ""))
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Invalid compare_to", func() {
+			getTriggerHistoryDiff(responseWriter, newRequest("2", "?compare_to=-1"))
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Unknown version", func() {
+			mockDb.EXPECT().GetTriggerHistory(triggerID).Return(history, nil)
+
+			getTriggerHistoryDiff(responseWriter, newRequest("3", ""))
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusNotFound)
+		})
+	})
+}
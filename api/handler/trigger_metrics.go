@@ -10,15 +10,17 @@ import (
 	"github.com/go-chi/render"
 	"github.com/go-graphite/carbonapi/date"
 
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/controller"
 	"github.com/moira-alert/moira/api/middleware"
 )
 
 func triggerMetrics(router chi.Router) {
-	router.With(middleware.DateRange("-10minutes", "now")).Get("/", getTriggerMetrics)
-	router.Delete("/", deleteTriggerMetric)
-	router.Delete("/nodata", deleteTriggerNodataMetrics)
+	router.With(triggersReadScope, middleware.DateRange("-10minutes", "now")).Get("/", getTriggerMetrics)
+	router.With(triggersScope).Delete("/", deleteTriggerMetric)
+	router.With(triggersScope).Delete("/nodata", deleteTriggerNodataMetrics)
+	router.With(triggersReadScope, middleware.DateRange("-1day", "now")).Get("/{metric}/timeline", getTriggerMetricTimeline)
 }
 
 // nolint: gofmt,goimports
@@ -89,7 +91,9 @@ func deleteTriggerMetric(writer http.ResponseWriter, request *http.Request) {
 	metricName := urlValues.Get("name")
 	if err := controller.DeleteTriggerMetric(database, metricName, triggerID); err != nil {
 		render.Render(writer, request, err) //nolint
+		return
 	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectTriggerMetrics, triggerID, map[string]string{"name": metricName}, nil)
 }
 
 // nolint: gofmt,goimports
@@ -108,5 +112,56 @@ func deleteTriggerNodataMetrics(writer http.ResponseWriter, request *http.Reques
 	triggerID := middleware.GetTriggerID(request)
 	if err := controller.DeleteTriggerNodataMetrics(database, triggerID); err != nil {
 		render.Render(writer, request, err) //nolint
+		return
+	}
+	recordAudit(request, moira.AuditActionDelete, moira.AuditObjectTriggerMetrics, triggerID, nil, nil)
+}
+
+// nolint: gofmt,goimports
+//
+//	@summary	Get intervals of metric states
+//	@id			get-trigger-metric-timeline
+//	@tags		trigger
+//	@produce	json
+//	@param		triggerID	path		string							true	"Trigger ID"						default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
+//	@param		metric		path		string							true	"URL encoded metric name"			default(DevOps.my_server.hdd.freespace_mbytes)
+//	@param		from		query		string							false	"Start time of timeline"			default(-1day)
+//	@param		to			query		string							false	"End time of timeline"				default(now)
+//	@success	200			{object}	dto.MetricStateTimeline			"Metric state timeline retrieved successfully"
+//	@failure	400			{object}	api.ErrorInvalidRequestExample	"Bad request from client"
+//	@failure	404			{object}	api.ErrorNotFoundExample		"Resource not found"
+//	@failure	422			{object}	api.ErrorRenderExample			"Render error"
+//	@failure	500			{object}	api.ErrorInternalServerExample	"Internal server error"
+//	@router		/trigger/{triggerID}/metrics/{metric}/timeline [get]
+func getTriggerMetricTimeline(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	metric, err := url.PathUnescape(chi.URLParam(request, "metric"))
+	if err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("failed to parse metric: %w", err))) //nolint
+		return
+	}
+
+	fromStr := middleware.GetFromStr(request)
+	from := date.DateParamToEpoch(fromStr, "UTC", 0, time.UTC)
+	if from == 0 {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("can not parse from: %s", fromStr))) //nolint
+		return
+	}
+
+	toStr := middleware.GetToStr(request)
+	to := date.DateParamToEpoch(toStr, "UTC", 0, time.UTC)
+	if to == 0 {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("can not parse to: %s", toStr))) //nolint
+		return
+	}
+
+	timeline, errorResponse := controller.GetTriggerMetricTimeline(database, triggerID, metric, from, to)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+
+	if err := render.Render(writer, request, timeline); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
 	}
 }
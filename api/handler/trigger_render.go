@@ -24,6 +24,7 @@ import (
 //	@id			render-trigger-metrics
 //	@tags		trigger
 //	@produce	png
+//	@produce	image/svg+xml
 //	@param		triggerID	path	string	true	"Trigger ID"						default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
 //	@param		target		query	string	false	"Target metric name"				default(t1)
 //	@param		from		query	string	false	"Start time for metrics retrieval"	default(-1hour)
@@ -31,6 +32,12 @@ import (
 //	@param		timezone	query	string	false	"Timezone for rendering"			default(UTC)
 //	@param		theme		query	string	false	"Plot theme"						default(light)
 //	@param		realtime	query	bool	false	"Fetch real-time data"				default(false)
+//	@param		metric		query	string	false	"Render only this metric and shade its state intervals"	default(DevOps.my_server.hdd.freespace_mbytes)
+//	@param		width		query	integer	false	"Plot width in pixels"									default(800)
+//	@param		height		query	integer	false	"Plot height in pixels"									default(400)
+//	@param		format		query	string	false	"Image format"											Enums(png, svg)	default(png)
+//	@param		overlay		query	bool	false	"Draw all targets on one plot, target parameter is ignored"	default(false)
+//	@param		events		query	bool	false	"Mark state changes and maintenance periods of trigger events"	default(false)
 //	@success	200			"Rendered plot image successfully"
 //	@failure	400			{object}	api.ErrorInvalidRequestExample	"Bad request from client"
 //	@failure	404			{object}	api.ErrorNotFoundExample		"Resource not found"
@@ -42,6 +49,11 @@ func renderTrigger(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
 		return
 	}
+	plottingData, overlayEvents, err := getRenderPlottingData(request)
+	if err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
+		return
+	}
 	metricsData, trigger, err := evaluateTargetMetrics(sourceProvider, from, to, triggerID, fetchRealtimeData)
 	if err != nil {
 		if trigger == nil {
@@ -52,23 +64,112 @@ func renderTrigger(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
-	targetMetrics, ok := metricsData[targetName]
-	if !ok {
-		render.Render(writer, request, api.ErrorNotFound(fmt.Sprintf("Cannot find target %s", targetName))) //nolint
+	if !plottingData.Overlay {
+		targetMetrics, ok := metricsData[targetName]
+		if !ok {
+			render.Render(writer, request, api.ErrorNotFound(fmt.Sprintf("Cannot find target %s", targetName))) //nolint
+			return
+		}
+		metricsData = map[string][]metricSource.MetricData{targetName: targetMetrics}
 	}
 
-	renderable, err := buildRenderable(request, trigger, targetMetrics, targetName)
+	var annotations plotting.Annotations
+	metric := request.URL.Query().Get("metric")
+	if metric != "" {
+		for name, metrics := range metricsData {
+			metricsData[name] = filterMetricData(metrics, metric)
+		}
+		timeline, errorResponse := controller.GetTriggerMetricTimeline(database, triggerID, metric, from, to)
+		if errorResponse != nil {
+			render.Render(writer, request, errorResponse) //nolint
+			return
+		}
+		annotations.StateIntervals = timeline.Intervals
+	}
+
+	if overlayEvents {
+		eventsAnnotations, err := getRenderEventsAnnotations(triggerID, metric, from, to)
+		if err != nil {
+			render.Render(writer, request, api.ErrorInternalServer(err)) //nolint
+			return
+		}
+		annotations.StateMarkers = eventsAnnotations.StateMarkers
+		annotations.StateIntervals = append(annotations.StateIntervals, eventsAnnotations.StateIntervals...)
+	}
+
+	renderable, err := buildRenderable(request, trigger, metricsData, targetName, plottingData, annotations)
 	if err != nil {
 		render.Render(writer, request, api.ErrorInternalServer(err)) //nolint
 		return
 	}
-	writer.Header().Set("Content-Type", "image/png")
-	err = renderable.Render(chart.PNG, writer)
+	setPlotHeaders(writer, plottingData.Format)
+	err = plotting.Render(*renderable, plottingData.Format, writer)
 	if err != nil {
 		render.Render(writer, request, api.ErrorInternalServer(fmt.Errorf("can not render plot %s", err.Error()))) //nolint
 	}
 }
 
+// setPlotHeaders sets content type of plot. SVG opened by browser is a document which can run scripts,
+// so it is sent as attachment with content security policy which forbids them.
+func setPlotHeaders(writer http.ResponseWriter, format moira.PlotFormat) {
+	writer.Header().Set("Content-Type", format.ContentType())
+	if format == moira.PlotFormatSVG {
+		writer.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+		writer.Header().Set("Content-Disposition", "attachment")
+	}
+}
+
+// getRenderPlottingData returns plot size, format and overlay from request and whether trigger events should be marked on plot.
+func getRenderPlottingData(request *http.Request) (moira.PlottingData, bool, error) {
+	urlValues := request.URL.Query()
+	plottingData := moira.PlottingData{
+		Format: moira.PlotFormat(urlValues.Get("format")),
+	}
+
+	var err error
+	if width := urlValues.Get("width"); width != "" {
+		if plottingData.Width, err = strconv.Atoi(width); err != nil {
+			return plottingData, false, fmt.Errorf("invalid width param: %s", err.Error())
+		}
+	}
+	if height := urlValues.Get("height"); height != "" {
+		if plottingData.Height, err = strconv.Atoi(height); err != nil {
+			return plottingData, false, fmt.Errorf("invalid height param: %s", err.Error())
+		}
+	}
+	if overlay := urlValues.Get("overlay"); overlay != "" {
+		if plottingData.Overlay, err = strconv.ParseBool(overlay); err != nil {
+			return plottingData, false, fmt.Errorf("invalid overlay param: %s", err.Error())
+		}
+	}
+
+	var overlayEvents bool
+	if events := urlValues.Get("events"); events != "" {
+		if overlayEvents, err = strconv.ParseBool(events); err != nil {
+			return plottingData, false, fmt.Errorf("invalid events param: %s", err.Error())
+		}
+	}
+
+	return plottingData, overlayEvents, plottingData.Validate()
+}
+
+// getRenderEventsAnnotations returns state changes and maintenance periods of trigger events, events of other metrics are skipped if metric is set.
+func getRenderEventsAnnotations(triggerID, metric string, from, to int64) (plotting.Annotations, error) {
+	historyEvents, err := database.GetNotificationEventsByTime(triggerID, from, to)
+	if err != nil {
+		return plotting.Annotations{}, err
+	}
+
+	events := make([]moira.NotificationEvent, 0, len(historyEvents))
+	for _, event := range historyEvents {
+		if metric != "" && event.Metric != metric {
+			continue
+		}
+		events = append(events, *event)
+	}
+	return plotting.GetEventsAnnotations(events), nil
+}
+
 func getEvaluationParameters(request *http.Request) (sourceProvider *metricSource.SourceProvider, targetName string, from int64, to int64, triggerID string, fetchRealtimeData bool, err error) {
 	sourceProvider = middleware.GetTriggerTargetsSourceProvider(request)
 	targetName = middleware.GetTargetName(request)
@@ -113,7 +214,24 @@ func evaluateTargetMetrics(metricSourceProvider *metricSource.SourceProvider, fr
 	return tts, trigger, err
 }
 
-func buildRenderable(request *http.Request, trigger *moira.Trigger, metricsData []metricSource.MetricData, targetName string) (*chart.Chart, error) {
+func filterMetricData(metricsData []metricSource.MetricData, metric string) []metricSource.MetricData {
+	filtered := make([]metricSource.MetricData, 0, 1)
+	for _, metricData := range metricsData {
+		if metricData.Name == metric {
+			filtered = append(filtered, metricData)
+		}
+	}
+	return filtered
+}
+
+func buildRenderable(
+	request *http.Request,
+	trigger *moira.Trigger,
+	metricsData map[string][]metricSource.MetricData,
+	targetName string,
+	plottingData moira.PlottingData,
+	annotations plotting.Annotations,
+) (*chart.Chart, error) {
 	urlValues, err := url.ParseQuery(request.URL.RawQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse query string: %w", err)
@@ -130,8 +248,14 @@ func buildRenderable(request *http.Request, trigger *moira.Trigger, metricsData
 	if err != nil {
 		return nil, fmt.Errorf("can not initialize plot theme %s", err.Error())
 	}
+	plotTemplate = plotTemplate.WithSize(plottingData.Width, plottingData.Height)
 
-	renderable, err := plotTemplate.GetRenderable(targetName, trigger, metricsData)
+	var renderable chart.Chart
+	if plottingData.Overlay {
+		renderable, err = plotTemplate.GetOverlayRenderable(trigger, metricsData, annotations)
+	} else {
+		renderable, err = plotTemplate.GetRenderableWithAnnotations(targetName, trigger, metricsData[targetName], annotations)
+	}
 	if err != nil {
 		return nil, err
 	}
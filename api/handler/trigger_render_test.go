@@ -134,5 +134,79 @@ func TestRenderTrigger(t *testing.T) {
 			So(contents, ShouldEqual, expected)
 			So(response.StatusCode, ShouldEqual, http.StatusBadRequest)
 		})
+
+		Convey("with the wrong format parameter", func() {
+			testRequest := httptest.NewRequest(http.MethodGet, "/trigger/triggerID-0000000000001/render?format=gif", nil)
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "triggerID", "triggerID-0000000000001"))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "metricSourceProvider", sourceProvider))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "target", "t1"))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "from", "-1hour"))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "to", "now"))
+
+			renderTrigger(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			contentBytes, _ := io.ReadAll(response.Body)
+			contents := string(contentBytes)
+			expected := `{"status":"Invalid request","error":"unknown plot format 'gif', use png or svg"}
+`
+
+			So(contents, ShouldEqual, expected)
+			So(response.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("with the wrong width parameter", func() {
+			testRequest := httptest.NewRequest(http.MethodGet, "/trigger/triggerID-0000000000001/render?width=10", nil)
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "triggerID", "triggerID-0000000000001"))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "metricSourceProvider", sourceProvider))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "target", "t1"))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "from", "-1hour"))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "to", "now"))
+
+			renderTrigger(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			So(response.StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("with svg format and overlay", func() {
+			mockDb.EXPECT().GetTrigger("triggerID-0000000000001").Return(moira.Trigger{
+				ID:            "triggerID-0000000000001",
+				Name:          "<script>alert(1)</script>",
+				Targets:       []string{"t1"},
+				TriggerSource: moira.GraphiteLocal,
+				ClusterId:     moira.DefaultCluster,
+			}, nil).Times(1)
+			fetchResult := mock_metric_source.NewMockFetchResult(mockCtrl)
+			fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData("metric", []float64{1, 2, 3}, 60, 0)}).Times(1)
+			localSource.EXPECT().Fetch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fetchResult, nil).Times(1)
+			mockDb.EXPECT().GetNotificationEventsByTime("triggerID-0000000000001", gomock.Any(), gomock.Any()).Return([]*moira.NotificationEvent{
+				{Timestamp: 60, Metric: "metric", State: moira.StateWARN, OldState: moira.StateOK},
+			}, nil).Times(1)
+
+			database = mockDb
+
+			testRequest := httptest.NewRequest(http.MethodGet, "/trigger/triggerID-0000000000001/render?format=svg&overlay=true&events=true&width=600&height=300", nil)
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "triggerID", "triggerID-0000000000001"))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "metricSourceProvider", sourceProvider))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "target", "t1"))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "from", "-1hour"))
+			testRequest = testRequest.WithContext(middleware.SetContextValueForTest(testRequest.Context(), "to", "now"))
+
+			renderTrigger(responseWriter, testRequest)
+
+			response := responseWriter.Result()
+			defer response.Body.Close()
+			contentBytes, _ := io.ReadAll(response.Body)
+
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+			So(response.Header.Get("Content-Type"), ShouldEqual, "image/svg+xml")
+			So(response.Header.Get("Content-Security-Policy"), ShouldEqual, "default-src 'none'; style-src 'unsafe-inline'")
+			So(response.Header.Get("Content-Disposition"), ShouldEqual, "attachment")
+			So(moira.GetPlotFormat(contentBytes), ShouldEqual, moira.PlotFormatSVG)
+			So(string(contentBytes), ShouldNotContainSubstring, "<script>")
+			So(string(contentBytes), ShouldContainSubstring, "&lt;script&gt;alert(1)&lt;/script&gt;")
+		})
 	})
 }
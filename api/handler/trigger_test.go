@@ -158,7 +158,8 @@ func TestUpdateTrigger(t *testing.T) {
 				TriggerSource: moira.GraphiteLocal,
 				ClusterId:     moira.DefaultCluster,
 			}
-			mockDb.EXPECT().GetTrigger(gomock.Any()).Return(trigger, nil)
+			mockDb.EXPECT().GetTrigger(gomock.Any()).Return(trigger, nil).Times(2)
+			mockDb.EXPECT().GetTriggerThrottling(gomock.Any())
 
 			jsonTrigger, _ := json.Marshal(trigger)
 			testRequest := httptest.NewRequest("", url, bytes.NewBuffer(jsonTrigger))
@@ -235,7 +236,8 @@ func TestUpdateTrigger(t *testing.T) {
 		jsonTrigger, _ := json.Marshal(trigger)
 
 		Convey("without validate like before", func() {
-			mockDb.EXPECT().GetTrigger(gomock.Any()).Return(trigger, nil)
+			mockDb.EXPECT().GetTrigger(gomock.Any()).Return(trigger, nil).Times(2)
+			mockDb.EXPECT().GetTriggerThrottling(gomock.Any())
 			mockDb.EXPECT().AcquireTriggerCheckLock(gomock.Any(), gomock.Any()).Return(nil)
 			mockDb.EXPECT().DeleteTriggerCheckLock(gomock.Any())
 			mockDb.EXPECT().GetTriggerLastCheck(gomock.Any())
@@ -260,7 +262,8 @@ func TestUpdateTrigger(t *testing.T) {
 		})
 
 		Convey("with validate", func() {
-			mockDb.EXPECT().GetTrigger(gomock.Any()).Return(trigger, nil)
+			mockDb.EXPECT().GetTrigger(gomock.Any()).Return(trigger, nil).Times(2)
+			mockDb.EXPECT().GetTriggerThrottling(gomock.Any())
 			mockDb.EXPECT().AcquireTriggerCheckLock(gomock.Any(), gomock.Any()).Return(nil)
 			mockDb.EXPECT().DeleteTriggerCheckLock(gomock.Any())
 			mockDb.EXPECT().GetTriggerLastCheck(gomock.Any())
@@ -323,7 +326,8 @@ func TestUpdateTrigger(t *testing.T) {
 		jsonTrigger, _ := json.Marshal(trigger)
 
 		Convey("without validate like before", func() {
-			mockDb.EXPECT().GetTrigger(gomock.Any()).Return(trigger, nil)
+			mockDb.EXPECT().GetTrigger(gomock.Any()).Return(trigger, nil).Times(2)
+			mockDb.EXPECT().GetTriggerThrottling(gomock.Any())
 			mockDb.EXPECT().AcquireTriggerCheckLock(gomock.Any(), gomock.Any()).Return(nil)
 			mockDb.EXPECT().DeleteTriggerCheckLock(gomock.Any())
 			mockDb.EXPECT().GetTriggerLastCheck(gomock.Any())
@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/go-chi/chi"
@@ -28,16 +27,17 @@ func triggers(metricSourceProvider *metricSource.SourceProvider, searcher moira.
 		router.Use(middleware.MetricSourceProvider(metricSourceProvider))
 		router.Use(middleware.SearchIndexContext(searcher))
 
-		router.With(middleware.AdminOnlyMiddleware()).Get("/", getAllTriggers)
-		router.With(middleware.AdminOnlyMiddleware()).Get("/unused", getUnusedTriggers)
+		router.With(middleware.AdminOnlyMiddleware(), readScope).Get("/", getAllTriggers)
+		router.With(middleware.AdminOnlyMiddleware(), readScope).Get("/unused", getUnusedTriggers)
+		router.With(triggersReadScope).Get("/deleted", getDeletedTriggers)
 
-		router.Put("/", createTrigger)
-		router.Put("/check", triggerCheck)
+		router.With(triggersScope).Put("/", createTrigger)
+		router.With(triggersScope).Put("/check", triggerCheck)
 		router.Route("/{triggerId}", trigger)
-		router.With(middleware.Paginate(0, 10)).With(middleware.Pager(false, "")).Get("/search", searchTriggers)
-		router.With(middleware.Pager(false, "")).Delete("/search/pager", deletePager)
+		router.With(triggersReadScope, middleware.Paginate(0, 10)).With(middleware.Pager(false, "")).Get("/search", searchTriggers)
+		router.With(triggersScope, middleware.Pager(false, "")).Delete("/search/pager", deletePager)
 		// ToDo: DEPRECATED method. Remove in Moira 2.6
-		router.With(middleware.Paginate(0, 10)).With(middleware.Pager(false, "")).Get("/page", searchTriggers)
+		router.With(triggersReadScope, middleware.Paginate(0, 10)).With(middleware.Pager(false, "")).Get("/page", searchTriggers)
 	}
 }
 
@@ -99,6 +99,7 @@ func getUnusedTriggers(writer http.ResponseWriter, request *http.Request) {
 //	@param		trigger		body		dto.Trigger								true	"Trigger data"
 //	@success	200			{object}	dto.SaveTriggerResponse					"Trigger created successfully"
 //	@failure	400			{object}	api.ErrorInvalidRequestExample			"Bad request from client"
+//	@failure	403			{object}	api.ErrorForbiddenExample				"Forbidden"
 //	@failure	422			{object}	api.ErrorRenderExample					"Render error"
 //	@failure	500			{object}	api.ErrorInternalServerExample			"Internal server error"
 //	@failure	503			{object}	api.ErrorRemoteServerUnavailableExample	"Remote server unavailable"
@@ -110,6 +111,11 @@ func createTrigger(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
+	if err = checkTriggerTeamPermissions(request, trigger.TeamID); err != nil {
+		render.Render(writer, request, err) //nolint
+		return
+	}
+
 	var problems []dto.TreeOfProblems
 	if needValidate(request) {
 		problems, err = validateTargets(request, trigger)
@@ -139,6 +145,7 @@ func createTrigger(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, err) //nolint
 		return
 	}
+	recordAudit(request, moira.AuditActionCreate, moira.AuditObjectTrigger, response.ID, nil, &trigger.TriggerModel)
 
 	if problems != nil {
 		response.CheckResult.Targets = problems
@@ -153,31 +160,36 @@ func createTrigger(writer http.ResponseWriter, request *http.Request) {
 func getTriggerFromRequest(request *http.Request) (*dto.Trigger, *api.ErrorResponse) {
 	trigger := &dto.Trigger{}
 	if err := render.Bind(request, trigger); err != nil {
-		switch err.(type) { // nolint:errorlint
-		case local.ErrParseExpr, local.ErrEvalExpr, local.ErrUnknownFunction:
-			return nil, api.ErrorInvalidRequest(fmt.Errorf("invalid graphite targets: %s", err.Error()))
-		case expression.ErrInvalidExpression:
-			return nil, api.ErrorInvalidRequest(fmt.Errorf("invalid expression: %s", err.Error()))
-		case api.ErrInvalidRequestContent:
-			return nil, api.ErrorInvalidRequest(err)
-		case remote.ErrRemoteTriggerResponse:
-			response := api.ErrorRemoteServerUnavailable(err)
-			middleware.GetLoggerEntry(request).Error().
-				String("status", response.StatusText).
-				Error(err).
-				Msg("Remote server unavailable")
-			return nil, response
-		case *json.UnmarshalTypeError:
-			return nil, api.ErrorInvalidRequest(fmt.Errorf("invalid payload: %s", err.Error()))
-		default:
-			return nil, api.ErrorInternalServer(err)
-		}
+		return nil, getTriggerBindErrorResponse(request, err)
 	}
 	trigger.UpdatedBy = middleware.GetLogin(request)
 
 	return trigger, nil
 }
 
+// getTriggerBindErrorResponse converts error returned by trigger binding to api error response.
+func getTriggerBindErrorResponse(request *http.Request, err error) *api.ErrorResponse {
+	switch err.(type) { // nolint:errorlint
+	case local.ErrParseExpr, local.ErrEvalExpr, local.ErrUnknownFunction:
+		return api.ErrorInvalidRequest(fmt.Errorf("invalid graphite targets: %s", err.Error()))
+	case expression.ErrInvalidExpression:
+		return api.ErrorInvalidRequest(fmt.Errorf("invalid expression: %s", err.Error()))
+	case api.ErrInvalidRequestContent:
+		return api.ErrorInvalidRequest(err)
+	case remote.ErrRemoteTriggerResponse:
+		response := api.ErrorRemoteServerUnavailable(err)
+		middleware.GetLoggerEntry(request).Error().
+			String("status", response.StatusText).
+			Error(err).
+			Msg("Remote server unavailable")
+		return response
+	case *json.UnmarshalTypeError:
+		return api.ErrorInvalidRequest(fmt.Errorf("invalid payload: %s", err.Error()))
+	default:
+		return api.ErrorInternalServer(err)
+	}
+}
+
 // getMetricTTLByTrigger gets metric ttl duration time from request context for local or remote trigger.
 func getMetricTTLByTrigger(request *http.Request, trigger *dto.Trigger) (time.Duration, error) {
 	metricTTLs := middleware.GetMetricTTL(request)
@@ -241,6 +253,8 @@ func triggerCheck(writer http.ResponseWriter, request *http.Request) {
 //	@summary		Search triggers. Replaces the deprecated `page` path
 //	@description	You can also add filtering by tags, for this purpose add query parameters tags[0]=test, tags[1]=test1 and so on
 //	@description	For example, `/api/trigger/search?tags[0]=test&tags[1]=test1`
+//	@description	Search text supports filters: `state:ERROR tag:db -tag:test target:"*.cpu.*" pattern:my.metric source:prometheus_remote cluster:default created_by:alice updated_by:alice updated:>7d`
+//	@description	Filter with `-` prefix excludes matching triggers, `updated` accepts `>` or `<` followed by duration (m, h, d, w) or date (YYYY-MM-DD)
 //	@id				search-triggers
 //	@tags			trigger
 //	@produce		json
@@ -251,6 +265,7 @@ func triggerCheck(writer http.ResponseWriter, request *http.Request) {
 //	@param			createPager		query		boolean							false	"Create pager"			default(false)
 //	@param			pagerID			query		string							false	"Pager ID"				default(bcba82f5-48cf-44c0-b7d6-e1d32c64a88c)
 //	@param			createdBy		query		string							false	"Created By"			default(moira.team)
+//	@param			sort			query		string							false	"Sort order"			Enums(score, name, last_event)	default(score)
 //	@success		200				{object}	dto.TriggersList				"Successfully fetched matching triggers"
 //	@failure		400				{object}	api.ErrorInvalidRequestExample	"Bad request from client"
 //	@failure		404				{object}	api.ErrorNotFoundExample		"Resource not found"
@@ -260,6 +275,12 @@ func triggerCheck(writer http.ResponseWriter, request *http.Request) {
 func searchTriggers(writer http.ResponseWriter, request *http.Request) {
 	request.ParseForm() //nolint
 
+	sortBy, err := getSearchSortBy(request)
+	if err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
+		return
+	}
+
 	createdBy, ok := getTriggerCreatedBy(request)
 	searchOptions := moira.SearchOptions{
 		Page:                  middleware.GetPage(request),
@@ -271,6 +292,7 @@ func searchTriggers(writer http.ResponseWriter, request *http.Request) {
 		NeedSearchByCreatedBy: ok,
 		CreatePager:           middleware.GetCreatePager(request),
 		PagerID:               middleware.GetPagerID(request),
+		SortBy:                sortBy,
 	}
 
 	triggersList, errorResponse := controller.SearchTriggers(database, searchIndex, searchOptions)
@@ -313,17 +335,22 @@ func deletePager(writer http.ResponseWriter, request *http.Request) {
 }
 
 func getRequestTags(request *http.Request) []string {
-	var filterTags []string
+	return getRequestList(request, "tags")
+}
+
+// getRequestList returns values of form list given as name[0], name[1], ...
+func getRequestList(request *http.Request, name string) []string {
+	var values []string
 	i := 0
 	for {
-		tag := request.FormValue(fmt.Sprintf("tags[%v]", i))
-		if tag == "" {
+		value := request.FormValue(fmt.Sprintf("%s[%v]", name, i))
+		if value == "" {
 			break
 		}
-		filterTags = append(filterTags, tag)
+		values = append(values, value)
 		i++
 	}
-	return filterTags
+	return values
 }
 
 func getOnlyProblemsFlag(request *http.Request) bool {
@@ -345,9 +372,22 @@ func getTriggerCreatedBy(request *http.Request) (string, bool) {
 	return "", false
 }
 
+// getSearchRequestString returns search text as is, because filter values like tags are case-sensitive.
+// Free text terms are lowercased by search index.
 func getSearchRequestString(request *http.Request) string {
 	searchText := request.FormValue("text")
-	searchText = strings.ToLower(searchText)
 	searchText, _ = url.PathUnescape(searchText)
 	return searchText
 }
+
+func getSearchSortBy(request *http.Request) (moira.SearchSortBy, error) {
+	sortBy := moira.SearchSortBy(request.FormValue("sort"))
+	switch sortBy {
+	case "":
+		return moira.SortByScore, nil
+	case moira.SortByScore, moira.SortByName, moira.SortByLastEvent:
+		return sortBy, nil
+	default:
+		return "", fmt.Errorf("unknown sort order '%s', use one of: %s, %s, %s", sortBy, moira.SortByScore, moira.SortByName, moira.SortByLastEvent)
+	}
+}
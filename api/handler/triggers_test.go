@@ -28,15 +28,15 @@ import (
 
 func TestGetSearchRequestString(t *testing.T) {
 	Convey("Given a search request string", t, func() {
-		Convey("The value should be converted into lower case", func() {
+		Convey("The value should be kept as is, so filter values stay case-sensitive", func() {
 			testCases := []struct {
 				text                  string
 				expectedSearchRequest string
 			}{
 				{"query", "query"},
-				{"QUERY", "query"},
-				{"Query", "query"},
-				{"QueRy", "query"},
+				{"QUERY", "QUERY"},
+				{"Query", "Query"},
+				{"tag:DB%20state:ERROR", "tag:DB state:ERROR"},
 			}
 			for _, testCase := range testCases {
 				req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, fmt.Sprintf("/api/trigger/search?onlyProblems=false&p=0&size=20&text=%s", testCase.text), nil)
@@ -47,6 +47,35 @@ func TestGetSearchRequestString(t *testing.T) {
 	})
 }
 
+func TestGetSearchSortBy(t *testing.T) {
+	Convey("Given a search request sort order", t, func() {
+		testCases := []struct {
+			query          string
+			expectedSortBy moira.SearchSortBy
+		}{
+			{"", moira.SortByScore},
+			{"&sort=score", moira.SortByScore},
+			{"&sort=name", moira.SortByName},
+			{"&sort=last_event", moira.SortByLastEvent},
+		}
+
+		Convey("Known sort orders should be parsed", func() {
+			for _, testCase := range testCases {
+				req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "/api/trigger/search?p=0"+testCase.query, nil)
+				sortBy, err := getSearchSortBy(req)
+				So(err, ShouldBeNil)
+				So(sortBy, ShouldEqual, testCase.expectedSortBy)
+			}
+		})
+
+		Convey("Unknown sort order should return an error", func() {
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "/api/trigger/search?sort=random", nil)
+			_, err := getSearchSortBy(req)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
 func TestGetTriggerFromRequest(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -12,8 +12,9 @@ import (
 )
 
 func user(router chi.Router) {
-	router.Get("/", getUserName)
-	router.Get("/settings", getUserSettings)
+	router.With(readScope).Get("/", getUserName)
+	router.With(readScope).Get("/settings", getUserSettings)
+	router.Route("/tokens", userAPITokens)
 }
 
 // nolint: gofmt,goimports
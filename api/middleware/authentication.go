@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/database"
+)
+
+const bearerPrefix = "bearer "
+
+// Authentication identifies user by moira api token or by bearer token if it is enabled in authorization configuration.
+// Request without token is identified by x-webauth-user header set in UserContext only if header fallback is allowed.
+// Members of admin groups get admin privileges and members of mapped groups are added to corresponding teams.
+// Teams of user are synced with groups only when user groups change, see syncUserGroupTeams.
+// It must be used after DatabaseContext and AuthorizationContext.
+func Authentication(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		auth := GetAuth(request)
+		token, ok := getBearerToken(request)
+		if ok && api.IsAPIToken(token) {
+			authenticateAPIToken(writer, request, next, token)
+			return
+		}
+
+		if !auth.IsTokenEnabled() {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		if !ok {
+			if auth.IsHeaderEnabled() {
+				next.ServeHTTP(writer, request)
+				return
+			}
+			render.Render(writer, request, api.ErrorUnauthorized("bearer token is required")) //nolint:errcheck
+			return
+		}
+
+		login, groups, err := auth.Authentication.TokenVerifier.Verify(token)
+		if err != nil {
+			render.Render(writer, request, api.ErrorUnauthorized(err.Error())) //nolint:errcheck
+			return
+		}
+
+		if err = syncUserGroupTeams(GetDatabase(request), auth, login, groups); err != nil {
+			render.Render(writer, request, api.ErrorInternalServer(err)) //nolint:errcheck
+			return
+		}
+
+		ctx := context.WithValue(request.Context(), loginKey, login)
+		if auth.IsAdminGroup(groups) {
+			ctx = context.WithValue(ctx, authKey, auth.WithAdmin(login))
+		}
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// authenticateAPIToken identifies request by moira api token. Token scopes are checked by RequireScope of routes.
+func authenticateAPIToken(writer http.ResponseWriter, request *http.Request, next http.Handler, token string) {
+	tokenID, secret, ok := api.ParseAPIToken(token)
+	if !ok {
+		render.Render(writer, request, api.ErrorUnauthorized("malformed api token")) //nolint:errcheck
+		return
+	}
+
+	apiToken, err := GetDatabase(request).GetAPIToken(tokenID)
+	if err != nil {
+		if errors.Is(err, database.ErrNil) {
+			render.Render(writer, request, api.ErrorUnauthorized("unknown api token")) //nolint:errcheck
+			return
+		}
+		render.Render(writer, request, api.ErrorInternalServer(err)) //nolint:errcheck
+		return
+	}
+
+	if !api.IsAPITokenSecretValid(secret, apiToken.SecretHash) {
+		render.Render(writer, request, api.ErrorUnauthorized("unknown api token")) //nolint:errcheck
+		return
+	}
+
+	if apiToken.IsExpired(time.Now()) {
+		render.Render(writer, request, api.ErrorUnauthorized("api token is expired")) //nolint:errcheck
+		return
+	}
+
+	ctx := context.WithValue(request.Context(), loginKey, apiToken.GetLogin())
+	ctx = context.WithValue(ctx, apiTokenKey, &apiToken)
+	next.ServeHTTP(writer, request.WithContext(ctx))
+}
+
+func getBearerToken(request *http.Request) (string, bool) {
+	header := request.Header.Get("Authorization")
+	if len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(bearerPrefix):]), true
+}
+
+// syncedGroupTeams keeps teams mapped from groups of users which were synced by this process.
+var syncedGroupTeams sync.Map
+
+// syncUserGroupTeams makes groups of identity provider authoritative for membership in mapped teams:
+// user is added to teams mapped from user groups and removed from teams which were mapped from them before.
+// Database is changed only when teams mapped from user groups differ from the ones synced last time,
+// so membership changed by team owners is kept while user groups stay the same.
+func syncUserGroupTeams(dataBase moira.Database, auth *api.Authorization, login string, groups []string) error {
+	if len(auth.Authentication.TeamGroups) == 0 {
+		return nil
+	}
+
+	teamIDs := auth.GetGroupsTeams(groups)
+	slices.Sort(teamIDs)
+	fingerprint := strings.Join(teamIDs, ",")
+	if synced, ok := syncedGroupTeams.Load(login); ok && synced == fingerprint {
+		return nil
+	}
+
+	if err := dataBase.SyncUserGroupTeams(login, teamIDs); err != nil {
+		return fmt.Errorf("cannot sync user teams: %w", err)
+	}
+	syncedGroupTeams.Store(login, fingerprint)
+	return nil
+}
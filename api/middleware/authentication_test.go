@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/database"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testTokenVerifier struct{}
+
+func (testTokenVerifier) Verify(token string) (string, []string, error) {
+	switch token {
+	case "admin-token":
+		return "admin", []string{"admins"}, nil
+	case "devops-token":
+		return "john", []string{"devops"}, nil
+	default:
+		return "", nil, errors.New("invalid token signature")
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Test authentication", t, func() {
+		auth := &api.Authorization{
+			Enabled:   true,
+			AdminList: map[string]struct{}{},
+			Authentication: api.Authentication{
+				TokenVerifier: testTokenVerifier{},
+				AdminGroups:   map[string]struct{}{"admins": {}},
+				TeamGroups:    map[string][]string{"devops": {"team1", "team2"}},
+			},
+		}
+
+		var login string
+		var isAdmin bool
+		var apiToken *moira.APIToken
+		handler := DatabaseContext(dataBase)(UserContext(AuthorizationContext(auth)(Authentication(
+			http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				login = GetLogin(request)
+				isAdmin = GetAuth(request).IsAdmin(login)
+				apiToken = GetAPIToken(request)
+			})))))
+
+		performRequest := func(method, path, header, value string) int {
+			login, isAdmin, apiToken = "", false, nil
+			request := httptest.NewRequest(method, path, nil)
+			if header != "" {
+				request.Header.Set(header, value)
+			}
+			responseWriter := httptest.NewRecorder()
+			handler.ServeHTTP(responseWriter, request)
+			return responseWriter.Code
+		}
+		perform := func(header, value string) int {
+			return performRequest(http.MethodGet, "/", header, value)
+		}
+
+		Convey("Member of admin group is admin", func() {
+			syncedGroupTeams.Delete("admin")
+			dataBase.EXPECT().SyncUserGroupTeams("admin", []string{}).Return(nil)
+			So(perform("Authorization", "Bearer admin-token"), ShouldEqual, http.StatusOK)
+			So(login, ShouldEqual, "admin")
+			So(isAdmin, ShouldBeTrue)
+			So(auth.IsAdmin("admin"), ShouldBeFalse)
+		})
+
+		Convey("Member of team group is added to teams", func() {
+			syncedGroupTeams.Delete("john")
+			dataBase.EXPECT().SyncUserGroupTeams("john", []string{"team1", "team2"}).Return(nil)
+
+			So(perform("Authorization", "bearer devops-token"), ShouldEqual, http.StatusOK)
+			So(login, ShouldEqual, "john")
+			So(isAdmin, ShouldBeFalse)
+
+			Convey("Teams are not synced again while groups are the same", func() {
+				So(perform("Authorization", "bearer devops-token"), ShouldEqual, http.StatusOK)
+				So(login, ShouldEqual, "john")
+			})
+
+			Convey("Teams are synced when groups change", func() {
+				auth.Authentication.TeamGroups["devops"] = []string{"team2"}
+				dataBase.EXPECT().SyncUserGroupTeams("john", []string{"team2"}).Return(nil)
+				So(perform("Authorization", "bearer devops-token"), ShouldEqual, http.StatusOK)
+			})
+		})
+
+		Convey("Error of teams sync fails request", func() {
+			syncedGroupTeams.Delete("john")
+			dataBase.EXPECT().SyncUserGroupTeams("john", []string{"team1", "team2"}).Return(errors.New("oops"))
+			So(perform("Authorization", "bearer devops-token"), ShouldEqual, http.StatusInternalServerError)
+		})
+
+		Convey("Invalid token", func() {
+			So(perform("Authorization", "Bearer invalid"), ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Header is not trusted without fallback", func() {
+			So(perform("x-webauth-user", "admin"), ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Header is trusted with fallback", func() {
+			auth.Authentication.HeaderFallback = true
+			So(perform("x-webauth-user", "john"), ShouldEqual, http.StatusOK)
+			So(login, ShouldEqual, "john")
+		})
+
+		Convey("Moira api token", func() {
+			secret := "secret"
+			expiresAt := time.Now().Add(time.Hour).Unix()
+			personal := moira.APIToken{
+				ID:         "personal",
+				User:       "john",
+				Scopes:     []moira.APITokenScope{moira.APITokenScopeTriggers},
+				ExpiresAt:  &expiresAt,
+				SecretHash: api.HashAPITokenSecret(secret),
+			}
+			team := moira.APIToken{
+				ID:         "team",
+				Name:       "ci",
+				TeamID:     "team1",
+				Scopes:     []moira.APITokenScope{moira.TeamScope("team1")},
+				SecretHash: api.HashAPITokenSecret(secret),
+			}
+
+			Convey("Personal token acts as its owner", func() {
+				dataBase.EXPECT().GetAPIToken("personal").Return(personal, nil)
+				So(performRequest(http.MethodPut, "/api/trigger/triggerID", "Authorization", "Bearer "+api.FormatAPIToken("personal", secret)), ShouldEqual, http.StatusOK)
+				So(login, ShouldEqual, "john")
+				So(apiToken, ShouldResemble, &personal)
+			})
+
+			Convey("Team token acts as service account", func() {
+				dataBase.EXPECT().GetAPIToken("team").Return(team, nil)
+				So(performRequest(http.MethodPost, "/api/teams/team1/contacts", "Authorization", "Bearer "+api.FormatAPIToken("team", secret)), ShouldEqual, http.StatusOK)
+				So(login, ShouldEqual, "team:team1/ci")
+			})
+
+			Convey("Invalid secret", func() {
+				dataBase.EXPECT().GetAPIToken("personal").Return(personal, nil)
+				So(perform("Authorization", "Bearer "+api.FormatAPIToken("personal", "other")), ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Expired token", func() {
+				expiresAt = time.Now().Add(-time.Hour).Unix()
+				dataBase.EXPECT().GetAPIToken("personal").Return(personal, nil)
+				So(perform("Authorization", "Bearer "+api.FormatAPIToken("personal", secret)), ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Unknown token", func() {
+				dataBase.EXPECT().GetAPIToken("unknown").Return(moira.APIToken{}, database.ErrNil)
+				So(perform("Authorization", "Bearer "+api.FormatAPIToken("unknown", secret)), ShouldEqual, http.StatusUnauthorized)
+			})
+		})
+
+		Convey("Header is trusted if tokens are disabled", func() {
+			auth.Authentication = api.Authentication{}
+			So(perform("x-webauth-user", "john"), ShouldEqual, http.StatusOK)
+			So(login, ShouldEqual, "john")
+		})
+	})
+}
@@ -70,6 +70,32 @@ func ContactContext(next http.Handler) http.Handler {
 	})
 }
 
+// APITokenIDContext gets tokenId from parsed URI corresponding to api token routes and set it to request context.
+func APITokenIDContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		tokenID := chi.URLParam(request, "tokenId")
+		if tokenID == "" {
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("tokenId must be set"))) //nolint:errcheck
+			return
+		}
+		ctx := context.WithValue(request.Context(), apiTokenIDKey, tokenID)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// DeadLetterIDContext gets deadLetterId from parsed URI corresponding to dead letter routes and set it to request context.
+func DeadLetterIDContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		deadLetterID := chi.URLParam(request, "deadLetterId")
+		if deadLetterID == "" {
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("deadLetterId must be set"))) //nolint:errcheck
+			return
+		}
+		ctx := context.WithValue(request.Context(), deadLetterIDKey, deadLetterID)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
 // TagContext gets tagName from parsed URI corresponding to tag routes and set it to request context.
 func TagContext(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
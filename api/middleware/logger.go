@@ -163,3 +163,11 @@ func (w *responseWriterWithBody) Write(buf []byte) (int, error) {
 	}
 	return n, err
 }
+
+// Flush sends buffered data to client. Flushed responses are streams, so their body is not kept for error logging.
+func (w *responseWriterWithBody) Flush() {
+	w.body.Reset()
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -39,6 +39,10 @@ var (
 	teamIDKey            ContextKey = "teamID"
 	teamUserIDKey        ContextKey = "teamUserIDKey"
 	authKey              ContextKey = "auth"
+	apiTokenKey          ContextKey = "apiToken"
+	apiTokenIDKey        ContextKey = "apiTokenID"
+	deadLetterIDKey      ContextKey = "deadLetterID"
+	scopeGrantedKey      ContextKey = "scopeGranted"
 	anonymousUser                   = "anonymous"
 )
 
@@ -148,6 +152,16 @@ func GetTeamID(request *http.Request) string {
 	return teamID.(string)
 }
 
+// GetAPITokenID gets api token id.
+func GetAPITokenID(request *http.Request) string {
+	return request.Context().Value(apiTokenIDKey).(string)
+}
+
+// GetDeadLetterID gets dead letter id.
+func GetDeadLetterID(request *http.Request) string {
+	return request.Context().Value(deadLetterIDKey).(string)
+}
+
 // GetTeamUserID gets team user id.
 func GetTeamUserID(request *http.Request) string {
 	return request.Context().Value(teamUserIDKey).(string)
@@ -162,3 +176,9 @@ func SetContextValueForTest(ctx context.Context, key string, value interface{})
 func GetAuth(request *http.Request) *api.Authorization {
 	return request.Context().Value(authKey).(*api.Authorization)
 }
+
+// GetAPIToken gets api token request was authenticated with, nil if request was made without api token.
+func GetAPIToken(request *http.Request) *moira.APIToken {
+	token, _ := request.Context().Value(apiTokenKey).(*moira.APIToken)
+	return token
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+)
+
+// RequireScope returns 403 if request is made with api token which has none of given scopes.
+// Requests made without api token are passed as is.
+func RequireScope(scopes ...moira.APITokenScope) func(next http.Handler) http.Handler {
+	return requireScope(func(*http.Request) []moira.APITokenScope {
+		return scopes
+	})
+}
+
+// RequireTeamScope works as RequireScope but also allows api token with scope of team from request.
+// It must be used after TeamContext.
+func RequireTeamScope(scopes ...moira.APITokenScope) func(next http.Handler) http.Handler {
+	return requireScope(func(request *http.Request) []moira.APITokenScope {
+		return append([]moira.APITokenScope{moira.TeamScope(GetTeamID(request))}, scopes...)
+	})
+}
+
+// RequireTeamFilterScope works as RequireScope but also allows api token with scope of team
+// which is given in team query parameter to filter data of this team.
+func RequireTeamFilterScope(scopes ...moira.APITokenScope) func(next http.Handler) http.Handler {
+	return requireScope(func(request *http.Request) []moira.APITokenScope {
+		if teamID := request.FormValue("team"); teamID != "" {
+			return append([]moira.APITokenScope{moira.TeamScope(teamID)}, scopes...)
+		}
+		return scopes
+	})
+}
+
+func requireScope(getScopes func(request *http.Request) []moira.APITokenScope) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			token := GetAPIToken(request)
+			if token == nil {
+				next.ServeHTTP(writer, request)
+				return
+			}
+
+			for _, scope := range getScopes(request) {
+				if token.HasScope(scope) {
+					ctx := context.WithValue(request.Context(), scopeGrantedKey, true)
+					next.ServeHTTP(writer, request.WithContext(ctx))
+					return
+				}
+			}
+			render.Render(writer, request, api.ErrorForbidden("api token scopes do not allow this request")) //nolint:errcheck
+		})
+	}
+}
+
+// ScopeChecked returns 403 if request is made with api token and no RequireScope was passed before it,
+// so routes which do not declare required scope can not be used with api tokens.
+func ScopeChecked(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if granted, _ := request.Context().Value(scopeGrantedKey).(bool); GetAPIToken(request) != nil && !granted {
+			render.Render(writer, request, api.ErrorForbidden("api token scopes do not allow this request")) //nolint:errcheck
+			return
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRequireScope(t *testing.T) {
+	Convey("Test required scope", t, func() {
+		token := &moira.APIToken{
+			ID:     "token",
+			TeamID: "team1",
+			Scopes: []moira.APITokenScope{moira.APITokenScopeTriggers, moira.TeamScope("team1")},
+		}
+
+		performRequestWithTarget := func(target string, token *moira.APIToken, teamID string, middlewares ...func(http.Handler) http.Handler) int {
+			var handler http.Handler = ScopeChecked(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+			for i := len(middlewares) - 1; i >= 0; i-- {
+				handler = middlewares[i](handler)
+			}
+			request := httptest.NewRequest(http.MethodGet, target, nil)
+			ctx := context.WithValue(request.Context(), teamIDKey, teamID)
+			if token != nil {
+				ctx = context.WithValue(ctx, apiTokenKey, token)
+			}
+			responseWriter := httptest.NewRecorder()
+			handler.ServeHTTP(responseWriter, request.WithContext(ctx))
+			return responseWriter.Code
+		}
+		performRequest := func(token *moira.APIToken, teamID string, middlewares ...func(http.Handler) http.Handler) int {
+			return performRequestWithTarget("/", token, teamID, middlewares...)
+		}
+
+		Convey("Request without token is allowed", func() {
+			So(performRequest(nil, "", RequireScope(moira.APITokenScopeRead)), ShouldEqual, http.StatusOK)
+			So(performRequest(nil, ""), ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Token with any of required scopes is allowed", func() {
+			So(performRequest(token, "", RequireScope(moira.APITokenScopeRead, moira.APITokenScopeTriggers)), ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Token without required scope is denied", func() {
+			So(performRequest(token, "", RequireScope(moira.APITokenScopeRead)), ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Every required scope is checked", func() {
+			So(performRequest(token, "", RequireScope(moira.APITokenScopeTriggers), RequireScope(moira.APITokenScopeRead)), ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Token with scope of team from request is allowed", func() {
+			So(performRequest(token, "team1", RequireTeamScope()), ShouldEqual, http.StatusOK)
+			So(performRequest(token, "team2", RequireTeamScope()), ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Token with scope of team from team filter is allowed", func() {
+			So(performRequestWithTarget("/?team=team1", token, "", RequireTeamFilterScope(moira.APITokenScopeRead)), ShouldEqual, http.StatusOK)
+			So(performRequestWithTarget("/?team=team2", token, "", RequireTeamFilterScope(moira.APITokenScopeRead)), ShouldEqual, http.StatusForbidden)
+			So(performRequestWithTarget("/", token, "", RequireTeamFilterScope(moira.APITokenScopeRead)), ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Token is denied if route does not require any scope", func() {
+			So(performRequest(token, ""), ShouldEqual, http.StatusForbidden)
+		})
+	})
+}
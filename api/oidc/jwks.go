@@ -0,0 +1,206 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+// minRefreshInterval limits how often keys are reloaded when token is signed with unknown key.
+const minRefreshInterval = time.Minute
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+type jsonWebKey struct {
+	KeyID   string `json:"kid"`
+	KeyType string `json:"kty"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type providerMetadata struct {
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// keySet loads public keys of identity provider and caches them until token signed with unknown key comes.
+type keySet struct {
+	mutex       sync.Mutex
+	url         string
+	issuer      string
+	client      *http.Client
+	clock       moira.Clock
+	keys        map[string]crypto.PublicKey
+	lastRefresh time.Time
+}
+
+func newKeySet(url, issuer string, client *http.Client, clock moira.Clock) *keySet {
+	return &keySet{
+		url:    url,
+		issuer: issuer,
+		client: client,
+		clock:  clock,
+		keys:   make(map[string]crypto.PublicKey),
+	}
+}
+
+// getKey returns key with given ID. Empty ID can be used only if identity provider has the only key.
+func (set *keySet) getKey(keyID string) (crypto.PublicKey, error) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	if key, ok := set.findKey(keyID); ok {
+		return key, nil
+	}
+
+	now := set.clock.Now()
+	if !set.lastRefresh.IsZero() && now.Sub(set.lastRefresh) < minRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key '%s'", keyID)
+	}
+	set.lastRefresh = now
+
+	keys, err := set.load()
+	if err != nil {
+		return nil, err
+	}
+	set.keys = keys
+
+	if key, ok := set.findKey(keyID); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key '%s'", keyID)
+}
+
+func (set *keySet) findKey(keyID string) (crypto.PublicKey, bool) {
+	if keyID == "" && len(set.keys) == 1 {
+		for _, key := range set.keys {
+			return key, true
+		}
+	}
+	key, ok := set.keys[keyID]
+	return key, ok
+}
+
+func (set *keySet) load() (map[string]crypto.PublicKey, error) {
+	if set.url == "" {
+		metadata := providerMetadata{}
+		if err := set.getJSON(strings.TrimSuffix(set.issuer, "/")+discoveryPath, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to discover identity provider: %w", err)
+		}
+		if metadata.JWKSURI == "" {
+			return nil, fmt.Errorf("identity provider metadata has no jwks_uri")
+		}
+		set.url = metadata.JWKSURI
+	}
+
+	webKeys := jsonWebKeySet{}
+	if err := set.getJSON(set.url, &webKeys); err != nil {
+		return nil, fmt.Errorf("failed to load signing keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(webKeys.Keys))
+	for _, webKey := range webKeys.Keys {
+		if webKey.Use != "" && webKey.Use != "sig" {
+			continue
+		}
+
+		key, err := parseKey(webKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse signing key '%s': %w", webKey.KeyID, err)
+		}
+		if key != nil {
+			keys[webKey.KeyID] = key
+		}
+	}
+	return keys, nil
+}
+
+func (set *keySet) getJSON(url string, result interface{}) error {
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := set.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with status %d", url, response.StatusCode)
+	}
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+// parseKey converts JSON web key to public key, unsupported key types are skipped with nil key.
+func parseKey(webKey jsonWebKey) (crypto.PublicKey, error) {
+	switch webKey.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(webKey.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(webKey.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curve, err := getCurve(webKey.Curve)
+		if err != nil {
+			return nil, err
+		}
+		x, err := decodeBigInt(webKey.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(webKey.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func getCurve(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve '%s'", name)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
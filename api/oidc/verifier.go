@@ -0,0 +1,221 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/clock"
+)
+
+var errMalformedToken = errors.New("malformed bearer token")
+
+// Config contains settings of OpenID Connect bearer tokens verification.
+type Config struct {
+	// Issuer is expected value of iss claim, also used to discover JWKS url if it is not set.
+	Issuer string
+	// Audience is expected value of aud claim, empty value disables the check.
+	Audience string
+	// JWKSURL is the url public keys of identity provider are loaded from.
+	JWKSURL string
+	// LoginClaim is the claim which contains user login.
+	LoginClaim string
+	// GroupsClaim is the claim which contains list of user groups.
+	GroupsClaim string
+	// Leeway is allowed clock skew while checking token lifetime.
+	Leeway time.Duration
+	// Timeout of requests to identity provider.
+	Timeout time.Duration
+}
+
+// Verifier checks signature and claims of JWT issued by OpenID Connect identity provider.
+type Verifier struct {
+	config Config
+	keys   *keySet
+	clock  moira.Clock
+}
+
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// NewVerifier creates Verifier, public keys are loaded on the first verification.
+func NewVerifier(config Config) *Verifier {
+	systemClock := clock.NewSystemClock()
+	return &Verifier{
+		config: config,
+		keys:   newKeySet(config.JWKSURL, config.Issuer, &http.Client{Timeout: config.Timeout}, systemClock),
+		clock:  systemClock,
+	}
+}
+
+// Verify checks token and returns login and groups of the user it was issued to.
+func (verifier *Verifier) Verify(token string) (string, []string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 { //nolint:gomnd
+		return "", nil, errMalformedToken
+	}
+
+	header := tokenHeader{}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", nil, errMalformedToken
+	}
+
+	claims := make(map[string]interface{})
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return "", nil, errMalformedToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, errMalformedToken
+	}
+
+	key, err := verifier.keys.getKey(header.KeyID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = verifySignature(header.Algorithm, key, parts[0]+"."+parts[1], signature); err != nil {
+		return "", nil, err
+	}
+
+	if err = verifier.verifyClaims(claims); err != nil {
+		return "", nil, err
+	}
+
+	login, _ := claims[verifier.config.LoginClaim].(string)
+	if login == "" {
+		return "", nil, fmt.Errorf("token has no '%s' claim", verifier.config.LoginClaim)
+	}
+
+	return login, getGroups(claims[verifier.config.GroupsClaim]), nil
+}
+
+func (verifier *Verifier) verifyClaims(claims map[string]interface{}) error {
+	if verifier.config.Issuer != "" {
+		if issuer, _ := claims["iss"].(string); issuer != verifier.config.Issuer {
+			return fmt.Errorf("token is issued by unknown issuer '%s'", issuer)
+		}
+	}
+
+	if verifier.config.Audience != "" && !hasAudience(claims["aud"], verifier.config.Audience) {
+		return fmt.Errorf("token is not issued for audience '%s'", verifier.config.Audience)
+	}
+
+	now := verifier.clock.Now()
+	expiresAt, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("token has no expiration time")
+	}
+	if now.After(time.Unix(int64(expiresAt), 0).Add(verifier.config.Leeway)) {
+		return errors.New("token is expired")
+	}
+
+	if notBefore, ok := claims["nbf"].(float64); ok && now.Before(time.Unix(int64(notBefore), 0).Add(-verifier.config.Leeway)) {
+		return errors.New("token is not valid yet")
+	}
+	return nil
+}
+
+func verifySignature(algorithm string, key crypto.PublicKey, signed string, signature []byte) error {
+	hash, err := getHash(algorithm)
+	if err != nil {
+		return err
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(signed))
+	digest := hasher.Sum(nil)
+
+	switch publicKey := key.(type) {
+	case *rsa.PublicKey:
+		switch algorithm[:2] {
+		case "RS":
+			err = rsa.VerifyPKCS1v15(publicKey, hash, digest, signature)
+		case "PS":
+			err = rsa.VerifyPSS(publicKey, hash, digest, signature, nil)
+		default:
+			return fmt.Errorf("algorithm %s can not be used with RSA key", algorithm)
+		}
+		if err != nil {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		size := (publicKey.Curve.Params().BitSize + 7) / 8 //nolint:gomnd
+		if algorithm[:2] != "ES" || len(signature) != 2*size {
+			return errors.New("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(publicKey, digest, r, s) {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	default:
+		return errors.New("unsupported signing key")
+	}
+}
+
+func getHash(algorithm string) (crypto.Hash, error) {
+	switch algorithm {
+	case "RS256", "PS256", "ES256":
+		return crypto.SHA256, nil
+	case "RS384", "PS384", "ES384":
+		return crypto.SHA384, nil
+	case "RS512", "PS512", "ES512":
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported signing algorithm '%s'", algorithm)
+	}
+}
+
+func hasAudience(claim interface{}, audience string) bool {
+	switch value := claim.(type) {
+	case string:
+		return value == audience
+	case []interface{}:
+		for _, item := range value {
+			if item == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getGroups converts claim value to the list of groups, both single string and list of strings are allowed.
+func getGroups(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		groups := make([]string, 0, len(value))
+		for _, item := range value {
+			if group, ok := item.(string); ok {
+				groups = append(groups, group)
+			}
+		}
+		return groups
+	default:
+		return []string{}
+	}
+}
+
+func decodeSegment(segment string, result interface{}) error {
+	bytes, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, result)
+}
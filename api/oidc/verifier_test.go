@@ -0,0 +1,221 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mock_clock "github.com/moira-alert/moira/mock/clock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testAudience = "moira"
+)
+
+func encodeSegment(value interface{}) string {
+	bytes, _ := json.Marshal(value)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+func signRSA(key *rsa.PrivateKey, keyID string, claims map[string]interface{}) string {
+	signed := encodeSegment(map[string]string{"alg": "RS256", "kid": keyID}) + "." + encodeSegment(claims)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(signed))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest.Sum(nil))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func signECDSA(key *ecdsa.PrivateKey, keyID string, claims map[string]interface{}) string {
+	signed := encodeSegment(map[string]string{"alg": "ES256", "kid": keyID}) + "." + encodeSegment(claims)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(signed))
+	r, s, _ := ecdsa.Sign(rand.Reader, key, digest.Sum(nil))
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func TestVerifier(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	jwksRequests := 0
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc(discoveryPath, func(writer http.ResponseWriter, request *http.Request) {
+		json.NewEncoder(writer).Encode(providerMetadata{JWKSURI: server.URL + "/keys"}) //nolint:errcheck
+	})
+	mux.HandleFunc("/keys", func(writer http.ResponseWriter, request *http.Request) {
+		jwksRequests++
+		json.NewEncoder(writer).Encode(jsonWebKeySet{Keys: []jsonWebKey{ //nolint:errcheck
+			{
+				KeyID:   "rsa",
+				KeyType: "RSA",
+				Use:     "sig",
+				N:       encodeBigInt(rsaKey.N),
+				E:       encodeBigInt(big.NewInt(int64(rsaKey.E))),
+			},
+			{
+				KeyID:   "ec",
+				KeyType: "EC",
+				Curve:   "P-256",
+				X:       encodeBigInt(ecKey.X),
+				Y:       encodeBigInt(ecKey.Y),
+			},
+			{
+				KeyID:   "enc",
+				KeyType: "RSA",
+				Use:     "enc",
+				N:       encodeBigInt(otherKey.N),
+				E:       encodeBigInt(big.NewInt(int64(otherKey.E))),
+			},
+		}})
+	})
+
+	now := time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC)
+	systemClock := mock_clock.NewMockClock(mockCtrl)
+	systemClock.EXPECT().Now().Return(now).AnyTimes()
+
+	Convey("Test verifier", t, func() {
+		jwksRequests = 0
+		verifier := NewVerifier(Config{
+			Issuer:      testIssuer,
+			Audience:    testAudience,
+			JWKSURL:     server.URL + "/keys",
+			LoginClaim:  "preferred_username",
+			GroupsClaim: "groups",
+			Leeway:      time.Minute,
+			Timeout:     time.Second,
+		})
+		verifier.clock = systemClock
+		verifier.keys.clock = systemClock
+
+		claims := map[string]interface{}{
+			"iss":                testIssuer,
+			"aud":                []string{"other", testAudience},
+			"exp":                now.Add(time.Hour).Unix(),
+			"nbf":                now.Add(-time.Hour).Unix(),
+			"preferred_username": "john",
+			"groups":             []string{"admins", "devops"},
+		}
+
+		Convey("Token signed with RSA key", func() {
+			login, groups, err := verifier.Verify(signRSA(rsaKey, "rsa", claims))
+			So(err, ShouldBeNil)
+			So(login, ShouldEqual, "john")
+			So(groups, ShouldResemble, []string{"admins", "devops"})
+		})
+
+		Convey("Token signed with EC key", func() {
+			claims["groups"] = "devops"
+			login, groups, err := verifier.Verify(signECDSA(ecKey, "ec", claims))
+			So(err, ShouldBeNil)
+			So(login, ShouldEqual, "john")
+			So(groups, ShouldResemble, []string{"devops"})
+		})
+
+		Convey("Keys are loaded once", func() {
+			_, _, err := verifier.Verify(signRSA(rsaKey, "rsa", claims))
+			So(err, ShouldBeNil)
+			_, _, err = verifier.Verify(signECDSA(ecKey, "ec", claims))
+			So(err, ShouldBeNil)
+			So(jwksRequests, ShouldEqual, 1)
+		})
+
+		Convey("Keys are discovered from issuer", func() {
+			claims["iss"] = server.URL
+			verifier.config.Issuer = server.URL
+			verifier.keys = newKeySet("", server.URL, http.DefaultClient, systemClock)
+
+			login, _, err := verifier.Verify(signRSA(rsaKey, "rsa", claims))
+			So(err, ShouldBeNil)
+			So(login, ShouldEqual, "john")
+		})
+
+		Convey("Invalid tokens", func() {
+			Convey("Malformed", func() {
+				_, _, err := verifier.Verify("not.a-token")
+				So(err, ShouldResemble, errMalformedToken)
+			})
+
+			Convey("Signed with unknown key", func() {
+				_, _, err := verifier.Verify(signRSA(otherKey, "enc", claims))
+				So(err.Error(), ShouldEqual, "unknown signing key 'enc'")
+			})
+
+			Convey("Signed with other key", func() {
+				_, _, err := verifier.Verify(signRSA(otherKey, "rsa", claims))
+				So(err.Error(), ShouldEqual, "invalid token signature")
+			})
+
+			Convey("Tampered", func() {
+				token := signRSA(rsaKey, "rsa", claims)
+				parts := strings.Split(token, ".")
+				claims["preferred_username"] = "admin"
+				parts[1] = encodeSegment(claims)
+				_, _, err := verifier.Verify(strings.Join(parts, "."))
+				So(err.Error(), ShouldEqual, "invalid token signature")
+			})
+
+			Convey("Not signed", func() {
+				token := encodeSegment(map[string]string{"alg": "none", "kid": "rsa"}) + "." + encodeSegment(claims) + "."
+				_, _, err := verifier.Verify(token)
+				So(err.Error(), ShouldEqual, "unsupported signing algorithm 'none'")
+			})
+
+			Convey("Expired", func() {
+				claims["exp"] = now.Add(-2 * time.Minute).Unix()
+				_, _, err := verifier.Verify(signRSA(rsaKey, "rsa", claims))
+				So(err.Error(), ShouldEqual, "token is expired")
+			})
+
+			Convey("Not valid yet", func() {
+				claims["nbf"] = now.Add(2 * time.Minute).Unix()
+				_, _, err := verifier.Verify(signRSA(rsaKey, "rsa", claims))
+				So(err.Error(), ShouldEqual, "token is not valid yet")
+			})
+
+			Convey("Unknown issuer", func() {
+				claims["iss"] = "https://other.example.com"
+				_, _, err := verifier.Verify(signRSA(rsaKey, "rsa", claims))
+				So(err.Error(), ShouldEqual, "token is issued by unknown issuer 'https://other.example.com'")
+			})
+
+			Convey("Other audience", func() {
+				claims["aud"] = "other"
+				_, _, err := verifier.Verify(signRSA(rsaKey, "rsa", claims))
+				So(err.Error(), ShouldEqual, "token is not issued for audience 'moira'")
+			})
+
+			Convey("Without login", func() {
+				delete(claims, "preferred_username")
+				_, _, err := verifier.Verify(signRSA(rsaKey, "rsa", claims))
+				So(err.Error(), ShouldEqual, "token has no 'preferred_username' claim")
+			})
+		})
+	})
+}
@@ -0,0 +1,165 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/clock"
+	"github.com/moira-alert/moira/metrics"
+)
+
+// DefaultQueueSize is the count of audit records waiting to be written to sinks, newer records are dropped if queue is full.
+const DefaultQueueSize = 1000
+
+// Sink is an additional destination audit records are written to besides database.
+type Sink interface {
+	Write(record *moira.AuditRecord) error
+}
+
+// Recorder saves changes of moira configuration made by users into database and writes them to sinks.
+// Sinks are written by background worker, so slow sinks do not slow down requests changing configuration.
+type Recorder struct {
+	database moira.Database
+	logger   moira.Logger
+	clock    moira.Clock
+	metrics  *metrics.AuditMetrics
+	sinks    []Sink
+	queue    chan *moira.AuditRecord
+	tomb     tomb.Tomb
+}
+
+// NewRecorder creates Recorder which saves audit records into database and writes them to given sinks.
+// At most queueSize records wait to be written to sinks, DefaultQueueSize is used if it is not positive.
+func NewRecorder(database moira.Database, logger moira.Logger, auditMetrics *metrics.AuditMetrics, queueSize int, sinks ...Sink) *Recorder {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	return &Recorder{
+		database: database,
+		logger:   logger,
+		clock:    clock.NewSystemClock(),
+		metrics:  auditMetrics,
+		sinks:    sinks,
+		queue:    make(chan *moira.AuditRecord, queueSize),
+	}
+}
+
+// Start starts worker writing audit records to sinks.
+func (recorder *Recorder) Start() {
+	recorder.tomb.Go(recorder.writeToSinks)
+}
+
+// Stop writes queued audit records to sinks and stops worker.
+func (recorder *Recorder) Stop() error {
+	recorder.tomb.Kill(nil)
+	return recorder.tomb.Wait()
+}
+
+// Record creates audit record with object states before and after the change.
+// Token is ID of api token the change was made with, empty if user made it directly.
+// Object is not changed back if record can not be saved, so errors are only logged.
+// Record on nil Recorder does nothing.
+func (recorder *Recorder) Record(actor, token string, action moira.AuditAction, objectType moira.AuditObjectType, objectID string, before, after interface{}) {
+	if recorder == nil {
+		return
+	}
+
+	record := &moira.AuditRecord{
+		Timestamp:  recorder.clock.Now().Unix(),
+		Actor:      actor,
+		Action:     action,
+		ObjectType: objectType,
+		ObjectID:   objectID,
+		Token:      token,
+		Before:     recorder.marshalState(before),
+		After:      recorder.marshalState(after),
+	}
+
+	if err := recorder.database.SaveAuditRecord(record); err != nil {
+		recorder.getLogger(record).Error().
+			Error(err).
+			Msg("Failed to save audit record")
+	}
+
+	if len(recorder.sinks) == 0 {
+		return
+	}
+
+	select {
+	case recorder.queue <- record:
+	default:
+		recorder.metrics.DroppedRecords.Mark(1)
+		recorder.getLogger(record).Warning().
+			Msg("Audit record is not written to sinks, queue is full")
+	}
+}
+
+func (recorder *Recorder) writeToSinks() error {
+	for {
+		select {
+		case record := <-recorder.queue:
+			recorder.writeRecord(record)
+		case <-recorder.tomb.Dying():
+			for {
+				select {
+				case record := <-recorder.queue:
+					recorder.writeRecord(record)
+				default:
+					return nil
+				}
+			}
+		}
+	}
+}
+
+func (recorder *Recorder) writeRecord(record *moira.AuditRecord) {
+	for _, sink := range recorder.sinks {
+		if err := sink.Write(record); err != nil {
+			recorder.getLogger(record).Error().
+				Error(err).
+				Msg("Failed to write audit record to sink")
+		}
+	}
+}
+
+func (recorder *Recorder) marshalState(state interface{}) json.RawMessage {
+	if isNil(state) {
+		return nil
+	}
+
+	bytes, err := json.Marshal(state)
+	if err != nil {
+		recorder.logger.Warning().
+			Error(err).
+			Msg("Failed to marshal object state for audit record")
+		return nil
+	}
+	return bytes
+}
+
+func (recorder *Recorder) getLogger(record *moira.AuditRecord) moira.Logger {
+	return recorder.logger.Clone().
+		String("audit_actor", record.Actor).
+		String("audit_action", string(record.Action)).
+		String("audit_object_type", string(record.ObjectType)).
+		String("audit_object_id", record.ObjectID).
+		String("audit_token", record.Token)
+}
+
+// isNil checks both untyped nil and nil pointers, maps and slices passed as interface.
+func isNil(state interface{}) bool {
+	if state == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(state)
+	switch value.Kind() { // nolint:exhaustive
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
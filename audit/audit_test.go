@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	"github.com/moira-alert/moira/metrics"
+	mock_clock "github.com/moira-alert/moira/mock/clock"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testSink struct {
+	records chan *moira.AuditRecord
+	err     error
+}
+
+func (sink *testSink) Write(record *moira.AuditRecord) error {
+	sink.records <- record
+	return sink.err
+}
+
+func (sink *testSink) next() *moira.AuditRecord {
+	select {
+	case record := <-sink.records:
+		return record
+	case <-time.After(time.Second):
+		return nil
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	clock := mock_clock.NewMockClock(mockCtrl)
+	logger, _ := logging.GetLogger("audit")
+
+	now := time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC)
+	clock.EXPECT().Now().Return(now).AnyTimes()
+
+	Convey("Test audit recorder", t, func() {
+		sink := &testSink{records: make(chan *moira.AuditRecord)}
+		recorder := NewRecorder(dataBase, logger, metrics.ConfigureAuditMetrics(metrics.NewDummyRegistry()), 1, sink)
+		recorder.clock = clock
+		recorder.Start()
+		defer recorder.Stop() //nolint
+
+		before := &map[string]string{"name": "old"}
+		after := map[string]string{"name": "new"}
+		expected := &moira.AuditRecord{
+			Timestamp:  now.Unix(),
+			Actor:      "user",
+			Action:     moira.AuditActionUpdate,
+			ObjectType: moira.AuditObjectTeam,
+			ObjectID:   "team",
+			Before:     json.RawMessage(`{"name":"old"}`),
+			After:      json.RawMessage(`{"name":"new"}`),
+		}
+
+		Convey("Record is saved into database and written to sinks", func() {
+			dataBase.EXPECT().SaveAuditRecord(expected).Return(nil)
+			recorder.Record("user", "", moira.AuditActionUpdate, moira.AuditObjectTeam, "team", before, after)
+			So(sink.next(), ShouldResemble, expected)
+		})
+
+		Convey("Record is written to sinks even if database fails", func() {
+			dataBase.EXPECT().SaveAuditRecord(expected).Return(errors.New("test error"))
+			recorder.Record("user", "", moira.AuditActionUpdate, moira.AuditObjectTeam, "team", before, after)
+			So(sink.next(), ShouldResemble, expected)
+		})
+
+		Convey("Nil states are not marshaled", func() {
+			var nilState map[string]string
+			expected.Action = moira.AuditActionCreate
+			expected.Before = nil
+			dataBase.EXPECT().SaveAuditRecord(expected).Return(nil)
+			recorder.Record("user", "", moira.AuditActionCreate, moira.AuditObjectTeam, "team", nilState, after)
+			So(sink.next(), ShouldResemble, expected)
+		})
+
+		Convey("Record is dropped if sinks queue is full", func() {
+			dataBase.EXPECT().SaveAuditRecord(expected).Return(nil).Times(3)
+			// The first record is being written by worker, the second waits in queue.
+			recorder.Record("user", "", moira.AuditActionUpdate, moira.AuditObjectTeam, "team", before, after)
+			recorder.Record("user", "", moira.AuditActionUpdate, moira.AuditObjectTeam, "team", before, after)
+			time.Sleep(100 * time.Millisecond)
+			recorder.Record("user", "", moira.AuditActionUpdate, moira.AuditObjectTeam, "team", before, after)
+
+			So(recorder.metrics.DroppedRecords.Count(), ShouldEqual, 1)
+			So(sink.next(), ShouldResemble, expected)
+			So(sink.next(), ShouldResemble, expected)
+			So(sink.next(), ShouldBeNil)
+		})
+
+		Convey("Nil recorder does nothing", func() {
+			var nilRecorder *Recorder
+			So(func() {
+				nilRecorder.Record("user", "", moira.AuditActionDelete, moira.AuditObjectTeam, "team", before, nil)
+			}, ShouldNotPanic)
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/moira-alert/moira"
+)
+
+// FileSink appends audit records to the file in JSON lines format.
+type FileSink struct {
+	mutex sync.Mutex
+	path  string
+}
+
+// NewFileSink creates FileSink and checks that file can be opened for writing.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640) //nolint:gomnd
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log file: %w", err)
+	}
+
+	if err = file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close audit log file: %w", err)
+	}
+
+	return &FileSink{path: path}, nil
+}
+
+// Write appends audit record as a single line to the file.
+// The file is reopened on each write so it can be rotated by external tools.
+func (sink *FileSink) Write(record *moira.AuditRecord) error {
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	file, err := os.OpenFile(sink.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640) //nolint:gomnd
+	if err != nil {
+		return fmt.Errorf("failed to open audit log file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(append(bytes, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
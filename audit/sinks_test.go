@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testAuditRecord = &moira.AuditRecord{
+	Timestamp:  1675166400,
+	Actor:      "user",
+	Action:     moira.AuditActionDelete,
+	ObjectType: moira.AuditObjectContact,
+	ObjectID:   "contact",
+	Before:     json.RawMessage(`{"id":"contact"}`),
+}
+
+func TestFileSink(t *testing.T) {
+	Convey("Test file sink", t, func() {
+		path := filepath.Join(t.TempDir(), "audit.log")
+
+		Convey("Records are appended as json lines", func() {
+			sink, err := NewFileSink(path)
+			So(err, ShouldBeNil)
+
+			So(sink.Write(testAuditRecord), ShouldBeNil)
+			So(sink.Write(testAuditRecord), ShouldBeNil)
+
+			content, err := os.ReadFile(path)
+			So(err, ShouldBeNil)
+
+			lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+			So(lines, ShouldHaveLength, 2)
+
+			actual := &moira.AuditRecord{}
+			So(json.Unmarshal([]byte(lines[1]), actual), ShouldBeNil)
+			So(actual, ShouldResemble, testAuditRecord)
+		})
+
+		Convey("File in unknown directory can not be used", func() {
+			sink, err := NewFileSink(filepath.Join(path, "unknown", "audit.log"))
+			So(err, ShouldNotBeNil)
+			So(sink, ShouldBeNil)
+		})
+	})
+}
+
+func TestWebhookSink(t *testing.T) {
+	Convey("Test webhook sink", t, func() {
+		status := http.StatusOK
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ = io.ReadAll(request.Body)
+			writer.WriteHeader(status)
+		}))
+		defer server.Close()
+
+		sink := NewWebhookSink(server.URL, time.Second)
+
+		Convey("Record is sent as json", func() {
+			So(sink.Write(testAuditRecord), ShouldBeNil)
+
+			actual := &moira.AuditRecord{}
+			So(json.Unmarshal(body, actual), ShouldBeNil)
+			So(actual, ShouldResemble, testAuditRecord)
+		})
+
+		Convey("Not successful response status is an error", func() {
+			status = http.StatusInternalServerError
+			So(sink.Write(testAuditRecord), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,152 @@
+package moira
+
+import (
+	"fmt"
+	"time"
+)
+
+// CriticalTriggerTag marks triggers ERROR notifications of which reach contacts even if their owners are unavailable.
+const CriticalTriggerTag = "critical"
+
+const minutesInDay = 24 * 60
+
+// ContactAvailability describes periods owner of contact should not be disturbed in.
+// Non-critical notifications are delayed until the end of such period or redirected to backup contact.
+type ContactAvailability struct {
+	// QuietHours are daily hours without notifications, e.g. nights.
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"`
+	// Vacations are periods of absence.
+	Vacations []AvailabilityPeriod `json:"vacations,omitempty"`
+	// DoNotDisturb is turned on by owner of contact at any time, it lasts until it is turned off or until DoNotDisturbUntil.
+	DoNotDisturb      bool  `json:"do_not_disturb,omitempty" example:"false"`
+	DoNotDisturbUntil int64 `json:"do_not_disturb_until,omitempty" example:"1590741878" format:"int64"`
+	// BackupContact is the id of contact notifications are redirected to, they are delayed if it is not set.
+	BackupContact string `json:"backup_contact,omitempty" example:"1dd38765-c5be-418d-81fa-7a5f879c2315"`
+}
+
+// QuietHours is a daily period set in minutes since the midnight of timezone.
+// Period may pass the midnight, e.g. from 1320 (22:00) to 480 (08:00).
+type QuietHours struct {
+	// Days are days of week quiet hours start on, starting from Monday. Quiet hours start every day if days are not set.
+	Days        []ScheduleDataDay `json:"days,omitempty"`
+	StartOffset int64             `json:"startOffset" example:"1320" format:"int64"`
+	EndOffset   int64             `json:"endOffset" example:"480" format:"int64"`
+	// Timezone is the name of IANA timezone, UTC is used if it is empty.
+	Timezone string `json:"timezone,omitempty" example:"Europe/Berlin"`
+}
+
+// AvailabilityPeriod is a period between two unix timestamps.
+type AvailabilityPeriod struct {
+	From int64 `json:"from" example:"1590741878" format:"int64"`
+	To   int64 `json:"to" example:"1591346678" format:"int64"`
+}
+
+// Validate checks that availability settings are consistent.
+func (availability *ContactAvailability) Validate() error {
+	if availability == nil {
+		return nil
+	}
+	if availability.QuietHours != nil {
+		if err := availability.QuietHours.validate(); err != nil {
+			return fmt.Errorf("invalid quiet hours: %w", err)
+		}
+	}
+	for _, vacation := range availability.Vacations {
+		if vacation.From >= vacation.To {
+			return fmt.Errorf("vacation must end after it starts")
+		}
+	}
+	if availability.DoNotDisturbUntil < 0 {
+		return fmt.Errorf("do_not_disturb_until must not be negative")
+	}
+	return nil
+}
+
+// UnavailableUntil checks whether owner of contact is unavailable at the moment and returns the end of current period.
+// Zero time is returned for Do Not Disturb without end.
+func (availability *ContactAvailability) UnavailableUntil(now time.Time) (time.Time, bool) {
+	if availability == nil {
+		return time.Time{}, false
+	}
+
+	var until time.Time
+	unavailable := false
+	if availability.DoNotDisturb {
+		if availability.DoNotDisturbUntil == 0 {
+			return time.Time{}, true
+		}
+		if dndEnd := time.Unix(availability.DoNotDisturbUntil, 0); now.Before(dndEnd) {
+			until, unavailable = dndEnd, true
+		}
+	}
+	for _, vacation := range availability.Vacations {
+		from, to := time.Unix(vacation.From, 0), time.Unix(vacation.To, 0)
+		if !now.Before(from) && now.Before(to) && to.After(until) {
+			until, unavailable = to, true
+		}
+	}
+	if end, ok := availability.QuietHours.end(now); ok && end.After(until) {
+		until, unavailable = end, true
+	}
+	return until, unavailable
+}
+
+func (quietHours *QuietHours) validate() error {
+	if quietHours.StartOffset < 0 || quietHours.StartOffset >= minutesInDay || quietHours.EndOffset < 0 || quietHours.EndOffset >= minutesInDay {
+		return fmt.Errorf("offsets must be in range [0, %d)", minutesInDay)
+	}
+	if quietHours.StartOffset == quietHours.EndOffset {
+		return fmt.Errorf("quiet hours must not be empty")
+	}
+	if len(quietHours.Days) != 0 && len(quietHours.Days) != 7 { //nolint:gomnd
+		return fmt.Errorf("%d days defined, expected 7", len(quietHours.Days))
+	}
+	if _, err := time.LoadLocation(quietHours.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone '%s'", quietHours.Timezone)
+	}
+	return nil
+}
+
+// end returns the end of quiet hours if they last at the moment.
+func (quietHours *QuietHours) end(now time.Time) (time.Time, bool) {
+	if quietHours == nil {
+		return time.Time{}, false
+	}
+	location, err := time.LoadLocation(quietHours.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	local := now.In(location)
+	year, month, day := local.Date()
+	minutes := int64(local.Hour()*60 + local.Minute()) //nolint:gomnd
+	dayEnd := time.Date(year, month, day, 0, int(quietHours.EndOffset), 0, 0, location)
+
+	if quietHours.StartOffset < quietHours.EndOffset {
+		if minutes >= quietHours.StartOffset && minutes < quietHours.EndOffset && quietHours.isDayEnabled(local.Weekday()) {
+			return dayEnd, true
+		}
+		return time.Time{}, false
+	}
+
+	// Quiet hours pass the midnight, they started either today or yesterday.
+	if minutes >= quietHours.StartOffset && quietHours.isDayEnabled(local.Weekday()) {
+		return dayEnd.AddDate(0, 0, 1), true
+	}
+	if minutes < quietHours.EndOffset && quietHours.isDayEnabled(local.AddDate(0, 0, -1).Weekday()) {
+		return dayEnd, true
+	}
+	return time.Time{}, false
+}
+
+func (quietHours *QuietHours) isDayEnabled(weekday time.Weekday) bool {
+	if len(quietHours.Days) == 0 {
+		return true
+	}
+	return quietHours.Days[int(weekday+6)%7].Enabled //nolint:gomnd
+}
+
+// IsCriticalNotification checks whether notification must reach contact even if its owner is unavailable.
+func IsCriticalNotification(event NotificationEvent, trigger TriggerData) bool {
+	return event.State == StateERROR && Subset([]string{CriticalTriggerTag}, trigger.Tags)
+}
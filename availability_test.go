@@ -0,0 +1,135 @@
+package moira
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestContactAvailability(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	// Wednesday.
+	now := time.Date(2024, 5, 15, 23, 30, 0, 0, berlin)
+
+	Convey("Test UnavailableUntil", t, func() {
+		Convey("Contact without availability settings is available", func() {
+			var availability *ContactAvailability
+			_, unavailable := availability.UnavailableUntil(now)
+			So(unavailable, ShouldBeFalse)
+		})
+
+		Convey("Do Not Disturb without end", func() {
+			availability := &ContactAvailability{DoNotDisturb: true}
+			until, unavailable := availability.UnavailableUntil(now)
+			So(unavailable, ShouldBeTrue)
+			So(until.IsZero(), ShouldBeTrue)
+		})
+
+		Convey("Do Not Disturb with end", func() {
+			availability := &ContactAvailability{DoNotDisturb: true, DoNotDisturbUntil: now.Add(time.Hour).Unix()}
+			until, unavailable := availability.UnavailableUntil(now)
+			So(unavailable, ShouldBeTrue)
+			So(until.Unix(), ShouldEqual, now.Add(time.Hour).Unix())
+
+			_, unavailable = availability.UnavailableUntil(now.Add(time.Hour))
+			So(unavailable, ShouldBeFalse)
+		})
+
+		Convey("Vacation", func() {
+			availability := &ContactAvailability{Vacations: []AvailabilityPeriod{
+				{From: now.Add(-time.Hour).Unix(), To: now.Add(48 * time.Hour).Unix()},
+				{From: now.Add(72 * time.Hour).Unix(), To: now.Add(96 * time.Hour).Unix()},
+			}}
+			until, unavailable := availability.UnavailableUntil(now)
+			So(unavailable, ShouldBeTrue)
+			So(until.Unix(), ShouldEqual, now.Add(48*time.Hour).Unix())
+
+			_, unavailable = availability.UnavailableUntil(now.Add(60 * time.Hour))
+			So(unavailable, ShouldBeFalse)
+		})
+
+		Convey("Quiet hours passing the midnight", func() {
+			availability := &ContactAvailability{QuietHours: &QuietHours{StartOffset: 22 * 60, EndOffset: 8 * 60, Timezone: "Europe/Berlin"}}
+
+			until, unavailable := availability.UnavailableUntil(now)
+			So(unavailable, ShouldBeTrue)
+			So(until, ShouldEqual, time.Date(2024, 5, 16, 8, 0, 0, 0, berlin))
+
+			until, unavailable = availability.UnavailableUntil(now.Add(5 * time.Hour))
+			So(unavailable, ShouldBeTrue)
+			So(until, ShouldEqual, time.Date(2024, 5, 16, 8, 0, 0, 0, berlin))
+
+			_, unavailable = availability.UnavailableUntil(time.Date(2024, 5, 16, 8, 0, 0, 0, berlin))
+			So(unavailable, ShouldBeFalse)
+
+			Convey("Timezone of quiet hours is used", func() {
+				_, unavailable = availability.UnavailableUntil(time.Date(2024, 5, 15, 21, 30, 0, 0, time.UTC))
+				So(unavailable, ShouldBeTrue)
+				_, unavailable = availability.UnavailableUntil(time.Date(2024, 5, 15, 19, 30, 0, 0, time.UTC))
+				So(unavailable, ShouldBeFalse)
+			})
+
+			Convey("Quiet hours start on enabled days only", func() {
+				days := make([]ScheduleDataDay, 7)
+				for i := range days {
+					days[i].Enabled = i >= 4
+				}
+				availability.QuietHours.Days = days
+
+				// Quiet hours of Wednesday night are disabled.
+				_, unavailable = availability.UnavailableUntil(now)
+				So(unavailable, ShouldBeFalse)
+				// Quiet hours of Friday night last till Saturday morning.
+				until, unavailable = availability.UnavailableUntil(time.Date(2024, 5, 18, 7, 0, 0, 0, berlin))
+				So(unavailable, ShouldBeTrue)
+				So(until, ShouldEqual, time.Date(2024, 5, 18, 8, 0, 0, 0, berlin))
+			})
+		})
+
+		Convey("Daytime quiet hours", func() {
+			availability := &ContactAvailability{QuietHours: &QuietHours{StartOffset: 12 * 60, EndOffset: 13 * 60}}
+			until, unavailable := availability.UnavailableUntil(time.Date(2024, 5, 15, 12, 15, 0, 0, time.UTC))
+			So(unavailable, ShouldBeTrue)
+			So(until, ShouldEqual, time.Date(2024, 5, 15, 13, 0, 0, 0, time.UTC))
+
+			_, unavailable = availability.UnavailableUntil(time.Date(2024, 5, 15, 13, 0, 0, 0, time.UTC))
+			So(unavailable, ShouldBeFalse)
+		})
+
+		Convey("The latest end of periods is returned", func() {
+			availability := &ContactAvailability{
+				QuietHours: &QuietHours{StartOffset: 22 * 60, EndOffset: 8 * 60, Timezone: "Europe/Berlin"},
+				Vacations:  []AvailabilityPeriod{{From: now.Add(-time.Hour).Unix(), To: now.Add(time.Hour).Unix()}},
+			}
+			until, unavailable := availability.UnavailableUntil(now)
+			So(unavailable, ShouldBeTrue)
+			So(until, ShouldEqual, time.Date(2024, 5, 16, 8, 0, 0, 0, berlin))
+		})
+	})
+
+	Convey("Test Validate", t, func() {
+		So((&ContactAvailability{QuietHours: &QuietHours{StartOffset: 1320, EndOffset: 480, Timezone: "Europe/Berlin"}}).Validate(), ShouldBeNil)
+
+		invalid := []*ContactAvailability{
+			{QuietHours: &QuietHours{StartOffset: 1320, EndOffset: 1440}},
+			{QuietHours: &QuietHours{StartOffset: 60, EndOffset: 60}},
+			{QuietHours: &QuietHours{StartOffset: 60, EndOffset: 120, Days: make([]ScheduleDataDay, 3)}},
+			{QuietHours: &QuietHours{StartOffset: 60, EndOffset: 120, Timezone: "Mars/Olympus"}},
+			{Vacations: []AvailabilityPeriod{{From: 100, To: 50}}},
+			{DoNotDisturb: true, DoNotDisturbUntil: -1},
+		}
+		for _, availability := range invalid {
+			So(availability.Validate(), ShouldNotBeNil)
+		}
+	})
+}
+
+func TestIsCriticalNotification(t *testing.T) {
+	Convey("Only ERROR of critical trigger is critical", t, func() {
+		critical := TriggerData{Tags: []string{"db", CriticalTriggerTag}}
+		So(IsCriticalNotification(NotificationEvent{State: StateERROR}, critical), ShouldBeTrue)
+		So(IsCriticalNotification(NotificationEvent{State: StateWARN}, critical), ShouldBeFalse)
+		So(IsCriticalNotification(NotificationEvent{State: StateERROR}, TriggerData{Tags: []string{"db"}}), ShouldBeFalse)
+	})
+}
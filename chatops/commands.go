@@ -0,0 +1,151 @@
+// Package chatops executes commands sent to moira from chats, e.g. Telegram bot messages and Slack slash commands.
+package chatops
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/audit"
+	metricSource "github.com/moira-alert/moira/metric_source"
+)
+
+const (
+	commandPrefix = "/"
+	// maxListedItems limits the number of triggers and subscriptions in responses to keep messages readable in chat.
+	maxListedItems = 20
+)
+
+// Config is the configuration of chat-ops commands.
+type Config struct {
+	// Users maps chat users to moira logins, only mapped users are allowed to execute commands.
+	Users map[string]string
+	// FrontURI is moira web ui address used in links to triggers.
+	FrontURI string
+	// Location is used to render time in responses and on plots.
+	Location *time.Location
+	// PlotTheme is the theme of plots attached to responses.
+	PlotTheme string
+}
+
+// Request is a command sent from chat.
+type Request struct {
+	// User is the chat user who sent command, it is mapped to moira login by config.
+	User string
+	// Text is the command with arguments, e.g. "/mute trigger-id 1h".
+	Text string
+}
+
+// Response is the result of command to be sent back to chat.
+type Response struct {
+	Text  string
+	Plots [][]byte
+}
+
+// Commands executes chat-ops commands on behalf of moira users.
+type Commands struct {
+	config               Config
+	database             moira.Database
+	searcher             moira.Searcher
+	metricSourceProvider *metricSource.SourceProvider
+	logger               moira.Logger
+	auditRecorder        *audit.Recorder
+	clock                func() time.Time
+}
+
+// NewCommands creates chat-ops commands executor.
+// Searcher is optional, triggers are listed from database without it.
+// Metric source provider is optional, plots are not rendered without it.
+// Audit recorder records maintenance set by commands on behalf of moira users, nothing is recorded if it is nil.
+func NewCommands(
+	config Config,
+	database moira.Database,
+	searcher moira.Searcher,
+	metricSourceProvider *metricSource.SourceProvider,
+	logger moira.Logger,
+	auditRecorder *audit.Recorder,
+) *Commands {
+	users := make(map[string]string, len(config.Users))
+	for chatUser, login := range config.Users {
+		users[normalizeChatUser(chatUser)] = login
+	}
+	config.Users = users
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+	return &Commands{
+		config:               config,
+		database:             database,
+		searcher:             searcher,
+		metricSourceProvider: metricSourceProvider,
+		logger:               logger,
+		auditRecorder:        auditRecorder,
+		clock:                time.Now,
+	}
+}
+
+// IsCommand returns true if text looks like chat-ops command.
+func IsCommand(text string) bool {
+	return strings.HasPrefix(strings.TrimSpace(text), commandPrefix)
+}
+
+// Execute executes command and returns response to be sent to chat.
+// Mistakes of user are reported in response text, error is returned only if command failed.
+func (commands *Commands) Execute(request Request) (Response, error) {
+	name, args := parseCommand(request.Text)
+	if name == "help" || name == "start" {
+		return Response{Text: helpText}, nil
+	}
+	handler, ok := commands.handlers()[name]
+	if !ok {
+		return Response{Text: fmt.Sprintf("Unknown command /%s.\n\n%s", name, helpText)}, nil
+	}
+
+	login, ok := commands.config.Users[normalizeChatUser(request.User)]
+	if !ok || login == "" {
+		return Response{Text: "You are not allowed to run commands, ask moira administrator to map your chat user to moira login."}, nil
+	}
+
+	response, err := handler(login, args)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to execute command %s: %w", name, err)
+	}
+	return response, nil
+}
+
+type commandHandler func(login string, args []string) (Response, error)
+
+func (commands *Commands) handlers() map[string]commandHandler {
+	return map[string]commandHandler{
+		"status":        commands.status,
+		"trigger":       commands.trigger,
+		"mute":          commands.mute,
+		"ack":           commands.ack,
+		"subscriptions": commands.subscriptions,
+	}
+}
+
+const helpText = `Available commands:
+/status <tag> - triggers with problems by tag
+/trigger <trigger id> - trigger state and plot
+/mute <trigger id> <duration> - set maintenance to trigger, e.g. /mute abc 1h
+/ack <trigger id> [duration] - set maintenance to metrics in problem state, default is 1h
+/subscriptions - your subscriptions`
+
+// parseCommand splits text to command name and arguments, bot name suffix of group chat commands is removed.
+func parseCommand(text string) (string, []string) {
+	fields := strings.Fields(strings.TrimSpace(text))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	name := strings.TrimPrefix(fields[0], commandPrefix)
+	if index := strings.Index(name, "@"); index >= 0 {
+		name = name[:index]
+	}
+	return strings.ToLower(name), fields[1:]
+}
+
+func normalizeChatUser(user string) string {
+	return strings.ToLower(strings.TrimPrefix(user, "@"))
+}
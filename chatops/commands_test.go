@@ -0,0 +1,285 @@
+package chatops
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/audit"
+	"github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	testChatUser = "@John_Doe"
+	testLogin    = "john.doe"
+	testTrigger  = "trigger-id"
+)
+
+func newTestCommands(dataBase moira.Database, searcher moira.Searcher) *Commands {
+	logger, _ := zerolog_adapter.GetLogger("Test")
+	commands := NewCommands(Config{
+		Users:    map[string]string{testChatUser: testLogin},
+		FrontURI: "https://moira.example.com",
+	}, dataBase, searcher, nil, logger, audit.NewRecorder(dataBase, logger, nil, 0))
+	commands.clock = func() time.Time { return time.Unix(1700000000, 0) }
+	return commands
+}
+
+func TestParseCommand(t *testing.T) {
+	Convey("Parse command", t, func() {
+		name, args := parseCommand("/mute trigger-id 1h")
+		So(name, ShouldEqual, "mute")
+		So(args, ShouldResemble, []string{"trigger-id", "1h"})
+
+		name, args = parseCommand("  /Status@MoiraBot backend ")
+		So(name, ShouldEqual, "status")
+		So(args, ShouldResemble, []string{"backend"})
+
+		name, args = parseCommand("")
+		So(name, ShouldBeEmpty)
+		So(args, ShouldBeEmpty)
+	})
+}
+
+func TestExecute(t *testing.T) {
+	Convey("Execute commands", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+		commands := newTestCommands(dataBase, nil)
+
+		Convey("Help is available to everyone", func() {
+			response, err := commands.Execute(Request{User: "stranger", Text: "/help"})
+			So(err, ShouldBeNil)
+			So(response.Text, ShouldEqual, helpText)
+		})
+
+		Convey("Unknown command", func() {
+			response, err := commands.Execute(Request{User: testChatUser, Text: "/reboot"})
+			So(err, ShouldBeNil)
+			So(response.Text, ShouldStartWith, "Unknown command /reboot.")
+		})
+
+		Convey("Not mapped user", func() {
+			response, err := commands.Execute(Request{User: "stranger", Text: "/subscriptions"})
+			So(err, ShouldBeNil)
+			So(response.Text, ShouldStartWith, "You are not allowed to run commands")
+		})
+
+		Convey("Mapped user is matched case insensitive and without @", func() {
+			dataBase.EXPECT().GetUserSubscriptionIDs(testLogin).Return([]string{}, nil)
+			dataBase.EXPECT().GetSubscriptions([]string{}).Return([]*moira.SubscriptionData{}, nil)
+			response, err := commands.Execute(Request{User: "john_doe", Text: "/subscriptions"})
+			So(err, ShouldBeNil)
+			So(response.Text, ShouldEqual, "User john.doe has no subscriptions")
+		})
+
+		Convey("Database error", func() {
+			dbErr := errors.New("database error")
+			dataBase.EXPECT().GetUserSubscriptionIDs(testLogin).Return(nil, dbErr)
+			_, err := commands.Execute(Request{User: testChatUser, Text: "/subscriptions"})
+			So(errors.Is(err, dbErr), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, "failed to execute command subscriptions: database error")
+		})
+	})
+}
+
+func TestStatus(t *testing.T) {
+	Convey("Status command", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+		checks := []*moira.TriggerCheck{
+			{Trigger: moira.Trigger{ID: "ok", Name: "OK trigger"}, LastCheck: moira.CheckData{State: moira.StateOK}},
+			{Trigger: moira.Trigger{ID: "warn", Name: "Warn trigger"}, LastCheck: moira.CheckData{State: moira.StateWARN, Score: 1}},
+			{Trigger: moira.Trigger{ID: "error", Name: "Error trigger"}, LastCheck: moira.CheckData{State: moira.StateERROR, Score: 100}},
+		}
+
+		Convey("Without search index", func() {
+			commands := newTestCommands(dataBase, nil)
+			dataBase.EXPECT().GetTagTriggerIDs("backend").Return([]string{"ok", "warn", "error"}, nil)
+			dataBase.EXPECT().GetTriggerChecks([]string{"ok", "warn", "error"}).Return(checks, nil)
+
+			response, err := commands.Execute(Request{User: testChatUser, Text: "/status backend"})
+			So(err, ShouldBeNil)
+			So(response.Text, ShouldEqual, "Problems with tag backend:\n"+
+				"ERROR Error trigger https://moira.example.com/trigger/error\n"+
+				"WARN Warn trigger https://moira.example.com/trigger/warn")
+		})
+
+		Convey("With search index", func() {
+			searcher := mock_moira_alert.NewMockSearcher(mockCtrl)
+			commands := newTestCommands(dataBase, searcher)
+			searcher.EXPECT().SearchTriggers(moira.SearchOptions{Size: -1, OnlyProblems: true, Tags: []string{"backend"}}).
+				Return([]*moira.SearchResult{{ObjectID: "error"}}, int64(1), nil)
+			dataBase.EXPECT().GetTriggerChecks([]string{"error"}).Return(checks[2:], nil)
+
+			response, err := commands.Execute(Request{User: testChatUser, Text: "/status backend"})
+			So(err, ShouldBeNil)
+			So(response.Text, ShouldEqual, "Problems with tag backend:\nERROR Error trigger https://moira.example.com/trigger/error")
+		})
+
+		Convey("Without problems", func() {
+			commands := newTestCommands(dataBase, nil)
+			dataBase.EXPECT().GetTagTriggerIDs("backend").Return([]string{"ok"}, nil)
+			dataBase.EXPECT().GetTriggerChecks([]string{"ok"}).Return(checks[:1], nil)
+
+			response, err := commands.Execute(Request{User: testChatUser, Text: "/status backend"})
+			So(err, ShouldBeNil)
+			So(response.Text, ShouldEqual, "No problems with tag backend.")
+		})
+
+		Convey("Without tag", func() {
+			commands := newTestCommands(dataBase, nil)
+			response, err := commands.Execute(Request{User: testChatUser, Text: "/status"})
+			So(err, ShouldBeNil)
+			So(response.Text, ShouldEqual, "Usage: /status <tag>")
+		})
+	})
+}
+
+func TestTriggerCommands(t *testing.T) {
+	Convey("Trigger commands", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+		commands := newTestCommands(dataBase, nil)
+		check := &moira.TriggerCheck{
+			Trigger: moira.Trigger{ID: testTrigger, Name: "Disk usage"},
+			LastCheck: moira.CheckData{
+				State: moira.StateWARN,
+				Metrics: map[string]moira.MetricState{
+					"server1.disk": {State: moira.StateOK},
+					"server2.disk": {State: moira.StateWARN},
+					"server3.disk": {State: moira.StateNODATA, Maintenance: 1800000000},
+				},
+			},
+		}
+		maintenance := int64(1700003600)
+
+		Convey("Trigger state", func() {
+			dataBase.EXPECT().GetTriggerChecks([]string{testTrigger}).Return([]*moira.TriggerCheck{check}, nil)
+
+			response, err := commands.Execute(Request{User: testChatUser, Text: "/trigger " + testTrigger})
+			So(err, ShouldBeNil)
+			So(response.Text, ShouldEqual, "WARN Disk usage\n"+
+				"WARN server2.disk\n"+
+				"NODATA server3.disk (muted)\n"+
+				"https://moira.example.com/trigger/trigger-id")
+			So(response.Plots, ShouldBeEmpty)
+		})
+
+		Convey("Not existing trigger", func() {
+			dataBase.EXPECT().GetTriggerChecks([]string{"unknown"}).Return([]*moira.TriggerCheck{nil}, nil)
+
+			response, err := commands.Execute(Request{User: testChatUser, Text: "/trigger unknown"})
+			So(err, ShouldBeNil)
+			So(response.Text, ShouldEqual, "Trigger unknown not found")
+		})
+
+		expectMaintenanceAudit := func(after string) {
+			dataBase.EXPECT().SaveAuditRecord(gomock.Any()).DoAndReturn(func(record *moira.AuditRecord) error {
+				So(record.Actor, ShouldEqual, testLogin)
+				So(record.Action, ShouldEqual, moira.AuditActionUpdate)
+				So(record.ObjectType, ShouldEqual, moira.AuditObjectTriggerMaintenance)
+				So(record.ObjectID, ShouldEqual, testTrigger)
+				So(record.Before, ShouldBeEmpty)
+				So(string(record.After), ShouldEqual, after)
+				return nil
+			})
+		}
+
+		Convey("Mute trigger", func() {
+			dataBase.EXPECT().GetTriggerChecks([]string{testTrigger}).Return([]*moira.TriggerCheck{check}, nil)
+			dataBase.EXPECT().AcquireTriggerCheckLock(testTrigger, maxTriggerLockAttempts).Return(nil)
+			dataBase.EXPECT().SetTriggerCheckMaintenance(testTrigger, nil, &maintenance, testLogin, int64(1700000000)).Return(nil)
+			dataBase.EXPECT().ReleaseTriggerCheckLock(testTrigger)
+			expectMaintenanceAudit(`{"trigger":1700003600,"metrics":null}`)
+
+			response, err := commands.Execute(Request{User: testChatUser, Text: "/mute " + testTrigger + " 1h"})
+			So(err, ShouldBeNil)
+			So(response.Text, ShouldEqual, "Trigger Disk usage is muted until 23:13 14.11.2023")
+		})
+
+		Convey("Mute trigger with invalid duration", func() {
+			response, err := commands.Execute(Request{User: testChatUser, Text: "/mute " + testTrigger + " forever"})
+			So(err, ShouldBeNil)
+			So(response.Text, ShouldStartWith, "Invalid duration forever")
+		})
+
+		Convey("Acknowledge problem metrics", func() {
+			dataBase.EXPECT().GetTriggerChecks([]string{testTrigger}).Return([]*moira.TriggerCheck{check}, nil)
+			dataBase.EXPECT().AcquireTriggerCheckLock(testTrigger, maxTriggerLockAttempts).Return(nil)
+			dataBase.EXPECT().SetTriggerCheckMaintenance(testTrigger, map[string]int64{
+				"server2.disk": maintenance,
+				"server3.disk": maintenance,
+			}, nil, testLogin, int64(1700000000)).Return(nil)
+			dataBase.EXPECT().ReleaseTriggerCheckLock(testTrigger)
+			expectMaintenanceAudit(`{"trigger":null,"metrics":{"server2.disk":1700003600,"server3.disk":1700003600}}`)
+
+			response, err := commands.Execute(Request{User: testChatUser, Text: "/ack " + testTrigger})
+			So(err, ShouldBeNil)
+			So(response.Text, ShouldEqual, "2 metrics of trigger Disk usage are acknowledged until 23:13 14.11.2023")
+		})
+
+		Convey("Team trigger", func() {
+			check.TeamID = "team-id"
+			dataBase.EXPECT().GetTriggerChecks([]string{testTrigger}).Return([]*moira.TriggerCheck{check}, nil)
+
+			Convey("User is not a team member", func() {
+				dataBase.EXPECT().IsTeamContainUser("team-id", testLogin).Return(false, nil)
+
+				response, err := commands.Execute(Request{User: testChatUser, Text: "/mute " + testTrigger + " 1h"})
+				So(err, ShouldBeNil)
+				So(response.Text, ShouldEqual, "You are not allowed to change trigger Disk usage of team team-id")
+			})
+
+			Convey("User is a team viewer", func() {
+				dataBase.EXPECT().IsTeamContainUser("team-id", testLogin).Return(true, nil)
+				dataBase.EXPECT().GetTeamUserRoles("team-id").Return(map[string]moira.TeamRole{testLogin: moira.TeamRoleViewer}, nil)
+
+				response, err := commands.Execute(Request{User: testChatUser, Text: "/ack " + testTrigger})
+				So(err, ShouldBeNil)
+				So(response.Text, ShouldEqual, "You are not allowed to change trigger Disk usage of team team-id")
+			})
+
+			Convey("User is a team editor", func() {
+				dataBase.EXPECT().IsTeamContainUser("team-id", testLogin).Return(true, nil)
+				dataBase.EXPECT().GetTeamUserRoles("team-id").Return(map[string]moira.TeamRole{"owner": moira.TeamRoleOwner}, nil)
+				dataBase.EXPECT().AcquireTriggerCheckLock(testTrigger, maxTriggerLockAttempts).Return(nil)
+				dataBase.EXPECT().SetTriggerCheckMaintenance(testTrigger, nil, &maintenance, testLogin, int64(1700000000)).Return(nil)
+				dataBase.EXPECT().ReleaseTriggerCheckLock(testTrigger)
+				expectMaintenanceAudit(`{"trigger":1700003600,"metrics":null}`)
+
+				response, err := commands.Execute(Request{User: testChatUser, Text: "/mute " + testTrigger + " 1h"})
+				So(err, ShouldBeNil)
+				So(response.Text, ShouldEqual, "Trigger Disk usage is muted until 23:13 14.11.2023")
+			})
+		})
+	})
+}
+
+func TestSubscriptions(t *testing.T) {
+	Convey("Subscriptions command", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+		commands := newTestCommands(dataBase, nil)
+
+		dataBase.EXPECT().GetUserSubscriptionIDs(testLogin).Return([]string{"sub1", "sub2"}, nil)
+		dataBase.EXPECT().GetSubscriptions([]string{"sub1", "sub2"}).Return([]*moira.SubscriptionData{
+			{ID: "sub1", Tags: []string{"backend", "disk"}, Contacts: []string{"c1"}, Enabled: true},
+			{ID: "sub2", AnyTags: true, Contacts: []string{"c1", "c2"}},
+		}, nil)
+
+		response, err := commands.Execute(Request{User: testChatUser, Text: "/subscriptions"})
+		So(err, ShouldBeNil)
+		So(response.Text, ShouldEqual, "Subscriptions of john.doe:\n"+
+			"sub1: backend, disk, 1 contacts (enabled)\n"+
+			"sub2: any tags, 2 contacts (disabled)")
+	})
+}
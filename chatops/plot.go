@@ -0,0 +1,55 @@
+package chatops
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/plotting"
+)
+
+// plotTimeRange is the time range of plots attached to trigger state.
+const plotTimeRange = 3600
+
+// buildTriggerPlots renders plot of the last hour for each target of trigger.
+func (commands *Commands) buildTriggerPlots(trigger *moira.Trigger) ([][]byte, error) {
+	if commands.metricSourceProvider == nil {
+		return nil, nil
+	}
+
+	metricsSource, err := commands.metricSourceProvider.GetTriggerMetricSource(trigger)
+	if err != nil {
+		return nil, err
+	}
+	plotTemplate, err := plotting.GetPlotTemplate(commands.config.PlotTheme, commands.config.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	to := commands.clock().Unix()
+	from := to - plotTimeRange
+	plots := make([][]byte, 0, len(trigger.Targets))
+	for i, target := range trigger.Targets {
+		targetName := fmt.Sprintf("t%d", i+1)
+
+		fetchResult, err := metricsSource.Fetch(target, from, to, true)
+		if err != nil {
+			return plots, err
+		}
+		metricsData := fetchResult.GetMetricsData()
+		if len(metricsData) == 0 {
+			continue
+		}
+
+		renderable, err := plotTemplate.GetRenderable(targetName, trigger, metricsData)
+		if err != nil {
+			return plots, err
+		}
+		buff := bytes.NewBuffer(make([]byte, 0))
+		if err = plotting.Render(renderable, moira.PlotFormatPNG, buff); err != nil {
+			return plots, err
+		}
+		plots = append(plots, buff.Bytes())
+	}
+	return plots, nil
+}
@@ -0,0 +1,74 @@
+package chatops
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	slackSignatureVersion = "v0"
+	slackSignatureHeader  = "X-Slack-Signature"
+	slackTimestampHeader  = "X-Slack-Request-Timestamp"
+	// slackMaxRequestAge protects from replaying of intercepted requests.
+	slackMaxRequestAge = 5 * time.Minute
+)
+
+// ErrInvalidSlackSignature is returned when slash command request is not signed by Slack.
+var ErrInvalidSlackSignature = errors.New("invalid slack request signature")
+
+// SlackResponse is the response to Slack slash command.
+type SlackResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// VerifySlackRequest checks signature of slash command request with signing secret of Slack app.
+func VerifySlackRequest(signingSecret string, header http.Header, body []byte, now time.Time) error {
+	timestamp, err := strconv.ParseInt(header.Get(slackTimestampHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp", ErrInvalidSlackSignature)
+	}
+	requestTime := time.Unix(timestamp, 0)
+	if now.Sub(requestTime) > slackMaxRequestAge || requestTime.Sub(now) > slackMaxRequestAge {
+		return fmt.Errorf("%w: request is too old", ErrInvalidSlackSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte(fmt.Sprintf("%s:%d:%s", slackSignatureVersion, timestamp, body)))
+	expected := slackSignatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get(slackSignatureHeader))) {
+		return ErrInvalidSlackSignature
+	}
+	return nil
+}
+
+// ParseSlackCommand builds request from slash command form, e.g. "/moira status backend" is executed as "/status backend".
+// Slack users are identified by their ids.
+// Command without text is executed as help.
+func ParseSlackCommand(form url.Values) Request {
+	text := strings.TrimSpace(form.Get("text"))
+	if text == "" {
+		text = "help"
+	}
+	return Request{
+		User: form.Get("user_id"),
+		Text: commandPrefix + text,
+	}
+}
+
+// NewSlackResponse converts command response to Slack slash command response visible only to the user,
+// plots can't be attached to it, so link to trigger is the only way to see them.
+func NewSlackResponse(response Response) SlackResponse {
+	return SlackResponse{
+		ResponseType: "ephemeral",
+		Text:         response.Text,
+	}
+}
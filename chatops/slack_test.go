@@ -0,0 +1,66 @@
+package chatops
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func signSlackRequest(signingSecret string, timestamp int64, body string) http.Header {
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + strconv.FormatInt(timestamp, 10) + ":" + body))
+	header := http.Header{}
+	header.Set(slackTimestampHeader, strconv.FormatInt(timestamp, 10))
+	header.Set(slackSignatureHeader, "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func TestVerifySlackRequest(t *testing.T) {
+	Convey("Verify Slack request", t, func() {
+		now := time.Unix(1700000000, 0)
+		body := []byte("text=status+backend&user_id=U123")
+
+		Convey("Valid signature", func() {
+			header := signSlackRequest("secret", now.Unix(), string(body))
+			So(VerifySlackRequest("secret", header, body, now), ShouldBeNil)
+		})
+
+		Convey("Signed with another secret", func() {
+			header := signSlackRequest("another", now.Unix(), string(body))
+			So(VerifySlackRequest("secret", header, body, now), ShouldEqual, ErrInvalidSlackSignature)
+		})
+
+		Convey("Changed body", func() {
+			header := signSlackRequest("secret", now.Unix(), string(body))
+			So(VerifySlackRequest("secret", header, []byte("text=mute"), now), ShouldEqual, ErrInvalidSlackSignature)
+		})
+
+		Convey("Old request", func() {
+			timestamp := now.Add(-10 * time.Minute).Unix()
+			header := signSlackRequest("secret", timestamp, string(body))
+			So(errors.Is(VerifySlackRequest("secret", header, body, now), ErrInvalidSlackSignature), ShouldBeTrue)
+		})
+
+		Convey("Without timestamp", func() {
+			So(errors.Is(VerifySlackRequest("secret", http.Header{}, body, now), ErrInvalidSlackSignature), ShouldBeTrue)
+		})
+	})
+}
+
+func TestParseSlackCommand(t *testing.T) {
+	Convey("Parse Slack command", t, func() {
+		request := ParseSlackCommand(url.Values{"text": {"status backend"}, "user_id": {"U123"}})
+		So(request, ShouldResemble, Request{User: "U123", Text: "/status backend"})
+
+		request = ParseSlackCommand(url.Values{"user_id": {"U123"}})
+		So(request, ShouldResemble, Request{User: "U123", Text: "/help"})
+	})
+}
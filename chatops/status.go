@@ -0,0 +1,81 @@
+package chatops
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moira-alert/moira"
+)
+
+// status lists triggers with problems by tag.
+func (commands *Commands) status(_ string, args []string) (Response, error) {
+	if len(args) != 1 {
+		return Response{Text: "Usage: /status <tag>"}, nil
+	}
+	tag := args[0]
+
+	checks, err := commands.getProblemTriggerChecks(tag)
+	if err != nil {
+		return Response{}, err
+	}
+	if len(checks) == 0 {
+		return Response{Text: fmt.Sprintf("No problems with tag %s.", tag)}, nil
+	}
+
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checks[i].LastCheck.Score > checks[j].LastCheck.Score
+	})
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Problems with tag %s:\n", tag))
+	for i, check := range checks {
+		if i == maxListedItems {
+			builder.WriteString(fmt.Sprintf("...and %d more\n", len(checks)-maxListedItems))
+			break
+		}
+		builder.WriteString(fmt.Sprintf("%s %s %s\n", check.LastCheck.State, check.Name, commands.triggerURI(check.ID)))
+	}
+	return Response{Text: strings.TrimSuffix(builder.String(), "\n")}, nil
+}
+
+// getProblemTriggerChecks returns checks of triggers with problems by tag,
+// search index is used if it is available, otherwise all triggers with tag are checked.
+func (commands *Commands) getProblemTriggerChecks(tag string) ([]*moira.TriggerCheck, error) {
+	var triggerIDs []string
+	if commands.searcher != nil {
+		searchResults, _, err := commands.searcher.SearchTriggers(moira.SearchOptions{
+			Size:         -1,
+			OnlyProblems: true,
+			Tags:         []string{tag},
+		})
+		if err != nil {
+			return nil, err
+		}
+		triggerIDs = make([]string, 0, len(searchResults))
+		for _, searchResult := range searchResults {
+			triggerIDs = append(triggerIDs, searchResult.ObjectID)
+		}
+	} else {
+		var err error
+		if triggerIDs, err = commands.database.GetTagTriggerIDs(tag); err != nil {
+			return nil, err
+		}
+	}
+
+	checks, err := commands.database.GetTriggerChecks(triggerIDs)
+	if err != nil {
+		return nil, err
+	}
+	problems := make([]*moira.TriggerCheck, 0, len(checks))
+	for _, check := range checks {
+		if check != nil && check.LastCheck.Score > 0 {
+			problems = append(problems, check)
+		}
+	}
+	return problems, nil
+}
+
+func (commands *Commands) triggerURI(triggerID string) string {
+	return moira.TriggerData{ID: triggerID}.GetTriggerURI(commands.config.FrontURI)
+}
@@ -0,0 +1,46 @@
+package chatops
+
+import (
+	"fmt"
+	"strings"
+)
+
+// subscriptions lists subscriptions of user.
+func (commands *Commands) subscriptions(login string, _ []string) (Response, error) {
+	subscriptionIDs, err := commands.database.GetUserSubscriptionIDs(login)
+	if err != nil {
+		return Response{}, err
+	}
+	subscriptions, err := commands.database.GetSubscriptions(subscriptionIDs)
+	if err != nil {
+		return Response{}, err
+	}
+
+	var builder strings.Builder
+	count := 0
+	for _, subscription := range subscriptions {
+		if subscription == nil {
+			continue
+		}
+		if count == maxListedItems {
+			builder.WriteString("...and more\n")
+			break
+		}
+		count++
+
+		status := "enabled"
+		if !subscription.Enabled {
+			status = "disabled"
+		}
+		tags := strings.Join(subscription.Tags, ", ")
+		if subscription.AnyTags {
+			tags = "any tags"
+		}
+		builder.WriteString(fmt.Sprintf("%s: %s, %d contacts (%s)\n", subscription.ID, tags, len(subscription.Contacts), status))
+	}
+
+	if count == 0 {
+		return Response{Text: fmt.Sprintf("User %s has no subscriptions", login)}, nil
+	}
+	return Response{Text: fmt.Sprintf("Subscriptions of %s:\n%s", login, strings.TrimSuffix(builder.String(), "\n"))}, nil
+}
@@ -0,0 +1,204 @@
+package chatops
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+const (
+	defaultAckDuration = time.Hour
+	maxMuteDuration    = 30 * 24 * time.Hour
+	// maxTriggerLockAttempts is the number of attempts to lock trigger check while setting maintenance.
+	maxTriggerLockAttempts = 10
+)
+
+// trigger shows trigger state, metrics in problem state and plot.
+func (commands *Commands) trigger(_ string, args []string) (Response, error) {
+	if len(args) != 1 {
+		return Response{Text: "Usage: /trigger <trigger id>"}, nil
+	}
+
+	check, response, err := commands.getTriggerCheck(args[0])
+	if check == nil {
+		return response, err
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%s %s\n", check.LastCheck.State, check.Name))
+	if check.LastCheck.Message != "" {
+		builder.WriteString(check.LastCheck.Message + "\n")
+	}
+	if check.LastCheck.IsTriggerOnMaintenance() {
+		builder.WriteString(fmt.Sprintf("Muted until %s\n", commands.formatTime(check.LastCheck.Maintenance)))
+	}
+
+	problems := getProblemMetrics(check.LastCheck)
+	for i, metric := range problems {
+		if i == maxListedItems {
+			builder.WriteString(fmt.Sprintf("...and %d more\n", len(problems)-maxListedItems))
+			break
+		}
+		builder.WriteString(fmt.Sprintf("%s %s", check.LastCheck.Metrics[metric].State, metric))
+		if check.LastCheck.IsMetricOnMaintenance(metric) {
+			builder.WriteString(" (muted)")
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString(commands.triggerURI(check.ID))
+
+	plots, err := commands.buildTriggerPlots(&check.Trigger)
+	if err != nil {
+		commands.logger.Warning().
+			String(moira.LogFieldNameTriggerID, check.ID).
+			Error(err).
+			Msg("Failed to build plots of trigger for chat")
+	}
+	return Response{Text: builder.String(), Plots: plots}, nil
+}
+
+// mute sets maintenance to the whole trigger.
+func (commands *Commands) mute(login string, args []string) (Response, error) {
+	if len(args) != 2 { //nolint:gomnd
+		return Response{Text: "Usage: /mute <trigger id> <duration>, e.g. /mute abc 1h"}, nil
+	}
+	duration, ok := parseMaintenanceDuration(args[1])
+	if !ok {
+		return Response{Text: invalidDurationText(args[1])}, nil
+	}
+
+	check, response, err := commands.getEditableTriggerCheck(login, args[0])
+	if check == nil {
+		return response, err
+	}
+
+	now := commands.clock()
+	maintenance := now.Add(duration).Unix()
+	if err = commands.setMaintenance(check.ID, nil, &maintenance, login, now.Unix()); err != nil {
+		return Response{}, err
+	}
+	return Response{Text: fmt.Sprintf("Trigger %s is muted until %s", check.Name, commands.formatTime(maintenance))}, nil
+}
+
+// ack sets maintenance to metrics in problem state, so new problems of trigger are still reported.
+func (commands *Commands) ack(login string, args []string) (Response, error) {
+	if len(args) != 1 && len(args) != 2 { //nolint:gomnd
+		return Response{Text: "Usage: /ack <trigger id> [duration], e.g. /ack abc 30m"}, nil
+	}
+	duration := defaultAckDuration
+	if len(args) == 2 { //nolint:gomnd
+		var ok bool
+		if duration, ok = parseMaintenanceDuration(args[1]); !ok {
+			return Response{Text: invalidDurationText(args[1])}, nil
+		}
+	}
+
+	check, response, err := commands.getEditableTriggerCheck(login, args[0])
+	if check == nil {
+		return response, err
+	}
+
+	problems := getProblemMetrics(check.LastCheck)
+	if len(problems) == 0 {
+		return Response{Text: fmt.Sprintf("Trigger %s has no metrics in problem state", check.Name)}, nil
+	}
+
+	now := commands.clock()
+	maintenance := now.Add(duration).Unix()
+	metrics := make(map[string]int64, len(problems))
+	for _, metric := range problems {
+		metrics[metric] = maintenance
+	}
+	if err = commands.setMaintenance(check.ID, metrics, nil, login, now.Unix()); err != nil {
+		return Response{}, err
+	}
+	return Response{Text: fmt.Sprintf("%d metrics of trigger %s are acknowledged until %s", len(problems), check.Name, commands.formatTime(maintenance))}, nil
+}
+
+// maintenanceAuditState is the audit state of maintenance, it has the same format as maintenance set by API.
+type maintenanceAuditState struct {
+	Trigger *int64           `json:"trigger"`
+	Metrics map[string]int64 `json:"metrics"`
+}
+
+func (commands *Commands) setMaintenance(triggerID string, metrics map[string]int64, triggerMaintenance *int64, login string, callTime int64) error {
+	if err := commands.database.AcquireTriggerCheckLock(triggerID, maxTriggerLockAttempts); err != nil {
+		return err
+	}
+	defer commands.database.ReleaseTriggerCheckLock(triggerID)
+	if err := commands.database.SetTriggerCheckMaintenance(triggerID, metrics, triggerMaintenance, login, callTime); err != nil {
+		return err
+	}
+	commands.auditRecorder.Record(login, "", moira.AuditActionUpdate, moira.AuditObjectTriggerMaintenance, triggerID, nil,
+		maintenanceAuditState{Trigger: triggerMaintenance, Metrics: metrics})
+	return nil
+}
+
+// getTriggerCheck returns trigger check, response explains why the check is not returned.
+func (commands *Commands) getTriggerCheck(triggerID string) (*moira.TriggerCheck, Response, error) {
+	checks, err := commands.database.GetTriggerChecks([]string{triggerID})
+	if err != nil && !errors.Is(err, database.ErrNil) {
+		return nil, Response{}, err
+	}
+	if len(checks) == 0 || checks[0] == nil {
+		return nil, Response{Text: fmt.Sprintf("Trigger %s not found", triggerID)}, nil
+	}
+	return checks[0], Response{}, nil
+}
+
+// getEditableTriggerCheck returns trigger check if user is allowed to change trigger,
+// triggers of teams can be changed only by team editors and owners, see moira.GetTeamUserRole.
+func (commands *Commands) getEditableTriggerCheck(login, triggerID string) (*moira.TriggerCheck, Response, error) {
+	check, response, err := commands.getTriggerCheck(triggerID)
+	if check == nil || check.TeamID == "" {
+		return check, response, err
+	}
+
+	isMember, err := commands.database.IsTeamContainUser(check.TeamID, login)
+	if err != nil {
+		return nil, Response{}, err
+	}
+	if isMember {
+		roles, err := commands.database.GetTeamUserRoles(check.TeamID)
+		if err != nil {
+			return nil, Response{}, err
+		}
+		if moira.GetTeamUserRole(roles, login).Includes(moira.TeamRoleEditor) {
+			return check, Response{}, nil
+		}
+	}
+	return nil, Response{Text: fmt.Sprintf("You are not allowed to change trigger %s of team %s", check.Name, check.TeamID)}, nil
+}
+
+// getProblemMetrics returns sorted names of metrics which are not in OK state.
+func getProblemMetrics(checkData moira.CheckData) []string {
+	metrics := make([]string, 0)
+	for metric, state := range checkData.Metrics {
+		if state.State != moira.StateOK {
+			metrics = append(metrics, metric)
+		}
+	}
+	sort.Strings(metrics)
+	return metrics
+}
+
+func parseMaintenanceDuration(value string) (time.Duration, bool) {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 || duration > maxMuteDuration {
+		return 0, false
+	}
+	return duration, true
+}
+
+func invalidDurationText(value string) string {
+	return fmt.Sprintf("Invalid duration %s, use e.g. 30m or 2h, max is %s", value, maxMuteDuration)
+}
+
+func (commands *Commands) formatTime(timestamp int64) string {
+	return time.Unix(timestamp, 0).In(commands.config.Location).Format("15:04 02.01.2006")
+}
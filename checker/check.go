@@ -55,6 +55,14 @@ func (triggerChecker *TriggerChecker) Check() error {
 		}
 	}
 
+	if len(triggerChecker.metricsTimeline) > 0 {
+		if err = triggerChecker.database.PushMetricStatesTimeline(triggerChecker.triggerID, triggerChecker.metricsTimeline); err != nil {
+			triggerChecker.logger.Warning().
+				Error(err).
+				Msg("Failed to save metric states timeline")
+		}
+	}
+
 	checkData.UpdateScore()
 	return triggerChecker.database.SetTriggerLastCheck(
 		triggerChecker.triggerID,
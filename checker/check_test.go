@@ -804,6 +804,9 @@ func TestCheck(t *testing.T) {
 					dataBase.EXPECT().GetMetricsTTLSeconds().Return(metricsTTL),
 					dataBase.EXPECT().RemoveMetricsValues([]string{metric}, triggerChecker.until-metricsTTL).Return(nil),
 					dataBase.EXPECT().PushNotificationEvent(&event, true).Return(nil),
+					dataBase.EXPECT().PushMetricStatesTimeline(triggerChecker.triggerID, map[string][]moira.MetricStatePoint{
+						metric: {{Timestamp: 17, State: moira.StateOK}},
+					}).Return(nil),
 					dataBase.EXPECT().SetTriggerLastCheck(
 						triggerChecker.triggerID,
 						&lastCheck,
@@ -852,6 +855,9 @@ func TestCheck(t *testing.T) {
 				dataBase.EXPECT().GetMetricsTTLSeconds().Return(metricsTTL),
 				dataBase.EXPECT().RemoveMetricsValues([]string{metric}, triggerChecker.until-metricsTTL).Return(nil),
 				dataBase.EXPECT().PushNotificationEvent(&event, true).Return(nil),
+				dataBase.EXPECT().PushMetricStatesTimeline(triggerChecker.triggerID, map[string][]moira.MetricStatePoint{
+					metric: {{Timestamp: 17, State: moira.StateOK}, {Timestamp: 57, State: moira.StateERROR}},
+				}).Return(nil),
 				dataBase.EXPECT().SetTriggerLastCheck(
 					triggerChecker.triggerID,
 					&lastCheck,
@@ -898,6 +904,9 @@ func TestCheck(t *testing.T) {
 			})
 			fetchResult.EXPECT().GetPatternMetrics().Return([]string{metric}, nil)
 			dataBase.EXPECT().PushNotificationEvent(&event, true).Return(nil)
+			dataBase.EXPECT().PushMetricStatesTimeline(triggerChecker.triggerID, map[string][]moira.MetricStatePoint{
+				metric: {{Timestamp: 17, State: moira.StateOK}},
+			}).Return(nil)
 			dataBase.EXPECT().SetTriggerLastCheck(
 				triggerChecker.triggerID,
 				&lastCheck,
@@ -972,6 +981,11 @@ func TestCheck(t *testing.T) {
 				dataBase.EXPECT().GetMetricsTTLSeconds().Return(metricsTTL),
 				dataBase.EXPECT().RemoveMetricsValues([]string{metricName1, metricNameAlone, metricName2}, triggerChecker.until-metricsTTL).Return(nil),
 
+				dataBase.EXPECT().PushMetricStatesTimeline(triggerChecker.triggerID, map[string][]moira.MetricStatePoint{
+					metricName1: {{Timestamp: -3533, State: moira.StateNODATA}},
+					metricName2: {{Timestamp: -3533, State: moira.StateNODATA}},
+				}).Return(nil),
+
 				dataBase.EXPECT().SetTriggerLastCheck(
 					triggerChecker.triggerID,
 					&lastCheck,
@@ -1570,6 +1584,9 @@ func TestTriggerChecker_Check(t *testing.T) {
 	source.EXPECT().Fetch(pattern, triggerChecker.from, triggerChecker.until, true).Return(fetchResult, nil)
 	fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData(metric, []float64{0, 1, 2, 3, 4}, retention, triggerChecker.from)})
 	fetchResult.EXPECT().GetPatternMetrics().Return([]string{metric}, nil)
+	dataBase.EXPECT().PushMetricStatesTimeline(triggerChecker.triggerID, map[string][]moira.MetricStatePoint{
+		metric: {{Timestamp: 17, State: moira.StateOK}},
+	}).Return(nil)
 	dataBase.EXPECT().SetTriggerLastCheck(
 		triggerChecker.triggerID,
 		&lastCheck,
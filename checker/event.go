@@ -105,6 +105,7 @@ func (triggerChecker *TriggerChecker) compareMetricStates(metric string, current
 			currentState.Suppressed = false
 			currentState.SuppressedState = ""
 		}
+		triggerChecker.addMetricTimelinePoint(metric, currentState, lastState, maintenanceTimestamp)
 		return currentState, nil
 	}
 
@@ -116,11 +117,13 @@ func (triggerChecker *TriggerChecker) compareMetricStates(metric string, current
 		if !lastState.Suppressed {
 			currentState.SuppressedState = lastState.State
 		}
+		triggerChecker.addMetricTimelinePoint(metric, currentState, lastState, maintenanceTimestamp)
 		return currentState, nil
 	}
 
 	currentState.Suppressed = false
 	currentState.SuppressedState = ""
+	triggerChecker.addMetricTimelinePoint(metric, currentState, lastState, maintenanceTimestamp)
 
 	err := triggerChecker.database.PushNotificationEvent(&moira.NotificationEvent{
 		TriggerID:        triggerChecker.triggerID,
@@ -134,6 +137,22 @@ func (triggerChecker *TriggerChecker) compareMetricStates(metric string, current
 	return currentState, err
 }
 
+// addMetricTimelinePoint remembers metric state if it differs from the last one, metric without events gets its first point.
+func (triggerChecker *TriggerChecker) addMetricTimelinePoint(metric string, currentState moira.MetricState, lastState moira.MetricState, maintenanceTimestamp int64) {
+	if lastState.EventTimestamp != 0 && currentState.State == lastState.State && currentState.Suppressed == lastState.Suppressed {
+		return
+	}
+	if triggerChecker.metricsTimeline == nil {
+		triggerChecker.metricsTimeline = make(map[string][]moira.MetricStatePoint)
+	}
+	triggerChecker.metricsTimeline[metric] = append(triggerChecker.metricsTimeline[metric], moira.MetricStatePoint{
+		Timestamp:   currentState.Timestamp,
+		State:       currentState.State,
+		Suppressed:  currentState.Suppressed,
+		Maintenance: maintenanceTimestamp,
+	})
+}
+
 func getEventOldState(lastCheckState moira.State, lastCheckSuppressedState moira.State, isSuppressed bool) moira.State {
 	if isSuppressed {
 		return lastCheckSuppressedState
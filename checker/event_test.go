@@ -692,3 +692,61 @@ func TestIsStateChanged(t *testing.T) {
 		})
 	})
 }
+
+func TestMetricsTimeline(t *testing.T) {
+	Convey("Test metric states timeline", t, func() {
+		dataBase, mockCtrl := newMocks(t)
+		defer mockCtrl.Finish()
+		logger, _ := logging.GetLogger("Test")
+
+		triggerChecker := TriggerChecker{
+			triggerID: "SuperId",
+			database:  dataBase,
+			logger:    logger,
+			trigger:   &moira.Trigger{},
+			lastCheck: &moira.CheckData{},
+		}
+		lastState := moira.MetricState{
+			State:          moira.StateOK,
+			Timestamp:      1000,
+			EventTimestamp: 900,
+		}
+
+		Convey("Same state is not added", func() {
+			currentState := moira.MetricState{State: moira.StateOK, Timestamp: 1060}
+			_, err := triggerChecker.compareMetricStates("m1", currentState, lastState)
+			So(err, ShouldBeNil)
+			So(triggerChecker.metricsTimeline, ShouldBeEmpty)
+		})
+
+		Convey("State change is added", func() {
+			currentState := moira.MetricState{State: moira.StateERROR, Timestamp: 1060}
+			dataBase.EXPECT().PushNotificationEvent(gomock.Any(), true).Return(nil)
+			_, err := triggerChecker.compareMetricStates("m1", currentState, lastState)
+			So(err, ShouldBeNil)
+			So(triggerChecker.metricsTimeline, ShouldResemble, map[string][]moira.MetricStatePoint{
+				"m1": {{Timestamp: 1060, State: moira.StateERROR}},
+			})
+		})
+
+		Convey("Suppressed state change is added with maintenance", func() {
+			lastState.Maintenance = 2000
+			currentState := moira.MetricState{State: moira.StateERROR, Timestamp: 1060, Maintenance: 2000}
+			_, err := triggerChecker.compareMetricStates("m1", currentState, lastState)
+			So(err, ShouldBeNil)
+			So(triggerChecker.metricsTimeline, ShouldResemble, map[string][]moira.MetricStatePoint{
+				"m1": {{Timestamp: 1060, State: moira.StateERROR, Suppressed: true, Maintenance: 2000}},
+			})
+		})
+
+		Convey("First state of new metric is added", func() {
+			lastState.EventTimestamp = 0
+			currentState := moira.MetricState{State: moira.StateOK, Timestamp: 1060}
+			_, err := triggerChecker.compareMetricStates("m1", currentState, lastState)
+			So(err, ShouldBeNil)
+			So(triggerChecker.metricsTimeline, ShouldResemble, map[string][]moira.MetricStatePoint{
+				"m1": {{Timestamp: 1060, State: moira.StateOK}},
+			})
+		})
+	})
+}
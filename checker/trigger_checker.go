@@ -27,6 +27,9 @@ type TriggerChecker struct {
 
 	ttl      int64
 	ttlState moira.TTLState
+
+	// metricsTimeline collects changes of metrics states which are saved along with check data
+	metricsTimeline map[string][]moira.MetricStatePoint
 }
 
 // MakeTriggerChecker initialize new triggerChecker data.
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/moira-alert/moira"
@@ -9,7 +10,13 @@ import (
 	"github.com/xiam/to"
 
 	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/exporter"
+	"github.com/moira-alert/moira/api/oidc"
+	"github.com/moira-alert/moira/audit"
+	"github.com/moira-alert/moira/chatops"
 	"github.com/moira-alert/moira/cmd"
+	"github.com/moira-alert/moira/image_store/filesystem"
+	metricSource "github.com/moira-alert/moira/metric_source"
 )
 
 type config struct {
@@ -48,6 +55,154 @@ type apiConfig struct {
 	EnableCORS bool `yaml:"enable_cors"`
 	// Authorization contains authorization configuration.
 	Authorization authorization `yaml:"authorization"`
+	// Audit contains configuration of additional audit log destinations.
+	Audit auditConfig `yaml:"audit"`
+	// PrometheusExporter contains configuration of trigger states exposition at /api/prometheus/metrics.
+	PrometheusExporter prometheusExporterConfig `yaml:"prometheus_exporter"`
+	// NotificationPreview contains configuration of notifications rendering at /api/notification/preview.
+	NotificationPreview notificationPreviewConfig `yaml:"notification_preview"`
+	// ImageStore contains configuration of serving plots saved by notifier at /api/image.
+	// It must be the same as image_store.filesystem in notifier config.
+	ImageStore filesystem.Config `yaml:"image_store"`
+	// ChatOps contains configuration of commands sent from chats.
+	ChatOps chatOpsConfig `yaml:"chatops"`
+}
+
+type chatOpsConfig struct {
+	// Moira web ui address used in links to triggers.
+	FrontURI string `yaml:"front_uri"`
+	// Timezone to render time in responses and on plots. Default is UTC.
+	Timezone string `yaml:"timezone"`
+	// Slack contains configuration of Slack slash command endpoint at /api/chatops/slack.
+	Slack slackChatOpsConfig `yaml:"slack"`
+}
+
+type slackChatOpsConfig struct {
+	// If true, Slack slash commands are executed.
+	Enabled bool `yaml:"enabled"`
+	// Signing secret of Slack app used to verify requests.
+	SigningSecret string `yaml:"signing_secret"`
+	// Users maps Slack user ids to moira logins, only mapped users are allowed to execute commands.
+	Users map[string]string `yaml:"users"`
+}
+
+func (config *chatOpsConfig) getSlackSettings(
+	database moira.Database,
+	searcher moira.Searcher,
+	metricSourceProvider *metricSource.SourceProvider,
+	logger moira.Logger,
+	auditRecorder *audit.Recorder,
+) (api.SlackChatOps, error) {
+	if !config.Slack.Enabled {
+		return api.SlackChatOps{}, nil
+	}
+	if config.Slack.SigningSecret == "" {
+		return api.SlackChatOps{}, fmt.Errorf("signing_secret of slack chatops is required")
+	}
+
+	location, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		return api.SlackChatOps{}, fmt.Errorf("failed to load timezone %s: %w", config.Timezone, err)
+	}
+
+	commands := chatops.NewCommands(chatops.Config{
+		Users:    config.Slack.Users,
+		FrontURI: config.FrontURI,
+		Location: location,
+	}, database, searcher, metricSourceProvider, logger, auditRecorder)
+
+	return api.SlackChatOps{
+		Enabled:       true,
+		SigningSecret: config.Slack.SigningSecret,
+		Commands:      commands,
+	}, nil
+}
+
+type notificationPreviewConfig struct {
+	// If true, notifications can be rendered the way senders deliver them without actual delivery.
+	Enabled bool `yaml:"enabled"`
+	// Moira web ui address used in links to triggers.
+	FrontURI string `yaml:"front_uri"`
+	// Timezone to render time in notifications. Default is UTC.
+	Timezone string `yaml:"timezone"`
+	// Format to render time in notifications. Default is 15:04 02.01.2006.
+	DateTimeFormat string `yaml:"date_time_format"`
+	// Senders settings in the same format as in notifier config. Credentials of external services are not required.
+	Senders []map[string]interface{} `yaml:"senders"`
+}
+
+func (config *notificationPreviewConfig) getSettings(logger moira.Logger) (api.NotificationPreview, error) {
+	if !config.Enabled {
+		return api.NotificationPreview{}, nil
+	}
+
+	location, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		return api.NotificationPreview{}, fmt.Errorf("failed to load timezone %s: %w", config.Timezone, err)
+	}
+
+	builders, err := notifier.NewMessageBuilders(config.Senders, config.FrontURI, logger, location, config.DateTimeFormat)
+	if err != nil {
+		return api.NotificationPreview{}, err
+	}
+
+	return api.NotificationPreview{
+		Enabled:  true,
+		Builders: builders,
+		Location: location,
+	}, nil
+}
+
+type prometheusExporterConfig struct {
+	// If true, states of triggers and metrics are exposed in Prometheus format.
+	Enabled bool `yaml:"enabled"`
+	// Period during which trigger checks are reused between scrapes. Default is 1m.
+	CacheTTL string `yaml:"cache_ttl"`
+	// Labels added to trigger series in addition to trigger_id: name, team_id, tags, trigger_source, cluster_id.
+	// Default is name and team_id.
+	TriggerLabels []string `yaml:"trigger_labels"`
+	// If true, moira_trigger_tag_info series are exposed for each tag of each trigger.
+	TagSeries bool `yaml:"tag_series"`
+	// If true, series are exposed for each metric of each trigger. It can produce a lot of series.
+	MetricStates bool `yaml:"metric_states"`
+}
+
+func (config *prometheusExporterConfig) getSettings() exporter.Config {
+	return exporter.Config{
+		Enabled:       config.Enabled,
+		CacheTTL:      to.Duration(config.CacheTTL),
+		TriggerLabels: config.TriggerLabels,
+		TagSeries:     config.TagSeries,
+		MetricStates:  config.MetricStates,
+	}
+}
+
+type auditConfig struct {
+	// Path to the file audit records are appended to in JSON lines format. Empty value disables file sink.
+	File string `yaml:"file"`
+	// URL audit records are sent to with POST request. Empty value disables webhook sink.
+	WebhookURL string `yaml:"webhook_url"`
+	// Timeout of audit webhook request. Default is 5s.
+	WebhookTimeout string `yaml:"webhook_timeout"`
+	// Count of audit records waiting to be written to file and webhook, newer records are dropped if queue is full. Default is 1000.
+	QueueSize int `yaml:"queue_size"`
+}
+
+func (config *auditConfig) getSinks() ([]audit.Sink, error) {
+	sinks := make([]audit.Sink, 0)
+	if config.File != "" {
+		fileSink, err := audit.NewFileSink(config.File)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, fileSink)
+	}
+
+	if config.WebhookURL != "" {
+		sinks = append(sinks, audit.NewWebhookSink(config.WebhookURL, to.Duration(config.WebhookTimeout)))
+	}
+
+	return sinks, nil
 }
 
 type authorization struct {
@@ -55,6 +210,64 @@ type authorization struct {
 	Enabled bool `yaml:"enabled"`
 	// List of logins of users who are considered to be admins.
 	AdminList []string `yaml:"admin_list"`
+	// OIDC contains configuration of authentication with bearer tokens.
+	OIDC oidcConfig `yaml:"oidc"`
+}
+
+type oidcConfig struct {
+	// If true, users are identified by bearer tokens issued by OpenID Connect identity provider.
+	Enabled bool `yaml:"enabled"`
+	// Expected token issuer. If jwks_url is empty, it is discovered from issuer metadata.
+	Issuer string `yaml:"issuer"`
+	// Expected token audience, empty value disables the check.
+	Audience string `yaml:"audience"`
+	// URL of identity provider public keys in JWKS format.
+	JWKSURL string `yaml:"jwks_url"`
+	// Token claim which contains user login. Default is preferred_username.
+	LoginClaim string `yaml:"login_claim"`
+	// Token claim which contains list of user groups. Default is groups.
+	GroupsClaim string `yaml:"groups_claim"`
+	// Members of these groups are considered to be admins.
+	AdminGroups []string `yaml:"admin_groups"`
+	// Members of group are added to listed teams, keys are groups and values are team IDs.
+	// Groups are authoritative for these teams: when groups of user change, user is removed from teams
+	// which are not mapped from user groups anymore, and removing user from mapped team via API lasts only
+	// until the next change of user groups.
+	TeamGroups map[string][]string `yaml:"team_groups"`
+	// If true, requests without bearer token are identified by x-webauth-user header.
+	HeaderFallback bool `yaml:"header_fallback"`
+	// Allowed clock skew while checking token lifetime. Default is 1m.
+	Leeway string `yaml:"leeway"`
+	// Timeout of requests to identity provider. Default is 5s.
+	Timeout string `yaml:"timeout"`
+}
+
+func (config *oidcConfig) getSettings() api.Authentication {
+	if !config.Enabled {
+		return api.Authentication{}
+	}
+
+	adminGroups := make(map[string]struct{}, len(config.AdminGroups))
+	for _, group := range config.AdminGroups {
+		adminGroups[group] = struct{}{}
+	}
+
+	verifier := oidc.NewVerifier(oidc.Config{
+		Issuer:      config.Issuer,
+		Audience:    config.Audience,
+		JWKSURL:     config.JWKSURL,
+		LoginClaim:  config.LoginClaim,
+		GroupsClaim: config.GroupsClaim,
+		Leeway:      to.Duration(config.Leeway),
+		Timeout:     to.Duration(config.Timeout),
+	})
+
+	return api.Authentication{
+		TokenVerifier:  verifier,
+		HeaderFallback: config.HeaderFallback,
+		AdminGroups:    adminGroups,
+		TeamGroups:     config.TeamGroups,
+	}
 }
 
 type sentryConfig struct {
@@ -111,11 +324,12 @@ func (config *apiConfig) getSettings(
 	webConfig *webConfig,
 ) *api.Config {
 	return &api.Config{
-		EnableCORS:    config.EnableCORS,
-		Listen:        config.Listen,
-		MetricsTTL:    metricsTTL,
-		Flags:         flags,
-		Authorization: config.Authorization.toApiConfig(webConfig),
+		EnableCORS:         config.EnableCORS,
+		Listen:             config.Listen,
+		MetricsTTL:         metricsTTL,
+		Flags:              flags,
+		Authorization:      config.Authorization.toApiConfig(webConfig),
+		PrometheusExporter: config.PrometheusExporter.getSettings(),
 	}
 }
 
@@ -135,6 +349,7 @@ func (auth *authorization) toApiConfig(webConfig *webConfig) api.Authorization {
 		Enabled:             auth.Enabled,
 		AdminList:           adminList,
 		AllowedContactTypes: allowedContactTypes,
+		Authentication:      auth.OIDC.getSettings(),
 	}
 }
 
@@ -204,6 +419,9 @@ func getDefault() config {
 			MetricsTTL:  "1h",
 			DialTimeout: "500ms",
 			MaxRetries:  3,
+
+			AuditLogTTL:       "720h",
+			MetricTimelineTTL: "720h",
 		},
 		NotificationHistory: cmd.NotificationHistoryConfig{
 			NotificationHistoryTTL:        "48h",
@@ -217,6 +435,26 @@ func getDefault() config {
 		API: apiConfig{
 			Listen:     ":8081",
 			EnableCORS: false,
+			Authorization: authorization{
+				OIDC: oidcConfig{
+					LoginClaim:  "preferred_username",
+					GroupsClaim: "groups",
+					Leeway:      "1m",
+					Timeout:     "5s",
+				},
+			},
+			Audit: auditConfig{
+				WebhookTimeout: "5s",
+				QueueSize:      audit.DefaultQueueSize,
+			},
+			PrometheusExporter: prometheusExporterConfig{
+				CacheTTL:      "1m",
+				TriggerLabels: []string{exporter.LabelName, exporter.LabelTeamID},
+			},
+			NotificationPreview: notificationPreviewConfig{
+				Timezone:       "UTC",
+				DateTimeFormat: "15:04 02.01.2006",
+			},
 		},
 		Web: webConfig{
 			RemoteAllowed: false,
@@ -8,6 +8,7 @@ import (
 	"github.com/moira-alert/moira/cmd"
 
 	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/audit"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -79,6 +80,9 @@ func Test_webConfig_getDefault(t *testing.T) {
 				MetricsTTL:  "1h",
 				DialTimeout: "500ms",
 				MaxRetries:  3,
+
+				AuditLogTTL:       "720h",
+				MetricTimelineTTL: "720h",
 			},
 			Logger: cmd.LoggerConfig{
 				LogFile:         "stdout",
@@ -88,6 +92,26 @@ func Test_webConfig_getDefault(t *testing.T) {
 			API: apiConfig{
 				Listen:     ":8081",
 				EnableCORS: false,
+				Authorization: authorization{
+					OIDC: oidcConfig{
+						LoginClaim:  "preferred_username",
+						GroupsClaim: "groups",
+						Leeway:      "1m",
+						Timeout:     "5s",
+					},
+				},
+				Audit: auditConfig{
+					WebhookTimeout: "5s",
+					QueueSize:      audit.DefaultQueueSize,
+				},
+				PrometheusExporter: prometheusExporterConfig{
+					CacheTTL:      "1m",
+					TriggerLabels: []string{"name", "team_id"},
+				},
+				NotificationPreview: notificationPreviewConfig{
+					Timezone:       "UTC",
+					DateTimeFormat: "15:04 02.01.2006",
+				},
 			},
 			Web: webConfig{
 				RemoteAllowed: false,
@@ -249,3 +273,27 @@ func Test_webConfig_getSettings(t *testing.T) {
 		})
 	})
 }
+
+func Test_oidcConfig_getSettings(t *testing.T) {
+	Convey("OIDC settings", t, func() {
+		Convey("Disabled", func() {
+			config := oidcConfig{Issuer: "https://issuer.example.com"}
+			So(config.getSettings(), ShouldResemble, api.Authentication{})
+		})
+
+		Convey("Enabled", func() {
+			config := oidcConfig{
+				Enabled:        true,
+				Issuer:         "https://issuer.example.com",
+				AdminGroups:    []string{"admins"},
+				TeamGroups:     map[string][]string{"devops": {"team"}},
+				HeaderFallback: true,
+			}
+			settings := config.getSettings()
+			So(settings.TokenVerifier, ShouldNotBeNil)
+			So(settings.HeaderFallback, ShouldBeTrue)
+			So(settings.AdminGroups, ShouldResemble, map[string]struct{}{"admins": {}})
+			So(settings.TeamGroups, ShouldResemble, map[string][]string{"devops": {"team"}})
+		})
+	})
+}
@@ -13,11 +13,14 @@ import (
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api/handler"
+	"github.com/moira-alert/moira/audit"
 	"github.com/moira-alert/moira/cmd"
 	"github.com/moira-alert/moira/database/redis"
 	"github.com/moira-alert/moira/database/stats"
+	"github.com/moira-alert/moira/image_store/filesystem"
 	"github.com/moira-alert/moira/index"
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	"github.com/moira-alert/moira/metrics"
 	_ "go.uber.org/automaxprocs"
 )
 
@@ -132,6 +135,52 @@ func main() {
 
 	webConfig := applicationConfig.Web.getSettings(len(metricSourceProvider.GetAllSources()) > 0, applicationConfig.Remotes)
 
+	if err = apiConfig.PrometheusExporter.Validate(); err != nil {
+		logger.Fatal().
+			Error(err).
+			Msg("Invalid prometheus exporter configuration")
+	}
+
+	apiConfig.NotificationPreview, err = applicationConfig.API.NotificationPreview.getSettings(logger)
+	if err != nil {
+		logger.Fatal().
+			Error(err).
+			Msg("Failed to initialize notification preview")
+	}
+
+	if applicationConfig.API.ImageStore != (filesystem.Config{}) {
+		imageStore := &filesystem.ImageStore{}
+		if err = imageStore.Init(applicationConfig.API.ImageStore); err != nil {
+			logger.Fatal().
+				Error(err).
+				Msg("Failed to initialize image store")
+		}
+		apiConfig.ImageServer = imageStore
+	}
+
+	auditSinks, err := applicationConfig.API.Audit.getSinks()
+	if err != nil {
+		logger.Fatal().
+			Error(err).
+			Msg("Failed to initialize audit sinks")
+	}
+	auditRecorder := audit.NewRecorder(
+		database,
+		logger,
+		metrics.ConfigureAuditMetrics(telemetry.Metrics),
+		applicationConfig.API.Audit.QueueSize,
+		auditSinks...,
+	)
+	auditRecorder.Start()
+	defer auditRecorder.Stop() //nolint
+
+	apiConfig.SlackChatOps, err = applicationConfig.API.ChatOps.getSlackSettings(database, searchIndex, metricSourceProvider, logger, auditRecorder)
+	if err != nil {
+		logger.Fatal().
+			Error(err).
+			Msg("Failed to initialize slack chatops")
+	}
+
 	httpHandler := handler.NewHandler(
 		database,
 		logger,
@@ -139,6 +188,7 @@ func main() {
 		apiConfig,
 		metricSourceProvider,
 		webConfig,
+		auditRecorder,
 	)
 
 	server := &http.Server{
@@ -131,6 +131,8 @@ func getDefault() config {
 			Addrs:       "localhost:6379",
 			MetricsTTL:  "1h",
 			DialTimeout: "500ms",
+
+			MetricTimelineTTL: "720h",
 		},
 		Logger: cmd.LoggerConfig{
 			LogFile:         "stdout",
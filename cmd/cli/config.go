@@ -29,6 +29,8 @@ func getDefault() config {
 			Addrs:       "localhost:6379",
 			MetricsTTL:  "1h",
 			DialTimeout: "500ms",
+
+			AuditLogTTL: "720h",
 		},
 		Cleanup: cleanupConfig{
 			Whitelist:                    []string{},
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+// printDeadLetters writes all dead letters as JSON, the newest letters go first.
+func printDeadLetters(database moira.Database, writer io.Writer) error {
+	letters, err := database.GetDeadLetters(0, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get dead letters: %w", err)
+	}
+	return writeJSON(letters, writer)
+}
+
+// printDeadLetter writes dead letter with given id as JSON.
+func printDeadLetter(database moira.Database, deadLetterID string, writer io.Writer) error {
+	letter, err := database.GetDeadLetter(deadLetterID)
+	if err != nil {
+		return fmt.Errorf("failed to get dead letter %s: %w", deadLetterID, err)
+	}
+	return writeJSON(letter, writer)
+}
+
+// replayDeadLetterToContact schedules notifications of dead letter to be sent to given contact and deletes the letter.
+// Notifications are sent to the original contact if contactID is empty. Returns count of scheduled notifications.
+func replayDeadLetterToContact(database moira.Database, deadLetterID, contactID string) (int, error) {
+	letter, err := database.GetDeadLetter(deadLetterID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get dead letter %s: %w", deadLetterID, err)
+	}
+
+	if contactID == "" {
+		contactID = letter.Contact.ID
+	}
+	if contactID == "" {
+		return 0, fmt.Errorf("dead letter %s has no original contact, use -replay-contact", deadLetterID)
+	}
+
+	contact, err := database.GetContact(contactID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get contact %s: %w", contactID, err)
+	}
+
+	now := time.Now().Unix()
+	notifications := letter.ToScheduledNotifications(contact, now)
+	if err = database.AddNotifications(notifications, now); err != nil {
+		return 0, fmt.Errorf("failed to add notifications: %w", err)
+	}
+
+	if err = database.RemoveDeadLetter(deadLetterID); err != nil {
+		return 0, fmt.Errorf("failed to remove dead letter %s: %w", deadLetterID, err)
+	}
+	return len(notifications), nil
+}
+
+func writeJSON(value interface{}, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDeadLetters(t *testing.T) {
+	Convey("Test dead letters", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		letter := moira.DeadLetter{
+			ID:      "letter",
+			Reason:  "contact is broken",
+			Events:  []moira.NotificationEvent{{Metric: "metric"}},
+			Contact: moira.ContactData{ID: "original"},
+		}
+
+		Convey("Print letter", func() {
+			mockDb.EXPECT().GetDeadLetter("letter").Return(letter, nil)
+
+			buffer := &bytes.Buffer{}
+			So(printDeadLetter(mockDb, "letter", buffer), ShouldBeNil)
+			So(buffer.String(), ShouldContainSubstring, `"reason": "contact is broken"`)
+		})
+
+		Convey("Replay letter to the original contact", func() {
+			mockDb.EXPECT().GetDeadLetter("letter").Return(letter, nil)
+			mockDb.EXPECT().GetContact("original").Return(moira.ContactData{ID: "original"}, nil)
+			mockDb.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).Return(nil)
+			mockDb.EXPECT().RemoveDeadLetter("letter").Return(nil)
+
+			count, err := replayDeadLetterToContact(mockDb, "letter", "")
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("Replay letter to another contact", func() {
+			mockDb.EXPECT().GetDeadLetter("letter").Return(letter, nil)
+			mockDb.EXPECT().GetContact("other").Return(moira.ContactData{ID: "other"}, nil)
+			mockDb.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).DoAndReturn(func(notifications []*moira.ScheduledNotification, _ int64) error {
+				So(notifications[0].Contact.ID, ShouldEqual, "other")
+				return nil
+			})
+			mockDb.EXPECT().RemoveDeadLetter("letter").Return(nil)
+
+			count, err := replayDeadLetterToContact(mockDb, "letter", "other")
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("Unknown letter is not replayed", func() {
+			mockDb.EXPECT().GetDeadLetter("unknown").Return(moira.DeadLetter{}, database.ErrNil)
+
+			_, err := replayDeadLetterToContact(mockDb, "unknown", "")
+			So(err, ShouldResemble, fmt.Errorf("failed to get dead letter unknown: %w", database.ErrNil))
+		})
+	})
+}
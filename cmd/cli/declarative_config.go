@@ -0,0 +1,574 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/dto"
+	"gopkg.in/yaml.v2"
+)
+
+// declarativeConfig is a set of Moira objects that can be kept in version control
+// and applied to Moira with plan and apply.
+type declarativeConfig struct {
+	Teams         []declarativeTeam        `json:"teams"`
+	Tags          []string                 `json:"tags"`
+	Contacts      []moira.ContactData      `json:"contacts"`
+	Triggers      []dto.TriggerModel       `json:"triggers"`
+	Subscriptions []moira.SubscriptionData `json:"subscriptions"`
+}
+
+// declarativeTeam is a team with its members. Roles are left untouched when they are not specified.
+type declarativeTeam struct {
+	ID          string                    `json:"id"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Users       []string                  `json:"users"`
+	Roles       map[string]moira.TeamRole `json:"roles"`
+}
+
+// configScope limits declarative config to objects of the team or objects with the tag.
+// Empty scope means all objects.
+type configScope struct {
+	Tag  string
+	Team string
+}
+
+// configStore is a place where Moira objects live, it is redis or Moira API.
+type configStore interface {
+	Load(scope configScope) (*declarativeConfig, error)
+	SaveTeam(team *declarativeTeam, isNew bool) error
+	RemoveTeam(teamID string) error
+	SaveTag(tag string) error
+	RemoveTag(tag string) error
+	SaveContact(contact *moira.ContactData, isNew bool) error
+	RemoveContact(contactID string) error
+	SaveTrigger(trigger *dto.TriggerModel, isNew bool) error
+	RemoveTrigger(triggerID string) error
+	SaveSubscription(subscription *moira.SubscriptionData, isNew bool) error
+	RemoveSubscription(subscriptionID string) error
+}
+
+type configChangeAction string
+
+const (
+	configChangeCreate configChangeAction = "create"
+	configChangeUpdate configChangeAction = "update"
+	configChangeDelete configChangeAction = "delete"
+)
+
+type configObjectKind string
+
+const (
+	configObjectTeam         configObjectKind = "team"
+	configObjectTag          configObjectKind = "tag"
+	configObjectContact      configObjectKind = "contact"
+	configObjectTrigger      configObjectKind = "trigger"
+	configObjectSubscription configObjectKind = "subscription"
+)
+
+// configObjectKinds is the order in which objects are created, objects are deleted in reverse order.
+var configObjectKinds = []configObjectKind{
+	configObjectTeam,
+	configObjectTag,
+	configObjectContact,
+	configObjectTrigger,
+	configObjectSubscription,
+}
+
+// optionalConfigFields are fields that are not compared when they are omitted in desired config.
+// Signing secret of contact is write-only, it is never loaded from store.
+var optionalConfigFields = map[configObjectKind][]string{
+	configObjectTeam:    {"roles"},
+	configObjectContact: {"signing_secret"},
+}
+
+type configObject struct {
+	id    string
+	value interface{}
+}
+
+// configChange is a single step of plan.
+type configChange struct {
+	Action configChangeAction
+	Kind   configObjectKind
+	ID     string
+	Fields []string
+	object interface{}
+}
+
+func (change configChange) String() string {
+	switch change.Action {
+	case configChangeCreate:
+		return fmt.Sprintf("+ %s %s", change.Kind, change.ID)
+	case configChangeUpdate:
+		return fmt.Sprintf("~ %s %s (%s)", change.Kind, change.ID, strings.Join(change.Fields, ", "))
+	default:
+		return fmt.Sprintf("- %s %s", change.Kind, change.ID)
+	}
+}
+
+func (scope configScope) isAll() bool {
+	return scope.Tag == "" && scope.Team == ""
+}
+
+// canPrune returns true if objects of given kind missing in desired config can be deleted in this scope.
+// Tags are shared between scopes and team itself is not removed in team scope.
+func (scope configScope) canPrune(kind configObjectKind) bool {
+	switch {
+	case scope.isAll():
+		return true
+	case scope.Team != "":
+		return kind == configObjectContact || kind == configObjectTrigger || kind == configObjectSubscription
+	default:
+		return kind == configObjectTrigger || kind == configObjectSubscription
+	}
+}
+
+// filter returns objects of config that belong to the scope.
+// In tag scope contacts used by subscriptions are kept, tags used by kept triggers and subscriptions are kept in any scope.
+func (scope configScope) filter(config *declarativeConfig) *declarativeConfig {
+	if scope.isAll() {
+		return config
+	}
+
+	result := &declarativeConfig{}
+	for _, team := range config.Teams {
+		if scope.Team != "" && team.ID == scope.Team {
+			result.Teams = append(result.Teams, team)
+		}
+	}
+	for _, trigger := range config.Triggers {
+		if (scope.Team != "" && trigger.TeamID == scope.Team) || (scope.Tag != "" && slices.Contains(trigger.Tags, scope.Tag)) {
+			result.Triggers = append(result.Triggers, trigger)
+		}
+	}
+	usedContacts := make(map[string]bool)
+	for _, subscription := range config.Subscriptions {
+		if (scope.Team != "" && subscription.TeamID == scope.Team) || (scope.Tag != "" && slices.Contains(subscription.Tags, scope.Tag)) {
+			result.Subscriptions = append(result.Subscriptions, subscription)
+			for _, contactID := range subscription.Contacts {
+				usedContacts[contactID] = true
+			}
+		}
+	}
+	for _, contact := range config.Contacts {
+		if (scope.Team != "" && contact.Team == scope.Team) || (scope.Tag != "" && usedContacts[contact.ID]) {
+			result.Contacts = append(result.Contacts, contact)
+		}
+	}
+	usedTags := make(map[string]bool)
+	for _, trigger := range result.Triggers {
+		for _, tag := range trigger.Tags {
+			usedTags[tag] = true
+		}
+	}
+	for _, subscription := range result.Subscriptions {
+		for _, tag := range subscription.Tags {
+			usedTags[tag] = true
+		}
+	}
+	for _, tag := range config.Tags {
+		if usedTags[tag] {
+			result.Tags = append(result.Tags, tag)
+		}
+	}
+	return result
+}
+
+// normalize removes fields that are computed by Moira and sorts objects by ID so configs can be compared and exported stably.
+func (config *declarativeConfig) normalize() {
+	for i := range config.Teams {
+		sort.Strings(config.Teams[i].Users)
+	}
+	for i := range config.Triggers {
+		trigger := &config.Triggers[i]
+		trigger.TriggerSource = trigger.TriggerSource.FillInIfNotSet(trigger.IsRemote)
+		trigger.ClusterId = trigger.ClusterId.FillInIfNotSet()
+		trigger.IsRemote = false
+		trigger.Patterns = nil
+		trigger.CreatedAt = nil
+		trigger.UpdatedAt = nil
+		trigger.CreatedBy = ""
+		trigger.UpdatedBy = ""
+	}
+	sort.Slice(config.Teams, func(i, j int) bool { return config.Teams[i].ID < config.Teams[j].ID })
+	sort.Strings(config.Tags)
+	sort.Slice(config.Contacts, func(i, j int) bool { return config.Contacts[i].ID < config.Contacts[j].ID })
+	sort.Slice(config.Triggers, func(i, j int) bool { return config.Triggers[i].ID < config.Triggers[j].ID })
+	sort.Slice(config.Subscriptions, func(i, j int) bool { return config.Subscriptions[i].ID < config.Subscriptions[j].ID })
+}
+
+func (config *declarativeConfig) objects(kind configObjectKind) []configObject {
+	var objects []configObject
+	switch kind {
+	case configObjectTeam:
+		for i := range config.Teams {
+			objects = append(objects, configObject{id: config.Teams[i].ID, value: &config.Teams[i]})
+		}
+	case configObjectTag:
+		for _, tag := range config.Tags {
+			objects = append(objects, configObject{id: tag, value: tag})
+		}
+	case configObjectContact:
+		for i := range config.Contacts {
+			objects = append(objects, configObject{id: config.Contacts[i].ID, value: &config.Contacts[i]})
+		}
+	case configObjectTrigger:
+		for i := range config.Triggers {
+			objects = append(objects, configObject{id: config.Triggers[i].ID, value: &config.Triggers[i]})
+		}
+	case configObjectSubscription:
+		for i := range config.Subscriptions {
+			objects = append(objects, configObject{id: config.Subscriptions[i].ID, value: &config.Subscriptions[i]})
+		}
+	}
+	return objects
+}
+
+// validate checks that every object has stable unique ID, so applying config twice makes no changes.
+func (config *declarativeConfig) validate() error {
+	for _, kind := range configObjectKinds {
+		ids := make(map[string]bool)
+		for _, object := range config.objects(kind) {
+			if object.id == "" {
+				return fmt.Errorf("%s without id", kind)
+			}
+			if ids[object.id] {
+				return fmt.Errorf("duplicate %s id: %s", kind, object.id)
+			}
+			ids[object.id] = true
+		}
+	}
+	return nil
+}
+
+// computeConfigPlan returns changes that turn current config into desired one.
+// Creates and updates go first in dependency order, deletes are made only with prune in reverse order.
+func computeConfigPlan(desired, current *declarativeConfig, scope configScope, prune bool) ([]configChange, error) {
+	if err := desired.validate(); err != nil {
+		return nil, err
+	}
+	desired.normalize()
+	current.normalize()
+	desired = scope.filter(desired)
+	allCurrentTags := current.Tags
+	current = scope.filter(current)
+	current.Tags = allCurrentTags
+
+	changes := make([]configChange, 0)
+	for _, kind := range configObjectKinds {
+		currentObjects := make(map[string]interface{})
+		for _, object := range current.objects(kind) {
+			currentObjects[object.id] = object.value
+		}
+		for _, object := range desired.objects(kind) {
+			currentValue, exists := currentObjects[object.id]
+			if !exists {
+				changes = append(changes, configChange{Action: configChangeCreate, Kind: kind, ID: object.id, object: object.value})
+				continue
+			}
+			fields, err := getChangedFields(kind, object.value, currentValue)
+			if err != nil {
+				return nil, err
+			}
+			if len(fields) > 0 {
+				changes = append(changes, configChange{Action: configChangeUpdate, Kind: kind, ID: object.id, Fields: fields, object: object.value})
+			}
+		}
+	}
+
+	if !prune {
+		return changes, nil
+	}
+	for i := len(configObjectKinds) - 1; i >= 0; i-- {
+		kind := configObjectKinds[i]
+		if !scope.canPrune(kind) {
+			continue
+		}
+		desiredObjects := make(map[string]bool)
+		for _, object := range desired.objects(kind) {
+			desiredObjects[object.id] = true
+		}
+		for _, object := range current.objects(kind) {
+			if !desiredObjects[object.id] {
+				changes = append(changes, configChange{Action: configChangeDelete, Kind: kind, ID: object.id})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// getChangedFields returns sorted names of top level fields that differ between desired and current object.
+func getChangedFields(kind configObjectKind, desired, current interface{}) ([]string, error) {
+	desiredValue, err := toGenericValue(desired)
+	if err != nil {
+		return nil, err
+	}
+	currentValue, err := toGenericValue(current)
+	if err != nil {
+		return nil, err
+	}
+	desiredFields, ok := desiredValue.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	currentFields, _ := currentValue.(map[string]interface{})
+	for _, field := range optionalConfigFields[kind] {
+		if _, ok := desiredFields[field]; !ok {
+			delete(currentFields, field)
+		}
+	}
+
+	changed := make([]string, 0)
+	for field, value := range desiredFields {
+		if !reflect.DeepEqual(value, currentFields[field]) {
+			changed = append(changed, field)
+		}
+	}
+	for field := range currentFields {
+		if _, ok := desiredFields[field]; !ok {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// applyConfigPlan makes changes in store one by one and stops at first failed change.
+func applyConfigPlan(store configStore, changes []configChange) error {
+	for _, change := range changes {
+		if err := applyConfigChange(store, change); err != nil {
+			return fmt.Errorf("cannot %s %s %s: %w", change.Action, change.Kind, change.ID, err)
+		}
+	}
+	return nil
+}
+
+func applyConfigChange(store configStore, change configChange) error {
+	isNew := change.Action == configChangeCreate
+	if change.Action == configChangeDelete {
+		switch change.Kind {
+		case configObjectTeam:
+			return store.RemoveTeam(change.ID)
+		case configObjectTag:
+			return store.RemoveTag(change.ID)
+		case configObjectContact:
+			return store.RemoveContact(change.ID)
+		case configObjectTrigger:
+			return store.RemoveTrigger(change.ID)
+		case configObjectSubscription:
+			return store.RemoveSubscription(change.ID)
+		}
+	}
+	switch change.Kind {
+	case configObjectTeam:
+		return store.SaveTeam(change.object.(*declarativeTeam), isNew)
+	case configObjectTag:
+		return store.SaveTag(change.ID)
+	case configObjectContact:
+		return store.SaveContact(change.object.(*moira.ContactData), isNew)
+	case configObjectTrigger:
+		return store.SaveTrigger(change.object.(*dto.TriggerModel), isNew)
+	case configObjectSubscription:
+		return store.SaveSubscription(change.object.(*moira.SubscriptionData), isNew)
+	}
+	return fmt.Errorf("unknown object kind: %s", change.Kind)
+}
+
+// formatConfigPlan returns human readable plan.
+func formatConfigPlan(changes []configChange) string {
+	counts := make(map[configChangeAction]int)
+	builder := strings.Builder{}
+	for _, change := range changes {
+		builder.WriteString(change.String())
+		builder.WriteString("\n")
+		counts[change.Action]++
+	}
+	if len(changes) == 0 {
+		builder.WriteString("No changes. Moira configuration matches the file.\n")
+		return builder.String()
+	}
+	builder.WriteString(fmt.Sprintf("Plan: %d to create, %d to update, %d to delete.\n",
+		counts[configChangeCreate], counts[configChangeUpdate], counts[configChangeDelete]))
+	return builder.String()
+}
+
+// exportConfig writes config of the scope to YAML.
+func exportConfig(store configStore, scope configScope, writer io.Writer) error {
+	config, err := store.Load(scope)
+	if err != nil {
+		return err
+	}
+	config.normalize()
+	return writeConfigYAML(scope.filter(config), writer)
+}
+
+// writeConfigYAML writes config as YAML with empty values omitted.
+func writeConfigYAML(config *declarativeConfig, writer io.Writer) error {
+	value, err := toGenericValue(config)
+	if err != nil {
+		return err
+	}
+	bytes, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cannot marshal config to yaml: %w", err)
+	}
+	_, err = writer.Write(bytes)
+	return err
+}
+
+// readConfigYAML reads config from YAML, fields are named the same way as in Moira API.
+func readConfigYAML(reader io.Reader) (*declarativeConfig, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err = yaml.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("cannot parse yaml: %w", err)
+	}
+	jsonBytes, err := json.Marshal(fromYAMLValue(value))
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert yaml: %w", err)
+	}
+
+	config := &declarativeConfig{}
+	if value == nil {
+		return config, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return config, nil
+}
+
+// toGenericValue converts value to maps and slices the same way it is represented in JSON without empty values.
+func toGenericValue(value interface{}) (interface{}, error) {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err = json.Unmarshal(bytes, &generic); err != nil {
+		return nil, err
+	}
+	return pruneEmptyValues(generic), nil
+}
+
+// pruneEmptyValues removes nulls, empty strings, empty lists and empty maps from map values. Zeros and false are kept.
+func pruneEmptyValues(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, item := range typed {
+			item = pruneEmptyValues(item)
+			if isEmptyValue(item) {
+				delete(typed, key)
+				continue
+			}
+			typed[key] = item
+		}
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = pruneEmptyValues(item)
+		}
+	}
+	return value
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch typed := value.(type) {
+	case nil:
+		return true
+	case string:
+		return typed == ""
+	case []interface{}:
+		return len(typed) == 0
+	case map[string]interface{}:
+		return len(typed) == 0
+	}
+	return false
+}
+
+// fromYAMLValue converts maps with interface keys produced by yaml to maps that can be marshaled to JSON.
+func fromYAMLValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			result[fmt.Sprint(key)] = fromYAMLValue(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = fromYAMLValue(item)
+		}
+	}
+	return value
+}
+
+func handleDeclarativeConfig(logger moira.Logger, database moira.Database) error {
+	scope := configScope{Tag: *configTag, Team: *configTeam}
+	if scope.Tag != "" && scope.Team != "" {
+		return fmt.Errorf("only one of -config-tag and -config-team can be set")
+	}
+
+	var store configStore = newRedisConfigStore(database)
+	if *configAPIURL != "" {
+		store = newAPIConfigStore(*configAPIURL, *configAPIToken)
+	}
+
+	if *exportDeclarativeConfig {
+		writer := io.Writer(os.Stdout)
+		if *declarativeConfigFile != "" {
+			f, err := openFile(*declarativeConfigFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+			if err != nil {
+				return err
+			}
+			defer closeFile(f, logger)
+			writer = f
+		}
+		return exportConfig(store, scope, writer)
+	}
+
+	f, err := openFile(*declarativeConfigFile, os.O_RDONLY)
+	if err != nil {
+		return err
+	}
+	defer closeFile(f, logger)
+
+	desired, err := readConfigYAML(f)
+	if err != nil {
+		return err
+	}
+	current, err := store.Load(scope)
+	if err != nil {
+		return err
+	}
+	changes, err := computeConfigPlan(desired, current, scope, *configPrune)
+	if err != nil {
+		return err
+	}
+	fmt.Print(formatConfigPlan(changes))
+
+	if !*applyDeclarativeConfig {
+		return nil
+	}
+	if err = applyConfigPlan(store, changes); err != nil {
+		return err
+	}
+	logger.Info().
+		Int("changes", len(changes)).
+		Msg("Declarative config was applied")
+	return nil
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/dto"
+)
+
+const apiRequestTimeout = 30 * time.Second
+
+var errAPINotFound = errors.New("not found")
+
+// apiConfigStore reads and writes declarative config through Moira API, so all API permissions and validations are applied.
+type apiConfigStore struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newAPIConfigStore(apiURL, token string) *apiConfigStore {
+	return &apiConfigStore{
+		url:    strings.TrimRight(apiURL, "/"),
+		token:  token,
+		client: &http.Client{Timeout: apiRequestTimeout},
+	}
+}
+
+func (store *apiConfigStore) do(method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequest(method, store.url+path, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if store.token != "" {
+		request.Header.Set("Authorization", "Bearer "+store.token)
+	}
+
+	response, err := store.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(response.Body)
+		if response.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("%s %s: %w: %s", method, path, errAPINotFound, strings.TrimSpace(string(message)))
+		}
+		return fmt.Errorf("%s %s: %s: %s", method, path, response.Status, strings.TrimSpace(string(message)))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+// Load reads objects of the scope from API. API has no method to list all subscriptions, so team or tag must be set.
+func (store *apiConfigStore) Load(scope configScope) (*declarativeConfig, error) {
+	if scope.isAll() {
+		return nil, fmt.Errorf("all objects can not be loaded from API, set -config-team or -config-tag")
+	}
+
+	config := &declarativeConfig{}
+	tags := dto.TagsData{}
+	if err := store.do(http.MethodGet, "/tag", nil, &tags); err != nil {
+		return nil, err
+	}
+	config.Tags = tags.TagNames
+
+	if scope.Team != "" {
+		if err := store.loadTeam(config, scope.Team); err != nil {
+			return nil, err
+		}
+		return config, nil
+	}
+	if err := store.loadTag(config, scope.Tag); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (store *apiConfigStore) loadTeam(config *declarativeConfig, teamID string) error {
+	teamPath := "/teams/" + url.PathEscape(teamID)
+
+	// Team that is not created yet has no objects
+	team := dto.TeamModel{}
+	if err := store.do(http.MethodGet, teamPath, nil, &team); err != nil {
+		if errors.Is(err, errAPINotFound) {
+			return nil
+		}
+		return err
+	}
+	members := dto.TeamMembers{}
+	if err := store.do(http.MethodGet, teamPath+"/users", nil, &members); err != nil {
+		return err
+	}
+	config.Teams = append(config.Teams, declarativeTeam{
+		ID:          teamID,
+		Name:        team.Name,
+		Description: team.Description,
+		Users:       members.Usernames,
+		Roles:       members.Roles,
+	})
+
+	settings := dto.TeamSettings{}
+	if err := store.do(http.MethodGet, teamPath+"/settings", nil, &settings); err != nil {
+		return err
+	}
+	for _, contact := range settings.Contacts {
+		config.Contacts = append(config.Contacts, contact.ContactData)
+	}
+	config.Subscriptions = settings.Subscriptions
+
+	triggers := dto.TriggersList{}
+	if err := store.do(http.MethodGet, teamPath+"/triggers", nil, &triggers); err != nil {
+		return err
+	}
+	for i := range triggers.List {
+		config.Triggers = append(config.Triggers, dto.CreateTriggerModel(&triggers.List[i].Trigger))
+	}
+	return nil
+}
+
+func (store *apiConfigStore) loadTag(config *declarativeConfig, tag string) error {
+	statistics := dto.TagsStatistics{}
+	if err := store.do(http.MethodGet, "/tag/stats", nil, &statistics); err != nil {
+		return err
+	}
+	for _, tagStatistics := range statistics.List {
+		if tagStatistics.TagName != tag {
+			continue
+		}
+		for _, triggerID := range tagStatistics.Triggers {
+			trigger := dto.TriggerModel{}
+			if err := store.do(http.MethodGet, "/trigger/"+url.PathEscape(triggerID), nil, &trigger); err != nil {
+				return err
+			}
+			config.Triggers = append(config.Triggers, trigger)
+		}
+		config.Subscriptions = tagStatistics.Subscriptions
+	}
+
+	contactIDs := make(map[string]bool)
+	for _, subscription := range config.Subscriptions {
+		for _, contactID := range subscription.Contacts {
+			if contactIDs[contactID] {
+				continue
+			}
+			contactIDs[contactID] = true
+			contact := moira.ContactData{}
+			if err := store.do(http.MethodGet, "/contact/"+url.PathEscape(contactID), nil, &contact); err != nil {
+				return err
+			}
+			config.Contacts = append(config.Contacts, contact)
+		}
+	}
+	return nil
+}
+
+// SaveTeam saves team and its members. Roles are set before users are removed so team always has an owner.
+func (store *apiConfigStore) SaveTeam(team *declarativeTeam, isNew bool) error {
+	teamPath := "/teams/" + url.PathEscape(team.ID)
+	model := dto.TeamModel{ID: team.ID, Name: team.Name, Description: team.Description}
+	if isNew {
+		if err := store.do(http.MethodPost, "/teams", model, nil); err != nil {
+			return err
+		}
+	} else if err := store.do(http.MethodPatch, teamPath, model, nil); err != nil {
+		return err
+	}
+
+	members := dto.TeamMembers{}
+	if err := store.do(http.MethodGet, teamPath+"/users", nil, &members); err != nil {
+		return err
+	}
+	addedUsers := moira.GetStringListsDiff(team.Users, members.Usernames)
+	if len(addedUsers) > 0 {
+		if err := store.do(http.MethodPost, teamPath+"/users", dto.TeamMembers{Usernames: addedUsers}, nil); err != nil {
+			return err
+		}
+	}
+	for userID, role := range team.Roles {
+		if members.Roles[userID] == role {
+			continue
+		}
+		if err := store.do(http.MethodPut, teamPath+"/users/"+url.PathEscape(userID)+"/role", dto.TeamUserRole{Role: role}, nil); err != nil {
+			return err
+		}
+	}
+	for _, userID := range moira.GetStringListsDiff(members.Usernames, team.Users) {
+		if err := store.do(http.MethodDelete, teamPath+"/users/"+url.PathEscape(userID), nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveTeam removes team.
+func (store *apiConfigStore) RemoveTeam(teamID string) error {
+	return store.do(http.MethodDelete, "/teams/"+url.PathEscape(teamID), nil, nil)
+}
+
+// SaveTag creates tag.
+func (store *apiConfigStore) SaveTag(tag string) error {
+	return store.do(http.MethodPost, "/tag", dto.TagsData{TagNames: []string{tag}}, nil)
+}
+
+// RemoveTag removes tag.
+func (store *apiConfigStore) RemoveTag(tag string) error {
+	return store.do(http.MethodDelete, "/tag/"+url.PathEscape(tag), nil, nil)
+}
+
+// SaveContact creates contact with given ID or updates it, team contacts are created in their team.
+// Api never returns signing secrets, so updated contact keeps its signing secret if config does not set a new one.
+func (store *apiConfigStore) SaveContact(contact *moira.ContactData, isNew bool) error {
+	switch {
+	case !isNew:
+		body := dto.ContactData{ContactData: *contact, HasSigningSecret: true}
+		return store.do(http.MethodPut, "/contact/"+url.PathEscape(contact.ID), body, nil)
+	case contact.Team != "":
+		return store.do(http.MethodPost, "/teams/"+url.PathEscape(contact.Team)+"/contacts", contact, nil)
+	default:
+		return store.do(http.MethodPut, "/contact", contact, nil)
+	}
+}
+
+// RemoveContact removes contact.
+func (store *apiConfigStore) RemoveContact(contactID string) error {
+	return store.do(http.MethodDelete, "/contact/"+url.PathEscape(contactID), nil, nil)
+}
+
+// SaveTrigger creates trigger with given ID or updates it.
+func (store *apiConfigStore) SaveTrigger(trigger *dto.TriggerModel, isNew bool) error {
+	if isNew {
+		return store.do(http.MethodPut, "/trigger", trigger, nil)
+	}
+	return store.do(http.MethodPut, "/trigger/"+url.PathEscape(trigger.ID), trigger, nil)
+}
+
+// RemoveTrigger removes trigger.
+func (store *apiConfigStore) RemoveTrigger(triggerID string) error {
+	return store.do(http.MethodDelete, "/trigger/"+url.PathEscape(triggerID), nil, nil)
+}
+
+// SaveSubscription creates subscription with given ID or updates it, team subscriptions are created in their team.
+func (store *apiConfigStore) SaveSubscription(subscription *moira.SubscriptionData, isNew bool) error {
+	switch {
+	case !isNew:
+		return store.do(http.MethodPut, "/subscription/"+url.PathEscape(subscription.ID), subscription, nil)
+	case subscription.TeamID != "":
+		return store.do(http.MethodPost, "/teams/"+url.PathEscape(subscription.TeamID)+"/subscriptions", subscription, nil)
+	default:
+		return store.do(http.MethodPut, "/subscription", subscription, nil)
+	}
+}
+
+// RemoveSubscription removes subscription.
+func (store *apiConfigStore) RemoveSubscription(subscriptionID string) error {
+	return store.do(http.MethodDelete, "/subscription/"+url.PathEscape(subscriptionID), nil, nil)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/dto"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testAPIRequest struct {
+	method string
+	path   string
+	body   string
+}
+
+func newTestAPIServer(responses map[string]interface{}) (*httptest.Server, *[]testAPIRequest) {
+	requests := make([]testAPIRequest, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		requests = append(requests, testAPIRequest{method: request.Method, path: request.URL.Path, body: string(body)})
+		if request.Header.Get("Authorization") != "Bearer token" {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		response, ok := responses[request.Method+" "+request.URL.Path]
+		if !ok {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(writer).Encode(response) //nolint
+	}))
+	return server, &requests
+}
+
+func TestAPIConfigStoreLoad(t *testing.T) {
+	Convey("Test api config store load", t, func() {
+		contact := moira.ContactData{ID: "contact", Type: "mail", Value: "user@example.com", Team: "team"}
+		subscription := moira.SubscriptionData{ID: "subscription", Contacts: []string{"contact"}, Tags: []string{"tag"}, TeamID: "team"}
+		trigger := moira.Trigger{ID: "trigger", Name: "Trigger", Targets: []string{"my.metric"}, Tags: []string{"tag"}, TeamID: "team"}
+
+		server, _ := newTestAPIServer(map[string]interface{}{
+			"GET /api/tag":                 dto.TagsData{TagNames: []string{"tag"}},
+			"GET /api/teams/team":          dto.TeamModel{ID: "team", Name: "Team"},
+			"GET /api/teams/team/users":    dto.TeamMembers{Usernames: []string{"user"}, Roles: map[string]moira.TeamRole{"user": moira.TeamRoleOwner}},
+			"GET /api/teams/team/settings": dto.TeamSettings{TeamID: "team", Contacts: []dto.ContactData{dto.NewContactData(contact)}, Subscriptions: []moira.SubscriptionData{subscription}},
+			"GET /api/teams/team/triggers": dto.TriggersList{List: []moira.TriggerCheck{{Trigger: trigger}}},
+			"GET /api/tag/stats": dto.TagsStatistics{List: []dto.TagStatistics{
+				{TagName: "tag", Triggers: []string{"trigger"}, Subscriptions: []moira.SubscriptionData{subscription}},
+				{TagName: "other", Triggers: []string{"other"}},
+			}},
+			"GET /api/trigger/trigger": dto.CreateTriggerModel(&trigger),
+			"GET /api/contact/contact": contact,
+		})
+		defer server.Close()
+		store := newAPIConfigStore(server.URL+"/api/", "token")
+
+		Convey("Team scope", func() {
+			config, err := store.Load(configScope{Team: "team"})
+			So(err, ShouldBeNil)
+			So(config.Teams, ShouldResemble, []declarativeTeam{{ID: "team", Name: "Team", Users: []string{"user"}, Roles: map[string]moira.TeamRole{"user": moira.TeamRoleOwner}}})
+			So(config.Tags, ShouldResemble, []string{"tag"})
+			So(config.Contacts, ShouldResemble, []moira.ContactData{contact})
+			So(config.Subscriptions, ShouldHaveLength, 1)
+			So(config.Triggers, ShouldHaveLength, 1)
+			So(config.Triggers[0].Targets, ShouldResemble, trigger.Targets)
+		})
+
+		Convey("Team scope of not existing team", func() {
+			config, err := store.Load(configScope{Team: "new-team"})
+			So(err, ShouldBeNil)
+			So(config.Teams, ShouldBeEmpty)
+		})
+
+		Convey("Tag scope", func() {
+			config, err := store.Load(configScope{Tag: "tag"})
+			So(err, ShouldBeNil)
+			So(config.Contacts, ShouldResemble, []moira.ContactData{contact})
+			So(config.Subscriptions, ShouldHaveLength, 1)
+			So(config.Triggers, ShouldHaveLength, 1)
+			So(config.Triggers[0].ID, ShouldEqual, "trigger")
+		})
+
+		Convey("All objects can not be loaded", func() {
+			_, err := store.Load(configScope{})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Wrong token is reported", func() {
+			store.token = "wrong"
+			_, err := store.Load(configScope{Team: "team"})
+			So(err.Error(), ShouldStartWith, "GET /tag: 401 Unauthorized")
+		})
+	})
+}
+
+func TestAPIConfigStoreSave(t *testing.T) {
+	Convey("Test api config store save", t, func() {
+		server, requests := newTestAPIServer(map[string]interface{}{
+			"POST /teams":                      dto.SaveTeamResponse{ID: "team"},
+			"GET /teams/team/users":            dto.TeamMembers{Usernames: []string{"creator"}, Roles: map[string]moira.TeamRole{"creator": moira.TeamRoleOwner}},
+			"POST /teams/team/users":           dto.TeamMembers{},
+			"PUT /teams/team/users/user/role":  dto.TeamMembers{},
+			"DELETE /teams/team/users/creator": dto.TeamMembers{},
+			"POST /teams/team/contacts":        moira.ContactData{},
+			"PUT /contact/contact":             moira.ContactData{},
+			"PUT /subscription":                moira.SubscriptionData{},
+			"PUT /trigger/trigger":             dto.SaveTriggerResponse{},
+		})
+		defer server.Close()
+		store := newAPIConfigStore(server.URL, "token")
+
+		Convey("New team members get roles before creator is removed", func() {
+			team := &declarativeTeam{ID: "team", Name: "Team", Users: []string{"user"}, Roles: map[string]moira.TeamRole{"user": moira.TeamRoleOwner}}
+
+			So(store.SaveTeam(team, true), ShouldBeNil)
+			So(*requests, ShouldResemble, []testAPIRequest{
+				{method: http.MethodPost, path: "/teams", body: `{"id":"team","name":"Team","description":""}`},
+				{method: http.MethodGet, path: "/teams/team/users"},
+				{method: http.MethodPost, path: "/teams/team/users", body: `{"usernames":["user"]}`},
+				{method: http.MethodPut, path: "/teams/team/users/user/role", body: `{"role":"owner"}`},
+				{method: http.MethodDelete, path: "/teams/team/users/creator"},
+			})
+		})
+
+		Convey("Objects are created in their team", func() {
+			So(store.SaveContact(&moira.ContactData{ID: "contact", Team: "team"}, true), ShouldBeNil)
+			So(store.SaveContact(&moira.ContactData{ID: "contact", Team: "team"}, false), ShouldBeNil)
+			So(store.SaveSubscription(&moira.SubscriptionData{ID: "subscription"}, true), ShouldBeNil)
+			So(store.SaveTrigger(&dto.TriggerModel{ID: "trigger"}, false), ShouldBeNil)
+
+			paths := make([]string, 0, len(*requests))
+			for _, request := range *requests {
+				paths = append(paths, request.method+" "+request.path)
+			}
+			So(paths, ShouldResemble, []string{"POST /teams/team/contacts", "PUT /contact/contact", "PUT /subscription", "PUT /trigger/trigger"})
+		})
+	})
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/moira-alert/moira/metric_source/local"
+)
+
+// patternsResolveInterval is a period of metrics fetched to resolve trigger patterns, the same as in API.
+const patternsResolveInterval = 600
+
+// redisConfigStore reads and writes declarative config directly in redis.
+type redisConfigStore struct {
+	database    moira.Database
+	localSource metricSource.MetricSource
+}
+
+func newRedisConfigStore(database moira.Database) *redisConfigStore {
+	return &redisConfigStore{
+		database: database,
+	}
+}
+
+// Load reads objects of the scope from redis, all tag names are loaded in any scope.
+func (store *redisConfigStore) Load(scope configScope) (*declarativeConfig, error) {
+	config := &declarativeConfig{}
+	tags, err := store.database.GetTagNames()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get tags: %w", err)
+	}
+	config.Tags = tags
+
+	switch {
+	case scope.Team != "":
+		err = store.loadTeam(config, scope.Team)
+	case scope.Tag != "":
+		err = store.loadTag(config, scope.Tag)
+	default:
+		err = store.loadAll(config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (store *redisConfigStore) loadAll(config *declarativeConfig) error {
+	teams, err := store.database.GetAllTeams()
+	if err != nil {
+		return fmt.Errorf("cannot get teams: %w", err)
+	}
+	for _, team := range teams {
+		if err = store.addTeam(config, team); err != nil {
+			return err
+		}
+	}
+
+	contacts, err := store.database.GetAllContacts()
+	if err != nil {
+		return fmt.Errorf("cannot get contacts: %w", err)
+	}
+	addContacts(config, contacts)
+
+	triggerIDs, err := store.database.GetAllTriggerIDs()
+	if err != nil {
+		return fmt.Errorf("cannot get trigger ids: %w", err)
+	}
+	if err = store.addTriggers(config, triggerIDs); err != nil {
+		return err
+	}
+
+	// Subscriptions are indexed by users, teams and tags, so all of them are collected
+	subscriptionIDs := make([]string, 0)
+	users := make(map[string]bool)
+	for _, contact := range config.Contacts {
+		if contact.User != "" && !users[contact.User] {
+			users[contact.User] = true
+			ids, err := store.database.GetUserSubscriptionIDs(contact.User)
+			if err != nil {
+				return fmt.Errorf("cannot get subscriptions of user %s: %w", contact.User, err)
+			}
+			subscriptionIDs = append(subscriptionIDs, ids...)
+		}
+	}
+	for _, team := range teams {
+		ids, err := store.database.GetTeamSubscriptionIDs(team.ID)
+		if err != nil {
+			return fmt.Errorf("cannot get subscriptions of team %s: %w", team.ID, err)
+		}
+		subscriptionIDs = append(subscriptionIDs, ids...)
+	}
+	if err = store.addSubscriptions(config, subscriptionIDs); err != nil {
+		return err
+	}
+	tagsSubscriptions, err := store.database.GetTagsSubscriptions(config.Tags)
+	if err != nil {
+		return fmt.Errorf("cannot get tags subscriptions: %w", err)
+	}
+	addSubscriptions(config, tagsSubscriptions)
+	return nil
+}
+
+func (store *redisConfigStore) loadTeam(config *declarativeConfig, teamID string) error {
+	// Team that is not created yet has no objects
+	team, err := store.database.GetTeam(teamID)
+	if errors.Is(err, database.ErrNil) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot get team %s: %w", teamID, err)
+	}
+	if err = store.addTeam(config, team); err != nil {
+		return err
+	}
+
+	contactIDs, err := store.database.GetTeamContactIDs(teamID)
+	if err != nil {
+		return fmt.Errorf("cannot get team contacts: %w", err)
+	}
+	contacts, err := store.database.GetContacts(contactIDs)
+	if err != nil {
+		return fmt.Errorf("cannot get contacts: %w", err)
+	}
+	addContacts(config, contacts)
+
+	triggerIDs, err := store.database.GetTeamTriggerIDs(teamID)
+	if err != nil {
+		return fmt.Errorf("cannot get team triggers: %w", err)
+	}
+	if err = store.addTriggers(config, triggerIDs); err != nil {
+		return err
+	}
+
+	subscriptionIDs, err := store.database.GetTeamSubscriptionIDs(teamID)
+	if err != nil {
+		return fmt.Errorf("cannot get team subscriptions: %w", err)
+	}
+	return store.addSubscriptions(config, subscriptionIDs)
+}
+
+func (store *redisConfigStore) loadTag(config *declarativeConfig, tag string) error {
+	triggerIDs, err := store.database.GetTagTriggerIDs(tag)
+	if err != nil {
+		return fmt.Errorf("cannot get tag triggers: %w", err)
+	}
+	if err = store.addTriggers(config, triggerIDs); err != nil {
+		return err
+	}
+
+	subscriptions, err := store.database.GetTagsSubscriptions([]string{tag})
+	if err != nil {
+		return fmt.Errorf("cannot get tag subscriptions: %w", err)
+	}
+	addSubscriptions(config, subscriptions)
+
+	contactIDs := make([]string, 0)
+	for _, subscription := range config.Subscriptions {
+		contactIDs = append(contactIDs, subscription.Contacts...)
+	}
+	contacts, err := store.database.GetContacts(contactIDs)
+	if err != nil {
+		return fmt.Errorf("cannot get contacts: %w", err)
+	}
+	addContacts(config, contacts)
+	return nil
+}
+
+func (store *redisConfigStore) addTeam(config *declarativeConfig, team moira.Team) error {
+	users, err := store.database.GetTeamUsers(team.ID)
+	if err != nil {
+		return fmt.Errorf("cannot get users of team %s: %w", team.ID, err)
+	}
+	roles, err := store.database.GetTeamUserRoles(team.ID)
+	if err != nil {
+		return fmt.Errorf("cannot get user roles of team %s: %w", team.ID, err)
+	}
+	config.Teams = append(config.Teams, declarativeTeam{
+		ID:          team.ID,
+		Name:        team.Name,
+		Description: team.Description,
+		Users:       users,
+		Roles:       roles,
+	})
+	return nil
+}
+
+func (store *redisConfigStore) addTriggers(config *declarativeConfig, triggerIDs []string) error {
+	triggers, err := store.database.GetTriggers(triggerIDs)
+	if err != nil {
+		return fmt.Errorf("cannot get triggers: %w", err)
+	}
+	for _, trigger := range triggers {
+		if trigger != nil {
+			config.Triggers = append(config.Triggers, dto.CreateTriggerModel(trigger))
+		}
+	}
+	return nil
+}
+
+func (store *redisConfigStore) addSubscriptions(config *declarativeConfig, subscriptionIDs []string) error {
+	subscriptions, err := store.database.GetSubscriptions(subscriptionIDs)
+	if err != nil {
+		return fmt.Errorf("cannot get subscriptions: %w", err)
+	}
+	addSubscriptions(config, subscriptions)
+	return nil
+}
+
+func addSubscriptions(config *declarativeConfig, subscriptions []*moira.SubscriptionData) {
+	added := make(map[string]bool, len(config.Subscriptions))
+	for _, subscription := range config.Subscriptions {
+		added[subscription.ID] = true
+	}
+	for _, subscription := range subscriptions {
+		if subscription != nil && !added[subscription.ID] {
+			added[subscription.ID] = true
+			config.Subscriptions = append(config.Subscriptions, *subscription)
+		}
+	}
+}
+
+// addContacts adds contacts without signing secrets, secrets are write-only the same as in API and are never exported.
+func addContacts(config *declarativeConfig, contacts []*moira.ContactData) {
+	added := make(map[string]bool, len(config.Contacts))
+	for _, contact := range config.Contacts {
+		added[contact.ID] = true
+	}
+	for _, contact := range contacts {
+		if contact != nil && !added[contact.ID] {
+			added[contact.ID] = true
+			loaded := *contact
+			loaded.SigningSecret = ""
+			config.Contacts = append(config.Contacts, loaded)
+		}
+	}
+}
+
+// SaveTeam saves team and updates teams of added and removed users.
+func (store *redisConfigStore) SaveTeam(team *declarativeTeam, isNew bool) error {
+	err := store.database.SaveTeam(team.ID, moira.Team{ID: team.ID, Name: team.Name, Description: team.Description})
+	if err != nil {
+		return err
+	}
+	if err = store.saveTeamUsers(team.ID, team.Users); err != nil {
+		return err
+	}
+	if len(team.Roles) == 0 {
+		return nil
+	}
+	return store.database.SaveTeamUserRoles(team.ID, team.Roles)
+}
+
+// RemoveTeam removes team and removes it from teams of its users.
+func (store *redisConfigStore) RemoveTeam(teamID string) error {
+	if err := store.saveTeamUsers(teamID, []string{}); err != nil {
+		return err
+	}
+	return store.database.DeleteTeam(teamID, "")
+}
+
+func (store *redisConfigStore) saveTeamUsers(teamID string, users []string) error {
+	existingUsers, err := store.database.GetTeamUsers(teamID)
+	if err != nil {
+		return fmt.Errorf("cannot get team users: %w", err)
+	}
+
+	finalUsers := make(map[string]bool, len(users))
+	for _, userID := range users {
+		finalUsers[userID] = true
+	}
+	usersTeams := make(map[string][]string)
+	for _, userID := range append(existingUsers, users...) {
+		if _, ok := usersTeams[userID]; ok {
+			continue
+		}
+		userTeams, err := store.database.GetUserTeams(userID)
+		if err != nil {
+			return fmt.Errorf("cannot get teams of user %s: %w", userID, err)
+		}
+		teams := make([]string, 0, len(userTeams)+1)
+		for _, userTeamID := range userTeams {
+			if userTeamID != teamID {
+				teams = append(teams, userTeamID)
+			}
+		}
+		if finalUsers[userID] {
+			teams = append(teams, teamID)
+		}
+		sort.Strings(teams)
+		usersTeams[userID] = teams
+	}
+	return store.database.SaveTeamsAndUsers(teamID, users, usersTeams)
+}
+
+// SaveTag creates tag.
+func (store *redisConfigStore) SaveTag(tag string) error {
+	return store.database.CreateTags([]string{tag})
+}
+
+// RemoveTag removes tag.
+func (store *redisConfigStore) RemoveTag(tag string) error {
+	return store.database.RemoveTag(tag)
+}
+
+// SaveContact saves contact, updated contact keeps its signing secret if config does not set a new one.
+func (store *redisConfigStore) SaveContact(contact *moira.ContactData, isNew bool) error {
+	if !isNew && contact.SigningSecret == "" {
+		existing, err := store.database.GetContact(contact.ID)
+		if err != nil && !errors.Is(err, database.ErrNil) {
+			return fmt.Errorf("cannot get contact: %w", err)
+		}
+		if existing.SigningSecret != "" {
+			updated := *contact
+			updated.SigningSecret = existing.SigningSecret
+			contact = &updated
+		}
+	}
+	return store.database.SaveContact(contact)
+}
+
+// RemoveContact removes contact.
+func (store *redisConfigStore) RemoveContact(contactID string) error {
+	return store.database.RemoveContact(contactID)
+}
+
+// SaveTrigger saves trigger, patterns of graphite local trigger are resolved from its targets the same way as in API.
+func (store *redisConfigStore) SaveTrigger(trigger *dto.TriggerModel, isNew bool) error {
+	if trigger.TriggerSource == moira.GraphiteLocal && len(trigger.Patterns) == 0 {
+		patterns, err := store.resolvePatterns(trigger.Targets)
+		if err != nil {
+			return fmt.Errorf("cannot resolve patterns: %w", err)
+		}
+		trigger.Patterns = patterns
+	}
+	return store.database.SaveTrigger(trigger.ID, trigger.ToMoiraTrigger())
+}
+
+func (store *redisConfigStore) resolvePatterns(targets []string) ([]string, error) {
+	if store.localSource == nil {
+		store.localSource = local.Create(store.database)
+	}
+
+	now := time.Now().Unix()
+	patterns := make([]string, 0)
+	for _, target := range targets {
+		fetchResult, err := store.localSource.Fetch(target, now-patternsResolveInterval, now, false)
+		if err != nil {
+			return nil, err
+		}
+		targetPatterns, err := fetchResult.GetPatterns()
+		if err == nil {
+			patterns = append(patterns, targetPatterns...)
+		}
+	}
+	return patterns, nil
+}
+
+// RemoveTrigger removes trigger.
+func (store *redisConfigStore) RemoveTrigger(triggerID string) error {
+	return store.database.RemoveTrigger(triggerID, "")
+}
+
+// SaveSubscription saves subscription.
+func (store *redisConfigStore) SaveSubscription(subscription *moira.SubscriptionData, isNew bool) error {
+	return store.database.SaveSubscription(subscription)
+}
+
+// RemoveSubscription removes subscription.
+func (store *redisConfigStore) RemoveSubscription(subscriptionID string) error {
+	return store.database.RemoveSubscription(subscriptionID)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRedisConfigStoreLoad(t *testing.T) {
+	Convey("Test redis config store load", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		store := newRedisConfigStore(mockDb)
+		contact := &moira.ContactData{ID: "contact", Type: "mail", Value: "user@example.com", Team: "team"}
+		subscription := &moira.SubscriptionData{ID: "subscription", Contacts: []string{"contact"}, Tags: []string{"tag"}, TeamID: "team"}
+		trigger := &moira.Trigger{ID: "trigger", Name: "Trigger", Tags: []string{"tag"}, TeamID: "team"}
+
+		mockDb.EXPECT().GetTagNames().Return([]string{"tag"}, nil)
+
+		Convey("Team scope", func() {
+			mockDb.EXPECT().GetTeam("team").Return(moira.Team{ID: "team", Name: "Team"}, nil)
+			mockDb.EXPECT().GetTeamUsers("team").Return([]string{"user"}, nil)
+			mockDb.EXPECT().GetTeamUserRoles("team").Return(map[string]moira.TeamRole{}, nil)
+			mockDb.EXPECT().GetTeamContactIDs("team").Return([]string{"contact"}, nil)
+			contactWithSecret := *contact
+			contactWithSecret.SigningSecret = "secret"
+			mockDb.EXPECT().GetContacts([]string{"contact"}).Return([]*moira.ContactData{&contactWithSecret}, nil)
+			mockDb.EXPECT().GetTeamTriggerIDs("team").Return([]string{"trigger", "removed"}, nil)
+			mockDb.EXPECT().GetTriggers([]string{"trigger", "removed"}).Return([]*moira.Trigger{trigger, nil}, nil)
+			mockDb.EXPECT().GetTeamSubscriptionIDs("team").Return([]string{"subscription"}, nil)
+			mockDb.EXPECT().GetSubscriptions([]string{"subscription"}).Return([]*moira.SubscriptionData{subscription}, nil)
+
+			config, err := store.Load(configScope{Team: "team"})
+			So(err, ShouldBeNil)
+			So(config, ShouldResemble, &declarativeConfig{
+				Teams:         []declarativeTeam{{ID: "team", Name: "Team", Users: []string{"user"}, Roles: map[string]moira.TeamRole{}}},
+				Tags:          []string{"tag"},
+				Contacts:      []moira.ContactData{*contact},
+				Triggers:      []dto.TriggerModel{dto.CreateTriggerModel(trigger)},
+				Subscriptions: []moira.SubscriptionData{*subscription},
+			})
+		})
+
+		Convey("Team scope of not existing team", func() {
+			mockDb.EXPECT().GetTeam("team").Return(moira.Team{}, database.ErrNil)
+
+			config, err := store.Load(configScope{Team: "team"})
+			So(err, ShouldBeNil)
+			So(config, ShouldResemble, &declarativeConfig{Tags: []string{"tag"}})
+		})
+
+		Convey("Tag scope", func() {
+			mockDb.EXPECT().GetTagTriggerIDs("tag").Return([]string{"trigger"}, nil)
+			mockDb.EXPECT().GetTriggers([]string{"trigger"}).Return([]*moira.Trigger{trigger}, nil)
+			mockDb.EXPECT().GetTagsSubscriptions([]string{"tag"}).Return([]*moira.SubscriptionData{subscription}, nil)
+			mockDb.EXPECT().GetContacts([]string{"contact"}).Return([]*moira.ContactData{contact}, nil)
+
+			config, err := store.Load(configScope{Tag: "tag"})
+			So(err, ShouldBeNil)
+			So(config.Triggers, ShouldHaveLength, 1)
+			So(config.Subscriptions, ShouldResemble, []moira.SubscriptionData{*subscription})
+			So(config.Contacts, ShouldResemble, []moira.ContactData{*contact})
+		})
+	})
+}
+
+func TestRedisConfigStoreSave(t *testing.T) {
+	Convey("Test redis config store save", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDb := mock_moira_alert.NewMockDatabase(mockCtrl)
+		store := newRedisConfigStore(mockDb)
+
+		Convey("Team users are replaced and teams of users are updated", func() {
+			team := &declarativeTeam{ID: "team", Name: "Team", Users: []string{"user1", "user3"}, Roles: map[string]moira.TeamRole{"user1": moira.TeamRoleOwner}}
+
+			mockDb.EXPECT().SaveTeam("team", moira.Team{ID: "team", Name: "Team"}).Return(nil)
+			mockDb.EXPECT().GetTeamUsers("team").Return([]string{"user1", "user2"}, nil)
+			mockDb.EXPECT().GetUserTeams("user1").Return([]string{"team", "other"}, nil)
+			mockDb.EXPECT().GetUserTeams("user2").Return([]string{"team"}, nil)
+			mockDb.EXPECT().GetUserTeams("user3").Return([]string{"other"}, nil)
+			mockDb.EXPECT().SaveTeamsAndUsers("team", []string{"user1", "user3"}, map[string][]string{
+				"user1": {"other", "team"},
+				"user2": {},
+				"user3": {"other", "team"},
+			}).Return(nil)
+			mockDb.EXPECT().SaveTeamUserRoles("team", team.Roles).Return(nil)
+
+			So(store.SaveTeam(team, false), ShouldBeNil)
+		})
+
+		Convey("Team is removed from teams of its users", func() {
+			mockDb.EXPECT().GetTeamUsers("team").Return([]string{"user1"}, nil)
+			mockDb.EXPECT().GetUserTeams("user1").Return([]string{"team"}, nil)
+			mockDb.EXPECT().SaveTeamsAndUsers("team", []string{}, map[string][]string{"user1": {}}).Return(nil)
+			mockDb.EXPECT().DeleteTeam("team", "").Return(nil)
+
+			So(store.RemoveTeam("team"), ShouldBeNil)
+		})
+
+		Convey("Trigger with patterns is saved as is", func() {
+			trigger := &dto.TriggerModel{ID: "trigger", Targets: []string{"my.metric"}, Patterns: []string{"my.metric"}, TriggerSource: moira.GraphiteLocal}
+
+			mockDb.EXPECT().SaveTrigger("trigger", trigger.ToMoiraTrigger()).Return(nil)
+
+			So(store.SaveTrigger(trigger, true), ShouldBeNil)
+		})
+
+		Convey("Updated contact keeps signing secret if config does not set it", func() {
+			mockDb.EXPECT().GetContact("contact").Return(moira.ContactData{ID: "contact", SigningSecret: "secret"}, nil)
+			mockDb.EXPECT().SaveContact(&moira.ContactData{ID: "contact", Value: "new", SigningSecret: "secret"}).Return(nil)
+
+			So(store.SaveContact(&moira.ContactData{ID: "contact", Value: "new"}, false), ShouldBeNil)
+		})
+
+		Convey("Signing secret from config replaces existing one", func() {
+			contact := &moira.ContactData{ID: "contact", SigningSecret: "new"}
+			mockDb.EXPECT().SaveContact(contact).Return(nil)
+
+			So(store.SaveContact(contact, false), ShouldBeNil)
+		})
+
+		Convey("Plan is applied in order", func() {
+			contact := moira.ContactData{ID: "contact"}
+			changes := []configChange{
+				{Action: configChangeCreate, Kind: configObjectTag, ID: "tag"},
+				{Action: configChangeUpdate, Kind: configObjectContact, ID: "contact", object: &contact},
+				{Action: configChangeDelete, Kind: configObjectSubscription, ID: "subscription"},
+			}
+
+			gomock.InOrder(
+				mockDb.EXPECT().CreateTags([]string{"tag"}).Return(nil),
+				mockDb.EXPECT().GetContact("contact").Return(moira.ContactData{ID: "contact"}, nil),
+				mockDb.EXPECT().SaveContact(&contact).Return(nil),
+				mockDb.EXPECT().RemoveSubscription("subscription").Return(nil),
+			)
+
+			So(applyConfigPlan(store, changes), ShouldBeNil)
+		})
+	})
+}
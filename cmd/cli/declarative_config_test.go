@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api/dto"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newTestDeclarativeConfig() *declarativeConfig {
+	warnValue := 10.0
+	return &declarativeConfig{
+		Teams: []declarativeTeam{
+			{ID: "team", Name: "Team", Users: []string{"user2", "user1"}},
+		},
+		Tags: []string{"tag1", "tag2"},
+		Contacts: []moira.ContactData{
+			{ID: "contact", Type: "mail", Value: "user@example.com", Team: "team"},
+		},
+		Triggers: []dto.TriggerModel{
+			{
+				ID:          "trigger",
+				Name:        "Trigger",
+				Targets:     []string{"my.metric"},
+				WarnValue:   &warnValue,
+				TriggerType: moira.RisingTrigger,
+				Tags:        []string{"tag1"},
+				TeamID:      "team",
+			},
+		},
+		Subscriptions: []moira.SubscriptionData{
+			{ID: "subscription", Contacts: []string{"contact"}, Tags: []string{"tag1"}, Enabled: true, TeamID: "team"},
+		},
+	}
+}
+
+func TestDeclarativeConfigYAML(t *testing.T) {
+	Convey("Test declarative config yaml", t, func() {
+		Convey("Config is read the same as it was written", func() {
+			config := newTestDeclarativeConfig()
+			config.normalize()
+
+			buffer := bytes.Buffer{}
+			err := writeConfigYAML(config, &buffer)
+			So(err, ShouldBeNil)
+			So(buffer.String(), ShouldNotContainSubstring, "created_at")
+			So(buffer.String(), ShouldContainSubstring, "enabled: true")
+
+			actual, err := readConfigYAML(&buffer)
+			So(err, ShouldBeNil)
+			actual.normalize()
+			So(actual, ShouldResemble, config)
+		})
+
+		Convey("Unknown fields are not allowed", func() {
+			_, err := readConfigYAML(strings.NewReader("triggers:\n- id: trigger\n  nmae: Trigger\n"))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Empty file is empty config", func() {
+			actual, err := readConfigYAML(strings.NewReader(""))
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, &declarativeConfig{})
+		})
+	})
+}
+
+func TestComputeConfigPlan(t *testing.T) {
+	Convey("Test compute config plan", t, func() {
+		Convey("Same configs have no changes", func() {
+			changes, err := computeConfigPlan(newTestDeclarativeConfig(), newTestDeclarativeConfig(), configScope{}, true)
+			So(err, ShouldBeNil)
+			So(changes, ShouldBeEmpty)
+		})
+
+		Convey("Computed trigger fields are ignored", func() {
+			current := newTestDeclarativeConfig()
+			current.Triggers[0].Patterns = []string{"my.metric"}
+			current.Triggers[0].CreatedBy = "user1"
+			current.Triggers[0].TriggerSource = moira.GraphiteLocal
+			current.Triggers[0].ClusterId = moira.DefaultCluster
+
+			changes, err := computeConfigPlan(newTestDeclarativeConfig(), current, configScope{}, true)
+			So(err, ShouldBeNil)
+			So(changes, ShouldBeEmpty)
+		})
+
+		Convey("Team roles are compared only if they are set", func() {
+			current := newTestDeclarativeConfig()
+			current.Teams[0].Roles = map[string]moira.TeamRole{"user1": moira.TeamRoleOwner, "user2": moira.TeamRoleOwner}
+
+			changes, err := computeConfigPlan(newTestDeclarativeConfig(), current, configScope{}, true)
+			So(err, ShouldBeNil)
+			So(changes, ShouldBeEmpty)
+
+			desired := newTestDeclarativeConfig()
+			desired.Teams[0].Roles = map[string]moira.TeamRole{"user1": moira.TeamRoleOwner, "user2": moira.TeamRoleViewer}
+			changes, err = computeConfigPlan(desired, current, configScope{}, true)
+			So(err, ShouldBeNil)
+			So(changes, ShouldHaveLength, 1)
+			So(changes[0].String(), ShouldEqual, "~ team team (roles)")
+		})
+
+		Convey("Signing secret of contact is compared only if it is set", func() {
+			desired := newTestDeclarativeConfig()
+			desired.Contacts[0].SigningSecret = "secret"
+
+			changes, err := computeConfigPlan(newTestDeclarativeConfig(), newTestDeclarativeConfig(), configScope{}, true)
+			So(err, ShouldBeNil)
+			So(changes, ShouldBeEmpty)
+
+			changes, err = computeConfigPlan(desired, newTestDeclarativeConfig(), configScope{}, true)
+			So(err, ShouldBeNil)
+			So(changes, ShouldHaveLength, 1)
+			So(changes[0].String(), ShouldEqual, "~ contact contact (signing_secret)")
+		})
+
+		Convey("Changes are ordered by dependencies", func() {
+			desired := newTestDeclarativeConfig()
+			desired.Triggers[0].Name = "New name"
+			desired.Subscriptions[0].Enabled = false
+			current := newTestDeclarativeConfig()
+			current.Teams = nil
+			current.Tags = []string{"tag2", "old"}
+			current.Contacts = append(current.Contacts, moira.ContactData{ID: "old-contact", Type: "mail", Value: "old@example.com"})
+
+			Convey("Without prune objects are not deleted", func() {
+				changes, err := computeConfigPlan(desired, current, configScope{}, false)
+				So(err, ShouldBeNil)
+				So(formatConfigPlan(changes), ShouldEqual, "+ team team\n"+
+					"+ tag tag1\n"+
+					"~ trigger trigger (name)\n"+
+					"~ subscription subscription (enabled)\n"+
+					"Plan: 2 to create, 2 to update, 0 to delete.\n")
+			})
+
+			Convey("With prune objects are deleted in reverse order", func() {
+				changes, err := computeConfigPlan(desired, current, configScope{}, true)
+				So(err, ShouldBeNil)
+				So(changes, ShouldHaveLength, 6)
+				So(changes[4].String(), ShouldEqual, "- contact old-contact")
+				So(changes[5].String(), ShouldEqual, "- tag old")
+			})
+		})
+
+		Convey("In team scope only objects of team are compared", func() {
+			desired := newTestDeclarativeConfig()
+			desired.Triggers = append(desired.Triggers, dto.TriggerModel{ID: "other-trigger", Tags: []string{"tag3"}})
+			current := newTestDeclarativeConfig()
+			current.Tags = []string{"tag1", "tag2", "tag3", "unused"}
+			current.Teams = nil
+			current.Subscriptions = nil
+
+			changes, err := computeConfigPlan(desired, current, configScope{Team: "team"}, true)
+			So(err, ShouldBeNil)
+			So(formatConfigPlan(changes), ShouldEqual, "+ team team\n"+
+				"+ subscription subscription\n"+
+				"Plan: 2 to create, 0 to update, 0 to delete.\n")
+		})
+
+		Convey("In tag scope contacts are not deleted", func() {
+			desired := newTestDeclarativeConfig()
+			desired.Triggers = nil
+			desired.Subscriptions = nil
+			desired.Contacts = nil
+
+			changes, err := computeConfigPlan(desired, newTestDeclarativeConfig(), configScope{Tag: "tag1"}, true)
+			So(err, ShouldBeNil)
+			So(formatConfigPlan(changes), ShouldEqual, "- subscription subscription\n"+
+				"- trigger trigger\n"+
+				"Plan: 0 to create, 0 to update, 2 to delete.\n")
+		})
+
+		Convey("Objects without ids are not allowed", func() {
+			desired := newTestDeclarativeConfig()
+			desired.Subscriptions[0].ID = ""
+
+			_, err := computeConfigPlan(desired, newTestDeclarativeConfig(), configScope{}, false)
+			So(err.Error(), ShouldEqual, "subscription without id")
+		})
+
+		Convey("Objects with duplicate ids are not allowed", func() {
+			desired := newTestDeclarativeConfig()
+			desired.Contacts = append(desired.Contacts, desired.Contacts[0])
+
+			_, err := computeConfigPlan(desired, newTestDeclarativeConfig(), configScope{}, false)
+			So(err.Error(), ShouldEqual, "duplicate contact id: contact")
+		})
+	})
+}
@@ -68,6 +68,25 @@ var (
 	triggerDumpFile = flag.String("trigger-dump-file", "", "File that holds trigger dump JSON from api method response")
 )
 
+var (
+	exportDeclarativeConfig = flag.Bool("export", false, "Export triggers, subscriptions, contacts, teams and tags to YAML file given by '-declarative-config' or to stdout")
+	planDeclarativeConfig   = flag.Bool("plan", false, "Show changes needed to bring Moira to the state described in YAML file given by '-declarative-config'")
+	applyDeclarativeConfig  = flag.Bool("apply", false, "Apply changes needed to bring Moira to the state described in YAML file given by '-declarative-config'")
+	declarativeConfigFile   = flag.String("declarative-config", "", "YAML file with triggers, subscriptions, contacts, teams and tags")
+	configTag               = flag.String("config-tag", "", "Limit export, plan and apply to triggers and subscriptions with given tag")
+	configTeam              = flag.String("config-team", "", "Limit export, plan and apply to objects of given team")
+	configPrune             = flag.Bool("prune", false, "Delete objects that are missing in YAML file during plan and apply")
+	configAPIURL            = flag.String("api-url", "", "Moira API URL (e.g. http://localhost:8081/api), plan and apply are made through API instead of redis if set")
+	configAPIToken          = flag.String("api-token", "", "Token used to authenticate in Moira API")
+)
+
+var (
+	listDeadLetters         = flag.Bool("list-dead-letters", false, "Print notifications notifier stopped trying to deliver")
+	showDeadLetter          = flag.String("show-dead-letter", "", "Print dead letter with given ID")
+	replayDeadLetter        = flag.String("replay-dead-letter", "", "Send notifications of dead letter with given ID again and delete the letter")
+	replayDeadLetterContact = flag.String("replay-contact", "", "ID of contact to send replayed notifications to, the original contact is used if empty")
+)
+
 var (
 	removeTriggersStartWith       = flag.String("remove-triggers-start-with", "", "Remove triggers which have ID starting with string parameter")
 	removeUnusedTriggersStartWith = flag.String("remove-unused-triggers-start-with", "", "Remove unused triggers which have ID starting with string parameter")
@@ -360,6 +379,45 @@ func main() { //nolint
 		logger.Info().Msg("Dump was pushed")
 	}
 
+	if *exportDeclarativeConfig || *planDeclarativeConfig || *applyDeclarativeConfig {
+		log := logger.String(moira.LogFieldNameContext, "declarative-config")
+		if err := handleDeclarativeConfig(logger, database); err != nil {
+			log.Fatal().
+				Error(err).
+				Msg("Failed to handle declarative config")
+		}
+	}
+
+	if *listDeadLetters {
+		if err := printDeadLetters(database, os.Stdout); err != nil {
+			logger.Fatal().
+				Error(err).
+				Msg("Failed to print dead letters")
+		}
+	}
+
+	if *showDeadLetter != "" {
+		if err := printDeadLetter(database, *showDeadLetter, os.Stdout); err != nil {
+			logger.Fatal().
+				Error(err).
+				Msg("Failed to print dead letter")
+		}
+	}
+
+	if *replayDeadLetter != "" {
+		count, err := replayDeadLetterToContact(database, *replayDeadLetter, *replayDeadLetterContact)
+		if err != nil {
+			logger.Fatal().
+				Error(err).
+				String("dead_letter_id", *replayDeadLetter).
+				Msg("Failed to replay dead letter")
+		}
+		logger.Info().
+			String("dead_letter_id", *replayDeadLetter).
+			Int("notifications", count).
+			Msg("Dead letter was replayed")
+	}
+
 	if *removeSubscriptions != "" {
 		logger.Info().Msg("Start deletion of subscriptions")
 		subscriptionIDs := strings.Split(*removeSubscriptions, ";")
@@ -59,7 +59,7 @@ func deleteTriggers(logger moira.Logger, triggers []string, prefix string, datab
 
 	deletedTriggersCount := 0
 	for _, id := range triggers {
-		err := database.RemoveTrigger(id)
+		err := database.RemoveTrigger(id, "")
 		if err != nil {
 			return fmt.Errorf("can't remove trigger with id %s: %w", id, err)
 		}
@@ -20,8 +20,8 @@ func Test_deleteTriggers(t *testing.T) {
 	delay = 1 * time.Millisecond
 
 	Convey("Success delete triggers", t, func() {
-		db.EXPECT().RemoveTrigger("trigger-1").Return(nil)
-		db.EXPECT().RemoveTrigger("trigger-2").Return(nil)
+		db.EXPECT().RemoveTrigger("trigger-1", "").Return(nil)
+		db.EXPECT().RemoveTrigger("trigger-2", "").Return(nil)
 
 		triggersToDelete := []string{"trigger-1", "trigger-2"}
 		err := deleteTriggers(logger, triggersToDelete, "trigger", db)
@@ -29,8 +29,8 @@ func Test_deleteTriggers(t *testing.T) {
 	})
 
 	Convey("Cannot delete trigger-2", t, func() {
-		db.EXPECT().RemoveTrigger("trigger-1").Return(nil)
-		db.EXPECT().RemoveTrigger("trigger-2").Return(errors.New("oops"))
+		db.EXPECT().RemoveTrigger("trigger-1", "").Return(nil)
+		db.EXPECT().RemoveTrigger("trigger-2", "").Return(errors.New("oops"))
 
 		triggersToDelete := []string{"trigger-1", "trigger-2"}
 		err := deleteTriggers(logger, triggersToDelete, "trigger", db)
@@ -50,8 +50,8 @@ func Test_handleRemoveTriggersStartWith(t *testing.T) {
 		triggersToDelete := []string{"trigger-1", "trigger-2"}
 
 		db.EXPECT().GetTriggerIDsStartWith("trigger").Return(triggersToDelete, nil)
-		db.EXPECT().RemoveTrigger("trigger-1").Return(nil)
-		db.EXPECT().RemoveTrigger("trigger-2").Return(nil)
+		db.EXPECT().RemoveTrigger("trigger-1", "").Return(nil)
+		db.EXPECT().RemoveTrigger("trigger-2", "").Return(nil)
 
 		err := handleRemoveTriggersStartWith(logger, db, "trigger")
 		So(err, ShouldBeNil)
@@ -78,7 +78,7 @@ func Test_handleRemoveUnusedTriggersStartWith(t *testing.T) {
 
 		db.EXPECT().GetTriggerIDsStartWith("trigger").Return(triggers, nil)
 		db.EXPECT().GetUnusedTriggerIDs().Return([]string{"trigger-1"}, nil)
-		db.EXPECT().RemoveTrigger("trigger-1").Return(nil)
+		db.EXPECT().RemoveTrigger("trigger-1", "").Return(nil)
 
 		err := handleRemoveUnusedTriggersStartWith(logger, db, "trigger")
 		So(err, ShouldBeNil)
@@ -9,6 +9,7 @@ import (
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/metrics"
 
+	"github.com/moira-alert/moira/image_store/filesystem"
 	"github.com/moira-alert/moira/image_store/s3"
 	prometheusRemoteSource "github.com/moira-alert/moira/metric_source/prometheus"
 	graphiteRemoteSource "github.com/moira-alert/moira/metric_source/remote"
@@ -55,10 +56,32 @@ type RedisConfig struct {
 	// Allows routing read-only commands to the **random** master or slave node.
 	// It automatically enables ReadOnly.
 	RouteRandomly bool `yaml:"route_randomly"`
+	// Count of trigger versions which are kept in trigger history. Default is 20, negative value disables trigger history.
+	// History is written by every service saving triggers, so the value should be the same in configs of all services.
+	TriggerHistorySize int `yaml:"trigger_history_size"`
+	// Time during which history of deleted trigger is kept and trigger can be restored. Default is 168h.
+	DeletedTriggersTTL string `yaml:"deleted_triggers_ttl"`
+	// Time during which audit log records are kept. Empty value means that records are kept forever.
+	AuditLogTTL string `yaml:"audit_log_ttl"`
+	// Time during which changes of metrics states are kept in metric state timeline. Empty value disables timeline.
+	MetricTimelineTTL string `yaml:"metric_timeline_ttl"`
+	// Time during which notification delivery attempts are kept in delivery log. Empty value disables delivery log.
+	DeliveryLogTTL string `yaml:"delivery_log_ttl"`
+	// Time during which undelivered notifications are kept in dead letters. Empty value means that they are kept until replayed or removed.
+	DeadLettersTTL string `yaml:"dead_letters_ttl"`
 }
 
 // GetSettings returns redis config parsed from moira config files.
 func (config *RedisConfig) GetSettings() redis.DatabaseConfig {
+	triggerHistorySize := config.TriggerHistorySize
+	if triggerHistorySize == 0 {
+		triggerHistorySize = redis.DefaultTriggerHistorySize
+	}
+	deletedTriggersTTL := redis.DefaultDeletedTriggersTTL
+	if config.DeletedTriggersTTL != "" {
+		deletedTriggersTTL = to.Duration(config.DeletedTriggersTTL)
+	}
+
 	return redis.DatabaseConfig{
 		MasterName:     config.MasterName,
 		Addrs:          strings.Split(config.Addrs, ","),
@@ -72,6 +95,13 @@ func (config *RedisConfig) GetSettings() redis.DatabaseConfig {
 		ReadOnly:       config.ReadOnly,
 		RouteByLatency: config.RouteByLatency,
 		RouteRandomly:  config.RouteRandomly,
+
+		TriggerHistorySize: triggerHistorySize,
+		DeletedTriggersTTL: deletedTriggersTTL,
+		AuditLogTTL:        to.Duration(config.AuditLogTTL),
+		MetricTimelineTTL:  to.Duration(config.MetricTimelineTTL),
+		DeliveryLogTTL:     to.Duration(config.DeliveryLogTTL),
+		DeadLettersTTL:     to.Duration(config.DeadLettersTTL),
 	}
 }
 
@@ -293,7 +323,8 @@ func (config *PrometheusRemoteConfig) GetPrometheusSourceSettings() *prometheusR
 
 // ImageStoreConfig defines the configuration for all the image stores to be initialized by InitImageStores.
 type ImageStoreConfig struct {
-	S3 s3.Config `yaml:"s3"`
+	S3         s3.Config         `yaml:"s3"`
+	Filesystem filesystem.Config `yaml:"filesystem"`
 }
 
 // ReadConfig parses config file by the given path into Moira-used type.
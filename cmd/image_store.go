@@ -3,14 +3,17 @@ package cmd
 import (
 	"github.com/moira-alert/moira"
 
+	"github.com/moira-alert/moira/image_store/filesystem"
 	"github.com/moira-alert/moira/image_store/s3"
 )
 
 const (
-	s3ImageStore = "s3"
+	s3ImageStore         = "s3"
+	filesystemImageStore = "filesystem"
 )
 
 // InitImageStores initializes the image storage provider with settings from the yaml config.
+// Garbage collector of filesystem image store is started, use StopImageStores to stop it.
 func InitImageStores(imageStores ImageStoreConfig, logger moira.Logger) map[string]moira.ImageStore {
 	var err error
 	imageStoreMap := make(map[string]moira.ImageStore)
@@ -29,5 +32,34 @@ func InitImageStores(imageStores ImageStoreConfig, logger moira.Logger) map[stri
 	}
 	imageStoreMap[s3ImageStore] = imageStore
 
+	fsImageStore := &filesystem.ImageStore{}
+	if imageStores.Filesystem != (filesystem.Config{}) {
+		if err = fsImageStore.Init(imageStores.Filesystem); err != nil {
+			logger.Warning().
+				Error(err).
+				Msg("Failed to initialize image store")
+		} else {
+			fsImageStore.Start(logger)
+			logger.Info().
+				String("image_storage", filesystemImageStore).
+				Msg("Image store initialized")
+		}
+	}
+	imageStoreMap[filesystemImageStore] = fsImageStore
+
 	return imageStoreMap
 }
+
+// StopImageStores stops background workers of image stores initialized by InitImageStores.
+func StopImageStores(imageStoreMap map[string]moira.ImageStore, logger moira.Logger) {
+	fsImageStore, ok := imageStoreMap[filesystemImageStore].(*filesystem.ImageStore)
+	if !ok || !fsImageStore.IsEnabled() {
+		return
+	}
+	if err := fsImageStore.Stop(); err != nil {
+		logger.Error().
+			Error(err).
+			String("image_storage", filesystemImageStore).
+			Msg("Failed to stop image store")
+	}
+}
@@ -2,11 +2,13 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/xiam/to"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/analytics"
 	"github.com/moira-alert/moira/cmd"
 	"github.com/moira-alert/moira/notifier"
 	"github.com/moira-alert/moira/notifier/selfstate"
@@ -54,6 +56,40 @@ type notifierConfig struct {
 	MaxFailAttemptToSendAvailable int `yaml:"max_fail_attempt_to_send_available"`
 	// Specify log level by entities
 	SetLogLevel setLogLevelConfig `yaml:"set_log_level"`
+	// Weekly report of noisy triggers, recovery times and sent notifications
+	AnalyticsReport analyticsReportConfig `yaml:"analytics_report"`
+}
+
+type analyticsReportConfig struct {
+	// If true, report is sent every week
+	Enabled bool `yaml:"enabled"`
+	// Day of week report is sent at, for example "monday"
+	Weekday string `yaml:"weekday"`
+	// Hour report is sent at
+	Hour int `yaml:"hour"`
+	// Timezone of weekday and hour. Default is UTC
+	Timezone string `yaml:"timezone"`
+	// Time range before sending covered by report
+	Period string `yaml:"period"`
+	// Max number of triggers in each top list of report
+	Top int `yaml:"top"`
+	// URL report is sent to in JSON with POST request. Empty value disables webhook
+	WebhookURL string `yaml:"webhook_url"`
+	// Timeout of report webhook request
+	WebhookTimeout string `yaml:"webhook_timeout"`
+	// Email settings, report is not sent by email if there are no recipients
+	Mail analyticsReportMailConfig `yaml:"mail"`
+}
+
+type analyticsReportMailConfig struct {
+	MailFrom    string   `yaml:"mail_from"`
+	MailTo      []string `yaml:"mail_to"`
+	SMTPHello   string   `yaml:"smtp_hello"`
+	SMTPHost    string   `yaml:"smtp_host"`
+	SMTPPort    int      `yaml:"smtp_port"`
+	SMTPUser    string   `yaml:"smtp_user"`
+	SMTPPass    string   `yaml:"smtp_pass"`
+	InsecureTLS bool     `yaml:"insecure_tls"`
 }
 
 type selfStateConfig struct {
@@ -83,6 +119,8 @@ func getDefault() config {
 			Addrs:       "localhost:6379",
 			MetricsTTL:  "1h",
 			DialTimeout: "500ms",
+
+			DeliveryLogTTL: "168h",
 		},
 		Logger: cmd.LoggerConfig{
 			LogFile:         "stdout",
@@ -114,6 +152,18 @@ func getDefault() config {
 			Timezone:                      "UTC",
 			ReadBatchSize:                 int(notifier.NotificationsLimitUnlimited),
 			MaxFailAttemptToSendAvailable: 3,
+			AnalyticsReport: analyticsReportConfig{
+				Enabled:        false,
+				Weekday:        "monday",
+				Hour:           9,
+				Timezone:       "UTC",
+				Period:         "168h",
+				Top:            analytics.DefaultTop,
+				WebhookTimeout: "10s",
+				Mail: analyticsReportMailConfig{
+					SMTPPort: 25,
+				},
+			},
 		},
 		Telemetry: cmd.TelemetryConfig{
 			Listen: ":8093",
@@ -229,3 +279,58 @@ func (config *selfStateConfig) getSettings() selfstate.Config {
 		NoticeIntervalSeconds:          int64(to.Duration(config.NoticeInterval).Seconds()),
 	}
 }
+
+func (config *analyticsReportConfig) getSettings(frontURI string) (analytics.ReporterConfig, []analytics.Sink, error) {
+	weekday, err := parseWeekday(config.Weekday)
+	if err != nil {
+		return analytics.ReporterConfig{}, nil, err
+	}
+
+	location, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		return analytics.ReporterConfig{}, nil, fmt.Errorf("failed to load analytics report timezone: %w", err)
+	}
+
+	if config.Hour < 0 || config.Hour > 23 { //nolint:gomnd
+		return analytics.ReporterConfig{}, nil, fmt.Errorf("analytics report hour must be in range 0-23, got %d", config.Hour)
+	}
+
+	sinks := make([]analytics.Sink, 0)
+	if config.WebhookURL != "" {
+		sinks = append(sinks, analytics.NewWebhookSink(config.WebhookURL, to.Duration(config.WebhookTimeout)))
+	}
+	if len(config.Mail.MailTo) > 0 {
+		sinks = append(sinks, analytics.NewMailSink(analytics.MailConfig{
+			From:        config.Mail.MailFrom,
+			To:          config.Mail.MailTo,
+			SMTPHello:   config.Mail.SMTPHello,
+			SMTPHost:    config.Mail.SMTPHost,
+			SMTPPort:    config.Mail.SMTPPort,
+			SMTPUser:    config.Mail.SMTPUser,
+			SMTPPass:    config.Mail.SMTPPass,
+			InsecureTLS: config.Mail.InsecureTLS,
+			FrontURI:    frontURI,
+		}))
+	}
+	if config.Enabled && len(sinks) == 0 {
+		return analytics.ReporterConfig{}, nil, fmt.Errorf("analytics report requires webhook_url or mail recipients")
+	}
+
+	return analytics.ReporterConfig{
+		Enabled:  config.Enabled,
+		Weekday:  weekday,
+		Hour:     config.Hour,
+		Location: location,
+		Period:   to.Duration(config.Period),
+		Top:      config.Top,
+	}, sinks, nil
+}
+
+func parseWeekday(weekday string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), weekday) {
+			return day, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("unknown analytics report weekday: %s", weekday)
+}
@@ -8,6 +8,7 @@ import (
 	"syscall"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/analytics"
 	"github.com/moira-alert/moira/cmd"
 	"github.com/moira-alert/moira/database/redis"
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
@@ -88,6 +89,7 @@ func main() {
 
 	// Initialize the image store
 	imageStoreMap := cmd.InitImageStores(config.ImageStores, logger)
+	defer cmd.StopImageStores(imageStoreMap, logger)
 
 	notifierConfig := config.Notifier.getSettings(logger)
 
@@ -121,6 +123,19 @@ func main() {
 		logger.Debug().Msg("Moira Self State Monitoring disabled")
 	}
 
+	// Start moira analytics reporter
+	if config.Notifier.AnalyticsReport.Enabled {
+		reporterConfig, reportSinks, err := config.Notifier.AnalyticsReport.getSettings(config.Notifier.FrontURI)
+		if err != nil {
+			logger.Fatal().
+				Error(err).
+				Msg("Can not configure analytics report")
+		}
+		reporter := analytics.NewReporter(database, logger, reporterConfig, reportSinks...)
+		reporter.Start()
+		defer stopAnalyticsReporter(reporter)
+	}
+
 	// Start moira notification fetcher
 	fetchNotificationsWorker := &notifications.FetchNotificationsWorker{
 		Logger:   logger,
@@ -167,6 +182,14 @@ func stopNotificationsFetcher(worker *notifications.FetchNotificationsWorker) {
 	}
 }
 
+func stopAnalyticsReporter(reporter *analytics.Reporter) {
+	if err := reporter.Stop(); err != nil {
+		logger.Error().
+			Error(err).
+			Msg("Failed to stop analytics reporter")
+	}
+}
+
 func stopSelfStateChecker(checker *selfstate.SelfCheckWorker) {
 	if err := checker.Stop(); err != nil {
 		logger.Error().
@@ -0,0 +1,25 @@
+package redis
+
+import (
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// GetAnalyticsReportSentAt returns time of the last scheduled analytics report, 0 if report was never sent.
+func (connector *DbConnector) GetAnalyticsReportSentAt() (int64, error) {
+	c := *connector.client
+	timestamp, err := c.Get(connector.context, analyticsReportSentAtKey).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return timestamp, err
+}
+
+// SetAnalyticsReportSentAt saves time of the last scheduled analytics report.
+func (connector *DbConnector) SetAnalyticsReportSentAt(timestamp int64) error {
+	c := *connector.client
+	return c.Set(connector.context, analyticsReportSentAtKey, timestamp, redis.KeepTTL).Err()
+}
+
+var analyticsReportSentAtKey = "moira-analytics-report-sent-at"
@@ -0,0 +1,27 @@
+package redis
+
+import (
+	"testing"
+
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAnalyticsReportSentAt(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	Convey("Analytics report sent at", t, func() {
+		sentAt, err := dataBase.GetAnalyticsReportSentAt()
+		So(err, ShouldBeNil)
+		So(sentAt, ShouldEqual, 0)
+
+		So(dataBase.SetAnalyticsReportSentAt(1000), ShouldBeNil)
+
+		sentAt, err = dataBase.GetAnalyticsReportSentAt()
+		So(err, ShouldBeNil)
+		So(sentAt, ShouldEqual, 1000)
+	})
+}
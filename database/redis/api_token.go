@@ -0,0 +1,115 @@
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/database/redis/reply"
+)
+
+// GetAPIToken returns api token by given id, if no value, return database.ErrNil error.
+func (connector *DbConnector) GetAPIToken(tokenID string) (moira.APIToken, error) {
+	c := *connector.client
+
+	return reply.APIToken(c.Get(connector.context, apiTokenKey(tokenID)))
+}
+
+// GetAPITokens returns api tokens by given ids, len of tokenIDs is equal to len of returned values array.
+// If there is no object by current ID, then nil is returned.
+func (connector *DbConnector) GetAPITokens(tokenIDs []string) ([]*moira.APIToken, error) {
+	c := *connector.client
+
+	results := make([]*redis.StringCmd, 0, len(tokenIDs))
+	pipe := c.TxPipeline()
+	for _, id := range tokenIDs {
+		results = append(results, pipe.Get(connector.context, apiTokenKey(id)))
+	}
+	if _, err := pipe.Exec(connector.context); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	return reply.APITokens(results)
+}
+
+// SaveAPIToken writes api token and adds it to tokens of its owner.
+func (connector *DbConnector) SaveAPIToken(token *moira.APIToken) error {
+	bytes, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal api token: %w", err)
+	}
+
+	c := *connector.client
+
+	pipe := c.TxPipeline()
+	pipe.Set(connector.context, apiTokenKey(token.ID), bytes, redis.KeepTTL)
+	if token.User != "" {
+		pipe.SAdd(connector.context, userAPITokensKey(token.User), token.ID)
+	}
+	if token.TeamID != "" {
+		pipe.SAdd(connector.context, teamAPITokensKey(token.TeamID), token.ID)
+	}
+	if _, err = pipe.Exec(connector.context); err != nil {
+		return fmt.Errorf("failed to save api token: %w", err)
+	}
+	return nil
+}
+
+// RemoveAPIToken deletes api token and removes it from tokens of its owner.
+func (connector *DbConnector) RemoveAPIToken(tokenID string) error {
+	existing, err := connector.GetAPIToken(tokenID)
+	if err != nil {
+		if errors.Is(err, database.ErrNil) {
+			return nil
+		}
+		return err
+	}
+
+	c := *connector.client
+
+	pipe := c.TxPipeline()
+	pipe.Del(connector.context, apiTokenKey(tokenID))
+	pipe.SRem(connector.context, userAPITokensKey(existing.User), tokenID)
+	pipe.SRem(connector.context, teamAPITokensKey(existing.TeamID), tokenID)
+	if _, err = pipe.Exec(connector.context); err != nil {
+		return fmt.Errorf("failed to remove api token: %w", err)
+	}
+	return nil
+}
+
+// GetUserAPITokenIDs returns ids of personal api tokens of given user.
+func (connector *DbConnector) GetUserAPITokenIDs(userLogin string) ([]string, error) {
+	c := *connector.client
+
+	tokenIDs, err := c.SMembers(connector.context, userAPITokensKey(userLogin)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api tokens for user %s: %w", userLogin, err)
+	}
+	return tokenIDs, nil
+}
+
+// GetTeamAPITokenIDs returns ids of api tokens of given team.
+func (connector *DbConnector) GetTeamAPITokenIDs(teamID string) ([]string, error) {
+	c := *connector.client
+
+	tokenIDs, err := c.SMembers(connector.context, teamAPITokensKey(teamID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api tokens for team %s: %w", teamID, err)
+	}
+	return tokenIDs, nil
+}
+
+func apiTokenKey(id string) string {
+	return "moira-api-token:" + id
+}
+
+func userAPITokensKey(userLogin string) string {
+	return "moira-user-api-tokens:" + userLogin
+}
+
+func teamAPITokensKey(teamID string) string {
+	return "moira-team-api-tokens:" + teamID
+}
@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"testing"
+
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+func TestAPITokenStoring(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	Convey("API token manipulation", t, func() {
+		dataBase.Flush()
+
+		personal := &moira.APIToken{
+			ID:         "personal",
+			Name:       "ci",
+			User:       user1,
+			Scopes:     []moira.APITokenScope{moira.APITokenScopeRead},
+			SecretHash: "hash1",
+		}
+		team := &moira.APIToken{
+			ID:         "team",
+			Name:       "ci",
+			TeamID:     "team1",
+			Scopes:     []moira.APITokenScope{moira.TeamScope("team1")},
+			SecretHash: "hash2",
+		}
+
+		Convey("Unknown token", func() {
+			_, err := dataBase.GetAPIToken("unknown")
+			So(err, ShouldResemble, database.ErrNil)
+		})
+
+		Convey("Save and get tokens", func() {
+			So(dataBase.SaveAPIToken(personal), ShouldBeNil)
+			So(dataBase.SaveAPIToken(team), ShouldBeNil)
+
+			token, err := dataBase.GetAPIToken("personal")
+			So(err, ShouldBeNil)
+			So(token, ShouldResemble, *personal)
+
+			tokens, err := dataBase.GetAPITokens([]string{"team", "unknown"})
+			So(err, ShouldBeNil)
+			So(tokens, ShouldResemble, []*moira.APIToken{team, nil})
+
+			tokenIDs, err := dataBase.GetUserAPITokenIDs(user1)
+			So(err, ShouldBeNil)
+			So(tokenIDs, ShouldResemble, []string{"personal"})
+
+			tokenIDs, err = dataBase.GetTeamAPITokenIDs("team1")
+			So(err, ShouldBeNil)
+			So(tokenIDs, ShouldResemble, []string{"team"})
+		})
+
+		Convey("Remove token", func() {
+			So(dataBase.SaveAPIToken(personal), ShouldBeNil)
+			So(dataBase.RemoveAPIToken("personal"), ShouldBeNil)
+			So(dataBase.RemoveAPIToken("unknown"), ShouldBeNil)
+
+			_, err := dataBase.GetAPIToken("personal")
+			So(err, ShouldResemble, database.ErrNil)
+
+			tokenIDs, err := dataBase.GetUserAPITokenIDs(user1)
+			So(err, ShouldBeNil)
+			So(tokenIDs, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/moira-alert/moira"
+)
+
+const auditLogKey = "moira-audit-log"
+
+// auditLogBatchSize is the count of audit records fetched from database at once while filtering them.
+const auditLogBatchSize = 1000
+
+// SaveAuditRecord saves audit record and deletes records older than audit log TTL.
+func (connector *DbConnector) SaveAuditRecord(record *moira.AuditRecord) error {
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	pipe := (*connector.client).TxPipeline()
+	pipe.ZAdd(connector.context, auditLogKey, &redis.Z{Score: float64(record.Timestamp), Member: bytes})
+
+	if connector.auditLogTTL > 0 {
+		to := connector.clock.Now().Add(-connector.auditLogTTL).Unix()
+		pipe.ZRemRangeByScore(connector.context, auditLogKey, "-inf", "("+strconv.FormatInt(to, 10))
+	}
+
+	if _, err = pipe.Exec(connector.context); err != nil {
+		return fmt.Errorf("failed to save audit record: %w", err)
+	}
+	return nil
+}
+
+// GetAuditRecords returns audit records matched by filter, the newest records go first.
+func (connector *DbConnector) GetAuditRecords(filter moira.AuditRecordsFilter) ([]*moira.AuditRecord, error) {
+	c := *connector.client
+
+	rangeBy := &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   "+inf",
+		Count: auditLogBatchSize,
+	}
+	if filter.From > 0 {
+		rangeBy.Min = strconv.FormatInt(filter.From, 10)
+	}
+	if filter.To > 0 {
+		rangeBy.Max = strconv.FormatInt(filter.To, 10)
+	}
+
+	records := make([]*moira.AuditRecord, 0)
+	for {
+		values, err := c.ZRevRangeByScoreWithScores(connector.context, auditLogKey, rangeBy).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get audit records: %w", err)
+		}
+
+		for _, value := range values {
+			member, _ := value.Member.(string)
+			record := &moira.AuditRecord{}
+			if err = json.Unmarshal([]byte(member), record); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal audit record %s: %w", member, err)
+			}
+			if !filter.IsMatched(record) {
+				continue
+			}
+
+			records = append(records, record)
+			if filter.Limit > 0 && int64(len(records)) >= filter.Limit {
+				return records, nil
+			}
+		}
+
+		if len(values) < auditLogBatchSize {
+			return records, nil
+		}
+		nextAuditLogBatch(rangeBy, values)
+	}
+}
+
+// nextAuditLogBatch moves range to records older than the last record of batch. Range is moved by score,
+// so records saved meanwhile do not shift batches, and only records with the same score as the last record
+// are skipped by offset, because they can be split between batches.
+func nextAuditLogBatch(rangeBy *redis.ZRangeBy, batch []redis.Z) {
+	lastScore := batch[len(batch)-1].Score
+	var sameScoreCount int64
+	for i := len(batch) - 1; i >= 0 && batch[i].Score == lastScore; i-- {
+		sameScoreCount++
+	}
+
+	maxScore := strconv.FormatFloat(lastScore, 'f', -1, 64)
+	if rangeBy.Max == maxScore {
+		rangeBy.Offset += sameScoreCount
+		return
+	}
+	rangeBy.Max = maxScore
+	rangeBy.Offset = sameScoreCount
+}
@@ -0,0 +1,104 @@
+package redis
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	mock_clock "github.com/moira-alert/moira/mock/clock"
+)
+
+func TestAuditLogStoring(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	clock := mock_clock.NewMockClock(mockCtrl)
+	dataBase.clock = clock
+	now := time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC)
+	clock.EXPECT().Now().Return(now).AnyTimes()
+
+	Convey("Audit log manipulation", t, func() {
+		dataBase.Flush()
+
+		first := &moira.AuditRecord{
+			Timestamp:  now.Add(-time.Hour).Unix(),
+			Actor:      user1,
+			Action:     moira.AuditActionCreate,
+			ObjectType: moira.AuditObjectTag,
+			ObjectID:   "tag",
+		}
+		second := &moira.AuditRecord{
+			Timestamp:  now.Unix(),
+			Actor:      user2,
+			Action:     moira.AuditActionDelete,
+			ObjectType: moira.AuditObjectTag,
+			ObjectID:   "tag",
+		}
+		So(dataBase.SaveAuditRecord(first), ShouldBeNil)
+		So(dataBase.SaveAuditRecord(second), ShouldBeNil)
+
+		Convey("Newest records go first", func() {
+			records, err := dataBase.GetAuditRecords(moira.AuditRecordsFilter{})
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []*moira.AuditRecord{second, first})
+		})
+
+		Convey("Records are filtered", func() {
+			records, err := dataBase.GetAuditRecords(moira.AuditRecordsFilter{Actor: user1})
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []*moira.AuditRecord{first})
+
+			records, err = dataBase.GetAuditRecords(moira.AuditRecordsFilter{To: now.Add(-time.Minute).Unix()})
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []*moira.AuditRecord{first})
+
+			records, err = dataBase.GetAuditRecords(moira.AuditRecordsFilter{Limit: 1})
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []*moira.AuditRecord{second})
+		})
+
+		Convey("Records are read in batches by score", func() {
+			for i := 0; i < auditLogBatchSize+10; i++ {
+				record := &moira.AuditRecord{
+					Timestamp: now.Add(-time.Duration(i%3) * time.Second).Unix(),
+					Actor:     user1,
+					Action:    moira.AuditActionUpdate,
+					ObjectID:  strconv.Itoa(i),
+				}
+				So(dataBase.SaveAuditRecord(record), ShouldBeNil)
+			}
+
+			records, err := dataBase.GetAuditRecords(moira.AuditRecordsFilter{Action: moira.AuditActionUpdate})
+			So(err, ShouldBeNil)
+			So(records, ShouldHaveLength, auditLogBatchSize+10)
+			objectIDs := make(map[string]bool)
+			for _, record := range records {
+				objectIDs[record.ObjectID] = true
+			}
+			So(objectIDs, ShouldHaveLength, auditLogBatchSize+10)
+		})
+
+		Convey("Outdated records are removed", func() {
+			outdated := &moira.AuditRecord{
+				Timestamp: now.Add(-dataBase.auditLogTTL - time.Hour).Unix(),
+				Actor:     user1,
+				Action:    moira.AuditActionUpdate,
+			}
+			So(dataBase.SaveAuditRecord(outdated), ShouldBeNil)
+
+			records, err := dataBase.GetAuditRecords(moira.AuditRecordsFilter{})
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []*moira.AuditRecord{second, first})
+		})
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/moira-alert/moira/database"
@@ -40,6 +41,41 @@ func (connector *DbConnector) RemoveUser(messenger, username string) error {
 	return nil
 }
 
+// GetMessageThread reads root message of thread by its key.
+func (connector *DbConnector) GetMessageThread(messenger, threadKey string) (string, error) {
+	c := *connector.client
+	result, err := c.Get(connector.context, messageThreadKey(messenger, threadKey)).Result()
+	if errors.Is(err, redis.Nil) {
+		return result, database.ErrNil
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to get message thread '%s' of messenger '%s', error: %w", threadKey, messenger, err)
+	}
+	return result, nil
+}
+
+// SetMessageThread stores root message of thread, it is removed after ttl.
+func (connector *DbConnector) SetMessageThread(messenger, threadKey, thread string, ttl time.Duration) error {
+	c := *connector.client
+	if err := c.Set(connector.context, messageThreadKey(messenger, threadKey), thread, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set message thread '%s' of messenger '%s', error: %w", threadKey, messenger, err)
+	}
+	return nil
+}
+
+// RemoveMessageThread removes root message of thread.
+func (connector *DbConnector) RemoveMessageThread(messenger, threadKey string) error {
+	c := *connector.client
+	if err := c.Del(connector.context, messageThreadKey(messenger, threadKey)).Err(); err != nil {
+		return fmt.Errorf("failed to delete message thread '%s' of messenger '%s', error: %w", threadKey, messenger, err)
+	}
+	return nil
+}
+
 func usernameKey(messenger, username string) string {
 	return fmt.Sprintf("moira-%s-users:%s", messenger, username)
 }
+
+func messageThreadKey(messenger, threadKey string) string {
+	return fmt.Sprintf("moira-%s-threads:%s", messenger, threadKey)
+}
@@ -2,6 +2,7 @@ package redis
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 
@@ -58,6 +59,49 @@ func TestBotDataStoring(t *testing.T) {
 	})
 }
 
+func TestMessageThreadStoring(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test", true)
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	Convey("Message threads manipulation", t, func() {
+		Convey("Get not existing thread", func() {
+			actual, err := dataBase.GetMessageThread(messenger1, "contact:trigger")
+			So(err, ShouldResemble, database.ErrNil)
+			So(actual, ShouldBeEmpty)
+		})
+
+		Convey("Set, get and remove thread", func() {
+			err := dataBase.SetMessageThread(messenger1, "contact:trigger", "thread", time.Hour)
+			So(err, ShouldBeNil)
+
+			actual, err := dataBase.GetMessageThread(messenger1, "contact:trigger")
+			So(err, ShouldBeNil)
+			So(actual, ShouldEqual, "thread")
+
+			actual, err = dataBase.GetMessageThread(messenger2, "contact:trigger")
+			So(err, ShouldResemble, database.ErrNil)
+			So(actual, ShouldBeEmpty)
+
+			err = dataBase.RemoveMessageThread(messenger1, "contact:trigger")
+			So(err, ShouldBeNil)
+
+			_, err = dataBase.GetMessageThread(messenger1, "contact:trigger")
+			So(err, ShouldResemble, database.ErrNil)
+		})
+
+		Convey("Thread expires", func() {
+			err := dataBase.SetMessageThread(messenger1, "contact:trigger", "thread", time.Millisecond)
+			So(err, ShouldBeNil)
+			time.Sleep(10 * time.Millisecond)
+
+			_, err = dataBase.GetMessageThread(messenger1, "contact:trigger")
+			So(err, ShouldResemble, database.ErrNil)
+		})
+	})
+}
+
 func TestBotDataStoringErrorConnection(t *testing.T) {
 	logger, _ := logging.ConfigureLog("stdout", "info", "test", true)
 	dataBase := NewTestDatabaseWithIncorrectConfig(logger)
@@ -73,6 +117,15 @@ func TestBotDataStoringErrorConnection(t *testing.T) {
 
 		err = dataBase.RemoveUser(messenger2, user1)
 		So(err, ShouldNotBeNil)
+
+		_, err = dataBase.GetMessageThread(messenger1, "thread")
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.SetMessageThread(messenger1, "thread", "value", time.Hour)
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.RemoveMessageThread(messenger1, "thread")
+		So(err, ShouldNotBeNil)
 	})
 }
 
@@ -2,6 +2,13 @@ package redis
 
 import "time"
 
+const (
+	// DefaultTriggerHistorySize is the count of trigger versions kept if it is not set in config.
+	DefaultTriggerHistorySize = 20
+	// DefaultDeletedTriggersTTL is the time during which history of deleted trigger is kept if it is not set in config.
+	DefaultDeletedTriggersTTL = 7 * 24 * time.Hour
+)
+
 // DatabaseConfig - Redis database connection config.
 type DatabaseConfig struct {
 	MasterName       string
@@ -18,6 +25,18 @@ type DatabaseConfig struct {
 	ReadOnly         bool
 	RouteByLatency   bool
 	RouteRandomly    bool
+	// TriggerHistorySize is the count of trigger versions to keep, non-positive value disables trigger history
+	TriggerHistorySize int
+	// DeletedTriggersTTL is the time during which history of deleted trigger is kept
+	DeletedTriggersTTL time.Duration
+	// AuditLogTTL is the time during which audit records are kept, 0 means forever
+	AuditLogTTL time.Duration
+	// MetricTimelineTTL is the time during which changes of metrics states are kept, 0 disables metric state timeline
+	MetricTimelineTTL time.Duration
+	// DeliveryLogTTL is the time during which notification delivery attempts are kept, 0 disables delivery log
+	DeliveryLogTTL time.Duration
+	// DeadLettersTTL is the time during which undelivered notifications are kept, 0 means forever
+	DeadLettersTTL time.Duration
 }
 
 type NotificationHistoryConfig struct {
@@ -10,7 +10,10 @@ import (
 	"github.com/moira-alert/moira"
 )
 
-const contactNotificationKey = "moira-contact-notifications"
+const (
+	contactNotificationKey        = "moira-contact-notifications"
+	notificationsHistoryBatchSize = 1000
+)
 
 func getNotificationBytes(notification *moira.NotificationEventHistoryItem) ([]byte, error) {
 	bytes, err := json.Marshal(notification)
@@ -56,6 +59,37 @@ func (connector *DbConnector) GetNotificationsByContactIdWithLimit(contactID str
 	return notifications, nil
 }
 
+// GetNotificationsHistory returns notifications sent to all contacts in given time range.
+// Unlike GetNotificationsByContactIdWithLimit it is not limited by notification history query limit.
+func (connector *DbConnector) GetNotificationsHistory(from int64, to int64) ([]*moira.NotificationEventHistoryItem, error) {
+	c := *connector.client
+	notifications := make([]*moira.NotificationEventHistoryItem, 0)
+
+	for offset := int64(0); ; offset += notificationsHistoryBatchSize {
+		notificationStrings, err := c.ZRangeByScore(connector.context, contactNotificationKey, &redis.ZRangeBy{
+			Min:    strconv.FormatInt(from, 10),
+			Max:    strconv.FormatInt(to, 10),
+			Offset: offset,
+			Count:  notificationsHistoryBatchSize,
+		}).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get notifications history: %w", err)
+		}
+
+		for _, notification := range notificationStrings {
+			notificationObj, err := getNotificationStruct(notification)
+			if err != nil {
+				return nil, err
+			}
+			notifications = append(notifications, &notificationObj)
+		}
+
+		if int64(len(notificationStrings)) < notificationsHistoryBatchSize {
+			return notifications, nil
+		}
+	}
+}
+
 // PushContactNotificationToHistory converts ScheduledNotification to NotificationEventHistoryItem and saves it,
 // and deletes items older than specified ttl.
 func (connector *DbConnector) PushContactNotificationToHistory(notification *moira.ScheduledNotification) error {
@@ -130,6 +130,27 @@ func TestGetNotificationsByContactIdWithLimit(t *testing.T) {
 	})
 }
 
+func TestGetNotificationsHistory(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+
+	Convey("Notifications history of all contacts", t, func() {
+		dataBase.Flush()
+		defer dataBase.Flush()
+
+		items, err := dataBase.GetNotificationsHistory(eventsShouldBeInDb[0].TimeStamp-5, eventsShouldBeInDb[0].TimeStamp+5)
+		So(err, ShouldBeNil)
+		So(items, ShouldBeEmpty)
+
+		err = dataBase.PushContactNotificationToHistory(&inputScheduledNotification)
+		So(err, ShouldBeNil)
+
+		items, err = dataBase.GetNotificationsHistory(eventsShouldBeInDb[0].TimeStamp-5, eventsShouldBeInDb[0].TimeStamp+5)
+		So(err, ShouldBeNil)
+		So(items, ShouldResemble, eventsShouldBeInDb)
+	})
+}
+
 func TestPushNotificationToHistory(t *testing.T) {
 	logger, _ := logging.GetLogger("dataBase")
 	dataBase := NewTestDatabase(logger)
@@ -46,6 +46,12 @@ type DbConnector struct {
 	source               DBSource
 	clock                moira.Clock
 	notificationHistory  NotificationHistoryConfig
+	triggerHistorySize   int
+	deletedTriggersTTL   time.Duration
+	auditLogTTL          time.Duration
+	metricTimelineTTL    time.Duration
+	deliveryLogTTL       time.Duration
+	deadLettersTTL       time.Duration
 	// Notifier configuration in redis
 	notification NotificationConfig
 }
@@ -83,6 +89,12 @@ func NewDatabase(logger moira.Logger, config DatabaseConfig, nh NotificationHist
 		source:               source,
 		clock:                clock.NewSystemClock(),
 		notificationHistory:  nh,
+		triggerHistorySize:   config.TriggerHistorySize,
+		deletedTriggersTTL:   config.DeletedTriggersTTL,
+		auditLogTTL:          config.AuditLogTTL,
+		metricTimelineTTL:    config.MetricTimelineTTL,
+		deliveryLogTTL:       config.DeliveryLogTTL,
+		deadLettersTTL:       config.DeadLettersTTL,
 		notification:         n,
 	}
 
@@ -92,7 +104,12 @@ func NewDatabase(logger moira.Logger, config DatabaseConfig, nh NotificationHist
 // NewTestDatabase use it only for tests.
 func NewTestDatabase(logger moira.Logger) *DbConnector {
 	return NewDatabase(logger, DatabaseConfig{
-		Addrs: []string{"0.0.0.0:6379"},
+		Addrs:              []string{"0.0.0.0:6379"},
+		TriggerHistorySize: 10,
+		DeletedTriggersTTL: time.Hour * 24,
+		AuditLogTTL:        time.Hour * 24,
+		MetricTimelineTTL:  time.Hour * 24,
+		DeliveryLogTTL:     time.Hour * 24,
 	},
 		NotificationHistoryConfig{
 			NotificationHistoryTTL:        time.Hour * 48,
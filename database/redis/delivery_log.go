@@ -0,0 +1,162 @@
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database/redis/reply"
+)
+
+const (
+	deliveryLogKey       = "moira-delivery-log"
+	deadLettersKey       = "moira-dead-letters"
+	deadLetterKeyPrefix  = "moira-dead-letter:"
+	deliveryLogBatchSize = 1000
+)
+
+// SaveDeliveryAttempt saves delivery attempt and deletes attempts older than delivery log TTL.
+// Attempts are not saved if delivery log is disabled.
+func (connector *DbConnector) SaveDeliveryAttempt(attempt *moira.DeliveryAttempt) error {
+	if connector.deliveryLogTTL <= 0 {
+		return nil
+	}
+
+	bytes, err := json.Marshal(attempt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delivery attempt: %w", err)
+	}
+
+	to := connector.clock.Now().Add(-connector.deliveryLogTTL).Unix()
+
+	pipe := (*connector.client).TxPipeline()
+	pipe.ZAdd(connector.context, deliveryLogKey, &redis.Z{Score: float64(attempt.Timestamp), Member: bytes})
+	pipe.ZRemRangeByScore(connector.context, deliveryLogKey, "-inf", "("+strconv.FormatInt(to, 10))
+	if _, err = pipe.Exec(connector.context); err != nil {
+		return fmt.Errorf("failed to save delivery attempt: %w", err)
+	}
+	return nil
+}
+
+// GetDeliveryAttempts returns delivery attempts matched by filter, the newest attempts go first.
+func (connector *DbConnector) GetDeliveryAttempts(filter moira.DeliveryAttemptsFilter) ([]*moira.DeliveryAttempt, error) {
+	c := *connector.client
+
+	rangeBy := &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   "+inf",
+		Count: deliveryLogBatchSize,
+	}
+	if filter.From > 0 {
+		rangeBy.Min = strconv.FormatInt(filter.From, 10)
+	}
+	if filter.To > 0 {
+		rangeBy.Max = strconv.FormatInt(filter.To, 10)
+	}
+
+	attempts := make([]*moira.DeliveryAttempt, 0)
+	for {
+		values, err := c.ZRevRangeByScore(connector.context, deliveryLogKey, rangeBy).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get delivery attempts: %w", err)
+		}
+
+		for _, value := range values {
+			attempt := &moira.DeliveryAttempt{}
+			if err = json.Unmarshal([]byte(value), attempt); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal delivery attempt %s: %w", value, err)
+			}
+			if !filter.IsMatched(attempt) {
+				continue
+			}
+
+			attempts = append(attempts, attempt)
+			if filter.Limit > 0 && int64(len(attempts)) >= filter.Limit {
+				return attempts, nil
+			}
+		}
+
+		if len(values) < deliveryLogBatchSize {
+			return attempts, nil
+		}
+		rangeBy.Offset += deliveryLogBatchSize
+	}
+}
+
+// SaveDeadLetter saves notification package notifier stopped trying to deliver.
+// Letters older than dead letters TTL are deleted, 0 TTL means that letters are kept until they are replayed or removed.
+func (connector *DbConnector) SaveDeadLetter(letter *moira.DeadLetter) error {
+	bytes, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	pipe := (*connector.client).TxPipeline()
+	pipe.Set(connector.context, deadLetterKey(letter.ID), bytes, connector.deadLettersTTL)
+	pipe.ZAdd(connector.context, deadLettersKey, &redis.Z{Score: float64(letter.Timestamp), Member: letter.ID})
+	if connector.deadLettersTTL > 0 {
+		to := connector.clock.Now().Add(-connector.deadLettersTTL).Unix()
+		pipe.ZRemRangeByScore(connector.context, deadLettersKey, "-inf", "("+strconv.FormatInt(to, 10))
+	}
+	if _, err = pipe.Exec(connector.context); err != nil {
+		return fmt.Errorf("failed to save dead letter: %w", err)
+	}
+	return nil
+}
+
+// GetDeadLetter returns dead letter by given id, if no value, return database.ErrNil error.
+func (connector *DbConnector) GetDeadLetter(id string) (moira.DeadLetter, error) {
+	c := *connector.client
+
+	return reply.DeadLetter(c.Get(connector.context, deadLetterKey(id)))
+}
+
+// GetDeadLetters returns dead letters saved in given time range, the newest letters go first. Zero from and to are not used.
+func (connector *DbConnector) GetDeadLetters(from, to int64) ([]*moira.DeadLetter, error) {
+	c := *connector.client
+
+	rangeBy := &redis.ZRangeBy{
+		Min: "-inf",
+		Max: "+inf",
+	}
+	if from > 0 {
+		rangeBy.Min = strconv.FormatInt(from, 10)
+	}
+	if to > 0 {
+		rangeBy.Max = strconv.FormatInt(to, 10)
+	}
+
+	ids, err := c.ZRevRangeByScore(connector.context, deadLettersKey, rangeBy).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead letters: %w", err)
+	}
+
+	results := make([]*redis.StringCmd, 0, len(ids))
+	pipe := c.TxPipeline()
+	for _, id := range ids {
+		results = append(results, pipe.Get(connector.context, deadLetterKey(id)))
+	}
+	if _, err = pipe.Exec(connector.context); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to get dead letters: %w", err)
+	}
+
+	return reply.DeadLetters(results)
+}
+
+// RemoveDeadLetter deletes dead letter by given id.
+func (connector *DbConnector) RemoveDeadLetter(id string) error {
+	pipe := (*connector.client).TxPipeline()
+	pipe.Del(connector.context, deadLetterKey(id))
+	pipe.ZRem(connector.context, deadLettersKey, id)
+	if _, err := pipe.Exec(connector.context); err != nil {
+		return fmt.Errorf("failed to remove dead letter: %w", err)
+	}
+	return nil
+}
+
+func deadLetterKey(id string) string {
+	return deadLetterKeyPrefix + id
+}
@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	mock_clock "github.com/moira-alert/moira/mock/clock"
+)
+
+func TestDeliveryLogStoring(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	clock := mock_clock.NewMockClock(mockCtrl)
+	dataBase.clock = clock
+	now := time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC)
+	clock.EXPECT().Now().Return(now).AnyTimes()
+
+	Convey("Delivery log manipulation", t, func() {
+		dataBase.Flush()
+
+		first := &moira.DeliveryAttempt{
+			Timestamp: now.Add(-time.Hour).Unix(),
+			ContactID: "contact-1",
+			TriggerID: "trigger-1",
+			Status:    moira.DeliveryStatusOK,
+		}
+		second := &moira.DeliveryAttempt{
+			Timestamp: now.Unix(),
+			ContactID: "contact-2",
+			TriggerID: "trigger-1",
+			Status:    moira.DeliveryStatusFailed,
+			Error:     "timeout",
+		}
+		So(dataBase.SaveDeliveryAttempt(first), ShouldBeNil)
+		So(dataBase.SaveDeliveryAttempt(second), ShouldBeNil)
+
+		Convey("Newest attempts go first", func() {
+			attempts, err := dataBase.GetDeliveryAttempts(moira.DeliveryAttemptsFilter{})
+			So(err, ShouldBeNil)
+			So(attempts, ShouldResemble, []*moira.DeliveryAttempt{second, first})
+		})
+
+		Convey("Attempts are filtered", func() {
+			attempts, err := dataBase.GetDeliveryAttempts(moira.DeliveryAttemptsFilter{ContactID: "contact-1"})
+			So(err, ShouldBeNil)
+			So(attempts, ShouldResemble, []*moira.DeliveryAttempt{first})
+
+			attempts, err = dataBase.GetDeliveryAttempts(moira.DeliveryAttemptsFilter{Status: moira.DeliveryStatusFailed})
+			So(err, ShouldBeNil)
+			So(attempts, ShouldResemble, []*moira.DeliveryAttempt{second})
+
+			attempts, err = dataBase.GetDeliveryAttempts(moira.DeliveryAttemptsFilter{To: now.Add(-time.Minute).Unix()})
+			So(err, ShouldBeNil)
+			So(attempts, ShouldResemble, []*moira.DeliveryAttempt{first})
+
+			attempts, err = dataBase.GetDeliveryAttempts(moira.DeliveryAttemptsFilter{Limit: 1})
+			So(err, ShouldBeNil)
+			So(attempts, ShouldResemble, []*moira.DeliveryAttempt{second})
+		})
+
+		Convey("Outdated attempts are removed", func() {
+			outdated := &moira.DeliveryAttempt{
+				Timestamp: now.Add(-dataBase.deliveryLogTTL - time.Hour).Unix(),
+				ContactID: "contact-1",
+			}
+			So(dataBase.SaveDeliveryAttempt(outdated), ShouldBeNil)
+
+			attempts, err := dataBase.GetDeliveryAttempts(moira.DeliveryAttemptsFilter{})
+			So(err, ShouldBeNil)
+			So(attempts, ShouldResemble, []*moira.DeliveryAttempt{second, first})
+		})
+	})
+}
+
+func TestDeadLettersStoring(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	Convey("Dead letters manipulation", t, func() {
+		dataBase.Flush()
+
+		first := &moira.DeadLetter{
+			ID:        "letter-1",
+			Timestamp: 100,
+			Reason:    "contact is broken",
+			Events:    []moira.NotificationEvent{{Metric: "metric", TriggerID: "trigger-1", State: moira.StateERROR}},
+			Trigger:   moira.TriggerData{ID: "trigger-1", Tags: []string{}},
+			Contact:   moira.ContactData{ID: "contact-1", Type: "mail", Value: "mail@example.com"},
+			FailCount: 3,
+		}
+		second := &moira.DeadLetter{
+			ID:        "letter-2",
+			Timestamp: 200,
+			Reason:    "too many attempts",
+			Events:    []moira.NotificationEvent{{Metric: "metric", TriggerID: "trigger-2", State: moira.StateOK}},
+			Trigger:   moira.TriggerData{ID: "trigger-2", Tags: []string{}},
+			Contact:   moira.ContactData{ID: "contact-1", Type: "mail", Value: "mail@example.com"},
+		}
+
+		Convey("Get unknown letter", func() {
+			_, err := dataBase.GetDeadLetter(first.ID)
+			So(err, ShouldResemble, database.ErrNil)
+
+			letters, err := dataBase.GetDeadLetters(0, 0)
+			So(err, ShouldBeNil)
+			So(letters, ShouldBeEmpty)
+		})
+
+		Convey("Save, get and remove letters", func() {
+			So(dataBase.SaveDeadLetter(first), ShouldBeNil)
+			So(dataBase.SaveDeadLetter(second), ShouldBeNil)
+
+			letter, err := dataBase.GetDeadLetter(first.ID)
+			So(err, ShouldBeNil)
+			So(letter, ShouldResemble, *first)
+
+			letters, err := dataBase.GetDeadLetters(0, 0)
+			So(err, ShouldBeNil)
+			So(letters, ShouldResemble, []*moira.DeadLetter{second, first})
+
+			letters, err = dataBase.GetDeadLetters(150, 0)
+			So(err, ShouldBeNil)
+			So(letters, ShouldResemble, []*moira.DeadLetter{second})
+
+			So(dataBase.RemoveDeadLetter(second.ID), ShouldBeNil)
+
+			_, err = dataBase.GetDeadLetter(second.ID)
+			So(err, ShouldResemble, database.ErrNil)
+
+			letters, err = dataBase.GetDeadLetters(0, 0)
+			So(err, ShouldBeNil)
+			So(letters, ShouldResemble, []*moira.DeadLetter{first})
+		})
+	})
+}
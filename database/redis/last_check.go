@@ -172,6 +172,8 @@ func (connector *DbConnector) SetTriggerCheckMaintenance(triggerID string, metri
 		return fmt.Errorf("failed to parse lastCheck json %s: %s", lastCheckString, err.Error())
 	}
 	metricsCheck := lastCheck.Metrics
+	// Maintenance changes are kept in metric state timeline, the latest set maintenance is the actual one
+	timelinePoints := make(map[string][]moira.MetricStatePoint)
 	if len(metricsCheck) > 0 {
 		for metric, value := range metrics {
 			data, ok := metricsCheck[metric]
@@ -180,17 +182,41 @@ func (connector *DbConnector) SetTriggerCheckMaintenance(triggerID string, metri
 			}
 			moira.SetMaintenanceUserAndTime(&data, value, userLogin, timeCallMaintenance)
 			metricsCheck[metric] = data
+			timelinePoints[metric] = []moira.MetricStatePoint{newMaintenancePoint(data, value, timeCallMaintenance)}
 		}
 	}
 	if triggerMaintenance != nil {
 		moira.SetMaintenanceUserAndTime(&lastCheck, *triggerMaintenance, userLogin, timeCallMaintenance)
+		for metric, data := range metricsCheck {
+			if _, ok := timelinePoints[metric]; !ok {
+				timelinePoints[metric] = []moira.MetricStatePoint{newMaintenancePoint(data, *triggerMaintenance, timeCallMaintenance)}
+			}
+		}
 	}
 	newLastCheck, err := json.Marshal(lastCheck)
 	if err != nil {
 		return err
 	}
 
-	return c.Set(ctx, metricLastCheckKey(triggerID), newLastCheck, redis.KeepTTL).Err()
+	pipe := c.TxPipeline()
+	pipe.Set(ctx, metricLastCheckKey(triggerID), newLastCheck, redis.KeepTTL)
+	if connector.metricTimelineTTL > 0 {
+		if err = appendPushMetricStatesTimelineToRedisPipeline(connector, pipe, triggerID, timelinePoints); err != nil {
+			return err
+		}
+	}
+
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func newMaintenancePoint(metricState moira.MetricState, maintenance int64, timestamp int64) moira.MetricStatePoint {
+	return moira.MetricStatePoint{
+		Timestamp:   timestamp,
+		State:       metricState.State,
+		Suppressed:  metricState.Suppressed,
+		Maintenance: maintenance,
+	}
 }
 
 // checkDataScoreChanged returns true if checkData.Score changed since last check.
@@ -0,0 +1,91 @@
+package redis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/moira-alert/moira"
+)
+
+// PushMetricStatesTimeline saves changes of metrics states and deletes points older than metric timeline TTL.
+// Timeline is not kept if TTL is not set.
+func (connector *DbConnector) PushMetricStatesTimeline(triggerID string, points map[string][]moira.MetricStatePoint) error {
+	if connector.metricTimelineTTL <= 0 || len(points) == 0 {
+		return nil
+	}
+
+	ctx := connector.context
+	pipe := (*connector.client).TxPipeline()
+	if err := appendPushMetricStatesTimelineToRedisPipeline(connector, pipe, triggerID, points); err != nil {
+		return err
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to push metric states timeline: %w", err)
+	}
+	return nil
+}
+
+// GetMetricStateTimeline returns metric state changes within [from, to] sorted by time,
+// the last change before from goes first if it exists because it defines state at the beginning of time range.
+func (connector *DbConnector) GetMetricStateTimeline(triggerID string, metric string, from int64, to int64) ([]*moira.MetricStatePoint, error) {
+	ctx := connector.context
+	key := metricStateTimelineKey(triggerID, metric)
+
+	pipe := (*connector.client).TxPipeline()
+	previous := pipe.ZRevRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   "(" + strconv.FormatInt(from, 10),
+		Count: 1,
+	})
+	inRange := pipe.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(from, 10),
+		Max: strconv.FormatInt(to, 10),
+	})
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to get metric state timeline: %w", err)
+	}
+
+	values := append(previous.Val(), inRange.Val()...)
+	points := make([]*moira.MetricStatePoint, 0, len(values))
+	for _, value := range values {
+		point := &moira.MetricStatePoint{}
+		if err := json.Unmarshal([]byte(value), point); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metric state point %s: %w", value, err)
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+func appendPushMetricStatesTimelineToRedisPipeline(connector *DbConnector, pipe redis.Pipeliner, triggerID string, points map[string][]moira.MetricStatePoint) error {
+	ctx := connector.context
+	expired := strconv.FormatInt(connector.clock.Now().Add(-connector.metricTimelineTTL).Unix(), 10)
+
+	for metric, metricPoints := range points {
+		if len(metricPoints) == 0 {
+			continue
+		}
+
+		key := metricStateTimelineKey(triggerID, metric)
+		members := make([]*redis.Z, 0, len(metricPoints))
+		for _, point := range metricPoints {
+			bytes, err := json.Marshal(point)
+			if err != nil {
+				return fmt.Errorf("failed to marshal metric state point: %w", err)
+			}
+			members = append(members, &redis.Z{Score: float64(point.Timestamp), Member: bytes})
+		}
+		pipe.ZAdd(ctx, key, members...)
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "("+expired)
+		pipe.Expire(ctx, key, connector.metricTimelineTTL)
+	}
+	return nil
+}
+
+func metricStateTimelineKey(triggerID, metric string) string {
+	return "moira-metric-state-timeline:" + triggerID + ":" + metric
+}
@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	mock_clock "github.com/moira-alert/moira/mock/clock"
+)
+
+func TestMetricStateTimeline(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	clock := mock_clock.NewMockClock(mockCtrl)
+	dataBase.clock = clock
+	now := time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC)
+	clock.EXPECT().Now().Return(now).AnyTimes()
+
+	const triggerID = "trigger-id"
+	const metric = "my.metric"
+
+	Convey("Metric state timeline manipulation", t, func() {
+		dataBase.Flush()
+
+		expired := moira.MetricStatePoint{Timestamp: now.Add(-25 * time.Hour).Unix(), State: moira.StateOK}
+		first := moira.MetricStatePoint{Timestamp: now.Add(-2 * time.Hour).Unix(), State: moira.StateOK}
+		second := moira.MetricStatePoint{Timestamp: now.Add(-time.Hour).Unix(), State: moira.StateERROR}
+		third := moira.MetricStatePoint{Timestamp: now.Unix(), State: moira.StateERROR, Suppressed: true, Maintenance: now.Add(time.Hour).Unix()}
+
+		err := dataBase.PushMetricStatesTimeline(triggerID, map[string][]moira.MetricStatePoint{
+			metric:         {expired, first, second},
+			"other.metric": {third},
+		})
+		So(err, ShouldBeNil)
+		So(dataBase.PushMetricStatesTimeline(triggerID, map[string][]moira.MetricStatePoint{metric: {third}}), ShouldBeNil)
+
+		Convey("Points older than TTL are removed", func() {
+			points, err := dataBase.GetMetricStateTimeline(triggerID, metric, 0, now.Unix())
+			So(err, ShouldBeNil)
+			So(points, ShouldResemble, []*moira.MetricStatePoint{&first, &second, &third})
+		})
+
+		Convey("The last point before time range is returned", func() {
+			points, err := dataBase.GetMetricStateTimeline(triggerID, metric, now.Add(-90*time.Minute).Unix(), now.Add(-time.Minute).Unix())
+			So(err, ShouldBeNil)
+			So(points, ShouldResemble, []*moira.MetricStatePoint{&first, &second})
+		})
+
+		Convey("Unknown metric has empty timeline", func() {
+			points, err := dataBase.GetMetricStateTimeline(triggerID, "unknown.metric", 0, now.Unix())
+			So(err, ShouldBeNil)
+			So(points, ShouldBeEmpty)
+		})
+
+		Convey("Maintenance is added to timeline", func() {
+			checkData := moira.CheckData{
+				Metrics: map[string]moira.MetricState{
+					metric: {State: moira.StateERROR, Timestamp: now.Unix()},
+				},
+				State: moira.StateOK,
+			}
+			So(dataBase.SetTriggerLastCheck(triggerID, &checkData, moira.DefaultLocalCluster), ShouldBeNil)
+
+			maintenance := now.Add(2 * time.Hour).Unix()
+			callTime := now.Add(time.Minute).Unix()
+			err := dataBase.SetTriggerCheckMaintenance(triggerID, map[string]int64{metric: maintenance}, nil, "user", callTime)
+			So(err, ShouldBeNil)
+
+			points, err := dataBase.GetMetricStateTimeline(triggerID, metric, now.Unix(), callTime)
+			So(err, ShouldBeNil)
+			So(points, ShouldResemble, []*moira.MetricStatePoint{
+				&second,
+				&third,
+				{Timestamp: callTime, State: moira.StateERROR, Maintenance: maintenance},
+			})
+		})
+	})
+
+	Convey("Timeline is not kept without TTL", t, func() {
+		dataBase.Flush()
+		dataBase.metricTimelineTTL = 0
+		defer func() { dataBase.metricTimelineTTL = time.Hour * 24 }()
+
+		err := dataBase.PushMetricStatesTimeline(triggerID, map[string][]moira.MetricStatePoint{
+			metric: {{Timestamp: now.Unix(), State: moira.StateOK}},
+		})
+		So(err, ShouldBeNil)
+
+		points, err := dataBase.GetMetricStateTimeline(triggerID, metric, 0, now.Unix())
+		So(err, ShouldBeNil)
+		So(points, ShouldBeEmpty)
+	})
+}
@@ -30,7 +30,27 @@ func (connector *DbConnector) GetNotificationEvents(triggerID string, start int6
 	return eventsData, nil
 }
 
+// GetNotificationEventsByTime gets NotificationEvents of given triggerID created in given time range, the oldest events go first.
+func (connector *DbConnector) GetNotificationEventsByTime(triggerID string, from, to int64) ([]*moira.NotificationEvent, error) {
+	ctx := connector.context
+	c := *connector.client
+
+	eventsData, err := reply.Events(c.ZRangeByScore(ctx, triggerEventsKey(triggerID), &redis.ZRangeBy{
+		Min: strconv.FormatInt(from, 10),
+		Max: strconv.FormatInt(to, 10),
+	}))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return make([]*moira.NotificationEvent, 0), nil
+		}
+		return nil, fmt.Errorf("failed to get range by score for trigger events, triggerID: %s, error: %w", triggerID, err)
+	}
+
+	return eventsData, nil
+}
+
 // PushNotificationEvent adds new NotificationEvent to events list and to given triggerID events list and deletes events who are older than 30 days.
+// Events of triggers are also added to events stream that is read by API clients.
 // If ui=true, then add to ui events list.
 func (connector *DbConnector) PushNotificationEvent(event *moira.NotificationEvent, ui bool) error {
 	eventBytes, err := reply.GetEventBytes(*event)
@@ -47,6 +67,12 @@ func (connector *DbConnector) PushNotificationEvent(event *moira.NotificationEve
 
 		pipe.ZAdd(ctx, triggerEventsKey(event.TriggerID), z)
 		pipe.ZRemRangeByScore(ctx, triggerEventsKey(event.TriggerID), "-inf", strconv.Itoa(to))
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: notificationEventsStream,
+			MaxLen: notificationEventsStreamMaxLength,
+			Approx: true,
+			Values: reply.EventsStreamValues(eventBytes),
+		})
 	}
 
 	if ui {
@@ -114,9 +140,52 @@ func (connector *DbConnector) RemoveAllNotificationEvents() error {
 	return nil
 }
 
+// GetNotificationEventsStreamLastID returns ID of the last event in events stream, "0-0" is returned for empty stream.
+func (connector *DbConnector) GetNotificationEventsStreamLastID() (string, error) {
+	ctx := connector.context
+	c := *connector.client
+
+	messages, err := c.XRevRangeN(ctx, notificationEventsStream, "+", "-", 1).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to get last event of events stream: %s", err.Error())
+	}
+	if len(messages) == 0 {
+		return notificationEventsStreamStartID, nil
+	}
+
+	return messages[0].ID, nil
+}
+
+// GetNotificationEventsStream returns up to count events added to events stream after event with given ID.
+// Events which can not be decoded are logged and skipped, lastID is ID of the last read event including skipped ones,
+// so reading can be continued after them. It is empty if there are no events after given ID.
+func (connector *DbConnector) GetNotificationEventsStream(afterID string, count int64) ([]moira.NotificationEventsStreamEntry, string, error) {
+	ctx := connector.context
+	c := *connector.client
+
+	entries, lastID, skipped, err := reply.EventsStream(c.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{notificationEventsStream, afterID},
+		Count:   count,
+		Block:   -1,
+	}))
+	for id, err := range skipped {
+		connector.logger.Error().
+			String("event_id", id).
+			Error(err).
+			Msg("Failed to decode event of events stream, event is skipped")
+	}
+	return entries, lastID, err
+}
+
 var (
 	notificationEventsList   = "moira-trigger-events"
 	notificationEventsUIList = "moira-trigger-events-ui"
+	notificationEventsStream = "moira-trigger-events-stream"
+)
+
+const (
+	notificationEventsStreamMaxLength = 10000
+	notificationEventsStreamStartID   = "0-0"
 )
 
 func triggerEventsKey(triggerID string) string {
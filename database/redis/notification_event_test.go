@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
 	. "github.com/smartystreets/goconvey/convey"
 
@@ -296,6 +297,89 @@ func TestNotificationEvents(t *testing.T) {
 	})
 }
 
+func TestNotificationEventsStream(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	Convey("Notification events stream", t, func() {
+		lastID, err := dataBase.GetNotificationEventsStreamLastID()
+		So(err, ShouldBeNil)
+		So(lastID, ShouldEqual, "0-0")
+
+		entries, readID, err := dataBase.GetNotificationEventsStream(lastID, 10)
+		So(err, ShouldBeNil)
+		So(entries, ShouldBeEmpty)
+		So(readID, ShouldBeEmpty)
+
+		event1 := moira.NotificationEvent{Timestamp: now, State: moira.StateERROR, OldState: moira.StateOK, TriggerID: triggerID1, Metric: "my.metric", Values: map[string]float64{"t1": 1}}
+		event2 := moira.NotificationEvent{Timestamp: now, State: moira.StateOK, OldState: moira.StateERROR, TriggerID: triggerID2, IsTriggerEvent: true, Values: map[string]float64{}}
+		So(dataBase.PushNotificationEvent(&event1, false), ShouldBeNil)
+		So(dataBase.PushNotificationEvent(&moira.NotificationEvent{Timestamp: now, State: moira.StateTEST}, false), ShouldBeNil)
+		So(dataBase.PushNotificationEvent(&event2, false), ShouldBeNil)
+
+		entries, readID, err = dataBase.GetNotificationEventsStream(lastID, 10)
+		So(err, ShouldBeNil)
+		So(entries, ShouldHaveLength, 2)
+		So(entries[0].Event, ShouldResemble, event1)
+		So(entries[1].Event, ShouldResemble, event2)
+		So(readID, ShouldEqual, entries[1].ID)
+
+		lastID, err = dataBase.GetNotificationEventsStreamLastID()
+		So(err, ShouldBeNil)
+		So(lastID, ShouldEqual, entries[1].ID)
+
+		entries, _, err = dataBase.GetNotificationEventsStream(entries[0].ID, 10)
+		So(err, ShouldBeNil)
+		So(entries, ShouldHaveLength, 1)
+		So(entries[0].Event, ShouldResemble, event2)
+
+		entries, readID, err = dataBase.GetNotificationEventsStream(lastID, 10)
+		So(err, ShouldBeNil)
+		So(entries, ShouldBeEmpty)
+		So(readID, ShouldBeEmpty)
+
+		Convey("Undecodable event is skipped", func() {
+			client := *dataBase.client
+			invalidID, err := client.XAdd(dataBase.context, &redis.XAddArgs{
+				Stream: notificationEventsStream,
+				Values: map[string]interface{}{"event": "{invalid"},
+			}).Result()
+			So(err, ShouldBeNil)
+
+			entries, readID, err = dataBase.GetNotificationEventsStream(lastID, 10)
+			So(err, ShouldBeNil)
+			So(entries, ShouldBeEmpty)
+			So(readID, ShouldEqual, invalidID)
+		})
+	})
+}
+
+func TestNotificationEventsByTime(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	Convey("Notification events by time", t, func() {
+		event1 := moira.NotificationEvent{Timestamp: now - 20, State: moira.StateERROR, OldState: moira.StateOK, TriggerID: triggerID1, Metric: "my.metric", Values: map[string]float64{}}
+		event2 := moira.NotificationEvent{Timestamp: now - 10, State: moira.StateOK, OldState: moira.StateERROR, TriggerID: triggerID1, Metric: "my.metric", Values: map[string]float64{}}
+		event3 := moira.NotificationEvent{Timestamp: now, State: moira.StateWARN, OldState: moira.StateOK, TriggerID: triggerID1, Metric: "my.metric", Values: map[string]float64{}}
+		So(dataBase.PushNotificationEvent(&event3, false), ShouldBeNil)
+		So(dataBase.PushNotificationEvent(&event1, false), ShouldBeNil)
+		So(dataBase.PushNotificationEvent(&event2, false), ShouldBeNil)
+
+		events, err := dataBase.GetNotificationEventsByTime(triggerID1, now-20, now-5)
+		So(err, ShouldBeNil)
+		So(events, ShouldResemble, []*moira.NotificationEvent{&event1, &event2})
+
+		events, err = dataBase.GetNotificationEventsByTime(triggerID2, now-20, now)
+		So(err, ShouldBeNil)
+		So(events, ShouldBeEmpty)
+	})
+}
+
 func TestNotificationEventErrorConnection(t *testing.T) {
 	logger, _ := logging.GetLogger("dataBase")
 	dataBase := NewTestDatabaseWithIncorrectConfig(logger)
@@ -0,0 +1,46 @@
+package reply
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+func unmarshalAPIToken(bytes []byte, err error) (moira.APIToken, error) {
+	token := moira.APIToken{}
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return token, database.ErrNil
+		}
+		return token, fmt.Errorf("failed to read api token: %w", err)
+	}
+
+	if err = json.Unmarshal(bytes, &token); err != nil {
+		return token, fmt.Errorf("failed to parse api token json %s: %w", string(bytes), err)
+	}
+	return token, nil
+}
+
+// APIToken converts redis DB reply to moira.APIToken object.
+func APIToken(rep *redis.StringCmd) (moira.APIToken, error) {
+	return unmarshalAPIToken(rep.Bytes())
+}
+
+// APITokens converts redis DB reply to moira.APIToken objects array, missing tokens are returned as nil.
+func APITokens(rep []*redis.StringCmd) ([]*moira.APIToken, error) {
+	tokens := make([]*moira.APIToken, len(rep))
+	for i, value := range rep {
+		token, err := unmarshalAPIToken(value.Bytes())
+		if err != nil && !errors.Is(err, database.ErrNil) {
+			return nil, err
+		}
+		if err == nil {
+			tokens[i] = &token
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,47 @@
+package reply
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+func unmarshalDeadLetter(bytes []byte, err error) (moira.DeadLetter, error) {
+	letter := moira.DeadLetter{}
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return letter, database.ErrNil
+		}
+		return letter, fmt.Errorf("failed to read dead letter: %w", err)
+	}
+
+	if err = json.Unmarshal(bytes, &letter); err != nil {
+		return letter, fmt.Errorf("failed to parse dead letter json %s: %w", string(bytes), err)
+	}
+	return letter, nil
+}
+
+// DeadLetter converts redis DB reply to moira.DeadLetter object.
+func DeadLetter(rep *redis.StringCmd) (moira.DeadLetter, error) {
+	return unmarshalDeadLetter(rep.Bytes())
+}
+
+// DeadLetters converts redis DB reply to moira.DeadLetter objects array, missing letters are skipped.
+func DeadLetters(rep []*redis.StringCmd) ([]*moira.DeadLetter, error) {
+	letters := make([]*moira.DeadLetter, 0, len(rep))
+	for _, value := range rep {
+		letter, err := unmarshalDeadLetter(value.Bytes())
+		if err != nil {
+			if errors.Is(err, database.ErrNil) {
+				continue
+			}
+			return nil, err
+		}
+		letters = append(letters, &letter)
+	}
+	return letters, nil
+}
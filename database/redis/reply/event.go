@@ -133,3 +133,39 @@ func Events(response *redis.StringSliceCmd) ([]*moira.NotificationEvent, error)
 	}
 	return events, nil
 }
+
+// EventsStream converts redis DB reply of events stream to moira.NotificationEventsStreamEntry objects array.
+// Entries which can not be decoded are skipped and their errors are returned by entry ID,
+// lastID is ID of the last read entry including skipped ones, it is empty if no entries are read.
+func EventsStream(response *redis.XStreamSliceCmd) (entries []moira.NotificationEventsStreamEntry, lastID string, skipped map[string]error, err error) {
+	entries = make([]moira.NotificationEventsStreamEntry, 0)
+	skipped = make(map[string]error)
+	streams, err := response.Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return entries, "", skipped, nil
+		}
+		return nil, "", nil, fmt.Errorf("failed to read events stream: %s", err.Error())
+	}
+
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			lastID = message.ID
+			data, _ := message.Values[eventsStreamField].(string)
+			event, err := unmarshalEvent(data, nil)
+			if err != nil {
+				skipped[message.ID] = err
+				continue
+			}
+			entries = append(entries, moira.NotificationEventsStreamEntry{ID: message.ID, Event: event})
+		}
+	}
+	return entries, lastID, skipped, nil
+}
+
+// EventsStreamValues returns values of events stream entry that holds given event bytes.
+func EventsStreamValues(eventBytes []byte) map[string]interface{} {
+	return map[string]interface{}{eventsStreamField: eventBytes}
+}
+
+const eventsStreamField = "event"
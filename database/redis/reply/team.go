@@ -56,3 +56,23 @@ func NewTeam(rep *redis.StringCmd) (moira.Team, error) {
 	}
 	return teamSE.toTeam(), nil
 }
+
+// NewTeams is a function that creates a list of teams from a hash of teams received from database.
+func NewTeams(rep *redis.StringStringMapCmd) ([]moira.Team, error) {
+	values, err := rep.Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read teams: %w", err)
+	}
+	teams := make([]moira.Team, 0, len(values))
+	for teamID, value := range values {
+		teamSE := teamStorageElement{}
+		err = json.Unmarshal([]byte(value), &teamSE)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse team json %s: %w", value, err)
+		}
+		team := teamSE.toTeam()
+		team.ID = teamID
+		teams = append(teams, team)
+	}
+	return teams, nil
+}
@@ -36,6 +36,7 @@ type triggerStorageElement struct {
 	UpdatedAt        *int64              `json:"updated_at"`
 	CreatedBy        string              `json:"created_by"`
 	UpdatedBy        string              `json:"updated_by"`
+	TeamID           string              `json:"team_id,omitempty"`
 }
 
 func (storageElement *triggerStorageElement) toTrigger() moira.Trigger {
@@ -75,6 +76,7 @@ func (storageElement *triggerStorageElement) toTrigger() moira.Trigger {
 		UpdatedAt:        storageElement.UpdatedAt,
 		CreatedBy:        storageElement.CreatedBy,
 		UpdatedBy:        storageElement.UpdatedBy,
+		TeamID:           storageElement.TeamID,
 	}
 }
 
@@ -103,6 +105,7 @@ func toTriggerStorageElement(trigger *moira.Trigger, triggerID string) *triggerS
 		UpdatedAt:        trigger.UpdatedAt,
 		CreatedBy:        trigger.CreatedBy,
 		UpdatedBy:        trigger.UpdatedBy,
+		TeamID:           trigger.TeamID,
 	}
 }
 
@@ -0,0 +1,64 @@
+package reply
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/moira-alert/moira"
+)
+
+// triggerHistoryItemStorageElement is a representation of trigger history item in database.
+type triggerHistoryItemStorageElement struct {
+	Version   int64                      `json:"version"`
+	Action    moira.TriggerHistoryAction `json:"action"`
+	Timestamp int64                      `json:"timestamp"`
+	User      string                     `json:"user"`
+	Trigger   *triggerStorageElement     `json:"trigger"`
+}
+
+// MarshalTriggerHistoryItem converts trigger history item to the bytes that can be held in database.
+func MarshalTriggerHistoryItem(item moira.TriggerHistoryItem) ([]byte, error) {
+	itemSE := triggerHistoryItemStorageElement{
+		Version:   item.Version,
+		Action:    item.Action,
+		Timestamp: item.Timestamp,
+		User:      item.User,
+		Trigger:   toTriggerStorageElement(&item.Trigger, item.Trigger.ID),
+	}
+	bytes, err := json.Marshal(itemSE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal trigger history item: %w", err)
+	}
+	return bytes, nil
+}
+
+// TriggerHistoryItems converts raw list of trigger history items received from database to moira.TriggerHistoryItem objects.
+func TriggerHistoryItems(values []string) ([]*moira.TriggerHistoryItem, error) {
+	items := make([]*moira.TriggerHistoryItem, 0, len(values))
+	for _, value := range values {
+		item, err := unmarshalTriggerHistoryItem([]byte(value))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	return items, nil
+}
+
+func unmarshalTriggerHistoryItem(bytes []byte) (moira.TriggerHistoryItem, error) {
+	itemSE := triggerHistoryItemStorageElement{}
+	if err := json.Unmarshal(bytes, &itemSE); err != nil {
+		return moira.TriggerHistoryItem{}, fmt.Errorf("failed to parse trigger history item json %s: %w", string(bytes), err)
+	}
+
+	item := moira.TriggerHistoryItem{
+		Version:   itemSE.Version,
+		Action:    itemSE.Action,
+		Timestamp: itemSE.Timestamp,
+		User:      itemSE.User,
+	}
+	if itemSE.Trigger != nil {
+		item.Trigger = itemSE.Trigger.toTrigger()
+	}
+	return item, nil
+}
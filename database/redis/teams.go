@@ -2,6 +2,10 @@ package redis
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/go-redis/redis/v8"
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/database/redis/reply"
@@ -37,12 +41,39 @@ func (connector *DbConnector) GetTeam(teamID string) (moira.Team, error) {
 	return team, nil
 }
 
+// GetAllTeams returns all teams stored in database.
+func (connector *DbConnector) GetAllTeams() ([]moira.Team, error) {
+	c := *connector.client
+
+	response := c.HGetAll(connector.context, teamsKey)
+	teams, err := reply.NewTeams(response)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].ID < teams[j].ID
+	})
+
+	return teams, nil
+}
+
 // SaveTeamsAndUsers is a function that saves users for one team and teams for bunch of users in one transaction.
 func (connector *DbConnector) SaveTeamsAndUsers(teamID string, users []string, teams map[string][]string) error {
 	c := *connector.client
 
+	roleUsers, err := c.HKeys(connector.context, teamUserRolesKey(teamID)).Result()
+	if err != nil {
+		return fmt.Errorf("cannot get user roles for team: %s, %w", teamID, err)
+	}
+
 	pipe := c.TxPipeline()
-	err := pipe.Del(connector.context, teamUsersKey(teamID)).Err()
+	for _, userID := range moira.GetStringListsDiff(roleUsers, users) {
+		err = pipe.HDel(connector.context, teamUserRolesKey(teamID), userID).Err()
+		if err != nil {
+			return fmt.Errorf("cannot remove role of deleted user for team: %s, %w", teamID, err)
+		}
+	}
+	err = pipe.Del(connector.context, teamUsersKey(teamID)).Err()
 	if err != nil {
 		return fmt.Errorf("cannot clear users set for team: %s, %w", teamID, err)
 	}
@@ -122,6 +153,11 @@ func (connector *DbConnector) DeleteTeam(teamID, userID string) error {
 		return fmt.Errorf("failed to remove team users: %w", err)
 	}
 
+	err = pipe.Del(connector.context, teamUserRolesKey(teamID)).Err()
+	if err != nil {
+		return fmt.Errorf("failed to remove team user roles: %w", err)
+	}
+
 	err = pipe.HDel(connector.context, teamsKey, teamID).Err()
 	if err != nil {
 		return fmt.Errorf("failed to remove team metadata: %w", err)
@@ -135,8 +171,112 @@ func (connector *DbConnector) DeleteTeam(teamID, userID string) error {
 	return nil
 }
 
+// GetTeamUserRoles returns stored roles of team members.
+func (connector *DbConnector) GetTeamUserRoles(teamID string) (map[string]moira.TeamRole, error) {
+	c := *connector.client
+
+	storedRoles, err := c.HGetAll(connector.context, teamUserRolesKey(teamID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve team user roles: %w", err)
+	}
+
+	roles := make(map[string]moira.TeamRole, len(storedRoles))
+	for userID, role := range storedRoles {
+		roles[userID] = moira.TeamRole(role)
+	}
+	return roles, nil
+}
+
+// SaveTeamUserRoles replaces stored roles of team members with given ones.
+func (connector *DbConnector) SaveTeamUserRoles(teamID string, roles map[string]moira.TeamRole) error {
+	c := *connector.client
+
+	pipe := c.TxPipeline()
+	pipe.Del(connector.context, teamUserRolesKey(teamID))
+	for userID, role := range roles {
+		pipe.HSet(connector.context, teamUserRolesKey(teamID), userID, string(role))
+	}
+
+	if _, err := pipe.Exec(connector.context); err != nil {
+		return fmt.Errorf("cannot commit transaction and save team user roles: %w", err)
+	}
+	return nil
+}
+
+// GetTeamTriggerIDs returns ids of triggers owned by team.
+func (connector *DbConnector) GetTeamTriggerIDs(teamID string) ([]string, error) {
+	c := *connector.client
+
+	triggerIDs, err := c.SMembers(connector.context, teamTriggersKey(teamID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve team triggers: %w", err)
+	}
+	return triggerIDs, nil
+}
+
+// SyncUserGroupTeams makes user a member of given teams mapped from groups of identity provider
+// and removes user from teams which were mapped from groups of user before but are not mapped anymore.
+// Teams which do not exist are skipped, membership in teams user was added to by team owners is not changed.
+// Nothing is changed if teams are the same as the last time, so team owners can remove user from mapped team until user groups change.
+// The whole change is made in one transaction, so concurrent changes of team members are not lost.
+func (connector *DbConnector) SyncUserGroupTeams(userID string, teamIDs []string) error {
+	ctx := connector.context
+	c := *connector.client
+	groupTeamsKey := userGroupTeamsKey(userID)
+
+	err := c.Watch(ctx, func(tx *redis.Tx) error {
+		previousTeamIDs, err := tx.SMembers(ctx, groupTeamsKey).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get user group teams: %w", err)
+		}
+
+		existingTeamIDs := make([]string, 0, len(teamIDs))
+		for _, teamID := range teamIDs {
+			exists, err := tx.HExists(ctx, teamsKey, teamID).Result()
+			if err != nil {
+				return fmt.Errorf("failed to check team %s: %w", teamID, err)
+			}
+			if exists {
+				existingTeamIDs = append(existingTeamIDs, teamID)
+			}
+		}
+
+		if len(previousTeamIDs) == len(existingTeamIDs) && moira.Subset(existingTeamIDs, previousTeamIDs) {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, teamID := range previousTeamIDs {
+				if slices.Contains(existingTeamIDs, teamID) {
+					continue
+				}
+				pipe.SRem(ctx, teamUsersKey(teamID), userID)
+				pipe.SRem(ctx, userTeamsKey(userID), teamID)
+				pipe.HDel(ctx, teamUserRolesKey(teamID), userID)
+			}
+
+			pipe.Del(ctx, groupTeamsKey)
+			for _, teamID := range existingTeamIDs {
+				pipe.SAdd(ctx, teamUsersKey(teamID), userID)
+				pipe.SAdd(ctx, userTeamsKey(userID), teamID)
+				pipe.SAdd(ctx, groupTeamsKey, teamID)
+			}
+			return nil
+		})
+		return err
+	}, groupTeamsKey)
+	if err != nil {
+		return fmt.Errorf("failed to sync user group teams: %w", err)
+	}
+	return nil
+}
+
 const teamsKey = "moira-teams"
 
+func userGroupTeamsKey(userID string) string {
+	return fmt.Sprintf("moira-userGroupTeams:%s", userID)
+}
+
 func userTeamsKey(userID string) string {
 	return fmt.Sprintf("moira-userTeams:%s", userID)
 }
@@ -144,3 +284,11 @@ func userTeamsKey(userID string) string {
 func teamUsersKey(teamID string) string {
 	return fmt.Sprintf("moira-teamUsers:%s", teamID)
 }
+
+func teamUserRolesKey(teamID string) string {
+	return fmt.Sprintf("moira-teamUserRoles:%s", teamID)
+}
+
+func teamTriggersKey(teamID string) string {
+	return fmt.Sprintf("moira-teamTriggers:%s", teamID)
+}
@@ -40,6 +40,10 @@ func TestTeamStoring(t *testing.T) {
 		So(err, ShouldResemble, database.ErrNil)
 		So(actualTeam, ShouldResemble, moira.Team{})
 
+		actualAllTeams, err := dataBase.GetAllTeams()
+		So(err, ShouldBeNil)
+		So(actualAllTeams, ShouldResemble, []moira.Team{team})
+
 		// Add two users for team 1
 		err = dataBase.SaveTeamsAndUsers(
 			teamID,
@@ -147,3 +151,168 @@ func TestTeamStoring(t *testing.T) {
 		So(actualUsers, ShouldHaveLength, 0)
 	})
 }
+
+func TestTeamUserRolesStoring(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping database test in short mode")
+	}
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	const teamID = "testTeam"
+	const userID = "userID"
+	const userID2 = "userID2"
+
+	Convey("Team user roles manipulation", t, func() {
+		roles, err := dataBase.GetTeamUserRoles(teamID)
+		So(err, ShouldBeNil)
+		So(roles, ShouldBeEmpty)
+
+		err = dataBase.SaveTeamUserRoles(teamID, map[string]moira.TeamRole{
+			userID:  moira.TeamRoleOwner,
+			userID2: moira.TeamRoleViewer,
+		})
+		So(err, ShouldBeNil)
+
+		roles, err = dataBase.GetTeamUserRoles(teamID)
+		So(err, ShouldBeNil)
+		So(roles, ShouldResemble, map[string]moira.TeamRole{
+			userID:  moira.TeamRoleOwner,
+			userID2: moira.TeamRoleViewer,
+		})
+
+		// Roles of removed users are removed with them
+		err = dataBase.SaveTeamsAndUsers(teamID, []string{userID}, map[string][]string{userID: {teamID}, userID2: {}})
+		So(err, ShouldBeNil)
+
+		roles, err = dataBase.GetTeamUserRoles(teamID)
+		So(err, ShouldBeNil)
+		So(roles, ShouldResemble, map[string]moira.TeamRole{userID: moira.TeamRoleOwner})
+
+		err = dataBase.DeleteTeam(teamID, userID)
+		So(err, ShouldBeNil)
+
+		roles, err = dataBase.GetTeamUserRoles(teamID)
+		So(err, ShouldBeNil)
+		So(roles, ShouldBeEmpty)
+	})
+}
+
+func TestTeamTriggersStoring(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping database test in short mode")
+	}
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	const teamID = "testTeam"
+	const teamID2 = "testTeam2"
+
+	Convey("Team triggers manipulation", t, func() {
+		trigger := moira.Trigger{
+			ID:            "triggerID",
+			Name:          "team trigger",
+			Targets:       []string{"my.metric"},
+			Patterns:      []string{"my.metric"},
+			TriggerSource: moira.GraphiteLocal,
+			ClusterId:     moira.DefaultCluster,
+			TeamID:        teamID,
+		}
+		err := dataBase.SaveTrigger(trigger.ID, &trigger)
+		So(err, ShouldBeNil)
+
+		triggerIDs, err := dataBase.GetTeamTriggerIDs(teamID)
+		So(err, ShouldBeNil)
+		So(triggerIDs, ShouldResemble, []string{trigger.ID})
+
+		actual, err := dataBase.GetTrigger(trigger.ID)
+		So(err, ShouldBeNil)
+		So(actual.TeamID, ShouldEqual, teamID)
+
+		// Trigger is moved to another team
+		trigger.TeamID = teamID2
+		err = dataBase.SaveTrigger(trigger.ID, &trigger)
+		So(err, ShouldBeNil)
+
+		triggerIDs, err = dataBase.GetTeamTriggerIDs(teamID)
+		So(err, ShouldBeNil)
+		So(triggerIDs, ShouldBeEmpty)
+
+		triggerIDs, err = dataBase.GetTeamTriggerIDs(teamID2)
+		So(err, ShouldBeNil)
+		So(triggerIDs, ShouldResemble, []string{trigger.ID})
+
+		err = dataBase.RemoveTrigger(trigger.ID, "")
+		So(err, ShouldBeNil)
+
+		triggerIDs, err = dataBase.GetTeamTriggerIDs(teamID2)
+		So(err, ShouldBeNil)
+		So(triggerIDs, ShouldBeEmpty)
+	})
+}
+
+func TestSyncUserGroupTeams(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping database test in short mode")
+	}
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	const userID = "userID"
+
+	Convey("User group teams synchronization", t, func() {
+		for _, teamID := range []string{"team1", "team2", "manual"} {
+			err := dataBase.SaveTeam(teamID, moira.Team{Name: teamID})
+			So(err, ShouldBeNil)
+		}
+		err := dataBase.SaveTeamsAndUsers("manual", []string{userID}, map[string][]string{userID: {"manual"}})
+		So(err, ShouldBeNil)
+
+		err = dataBase.SyncUserGroupTeams(userID, []string{"team1", "team2", "unknown"})
+		So(err, ShouldBeNil)
+
+		userTeams, err := dataBase.GetUserTeams(userID)
+		So(err, ShouldBeNil)
+		So(userTeams, ShouldHaveLength, 3)
+		So(userTeams, ShouldContain, "team1")
+		So(userTeams, ShouldContain, "team2")
+		So(userTeams, ShouldContain, "manual")
+
+		isMember, err := dataBase.IsTeamContainUser("unknown", userID)
+		So(err, ShouldBeNil)
+		So(isMember, ShouldBeFalse)
+
+		Convey("User removed from mapped team is not added again while groups are the same", func() {
+			err = dataBase.SaveTeamsAndUsers("team1", []string{}, map[string][]string{userID: {"team2", "manual"}})
+			So(err, ShouldBeNil)
+
+			err = dataBase.SyncUserGroupTeams(userID, []string{"team1", "team2", "unknown"})
+			So(err, ShouldBeNil)
+
+			isMember, err = dataBase.IsTeamContainUser("team1", userID)
+			So(err, ShouldBeNil)
+			So(isMember, ShouldBeFalse)
+		})
+
+		Convey("User is removed from teams which are not mapped from groups anymore", func() {
+			err = dataBase.SyncUserGroupTeams(userID, []string{"team2"})
+			So(err, ShouldBeNil)
+
+			userTeams, err = dataBase.GetUserTeams(userID)
+			So(err, ShouldBeNil)
+			So(userTeams, ShouldHaveLength, 2)
+			So(userTeams, ShouldContain, "team2")
+			So(userTeams, ShouldContain, "manual")
+
+			isMember, err = dataBase.IsTeamContainUser("team1", userID)
+			So(err, ShouldBeNil)
+			So(isMember, ShouldBeFalse)
+		})
+	})
+}
@@ -156,6 +156,14 @@ func (connector *DbConnector) SaveTrigger(triggerID string, trigger *moira.Trigg
 		return fmt.Errorf("failed to update trigger: %w", err)
 	}
 
+	action := moira.TriggerHistoryActionUpdate
+	if oldTrigger == nil {
+		action = moira.TriggerHistoryActionCreate
+	}
+	if err = connector.saveTriggerHistoryItem(triggerID, *trigger, action, trigger.UpdatedBy); err != nil {
+		return fmt.Errorf("failed to save trigger history: %w", err)
+	}
+
 	hasSubscriptions, err := connector.triggerHasSubscriptions(trigger)
 	if err != nil {
 		return fmt.Errorf("failed to check trigger subscriptions: %s", err.Error())
@@ -218,6 +226,10 @@ func (connector *DbConnector) updateTrigger(triggerID string, newTrigger *moira.
 			}
 			pipe.SRem(connector.context, oldTriggersListKey, triggerID)
 		}
+
+		if oldTrigger.TeamID != "" && oldTrigger.TeamID != newTrigger.TeamID {
+			pipe.SRem(connector.context, teamTriggersKey(oldTrigger.TeamID), triggerID)
+		}
 	}
 	pipe.Set(connector.context, triggerKey(triggerID), bytes, redis.KeepTTL)
 	pipe.SAdd(connector.context, allTriggersListKey, triggerID)
@@ -239,6 +251,9 @@ func (connector *DbConnector) updateTrigger(triggerID string, newTrigger *moira.
 		pipe.SAdd(connector.context, tagTriggersKey(tag), triggerID)
 		pipe.SAdd(connector.context, tagsKey, tag)
 	}
+	if newTrigger.TeamID != "" {
+		pipe.SAdd(connector.context, teamTriggersKey(newTrigger.TeamID), triggerID)
+	}
 	if connector.source != Cli {
 		z := &redis.Z{Score: float64(time.Now().Unix()), Member: triggerID}
 		pipe.ZAdd(connector.context, triggersToReindexKey, z)
@@ -268,7 +283,8 @@ func (connector *DbConnector) preSaveTrigger(newTrigger *moira.Trigger, oldTrigg
 // RemoveTrigger deletes trigger data by given triggerID, delete trigger tag list,
 // deletes triggerID from containing tags triggers list and from containing patterns triggers list.
 // If containing patterns doesn't used in another triggers, then delete this patterns with metrics data.
-func (connector *DbConnector) RemoveTrigger(triggerID string) error {
+// Deletion is saved to trigger history on behalf of given user.
+func (connector *DbConnector) RemoveTrigger(triggerID, userLogin string) error {
 	trigger, err := connector.GetTrigger(triggerID)
 	if err != nil {
 		if errors.Is(err, database.ErrNil) {
@@ -281,6 +297,10 @@ func (connector *DbConnector) RemoveTrigger(triggerID string) error {
 		return fmt.Errorf("failed to EXEC: %s", err.Error())
 	}
 
+	if err = connector.saveTriggerHistoryItem(triggerID, trigger, moira.TriggerHistoryActionDelete, userLogin); err != nil {
+		return fmt.Errorf("failed to save trigger history: %w", err)
+	}
+
 	return connector.cleanupPatternsOutOfUse(trigger.Patterns)
 }
 
@@ -304,6 +324,9 @@ func (connector *DbConnector) removeTrigger(triggerID string, trigger *moira.Tri
 	for _, pattern := range trigger.Patterns {
 		pipe.SRem(connector.context, patternTriggersKey(pattern), triggerID)
 	}
+	if trigger.TeamID != "" {
+		pipe.SRem(connector.context, teamTriggersKey(trigger.TeamID), triggerID)
+	}
 	z := &redis.Z{Score: float64(time.Now().Unix()), Member: triggerID}
 	pipe.ZAdd(connector.context, triggersToReindexKey, z)
 
@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/database/redis/reply"
+)
+
+// GetTriggerHistory returns saved versions of trigger by given triggerID, the newest versions go first.
+func (connector *DbConnector) GetTriggerHistory(triggerID string) ([]*moira.TriggerHistoryItem, error) {
+	c := *connector.client
+
+	values, err := c.LRange(connector.context, triggerHistoryKey(triggerID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trigger history: %w", err)
+	}
+
+	return reply.TriggerHistoryItems(values)
+}
+
+// GetTriggerHistoryItem returns trigger history item with given version.
+// If there is no such version in trigger history, then database.ErrNil is returned.
+func (connector *DbConnector) GetTriggerHistoryItem(triggerID string, version int64) (moira.TriggerHistoryItem, error) {
+	items, err := connector.GetTriggerHistory(triggerID)
+	if err != nil {
+		return moira.TriggerHistoryItem{}, err
+	}
+
+	for _, item := range items {
+		if item.Version == version {
+			return *item, nil
+		}
+	}
+
+	return moira.TriggerHistoryItem{}, database.ErrNil
+}
+
+// GetDeletedTriggers returns the last history items of triggers which were deleted during the deleted triggers TTL.
+// Information about triggers deleted earlier is cleaned up.
+func (connector *DbConnector) GetDeletedTriggers() ([]*moira.TriggerHistoryItem, error) {
+	c := *connector.client
+
+	from := connector.clock.Now().Add(-connector.deletedTriggersTTL).Unix()
+	err := c.ZRemRangeByScore(connector.context, deletedTriggersKey, "-inf", "("+strconv.FormatInt(from, 10)).Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clean up deleted triggers: %w", err)
+	}
+
+	triggerIDs, err := c.ZRevRange(connector.context, deletedTriggersKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted triggers: %w", err)
+	}
+
+	pipe := c.TxPipeline()
+	for _, triggerID := range triggerIDs {
+		pipe.LIndex(connector.context, triggerHistoryKey(triggerID), 0)
+	}
+	rawResponse, err := pipe.Exec(connector.context)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to EXEC: %w", err)
+	}
+
+	values := make([]string, 0, len(rawResponse))
+	for _, cmd := range rawResponse {
+		value, err := cmd.(*redis.StringCmd).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get deleted trigger: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	return reply.TriggerHistoryItems(values)
+}
+
+// saveTriggerHistoryItem stores snapshot of trigger as the next version of trigger history.
+// Only the last triggerHistorySize versions are kept. History of deleted trigger expires after deletedTriggersTTL.
+func (connector *DbConnector) saveTriggerHistoryItem(triggerID string, trigger moira.Trigger, action moira.TriggerHistoryAction, user string) error {
+	if connector.triggerHistorySize <= 0 {
+		return nil
+	}
+
+	c := *connector.client
+
+	version, err := c.Incr(connector.context, triggerHistoryVersionKey(triggerID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get next trigger history version: %w", err)
+	}
+
+	now := connector.clock.Now()
+	trigger.ID = triggerID
+	bytes, err := reply.MarshalTriggerHistoryItem(moira.TriggerHistoryItem{
+		Version:   version,
+		Action:    action,
+		Timestamp: now.Unix(),
+		User:      user,
+		Trigger:   trigger,
+	})
+	if err != nil {
+		return err
+	}
+
+	pipe := c.TxPipeline()
+	pipe.LPush(connector.context, triggerHistoryKey(triggerID), bytes)
+	pipe.LTrim(connector.context, triggerHistoryKey(triggerID), 0, int64(connector.triggerHistorySize-1))
+
+	if action == moira.TriggerHistoryActionDelete {
+		if connector.deletedTriggersTTL > 0 {
+			pipe.Expire(connector.context, triggerHistoryKey(triggerID), connector.deletedTriggersTTL)
+			pipe.Expire(connector.context, triggerHistoryVersionKey(triggerID), connector.deletedTriggersTTL)
+			pipe.ZAdd(connector.context, deletedTriggersKey, &redis.Z{Score: float64(now.Unix()), Member: triggerID})
+		} else {
+			pipe.Del(connector.context, triggerHistoryKey(triggerID))
+			pipe.Del(connector.context, triggerHistoryVersionKey(triggerID))
+		}
+	} else {
+		pipe.Persist(connector.context, triggerHistoryKey(triggerID))
+		pipe.Persist(connector.context, triggerHistoryVersionKey(triggerID))
+		pipe.ZRem(connector.context, deletedTriggersKey, triggerID)
+	}
+
+	if _, err = pipe.Exec(connector.context); err != nil {
+		return fmt.Errorf("failed to EXEC: %w", err)
+	}
+	return nil
+}
+
+const deletedTriggersKey = "moira-deleted-triggers"
+
+func triggerHistoryKey(triggerID string) string {
+	return "moira-trigger-history:" + triggerID
+}
+
+func triggerHistoryVersionKey(triggerID string) string {
+	return "moira-trigger-history-version:" + triggerID
+}
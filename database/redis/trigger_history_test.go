@@ -0,0 +1,135 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	mock_clock "github.com/moira-alert/moira/mock/clock"
+)
+
+func TestTriggerHistoryStoring(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabase(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	clock := mock_clock.NewMockClock(mockCtrl)
+	dataBase.clock = clock
+	now := time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC)
+	clock.EXPECT().Now().Return(now).AnyTimes()
+
+	Convey("Trigger history manipulation", t, func() {
+		trigger := testTriggers[0]
+		trigger.UpdatedBy = user1
+
+		Convey("Empty history for unknown trigger", func() {
+			history, err := dataBase.GetTriggerHistory(trigger.ID)
+			So(err, ShouldBeNil)
+			So(history, ShouldBeEmpty)
+
+			_, err = dataBase.GetTriggerHistoryItem(trigger.ID, 1)
+			So(err, ShouldResemble, database.ErrNil)
+		})
+
+		Convey("Save, update and remove trigger", func() {
+			err := dataBase.SaveTrigger(trigger.ID, &trigger)
+			So(err, ShouldBeNil)
+
+			updatedTrigger := trigger
+			updatedTrigger.Name = "updated name"
+			updatedTrigger.UpdatedBy = user2
+			err = dataBase.SaveTrigger(trigger.ID, &updatedTrigger)
+			So(err, ShouldBeNil)
+
+			history, err := dataBase.GetTriggerHistory(trigger.ID)
+			So(err, ShouldBeNil)
+			So(history, ShouldHaveLength, 2)
+			So(history[0].Version, ShouldEqual, 2)
+			So(history[0].Action, ShouldEqual, moira.TriggerHistoryActionUpdate)
+			So(history[0].User, ShouldEqual, user2)
+			So(history[0].Timestamp, ShouldEqual, now.Unix())
+			So(history[0].Trigger.Name, ShouldEqual, "updated name")
+			So(history[1].Version, ShouldEqual, 1)
+			So(history[1].Action, ShouldEqual, moira.TriggerHistoryActionCreate)
+			So(history[1].User, ShouldEqual, user1)
+			So(history[1].Trigger.Name, ShouldEqual, trigger.Name)
+
+			item, err := dataBase.GetTriggerHistoryItem(trigger.ID, 1)
+			So(err, ShouldBeNil)
+			So(item, ShouldResemble, *history[1])
+
+			deleted, err := dataBase.GetDeletedTriggers()
+			So(err, ShouldBeNil)
+			So(deleted, ShouldBeEmpty)
+
+			err = dataBase.RemoveTrigger(trigger.ID, user1)
+			So(err, ShouldBeNil)
+
+			deleted, err = dataBase.GetDeletedTriggers()
+			So(err, ShouldBeNil)
+			So(deleted, ShouldHaveLength, 1)
+			So(deleted[0].Version, ShouldEqual, 3)
+			So(deleted[0].Action, ShouldEqual, moira.TriggerHistoryActionDelete)
+			So(deleted[0].User, ShouldEqual, user1)
+			So(deleted[0].Trigger.ID, ShouldEqual, trigger.ID)
+			So(dataBase.getTTL(triggerHistoryKey(trigger.ID)), ShouldBeGreaterThan, 0)
+
+			Convey("Restored trigger is not listed as deleted", func() {
+				err = dataBase.SaveTrigger(trigger.ID, &trigger)
+				So(err, ShouldBeNil)
+
+				deleted, err = dataBase.GetDeletedTriggers()
+				So(err, ShouldBeNil)
+				So(deleted, ShouldBeEmpty)
+				So(dataBase.getTTL(triggerHistoryKey(trigger.ID)), ShouldEqual, time.Duration(-1))
+
+				history, err = dataBase.GetTriggerHistory(trigger.ID)
+				So(err, ShouldBeNil)
+				So(history, ShouldHaveLength, 4)
+				So(history[0].Action, ShouldEqual, moira.TriggerHistoryActionUpdate)
+			})
+		})
+
+		Convey("History is trimmed to configured size", func() {
+			dataBase.Flush()
+			for i := 0; i < dataBase.triggerHistorySize+5; i++ {
+				err := dataBase.SaveTrigger(trigger.ID, &trigger)
+				So(err, ShouldBeNil)
+			}
+
+			history, err := dataBase.GetTriggerHistory(trigger.ID)
+			So(err, ShouldBeNil)
+			So(history, ShouldHaveLength, dataBase.triggerHistorySize)
+			So(history[0].Version, ShouldEqual, dataBase.triggerHistorySize+5)
+		})
+	})
+}
+
+func TestTriggerHistoryErrorConnection(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewTestDatabaseWithIncorrectConfig(logger)
+	dataBase.Flush()
+	defer dataBase.Flush()
+
+	Convey("Should throw error when no connection", t, func() {
+		history, err := dataBase.GetTriggerHistory("")
+		So(err, ShouldNotBeNil)
+		So(history, ShouldBeNil)
+
+		_, err = dataBase.GetTriggerHistoryItem("", 1)
+		So(err, ShouldNotBeNil)
+
+		deleted, err := dataBase.GetDeletedTriggers()
+		So(err, ShouldNotBeNil)
+		So(deleted, ShouldBeNil)
+	})
+}
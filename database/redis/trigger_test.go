@@ -59,7 +59,7 @@ func TestTriggerStoring(t *testing.T) {
 			So(err, ShouldResemble, database.ErrNil)
 			So(actual, ShouldResemble, moira.Trigger{})
 
-			err = dataBase.RemoveTrigger(trigger.ID)
+			err = dataBase.RemoveTrigger(trigger.ID, "")
 			So(err, ShouldBeNil)
 
 			// Now write it
@@ -184,7 +184,7 @@ func TestTriggerStoring(t *testing.T) {
 			So(actualTags, ShouldHaveLength, 2)
 
 			// Stop it!! Remove trigger and check for no existing it by pointers
-			err = dataBase.RemoveTrigger(changedAgainTrigger.ID)
+			err = dataBase.RemoveTrigger(changedAgainTrigger.ID, "")
 			So(err, ShouldBeNil)
 
 			// And check for existing by several pointers like id or tag
@@ -285,7 +285,7 @@ func TestTriggerStoring(t *testing.T) {
 			So(actualTriggerChecks, ShouldResemble, []*moira.TriggerCheck{triggerCheck})
 
 			// Can not remove check data, but can remove trigger!
-			err = dataBase.RemoveTrigger(trigger.ID)
+			err = dataBase.RemoveTrigger(trigger.ID, "")
 			So(err, ShouldBeNil)
 
 			actualTriggerChecks, err = dataBase.GetTriggerChecks([]string{trigger.ID})
@@ -447,7 +447,7 @@ func TestTriggerStoring(t *testing.T) {
 			So(actualPatternMetrics, ShouldResemble, []string{metric2})
 
 			// It's time to remove trigger and check all data
-			err = dataBase.RemoveTrigger(triggerVer2.ID)
+			err = dataBase.RemoveTrigger(triggerVer2.ID, "")
 			So(err, ShouldBeNil)
 
 			actual, err = dataBase.GetTrigger(triggerVer2.ID)
@@ -523,7 +523,7 @@ func TestTriggerStoring(t *testing.T) {
 			So(actual, ShouldBeEmpty)
 
 			// Remove trigger
-			err = dataBase.RemoveTrigger(trigger.ID)
+			err = dataBase.RemoveTrigger(trigger.ID, "")
 			So(err, ShouldBeNil)
 
 			actual, err = dataBase.FetchTriggersToReindex(time.Now().Unix() - 1)
@@ -711,7 +711,7 @@ func TestTriggerErrorConnection(t *testing.T) {
 		err = dataBase.SaveTrigger("", &testTriggers[0])
 		So(err, ShouldNotBeNil)
 
-		err = dataBase.RemoveTrigger("")
+		err = dataBase.RemoveTrigger("", "")
 		So(err, ShouldNotBeNil)
 
 		actual4, err := dataBase.GetPatternTriggerIDs("")
@@ -32,6 +32,13 @@ const (
 	DefaultTimeFormat = "15:04"
 	remindMessage     = "This metric has been in bad state for more than %v hours - please, fix."
 	limit             = 1000
+	// plotFormatDetectionLen is the length of plot prefix where SVG tag is looked for.
+	plotFormatDetectionLen = 512
+	// minPlotSize and maxPlotSize are limits of plot width and height in pixels.
+	minPlotSize = 200
+	maxPlotSize = 4000
+	// maxPlotTimeRange is the longest time range of plot in seconds, it is one week.
+	maxPlotTimeRange = 7 * 24 * 60 * 60
 )
 
 type NotificationEventSettings int
@@ -57,6 +64,21 @@ type NotificationEvent struct {
 	MessageEventInfo *EventInfo         `json:"event_message" extensions:"x-nullable"`
 }
 
+// NotificationPayload is a notification rendered by sender the way it is delivered to contact.
+type NotificationPayload struct {
+	// ContentType describes format of Body, e.g. text/markdown for chats or application/json for http apis.
+	ContentType string `json:"content_type" example:"text/markdown"`
+	// Title is a part of notification delivered separately from body, e.g. subject of mail, title of card or url of webhook.
+	Title string `json:"title,omitempty" example:"ERROR Not enough disk space left [server]"`
+	Body  string `json:"body" example:"ERROR Not enough disk space left [server] (1)"`
+}
+
+// NotificationEventsStreamEntry is a notification event with its ID in events stream, ID is used as a cursor to continue reading stream.
+type NotificationEventsStreamEntry struct {
+	ID    string
+	Event NotificationEvent
+}
+
 // NotificationEventHistoryItem is in use to store notifications history of channel.
 // (See database/redis/contact_notifications_history.go.
 type NotificationEventHistoryItem struct {
@@ -159,6 +181,25 @@ func (events NotificationEvents) ToTemplateEvents() []templating.Event {
 	return templateEvents
 }
 
+// ToTemplateMessageEvents converts a slice of NotificationEvent into a slice of templating.MessageEvent,
+// event time is formatted in the given location.
+func (events NotificationEvents) ToTemplateMessageEvents(location *time.Location, timeFormat string) []templating.MessageEvent {
+	templateEvents := events.ToTemplateEvents()
+	messageEvents := make([]templating.MessageEvent, 0, len(events))
+	for i, event := range events {
+		messageEvents = append(messageEvents, templating.MessageEvent{
+			Event:        templateEvents[i],
+			OldState:     string(event.OldState),
+			Values:       event.Values,
+			ValuesString: event.GetMetricsValues(DefaultNotificationSettings),
+			Time:         event.FormatTimestamp(location, timeFormat),
+			Message:      event.CreateMessage(location),
+		})
+	}
+
+	return messageEvents
+}
+
 // TriggerData represents trigger object.
 type TriggerData struct {
 	ID            string        `json:"id" example:"292516ed-4924-4154-a62c-ebe312431fce"`
@@ -186,6 +227,19 @@ func (trigger TriggerData) GetTriggerURI(frontURI string) string {
 	return ""
 }
 
+// ToTemplateMessageTrigger converts a TriggerData into a template MessageTrigger.
+func (trigger TriggerData) ToTemplateMessageTrigger(frontURI string) templating.MessageTrigger {
+	return templating.MessageTrigger{
+		ID:          trigger.ID,
+		Name:        trigger.Name,
+		Description: trigger.Desc,
+		Tags:        trigger.Tags,
+		URI:         trigger.GetTriggerURI(frontURI),
+		WarnValue:   trigger.WarnValue,
+		ErrorValue:  trigger.ErrorValue,
+	}
+}
+
 // Team is a structure that represents a group of users that share a subscriptions and contacts.
 type Team struct {
 	ID          string
@@ -193,6 +247,48 @@ type Team struct {
 	Description string
 }
 
+// TeamRole represents the rights of team member.
+type TeamRole string
+
+const (
+	// TeamRoleOwner can do anything with the team: change it, manage its members and api tokens.
+	TeamRoleOwner TeamRole = "owner"
+	// TeamRoleEditor can change contacts, subscriptions and triggers of the team.
+	TeamRoleEditor TeamRole = "editor"
+	// TeamRoleViewer can only read team settings and triggers.
+	TeamRoleViewer TeamRole = "viewer"
+)
+
+var teamRoleLevels = map[TeamRole]int{
+	TeamRoleViewer: 1,
+	TeamRoleEditor: 2,
+	TeamRoleOwner:  3,
+}
+
+// IsValid returns true if role is one of known team roles.
+func (role TeamRole) IsValid() bool {
+	_, ok := teamRoleLevels[role]
+	return ok
+}
+
+// Includes returns true if role grants all rights of required role.
+func (role TeamRole) Includes(required TeamRole) bool {
+	return role.IsValid() && teamRoleLevels[role] >= teamRoleLevels[required]
+}
+
+// GetTeamUserRole returns role of team member by roles stored for the team. Members of teams
+// which have no stored roles, e.g. teams created before roles were introduced, are owners,
+// other members without stored role are editors.
+func GetTeamUserRole(storedRoles map[string]TeamRole, userID string) TeamRole {
+	if role, ok := storedRoles[userID]; ok {
+		return role
+	}
+	if len(storedRoles) == 0 {
+		return TeamRoleOwner
+	}
+	return TeamRoleEditor
+}
+
 // ContactData represents contact object.
 type ContactData struct {
 	Type  string `json:"type" example:"mail"`
@@ -201,6 +297,12 @@ type ContactData struct {
 	ID    string `json:"id" example:"1dd38765-c5be-418d-81fa-7a5f879c2315"`
 	User  string `json:"user" example:""`
 	Team  string `json:"team"`
+	// MessageTemplate is a Go template of notification message, it overrides message template of sender.
+	MessageTemplate string `json:"message_template,omitempty"`
+	// SigningSecret is a key senders use to sign requests, e.g. HMAC of webhook body.
+	SigningSecret string `json:"signing_secret,omitempty"`
+	// Availability describes periods owner of contact should not be disturbed in.
+	Availability *ContactAvailability `json:"availability,omitempty"`
 }
 
 // ToTemplateContact converts a ContactData into a template Contact.
@@ -231,6 +333,61 @@ type SubscriptionData struct {
 type PlottingData struct {
 	Enabled bool   `json:"enabled" example:"true"`
 	Theme   string `json:"theme" example:"dark"`
+	// TimeRange is the time range of plot in seconds before the last event, 0 means default time range.
+	TimeRange int64 `json:"time_range,omitempty" example:"3600" format:"int64"`
+	// Width and Height are the size of plot in pixels, 0 means default size.
+	Width  int        `json:"width,omitempty" example:"800"`
+	Height int        `json:"height,omitempty" example:"400"`
+	Format PlotFormat `json:"format,omitempty" example:"png"`
+	// Overlay draws all targets of trigger on one plot instead of plot per target.
+	Overlay bool `json:"overlay,omitempty" example:"false"`
+}
+
+// Validate checks that plot size, format and time range are within limits, zero values mean defaults.
+func (plotting PlottingData) Validate() error {
+	for _, size := range []int{plotting.Width, plotting.Height} {
+		if size != 0 && (size < minPlotSize || size > maxPlotSize) {
+			return fmt.Errorf("plot width and height must be between %d and %d pixels", minPlotSize, maxPlotSize)
+		}
+	}
+	if plotting.TimeRange < 0 || plotting.TimeRange > maxPlotTimeRange {
+		return fmt.Errorf("plot time range must be between 0 and %d seconds", maxPlotTimeRange)
+	}
+	switch plotting.Format {
+	case "", PlotFormatPNG, PlotFormatSVG:
+		return nil
+	default:
+		return fmt.Errorf("unknown plot format '%s', use %s or %s", plotting.Format, PlotFormatPNG, PlotFormatSVG)
+	}
+}
+
+// PlotFormat is the image format plots are rendered to.
+type PlotFormat string
+
+// Supported plot formats.
+const (
+	PlotFormatPNG PlotFormat = "png"
+	PlotFormatSVG PlotFormat = "svg"
+)
+
+// GetPlotFormat returns format of rendered plot, plots are considered to be PNG unless they are SVG documents.
+func GetPlotFormat(plot []byte) PlotFormat {
+	head := plot
+	if len(head) > plotFormatDetectionLen {
+		head = head[:plotFormatDetectionLen]
+	}
+	if bytes.Contains(head, []byte("<svg")) {
+		return PlotFormatSVG
+	}
+	return PlotFormatPNG
+}
+
+// ContentType returns MIME type of plots of given format.
+func (format PlotFormat) ContentType() string {
+	if format == PlotFormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
 }
 
 // ScheduleData represents subscription schedule.
@@ -353,6 +510,7 @@ type Trigger struct {
 	UpdatedAt        *int64          `json:"updated_at" format:"int64" extensions:"x-nullable"`
 	CreatedBy        string          `json:"created_by"`
 	UpdatedBy        string          `json:"updated_by"`
+	TeamID           string          `json:"team_id,omitempty"`
 }
 
 // ClusterKey returns cluster key composed of trigger source and cluster id associated with the trigger.
@@ -360,6 +518,142 @@ func (trigger *Trigger) ClusterKey() ClusterKey {
 	return MakeClusterKey(trigger.TriggerSource, trigger.ClusterId)
 }
 
+// TriggerHistoryAction represents the kind of trigger change stored in trigger history.
+type TriggerHistoryAction string
+
+const (
+	// TriggerHistoryActionCreate is used when trigger was created.
+	TriggerHistoryActionCreate TriggerHistoryAction = "create"
+	// TriggerHistoryActionUpdate is used when existing or deleted trigger was saved.
+	TriggerHistoryActionUpdate TriggerHistoryAction = "update"
+	// TriggerHistoryActionDelete is used when trigger was removed.
+	TriggerHistoryActionDelete TriggerHistoryAction = "delete"
+)
+
+// TriggerHistoryItem represents a versioned snapshot of trigger saved on each trigger change.
+type TriggerHistoryItem struct {
+	Version   int64                `json:"version" example:"3" format:"int64"`
+	Action    TriggerHistoryAction `json:"action" example:"update"`
+	Timestamp int64                `json:"timestamp" example:"1590741878" format:"int64"`
+	User      string               `json:"user" example:"alice"`
+	Trigger   Trigger              `json:"trigger"`
+}
+
+// AuditAction represents the kind of change recorded in audit log.
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// AuditObjectType represents the type of object changed by user.
+type AuditObjectType string
+
+const (
+	AuditObjectTrigger            AuditObjectType = "trigger"
+	AuditObjectTriggerMaintenance AuditObjectType = "trigger_maintenance"
+	AuditObjectTriggerThrottling  AuditObjectType = "trigger_throttling"
+	AuditObjectTriggerMetrics     AuditObjectType = "trigger_metrics"
+	AuditObjectContact            AuditObjectType = "contact"
+	AuditObjectSubscription       AuditObjectType = "subscription"
+	AuditObjectTeam               AuditObjectType = "team"
+	AuditObjectTeamUsers          AuditObjectType = "team_users"
+	AuditObjectTag                AuditObjectType = "tag"
+	AuditObjectPattern            AuditObjectType = "pattern"
+	AuditObjectNotifierState      AuditObjectType = "notifier_state"
+	AuditObjectNotification       AuditObjectType = "notification"
+	AuditObjectEvents             AuditObjectType = "events"
+	AuditObjectAPIToken           AuditObjectType = "api_token"
+	AuditObjectDeadLetter         AuditObjectType = "dead_letter"
+)
+
+// AuditRecord represents single change of moira configuration made by user.
+type AuditRecord struct {
+	Timestamp  int64           `json:"timestamp" example:"1590741878" format:"int64"`
+	Actor      string          `json:"actor" example:"alice"`
+	Action     AuditAction     `json:"action" example:"update"`
+	ObjectType AuditObjectType `json:"object_type" example:"contact"`
+	ObjectID   string          `json:"object_id" example:"1dd38765-c5be-418d-81fa-7a5f879c2315"`
+	Token      string          `json:"token,omitempty" example:"8e4f2b7c-5a0f-4f8a-9d3e-2b1c6a7d9e0f"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object" extensions:"x-nullable"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object" extensions:"x-nullable"`
+}
+
+// AuditRecordsFilter contains conditions audit records are searched by. Empty fields are not used.
+type AuditRecordsFilter struct {
+	Actor      string
+	Action     AuditAction
+	ObjectType AuditObjectType
+	ObjectID   string
+	From       int64
+	To         int64
+	Limit      int64
+}
+
+// IsMatched returns true if audit record satisfies all conditions of the filter except time range and limit.
+func (filter *AuditRecordsFilter) IsMatched(record *AuditRecord) bool {
+	return (filter.Actor == "" || filter.Actor == record.Actor) &&
+		(filter.Action == "" || filter.Action == record.Action) &&
+		(filter.ObjectType == "" || filter.ObjectType == record.ObjectType) &&
+		(filter.ObjectID == "" || filter.ObjectID == record.ObjectID)
+}
+
+// APITokenScope is a permission granted to API token.
+type APITokenScope string
+
+const (
+	// APITokenScopeRead allows to read, it does not allow requests which change anything.
+	APITokenScopeRead APITokenScope = "read"
+	// APITokenScopeTriggers allows to read and manage triggers.
+	APITokenScopeTriggers APITokenScope = "triggers"
+	// APITokenScopeTeamPrefix followed by team ID allows to read and manage the team, e.g. "team:d5d98eb3".
+	APITokenScopeTeamPrefix = "team:"
+)
+
+// TeamScope returns scope which allows to manage given team.
+func TeamScope(teamID string) APITokenScope {
+	return APITokenScope(APITokenScopeTeamPrefix + teamID)
+}
+
+// APIToken represents personal access token of user or service account token of team used by automation.
+type APIToken struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	User       string          `json:"user,omitempty"`
+	TeamID     string          `json:"team_id,omitempty"`
+	Scopes     []APITokenScope `json:"scopes"`
+	CreatedBy  string          `json:"created_by"`
+	CreatedAt  int64           `json:"created_at"`
+	ExpiresAt  *int64          `json:"expires_at,omitempty"`
+	SecretHash string          `json:"secret_hash"`
+}
+
+// IsExpired returns true if token can not be used anymore.
+func (token *APIToken) IsExpired(now time.Time) bool {
+	return token.ExpiresAt != nil && now.Unix() >= *token.ExpiresAt
+}
+
+// HasScope returns true if token is granted with given scope.
+func (token *APIToken) HasScope(scope APITokenScope) bool {
+	for _, tokenScope := range token.Scopes {
+		if tokenScope == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLogin returns login requests made with token are performed on behalf of.
+// Personal tokens act as their owner, team tokens act as service account of the team.
+func (token *APIToken) GetLogin() string {
+	if token.User != "" {
+		return token.User
+	}
+	return fmt.Sprintf("%s%s/%s", APITokenScopeTeamPrefix, token.TeamID, token.Name)
+}
+
 // TriggerSource is a enum which values correspond to types of moira's metric sources.
 type TriggerSource string
 
@@ -465,8 +759,21 @@ type SearchOptions struct {
 	NeedSearchByCreatedBy bool
 	CreatePager           bool
 	PagerID               string
+	SortBy                SearchSortBy
 }
 
+// SearchSortBy represents the order in which found triggers are returned.
+type SearchSortBy string
+
+const (
+	// SortByScore sorts triggers by last check score (desc), relevance and name. It is used by default.
+	SortByScore SearchSortBy = "score"
+	// SortByName sorts triggers by name (asc).
+	SortByName SearchSortBy = "name"
+	// SortByLastEvent sorts triggers by last event time, the most recent first.
+	SortByLastEvent SearchSortBy = "last_event"
+)
+
 // MaintenanceCheck set maintenance user, time.
 type MaintenanceCheck interface {
 	SetMaintenance(maintenanceInfo *MaintenanceInfo, maintenance int64)
@@ -514,6 +821,21 @@ func (checkData *CheckData) RemoveMetricsToTargetRelation() {
 	checkData.MetricsToTargetRelation = make(map[string]string)
 }
 
+// GetWorstState returns the most critical state of trigger and its metrics, it is OK only if nothing is in problem state.
+func (checkData *CheckData) GetWorstState() State {
+	states := map[State]bool{checkData.State: true}
+	for _, metricState := range checkData.Metrics {
+		states[metricState.State] = true
+	}
+	result := StateOK
+	for _, state := range eventStatesPriority {
+		if states[state] {
+			result = state
+		}
+	}
+	return result
+}
+
 // IsTriggerOnMaintenance checks if the trigger is on Maintenance.
 func (checkData *CheckData) IsTriggerOnMaintenance() bool {
 	return time.Now().Unix() <= checkData.Maintenance
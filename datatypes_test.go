@@ -978,3 +978,50 @@ func TestScheduledNotificationIsDelayed(t *testing.T) {
 		})
 	})
 }
+
+func TestTeamRoleIncludes(t *testing.T) {
+	Convey("Test team role includes", t, func() {
+		So(TeamRoleOwner.Includes(TeamRoleEditor), ShouldBeTrue)
+		So(TeamRoleEditor.Includes(TeamRoleEditor), ShouldBeTrue)
+		So(TeamRoleEditor.Includes(TeamRoleOwner), ShouldBeFalse)
+		So(TeamRoleViewer.Includes(TeamRoleEditor), ShouldBeFalse)
+		So(TeamRole("unknown").Includes(TeamRoleViewer), ShouldBeFalse)
+	})
+}
+
+func TestCheckDataGetWorstState(t *testing.T) {
+	Convey("Test worst state of check", t, func() {
+		So((&CheckData{State: StateOK}).GetWorstState(), ShouldEqual, StateOK)
+		So((&CheckData{State: StateOK, Metrics: map[string]MetricState{"a": {State: StateOK}, "b": {State: StateNODATA}, "c": {State: StateERROR}}}).GetWorstState(), ShouldEqual, StateNODATA)
+		So((&CheckData{State: StateEXCEPTION, Metrics: map[string]MetricState{"a": {State: StateOK}}}).GetWorstState(), ShouldEqual, StateEXCEPTION)
+	})
+}
+
+func TestGetTeamUserRole(t *testing.T) {
+	Convey("Test team user role", t, func() {
+		roles := map[string]TeamRole{"owner": TeamRoleOwner, "viewer": TeamRoleViewer}
+		So(GetTeamUserRole(roles, "viewer"), ShouldEqual, TeamRoleViewer)
+		So(GetTeamUserRole(roles, "other"), ShouldEqual, TeamRoleEditor)
+		So(GetTeamUserRole(map[string]TeamRole{}, "other"), ShouldEqual, TeamRoleOwner)
+	})
+}
+
+func TestPlottingDataValidate(t *testing.T) {
+	Convey("Test plotting data validation", t, func() {
+		So(PlottingData{}.Validate(), ShouldBeNil)
+		So(PlottingData{Width: 800, Height: 400, Format: PlotFormatSVG, TimeRange: 3600}.Validate(), ShouldBeNil)
+		So(PlottingData{Width: 100}.Validate(), ShouldNotBeNil)
+		So(PlottingData{Height: 5000}.Validate(), ShouldNotBeNil)
+		So(PlottingData{TimeRange: -1}.Validate(), ShouldNotBeNil)
+		So(PlottingData{Format: "gif"}.Validate(), ShouldNotBeNil)
+	})
+}
+
+func TestGetPlotFormat(t *testing.T) {
+	Convey("Test plot format detection", t, func() {
+		So(GetPlotFormat([]byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`)), ShouldEqual, PlotFormatSVG)
+		So(GetPlotFormat([]byte("\x89PNG\r\n\x1a\n")), ShouldEqual, PlotFormatPNG)
+		So(PlotFormatSVG.ContentType(), ShouldEqual, "image/svg+xml")
+		So(PlotFormatPNG.ContentType(), ShouldEqual, "image/png")
+	})
+}
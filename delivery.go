@@ -0,0 +1,78 @@
+package moira
+
+// DeliveryStatus is a result of attempt to send notification package.
+type DeliveryStatus string
+
+const (
+	// DeliveryStatusOK means that package was delivered.
+	DeliveryStatusOK DeliveryStatus = "ok"
+	// DeliveryStatusFailed means that package was not delivered and it is scheduled to be resent.
+	DeliveryStatusFailed DeliveryStatus = "failed"
+	// DeliveryStatusDropped means that package was not delivered and it was moved to dead letters.
+	DeliveryStatusDropped DeliveryStatus = "dropped"
+)
+
+// DeliveryAttempt is a record of delivery log about single attempt to send notification package.
+type DeliveryAttempt struct {
+	Timestamp   int64          `json:"timestamp" example:"1590741878" format:"int64"`
+	ContactID   string         `json:"contact_id" example:"1dd38765-c5be-418d-81fa-7a5f879c2315"`
+	ContactType string         `json:"contact_type" example:"slack"`
+	SenderType  string         `json:"sender_type,omitempty" example:"slack"`
+	TriggerID   string         `json:"trigger_id" example:"5ff37996-8927-4cab-8987-970e80d8e0a8"`
+	EventsCount int            `json:"events_count" example:"1"`
+	FailCount   int            `json:"fail_count" example:"0"`
+	Status      DeliveryStatus `json:"status" example:"failed"`
+	Error       string         `json:"error,omitempty" example:"failed to post message to slack: channel_not_found"`
+	// LatencyMs is the time sender spent on the attempt.
+	LatencyMs int64 `json:"latency_ms" example:"250" format:"int64"`
+	// DeadLetterID is set if package was moved to dead letters after the attempt.
+	DeadLetterID string `json:"dead_letter_id,omitempty" example:"d3adbeef-5a0f-4f8a-9d3e-2b1c6a7d9e0f"`
+}
+
+// DeliveryAttemptsFilter contains conditions delivery attempts are searched by. Empty fields are not used.
+type DeliveryAttemptsFilter struct {
+	ContactID string
+	TriggerID string
+	Status    DeliveryStatus
+	From      int64
+	To        int64
+	Limit     int64
+}
+
+// IsMatched returns true if delivery attempt satisfies all conditions of the filter except time range and limit.
+func (filter *DeliveryAttemptsFilter) IsMatched(attempt *DeliveryAttempt) bool {
+	return (filter.ContactID == "" || filter.ContactID == attempt.ContactID) &&
+		(filter.TriggerID == "" || filter.TriggerID == attempt.TriggerID) &&
+		(filter.Status == "" || filter.Status == attempt.Status)
+}
+
+// DeadLetter is a notification package notifier stopped trying to deliver.
+type DeadLetter struct {
+	ID        string              `json:"id" example:"d3adbeef-5a0f-4f8a-9d3e-2b1c6a7d9e0f"`
+	Timestamp int64               `json:"timestamp" example:"1590741878" format:"int64"`
+	Reason    string              `json:"reason" example:"failed to post message to slack: channel_not_found"`
+	Events    []NotificationEvent `json:"events"`
+	Trigger   TriggerData         `json:"trigger"`
+	Contact   ContactData         `json:"contact"`
+	Plotting  PlottingData        `json:"plotting"`
+	Throttled bool                `json:"throttled" example:"false"`
+	FailCount int                 `json:"fail_count" example:"1440"`
+}
+
+// ToScheduledNotifications creates notifications which send dead letter events to given contact at given time.
+func (letter *DeadLetter) ToScheduledNotifications(contact ContactData, timestamp int64) []*ScheduledNotification {
+	notifications := make([]*ScheduledNotification, 0, len(letter.Events))
+	for _, event := range letter.Events {
+		event.ContactID = contact.ID
+		notifications = append(notifications, &ScheduledNotification{
+			Event:     event,
+			Trigger:   letter.Trigger,
+			Contact:   contact,
+			Plotting:  letter.Plotting,
+			Throttled: letter.Throttled,
+			Timestamp: timestamp,
+			CreatedAt: timestamp,
+		})
+	}
+	return notifications
+}
@@ -0,0 +1,59 @@
+package moira
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDeliveryAttemptsFilter_IsMatched(t *testing.T) {
+	Convey("Test delivery attempts filter", t, func() {
+		attempt := &DeliveryAttempt{ContactID: "contact", TriggerID: "trigger", Status: DeliveryStatusFailed}
+
+		So((&DeliveryAttemptsFilter{}).IsMatched(attempt), ShouldBeTrue)
+		So((&DeliveryAttemptsFilter{ContactID: "contact", TriggerID: "trigger", Status: DeliveryStatusFailed}).IsMatched(attempt), ShouldBeTrue)
+		So((&DeliveryAttemptsFilter{ContactID: "other"}).IsMatched(attempt), ShouldBeFalse)
+		So((&DeliveryAttemptsFilter{TriggerID: "other"}).IsMatched(attempt), ShouldBeFalse)
+		So((&DeliveryAttemptsFilter{Status: DeliveryStatusOK}).IsMatched(attempt), ShouldBeFalse)
+	})
+}
+
+func TestDeadLetter_ToScheduledNotifications(t *testing.T) {
+	Convey("Dead letter events are scheduled to given contact", t, func() {
+		letter := &DeadLetter{
+			Events: []NotificationEvent{
+				{Metric: "metric1", ContactID: "old", State: StateERROR},
+				{Metric: "metric2", ContactID: "old", State: StateOK},
+			},
+			Trigger:   TriggerData{ID: "trigger"},
+			Contact:   ContactData{ID: "old"},
+			Plotting:  PlottingData{Enabled: true},
+			Throttled: true,
+			FailCount: 1440,
+		}
+		contact := ContactData{ID: "new", Type: "slack"}
+
+		notifications := letter.ToScheduledNotifications(contact, 100)
+		So(notifications, ShouldResemble, []*ScheduledNotification{
+			{
+				Event:     NotificationEvent{Metric: "metric1", ContactID: "new", State: StateERROR},
+				Trigger:   letter.Trigger,
+				Contact:   contact,
+				Plotting:  letter.Plotting,
+				Throttled: true,
+				Timestamp: 100,
+				CreatedAt: 100,
+			},
+			{
+				Event:     NotificationEvent{Metric: "metric2", ContactID: "new", State: StateOK},
+				Trigger:   letter.Trigger,
+				Contact:   contact,
+				Plotting:  letter.Plotting,
+				Throttled: true,
+				Timestamp: 100,
+				CreatedAt: 100,
+			},
+		})
+		So(letter.Events[0].ContactID, ShouldEqual, "old")
+	})
+}
@@ -1,5 +1,7 @@
 package moira
 
+import "fmt"
+
 // SenderBrokenContactError means than sender has no way to send message to contact.
 // Maybe receive contact was deleted, blocked or archived.
 type SenderBrokenContactError struct {
@@ -15,3 +17,40 @@ func NewSenderBrokenContactError(senderError error) SenderBrokenContactError {
 func (e SenderBrokenContactError) Error() string {
 	return e.SenderError.Error()
 }
+
+// Unwrap returns error of sender.
+func (e SenderBrokenContactError) Unwrap() error {
+	return e.SenderError
+}
+
+// SenderPermanentError means that sender failed to send message and retrying won't help,
+// e.g. message was rejected as malformed or too large. Unlike SenderBrokenContactError contact itself may be fine.
+type SenderPermanentError struct {
+	SenderError error
+}
+
+func NewSenderPermanentError(senderError error) SenderPermanentError {
+	return SenderPermanentError{
+		SenderError: senderError,
+	}
+}
+
+func (e SenderPermanentError) Error() string {
+	return e.SenderError.Error()
+}
+
+// Unwrap returns error of sender.
+func (e SenderPermanentError) Unwrap() error {
+	return e.SenderError
+}
+
+// ErrInvalidSearchQuery is returned by Searcher when search string contains filter which can not be converted into query.
+type ErrInvalidSearchQuery struct {
+	Filter string
+	Reason string
+}
+
+// Error is implementation of golang error interface for ErrInvalidSearchQuery struct.
+func (err ErrInvalidSearchQuery) Error() string {
+	return fmt.Sprintf("invalid search filter '%s': %s", err.Filter, err.Reason)
+}
@@ -0,0 +1,15 @@
+package filesystem
+
+// Config is the configuration structure for filesystem image store.
+type Config struct {
+	// Path is the directory where images are stored, it must be shared by notifier and api.
+	Path string `yaml:"path"`
+	// URL is the address images are served at by api, e.g. https://moira.example.com/api/image.
+	URL string `yaml:"url"`
+	// SigningKey is the secret used to sign links to images.
+	SigningKey string `yaml:"signing_key"`
+	// LinkTTL is the period links to images are valid for, images are removed afterwards. Default is 168h.
+	LinkTTL string `yaml:"link_ttl"`
+	// GCInterval is the period of removing expired images. Default is 1h.
+	GCInterval string `yaml:"gc_interval"`
+}
@@ -0,0 +1,71 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+// Start runs garbage collector which removes images with expired links.
+func (imageStore *ImageStore) Start(logger moira.Logger) {
+	imageStore.tomb.Go(func() error {
+		ticker := time.NewTicker(imageStore.gcInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-imageStore.tomb.Dying():
+				return nil
+			case <-ticker.C:
+				if removed, err := imageStore.removeExpiredImages(time.Now()); err != nil {
+					logger.Warning().
+						Error(err).
+						Int("removed", removed).
+						Msg("Failed to remove expired images")
+				} else if removed > 0 {
+					logger.Debug().
+						Int("removed", removed).
+						Msg("Expired images removed")
+				}
+			}
+		}
+	})
+}
+
+// Stop stops garbage collector and waits for it to finish.
+func (imageStore *ImageStore) Stop() error {
+	imageStore.tomb.Kill(nil)
+	return imageStore.tomb.Wait()
+}
+
+// removeExpiredImages removes images stored earlier than link TTL before now, links to them are already expired.
+func (imageStore *ImageStore) removeExpiredImages(now time.Time) (int, error) {
+	entries, err := os.ReadDir(imageStore.path)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	deadline := now.Add(-imageStore.linkTTL)
+	for _, entry := range entries {
+		if entry.IsDir() || !imageIDPattern.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return removed, err
+		}
+		if info.ModTime().After(deadline) {
+			continue
+		}
+		if err = os.Remove(filepath.Join(imageStore.path, entry.Name())); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
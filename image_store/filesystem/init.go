@@ -0,0 +1,79 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/tomb.v2"
+)
+
+const (
+	defaultLinkTTL    = 7 * 24 * time.Hour
+	defaultGCInterval = time.Hour
+)
+
+// ImageStore implements the ImageStore interface for local disk, images are served by api with signed links.
+type ImageStore struct {
+	path       string
+	url        string
+	signingKey []byte
+	linkTTL    time.Duration
+	gcInterval time.Duration
+	enabled    bool
+	tomb       tomb.Tomb
+}
+
+// Init initializes the filesystem image store with config from the yaml file.
+func (imageStore *ImageStore) Init(config Config) error {
+	if config.Path == "" {
+		return fmt.Errorf("path not found while configuring filesystem image store")
+	}
+	if config.URL == "" {
+		return fmt.Errorf("url not found while configuring filesystem image store")
+	}
+	if config.SigningKey == "" {
+		return fmt.Errorf("signing key not found while configuring filesystem image store")
+	}
+
+	linkTTL, err := parseDuration(config.LinkTTL, defaultLinkTTL)
+	if err != nil {
+		return fmt.Errorf("invalid link_ttl of filesystem image store: %w", err)
+	}
+	gcInterval, err := parseDuration(config.GCInterval, defaultGCInterval)
+	if err != nil {
+		return fmt.Errorf("invalid gc_interval of filesystem image store: %w", err)
+	}
+
+	if err = os.MkdirAll(config.Path, 0o750); err != nil {
+		return fmt.Errorf("could not create directory of filesystem image store: %w", err)
+	}
+
+	imageStore.path = config.Path
+	imageStore.url = strings.TrimSuffix(config.URL, "/")
+	imageStore.signingKey = []byte(config.SigningKey)
+	imageStore.linkTTL = linkTTL
+	imageStore.gcInterval = gcInterval
+	imageStore.enabled = true
+	return nil
+}
+
+// IsEnabled indicates whether the image store has been configured or not.
+func (imageStore *ImageStore) IsEnabled() bool {
+	return imageStore.enabled
+}
+
+func parseDuration(value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("duration must be positive, got %s", value)
+	}
+	return duration, nil
+}
@@ -0,0 +1,54 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInit(t *testing.T) {
+	Convey("Init tests", t, func() {
+		imageStore := &ImageStore{}
+		path := filepath.Join(t.TempDir(), "images")
+
+		Convey("Empty settings", func() {
+			err := imageStore.Init(Config{})
+			So(err, ShouldResemble, fmt.Errorf("path not found while configuring filesystem image store"))
+			So(imageStore.IsEnabled(), ShouldBeFalse)
+		})
+
+		Convey("Missing url", func() {
+			err := imageStore.Init(Config{Path: path, SigningKey: "key"})
+			So(err, ShouldResemble, fmt.Errorf("url not found while configuring filesystem image store"))
+			So(imageStore.IsEnabled(), ShouldBeFalse)
+		})
+
+		Convey("Missing signing key", func() {
+			err := imageStore.Init(Config{Path: path, URL: "http://moira/api/image"})
+			So(err, ShouldResemble, fmt.Errorf("signing key not found while configuring filesystem image store"))
+			So(imageStore.IsEnabled(), ShouldBeFalse)
+		})
+
+		Convey("Invalid link ttl", func() {
+			err := imageStore.Init(Config{Path: path, URL: "http://moira/api/image", SigningKey: "key", LinkTTL: "-1h"})
+			So(err, ShouldNotBeNil)
+			So(imageStore.IsEnabled(), ShouldBeFalse)
+		})
+
+		Convey("Has settings", func() {
+			err := imageStore.Init(Config{Path: path, URL: "http://moira/api/image/", SigningKey: "key", GCInterval: "10m"})
+			So(err, ShouldBeNil)
+			So(imageStore.IsEnabled(), ShouldBeTrue)
+			So(imageStore.url, ShouldEqual, "http://moira/api/image")
+			So(imageStore.linkTTL, ShouldEqual, defaultLinkTTL)
+			So(imageStore.gcInterval, ShouldEqual, 10*time.Minute)
+			info, err := os.Stat(path)
+			So(err, ShouldBeNil)
+			So(info.IsDir(), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,80 @@
+package filesystem
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/moira-alert/moira"
+)
+
+var (
+	// ErrInvalidSignature is returned when link to image is not signed by the store.
+	ErrInvalidSignature = errors.New("invalid image link signature")
+	// ErrLinkExpired is returned when link to image is expired.
+	ErrLinkExpired = errors.New("image link is expired")
+	// ErrImageNotFound is returned when image does not exist or has been removed by garbage collector.
+	ErrImageNotFound = errors.New("image not found")
+)
+
+var imageIDPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\.(png|svg)$`)
+
+// StoreImage saves an image to disk and returns signed link to it which expires after link TTL.
+func (imageStore *ImageStore) StoreImage(image []byte) (string, error) {
+	imageUUID, err := uuid.NewV4()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate uuid: %w", err)
+	}
+	imageID := imageUUID.String() + "." + string(moira.GetPlotFormat(image))
+
+	if err = os.WriteFile(filepath.Join(imageStore.path, imageID), image, 0o640); err != nil { //nolint:gosec
+		return "", fmt.Errorf("error while saving image: %w", err)
+	}
+
+	expires := time.Now().Add(imageStore.linkTTL).Unix()
+	return imageStore.buildLink(imageID, expires), nil
+}
+
+// GetImage returns image by signed link parameters.
+func (imageStore *ImageStore) GetImage(imageID string, expires int64, signature string) ([]byte, moira.PlotFormat, error) {
+	if !imageIDPattern.MatchString(imageID) {
+		return nil, "", ErrImageNotFound
+	}
+	if !hmac.Equal([]byte(signature), []byte(imageStore.sign(imageID, expires))) {
+		return nil, "", ErrInvalidSignature
+	}
+	if time.Now().Unix() > expires {
+		return nil, "", ErrLinkExpired
+	}
+
+	image, err := os.ReadFile(filepath.Join(imageStore.path, imageID))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, "", ErrImageNotFound
+		}
+		return nil, "", fmt.Errorf("error while reading image: %w", err)
+	}
+	return image, moira.GetPlotFormat(image), nil
+}
+
+func (imageStore *ImageStore) buildLink(imageID string, expires int64) string {
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", imageStore.sign(imageID, expires))
+	return fmt.Sprintf("%s/%s?%s", imageStore.url, imageID, query.Encode())
+}
+
+func (imageStore *ImageStore) sign(imageID string, expires int64) string {
+	mac := hmac.New(sha256.New, imageStore.signingKey)
+	mac.Write([]byte(imageID + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
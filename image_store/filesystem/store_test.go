@@ -0,0 +1,113 @@
+package filesystem
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStoreImage(t *testing.T) {
+	imageStore := &ImageStore{}
+	imageStore.Init(Config{ //nolint
+		Path:       t.TempDir(),
+		URL:        "http://moira/api/image",
+		SigningKey: "key",
+	})
+	image := []byte("\x89PNG\r\n\x1a\nimage")
+
+	Convey("Store and get image", t, func() {
+		link, err := imageStore.StoreImage(image)
+		So(err, ShouldBeNil)
+		So(link, ShouldStartWith, "http://moira/api/image/")
+
+		parsed, err := url.Parse(link)
+		So(err, ShouldBeNil)
+		imageID := path.Base(parsed.Path)
+		So(imageID, ShouldEndWith, ".png")
+		expires, err := strconv.ParseInt(parsed.Query().Get("expires"), 10, 64)
+		So(err, ShouldBeNil)
+		So(expires, ShouldBeGreaterThan, time.Now().Add(defaultLinkTTL-time.Minute).Unix())
+		signature := parsed.Query().Get("signature")
+
+		Convey("With valid signature", func() {
+			actual, format, err := imageStore.GetImage(imageID, expires, signature)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, image)
+			So(format, ShouldEqual, moira.PlotFormatPNG)
+		})
+
+		Convey("With changed expiration", func() {
+			_, _, err := imageStore.GetImage(imageID, expires+1, signature)
+			So(err, ShouldEqual, ErrInvalidSignature)
+		})
+
+		Convey("With signature of another store", func() {
+			another := &ImageStore{signingKey: []byte("another")}
+			_, _, err := imageStore.GetImage(imageID, expires, another.sign(imageID, expires))
+			So(err, ShouldEqual, ErrInvalidSignature)
+		})
+
+		Convey("With expired link", func() {
+			expired := time.Now().Add(-time.Minute).Unix()
+			_, _, err := imageStore.GetImage(imageID, expired, imageStore.sign(imageID, expired))
+			So(err, ShouldEqual, ErrLinkExpired)
+		})
+
+		Convey("With path outside of store", func() {
+			imageID := "../" + imageID
+			_, _, err := imageStore.GetImage(imageID, expires, imageStore.sign(imageID, expires))
+			So(err, ShouldEqual, ErrImageNotFound)
+		})
+
+		Convey("With removed image", func() {
+			So(os.Remove(filepath.Join(imageStore.path, imageID)), ShouldBeNil)
+			_, _, err := imageStore.GetImage(imageID, expires, signature)
+			So(err, ShouldEqual, ErrImageNotFound)
+		})
+	})
+}
+
+func TestRemoveExpiredImages(t *testing.T) {
+	Convey("Remove expired images", t, func() {
+		imageStore := &ImageStore{}
+		imageStore.Init(Config{ //nolint
+			Path:       t.TempDir(),
+			URL:        "http://moira/api/image",
+			SigningKey: "key",
+			LinkTTL:    "1h",
+		})
+		link, err := imageStore.StoreImage([]byte("<svg></svg>"))
+		So(err, ShouldBeNil)
+		imageID := strings.Split(path.Base(link), "?")[0]
+		foreign := filepath.Join(imageStore.path, "foreign.txt")
+		So(os.WriteFile(foreign, []byte("data"), 0o600), ShouldBeNil)
+
+		Convey("Images with valid links are kept", func() {
+			removed, err := imageStore.removeExpiredImages(time.Now())
+			So(err, ShouldBeNil)
+			So(removed, ShouldEqual, 0)
+			So(fileExists(filepath.Join(imageStore.path, imageID)), ShouldBeTrue)
+		})
+
+		Convey("Images with expired links are removed, other files are kept", func() {
+			removed, err := imageStore.removeExpiredImages(time.Now().Add(2 * time.Hour))
+			So(err, ShouldBeNil)
+			So(removed, ShouldEqual, 1)
+			So(fileExists(filepath.Join(imageStore.path, imageID)), ShouldBeFalse)
+			So(fileExists(foreign), ShouldBeTrue)
+		})
+	})
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -6,4 +6,8 @@ type Config struct {
 	AccessKey   string `yaml:"access_key"`
 	Region      string `yaml:"region"`
 	Bucket      string `yaml:"bucket"`
+	// If true, images are uploaded as private objects and pre-signed GET links to them are returned instead of public ones.
+	PresignedURLs bool `yaml:"presigned_urls"`
+	// PresignedURLTTL is the period pre-signed links are valid for, it can't be longer than 168h. Default is 168h.
+	PresignedURLTTL string `yaml:"presigned_url_ttl"`
 }
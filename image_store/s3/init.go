@@ -2,20 +2,28 @@ package s3
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 )
 
+// maxPresignedURLTTL is the longest period pre-signed links can be valid for with signature version 4.
+const maxPresignedURLTTL = 7 * 24 * time.Hour
+
 // ImageStore implements the ImageStore interface for aws s3.
 type ImageStore struct {
-	sess     *session.Session
-	uploader *s3manager.Uploader
-	bucket   string
-	enabled  bool
+	sess       *session.Session
+	uploader   *s3manager.Uploader
+	client     *s3.S3
+	bucket     string
+	presign    bool
+	presignTTL time.Duration
+	enabled    bool
 }
 
 // Init initializes the s3 image store with config from the yaml file.
@@ -35,17 +43,32 @@ func (imageStore *ImageStore) Init(config Config) error {
 	}
 	awsconfig.Region = aws.String(config.Region)
 
-	imageStore.bucket = config.Bucket
-	if imageStore.bucket == "" {
+	if config.Bucket == "" {
 		return fmt.Errorf("bucket not found while configuring s3 image store")
 	}
 
-	var err error
-	imageStore.sess, err = session.NewSession(awsconfig)
+	presignTTL := maxPresignedURLTTL
+	if config.PresignedURLTTL != "" {
+		var err error
+		presignTTL, err = time.ParseDuration(config.PresignedURLTTL)
+		if err != nil {
+			return fmt.Errorf("invalid presigned_url_ttl of s3 image store: %w", err)
+		}
+		if presignTTL <= 0 || presignTTL > maxPresignedURLTTL {
+			return fmt.Errorf("presigned_url_ttl of s3 image store must be between 0 and %s", maxPresignedURLTTL)
+		}
+	}
+
+	sess, err := session.NewSession(awsconfig)
 	if err != nil {
 		return fmt.Errorf("could not configure s3 session: %w", err)
 	}
+	imageStore.sess = sess
+	imageStore.bucket = config.Bucket
 	imageStore.uploader = s3manager.NewUploader(imageStore.sess)
+	imageStore.client = s3.New(imageStore.sess)
+	imageStore.presign = config.PresignedURLs
+	imageStore.presignTTL = presignTTL
 
 	imageStore.enabled = true
 	return nil
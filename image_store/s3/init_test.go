@@ -52,6 +52,20 @@ func TestInit(t *testing.T) {
 			So(imageStore, ShouldResemble, &ImageStore{})
 		})
 
+		Convey("Too long presigned url ttl", func() {
+			config := Config{
+				AccessKeyID:     "123",
+				AccessKey:       "123",
+				Region:          "ap-south-1",
+				Bucket:          "testbucket",
+				PresignedURLs:   true,
+				PresignedURLTTL: "200h",
+			}
+			err := imageStore.Init(config)
+			So(err, ShouldResemble, fmt.Errorf("presigned_url_ttl of s3 image store must be between 0 and 168h0m0s"))
+			So(imageStore, ShouldResemble, &ImageStore{})
+		})
+
 		Convey("Has settings", func() {
 			config := Config{
 				AccessKeyID: "123",
@@ -66,6 +80,8 @@ func TestInit(t *testing.T) {
 			So(imageStore.sess.Config.Region, ShouldResemble, aws.String(config.Region))
 			So(imageStore.bucket, ShouldResemble, config.Bucket)
 			So(imageStore.enabled, ShouldResemble, true)
+			So(imageStore.presign, ShouldBeFalse)
+			So(imageStore.presignTTL, ShouldEqual, maxPresignedURLTTL)
 		})
 	})
 }
@@ -3,14 +3,16 @@ package s3
 import (
 	"bytes"
 	"fmt"
-	"net/http"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/gofrs/uuid"
+	"github.com/moira-alert/moira"
 )
 
-// StoreImage stores an image in aws s3 and returns the link to it.
+// StoreImage stores an image in aws s3 and returns the link to it,
+// the link is pre-signed and expires if pre-signed links are enabled.
 func (imageStore *ImageStore) StoreImage(image []byte) (string, error) {
 	uploadInput, err := imageStore.buildUploadInput(image)
 	if err != nil {
@@ -21,7 +23,22 @@ func (imageStore *ImageStore) StoreImage(image []byte) (string, error) {
 		return "", fmt.Errorf("error while uploading to s3: %w", err)
 	}
 
-	return result.Location, nil
+	if !imageStore.presign {
+		return result.Location, nil
+	}
+	return imageStore.presignLink(uploadInput.Key)
+}
+
+func (imageStore *ImageStore) presignLink(key *string) (string, error) {
+	request, _ := imageStore.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(imageStore.bucket),
+		Key:    key,
+	})
+	link, err := request.Presign(imageStore.presignTTL)
+	if err != nil {
+		return "", fmt.Errorf("error while presigning s3 link: %w", err)
+	}
+	return link, nil
 }
 
 func (imageStore *ImageStore) buildUploadInput(image []byte) (*s3manager.UploadInput, error) {
@@ -30,12 +47,15 @@ func (imageStore *ImageStore) buildUploadInput(image []byte) (*s3manager.UploadI
 		return nil, fmt.Errorf("failed to generate uuid: %w", err)
 	}
 	key := "moira-plots/" + uuid.String()
-	return &s3manager.UploadInput{
+	uploadInput := &s3manager.UploadInput{
 		Bucket:             aws.String(imageStore.bucket),
 		Key:                aws.String(key),
-		ACL:                aws.String("public-read"),
 		Body:               bytes.NewReader(image),
-		ContentType:        aws.String(http.DetectContentType(image)),
+		ContentType:        aws.String(moira.GetPlotFormat(image).ContentType()),
 		ContentDisposition: aws.String("attachment"),
-	}, nil
+	}
+	if !imageStore.presign {
+		uploadInput.ACL = aws.String("public-read")
+	}
+	return uploadInput, nil
 }
@@ -23,5 +23,39 @@ func TestBuildUploadInput(t *testing.T) {
 			So(uploadInput.Bucket, ShouldResemble, aws.String(imageStore.bucket))
 			So(uploadInput.ACL, ShouldResemble, aws.String("public-read"))
 		})
+
+		Convey("Build upload input with pre-signed links", func() {
+			presignedStore := &ImageStore{}
+			presignedStore.Init(Config{ //nolint
+				AccessKeyID:   "123",
+				AccessKey:     "123",
+				Region:        "ap-south-1",
+				Bucket:        "testbucket",
+				PresignedURLs: true,
+			})
+			uploadInput, _ := presignedStore.buildUploadInput([]byte{})
+			So(uploadInput.Bucket, ShouldResemble, aws.String(presignedStore.bucket))
+			So(uploadInput.ACL, ShouldBeNil)
+		})
+	})
+}
+
+func TestPresignLink(t *testing.T) {
+	imageStore := &ImageStore{}
+	imageStore.Init(Config{ //nolint
+		AccessKeyID:     "123",
+		AccessKey:       "123",
+		Region:          "ap-south-1",
+		Bucket:          "testbucket",
+		PresignedURLs:   true,
+		PresignedURLTTL: "1h",
+	})
+	Convey("Presign link to uploaded image", t, func() {
+		link, err := imageStore.presignLink(aws.String("moira-plots/plot"))
+		So(err, ShouldBeNil)
+		So(link, ShouldContainSubstring, "testbucket")
+		So(link, ShouldContainSubstring, "moira-plots/plot")
+		So(link, ShouldContainSubstring, "X-Amz-Expires=3600")
+		So(link, ShouldContainSubstring, "X-Amz-Signature=")
 	})
 }
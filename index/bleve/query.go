@@ -3,6 +3,7 @@ package bleve
 import (
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/blevesearch/bleve/v2"
 	"github.com/blevesearch/bleve/v2/search/query"
@@ -10,10 +11,16 @@ import (
 	"github.com/moira-alert/moira/index/mapping"
 )
 
-func buildSearchQuery(options moira.SearchOptions) query.Query {
-	searchTerms := splitStringToTerms(options.SearchString)
-	if !options.OnlyProblems && len(options.Tags) == 0 && len(searchTerms) == 0 && !options.NeedSearchByCreatedBy {
-		return bleve.NewMatchAllQuery()
+func buildSearchQuery(options moira.SearchOptions) (query.Query, error) {
+	searchText, searchFilters := parseSearchString(options.SearchString)
+	searchTerms := splitStringToTerms(strings.ToLower(searchText))
+	if !options.OnlyProblems && len(options.Tags) == 0 && len(searchTerms) == 0 && !options.NeedSearchByCreatedBy && len(searchFilters) == 0 {
+		return bleve.NewMatchAllQuery(), nil
+	}
+
+	filterQueries, err := buildQueryForFilters(searchFilters, time.Now())
+	if err != nil {
+		return nil, err
 	}
 
 	searchQueries := make([]query.Query, 0)
@@ -22,8 +29,9 @@ func buildSearchQuery(options moira.SearchOptions) query.Query {
 	searchQueries = append(searchQueries, buildQueryForTerms(searchTerms)...)
 	searchQueries = append(searchQueries, buildQueryForOnlyErrors(options.OnlyProblems)...)
 	searchQueries = append(searchQueries, buildQueryForCreatedBy(options.CreatedBy, options.NeedSearchByCreatedBy)...)
+	searchQueries = append(searchQueries, filterQueries...)
 
-	return bleve.NewConjunctionQuery(searchQueries...)
+	return bleve.NewConjunctionQuery(searchQueries...), nil
 }
 
 func buildQueryForTags(filterTags []string) (searchQueries []query.Query) {
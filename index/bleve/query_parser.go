@@ -0,0 +1,250 @@
+package bleve
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/index/mapping"
+)
+
+// Search string filter keys. A filter looks like "key:value", "-key:value" or key:"quoted value".
+const (
+	filterState     = "state"
+	filterTag       = "tag"
+	filterTarget    = "target"
+	filterPattern   = "pattern"
+	filterSource    = "source"
+	filterCluster   = "cluster"
+	filterCreatedBy = "created_by"
+	filterUpdatedBy = "updated_by"
+	filterUpdated   = "updated"
+)
+
+const dateLayout = "2006-01-02"
+
+var knownFilters = map[string]bool{
+	filterState:     true,
+	filterTag:       true,
+	filterTarget:    true,
+	filterPattern:   true,
+	filterSource:    true,
+	filterCluster:   true,
+	filterCreatedBy: true,
+	filterUpdatedBy: true,
+	filterUpdated:   true,
+}
+
+var searchableStates = map[moira.State]bool{
+	moira.StateOK:        true,
+	moira.StateWARN:      true,
+	moira.StateERROR:     true,
+	moira.StateNODATA:    true,
+	moira.StateEXCEPTION: true,
+}
+
+var searchableSources = map[moira.TriggerSource]bool{
+	moira.GraphiteLocal:    true,
+	moira.GraphiteRemote:   true,
+	moira.PrometheusRemote: true,
+}
+
+// searchFilter represents single "key:value" condition of search string.
+type searchFilter struct {
+	key     string
+	value   string
+	negated bool
+}
+
+func (filter searchFilter) String() string {
+	prefix := ""
+	if filter.negated {
+		prefix = "-"
+	}
+	return fmt.Sprintf("%s%s:%s", prefix, filter.key, filter.value)
+}
+
+// parseSearchString splits search string into free text and filters.
+// Tokens that look like filters but use unknown keys are treated as free text.
+func parseSearchString(searchString string) (text string, filters []searchFilter) {
+	textTokens := make([]string, 0)
+	for _, token := range splitStringToTokens(searchString) {
+		filter, ok := parseFilter(token)
+		if !ok {
+			textTokens = append(textTokens, token)
+			continue
+		}
+		filters = append(filters, filter)
+	}
+	return strings.Join(textTokens, " "), filters
+}
+
+// splitStringToTokens splits string by whitespaces, but keeps double-quoted parts together.
+func splitStringToTokens(searchString string) []string {
+	tokens := make([]string, 0)
+	var builder strings.Builder
+	inQuotes := false
+	for _, char := range searchString {
+		switch {
+		case char == '"':
+			inQuotes = !inQuotes
+			builder.WriteRune(char)
+		case !inQuotes && (char == ' ' || char == '\t' || char == '\n'):
+			if builder.Len() > 0 {
+				tokens = append(tokens, builder.String())
+				builder.Reset()
+			}
+		default:
+			builder.WriteRune(char)
+		}
+	}
+	if builder.Len() > 0 {
+		tokens = append(tokens, builder.String())
+	}
+	return tokens
+}
+
+func parseFilter(token string) (searchFilter, bool) {
+	filter := searchFilter{}
+	if strings.HasPrefix(token, "-") {
+		filter.negated = true
+		token = token[1:]
+	}
+
+	key, value, found := strings.Cut(token, ":")
+	if !found {
+		return searchFilter{}, false
+	}
+
+	filter.key = strings.ToLower(key)
+	if !knownFilters[filter.key] {
+		return searchFilter{}, false
+	}
+
+	filter.value = strings.Trim(value, `"`)
+	return filter, true
+}
+
+func buildQueryForFilters(filters []searchFilter, now time.Time) ([]query.Query, error) {
+	searchQueries := make([]query.Query, 0, len(filters))
+	for _, filter := range filters {
+		if filter.value == "" {
+			return nil, moira.ErrInvalidSearchQuery{Filter: filter.String(), Reason: "value must be set"}
+		}
+
+		qr, err := buildQueryForFilter(filter, now)
+		if err != nil {
+			return nil, err
+		}
+
+		if filter.negated {
+			negatedQuery := bleve.NewBooleanQuery()
+			negatedQuery.AddMustNot(qr)
+			qr = negatedQuery
+		}
+		searchQueries = append(searchQueries, qr)
+	}
+	return searchQueries, nil
+}
+
+func buildQueryForFilter(filter searchFilter, now time.Time) (query.Query, error) {
+	switch filter.key {
+	case filterState:
+		state := moira.State(strings.ToUpper(filter.value))
+		if !searchableStates[state] {
+			return nil, moira.ErrInvalidSearchQuery{Filter: filter.String(), Reason: "unknown state"}
+		}
+		return newFieldTermQuery(mapping.TriggerLastCheckState, state.String()), nil
+	case filterTag:
+		return newFieldTermQuery(mapping.TriggerTags, filter.value), nil
+	case filterTarget:
+		return newFieldWildcardQuery(mapping.TriggerTargets, filter.value), nil
+	case filterPattern:
+		return newFieldWildcardQuery(mapping.TriggerPatterns, filter.value), nil
+	case filterSource:
+		source := moira.TriggerSource(strings.ToLower(filter.value))
+		if !searchableSources[source] {
+			return nil, moira.ErrInvalidSearchQuery{Filter: filter.String(), Reason: "unknown trigger source"}
+		}
+		return newFieldTermQuery(mapping.TriggerSource, source.String()), nil
+	case filterCluster:
+		return newFieldTermQuery(mapping.TriggerClusterID, filter.value), nil
+	case filterCreatedBy:
+		return newFieldTermQuery(mapping.TriggerCreatedBy, filter.value), nil
+	case filterUpdatedBy:
+		return newFieldTermQuery(mapping.TriggerUpdatedBy, filter.value), nil
+	case filterUpdated:
+		return buildQueryForUpdated(filter, now)
+	}
+	return nil, moira.ErrInvalidSearchQuery{Filter: filter.String(), Reason: "unknown filter"}
+}
+
+// buildQueryForUpdated converts values like ">7d", "<12h" or ">2023-01-31" into numeric range query.
+// ">" means that trigger was updated after given moment, "<" means that trigger was updated before it.
+func buildQueryForUpdated(filter searchFilter, now time.Time) (query.Query, error) {
+	if len(filter.value) < 2 || (filter.value[0] != '>' && filter.value[0] != '<') {
+		return nil, moira.ErrInvalidSearchQuery{Filter: filter.String(), Reason: "value must start with '>' or '<'"}
+	}
+
+	moment, err := parseMoment(filter.value[1:], now)
+	if err != nil {
+		return nil, moira.ErrInvalidSearchQuery{Filter: filter.String(), Reason: err.Error()}
+	}
+
+	timestamp := float64(moment.Unix())
+	var qr *query.NumericRangeQuery
+	if filter.value[0] == '>' {
+		qr = bleve.NewNumericRangeQuery(&timestamp, nil)
+	} else {
+		qr = bleve.NewNumericRangeQuery(nil, &timestamp)
+	}
+	qr.FieldVal = mapping.TriggerUpdatedAt.GetName()
+	return qr, nil
+}
+
+// parseMoment parses either date in YYYY-MM-DD format or duration back from now, e.g. 30m, 12h, 7d, 2w.
+func parseMoment(value string, now time.Time) (time.Time, error) {
+	if date, err := time.Parse(dateLayout, value); err == nil {
+		return date, nil
+	}
+
+	unit := value[len(value)-1]
+	amount, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+	if err != nil || amount < 0 {
+		return time.Time{}, fmt.Errorf("expected duration like 7d or date like %s", dateLayout)
+	}
+
+	var duration time.Duration
+	switch unit {
+	case 'm':
+		duration = time.Minute
+	case 'h':
+		duration = time.Hour
+	case 'd':
+		duration = 24 * time.Hour //nolint
+	case 'w':
+		duration = 7 * 24 * time.Hour //nolint
+	default:
+		return time.Time{}, fmt.Errorf("unknown duration unit '%c', use one of m, h, d, w", unit)
+	}
+	return now.Add(-time.Duration(amount) * duration), nil
+}
+
+func newFieldTermQuery(field mapping.FieldData, value string) query.Query {
+	qr := bleve.NewTermQuery(value)
+	qr.FieldVal = field.GetName()
+	return qr
+}
+
+func newFieldWildcardQuery(field mapping.FieldData, value string) query.Query {
+	if !strings.ContainsAny(value, "*?") {
+		return newFieldTermQuery(field, value)
+	}
+	qr := bleve.NewWildcardQuery(value)
+	qr.FieldVal = field.GetName()
+	return qr
+}
@@ -2,10 +2,12 @@ package bleve
 
 import (
 	"testing"
+	"time"
 
 	"github.com/blevesearch/bleve/v2"
 	"github.com/blevesearch/bleve/v2/search/query"
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/index/mapping"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -23,7 +25,8 @@ func TestIndex_BuildSearchQuery(t *testing.T) {
 	Convey("Test build search query", t, func() {
 		Convey("Empty query", func() {
 			expected := bleve.NewMatchAllQuery()
-			actual := buildSearchQuery(searchOptions)
+			actual, err := buildSearchQuery(searchOptions)
+			So(err, ShouldBeNil)
 			So(actual, ShouldResemble, expected)
 		})
 
@@ -32,7 +35,8 @@ func TestIndex_BuildSearchQuery(t *testing.T) {
 				searchOptions.OnlyProblems = true
 				qr := buildQueryForOnlyErrors(searchOptions.OnlyProblems)
 				expected := bleve.NewConjunctionQuery(qr...)
-				actual := buildSearchQuery(searchOptions)
+				actual, err := buildSearchQuery(searchOptions)
+				So(err, ShouldBeNil)
 				So(actual, ShouldResemble, expected)
 			})
 
@@ -41,7 +45,8 @@ func TestIndex_BuildSearchQuery(t *testing.T) {
 				searchOptions.Tags = []string{"123", "456"}
 				qr := buildQueryForTags(searchOptions.Tags)
 				expected := bleve.NewConjunctionQuery(qr...)
-				actual := buildSearchQuery(searchOptions)
+				actual, err := buildSearchQuery(searchOptions)
+				So(err, ShouldBeNil)
 				So(actual, ShouldResemble, expected)
 			})
 
@@ -53,7 +58,8 @@ func TestIndex_BuildSearchQuery(t *testing.T) {
 
 				qr := buildQueryForTerms(searchTerms)
 				expected := bleve.NewConjunctionQuery(qr...)
-				actual := buildSearchQuery(searchOptions)
+				actual, err := buildSearchQuery(searchOptions)
+				So(err, ShouldBeNil)
 				So(actual, ShouldResemble, expected)
 			})
 
@@ -70,7 +76,8 @@ func TestIndex_BuildSearchQuery(t *testing.T) {
 				searchQueries = append(searchQueries, buildQueryForOnlyErrors(searchOptions.OnlyProblems)...)
 				expected := bleve.NewConjunctionQuery(searchQueries...)
 
-				actual := buildSearchQuery(searchOptions)
+				actual, err := buildSearchQuery(searchOptions)
+				So(err, ShouldBeNil)
 				So(actual, ShouldResemble, expected)
 			})
 
@@ -84,7 +91,8 @@ func TestIndex_BuildSearchQuery(t *testing.T) {
 				qr := buildQueryForCreatedBy(searchOptions.CreatedBy, searchOptions.NeedSearchByCreatedBy)
 				expected := bleve.NewConjunctionQuery(qr...)
 
-				actual := buildSearchQuery(searchOptions)
+				actual, err := buildSearchQuery(searchOptions)
+				So(err, ShouldBeNil)
 				So(actual, ShouldResemble, expected)
 			})
 
@@ -102,9 +110,108 @@ func TestIndex_BuildSearchQuery(t *testing.T) {
 				searchQueries = append(searchQueries, buildQueryForCreatedBy(searchOptions.CreatedBy, searchOptions.NeedSearchByCreatedBy)...)
 				expected := bleve.NewConjunctionQuery(searchQueries...)
 
-				actual := buildSearchQuery(searchOptions)
+				actual, err := buildSearchQuery(searchOptions)
+				So(err, ShouldBeNil)
 				So(actual, ShouldResemble, expected)
 			})
 		})
 	})
 }
+
+func TestParseSearchString(t *testing.T) {
+	Convey("Test parse search string", t, func() {
+		Convey("Only free text", func() {
+			text, filters := parseSearchString("disk space")
+			So(text, ShouldEqual, "disk space")
+			So(filters, ShouldBeEmpty)
+		})
+
+		Convey("Filters and free text", func() {
+			text, filters := parseSearchString(`state:ERROR disk tag:db -tag:test target:"*.cpu.*" updated:>7d`)
+			So(text, ShouldEqual, "disk")
+			So(filters, ShouldResemble, []searchFilter{
+				{key: filterState, value: "ERROR"},
+				{key: filterTag, value: "db"},
+				{key: filterTag, value: "test", negated: true},
+				{key: filterTarget, value: "*.cpu.*"},
+				{key: filterUpdated, value: ">7d"},
+			})
+		})
+
+		Convey("Quoted value with spaces", func() {
+			text, filters := parseSearchString(`tag:"my tag" name`)
+			So(text, ShouldEqual, "name")
+			So(filters, ShouldResemble, []searchFilter{{key: filterTag, value: "my tag"}})
+		})
+
+		Convey("Unknown keys are treated as free text", func() {
+			text, filters := parseSearchString("break:free")
+			So(text, ShouldEqual, "break:free")
+			So(filters, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestBuildQueryForFilters(t *testing.T) {
+	now := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	Convey("Test build query for filters", t, func() {
+		Convey("Term and wildcard filters", func() {
+			actual, err := buildQueryForFilters([]searchFilter{
+				{key: filterState, value: "error"},
+				{key: filterTarget, value: "*.cpu.*"},
+				{key: filterPattern, value: "my.metric"},
+			}, now)
+			So(err, ShouldBeNil)
+
+			stateQuery := bleve.NewTermQuery("ERROR")
+			stateQuery.FieldVal = mapping.TriggerLastCheckState.GetName()
+			targetQuery := bleve.NewWildcardQuery("*.cpu.*")
+			targetQuery.FieldVal = mapping.TriggerTargets.GetName()
+			patternQuery := bleve.NewTermQuery("my.metric")
+			patternQuery.FieldVal = mapping.TriggerPatterns.GetName()
+			So(actual, ShouldResemble, []query.Query{stateQuery, targetQuery, patternQuery})
+		})
+
+		Convey("Negated filter", func() {
+			actual, err := buildQueryForFilters([]searchFilter{{key: filterTag, value: "test", negated: true}}, now)
+			So(err, ShouldBeNil)
+
+			tagQuery := bleve.NewTermQuery("test")
+			tagQuery.FieldVal = mapping.TriggerTags.GetName()
+			expected := bleve.NewBooleanQuery()
+			expected.AddMustNot(tagQuery)
+			So(actual, ShouldResemble, []query.Query{expected})
+		})
+
+		Convey("Updated filter", func() {
+			actual, err := buildQueryForFilters([]searchFilter{
+				{key: filterUpdated, value: ">7d"},
+				{key: filterUpdated, value: "<2023-01-01"},
+			}, now)
+			So(err, ShouldBeNil)
+
+			after := float64(now.Add(-7 * 24 * time.Hour).Unix())
+			afterQuery := bleve.NewNumericRangeQuery(&after, nil)
+			afterQuery.FieldVal = mapping.TriggerUpdatedAt.GetName()
+			before := float64(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+			beforeQuery := bleve.NewNumericRangeQuery(nil, &before)
+			beforeQuery.FieldVal = mapping.TriggerUpdatedAt.GetName()
+			So(actual, ShouldResemble, []query.Query{afterQuery, beforeQuery})
+		})
+
+		Convey("Invalid filters", func() {
+			invalidFilters := []searchFilter{
+				{key: filterState, value: "BROKEN"},
+				{key: filterSource, value: "influx"},
+				{key: filterUpdated, value: "7d"},
+				{key: filterUpdated, value: ">7y"},
+				{key: filterTag, value: ""},
+			}
+			for _, filter := range invalidFilters {
+				_, err := buildQueryForFilters([]searchFilter{filter}, now)
+				So(err, ShouldHaveSameTypeAs, moira.ErrInvalidSearchQuery{})
+			}
+		})
+	})
+}
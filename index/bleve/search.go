@@ -10,7 +10,8 @@ import (
 	"github.com/moira-alert/moira/index/mapping"
 )
 
-// Search gets search params and returns triggerIDs in following order:
+// Search gets search params and returns triggerIDs in order given by options.SortBy.
+// By default the order is following:
 // TriggerCheck.Score (desc).
 // Relevance (asc).
 // Trigger.Name (asc).
@@ -21,7 +22,10 @@ func (index *TriggerIndex) Search(options moira.SearchOptions) (searchResults []
 		options.Size = int64(docs)
 	}
 
-	req := buildSearchRequest(options)
+	req, err := buildSearchRequest(options)
+	if err != nil {
+		return
+	}
 
 	searchResult, err := index.index.Search(req)
 	if err != nil {
@@ -60,17 +64,39 @@ func getHighlights(fragmentsMap search.FieldFragmentMap, triggerFields ...mappin
 	return highlights
 }
 
-func buildSearchRequest(options moira.SearchOptions) *bleve.SearchRequest {
-	searchQuery := buildSearchQuery(options)
+func buildSearchRequest(options moira.SearchOptions) (*bleve.SearchRequest, error) {
+	searchQuery, err := buildSearchQuery(options)
+	if err != nil {
+		return nil, err
+	}
 
 	from := options.Page * options.Size
 	req := bleve.NewSearchRequestOptions(searchQuery, int(options.Size), int(from), false)
-	// sorting order:
-	// TriggerCheck.Score (desc)
-	// Relevance (asc)
-	// Trigger.Name (asc)
-	req.SortBy([]string{fmt.Sprintf("-%s", mapping.TriggerLastCheckScore.GetName()), "-_score", mapping.TriggerName.GetName()})
+	req.SortBy(getSortOrder(options.SortBy))
 	req.Highlight = bleve.NewHighlight()
 
-	return req
+	return req, nil
+}
+
+func getSortOrder(sortBy moira.SearchSortBy) []string {
+	switch sortBy {
+	case moira.SortByName:
+		// Trigger.Name (asc)
+		// TriggerCheck.Score (desc)
+		return []string{mapping.TriggerName.GetName(), fmt.Sprintf("-%s", mapping.TriggerLastCheckScore.GetName())}
+	case moira.SortByLastEvent:
+		// TriggerCheck.EventTimestamp (desc)
+		// TriggerCheck.Score (desc)
+		// Trigger.Name (asc)
+		return []string{
+			fmt.Sprintf("-%s", mapping.TriggerLastEventTimestamp.GetName()),
+			fmt.Sprintf("-%s", mapping.TriggerLastCheckScore.GetName()),
+			mapping.TriggerName.GetName(),
+		}
+	default:
+		// TriggerCheck.Score (desc)
+		// Relevance (asc)
+		// Trigger.Name (asc)
+		return []string{fmt.Sprintf("-%s", mapping.TriggerLastCheckScore.GetName()), "-_score", mapping.TriggerName.GetName()}
+	}
 }
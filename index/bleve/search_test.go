@@ -2,6 +2,7 @@ package bleve
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 
@@ -376,6 +377,116 @@ func TestTriggerIndex_Search(t *testing.T) {
 	})
 }
 
+func TestTriggerIndex_SearchWithFilters(t *testing.T) {
+	now := time.Now().Unix()
+	weekAgo := now - 7*24*60*60
+	monthAgo := now - 30*24*60*60
+
+	triggerChecks := []*moira.TriggerCheck{
+		{
+			Trigger: moira.Trigger{
+				ID:            "cpu-trigger",
+				Name:          "CPU usage",
+				Targets:       []string{"servers.*.cpu.user"},
+				Patterns:      []string{"servers.*.cpu.user"},
+				Tags:          []string{"db", "cpu"},
+				TriggerSource: moira.GraphiteLocal,
+				UpdatedBy:     "alice",
+				UpdatedAt:     &now,
+			},
+			LastCheck: moira.CheckData{State: moira.StateERROR, Score: 100, EventTimestamp: weekAgo},
+		},
+		{
+			Trigger: moira.Trigger{
+				ID:            "memory-trigger",
+				Name:          "Memory usage",
+				Targets:       []string{"servers.*.memory.free"},
+				Patterns:      []string{"servers.*.memory.free"},
+				Tags:          []string{"db", "test"},
+				TriggerSource: moira.PrometheusRemote,
+				UpdatedBy:     "bob",
+				UpdatedAt:     &monthAgo,
+			},
+			LastCheck: moira.CheckData{State: moira.StateOK, Score: 0, EventTimestamp: now},
+		},
+		{
+			Trigger: moira.Trigger{
+				ID:            "disk-trigger",
+				Name:          "Disk space",
+				Targets:       []string{"servers.db1.disk.free"},
+				Patterns:      []string{"servers.db1.disk.free"},
+				Tags:          []string{"disk"},
+				TriggerSource: moira.PrometheusRemote,
+				UpdatedBy:     "alice",
+				UpdatedAt:     &weekAgo,
+			},
+			LastCheck: moira.CheckData{State: moira.StateNODATA, Score: 1000, EventTimestamp: monthAgo},
+		},
+	}
+
+	newIndex, err := CreateTriggerIndex(mapping.BuildIndexMapping(mapping.Trigger{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = newIndex.Write(triggerChecks); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(searchString string, sortBy moira.SearchSortBy) ([]string, error) {
+		searchResults, _, err := newIndex.Search(moira.SearchOptions{Size: 10, SearchString: searchString, SortBy: sortBy})
+		triggerIDs := make([]string, 0, len(searchResults))
+		for _, searchResult := range searchResults {
+			triggerIDs = append(triggerIDs, searchResult.ObjectID)
+		}
+		return triggerIDs, err
+	}
+
+	Convey("Search triggers with filters", t, func() {
+		testCases := []struct {
+			searchString string
+			expected     []string
+		}{
+			{"state:ERROR", []string{"cpu-trigger"}},
+			{"state:ok", []string{"memory-trigger"}},
+			{"tag:db -tag:test", []string{"cpu-trigger"}},
+			{`target:"*.cpu.*"`, []string{"cpu-trigger"}},
+			{"pattern:servers.db1.disk.free", []string{"disk-trigger"}},
+			{"source:prometheus_remote", []string{"disk-trigger", "memory-trigger"}},
+			{"updated_by:alice", []string{"disk-trigger", "cpu-trigger"}},
+			{"updated:>8d", []string{"disk-trigger", "cpu-trigger"}},
+			{"updated:<8d", []string{"memory-trigger"}},
+			{"source:prometheus_remote Memory", []string{"memory-trigger"}},
+		}
+
+		for _, testCase := range testCases {
+			Convey(testCase.searchString, func() {
+				triggerIDs, err := search(testCase.searchString, moira.SortByScore)
+				So(err, ShouldBeNil)
+				So(triggerIDs, ShouldResemble, testCase.expected)
+			})
+		}
+
+		Convey("Invalid filter returns error", func() {
+			_, err := search("state:BROKEN", moira.SortByScore)
+			So(err, ShouldHaveSameTypeAs, moira.ErrInvalidSearchQuery{})
+		})
+	})
+
+	Convey("Search triggers with sort order", t, func() {
+		Convey("By name", func() {
+			triggerIDs, err := search("", moira.SortByName)
+			So(err, ShouldBeNil)
+			So(triggerIDs, ShouldResemble, []string{"cpu-trigger", "disk-trigger", "memory-trigger"})
+		})
+
+		Convey("By last event", func() {
+			triggerIDs, err := search("", moira.SortByLastEvent)
+			So(err, ShouldBeNil)
+			So(triggerIDs, ShouldResemble, []string{"memory-trigger", "cpu-trigger", "disk-trigger"})
+		})
+	})
+}
+
 func TestStringsManipulations(t *testing.T) {
 	Convey("Test escape symbols", t, func() {
 		So(escapeString("12345"), ShouldResemble, "12345")
@@ -17,18 +17,42 @@ var (
 	TriggerTags = FieldData{"Tags", "tags", 0}
 	// TriggerCreatedBy represents field data for moira.Trigger.CreatedBy.
 	TriggerCreatedBy = FieldData{"CreatedBy", "created_by", 0}
+	// TriggerUpdatedBy represents field data for moira.Trigger.UpdatedBy.
+	TriggerUpdatedBy = FieldData{"UpdatedBy", "updated_by", 0}
+	// TriggerUpdatedAt represents field data for moira.Trigger.UpdatedAt.
+	TriggerUpdatedAt = FieldData{"UpdatedAt", "updated_at", 0}
+	// TriggerTargets represents field data for moira.Trigger.Targets.
+	TriggerTargets = FieldData{"Targets", "targets", 0}
+	// TriggerPatterns represents field data for moira.Trigger.Patterns.
+	TriggerPatterns = FieldData{"Patterns", "patterns", 0}
+	// TriggerSource represents field data for moira.Trigger.TriggerSource.
+	TriggerSource = FieldData{"TriggerSource", "trigger_source", 0}
+	// TriggerClusterID represents field data for moira.Trigger.ClusterId.
+	TriggerClusterID = FieldData{"ClusterId", "cluster_id", 0}
 	// TriggerLastCheckScore represents field data for moira.CheckData score.
 	TriggerLastCheckScore = FieldData{"LastCheckScore", "", 0}
+	// TriggerLastCheckState represents field data for moira.CheckData state.
+	TriggerLastCheckState = FieldData{"LastCheckState", "", 0}
+	// TriggerLastEventTimestamp represents field data for moira.CheckData event timestamp.
+	TriggerLastEventTimestamp = FieldData{"LastEventTimestamp", "", 0}
 )
 
 // Trigger represents Moira.Trigger type for full-text search index. It includes only indexed fields.
 type Trigger struct {
-	ID             string
-	Name           string
-	Desc           string
-	Tags           []string
-	CreatedBy      string
-	LastCheckScore int64
+	ID                 string
+	Name               string
+	Desc               string
+	Tags               []string
+	CreatedBy          string
+	UpdatedBy          string
+	UpdatedAt          int64
+	Targets            []string
+	Patterns           []string
+	TriggerSource      string
+	ClusterId          string
+	LastCheckScore     int64
+	LastCheckState     string
+	LastEventTimestamp int64
 }
 
 // Type returns string with type name. It is used for Bleve.Search.
@@ -44,19 +68,40 @@ func (Trigger) GetDocumentMapping() *mapping.DocumentMapping {
 	triggerMapping.AddFieldMappingsAt(TriggerTags.GetName(), getKeywordMapping())
 	triggerMapping.AddFieldMappingsAt(TriggerDesc.GetName(), getStandardMapping())
 	triggerMapping.AddFieldMappingsAt(TriggerCreatedBy.GetName(), getKeywordMapping())
+	triggerMapping.AddFieldMappingsAt(TriggerUpdatedBy.GetName(), getKeywordMapping())
+	triggerMapping.AddFieldMappingsAt(TriggerUpdatedAt.GetName(), getNumericMapping())
+	triggerMapping.AddFieldMappingsAt(TriggerTargets.GetName(), getKeywordMapping())
+	triggerMapping.AddFieldMappingsAt(TriggerPatterns.GetName(), getKeywordMapping())
+	triggerMapping.AddFieldMappingsAt(TriggerSource.GetName(), getKeywordMapping())
+	triggerMapping.AddFieldMappingsAt(TriggerClusterID.GetName(), getKeywordMapping())
 	triggerMapping.AddFieldMappingsAt(TriggerLastCheckScore.GetName(), getNumericMapping())
+	triggerMapping.AddFieldMappingsAt(TriggerLastCheckState.GetName(), getKeywordMapping())
+	triggerMapping.AddFieldMappingsAt(TriggerLastEventTimestamp.GetName(), getNumericMapping())
 
 	return triggerMapping
 }
 
 // CreateIndexedTrigger creates mapping.Trigger object out of moira.TriggerCheck.
 func CreateIndexedTrigger(triggerCheck *moira.TriggerCheck) Trigger {
+	var updatedAt int64
+	if triggerCheck.UpdatedAt != nil {
+		updatedAt = *triggerCheck.UpdatedAt
+	}
+
 	return Trigger{
-		ID:             triggerCheck.ID,
-		Name:           triggerCheck.Name,
-		Desc:           moira.UseString(triggerCheck.Desc),
-		Tags:           triggerCheck.Tags,
-		CreatedBy:      triggerCheck.CreatedBy,
-		LastCheckScore: triggerCheck.LastCheck.Score,
+		ID:                 triggerCheck.ID,
+		Name:               triggerCheck.Name,
+		Desc:               moira.UseString(triggerCheck.Desc),
+		Tags:               triggerCheck.Tags,
+		CreatedBy:          triggerCheck.CreatedBy,
+		UpdatedBy:          triggerCheck.UpdatedBy,
+		UpdatedAt:          updatedAt,
+		Targets:            triggerCheck.Targets,
+		Patterns:           triggerCheck.Patterns,
+		TriggerSource:      triggerCheck.TriggerSource.FillInIfNotSet(false).String(),
+		ClusterId:          triggerCheck.ClusterId.FillInIfNotSet().String(),
+		LastCheckScore:     triggerCheck.LastCheck.Score,
+		LastCheckState:     triggerCheck.LastCheck.State.String(),
+		LastEventTimestamp: triggerCheck.LastCheck.GetEventTimestamp(),
 	}
 }
@@ -15,11 +15,18 @@ var testTriggerFields = []FieldData{
 	TriggerName,
 	TriggerDesc,
 	TriggerTags,
+	TriggerCreatedBy,
+	TriggerUpdatedBy,
+	TriggerUpdatedAt,
+	TriggerTargets,
+	TriggerPatterns,
+	TriggerSource,
+	TriggerClusterID,
 	TriggerLastCheckScore,
 }
 
 func TestTriggerField_GetPriority(t *testing.T) {
-	expected := []float64{5, 3, 1, 0, 0}
+	expected := []float64{5, 3, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0}
 	actual := make([]float64, 0, len(testTriggerFields))
 	Convey("Test GetPriority returns correct field priority", t, func() {
 		for _, triggerField := range testTriggerFields {
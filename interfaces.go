@@ -33,6 +33,10 @@ type Database interface {
 	SetTriggerCheckMaintenance(triggerID string, metrics map[string]int64, triggerMaintenance *int64, userLogin string, timeCallMaintenance int64) error
 	CleanUpAbandonedTriggerLastCheck() error
 
+	// Metric state timeline storing
+	PushMetricStatesTimeline(triggerID string, points map[string][]MetricStatePoint) error
+	GetMetricStateTimeline(triggerID string, metric string, from int64, to int64) ([]*MetricStatePoint, error)
+
 	// Trigger storing
 	GetAllTriggerIDs() ([]string, error)
 	GetTriggerIDs(clusterKey ClusterKey) ([]string, error)
@@ -43,11 +47,38 @@ type Database interface {
 	GetTriggers(triggerIDs []string) ([]*Trigger, error)
 	GetTriggerChecks(triggerIDs []string) ([]*TriggerCheck, error)
 	SaveTrigger(triggerID string, trigger *Trigger) error
-	RemoveTrigger(triggerID string) error
+	RemoveTrigger(triggerID, userLogin string) error
 	GetPatternTriggerIDs(pattern string) ([]string, error)
 	RemovePatternTriggerIDs(pattern string) error
 	GetTriggerIDsStartWith(prefix string) ([]string, error)
 
+	// Trigger history storing
+	GetTriggerHistory(triggerID string) ([]*TriggerHistoryItem, error)
+	GetTriggerHistoryItem(triggerID string, version int64) (TriggerHistoryItem, error)
+	GetDeletedTriggers() ([]*TriggerHistoryItem, error)
+
+	// Audit log storing
+	SaveAuditRecord(record *AuditRecord) error
+	GetAuditRecords(filter AuditRecordsFilter) ([]*AuditRecord, error)
+
+	// Delivery log storing
+	SaveDeliveryAttempt(attempt *DeliveryAttempt) error
+	GetDeliveryAttempts(filter DeliveryAttemptsFilter) ([]*DeliveryAttempt, error)
+
+	// Dead letters storing
+	SaveDeadLetter(letter *DeadLetter) error
+	GetDeadLetter(id string) (DeadLetter, error)
+	GetDeadLetters(from, to int64) ([]*DeadLetter, error)
+	RemoveDeadLetter(id string) error
+
+	// API tokens storing
+	GetAPIToken(tokenID string) (APIToken, error)
+	GetAPITokens(tokenIDs []string) ([]*APIToken, error)
+	SaveAPIToken(token *APIToken) error
+	RemoveAPIToken(tokenID string) error
+	GetUserAPITokenIDs(userLogin string) ([]string, error)
+	GetTeamAPITokenIDs(teamID string) ([]string, error)
+
 	// SearchResult AKA pager storing
 	GetTriggersSearchResults(searchResultsID string, page, size int64) ([]*SearchResult, int64, error)
 	SaveTriggersSearchResults(searchResultsID string, searchResults []*SearchResult) error
@@ -61,10 +92,13 @@ type Database interface {
 
 	// NotificationEvent storing
 	GetNotificationEvents(triggerID string, start, size int64) ([]*NotificationEvent, error)
+	GetNotificationEventsByTime(triggerID string, from, to int64) ([]*NotificationEvent, error)
 	PushNotificationEvent(event *NotificationEvent, ui bool) error
 	GetNotificationEventCount(triggerID string, from int64) int64
 	FetchNotificationEvent() (NotificationEvent, error)
 	RemoveAllNotificationEvents() error
+	GetNotificationEventsStreamLastID() (string, error)
+	GetNotificationEventsStream(afterID string, count int64) (entries []NotificationEventsStreamEntry, lastID string, err error)
 
 	// ContactData storing
 	GetContact(contactID string) (ContactData, error)
@@ -88,6 +122,7 @@ type Database interface {
 	// ScheduledNotification storing
 	GetNotifications(start, end int64) ([]*ScheduledNotification, int64, error)
 	GetNotificationsByContactIdWithLimit(contactID string, from int64, to int64) ([]*NotificationEventHistoryItem, error)
+	GetNotificationsHistory(from int64, to int64) ([]*NotificationEventHistoryItem, error)
 	RemoveNotification(notificationKey string) (int64, error)
 	RemoveAllNotifications() error
 	FetchNotifications(to int64, limit int64) ([]*ScheduledNotification, error)
@@ -126,6 +161,9 @@ type Database interface {
 	GetIDByUsername(messenger, username string) (string, error)
 	SetUsernameID(messenger, username, id string) error
 	RemoveUser(messenger, username string) error
+	GetMessageThread(messenger, threadKey string) (string, error)
+	SetMessageThread(messenger, threadKey, thread string, ttl time.Duration) error
+	RemoveMessageThread(messenger, threadKey string) error
 
 	// Triggers without subscription manipulation
 	MarkTriggersAsUnused(triggerIDs ...string) error
@@ -142,11 +180,20 @@ type Database interface {
 	// Teams management
 	SaveTeam(teamID string, team Team) error
 	GetTeam(teamID string) (Team, error)
+	GetAllTeams() ([]Team, error)
 	SaveTeamsAndUsers(teamID string, users []string, usersTeams map[string][]string) error
 	GetUserTeams(userID string) ([]string, error)
 	GetTeamUsers(teamID string) ([]string, error)
 	IsTeamContainUser(teamID, userID string) (bool, error)
 	DeleteTeam(teamID, userID string) error
+	GetTeamUserRoles(teamID string) (map[string]TeamRole, error)
+	SaveTeamUserRoles(teamID string, roles map[string]TeamRole) error
+	SyncUserGroupTeams(userID string, teamIDs []string) error
+	GetTeamTriggerIDs(teamID string) ([]string, error)
+
+	// Analytics reports
+	GetAnalyticsReportSentAt() (int64, error)
+	SetAnalyticsReportSentAt(timestamp int64) error
 
 	// Metrics management
 	CleanUpOutdatedMetrics(duration time.Duration) error
@@ -197,6 +244,14 @@ type Sender interface {
 	Init(senderSettings interface{}, logger Logger, location *time.Location, dateTimeFormat string) error
 }
 
+// MessageBuilder is implemented by senders which build notification payload separately from its delivery.
+type MessageBuilder interface {
+	// InitMessageBuilder reads sender settings required to build messages, it doesn't connect to external services.
+	InitMessageBuilder(senderSettings interface{}, logger Logger, location *time.Location, dateTimeFormat string) error
+	// BuildMessage builds payload which would be delivered by SendEvents with the same arguments.
+	BuildMessage(events NotificationEvents, contact ContactData, trigger TriggerData, plots [][]byte, throttled bool) (NotificationPayload, error)
+}
+
 // ImageStore is the interface for image storage providers.
 type ImageStore interface {
 	StoreImage(image []byte) (string, error)
@@ -220,6 +275,8 @@ type PlotTheme interface {
 	GetThresholdStyle(thresholdType string) chart.Style
 	GetAnnotationStyle(thresholdType string) chart.Style
 	GetSerieStyles(curveInd int) (curveStyle, pointStyle chart.Style)
+	GetStateIntervalStyle(state State, maintenance bool) chart.Style
+	GetStateMarkerStyle(state State) chart.Style
 	GetLegendStyle() chart.Style
 	GetXAxisStyle() chart.Style
 	GetYAxisStyle() chart.Style
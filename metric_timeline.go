@@ -0,0 +1,70 @@
+package moira
+
+// MetricStatePoint is a change of metric state kept in metric state timeline.
+type MetricStatePoint struct {
+	Timestamp int64 `json:"ts"`
+	State     State `json:"state"`
+	// Suppressed is true if notification about the change was not sent because of maintenance or trigger schedule.
+	Suppressed bool `json:"suppressed,omitempty"`
+	// Maintenance is the time until which metric or its trigger is on maintenance.
+	Maintenance int64 `json:"maintenance,omitempty"`
+}
+
+// MetricStateInterval represents time span during which metric was in the same state.
+type MetricStateInterval struct {
+	From        int64 `json:"from" example:"1590741878" format:"int64"`
+	To          int64 `json:"to" example:"1590742878" format:"int64"`
+	State       State `json:"state" example:"ERROR"`
+	Suppressed  bool  `json:"suppressed" example:"false"`
+	Maintenance bool  `json:"maintenance" example:"false"`
+}
+
+// GetMetricStateIntervals converts timeline points sorted by time to intervals within [from, to].
+// Point before from defines state at the beginning of time range, time before the first point is not covered by intervals.
+func GetMetricStateIntervals(points []*MetricStatePoint, from, to int64) []MetricStateInterval {
+	intervals := make([]MetricStateInterval, 0, len(points))
+	for i, point := range points {
+		end := to
+		if i+1 < len(points) && points[i+1].Timestamp < to {
+			end = points[i+1].Timestamp
+		}
+		start := MaxInt64(point.Timestamp, from)
+		if start >= end {
+			continue
+		}
+
+		interval := MetricStateInterval{
+			From:        start,
+			To:          end,
+			State:       point.State,
+			Suppressed:  point.Suppressed,
+			Maintenance: point.Maintenance >= start,
+		}
+		if interval.Maintenance && point.Maintenance < end {
+			interval.To = point.Maintenance
+			intervals = appendMetricStateInterval(intervals, interval)
+
+			interval.From = point.Maintenance
+			interval.To = end
+			interval.Maintenance = false
+		}
+		intervals = appendMetricStateInterval(intervals, interval)
+	}
+	return intervals
+}
+
+// appendMetricStateInterval merges interval with the last one if they are adjacent and describe the same state.
+func appendMetricStateInterval(intervals []MetricStateInterval, interval MetricStateInterval) []MetricStateInterval {
+	if interval.From >= interval.To {
+		return intervals
+	}
+	if len(intervals) > 0 {
+		last := &intervals[len(intervals)-1]
+		if last.To == interval.From && last.State == interval.State &&
+			last.Suppressed == interval.Suppressed && last.Maintenance == interval.Maintenance {
+			last.To = interval.To
+			return intervals
+		}
+	}
+	return append(intervals, interval)
+}
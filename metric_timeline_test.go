@@ -0,0 +1,71 @@
+package moira
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetMetricStateIntervals(t *testing.T) {
+	Convey("Test GetMetricStateIntervals", t, func() {
+		Convey("Empty timeline has no intervals", func() {
+			So(GetMetricStateIntervals(nil, 100, 200), ShouldBeEmpty)
+		})
+
+		Convey("Point before time range defines state at its beginning", func() {
+			points := []*MetricStatePoint{
+				{Timestamp: 50, State: StateOK},
+				{Timestamp: 120, State: StateERROR},
+				{Timestamp: 150, State: StateOK},
+			}
+			So(GetMetricStateIntervals(points, 100, 200), ShouldResemble, []MetricStateInterval{
+				{From: 100, To: 120, State: StateOK},
+				{From: 120, To: 150, State: StateERROR},
+				{From: 150, To: 200, State: StateOK},
+			})
+		})
+
+		Convey("Time before the first point is not covered", func() {
+			points := []*MetricStatePoint{
+				{Timestamp: 130, State: StateNODATA},
+			}
+			So(GetMetricStateIntervals(points, 100, 200), ShouldResemble, []MetricStateInterval{
+				{From: 130, To: 200, State: StateNODATA},
+			})
+		})
+
+		Convey("Points after time range are ignored", func() {
+			points := []*MetricStatePoint{
+				{Timestamp: 100, State: StateWARN},
+				{Timestamp: 250, State: StateOK},
+			}
+			So(GetMetricStateIntervals(points, 100, 200), ShouldResemble, []MetricStateInterval{
+				{From: 100, To: 200, State: StateWARN},
+			})
+		})
+
+		Convey("Interval is split at the end of maintenance", func() {
+			points := []*MetricStatePoint{
+				{Timestamp: 110, State: StateOK},
+				{Timestamp: 120, State: StateOK, Maintenance: 160},
+				{Timestamp: 140, State: StateERROR, Suppressed: true, Maintenance: 160},
+			}
+			So(GetMetricStateIntervals(points, 100, 200), ShouldResemble, []MetricStateInterval{
+				{From: 110, To: 120, State: StateOK},
+				{From: 120, To: 140, State: StateOK, Maintenance: true},
+				{From: 140, To: 160, State: StateERROR, Suppressed: true, Maintenance: true},
+				{From: 160, To: 200, State: StateERROR, Suppressed: true},
+			})
+		})
+
+		Convey("Adjacent intervals with the same state are merged", func() {
+			points := []*MetricStatePoint{
+				{Timestamp: 100, State: StateERROR, Maintenance: 90},
+				{Timestamp: 130, State: StateERROR},
+			}
+			So(GetMetricStateIntervals(points, 100, 200), ShouldResemble, []MetricStateInterval{
+				{From: 100, To: 200, State: StateERROR},
+			})
+		})
+	})
+}
@@ -0,0 +1,13 @@
+package metrics
+
+// AuditMetrics is a collection of metrics used in audit log.
+type AuditMetrics struct {
+	DroppedRecords Meter
+}
+
+// ConfigureAuditMetrics is audit log metrics configurator.
+func ConfigureAuditMetrics(registry Registry) *AuditMetrics {
+	return &AuditMetrics{
+		DroppedRecords: registry.NewMeter("audit", "dropped_records"),
+	}
+}
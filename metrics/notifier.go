@@ -13,6 +13,8 @@ type NotifierMetrics struct {
 	SendersOkMetrics               MetersCollection
 	SendersFailedMetrics           MetersCollection
 	SendersDroppedNotifications    MetersCollection
+	SendersPostponedNotifications  MetersCollection
+	SendersOpenedCircuits          MetersCollection
 	PlotsBuildDurationMs           Histogram
 	PlotsEvaluateTriggerDurationMs Histogram
 	fetchNotificationsDurationMs   Histogram
@@ -30,6 +32,8 @@ func ConfigureNotifierMetrics(registry Registry, prefix string) *NotifierMetrics
 		SendersOkMetrics:               NewMetersCollection(registry),
 		SendersFailedMetrics:           NewMetersCollection(registry),
 		SendersDroppedNotifications:    NewMetersCollection(registry),
+		SendersPostponedNotifications:  NewMetersCollection(registry),
+		SendersOpenedCircuits:          NewMetersCollection(registry),
 		PlotsBuildDurationMs:           registry.NewHistogram("plots", "build", "duration", "ms"),
 		PlotsEvaluateTriggerDurationMs: registry.NewHistogram("plots", "evaluate", "trigger", "duration", "ms"),
 		fetchNotificationsDurationMs:   registry.NewHistogram("fetch", "notifications", "duration", "ms"),
@@ -48,6 +52,20 @@ func (metrics *NotifierMetrics) MarkSendersDroppedNotifications(contactType stri
 	}
 }
 
+// MarkSendersPostponedNotifications marks metrics as 1 by contactType for notifications postponed by rate limit or open circuit.
+func (metrics *NotifierMetrics) MarkSendersPostponedNotifications(contactType string) {
+	if metric, found := metrics.SendersPostponedNotifications.GetRegisteredMeter(contactType); found {
+		metric.Mark(1)
+	}
+}
+
+// MarkSendersOpenedCircuits marks metrics as 1 by contactType when circuit of sender was opened after consecutive failures.
+func (metrics *NotifierMetrics) MarkSendersOpenedCircuits(contactType string) {
+	if metric, found := metrics.SendersOpenedCircuits.GetRegisteredMeter(contactType); found {
+		metric.Mark(1)
+	}
+}
+
 // MarkSendersOkMetrics marks metrics as 1 by contactType when notifications were successfully sent.
 func (metrics *NotifierMetrics) MarkSendersOkMetrics(contactType string) {
 	if metric, found := metrics.SendersOkMetrics.GetRegisteredMeter(contactType); found {
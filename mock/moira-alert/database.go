@@ -307,6 +307,36 @@ func (mr *MockDatabaseMockRecorder) FetchTriggersToReindex(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchTriggersToReindex", reflect.TypeOf((*MockDatabase)(nil).FetchTriggersToReindex), arg0)
 }
 
+// GetAPIToken mocks base method.
+func (m *MockDatabase) GetAPIToken(arg0 string) (moira.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIToken", arg0)
+	ret0, _ := ret[0].(moira.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIToken indicates an expected call of GetAPIToken.
+func (mr *MockDatabaseMockRecorder) GetAPIToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIToken", reflect.TypeOf((*MockDatabase)(nil).GetAPIToken), arg0)
+}
+
+// GetAPITokens mocks base method.
+func (m *MockDatabase) GetAPITokens(arg0 []string) ([]*moira.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPITokens", arg0)
+	ret0, _ := ret[0].([]*moira.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPITokens indicates an expected call of GetAPITokens.
+func (mr *MockDatabaseMockRecorder) GetAPITokens(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPITokens", reflect.TypeOf((*MockDatabase)(nil).GetAPITokens), arg0)
+}
+
 // GetAllContacts mocks base method.
 func (m *MockDatabase) GetAllContacts() ([]*moira.ContactData, error) {
 	m.ctrl.T.Helper()
@@ -322,6 +352,21 @@ func (mr *MockDatabaseMockRecorder) GetAllContacts() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllContacts", reflect.TypeOf((*MockDatabase)(nil).GetAllContacts))
 }
 
+// GetAllTeams mocks base method.
+func (m *MockDatabase) GetAllTeams() ([]moira.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllTeams")
+	ret0, _ := ret[0].([]moira.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllTeams indicates an expected call of GetAllTeams.
+func (mr *MockDatabaseMockRecorder) GetAllTeams() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTeams", reflect.TypeOf((*MockDatabase)(nil).GetAllTeams))
+}
+
 // GetAllTriggerIDs mocks base method.
 func (m *MockDatabase) GetAllTriggerIDs() ([]string, error) {
 	m.ctrl.T.Helper()
@@ -337,6 +382,36 @@ func (mr *MockDatabaseMockRecorder) GetAllTriggerIDs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetAllTriggerIDs))
 }
 
+// GetAnalyticsReportSentAt mocks base method.
+func (m *MockDatabase) GetAnalyticsReportSentAt() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnalyticsReportSentAt")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnalyticsReportSentAt indicates an expected call of GetAnalyticsReportSentAt.
+func (mr *MockDatabaseMockRecorder) GetAnalyticsReportSentAt() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnalyticsReportSentAt", reflect.TypeOf((*MockDatabase)(nil).GetAnalyticsReportSentAt))
+}
+
+// GetAuditRecords mocks base method.
+func (m *MockDatabase) GetAuditRecords(arg0 moira.AuditRecordsFilter) ([]*moira.AuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditRecords", arg0)
+	ret0, _ := ret[0].([]*moira.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditRecords indicates an expected call of GetAuditRecords.
+func (mr *MockDatabaseMockRecorder) GetAuditRecords(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditRecords", reflect.TypeOf((*MockDatabase)(nil).GetAuditRecords), arg0)
+}
+
 // GetChecksUpdatesCount mocks base method.
 func (m *MockDatabase) GetChecksUpdatesCount() (int64, error) {
 	m.ctrl.T.Helper()
@@ -382,6 +457,66 @@ func (mr *MockDatabaseMockRecorder) GetContacts(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContacts", reflect.TypeOf((*MockDatabase)(nil).GetContacts), arg0)
 }
 
+// GetDeadLetter mocks base method.
+func (m *MockDatabase) GetDeadLetter(arg0 string) (moira.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", arg0)
+	ret0, _ := ret[0].(moira.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockDatabaseMockRecorder) GetDeadLetter(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockDatabase)(nil).GetDeadLetter), arg0)
+}
+
+// GetDeadLetters mocks base method.
+func (m *MockDatabase) GetDeadLetters(arg0, arg1 int64) ([]*moira.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetters", arg0, arg1)
+	ret0, _ := ret[0].([]*moira.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetters indicates an expected call of GetDeadLetters.
+func (mr *MockDatabaseMockRecorder) GetDeadLetters(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetters", reflect.TypeOf((*MockDatabase)(nil).GetDeadLetters), arg0, arg1)
+}
+
+// GetDeletedTriggers mocks base method.
+func (m *MockDatabase) GetDeletedTriggers() ([]*moira.TriggerHistoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedTriggers")
+	ret0, _ := ret[0].([]*moira.TriggerHistoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedTriggers indicates an expected call of GetDeletedTriggers.
+func (mr *MockDatabaseMockRecorder) GetDeletedTriggers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedTriggers", reflect.TypeOf((*MockDatabase)(nil).GetDeletedTriggers))
+}
+
+// GetDeliveryAttempts mocks base method.
+func (m *MockDatabase) GetDeliveryAttempts(arg0 moira.DeliveryAttemptsFilter) ([]*moira.DeliveryAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveryAttempts", arg0)
+	ret0, _ := ret[0].([]*moira.DeliveryAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryAttempts indicates an expected call of GetDeliveryAttempts.
+func (mr *MockDatabaseMockRecorder) GetDeliveryAttempts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryAttempts", reflect.TypeOf((*MockDatabase)(nil).GetDeliveryAttempts), arg0)
+}
+
 // GetIDByUsername mocks base method.
 func (m *MockDatabase) GetIDByUsername(arg0, arg1 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -397,6 +532,21 @@ func (mr *MockDatabaseMockRecorder) GetIDByUsername(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIDByUsername", reflect.TypeOf((*MockDatabase)(nil).GetIDByUsername), arg0, arg1)
 }
 
+// GetMessageThread mocks base method.
+func (m *MockDatabase) GetMessageThread(arg0, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessageThread", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessageThread indicates an expected call of GetMessageThread.
+func (mr *MockDatabaseMockRecorder) GetMessageThread(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessageThread", reflect.TypeOf((*MockDatabase)(nil).GetMessageThread), arg0, arg1)
+}
+
 // GetMetricRetention mocks base method.
 func (m *MockDatabase) GetMetricRetention(arg0 string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -412,6 +562,21 @@ func (mr *MockDatabaseMockRecorder) GetMetricRetention(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricRetention", reflect.TypeOf((*MockDatabase)(nil).GetMetricRetention), arg0)
 }
 
+// GetMetricStateTimeline mocks base method.
+func (m *MockDatabase) GetMetricStateTimeline(arg0, arg1 string, arg2, arg3 int64) ([]*moira.MetricStatePoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetricStateTimeline", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*moira.MetricStatePoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetricStateTimeline indicates an expected call of GetMetricStateTimeline.
+func (mr *MockDatabaseMockRecorder) GetMetricStateTimeline(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricStateTimeline", reflect.TypeOf((*MockDatabase)(nil).GetMetricStateTimeline), arg0, arg1, arg2, arg3)
+}
+
 // GetMetricsTTLSeconds mocks base method.
 func (m *MockDatabase) GetMetricsTTLSeconds() int64 {
 	m.ctrl.T.Helper()
@@ -485,6 +650,52 @@ func (mr *MockDatabaseMockRecorder) GetNotificationEvents(arg0, arg1, arg2 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationEvents", reflect.TypeOf((*MockDatabase)(nil).GetNotificationEvents), arg0, arg1, arg2)
 }
 
+// GetNotificationEventsByTime mocks base method.
+func (m *MockDatabase) GetNotificationEventsByTime(arg0 string, arg1, arg2 int64) ([]*moira.NotificationEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationEventsByTime", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*moira.NotificationEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationEventsByTime indicates an expected call of GetNotificationEventsByTime.
+func (mr *MockDatabaseMockRecorder) GetNotificationEventsByTime(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationEventsByTime", reflect.TypeOf((*MockDatabase)(nil).GetNotificationEventsByTime), arg0, arg1, arg2)
+}
+
+// GetNotificationEventsStream mocks base method.
+func (m *MockDatabase) GetNotificationEventsStream(arg0 string, arg1 int64) ([]moira.NotificationEventsStreamEntry, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationEventsStream", arg0, arg1)
+	ret0, _ := ret[0].([]moira.NotificationEventsStreamEntry)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetNotificationEventsStream indicates an expected call of GetNotificationEventsStream.
+func (mr *MockDatabaseMockRecorder) GetNotificationEventsStream(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationEventsStream", reflect.TypeOf((*MockDatabase)(nil).GetNotificationEventsStream), arg0, arg1)
+}
+
+// GetNotificationEventsStreamLastID mocks base method.
+func (m *MockDatabase) GetNotificationEventsStreamLastID() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationEventsStreamLastID")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationEventsStreamLastID indicates an expected call of GetNotificationEventsStreamLastID.
+func (mr *MockDatabaseMockRecorder) GetNotificationEventsStreamLastID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationEventsStreamLastID", reflect.TypeOf((*MockDatabase)(nil).GetNotificationEventsStreamLastID))
+}
+
 // GetNotifications mocks base method.
 func (m *MockDatabase) GetNotifications(arg0, arg1 int64) ([]*moira.ScheduledNotification, int64, error) {
 	m.ctrl.T.Helper()
@@ -516,6 +727,21 @@ func (mr *MockDatabaseMockRecorder) GetNotificationsByContactIdWithLimit(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsByContactIdWithLimit", reflect.TypeOf((*MockDatabase)(nil).GetNotificationsByContactIdWithLimit), arg0, arg1, arg2)
 }
 
+// GetNotificationsHistory mocks base method.
+func (m *MockDatabase) GetNotificationsHistory(arg0, arg1 int64) ([]*moira.NotificationEventHistoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationsHistory", arg0, arg1)
+	ret0, _ := ret[0].([]*moira.NotificationEventHistoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationsHistory indicates an expected call of GetNotificationsHistory.
+func (mr *MockDatabaseMockRecorder) GetNotificationsHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsHistory", reflect.TypeOf((*MockDatabase)(nil).GetNotificationsHistory), arg0, arg1)
+}
+
 // GetNotifierState mocks base method.
 func (m *MockDatabase) GetNotifierState() (string, error) {
 	m.ctrl.T.Helper()
@@ -696,6 +922,21 @@ func (mr *MockDatabaseMockRecorder) GetTeam(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeam", reflect.TypeOf((*MockDatabase)(nil).GetTeam), arg0)
 }
 
+// GetTeamAPITokenIDs mocks base method.
+func (m *MockDatabase) GetTeamAPITokenIDs(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamAPITokenIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamAPITokenIDs indicates an expected call of GetTeamAPITokenIDs.
+func (mr *MockDatabaseMockRecorder) GetTeamAPITokenIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamAPITokenIDs", reflect.TypeOf((*MockDatabase)(nil).GetTeamAPITokenIDs), arg0)
+}
+
 // GetTeamContactIDs mocks base method.
 func (m *MockDatabase) GetTeamContactIDs(arg0 string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -726,6 +967,36 @@ func (mr *MockDatabaseMockRecorder) GetTeamSubscriptionIDs(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamSubscriptionIDs", reflect.TypeOf((*MockDatabase)(nil).GetTeamSubscriptionIDs), arg0)
 }
 
+// GetTeamTriggerIDs mocks base method.
+func (m *MockDatabase) GetTeamTriggerIDs(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamTriggerIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamTriggerIDs indicates an expected call of GetTeamTriggerIDs.
+func (mr *MockDatabaseMockRecorder) GetTeamTriggerIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetTeamTriggerIDs), arg0)
+}
+
+// GetTeamUserRoles mocks base method.
+func (m *MockDatabase) GetTeamUserRoles(arg0 string) (map[string]moira.TeamRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamUserRoles", arg0)
+	ret0, _ := ret[0].(map[string]moira.TeamRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamUserRoles indicates an expected call of GetTeamUserRoles.
+func (mr *MockDatabaseMockRecorder) GetTeamUserRoles(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamUserRoles", reflect.TypeOf((*MockDatabase)(nil).GetTeamUserRoles), arg0)
+}
+
 // GetTeamUsers mocks base method.
 func (m *MockDatabase) GetTeamUsers(arg0 string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -786,6 +1057,36 @@ func (mr *MockDatabaseMockRecorder) GetTriggerCount(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggerCount", reflect.TypeOf((*MockDatabase)(nil).GetTriggerCount), arg0)
 }
 
+// GetTriggerHistory mocks base method.
+func (m *MockDatabase) GetTriggerHistory(arg0 string) ([]*moira.TriggerHistoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTriggerHistory", arg0)
+	ret0, _ := ret[0].([]*moira.TriggerHistoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTriggerHistory indicates an expected call of GetTriggerHistory.
+func (mr *MockDatabaseMockRecorder) GetTriggerHistory(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggerHistory", reflect.TypeOf((*MockDatabase)(nil).GetTriggerHistory), arg0)
+}
+
+// GetTriggerHistoryItem mocks base method.
+func (m *MockDatabase) GetTriggerHistoryItem(arg0 string, arg1 int64) (moira.TriggerHistoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTriggerHistoryItem", arg0, arg1)
+	ret0, _ := ret[0].(moira.TriggerHistoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTriggerHistoryItem indicates an expected call of GetTriggerHistoryItem.
+func (mr *MockDatabaseMockRecorder) GetTriggerHistoryItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggerHistoryItem", reflect.TypeOf((*MockDatabase)(nil).GetTriggerHistoryItem), arg0, arg1)
+}
+
 // GetTriggerIDs mocks base method.
 func (m *MockDatabase) GetTriggerIDs(arg0 moira.ClusterKey) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -922,6 +1223,21 @@ func (mr *MockDatabaseMockRecorder) GetUnusedTriggerIDs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnusedTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetUnusedTriggerIDs))
 }
 
+// GetUserAPITokenIDs mocks base method.
+func (m *MockDatabase) GetUserAPITokenIDs(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAPITokenIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAPITokenIDs indicates an expected call of GetUserAPITokenIDs.
+func (mr *MockDatabaseMockRecorder) GetUserAPITokenIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAPITokenIDs", reflect.TypeOf((*MockDatabase)(nil).GetUserAPITokenIDs), arg0)
+}
+
 // GetUserContactIDs mocks base method.
 func (m *MockDatabase) GetUserContactIDs(arg0 string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -1061,6 +1377,20 @@ func (mr *MockDatabaseMockRecorder) PushContactNotificationToHistory(arg0 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushContactNotificationToHistory", reflect.TypeOf((*MockDatabase)(nil).PushContactNotificationToHistory), arg0)
 }
 
+// PushMetricStatesTimeline mocks base method.
+func (m *MockDatabase) PushMetricStatesTimeline(arg0 string, arg1 map[string][]moira.MetricStatePoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushMetricStatesTimeline", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PushMetricStatesTimeline indicates an expected call of PushMetricStatesTimeline.
+func (mr *MockDatabaseMockRecorder) PushMetricStatesTimeline(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushMetricStatesTimeline", reflect.TypeOf((*MockDatabase)(nil).PushMetricStatesTimeline), arg0, arg1)
+}
+
 // PushNotificationEvent mocks base method.
 func (m *MockDatabase) PushNotificationEvent(arg0 *moira.NotificationEvent, arg1 bool) error {
 	m.ctrl.T.Helper()
@@ -1087,6 +1417,20 @@ func (mr *MockDatabaseMockRecorder) ReleaseTriggerCheckLock(arg0 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseTriggerCheckLock", reflect.TypeOf((*MockDatabase)(nil).ReleaseTriggerCheckLock), arg0)
 }
 
+// RemoveAPIToken mocks base method.
+func (m *MockDatabase) RemoveAPIToken(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAPIToken", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAPIToken indicates an expected call of RemoveAPIToken.
+func (mr *MockDatabaseMockRecorder) RemoveAPIToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAPIToken", reflect.TypeOf((*MockDatabase)(nil).RemoveAPIToken), arg0)
+}
+
 // RemoveAllMetrics mocks base method.
 func (m *MockDatabase) RemoveAllMetrics() error {
 	m.ctrl.T.Helper()
@@ -1143,6 +1487,34 @@ func (mr *MockDatabaseMockRecorder) RemoveContact(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveContact", reflect.TypeOf((*MockDatabase)(nil).RemoveContact), arg0)
 }
 
+// RemoveDeadLetter mocks base method.
+func (m *MockDatabase) RemoveDeadLetter(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDeadLetter", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveDeadLetter indicates an expected call of RemoveDeadLetter.
+func (mr *MockDatabaseMockRecorder) RemoveDeadLetter(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDeadLetter", reflect.TypeOf((*MockDatabase)(nil).RemoveDeadLetter), arg0)
+}
+
+// RemoveMessageThread mocks base method.
+func (m *MockDatabase) RemoveMessageThread(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMessageThread", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMessageThread indicates an expected call of RemoveMessageThread.
+func (mr *MockDatabaseMockRecorder) RemoveMessageThread(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMessageThread", reflect.TypeOf((*MockDatabase)(nil).RemoveMessageThread), arg0, arg1)
+}
+
 // RemoveMetricRetention mocks base method.
 func (m *MockDatabase) RemoveMetricRetention(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -1300,17 +1672,17 @@ func (mr *MockDatabaseMockRecorder) RemoveTag(arg0 interface{}) *gomock.Call {
 }
 
 // RemoveTrigger mocks base method.
-func (m *MockDatabase) RemoveTrigger(arg0 string) error {
+func (m *MockDatabase) RemoveTrigger(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveTrigger", arg0)
+	ret := m.ctrl.Call(m, "RemoveTrigger", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveTrigger indicates an expected call of RemoveTrigger.
-func (mr *MockDatabaseMockRecorder) RemoveTrigger(arg0 interface{}) *gomock.Call {
+func (mr *MockDatabaseMockRecorder) RemoveTrigger(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTrigger", reflect.TypeOf((*MockDatabase)(nil).RemoveTrigger), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTrigger", reflect.TypeOf((*MockDatabase)(nil).RemoveTrigger), arg0, arg1)
 }
 
 // RemoveTriggerLastCheck mocks base method.
@@ -1355,6 +1727,34 @@ func (mr *MockDatabaseMockRecorder) RemoveUser(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUser", reflect.TypeOf((*MockDatabase)(nil).RemoveUser), arg0, arg1)
 }
 
+// SaveAPIToken mocks base method.
+func (m *MockDatabase) SaveAPIToken(arg0 *moira.APIToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAPIToken", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAPIToken indicates an expected call of SaveAPIToken.
+func (mr *MockDatabaseMockRecorder) SaveAPIToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAPIToken", reflect.TypeOf((*MockDatabase)(nil).SaveAPIToken), arg0)
+}
+
+// SaveAuditRecord mocks base method.
+func (m *MockDatabase) SaveAuditRecord(arg0 *moira.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAuditRecord", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAuditRecord indicates an expected call of SaveAuditRecord.
+func (mr *MockDatabaseMockRecorder) SaveAuditRecord(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAuditRecord", reflect.TypeOf((*MockDatabase)(nil).SaveAuditRecord), arg0)
+}
+
 // SaveContact mocks base method.
 func (m *MockDatabase) SaveContact(arg0 *moira.ContactData) error {
 	m.ctrl.T.Helper()
@@ -1369,6 +1769,34 @@ func (mr *MockDatabaseMockRecorder) SaveContact(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveContact", reflect.TypeOf((*MockDatabase)(nil).SaveContact), arg0)
 }
 
+// SaveDeadLetter mocks base method.
+func (m *MockDatabase) SaveDeadLetter(arg0 *moira.DeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeadLetter", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDeadLetter indicates an expected call of SaveDeadLetter.
+func (mr *MockDatabaseMockRecorder) SaveDeadLetter(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeadLetter", reflect.TypeOf((*MockDatabase)(nil).SaveDeadLetter), arg0)
+}
+
+// SaveDeliveryAttempt mocks base method.
+func (m *MockDatabase) SaveDeliveryAttempt(arg0 *moira.DeliveryAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeliveryAttempt", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDeliveryAttempt indicates an expected call of SaveDeliveryAttempt.
+func (mr *MockDatabaseMockRecorder) SaveDeliveryAttempt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeliveryAttempt", reflect.TypeOf((*MockDatabase)(nil).SaveDeliveryAttempt), arg0)
+}
+
 // SaveMetrics mocks base method.
 func (m *MockDatabase) SaveMetrics(arg0 map[string]*moira.MatchedMetric) error {
 	m.ctrl.T.Helper()
@@ -1425,6 +1853,20 @@ func (mr *MockDatabaseMockRecorder) SaveTeam(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTeam", reflect.TypeOf((*MockDatabase)(nil).SaveTeam), arg0, arg1)
 }
 
+// SaveTeamUserRoles mocks base method.
+func (m *MockDatabase) SaveTeamUserRoles(arg0 string, arg1 map[string]moira.TeamRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTeamUserRoles", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTeamUserRoles indicates an expected call of SaveTeamUserRoles.
+func (mr *MockDatabaseMockRecorder) SaveTeamUserRoles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTeamUserRoles", reflect.TypeOf((*MockDatabase)(nil).SaveTeamUserRoles), arg0, arg1)
+}
+
 // SaveTeamsAndUsers mocks base method.
 func (m *MockDatabase) SaveTeamsAndUsers(arg0 string, arg1 []string, arg2 map[string][]string) error {
 	m.ctrl.T.Helper()
@@ -1467,6 +1909,34 @@ func (mr *MockDatabaseMockRecorder) SaveTriggersSearchResults(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTriggersSearchResults", reflect.TypeOf((*MockDatabase)(nil).SaveTriggersSearchResults), arg0, arg1)
 }
 
+// SetAnalyticsReportSentAt mocks base method.
+func (m *MockDatabase) SetAnalyticsReportSentAt(arg0 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAnalyticsReportSentAt", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAnalyticsReportSentAt indicates an expected call of SetAnalyticsReportSentAt.
+func (mr *MockDatabaseMockRecorder) SetAnalyticsReportSentAt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAnalyticsReportSentAt", reflect.TypeOf((*MockDatabase)(nil).SetAnalyticsReportSentAt), arg0)
+}
+
+// SetMessageThread mocks base method.
+func (m *MockDatabase) SetMessageThread(arg0, arg1, arg2 string, arg3 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMessageThread", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMessageThread indicates an expected call of SetMessageThread.
+func (mr *MockDatabaseMockRecorder) SetMessageThread(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMessageThread", reflect.TypeOf((*MockDatabase)(nil).SetMessageThread), arg0, arg1, arg2, arg3)
+}
+
 // SetNotifierState mocks base method.
 func (m *MockDatabase) SetNotifierState(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -1567,6 +2037,20 @@ func (mr *MockDatabaseMockRecorder) SubscribeMetricEvents(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeMetricEvents", reflect.TypeOf((*MockDatabase)(nil).SubscribeMetricEvents), arg0, arg1)
 }
 
+// SyncUserGroupTeams mocks base method.
+func (m *MockDatabase) SyncUserGroupTeams(arg0 string, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncUserGroupTeams", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncUserGroupTeams indicates an expected call of SyncUserGroupTeams.
+func (mr *MockDatabaseMockRecorder) SyncUserGroupTeams(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncUserGroupTeams", reflect.TypeOf((*MockDatabase)(nil).SyncUserGroupTeams), arg0, arg1)
+}
+
 // UpdateMetricsHeartbeat mocks base method.
 func (m *MockDatabase) UpdateMetricsHeartbeat() error {
 	m.ctrl.T.Helper()
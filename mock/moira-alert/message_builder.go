@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/moira-alert/moira (interfaces: MessageBuilder)
+
+// Package mock_moira_alert is a generated GoMock package.
+package mock_moira_alert
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	moira "github.com/moira-alert/moira"
+)
+
+// MockMessageBuilder is a mock of MessageBuilder interface.
+type MockMessageBuilder struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageBuilderMockRecorder
+}
+
+// MockMessageBuilderMockRecorder is the mock recorder for MockMessageBuilder.
+type MockMessageBuilderMockRecorder struct {
+	mock *MockMessageBuilder
+}
+
+// NewMockMessageBuilder creates a new mock instance.
+func NewMockMessageBuilder(ctrl *gomock.Controller) *MockMessageBuilder {
+	mock := &MockMessageBuilder{ctrl: ctrl}
+	mock.recorder = &MockMessageBuilderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageBuilder) EXPECT() *MockMessageBuilderMockRecorder {
+	return m.recorder
+}
+
+// BuildMessage mocks base method.
+func (m *MockMessageBuilder) BuildMessage(arg0 moira.NotificationEvents, arg1 moira.ContactData, arg2 moira.TriggerData, arg3 [][]byte, arg4 bool) (moira.NotificationPayload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildMessage", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(moira.NotificationPayload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuildMessage indicates an expected call of BuildMessage.
+func (mr *MockMessageBuilderMockRecorder) BuildMessage(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildMessage", reflect.TypeOf((*MockMessageBuilder)(nil).BuildMessage), arg0, arg1, arg2, arg3, arg4)
+}
+
+// InitMessageBuilder mocks base method.
+func (m *MockMessageBuilder) InitMessageBuilder(arg0 interface{}, arg1 moira.Logger, arg2 *time.Location, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InitMessageBuilder", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InitMessageBuilder indicates an expected call of InitMessageBuilder.
+func (mr *MockMessageBuilderMockRecorder) InitMessageBuilder(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitMessageBuilder", reflect.TypeOf((*MockMessageBuilder)(nil).InitMessageBuilder), arg0, arg1, arg2, arg3)
+}
@@ -51,6 +51,22 @@ func (mr *MockClientMockRecorder) CreatePost(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockClient)(nil).CreatePost), arg0, arg1)
 }
 
+// PatchPost mocks base method.
+func (m *MockClient) PatchPost(arg0 context.Context, arg1 string, arg2 *model.PostPatch) (*model.Post, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchPost", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Post)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PatchPost indicates an expected call of PatchPost.
+func (mr *MockClientMockRecorder) PatchPost(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchPost", reflect.TypeOf((*MockClient)(nil).PatchPost), arg0, arg1, arg2)
+}
+
 // SetToken mocks base method.
 func (m *MockClient) SetToken(arg0 string) {
 	m.ctrl.T.Helper()
@@ -48,3 +48,17 @@ func (mr *MockSchedulerMockRecorder) ScheduleNotification(arg0, arg1, arg2, arg3
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleNotification", reflect.TypeOf((*MockScheduler)(nil).ScheduleNotification), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 }
+
+// ScheduleRetry mocks base method.
+func (m *MockScheduler) ScheduleRetry(arg0 time.Time, arg1 time.Duration, arg2 moira.NotificationEvent, arg3 moira.TriggerData, arg4 moira.ContactData, arg5 moira.PlottingData, arg6 bool, arg7 int, arg8 moira.Logger) *moira.ScheduledNotification {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleRetry", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
+	ret0, _ := ret[0].(*moira.ScheduledNotification)
+	return ret0
+}
+
+// ScheduleRetry indicates an expected call of ScheduleRetry.
+func (mr *MockSchedulerMockRecorder) ScheduleRetry(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleRetry", reflect.TypeOf((*MockScheduler)(nil).ScheduleRetry), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
+}
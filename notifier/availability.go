@@ -0,0 +1,109 @@
+package notifier
+
+import (
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+// doNotDisturbRecheckInterval is the delay of notifications to contact with Do Not Disturb without end.
+const doNotDisturbRecheckInterval = 15 * time.Minute
+
+// getContactUnavailability checks whether owner of contact is unavailable and returns time notifications should be delayed until.
+// Notifications are not delayed if any of events is ERROR of critical trigger.
+func getContactUnavailability(now time.Time, events []moira.NotificationEvent, trigger moira.TriggerData, contact moira.ContactData) (time.Time, bool) {
+	for _, event := range events {
+		if moira.IsCriticalNotification(event, trigger) {
+			return time.Time{}, false
+		}
+	}
+
+	until, unavailable := contact.Availability.UnavailableUntil(now)
+	if unavailable && until.IsZero() {
+		until = now.Add(doNotDisturbRecheckInterval)
+	}
+	return until, unavailable
+}
+
+// getBackupContact returns backup contact of unavailable contact if backup contact is available itself.
+func getBackupContact(database moira.Database, now time.Time, contact moira.ContactData) (moira.ContactData, bool, error) {
+	backupID := contact.Availability.BackupContact
+	if backupID == "" || backupID == contact.ID {
+		return moira.ContactData{}, false, nil
+	}
+
+	backup, err := database.GetContact(backupID)
+	if err != nil {
+		return moira.ContactData{}, false, err
+	}
+	if _, unavailable := backup.Availability.UnavailableUntil(now); unavailable {
+		return moira.ContactData{}, false, nil
+	}
+	return backup, true, nil
+}
+
+// applyContactAvailability delays notification or redirects it to backup contact while owner of contact is unavailable.
+func (scheduler *StandardScheduler) applyContactAvailability(next time.Time, event moira.NotificationEvent, trigger moira.TriggerData,
+	contact moira.ContactData, logger moira.Logger,
+) (time.Time, moira.ContactData) {
+	until, unavailable := getContactUnavailability(next, []moira.NotificationEvent{event}, trigger, contact)
+	if !unavailable {
+		return next, contact
+	}
+
+	backup, ok, err := getBackupContact(scheduler.database, next, contact)
+	if err != nil {
+		logger.Warning().
+			String("backup_contact_id", contact.Availability.BackupContact).
+			Error(err).
+			Msg("Failed to get backup contact")
+	}
+	if ok {
+		logger.Debug().
+			String("backup_contact_id", backup.ID).
+			Msg("Contact is unavailable, notification is redirected to backup contact")
+		return next, backup
+	}
+
+	logger.Debug().
+		String("unavailable_until", until.Format("2006/01/02 15:04:05")).
+		Msg("Contact is unavailable, notification is delayed")
+	return until, contact
+}
+
+// holdForUnavailableContact checks availability of contact right before sending, as it may be changed after package was scheduled.
+// Returns true if package was postponed or redirected to backup contact.
+func (notifier *StandardNotifier) holdForUnavailableContact(pkg *NotificationPackage) bool {
+	logger := getLogWithPackageContext(&notifier.logger, pkg, &notifier.config)
+
+	contact, err := notifier.database.GetContact(pkg.Contact.ID)
+	if err != nil {
+		logger.Warning().
+			Error(err).
+			Msg("Failed to get actual availability of contact")
+		contact = pkg.Contact
+	}
+
+	now := time.Now()
+	until, unavailable := getContactUnavailability(now, pkg.Events, pkg.Trigger, contact)
+	if !unavailable {
+		return false
+	}
+
+	backup, ok, err := getBackupContact(notifier.database, now, contact)
+	if err != nil {
+		logger.Warning().
+			String("backup_contact_id", contact.Availability.BackupContact).
+			Error(err).
+			Msg("Failed to get backup contact")
+	}
+	if ok {
+		notifier.redirect(pkg, backup, logger.Clone().String("backup_contact_id", backup.ID))
+		logger.Info().
+			Msg("Contact is unavailable, notifications are redirected to backup contact")
+		return true
+	}
+
+	notifier.postpone(pkg, until.Sub(now), "contact is unavailable")
+	return true
+}
@@ -0,0 +1,137 @@
+package notifier
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	"github.com/moira-alert/moira/metrics"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestApplyContactAvailability(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	database := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Scheduler")
+	scheduler := NewScheduler(database, logger, metrics.ConfigureNotifierMetrics(metrics.NewDummyRegistry(), "notifier"))
+
+	now := time.Now()
+	trigger := moira.TriggerData{ID: "triggerID-0000000000001", Tags: []string{moira.CriticalTriggerTag}}
+	vacation := &moira.ContactAvailability{
+		Vacations: []moira.AvailabilityPeriod{{From: now.Add(-time.Hour).Unix(), To: now.Add(time.Hour).Unix()}},
+	}
+	contact := moira.ContactData{ID: "contact-id", Type: "mail", Value: "owner@example.com", Availability: vacation}
+
+	Convey("Test applyContactAvailability", t, func() {
+		Convey("Available contact gets notification in time", func() {
+			next, actual := scheduler.applyContactAvailability(now, event, trigger, moira.ContactData{ID: "contact-id"}, logger)
+			So(next, ShouldEqual, now)
+			So(actual, ShouldResemble, moira.ContactData{ID: "contact-id"})
+		})
+
+		Convey("Notification to unavailable contact is delayed", func() {
+			next, actual := scheduler.applyContactAvailability(now, event, trigger, contact, logger)
+			So(next.Unix(), ShouldEqual, now.Add(time.Hour).Unix())
+			So(actual, ShouldResemble, contact)
+		})
+
+		Convey("Do Not Disturb without end delays notification for recheck interval", func() {
+			dnd := contact
+			dnd.Availability = &moira.ContactAvailability{DoNotDisturb: true}
+			next, _ := scheduler.applyContactAvailability(now, event, trigger, dnd, logger)
+			So(next, ShouldEqual, now.Add(doNotDisturbRecheckInterval))
+		})
+
+		Convey("ERROR of critical trigger is not delayed", func() {
+			errorEvent := event
+			errorEvent.State = moira.StateERROR
+			next, actual := scheduler.applyContactAvailability(now, errorEvent, trigger, contact, logger)
+			So(next, ShouldEqual, now)
+			So(actual, ShouldResemble, contact)
+		})
+
+		Convey("Notification is redirected to backup contact", func() {
+			withBackup := contact
+			withBackup.Availability = &moira.ContactAvailability{Vacations: vacation.Vacations, BackupContact: "backup-id"}
+			backup := moira.ContactData{ID: "backup-id", Type: "mail", Value: "backup@example.com"}
+			database.EXPECT().GetContact("backup-id").Return(backup, nil)
+
+			next, actual := scheduler.applyContactAvailability(now, event, trigger, withBackup, logger)
+			So(next, ShouldEqual, now)
+			So(actual, ShouldResemble, backup)
+
+			Convey("Notification is delayed if backup contact is unavailable too", func() {
+				backup.Availability = vacation
+				database.EXPECT().GetContact("backup-id").Return(backup, nil)
+
+				next, actual = scheduler.applyContactAvailability(now, event, trigger, withBackup, logger)
+				So(next.Unix(), ShouldEqual, now.Add(time.Hour).Unix())
+				So(actual, ShouldResemble, withBackup)
+			})
+		})
+	})
+}
+
+func TestHoldForUnavailableContact(t *testing.T) {
+	configureNotifier(t, defaultConfig)
+	defer afterTest()
+
+	Convey("Package to contact which became unavailable after scheduling is held", t, func() {
+		pkg := NotificationPackage{
+			Events:  []moira.NotificationEvent{event},
+			Contact: moira.ContactData{ID: "contact-id", Type: "test_contact_type"},
+		}
+		actual := pkg.Contact
+		actual.Availability = &moira.ContactAvailability{DoNotDisturb: true, DoNotDisturbUntil: time.Now().Add(time.Hour).Unix()}
+
+		Convey("Package is postponed", func() {
+			postponed := make(chan *moira.ScheduledNotification, 1)
+			dataBase.EXPECT().GetContact(pkg.Contact.ID).Return(actual, nil)
+			dataBase.EXPECT().AddNotification(gomock.Any()).Return(nil).Do(func(notification *moira.ScheduledNotification) {
+				postponed <- notification
+			})
+
+			var wg sync.WaitGroup
+			standardNotifier.Send(&pkg, &wg)
+			wg.Wait()
+
+			notification := <-postponed
+			So(notification.Contact, ShouldResemble, pkg.Contact)
+			So(notification.Timestamp, ShouldBeGreaterThanOrEqualTo, actual.Availability.DoNotDisturbUntil)
+		})
+
+		Convey("Package is redirected to backup contact", func() {
+			actual.Availability.BackupContact = "backup-id"
+			backup := moira.ContactData{ID: "backup-id", Type: "test_contact_type", Value: "backup"}
+			notification := moira.ScheduledNotification{}
+			redirected := make(chan struct{})
+			dataBase.EXPECT().GetContact(pkg.Contact.ID).Return(actual, nil)
+			dataBase.EXPECT().GetContact("backup-id").Return(backup, nil)
+			scheduler.EXPECT().ScheduleRetry(gomock.Any(), time.Duration(0), event, pkg.Trigger, backup, pkg.Plotting, false, 0, gomock.Any()).Return(&notification)
+			dataBase.EXPECT().AddNotification(&notification).Return(nil).Do(func(*moira.ScheduledNotification) { close(redirected) })
+
+			var wg sync.WaitGroup
+			standardNotifier.Send(&pkg, &wg)
+			wg.Wait()
+			<-redirected
+		})
+
+		Convey("Package is sent if contact is available", func() {
+			sent := make(chan struct{})
+			dataBase.EXPECT().GetContact(pkg.Contact.ID).Return(pkg.Contact, nil)
+			dataBase.EXPECT().SaveDeliveryAttempt(gomock.Any()).Return(nil).Do(func(*moira.DeliveryAttempt) { close(sent) })
+			sender.EXPECT().SendEvents(gomock.Any(), pkg.Contact, pkg.Trigger, gomock.Any(), false).Return(nil)
+
+			var wg sync.WaitGroup
+			standardNotifier.Send(&pkg, &wg)
+			wg.Wait()
+			<-sent
+		})
+	})
+}
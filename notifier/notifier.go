@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/logging"
 	metricSource "github.com/moira-alert/moira/metric_source"
@@ -75,6 +76,8 @@ type Notifier interface {
 type StandardNotifier struct {
 	waitGroup            sync.WaitGroup
 	senders              map[string]chan NotificationPackage
+	senderTypes          map[string]string
+	retryPolicies        map[string]retryPolicy
 	logger               moira.Logger
 	database             moira.Database
 	scheduler            Scheduler
@@ -88,6 +91,8 @@ type StandardNotifier struct {
 func NewNotifier(database moira.Database, logger moira.Logger, config Config, metrics *metrics.NotifierMetrics, metricSourceProvider *metricSource.SourceProvider, imageStoreMap map[string]moira.ImageStore) *StandardNotifier {
 	return &StandardNotifier{
 		senders:              make(map[string]chan NotificationPackage),
+		senderTypes:          make(map[string]string),
+		retryPolicies:        make(map[string]retryPolicy),
 		logger:               logger,
 		database:             database,
 		scheduler:            NewScheduler(database, logger, metrics),
@@ -102,7 +107,9 @@ func NewNotifier(database moira.Database, logger moira.Logger, config Config, me
 func (notifier *StandardNotifier) Send(pkg *NotificationPackage, waitGroup *sync.WaitGroup) {
 	ch, found := notifier.senders[pkg.Contact.Type]
 	if !found {
-		notifier.reschedule(pkg, fmt.Sprintf("Unknown sender contact type '%s' [%s]", pkg.Contact.Type, pkg))
+		reason := fmt.Sprintf("Unknown sender contact type '%s' [%s]", pkg.Contact.Type, pkg)
+		status, deadLetterID := notifier.reschedule(pkg, reason)
+		notifier.saveDeliveryAttempt(pkg, status, reason, 0, deadLetterID)
 		return
 	}
 	waitGroup.Add(1)
@@ -117,7 +124,9 @@ func (notifier *StandardNotifier) Send(pkg *NotificationPackage, waitGroup *sync
 		case ch <- *pkg:
 			break
 		case <-time.After(notifier.config.SendingTimeout):
-			notifier.reschedule(pkg, fmt.Sprintf("Timeout sending %s", pkg))
+			reason := fmt.Sprintf("Timeout sending %s", pkg)
+			status, deadLetterID := notifier.reschedule(pkg, reason)
+			notifier.saveDeliveryAttempt(pkg, status, reason, notifier.config.SendingTimeout, deadLetterID)
 			break
 		}
 	}(pkg)
@@ -137,10 +146,12 @@ func (notifier *StandardNotifier) GetReadBatchSize() int64 {
 	return notifier.config.ReadBatchSize
 }
 
-func (notifier *StandardNotifier) reschedule(pkg *NotificationPackage, reason string) {
+// reschedule schedules failed notification package to be sent again according to retry policy of sender.
+// Package is dropped if it must not be resent and moved to dead letters if retries are exhausted.
+func (notifier *StandardNotifier) reschedule(pkg *NotificationPackage, reason string) (status moira.DeliveryStatus, deadLetterID string) {
 	if pkg.DontResend {
 		notifier.metrics.MarkSendersDroppedNotifications(pkg.Contact.Type)
-		return
+		return moira.DeliveryStatusDropped, ""
 	}
 
 	notifier.metrics.MarkSendingFailed()
@@ -148,23 +159,29 @@ func (notifier *StandardNotifier) reschedule(pkg *NotificationPackage, reason st
 
 	logger := getLogWithPackageContext(&notifier.logger, pkg, &notifier.config)
 
-	if notifier.needToStop(pkg.FailCount) {
+	policy := notifier.getRetryPolicy(pkg.Contact.Type)
+	if policy.isExhausted(pkg.FailCount, notifier.config.ResendingTimeout) {
 		notifier.metrics.MarkSendersDroppedNotifications(pkg.Contact.Type)
 		logger.Error().
-			Msg("Stop resending. Notification interval is timed out")
-		return
+			Int("number_of_retries", pkg.FailCount).
+			Msg("Stop resending. Retries are exhausted")
+		deadLetterID = notifier.saveDeadLetter(pkg, reason)
+		notifier.sendToFallbackContact(pkg, policy)
+		return moira.DeliveryStatusDropped, deadLetterID
 	}
 
+	delay := policy.delay(pkg.FailCount + 1)
 	logger.Warning().
 		Int("number_of_retries", pkg.FailCount).
 		String("reason", reason).
-		Msg("Can't send message. Retry again in 1 min")
+		String("retry_in", delay.String()).
+		Msg("Can't send message. Retry again later")
 
 	for _, event := range pkg.Events {
 		subID := moira.UseString(event.SubscriptionID)
 		eventLogger := logger.Clone().String(moira.LogFieldNameSubscriptionID, subID)
 		SetLogLevelByConfig(notifier.config.LogSubscriptionsToLevel, subID, &eventLogger)
-		notification := notifier.scheduler.ScheduleNotification(time.Now(), event,
+		notification := notifier.scheduler.ScheduleRetry(time.Now(), delay, event,
 			pkg.Trigger, pkg.Contact, pkg.Plotting, pkg.Throttled, pkg.FailCount+1, eventLogger)
 		if err := notifier.database.AddNotification(notification); err != nil {
 			eventLogger.Error().
@@ -172,9 +189,163 @@ func (notifier *StandardNotifier) reschedule(pkg *NotificationPackage, reason st
 				Msg("Failed to save scheduled notification")
 		}
 	}
+	return moira.DeliveryStatusFailed, ""
+}
+
+// drop moves notification package which can't be delivered to dead letters without retrying.
+func (notifier *StandardNotifier) drop(pkg *NotificationPackage, reason string, latency time.Duration) {
+	notifier.metrics.MarkSendersDroppedNotifications(pkg.Contact.Type)
+	deadLetterID := notifier.saveDeadLetter(pkg, reason)
+	notifier.saveDeliveryAttempt(pkg, moira.DeliveryStatusDropped, reason, latency, deadLetterID)
+	notifier.sendToFallbackContact(pkg, notifier.getRetryPolicy(pkg.Contact.Type))
+}
+
+// sendToFallbackContact schedules notifications of package notifier gave up on to fallback contact of sender.
+// Packages of fallback contacts are not sent to fallback contacts to avoid loops.
+func (notifier *StandardNotifier) sendToFallbackContact(pkg *NotificationPackage, policy retryPolicy) {
+	if policy.fallbackContact == "" || pkg.DontResend || notifier.isFallbackContact(pkg.Contact.ID) {
+		return
+	}
+
+	logger := getLogWithPackageContext(&notifier.logger, pkg, &notifier.config).
+		String("fallback_contact_id", policy.fallbackContact)
+	contact, err := notifier.database.GetContact(policy.fallbackContact)
+	if err != nil {
+		logger.Error().
+			Error(err).
+			Msg("Failed to get fallback contact")
+		return
+	}
+
+	notifier.redirect(pkg, contact, logger)
+	logger.Info().
+		Msg("Notifications are sent to fallback contact")
+}
+
+// redirect schedules notifications of package to be sent to another contact right away.
+func (notifier *StandardNotifier) redirect(pkg *NotificationPackage, contact moira.ContactData, logger moira.Logger) {
+	now := time.Now()
+	for _, event := range pkg.Events {
+		eventLogger := logger.Clone().String(moira.LogFieldNameSubscriptionID, moira.UseString(event.SubscriptionID))
+		notification := notifier.scheduler.ScheduleRetry(now, 0, event,
+			pkg.Trigger, contact, pkg.Plotting, pkg.Throttled, 0, eventLogger)
+		if err := notifier.database.AddNotification(notification); err != nil {
+			eventLogger.Error().
+				Error(err).
+				Msg("Failed to save redirected notification")
+		}
+	}
+}
+
+func (notifier *StandardNotifier) isFallbackContact(contactID string) bool {
+	for _, policy := range notifier.retryPolicies {
+		if policy.fallbackContact != "" && policy.fallbackContact == contactID {
+			return true
+		}
+	}
+	return false
+}
+
+func (notifier *StandardNotifier) getRetryPolicy(contactType string) retryPolicy {
+	if policy, ok := notifier.retryPolicies[contactType]; ok {
+		return policy
+	}
+	return defaultRetryPolicy
+}
+
+// saveDeadLetter saves dropped notification package to dead letters so it can be inspected and replayed later.
+// Returns id of saved dead letter or empty string if package was not saved.
+func (notifier *StandardNotifier) saveDeadLetter(pkg *NotificationPackage, reason string) string {
+	logger := getLogWithPackageContext(&notifier.logger, pkg, &notifier.config)
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		logger.Warning().
+			Error(err).
+			Msg("Failed to generate dead letter id")
+		return ""
+	}
+
+	letter := &moira.DeadLetter{
+		ID:        id.String(),
+		Timestamp: time.Now().Unix(),
+		Reason:    reason,
+		Events:    pkg.Events,
+		Trigger:   pkg.Trigger,
+		Contact:   pkg.Contact,
+		Plotting:  pkg.Plotting,
+		Throttled: pkg.Throttled,
+		FailCount: pkg.FailCount,
+	}
+	if err = notifier.database.SaveDeadLetter(letter); err != nil {
+		logger.Warning().
+			Error(err).
+			Msg("Failed to save dead letter")
+		return ""
+	}
+	return letter.ID
+}
+
+// saveDeliveryAttempt saves result of sending notification package to delivery log.
+func (notifier *StandardNotifier) saveDeliveryAttempt(pkg *NotificationPackage, status moira.DeliveryStatus, reason string, latency time.Duration, deadLetterID string) {
+	attempt := &moira.DeliveryAttempt{
+		Timestamp:    time.Now().Unix(),
+		ContactID:    pkg.Contact.ID,
+		ContactType:  pkg.Contact.Type,
+		SenderType:   notifier.senderTypes[pkg.Contact.Type],
+		TriggerID:    pkg.Trigger.ID,
+		EventsCount:  len(pkg.Events),
+		FailCount:    pkg.FailCount,
+		Status:       status,
+		Error:        reason,
+		LatencyMs:    latency.Milliseconds(),
+		DeadLetterID: deadLetterID,
+	}
+	if err := notifier.database.SaveDeliveryAttempt(attempt); err != nil {
+		getLogWithPackageContext(&notifier.logger, pkg, &notifier.config).
+			Warning().
+			Error(err).
+			Msg("Failed to save delivery attempt")
+	}
+}
+
+// postpone schedules notification package to be sent later without counting it as failed attempt.
+// Postponed notifications are fetched together with newer notifications of the same contact and trigger,
+// so they are coalesced into single package instead of being dropped.
+func (notifier *StandardNotifier) postpone(pkg *NotificationPackage, delay time.Duration, reason string) {
+	now := time.Now()
+	next := now.Add(delay).Truncate(time.Second)
+	if !next.After(now) {
+		next = next.Add(time.Second)
+	}
+
+	logger := getLogWithPackageContext(&notifier.logger, pkg, &notifier.config)
+	logger.Debug().
+		String("reason", reason).
+		String("postponed_until", next.Format("2006/01/02 15:04:05")).
+		Msg("Postpone sending")
+
+	for _, event := range pkg.Events {
+		notification := &moira.ScheduledNotification{
+			Event:     event,
+			Trigger:   pkg.Trigger,
+			Contact:   pkg.Contact,
+			Plotting:  pkg.Plotting,
+			Throttled: pkg.Throttled,
+			SendFail:  pkg.FailCount,
+			Timestamp: next.Unix(),
+			CreatedAt: now.Unix(),
+		}
+		if err := notifier.database.AddNotification(notification); err != nil {
+			logger.Error().
+				String(moira.LogFieldNameSubscriptionID, moira.UseString(event.SubscriptionID)).
+				Error(err).
+				Msg("Failed to save postponed notification")
+		}
+	}
 }
 
-func (notifier *StandardNotifier) runSender(sender moira.Sender, ch chan NotificationPackage) {
+func (notifier *StandardNotifier) runSender(sender moira.Sender, guard *senderGuard, ch chan NotificationPackage) {
 	defer func() {
 		if err := recover(); err != nil {
 			notifier.logger.Error().
@@ -187,6 +358,18 @@ func (notifier *StandardNotifier) runSender(sender moira.Sender, ch chan Notific
 
 	for pkg := range ch {
 		log := getLogWithPackageContext(&notifier.logger, &pkg, &notifier.config)
+		// Self state packages are not stored, so they can't be postponed.
+		if !pkg.DontResend {
+			if notifier.holdForUnavailableContact(&pkg) {
+				continue
+			}
+			if ok, delay, reason := guard.allow(pkg.Contact.ID, time.Now()); !ok {
+				notifier.metrics.MarkSendersPostponedNotifications(pkg.Contact.Type)
+				notifier.postpone(&pkg, delay, reason)
+				continue
+			}
+		}
+
 		plottingLog := log.Clone().String(moira.LogFieldNameContext, "plotting")
 		plots, err := notifier.buildNotificationPackagePlots(pkg, plottingLog)
 		if err != nil {
@@ -210,9 +393,18 @@ func (notifier *StandardNotifier) runSender(sender moira.Sender, ch chan Notific
 				Msg("Error populate description")
 		}
 
+		sendingStart := time.Now()
 		err = sender.SendEvents(pkg.Events, pkg.Contact, pkg.Trigger, plots, pkg.Throttled)
+		latency := time.Since(sendingStart)
+		if guard.done(err == nil || isContactError(err), time.Now()) {
+			log.Warning().
+				Error(err).
+				Msg("Sender circuit is opened after consecutive failures")
+			notifier.metrics.MarkSendersOpenedCircuits(pkg.Contact.Type)
+		}
 		if err == nil {
 			notifier.metrics.MarkSendersOkMetrics(pkg.Contact.Type)
+			notifier.saveDeliveryAttempt(&pkg, moira.DeliveryStatusOK, "", latency, "")
 			continue
 		}
 		switch e := err.(type) { // nolint:errorlint
@@ -220,7 +412,12 @@ func (notifier *StandardNotifier) runSender(sender moira.Sender, ch chan Notific
 			log.Warning().
 				Error(e).
 				Msg("Cannot send to broken contact")
-			notifier.metrics.MarkSendersDroppedNotifications(pkg.Contact.Type)
+			notifier.drop(&pkg, e.Error(), latency)
+		case moira.SenderPermanentError:
+			log.Warning().
+				Error(e).
+				Msg("Cannot send notification, sending won't be retried")
+			notifier.drop(&pkg, e.Error(), latency)
 		default:
 			if pkg.FailCount > notifier.config.MaxFailAttemptToSendAvailable {
 				log.Error().
@@ -233,11 +430,18 @@ func (notifier *StandardNotifier) runSender(sender moira.Sender, ch chan Notific
 					Msg("Cannot send notification")
 			}
 
-			notifier.reschedule(&pkg, err.Error())
+			status, deadLetterID := notifier.reschedule(&pkg, err.Error())
+			notifier.saveDeliveryAttempt(&pkg, status, err.Error(), latency, deadLetterID)
 		}
 	}
 }
 
-func (notifier *StandardNotifier) needToStop(failCount int) bool {
-	return time.Duration(failCount)*time.Minute > notifier.config.ResendingTimeout
+// isContactError checks whether sender failed because of contact or message, such errors don't open circuit of sender.
+func isContactError(err error) bool {
+	switch err.(type) { // nolint:errorlint
+	case moira.SenderBrokenContactError, moira.SenderPermanentError:
+		return true
+	default:
+		return false
+	}
 }
@@ -94,6 +94,7 @@ func TestGetWindow(t *testing.T) {
 func TestUnknownContactType(t *testing.T) {
 	configureNotifier(t, defaultConfig)
 	defer afterTest()
+	dataBase.EXPECT().SaveDeliveryAttempt(gomock.Any()).Return(nil).AnyTimes()
 
 	var eventsData moira.NotificationEvents = []moira.NotificationEvent{event}
 
@@ -104,7 +105,7 @@ func TestUnknownContactType(t *testing.T) {
 		},
 	}
 	notification := moira.ScheduledNotification{}
-	scheduler.EXPECT().ScheduleNotification(gomock.Any(), event, pkg.Trigger, pkg.Contact, pkg.Plotting, pkg.Throttled, pkg.FailCount+1, gomock.Any()).Return(&notification)
+	scheduler.EXPECT().ScheduleRetry(gomock.Any(), time.Minute, event, pkg.Trigger, pkg.Contact, pkg.Plotting, pkg.Throttled, pkg.FailCount+1, gomock.Any()).Return(&notification)
 	dataBase.EXPECT().AddNotification(&notification).Return(nil)
 
 	var wg sync.WaitGroup
@@ -115,6 +116,7 @@ func TestUnknownContactType(t *testing.T) {
 func TestFailSendEvent(t *testing.T) {
 	configureNotifier(t, defaultConfig)
 	defer afterTest()
+	dataBase.EXPECT().SaveDeliveryAttempt(gomock.Any()).Return(nil).AnyTimes()
 
 	var eventsData moira.NotificationEvents = []moira.NotificationEvent{event}
 
@@ -125,8 +127,9 @@ func TestFailSendEvent(t *testing.T) {
 		},
 	}
 	notification := moira.ScheduledNotification{}
+	dataBase.EXPECT().GetContact(pkg.Contact.ID).Return(pkg.Contact, nil)
 	sender.EXPECT().SendEvents(eventsData, pkg.Contact, pkg.Trigger, plots, pkg.Throttled).Return(fmt.Errorf("Cant't send"))
-	scheduler.EXPECT().ScheduleNotification(gomock.Any(), event, pkg.Trigger, pkg.Contact, pkg.Plotting, pkg.Throttled, pkg.FailCount+1, gomock.Any()).Return(&notification)
+	scheduler.EXPECT().ScheduleRetry(gomock.Any(), time.Minute, event, pkg.Trigger, pkg.Contact, pkg.Plotting, pkg.Throttled, pkg.FailCount+1, gomock.Any()).Return(&notification)
 	dataBase.EXPECT().AddNotification(&notification).Return(nil)
 
 	var wg sync.WaitGroup
@@ -142,6 +145,7 @@ func TestNoResendForSendToBrokenContact(t *testing.T) {
 
 	configureNotifier(t, defaultConfig)
 	defer afterTest()
+	dataBase.EXPECT().SaveDeliveryAttempt(gomock.Any()).Return(nil).AnyTimes()
 
 	var eventsData moira.NotificationEvents = []moira.NotificationEvent{event}
 
@@ -151,19 +155,107 @@ func TestNoResendForSendToBrokenContact(t *testing.T) {
 			Type: "test_contact_type",
 		},
 	}
+	dataBase.EXPECT().GetContact(pkg.Contact.ID).Return(pkg.Contact, nil)
 	sender.EXPECT().SendEvents(eventsData, pkg.Contact, pkg.Trigger, plots, pkg.Throttled).
 		Return(moira.NewSenderBrokenContactError(fmt.Errorf("some sender reason")))
+	saved := make(chan *moira.DeadLetter, 1)
+	dataBase.EXPECT().SaveDeadLetter(gomock.Any()).DoAndReturn(func(letter *moira.DeadLetter) error {
+		saved <- letter
+		return nil
+	})
 
 	var wg sync.WaitGroup
 	standardNotifier.Send(&pkg, &wg)
 	wg.Wait()
-	time.Sleep(time.Second * 2)
+
+	Convey("Dropped package should be saved to dead letters", t, func() {
+		select {
+		case letter := <-saved:
+			So(letter.ID, ShouldNotBeEmpty)
+			So(letter.Reason, ShouldEqual, "some sender reason")
+			So(letter.Events, ShouldResemble, []moira.NotificationEvent(eventsData))
+			So(letter.Contact, ShouldResemble, pkg.Contact)
+		case <-time.After(time.Second * 2):
+			So("dead letter is not saved", ShouldBeEmpty)
+		}
+	})
+}
+
+func TestDeadLetterWhenResendingIsTimedOut(t *testing.T) {
+	configureNotifier(t, defaultConfig)
+	defer afterTest()
+	dataBase.EXPECT().SaveDeliveryAttempt(gomock.Any()).Return(nil).AnyTimes()
+
+	var eventsData moira.NotificationEvents = []moira.NotificationEvent{event}
+
+	pkg := NotificationPackage{
+		Events: eventsData,
+		Contact: moira.ContactData{
+			Type: "unknown contact",
+		},
+		FailCount: int(defaultConfig.ResendingTimeout/time.Minute) + 1,
+	}
+	var saved *moira.DeadLetter
+	dataBase.EXPECT().SaveDeadLetter(gomock.Any()).DoAndReturn(func(letter *moira.DeadLetter) error {
+		saved = letter
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	standardNotifier.Send(&pkg, &wg)
+	wg.Wait()
+
+	Convey("Package should be saved to dead letters", t, func() {
+		So(saved, ShouldNotBeNil)
+		So(saved.Events, ShouldResemble, []moira.NotificationEvent(eventsData))
+		So(saved.FailCount, ShouldEqual, pkg.FailCount)
+	})
+}
+
+func TestDeliveryAttemptIsSaved(t *testing.T) {
+	configureNotifier(t, defaultConfig)
+	defer afterTest()
+
+	var eventsData moira.NotificationEvents = []moira.NotificationEvent{event}
+
+	pkg := NotificationPackage{
+		Events: eventsData,
+		Contact: moira.ContactData{
+			ID:   "contact-id",
+			Type: "test_contact_type",
+		},
+	}
+	saved := make(chan *moira.DeliveryAttempt, 1)
+	dataBase.EXPECT().GetContact(pkg.Contact.ID).Return(pkg.Contact, nil)
+	sender.EXPECT().SendEvents(eventsData, pkg.Contact, pkg.Trigger, plots, pkg.Throttled).Return(nil)
+	dataBase.EXPECT().SaveDeliveryAttempt(gomock.Any()).DoAndReturn(func(attempt *moira.DeliveryAttempt) error {
+		saved <- attempt
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	standardNotifier.Send(&pkg, &wg)
+	wg.Wait()
+
+	Convey("Delivery attempt should be saved", t, func() {
+		select {
+		case attempt := <-saved:
+			So(attempt.ContactID, ShouldEqual, "contact-id")
+			So(attempt.ContactType, ShouldEqual, "test_contact_type")
+			So(attempt.SenderType, ShouldEqual, "test_type")
+			So(attempt.EventsCount, ShouldEqual, 1)
+			So(attempt.Status, ShouldEqual, moira.DeliveryStatusOK)
+		case <-time.After(time.Second * 2):
+			So("delivery attempt is not saved", ShouldBeEmpty)
+		}
+	})
 }
 
 func TestTimeout(t *testing.T) {
 	configureNotifier(t, defaultConfig)
 	var wg sync.WaitGroup
 	defer afterTest()
+	dataBase.EXPECT().SaveDeliveryAttempt(gomock.Any()).Return(nil).AnyTimes()
 
 	var eventsData moira.NotificationEvents = []moira.NotificationEvent{event}
 
@@ -175,6 +267,7 @@ func TestTimeout(t *testing.T) {
 		},
 	}
 
+	dataBase.EXPECT().GetContact(pkg.Contact.ID).Return(pkg.Contact, nil).AnyTimes()
 	sender.EXPECT().SendEvents(eventsData, pkg.Contact, pkg.Trigger, plots, pkg.Throttled).Return(nil).Do(func(arg0, arg1, arg2, arg3, arg4 interface{}) {
 		fmt.Print("Trying to send for 10 second")
 		time.Sleep(time.Second * 10)
@@ -195,7 +288,7 @@ func TestTimeout(t *testing.T) {
 		},
 	}
 
-	scheduler.EXPECT().ScheduleNotification(gomock.Any(), event, pkg2.Trigger, pkg2.Contact, pkg.Plotting, pkg2.Throttled, pkg2.FailCount+1, gomock.Any()).Return(&notification)
+	scheduler.EXPECT().ScheduleRetry(gomock.Any(), time.Minute, event, pkg2.Trigger, pkg2.Contact, pkg.Plotting, pkg2.Throttled, pkg2.FailCount+1, gomock.Any()).Return(&notification)
 	dataBase.EXPECT().AddNotification(&notification).Return(nil).Do(func(f ...interface{}) { close(shutdown) })
 
 	standardNotifier.Send(&pkg2, &wg)
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/moira-alert/go-chart"
 	"github.com/moira-alert/moira"
 	metricSource "github.com/moira-alert/moira/metric_source"
 	"github.com/moira-alert/moira/metric_source/local"
@@ -34,17 +33,30 @@ func (err errFetchAvailableSeriesFailed) Error() string {
 }
 
 // buildTriggerPlots returns bytes slices containing trigger plots.
+// Plots are rendered per target or as one plot with all targets if overlay is enabled in plotting settings.
 func buildTriggerPlots(trigger *moira.Trigger, metricsData map[string][]metricSource.MetricData,
-	plotTemplate *plotting.Plot,
+	plotTemplate *plotting.Plot, plottingData moira.PlottingData, annotations plotting.Annotations,
 ) ([][]byte, error) {
+	if plottingData.Overlay {
+		renderable, err := plotTemplate.GetOverlayRenderable(trigger, metricsData, annotations)
+		if err != nil {
+			return nil, err
+		}
+		buff := bytes.NewBuffer(make([]byte, 0))
+		if err = plotting.Render(renderable, plottingData.Format, buff); err != nil {
+			return nil, err
+		}
+		return [][]byte{buff.Bytes()}, nil
+	}
+
 	result := make([][]byte, 0)
 	for targetName, metrics := range metricsData {
-		renderable, err := plotTemplate.GetRenderable(targetName, trigger, metrics)
+		renderable, err := plotTemplate.GetRenderableWithAnnotations(targetName, trigger, metrics, annotations)
 		if err != nil {
 			return nil, err
 		}
 		buff := bytes.NewBuffer(make([]byte, 0))
-		if err = renderable.Render(chart.PNG, buff); err != nil {
+		if err = plotting.Render(renderable, plottingData.Format, buff); err != nil {
 			return nil, err
 		}
 		result = append(result, buff.Bytes())
@@ -71,6 +83,7 @@ func (notifier *StandardNotifier) buildNotificationPackagePlots(pkg Notification
 	if err != nil {
 		return nil, err
 	}
+	plotTemplate = plotTemplate.WithSize(pkg.Plotting.Width, pkg.Plotting.Height)
 
 	from, to := resolveMetricsWindow(logger, pkg.Trigger, pkg)
 	evaluateTriggerStartTime := time.Now()
@@ -86,7 +99,7 @@ func (notifier *StandardNotifier) buildNotificationPackagePlots(pkg Notification
 		Msg("Build plot from MetricsData")
 
 	buildPlotStartTime := time.Now()
-	result, err := buildTriggerPlots(trigger, metricsData, plotTemplate)
+	result, err := buildTriggerPlots(trigger, metricsData, plotTemplate, pkg.Plotting, plotting.GetEventsAnnotations(pkg.Events))
 	notifier.metrics.PlotsBuildDurationMs.Update(time.Since(buildPlotStartTime).Milliseconds())
 
 	logger.Info().
@@ -98,6 +111,9 @@ func (notifier *StandardNotifier) buildNotificationPackagePlots(pkg Notification
 
 // resolveMetricsWindow returns from, to parameters depending on trigger type.
 func resolveMetricsWindow(logger moira.Logger, trigger moira.TriggerData, pkg NotificationPackage) (int64, int64) {
+	if pkg.Plotting.TimeRange > 0 {
+		return resolveCustomMetricsWindow(pkg)
+	}
 	// resolve default realtime window for any case
 	now := time.Now()
 	defaultFrom := roundToRetention(now.UTC().Add(-defaultTimeRange).Unix())
@@ -136,6 +152,17 @@ func resolveMetricsWindow(logger moira.Logger, trigger moira.TriggerData, pkg No
 	return defaultFrom, defaultTo
 }
 
+// resolveCustomMetricsWindow returns window of time range from plotting settings ending at the last package event.
+func resolveCustomMetricsWindow(pkg NotificationPackage) (int64, int64) {
+	timeRange := time.Duration(pkg.Plotting.TimeRange) * time.Second
+	to := roundToRetention(time.Now().UTC().Unix())
+	if _, lastEvent, err := pkg.GetWindow(); err == nil {
+		to = roundToRetention(lastEvent)
+	}
+	toTime := moira.Int64ToTime(to)
+	return toTime.Add(-timeRange + defaultTimeShift).Unix(), toTime.Add(defaultTimeShift).Unix()
+}
+
 func roundToRetention(unixTime int64) int64 {
 	return moira.RoundToNearestRetention(unixTime, defaultRetentionSeconds)
 }
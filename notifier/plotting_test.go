@@ -133,6 +133,18 @@ func TestResolveMetricsWindow(t *testing.T) {
 			}
 		})
 	})
+	Convey("ANY TRIGGER | Time range from plotting settings is used", t, func() {
+		for _, trigger := range []moira.TriggerData{localTrigger, remoteTrigger} {
+			pkg := oldTriggerEvents
+			pkg.Plotting = moira.PlottingData{Enabled: true, TimeRange: 7200}
+			_, expectedTo, err := pkg.GetWindow()
+			So(err, ShouldBeNil)
+			expectedTo = roundToRetention(expectedTo)
+			from, to := resolveMetricsWindow(logger, trigger, pkg)
+			So(from, ShouldEqual, expectedTo-7200+timeShift)
+			So(to, ShouldEqual, expectedTo+timeShift)
+		}
+	})
 	Convey("ANY TRIGGER | Zero time range, force default time range", t, func() {
 		allTriggers := []moira.TriggerData{localTrigger, remoteTrigger}
 		for _, trigger := range allTriggers {
@@ -252,9 +264,19 @@ func TestBuildTriggerPlots(t *testing.T) {
 
 		Convey("without errors", func() {
 			testMetricsData := generateTestMetricsData()
-			result, err := buildTriggerPlots(&trigger, testMetricsData, plotTemplate)
+			result, err := buildTriggerPlots(&trigger, testMetricsData, plotTemplate, moira.PlottingData{}, plotting.Annotations{})
 			So(len(result), ShouldResemble, 4)
 			So(err, ShouldBeNil)
 		})
+
+		Convey("with overlay to SVG", func() {
+			testMetricsData := generateTestMetricsData()
+			plottingData := moira.PlottingData{Overlay: true, Format: moira.PlotFormatSVG}
+			annotations := plotting.Annotations{StateMarkers: []plotting.StateMarker{{Timestamp: 50, State: moira.StateERROR}}}
+			result, err := buildTriggerPlots(&trigger, testMetricsData, plotTemplate, plottingData, annotations)
+			So(err, ShouldBeNil)
+			So(result, ShouldHaveLength, 1)
+			So(moira.GetPlotFormat(result[0]), ShouldEqual, moira.PlotFormatSVG)
+		})
 	})
 }
@@ -4,10 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
 	"github.com/moira-alert/moira/senders/discord"
+	"github.com/moira-alert/moira/senders/eventbus"
+	"github.com/moira-alert/moira/senders/googlechat"
+	"github.com/moira-alert/moira/senders/jira"
 	"github.com/moira-alert/moira/senders/mail"
+	"github.com/moira-alert/moira/senders/matrix"
 	"github.com/moira-alert/moira/senders/mattermost"
 	"github.com/moira-alert/moira/senders/msteams"
 	"github.com/moira-alert/moira/senders/opsgenie"
@@ -20,6 +26,7 @@ import (
 	"github.com/moira-alert/moira/senders/twilio"
 	"github.com/moira-alert/moira/senders/victorops"
 	"github.com/moira-alert/moira/senders/webhook"
+	"github.com/moira-alert/moira/senders/zulip"
 	// "github.com/moira-alert/moira/senders/kontur"
 )
 
@@ -39,6 +46,11 @@ const (
 	pagerdutySender   = "pagerduty"
 	msTeamsSender     = "msteams"
 	mattermostSender  = "mattermost"
+	matrixSender      = "matrix"
+	googleChatSender  = "googlechat"
+	zulipSender       = "zulip"
+	jiraSender        = "jira"
+	eventBusSender    = "eventbus"
 )
 
 var (
@@ -48,46 +60,15 @@ var (
 )
 
 // RegisterSenders watch on senders config and register all configured senders.
-func (notifier *StandardNotifier) RegisterSenders(connector moira.Database) error { //nolint
-	var err error
+func (notifier *StandardNotifier) RegisterSenders(connector moira.Database) error {
 	for _, senderSettings := range notifier.config.Senders {
 		senderSettings["front_uri"] = notifier.config.FrontURL
-		switch senderSettings["sender_type"] {
-		case mailSender:
-			err = notifier.RegisterSender(senderSettings, &mail.Sender{})
-		case pushoverSender:
-			err = notifier.RegisterSender(senderSettings, &pushover.Sender{})
-		case scriptSender:
-			err = notifier.RegisterSender(senderSettings, &script.Sender{})
-		case discordSender:
-			err = notifier.RegisterSender(senderSettings, &discord.Sender{DataBase: connector})
-		case slackSender:
-			err = notifier.RegisterSender(senderSettings, &slack.Sender{})
-		case telegramSender:
-			err = notifier.RegisterSender(senderSettings, &telegram.Sender{DataBase: connector})
-		case msTeamsSender:
-			err = notifier.RegisterSender(senderSettings, &msteams.Sender{})
-		case pagerdutySender:
-			err = notifier.RegisterSender(senderSettings, &pagerduty.Sender{ImageStores: notifier.imageStores})
-		case twilioSmsSender, twilioVoiceSender:
-			err = notifier.RegisterSender(senderSettings, &twilio.Sender{})
-		case webhookSender:
-			err = notifier.RegisterSender(senderSettings, &webhook.Sender{})
-		case opsgenieSender:
-			err = notifier.RegisterSender(senderSettings, &opsgenie.Sender{ImageStores: notifier.imageStores})
-		case victoropsSender:
-			err = notifier.RegisterSender(senderSettings, &victorops.Sender{ImageStores: notifier.imageStores})
-		case mattermostSender:
-			err = notifier.RegisterSender(senderSettings, &mattermost.Sender{})
-		// case "email":
-		// 	err = notifier.RegisterSender(senderSettings, &kontur.MailSender{})
-		// case "phone":
-		// 	err = notifier.RegisterSender(senderSettings, &kontur.SmsSender{})
-		default:
-			return fmt.Errorf("unknown sender type [%s]", senderSettings["sender_type"])
+		sender, err := newSender(senderSettings, connector, notifier.imageStores, notifier.metricSourceProvider)
+		if err != nil {
+			return err
 		}
 
-		if err != nil {
+		if err = notifier.RegisterSender(senderSettings, sender); err != nil {
 			return err
 		}
 	}
@@ -96,7 +77,7 @@ func (notifier *StandardNotifier) RegisterSenders(connector moira.Database) erro
 			"sender_type":  selfStateSender,
 			"contact_type": selfStateSender,
 		}
-		if err = notifier.RegisterSender(selfStateSettings, &selfstate.Sender{Database: connector}); err != nil {
+		if err := notifier.RegisterSender(selfStateSettings, &selfstate.Sender{Database: connector}); err != nil {
 			notifier.logger.Warning().
 				Error(err).
 				Msg("Failed to register selfstate sender")
@@ -106,10 +87,108 @@ func (notifier *StandardNotifier) RegisterSenders(connector moira.Database) erro
 	return nil
 }
 
+func newSender( //nolint
+	senderSettings map[string]interface{},
+	connector moira.Database,
+	imageStores map[string]moira.ImageStore,
+	metricSourceProvider *metricSource.SourceProvider,
+) (moira.Sender, error) {
+	switch senderSettings["sender_type"] {
+	case mailSender:
+		return &mail.Sender{}, nil
+	case pushoverSender:
+		return &pushover.Sender{}, nil
+	case scriptSender:
+		return &script.Sender{}, nil
+	case discordSender:
+		return &discord.Sender{DataBase: connector}, nil
+	case slackSender:
+		return &slack.Sender{DataBase: connector}, nil
+	case telegramSender:
+		return &telegram.Sender{DataBase: connector, MetricSourceProvider: metricSourceProvider}, nil
+	case msTeamsSender:
+		return &msteams.Sender{}, nil
+	case pagerdutySender:
+		return &pagerduty.Sender{ImageStores: imageStores}, nil
+	case twilioSmsSender, twilioVoiceSender:
+		return &twilio.Sender{}, nil
+	case webhookSender:
+		return &webhook.Sender{}, nil
+	case opsgenieSender:
+		return &opsgenie.Sender{ImageStores: imageStores}, nil
+	case victoropsSender:
+		return &victorops.Sender{ImageStores: imageStores}, nil
+	case mattermostSender:
+		return &mattermost.Sender{DataBase: connector}, nil
+	case matrixSender:
+		return &matrix.Sender{}, nil
+	case googleChatSender:
+		return &googlechat.Sender{ImageStores: imageStores}, nil
+	case zulipSender:
+		return &zulip.Sender{}, nil
+	case jiraSender:
+		return &jira.Sender{DataBase: connector}, nil
+	case eventBusSender:
+		return &eventbus.Sender{}, nil
+	// case "email":
+	// 	return &kontur.MailSender{}, nil
+	// case "phone":
+	// 	return &kontur.SmsSender{}, nil
+	default:
+		return nil, fmt.Errorf("unknown sender type [%s]", senderSettings["sender_type"])
+	}
+}
+
+// NewMessageBuilders creates message builders for configured senders keyed by contact type.
+// Builders don't connect to external services, so they can be used outside of notifier, e.g. to preview notifications.
+// Senders which don't separate message building from delivery are skipped.
+func NewMessageBuilders(
+	sendersSettings []map[string]interface{},
+	frontURI string,
+	logger moira.Logger,
+	location *time.Location,
+	dateTimeFormat string,
+) (map[string]moira.MessageBuilder, error) {
+	builders := make(map[string]moira.MessageBuilder, len(sendersSettings))
+	for _, senderSettings := range sendersSettings {
+		senderSettings["front_uri"] = frontURI
+		if _, ok := senderSettings["sender_type"].(string); !ok {
+			return nil, ErrMissingSenderType
+		}
+
+		senderContactType, ok := senderSettings["contact_type"].(string)
+		if !ok {
+			return nil, ErrMissingContactType
+		}
+
+		if _, ok := builders[senderContactType]; ok {
+			return nil, fmt.Errorf("failed to initialize message builder [%s], err [%w]", senderContactType, ErrSenderRegistered)
+		}
+
+		sender, err := newSender(senderSettings, nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		builder, ok := sender.(moira.MessageBuilder)
+		if !ok {
+			continue
+		}
+
+		if err = builder.InitMessageBuilder(senderSettings, logger, location, dateTimeFormat); err != nil {
+			return nil, fmt.Errorf("failed to initialize message builder [%s], err [%w]", senderContactType, err)
+		}
+		builders[senderContactType] = builder
+	}
+	return builders, nil
+}
+
 func (notifier *StandardNotifier) registerMetrics(senderContactType string) {
 	notifier.metrics.SendersOkMetrics.RegisterMeter(senderContactType, getGraphiteSenderIdent(senderContactType), "sends_ok")
 	notifier.metrics.SendersFailedMetrics.RegisterMeter(senderContactType, getGraphiteSenderIdent(senderContactType), "sends_failed")
 	notifier.metrics.SendersDroppedNotifications.RegisterMeter(senderContactType, getGraphiteSenderIdent(senderContactType), "notifications_dropped")
+	notifier.metrics.SendersPostponedNotifications.RegisterMeter(senderContactType, getGraphiteSenderIdent(senderContactType), "notifications_postponed")
+	notifier.metrics.SendersOpenedCircuits.RegisterMeter(senderContactType, getGraphiteSenderIdent(senderContactType), "circuit_opened")
 }
 
 // RegisterSender adds sender for notification type and registers metrics.
@@ -128,16 +207,28 @@ func (notifier *StandardNotifier) RegisterSender(senderSettings map[string]inter
 		return fmt.Errorf("failed to initialize sender [%s], err [%w]", senderContactType, ErrSenderRegistered)
 	}
 
-	err := sender.Init(senderSettings, notifier.logger, notifier.config.Location, notifier.config.DateTimeFormat)
+	guard, err := newSenderGuard(senderSettings)
+	if err != nil {
+		return fmt.Errorf("failed to initialize sender [%s], err [%w]", senderContactType, err)
+	}
+
+	policy, err := newRetryPolicy(senderSettings)
+	if err != nil {
+		return fmt.Errorf("failed to initialize sender [%s], err [%w]", senderContactType, err)
+	}
+
+	err = sender.Init(senderSettings, notifier.logger, notifier.config.Location, notifier.config.DateTimeFormat)
 	if err != nil {
 		return fmt.Errorf("failed to initialize sender [%s], err [%w]", senderContactType, err)
 	}
 
 	eventsChannel := make(chan NotificationPackage)
 	notifier.senders[senderContactType] = eventsChannel
+	notifier.senderTypes[senderContactType] = senderType
+	notifier.retryPolicies[senderContactType] = policy
 
 	notifier.registerMetrics(senderContactType)
-	notifier.runSenders(sender, eventsChannel)
+	notifier.runSenders(sender, guard, eventsChannel)
 
 	notifier.logger.Info().
 		String("sender_contact_type", senderContactType).
@@ -149,10 +240,10 @@ func (notifier *StandardNotifier) RegisterSender(senderSettings map[string]inter
 
 const maxParallelSendsPerSender = 16
 
-func (notifier *StandardNotifier) runSenders(sender moira.Sender, eventsChannel chan NotificationPackage) {
+func (notifier *StandardNotifier) runSenders(sender moira.Sender, guard *senderGuard, eventsChannel chan NotificationPackage) {
 	for i := 0; i < maxParallelSendsPerSender; i++ {
 		notifier.waitGroup.Add(1)
-		go notifier.runSender(sender, eventsChannel)
+		go notifier.runSender(sender, guard, eventsChannel)
 	}
 }
 
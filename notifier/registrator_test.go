@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -109,3 +110,56 @@ func TestRegisterSendersWithValidType(t *testing.T) {
 		So(err, ShouldBeNil)
 	})
 }
+
+func TestNewMessageBuilders(t *testing.T) {
+	logger, _ := logging.GetLogger("Notifier")
+
+	Convey("Test NewMessageBuilders", t, func() {
+		Convey("Builders are created without connecting to external services", func() {
+			sendersSettings := []map[string]interface{}{
+				{
+					"sender_type":  "slack",
+					"contact_type": "slack",
+					"api_token":    "123",
+				},
+				{
+					"sender_type":  "webhook",
+					"contact_type": "webhook",
+					"url":          "http://localhost/",
+				},
+			}
+
+			builders, err := NewMessageBuilders(sendersSettings, "http://moira.example.com", logger, location, dateTimeFormat)
+			So(err, ShouldBeNil)
+			So(builders, ShouldHaveLength, 2)
+			So(builders, ShouldContainKey, "slack")
+			So(builders, ShouldContainKey, "webhook")
+			So(sendersSettings[0]["front_uri"], ShouldEqual, "http://moira.example.com")
+		})
+
+		Convey("Unknown sender type", func() {
+			sendersSettings := []map[string]interface{}{
+				{
+					"sender_type":  "some_type",
+					"contact_type": "some_type",
+				},
+			}
+
+			builders, err := NewMessageBuilders(sendersSettings, "", logger, location, dateTimeFormat)
+			So(err, ShouldResemble, fmt.Errorf("unknown sender type [%s]", "some_type"))
+			So(builders, ShouldBeNil)
+		})
+
+		Convey("Sender without contact type", func() {
+			sendersSettings := []map[string]interface{}{
+				{
+					"sender_type": "slack",
+				},
+			}
+
+			builders, err := NewMessageBuilders(sendersSettings, "", logger, location, dateTimeFormat)
+			So(err, ShouldResemble, ErrMissingContactType)
+			So(builders, ShouldBeNil)
+		})
+	})
+}
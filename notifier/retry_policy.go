@@ -0,0 +1,125 @@
+package notifier
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	defaultRetryInterval = time.Minute
+	maxRetryInterval     = 24 * time.Hour
+)
+
+// retryPolicyConfig is the retry section of sender settings.
+type retryPolicyConfig struct {
+	// InitialInterval is the delay before the first retry.
+	InitialInterval string `mapstructure:"initial_interval"`
+	// MaxInterval limits growth of delay.
+	MaxInterval string `mapstructure:"max_interval"`
+	// Multiplier is the factor delay grows with after every failed attempt.
+	Multiplier float64 `mapstructure:"multiplier"`
+	// Jitter is the fraction delay is randomly changed by, so retries of many packages are spread in time.
+	Jitter float64 `mapstructure:"jitter"`
+	// MaxAttempts is the number of attempts to send package including the first one, zero means that
+	// notifier retries until resending_timeout is reached.
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// FallbackContact is the id of contact which receives notifications notifier gave up on.
+	FallbackContact string `mapstructure:"fallback_contact"`
+}
+
+// retryPolicy describes when failed package is sent again. Default policy retries every minute.
+type retryPolicy struct {
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	multiplier      float64
+	jitter          float64
+	maxAttempts     int
+	fallbackContact string
+}
+
+var defaultRetryPolicy = retryPolicy{
+	initialInterval: defaultRetryInterval,
+	maxInterval:     maxRetryInterval,
+	multiplier:      1,
+}
+
+// newRetryPolicy reads retry settings of sender.
+func newRetryPolicy(senderSettings map[string]interface{}) (retryPolicy, error) {
+	var config struct {
+		Retry retryPolicyConfig `mapstructure:"retry"`
+	}
+	if err := mapstructure.Decode(senderSettings, &config); err != nil {
+		return retryPolicy{}, fmt.Errorf("failed to decode retry settings: %w", err)
+	}
+
+	policy := defaultRetryPolicy
+	policy.maxAttempts = config.Retry.MaxAttempts
+	policy.fallbackContact = config.Retry.FallbackContact
+
+	var err error
+	if config.Retry.InitialInterval != "" {
+		if policy.initialInterval, err = time.ParseDuration(config.Retry.InitialInterval); err != nil {
+			return retryPolicy{}, fmt.Errorf("failed to parse retry initial_interval: %w", err)
+		}
+	}
+	if config.Retry.MaxInterval != "" {
+		if policy.maxInterval, err = time.ParseDuration(config.Retry.MaxInterval); err != nil {
+			return retryPolicy{}, fmt.Errorf("failed to parse retry max_interval: %w", err)
+		}
+	}
+	if config.Retry.Multiplier != 0 {
+		policy.multiplier = config.Retry.Multiplier
+	}
+	policy.jitter = config.Retry.Jitter
+
+	switch {
+	case policy.initialInterval <= 0 || policy.maxInterval <= 0:
+		return retryPolicy{}, fmt.Errorf("retry intervals must be positive")
+	case policy.multiplier < 1:
+		return retryPolicy{}, fmt.Errorf("retry multiplier must not be less than 1")
+	case policy.jitter < 0 || policy.jitter >= 1:
+		return retryPolicy{}, fmt.Errorf("retry jitter must be in range [0, 1)")
+	case policy.maxAttempts < 0:
+		return retryPolicy{}, fmt.Errorf("retry max_attempts must not be negative")
+	}
+	policy.maxInterval = min(policy.maxInterval, maxRetryInterval)
+	return policy, nil
+}
+
+// interval returns delay after failed attempt with given number, jitter is not applied.
+func (policy retryPolicy) interval(failCount int) time.Duration {
+	interval := float64(policy.initialInterval)
+	for i := 1; i < failCount && interval < float64(policy.maxInterval); i++ {
+		interval *= policy.multiplier
+	}
+	return min(time.Duration(interval), policy.maxInterval)
+}
+
+// delay returns randomized delay after failed attempt with given number.
+func (policy retryPolicy) delay(failCount int) time.Duration {
+	interval := policy.interval(failCount)
+	if policy.jitter == 0 {
+		return interval
+	}
+	return time.Duration(float64(interval) * (1 + policy.jitter*(2*rand.Float64()-1))) //nolint:gosec,gomnd
+}
+
+// elapsed returns approximate time spent on retries after given number of failed attempts.
+func (policy retryPolicy) elapsed(failCount int) time.Duration {
+	var elapsed time.Duration
+	for i := 1; i <= failCount; i++ {
+		elapsed += policy.interval(i)
+	}
+	return elapsed
+}
+
+// isExhausted checks whether attempt with given number of previous failures was the last one.
+func (policy retryPolicy) isExhausted(failCount int, resendingTimeout time.Duration) bool {
+	if policy.maxAttempts > 0 && failCount+1 >= policy.maxAttempts {
+		return true
+	}
+	return policy.elapsed(failCount) > resendingTimeout
+}
@@ -0,0 +1,187 @@
+package notifier
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+)
+
+var errSendFailed = errors.New("send failed")
+
+func TestNewRetryPolicy(t *testing.T) {
+	Convey("Test newRetryPolicy", t, func() {
+		Convey("Without settings policy retries every minute", func() {
+			policy, err := newRetryPolicy(map[string]interface{}{"sender_type": "slack"})
+			So(err, ShouldBeNil)
+			So(policy, ShouldResemble, defaultRetryPolicy)
+		})
+
+		Convey("With settings from yaml", func() {
+			policy, err := newRetryPolicy(map[string]interface{}{
+				"retry": map[interface{}]interface{}{
+					"initial_interval": "30s",
+					"max_interval":     "1h",
+					"multiplier":       2,
+					"jitter":           0.1,
+					"max_attempts":     8,
+					"fallback_contact": "fallback-contact-id",
+				},
+			})
+			So(err, ShouldBeNil)
+			So(policy, ShouldResemble, retryPolicy{
+				initialInterval: 30 * time.Second,
+				maxInterval:     time.Hour,
+				multiplier:      2,
+				jitter:          0.1,
+				maxAttempts:     8,
+				fallbackContact: "fallback-contact-id",
+			})
+		})
+
+		Convey("Invalid settings", func() {
+			for _, retry := range []map[interface{}]interface{}{
+				{"initial_interval": "soon"},
+				{"max_interval": "-1m"},
+				{"multiplier": 0.5},
+				{"jitter": 1},
+				{"max_attempts": -1},
+			} {
+				_, err := newRetryPolicy(map[string]interface{}{"retry": retry})
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	Convey("Test retryPolicy", t, func() {
+		Convey("Default policy keeps retrying every minute until resending timeout", func() {
+			So(defaultRetryPolicy.delay(1), ShouldEqual, time.Minute)
+			So(defaultRetryPolicy.delay(100), ShouldEqual, time.Minute)
+			So(defaultRetryPolicy.isExhausted(60, time.Hour), ShouldBeFalse)
+			So(defaultRetryPolicy.isExhausted(61, time.Hour), ShouldBeTrue)
+		})
+
+		policy := retryPolicy{
+			initialInterval: time.Minute,
+			maxInterval:     10 * time.Minute,
+			multiplier:      2,
+		}
+
+		Convey("Delay grows exponentially up to max interval", func() {
+			So(policy.delay(1), ShouldEqual, time.Minute)
+			So(policy.delay(2), ShouldEqual, 2*time.Minute)
+			So(policy.delay(4), ShouldEqual, 8*time.Minute)
+			So(policy.delay(5), ShouldEqual, 10*time.Minute)
+			So(policy.delay(1000), ShouldEqual, 10*time.Minute)
+			So(policy.elapsed(5), ShouldEqual, 25*time.Minute)
+		})
+
+		Convey("Jitter spreads delay", func() {
+			policy.jitter = 0.5
+			for i := 0; i < 100; i++ {
+				delay := policy.delay(2)
+				So(delay, ShouldBeBetweenOrEqual, time.Minute, 3*time.Minute)
+			}
+		})
+
+		Convey("Retries are exhausted after max attempts", func() {
+			policy.maxAttempts = 3
+			So(policy.isExhausted(1, 24*time.Hour), ShouldBeFalse)
+			So(policy.isExhausted(2, 24*time.Hour), ShouldBeTrue)
+		})
+
+		Convey("Retries are exhausted after resending timeout", func() {
+			So(policy.isExhausted(4, 20*time.Minute), ShouldBeFalse)
+			So(policy.isExhausted(5, 20*time.Minute), ShouldBeTrue)
+		})
+	})
+}
+
+func TestFallbackContact(t *testing.T) {
+	configureNotifier(t, defaultConfig)
+	defer afterTest()
+	dataBase.EXPECT().SaveDeliveryAttempt(gomock.Any()).Return(nil).AnyTimes()
+	dataBase.EXPECT().SaveDeadLetter(gomock.Any()).Return(nil).AnyTimes()
+	dataBase.EXPECT().GetContact("contact-id").Return(moira.ContactData{ID: "contact-id", Type: "test_contact_type"}, nil).AnyTimes()
+
+	standardNotifier.retryPolicies["test_contact_type"] = retryPolicy{
+		initialInterval: time.Minute,
+		maxInterval:     time.Hour,
+		multiplier:      2,
+		maxAttempts:     3,
+		fallbackContact: "fallback-contact-id",
+	}
+	fallbackContact := moira.ContactData{ID: "fallback-contact-id", Type: "mail", Value: "ops@example.com"}
+
+	Convey("Test fallback contact", t, func() {
+		pkg := NotificationPackage{
+			Events:  []moira.NotificationEvent{event},
+			Contact: moira.ContactData{ID: "contact-id", Type: "test_contact_type"},
+		}
+
+		Convey("Failed package is retried with backoff", func() {
+			pkg.FailCount = 1
+			notification := moira.ScheduledNotification{}
+			done := make(chan struct{})
+			sender.EXPECT().SendEvents(gomock.Any(), pkg.Contact, pkg.Trigger, gomock.Any(), false).Return(errSendFailed)
+			scheduler.EXPECT().ScheduleRetry(gomock.Any(), 2*time.Minute, event, pkg.Trigger, pkg.Contact, pkg.Plotting, false, 2, gomock.Any()).Return(&notification)
+			dataBase.EXPECT().AddNotification(&notification).Return(nil).Do(func(*moira.ScheduledNotification) { close(done) })
+
+			var wg sync.WaitGroup
+			standardNotifier.Send(&pkg, &wg)
+			wg.Wait()
+			<-done
+		})
+
+		Convey("Package is sent to fallback contact when retries are exhausted", func() {
+			pkg.FailCount = 2
+			notification := moira.ScheduledNotification{}
+			done := make(chan struct{})
+			sender.EXPECT().SendEvents(gomock.Any(), pkg.Contact, pkg.Trigger, gomock.Any(), false).Return(errSendFailed)
+			dataBase.EXPECT().GetContact(fallbackContact.ID).Return(fallbackContact, nil)
+			scheduler.EXPECT().ScheduleRetry(gomock.Any(), time.Duration(0), event, pkg.Trigger, fallbackContact, pkg.Plotting, false, 0, gomock.Any()).Return(&notification)
+			dataBase.EXPECT().AddNotification(&notification).Return(nil).Do(func(*moira.ScheduledNotification) { close(done) })
+
+			var wg sync.WaitGroup
+			standardNotifier.Send(&pkg, &wg)
+			wg.Wait()
+			<-done
+		})
+
+		Convey("Package with permanent error is sent to fallback contact without retries", func() {
+			notification := moira.ScheduledNotification{}
+			done := make(chan struct{})
+			sender.EXPECT().SendEvents(gomock.Any(), pkg.Contact, pkg.Trigger, gomock.Any(), false).
+				Return(moira.NewSenderPermanentError(errSendFailed))
+			dataBase.EXPECT().GetContact(fallbackContact.ID).Return(fallbackContact, nil)
+			scheduler.EXPECT().ScheduleRetry(gomock.Any(), time.Duration(0), event, pkg.Trigger, fallbackContact, pkg.Plotting, false, 0, gomock.Any()).Return(&notification)
+			dataBase.EXPECT().AddNotification(&notification).Return(nil).Do(func(*moira.ScheduledNotification) { close(done) })
+
+			var wg sync.WaitGroup
+			standardNotifier.Send(&pkg, &wg)
+			wg.Wait()
+			<-done
+		})
+
+		Convey("Package of fallback contact is not sent to fallback contact", func() {
+			pkg.Contact.ID = fallbackContact.ID
+			pkg.FailCount = 2
+			dropped := make(chan struct{})
+			dataBase.EXPECT().GetContact(fallbackContact.ID).Return(pkg.Contact, nil)
+			sender.EXPECT().SendEvents(gomock.Any(), pkg.Contact, pkg.Trigger, gomock.Any(), false).
+				Return(moira.NewSenderPermanentError(errSendFailed)).Do(func(moira.NotificationEvents, moira.ContactData, moira.TriggerData, [][]byte, bool) { close(dropped) })
+
+			var wg sync.WaitGroup
+			standardNotifier.Send(&pkg, &wg)
+			wg.Wait()
+			<-dropped
+		})
+	})
+}
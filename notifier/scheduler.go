@@ -12,6 +12,8 @@ import (
 type Scheduler interface {
 	ScheduleNotification(now time.Time, event moira.NotificationEvent, trigger moira.TriggerData,
 		contact moira.ContactData, plotting moira.PlottingData, throttledOld bool, sendFail int, logger moira.Logger) *moira.ScheduledNotification
+	ScheduleRetry(now time.Time, delay time.Duration, event moira.NotificationEvent, trigger moira.TriggerData,
+		contact moira.ContactData, plotting moira.PlottingData, throttled bool, sendFail int, logger moira.Logger) *moira.ScheduledNotification
 }
 
 // StandardScheduler represents standard event scheduling.
@@ -38,21 +40,35 @@ func NewScheduler(database moira.Database, logger moira.Logger, metrics *metrics
 func (scheduler *StandardScheduler) ScheduleNotification(now time.Time, event moira.NotificationEvent, trigger moira.TriggerData,
 	contact moira.ContactData, plotting moira.PlottingData, throttledOld bool, sendFail int, logger moira.Logger,
 ) *moira.ScheduledNotification {
+	if sendFail > 0 {
+		return scheduler.ScheduleRetry(now, defaultRetryInterval, event, trigger, contact, plotting, throttledOld, sendFail, logger)
+	}
+
 	var (
 		next      time.Time
 		throttled bool
 	)
-	if sendFail > 0 {
-		next = now.Add(time.Minute)
-		throttled = throttledOld
+	if event.State == moira.StateTEST {
+		next = now
+		throttled = false
 	} else {
-		if event.State == moira.StateTEST {
-			next = now
-			throttled = false
-		} else {
-			next, throttled = scheduler.calculateNextDelivery(now, &event, logger)
-		}
+		next, throttled = scheduler.calculateNextDelivery(now, &event, logger)
+		next, contact = scheduler.applyContactAvailability(next, event, trigger, contact, logger)
 	}
+	return newScheduledNotification(now, next, event, trigger, contact, plotting, throttled, sendFail, logger)
+}
+
+// ScheduleRetry schedules notification which failed to be sent to be sent again after delay.
+// Throttling and schedule of subscription are not applied to retries.
+func (scheduler *StandardScheduler) ScheduleRetry(now time.Time, delay time.Duration, event moira.NotificationEvent, trigger moira.TriggerData,
+	contact moira.ContactData, plotting moira.PlottingData, throttled bool, sendFail int, logger moira.Logger,
+) *moira.ScheduledNotification {
+	return newScheduledNotification(now, now.Add(delay), event, trigger, contact, plotting, throttled, sendFail, logger)
+}
+
+func newScheduledNotification(now, next time.Time, event moira.NotificationEvent, trigger moira.TriggerData,
+	contact moira.ContactData, plotting moira.PlottingData, throttled bool, sendFail int, logger moira.Logger,
+) *moira.ScheduledNotification {
 	notification := &moira.ScheduledNotification{
 		Event:     event,
 		Trigger:   trigger,
@@ -73,6 +73,16 @@ func TestThrottling(t *testing.T) {
 		So(notification, ShouldResemble, &expected2)
 	})
 
+	Convey("Test retry is scheduled after delay of retry policy", t, func() {
+		expected2 := expected
+		expected2.SendFail = 4
+		expected2.Timestamp = now.Add(8 * time.Minute).Unix()
+		expected2.Throttled = true
+
+		notification := scheduler.ScheduleRetry(now, 8*time.Minute, event, trigger, contact, plottingData, true, 4, logger)
+		So(notification, ShouldResemble, &expected2)
+	})
+
 	Convey("Test sendFail more than 0, and has throttling, should send message in one minute", t, func() {
 		expected2 := expected
 		expected2.SendFail = 3
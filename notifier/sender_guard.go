@@ -0,0 +1,293 @@
+package notifier
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// contactBucketsCleanupSize is the number of contact buckets after which idle buckets are removed.
+const contactBucketsCleanupSize = 10_000
+
+// rateLimitConfig is the rate_limit section of sender settings.
+// Rates are set in packages per second, limits are disabled if rates are not set.
+type rateLimitConfig struct {
+	Rate         float64 `mapstructure:"rate"`
+	Burst        int     `mapstructure:"burst"`
+	ContactRate  float64 `mapstructure:"contact_rate"`
+	ContactBurst int     `mapstructure:"contact_burst"`
+}
+
+// circuitBreakerConfig is the circuit_breaker section of sender settings.
+// Circuit breaker is disabled if failures are not set.
+type circuitBreakerConfig struct {
+	// Failures is the number of consecutive failed sends after which sender is paused.
+	Failures int `mapstructure:"failures"`
+	// Timeout is the pause after which single package is sent to probe sender.
+	Timeout string `mapstructure:"timeout"`
+}
+
+type senderGuardConfig struct {
+	RateLimit      rateLimitConfig      `mapstructure:"rate_limit"`
+	CircuitBreaker circuitBreakerConfig `mapstructure:"circuit_breaker"`
+}
+
+const defaultCircuitBreakerTimeout = time.Minute
+
+// senderGuard limits rate of sends and pauses sender after consecutive failures.
+// Nil guard allows all sends.
+type senderGuard struct {
+	limiter *rateLimiter
+	breaker *circuitBreaker
+}
+
+// newSenderGuard reads rate_limit and circuit_breaker settings of sender, nil is returned if both are disabled.
+func newSenderGuard(senderSettings map[string]interface{}) (*senderGuard, error) {
+	var config senderGuardConfig
+	if err := mapstructure.Decode(senderSettings, &config); err != nil {
+		return nil, fmt.Errorf("failed to decode rate_limit and circuit_breaker settings: %w", err)
+	}
+
+	guard := &senderGuard{}
+	if config.RateLimit.Rate < 0 || config.RateLimit.ContactRate < 0 {
+		return nil, fmt.Errorf("rate_limit rates must not be negative")
+	}
+	if config.RateLimit.Rate > 0 || config.RateLimit.ContactRate > 0 {
+		guard.limiter = newRateLimiter(config.RateLimit, time.Now())
+	}
+
+	if config.CircuitBreaker.Failures > 0 {
+		timeout := defaultCircuitBreakerTimeout
+		if config.CircuitBreaker.Timeout != "" {
+			var err error
+			if timeout, err = time.ParseDuration(config.CircuitBreaker.Timeout); err != nil {
+				return nil, fmt.Errorf("failed to parse circuit_breaker timeout: %w", err)
+			}
+		}
+		guard.breaker = newCircuitBreaker(config.CircuitBreaker.Failures, timeout)
+	}
+
+	if guard.limiter == nil && guard.breaker == nil {
+		return nil, nil
+	}
+	return guard, nil
+}
+
+// allow checks whether package to contact can be sent now, otherwise returns the delay and the reason of postponing.
+func (guard *senderGuard) allow(contactID string, now time.Time) (bool, time.Duration, string) {
+	if guard == nil {
+		return true, 0, ""
+	}
+	if guard.breaker != nil {
+		if ok, wait := guard.breaker.allow(now); !ok {
+			return false, wait, "sender circuit is open"
+		}
+	}
+	if guard.limiter != nil {
+		if ok, wait := guard.limiter.take(contactID, now); !ok {
+			if guard.breaker != nil {
+				guard.breaker.cancelProbe()
+			}
+			return false, wait, "sender rate limit is exceeded"
+		}
+	}
+	return true, 0, ""
+}
+
+// done records result of send, returns true if it opened the circuit.
+func (guard *senderGuard) done(success bool, now time.Time) bool {
+	if guard == nil || guard.breaker == nil {
+		return false
+	}
+	if success {
+		guard.breaker.succeed()
+		return false
+	}
+	return guard.breaker.fail(now)
+}
+
+// tokenBucket allows burst of sends and refills at constant rate.
+type tokenBucket struct {
+	rate    float64
+	burst   float64
+	tokens  float64
+	updated time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:    rate,
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		updated: now,
+	}
+}
+
+// take takes token if it is available, otherwise returns time left until the next token.
+func (bucket *tokenBucket) take(now time.Time) (bool, time.Duration) {
+	bucket.refill(now)
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - bucket.tokens) / bucket.rate * float64(time.Second))
+}
+
+// put returns token taken for send that did not happen.
+func (bucket *tokenBucket) put() {
+	bucket.tokens = min(bucket.tokens+1, bucket.burst)
+}
+
+func (bucket *tokenBucket) isFull(now time.Time) bool {
+	bucket.refill(now)
+	return bucket.tokens >= bucket.burst
+}
+
+func (bucket *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(bucket.updated); elapsed > 0 {
+		bucket.tokens = min(bucket.tokens+elapsed.Seconds()*bucket.rate, bucket.burst)
+		bucket.updated = now
+	}
+}
+
+// rateLimiter limits rate of sends of sender and rate of sends to each contact.
+type rateLimiter struct {
+	mutex    sync.Mutex
+	config   rateLimitConfig
+	sender   *tokenBucket
+	contacts map[string]*tokenBucket
+}
+
+func newRateLimiter(config rateLimitConfig, now time.Time) *rateLimiter {
+	limiter := &rateLimiter{
+		config:   config,
+		contacts: make(map[string]*tokenBucket),
+	}
+	if config.Rate > 0 {
+		limiter.sender = newTokenBucket(config.Rate, config.Burst, now)
+	}
+	return limiter
+}
+
+// take takes tokens of contact and sender, otherwise returns time left until both tokens are available.
+func (limiter *rateLimiter) take(contactID string, now time.Time) (bool, time.Duration) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	contact := limiter.getContactBucket(contactID, now)
+	if contact != nil {
+		if ok, wait := contact.take(now); !ok {
+			return false, wait
+		}
+	}
+	if limiter.sender != nil {
+		if ok, wait := limiter.sender.take(now); !ok {
+			if contact != nil {
+				contact.put()
+			}
+			return false, wait
+		}
+	}
+	return true, 0
+}
+
+func (limiter *rateLimiter) getContactBucket(contactID string, now time.Time) *tokenBucket {
+	if limiter.config.ContactRate <= 0 {
+		return nil
+	}
+	if bucket, ok := limiter.contacts[contactID]; ok {
+		return bucket
+	}
+	if len(limiter.contacts) >= contactBucketsCleanupSize {
+		for id, bucket := range limiter.contacts {
+			if bucket.isFull(now) {
+				delete(limiter.contacts, id)
+			}
+		}
+	}
+	bucket := newTokenBucket(limiter.config.ContactRate, limiter.config.ContactBurst, now)
+	limiter.contacts[contactID] = bucket
+	return bucket
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker opens after consecutive failures and lets single probe send after timeout.
+// Circuit is closed if probe succeeds and opened again if it fails.
+type circuitBreaker struct {
+	mutex     sync.Mutex
+	threshold int
+	timeout   time.Duration
+	failures  int
+	state     circuitState
+	openedAt  time.Time
+}
+
+func newCircuitBreaker(threshold int, timeout time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		timeout:   timeout,
+	}
+}
+
+// allow checks whether send is allowed, otherwise returns time left until the probe.
+func (breaker *circuitBreaker) allow(now time.Time) (bool, time.Duration) {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	switch breaker.state {
+	case circuitOpen:
+		if elapsed := now.Sub(breaker.openedAt); elapsed < breaker.timeout {
+			return false, breaker.timeout - elapsed
+		}
+		breaker.state = circuitHalfOpen
+		return true, 0
+	case circuitHalfOpen:
+		return false, breaker.timeout
+	default:
+		return true, 0
+	}
+}
+
+// cancelProbe opens circuit again if allowed probe was not sent.
+func (breaker *circuitBreaker) cancelProbe() {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	if breaker.state == circuitHalfOpen {
+		breaker.state = circuitOpen
+	}
+}
+
+func (breaker *circuitBreaker) succeed() {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	breaker.failures = 0
+	breaker.state = circuitClosed
+}
+
+// fail counts failed send, returns true if circuit was opened.
+func (breaker *circuitBreaker) fail(now time.Time) bool {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	breaker.failures++
+	if breaker.state == circuitHalfOpen || (breaker.state == circuitClosed && breaker.failures >= breaker.threshold) {
+		breaker.state = circuitOpen
+		breaker.openedAt = now
+		return true
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package notifier
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	"github.com/moira-alert/moira/metrics"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestNewSenderGuard(t *testing.T) {
+	Convey("Test newSenderGuard", t, func() {
+		Convey("Without limits guard is disabled", func() {
+			guard, err := newSenderGuard(map[string]interface{}{"sender_type": "slack"})
+			So(err, ShouldBeNil)
+			So(guard, ShouldBeNil)
+
+			ok, _, _ := guard.allow("contact", time.Now())
+			So(ok, ShouldBeTrue)
+			So(guard.done(false, time.Now()), ShouldBeFalse)
+		})
+
+		Convey("With limits from yaml", func() {
+			guard, err := newSenderGuard(map[string]interface{}{
+				"rate_limit": map[interface{}]interface{}{
+					"rate":          10,
+					"burst":         20,
+					"contact_rate":  0.5,
+					"contact_burst": 2,
+				},
+				"circuit_breaker": map[interface{}]interface{}{
+					"failures": 5,
+					"timeout":  "30s",
+				},
+			})
+			So(err, ShouldBeNil)
+			So(guard.limiter.config, ShouldResemble, rateLimitConfig{Rate: 10, Burst: 20, ContactRate: 0.5, ContactBurst: 2})
+			So(guard.breaker.threshold, ShouldEqual, 5)
+			So(guard.breaker.timeout, ShouldEqual, 30*time.Second)
+		})
+
+		Convey("Circuit breaker has default timeout", func() {
+			guard, err := newSenderGuard(map[string]interface{}{
+				"circuit_breaker": map[interface{}]interface{}{"failures": 3},
+			})
+			So(err, ShouldBeNil)
+			So(guard.limiter, ShouldBeNil)
+			So(guard.breaker.timeout, ShouldEqual, defaultCircuitBreakerTimeout)
+		})
+
+		Convey("Invalid settings", func() {
+			_, err := newSenderGuard(map[string]interface{}{
+				"circuit_breaker": map[interface{}]interface{}{"failures": 3, "timeout": "soon"},
+			})
+			So(err, ShouldNotBeNil)
+
+			_, err = newSenderGuard(map[string]interface{}{
+				"rate_limit": map[interface{}]interface{}{"rate": -1},
+			})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+
+	Convey("Test rateLimiter", t, func() {
+		Convey("Sender rate limit", func() {
+			limiter := newRateLimiter(rateLimitConfig{Rate: 2, Burst: 2}, now)
+
+			ok, _ := limiter.take("first", now)
+			So(ok, ShouldBeTrue)
+			ok, _ = limiter.take("second", now)
+			So(ok, ShouldBeTrue)
+			ok, wait := limiter.take("third", now)
+			So(ok, ShouldBeFalse)
+			So(wait, ShouldEqual, 500*time.Millisecond)
+
+			ok, _ = limiter.take("third", now.Add(500*time.Millisecond))
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("Contact rate limit", func() {
+			limiter := newRateLimiter(rateLimitConfig{ContactRate: 0.1}, now)
+
+			ok, _ := limiter.take("first", now)
+			So(ok, ShouldBeTrue)
+			ok, wait := limiter.take("first", now)
+			So(ok, ShouldBeFalse)
+			So(wait, ShouldEqual, 10*time.Second)
+
+			ok, _ = limiter.take("second", now)
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("Contact token is returned if sender limit is exceeded", func() {
+			limiter := newRateLimiter(rateLimitConfig{Rate: 1, ContactRate: 1, ContactBurst: 1}, now)
+
+			ok, _ := limiter.take("first", now)
+			So(ok, ShouldBeTrue)
+			ok, _ = limiter.take("second", now)
+			So(ok, ShouldBeFalse)
+			So(limiter.contacts["second"].tokens, ShouldEqual, 1)
+		})
+	})
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+
+	Convey("Test circuitBreaker", t, func() {
+		breaker := newCircuitBreaker(2, time.Minute)
+
+		So(breaker.fail(now), ShouldBeFalse)
+		So(breaker.fail(now), ShouldBeTrue)
+
+		ok, wait := breaker.allow(now.Add(20 * time.Second))
+		So(ok, ShouldBeFalse)
+		So(wait, ShouldEqual, 40*time.Second)
+
+		Convey("Failed probe opens circuit again", func() {
+			ok, _ = breaker.allow(now.Add(time.Minute))
+			So(ok, ShouldBeTrue)
+			ok, _ = breaker.allow(now.Add(time.Minute))
+			So(ok, ShouldBeFalse)
+
+			So(breaker.fail(now.Add(time.Minute)), ShouldBeTrue)
+			ok, _ = breaker.allow(now.Add(time.Minute + time.Second))
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Successful probe closes circuit", func() {
+			ok, _ = breaker.allow(now.Add(time.Minute))
+			So(ok, ShouldBeTrue)
+
+			breaker.succeed()
+			ok, _ = breaker.allow(now.Add(time.Minute))
+			So(ok, ShouldBeTrue)
+			So(breaker.fail(now.Add(time.Minute)), ShouldBeFalse)
+		})
+
+		Convey("Cancelled probe can be sent again", func() {
+			ok, _ = breaker.allow(now.Add(time.Minute))
+			So(ok, ShouldBeTrue)
+
+			breaker.cancelProbe()
+			ok, _ = breaker.allow(now.Add(time.Minute))
+			So(ok, ShouldBeTrue)
+		})
+	})
+}
+
+func TestPostponeRateLimitedPackage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	database := mock_moira_alert.NewMockDatabase(mockCtrl)
+	limitedSender := mock_moira_alert.NewMockSender(mockCtrl)
+	log, _ := logging.GetLogger("Notifier")
+	notifierMetrics := metrics.ConfigureNotifierMetrics(metrics.NewDummyRegistry(), "notifier")
+
+	notifier := NewNotifier(database, log, defaultConfig, notifierMetrics, nil, map[string]moira.ImageStore{})
+	senderSettings := map[string]interface{}{
+		"sender_type":  "test_type",
+		"contact_type": "test_contact_type",
+		"rate_limit": map[interface{}]interface{}{
+			"contact_rate": 0.01,
+		},
+	}
+	limitedSender.EXPECT().Init(senderSettings, log, location, dateTimeFormat).Return(nil)
+
+	Convey("Second package to contact is postponed", t, func() {
+		So(notifier.RegisterSender(senderSettings, limitedSender), ShouldBeNil)
+		defer notifier.StopSenders()
+
+		pkg := NotificationPackage{
+			Events:    []moira.NotificationEvent{event},
+			Trigger:   moira.TriggerData{ID: "triggerID-0000000000001"},
+			Contact:   moira.ContactData{ID: "contactID", Type: "test_contact_type", Value: "contact"},
+			FailCount: 1,
+		}
+
+		database.EXPECT().GetContact(pkg.Contact.ID).Return(pkg.Contact, nil).Times(2)
+		sent := make(chan struct{})
+		limitedSender.EXPECT().SendEvents(gomock.Any(), pkg.Contact, pkg.Trigger, gomock.Any(), false).
+			Return(nil).Do(func(moira.NotificationEvents, moira.ContactData, moira.TriggerData, [][]byte, bool) { close(sent) })
+		database.EXPECT().SaveDeliveryAttempt(gomock.Any()).Return(nil)
+
+		var wg sync.WaitGroup
+		notifier.Send(&pkg, &wg)
+		wg.Wait()
+		<-sent
+
+		postponed := make(chan *moira.ScheduledNotification, 1)
+		database.EXPECT().AddNotification(gomock.Any()).Return(nil).Do(func(notification *moira.ScheduledNotification) {
+			postponed <- notification
+		})
+
+		notifier.Send(&pkg, &wg)
+		wg.Wait()
+
+		notification := <-postponed
+		So(notification.Event, ShouldResemble, event)
+		So(notification.Contact, ShouldResemble, pkg.Contact)
+		So(notification.SendFail, ShouldEqual, pkg.FailCount)
+		So(notification.Timestamp, ShouldBeGreaterThan, time.Now().Add(time.Minute).Unix())
+	})
+}
@@ -0,0 +1,78 @@
+package plotting
+
+import (
+	"sort"
+	"time"
+
+	"github.com/moira-alert/go-chart"
+	"github.com/moira-alert/moira"
+)
+
+// stateMarkerSerie is a name that indicates vertical line of state change.
+const stateMarkerSerie = "state marker"
+
+// StateMarker is the moment when metric or trigger changed its state.
+type StateMarker struct {
+	Timestamp int64
+	State     moira.State
+}
+
+// Annotations are parts of trigger history shown on plot: state changes drawn as vertical lines
+// and state intervals, such as maintenance periods, shaded behind curves.
+type Annotations struct {
+	StateMarkers   []StateMarker
+	StateIntervals []moira.MetricStateInterval
+}
+
+// GetEventsAnnotations returns state changes and maintenance periods of notification events.
+func GetEventsAnnotations(events []moira.NotificationEvent) Annotations {
+	annotations := Annotations{
+		StateMarkers:   make([]StateMarker, 0, len(events)),
+		StateIntervals: make([]moira.MetricStateInterval, 0),
+	}
+	for _, event := range events {
+		annotations.StateMarkers = append(annotations.StateMarkers, StateMarker{
+			Timestamp: event.Timestamp,
+			State:     event.State,
+		})
+
+		if event.MessageEventInfo == nil || event.MessageEventInfo.Maintenance == nil {
+			continue
+		}
+		maintenance := event.MessageEventInfo.Maintenance
+		if maintenance.StartTime == nil || maintenance.StopTime == nil {
+			continue
+		}
+		annotations.StateIntervals = append(annotations.StateIntervals, moira.MetricStateInterval{
+			From:        *maintenance.StartTime,
+			To:          *maintenance.StopTime,
+			State:       event.OldState,
+			Maintenance: true,
+		})
+	}
+	sort.Slice(annotations.StateMarkers, func(i, j int) bool {
+		return annotations.StateMarkers[i].Timestamp < annotations.StateMarkers[j].Timestamp
+	})
+	return annotations
+}
+
+// getStateMarkerSeriesList returns series of vertical lines crossing the whole plot height at the moments of state changes.
+// Markers outside of plot limits are skipped.
+func getStateMarkerSeriesList(markers []StateMarker, theme moira.PlotTheme, limits plotLimits) []chart.Series {
+	markerSeriesList := make([]chart.Series, 0, len(markers))
+	for _, marker := range markers {
+		timestamp := moira.Int64ToTime(marker.Timestamp)
+		if timestamp.Before(limits.from) || timestamp.After(limits.to) {
+			continue
+		}
+
+		markerSeriesList = append(markerSeriesList, chart.TimeSeries{
+			Name:    stateMarkerSerie,
+			YAxis:   chart.YAxisSecondary,
+			Style:   theme.GetStateMarkerStyle(marker.State),
+			XValues: []time.Time{timestamp, timestamp},
+			YValues: []float64{limits.lowest, limits.highest},
+		})
+	}
+	return markerSeriesList
+}
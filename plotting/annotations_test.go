@@ -0,0 +1,121 @@
+package plotting
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/go-chart"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
+)
+
+func TestGetEventsAnnotations(t *testing.T) {
+	Convey("Test events annotations", t, func() {
+		startTime, stopTime := int64(110), int64(140)
+		events := []moira.NotificationEvent{
+			{Timestamp: 180, State: moira.StateOK, OldState: moira.StateERROR},
+			{
+				Timestamp:        150,
+				State:            moira.StateERROR,
+				OldState:         moira.StateWARN,
+				MessageEventInfo: &moira.EventInfo{Maintenance: &moira.MaintenanceInfo{StartTime: &startTime, StopTime: &stopTime}},
+			},
+			{
+				Timestamp:        120,
+				State:            moira.StateWARN,
+				MessageEventInfo: &moira.EventInfo{Maintenance: &moira.MaintenanceInfo{StartTime: &startTime}},
+			},
+		}
+
+		annotations := GetEventsAnnotations(events)
+		So(annotations.StateMarkers, ShouldResemble, []StateMarker{
+			{Timestamp: 120, State: moira.StateWARN},
+			{Timestamp: 150, State: moira.StateERROR},
+			{Timestamp: 180, State: moira.StateOK},
+		})
+		So(annotations.StateIntervals, ShouldResemble, []moira.MetricStateInterval{
+			{From: 110, To: 140, State: moira.StateWARN, Maintenance: true},
+		})
+	})
+}
+
+func TestGetStateMarkerSeriesList(t *testing.T) {
+	Convey("Test state marker series", t, func() {
+		theme, err := getPlotTheme(darkPlotTheme)
+		So(err, ShouldBeNil)
+
+		limits := plotLimits{
+			from:    moira.Int64ToTime(100),
+			to:      moira.Int64ToTime(200),
+			lowest:  -10,
+			highest: 50,
+		}
+		markers := []StateMarker{
+			{Timestamp: 50, State: moira.StateERROR},
+			{Timestamp: 150, State: moira.StateOK},
+			{Timestamp: 250, State: moira.StateWARN},
+		}
+
+		seriesList := getStateMarkerSeriesList(markers, theme, limits)
+		So(seriesList, ShouldHaveLength, 1)
+
+		series := seriesList[0].(chart.TimeSeries)
+		So(series.Name, ShouldEqual, stateMarkerSerie)
+		So(series.Style, ShouldResemble, theme.GetStateMarkerStyle(moira.StateOK))
+		So(series.XValues, ShouldResemble, []time.Time{moira.Int64ToTime(150), moira.Int64ToTime(150)})
+		So(series.YValues, ShouldResemble, []float64{-10, 50})
+	})
+}
+
+func TestPlotOptions(t *testing.T) {
+	location, _ := time.LoadLocation("UTC")
+	trigger := &moira.Trigger{ID: "trigger", Name: "Trigger"}
+	metricsData := map[string][]metricSource.MetricData{
+		"t2": {{Name: "second", StartTime: 0, StepTime: 10, StopTime: 30, Values: []float64{1, 2, 3}}},
+		"t1": {{Name: "first", StartTime: 0, StepTime: 10, StopTime: 30, Values: []float64{3, 2, 1}}},
+	}
+
+	Convey("Test plot options", t, func() {
+		plotTemplate, err := GetPlotTemplate(lightPlotTheme, location)
+		So(err, ShouldBeNil)
+
+		Convey("Size is changed only if set", func() {
+			resized := plotTemplate.WithSize(1024, 0)
+			So(resized.width, ShouldEqual, 1024)
+			So(resized.height, ShouldEqual, plotTemplate.height)
+			So(plotTemplate.width, ShouldEqual, 800)
+		})
+
+		Convey("Targets are drawn on one plot", func() {
+			renderable, err := plotTemplate.GetOverlayRenderable(trigger, metricsData, Annotations{})
+			So(err, ShouldBeNil)
+			So(renderable.Title, ShouldEqual, "Trigger")
+
+			names := make([]string, 0)
+			for _, series := range renderable.Series {
+				if series.GetName() != thresholdSerie {
+					names = append(names, series.GetName())
+				}
+			}
+			So(names, ShouldResemble, []string{"t1: first", "t2: second"})
+		})
+
+		Convey("Plot is rendered to SVG", func() {
+			renderable, err := plotTemplate.GetRenderableWithAnnotations("t1", trigger, metricsData["t1"], Annotations{
+				StateMarkers: []StateMarker{{Timestamp: 10, State: moira.StateERROR}},
+			})
+			So(err, ShouldBeNil)
+
+			buff := bytes.NewBuffer(make([]byte, 0))
+			So(Render(renderable, moira.PlotFormatSVG, buff), ShouldBeNil)
+			So(moira.GetPlotFormat(buff.Bytes()), ShouldEqual, moira.PlotFormatSVG)
+
+			buff.Reset()
+			So(Render(renderable, moira.PlotFormatPNG, buff), ShouldBeNil)
+			So(moira.GetPlotFormat(buff.Bytes()), ShouldEqual, moira.PlotFormatPNG)
+		})
+	})
+}
@@ -1,7 +1,11 @@
 package plotting
 
 import (
+	"encoding/xml"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/moira-alert/go-chart"
@@ -48,11 +52,56 @@ func GetPlotTemplate(theme string, location *time.Location) (*Plot, error) {
 	}, nil
 }
 
+// WithSize returns copy of plot template with given size, zero width or height keeps the size of template.
+func (plot *Plot) WithSize(width, height int) *Plot {
+	result := *plot
+	if width > 0 {
+		result.width = width
+	}
+	if height > 0 {
+		result.height = height
+	}
+	return &result
+}
+
 // GetRenderable returns go-chart to render.
 func (plot *Plot) GetRenderable(targetName string, trigger *moira.Trigger, metricsData []metricSource.MetricData) (chart.Chart, error) {
-	var renderable chart.Chart
+	return plot.GetRenderableWithAnnotations(targetName, trigger, metricsData, Annotations{})
+}
+
+// GetRenderableWithStates returns go-chart to render with shaded metric state intervals behind curves.
+func (plot *Plot) GetRenderableWithStates(targetName string, trigger *moira.Trigger, metricsData []metricSource.MetricData, stateIntervals []moira.MetricStateInterval) (chart.Chart, error) {
+	return plot.GetRenderableWithAnnotations(targetName, trigger, metricsData, Annotations{StateIntervals: stateIntervals})
+}
+
+// GetRenderableWithAnnotations returns go-chart to render with state changes and state intervals of trigger history.
+func (plot *Plot) GetRenderableWithAnnotations(targetName string, trigger *moira.Trigger, metricsData []metricSource.MetricData, annotations Annotations) (chart.Chart, error) {
+	name := fmt.Sprintf("%s - %s", targetName, trigger.Name)
+	return plot.getRenderable(name, trigger, metricsData, annotations)
+}
+
+// GetOverlayRenderable returns go-chart to render with metrics of all targets drawn on one plot.
+// Metric names are prefixed with their target names.
+func (plot *Plot) GetOverlayRenderable(trigger *moira.Trigger, metricsData map[string][]metricSource.MetricData, annotations Annotations) (chart.Chart, error) {
+	targetNames := make([]string, 0, len(metricsData))
+	for targetName := range metricsData {
+		targetNames = append(targetNames, targetName)
+	}
+	sort.Strings(targetNames)
+
+	overlayMetricsData := make([]metricSource.MetricData, 0)
+	for _, targetName := range targetNames {
+		for _, metricData := range metricsData[targetName] {
+			metricData.Name = fmt.Sprintf("%s: %s", targetName, metricData.Name)
+			overlayMetricsData = append(overlayMetricsData, metricData)
+		}
+	}
+
+	return plot.getRenderable(trigger.Name, trigger, overlayMetricsData, annotations)
+}
 
-	plotSeries := make([]chart.Series, 0)
+func (plot *Plot) getRenderable(name string, trigger *moira.Trigger, metricsData []metricSource.MetricData, annotations Annotations) (chart.Chart, error) {
+	var renderable chart.Chart
 
 	limits := resolveLimits(metricsData)
 
@@ -61,10 +110,14 @@ func (plot *Plot) GetRenderable(targetName string, trigger *moira.Trigger, metri
 		return renderable, ErrNoPointsToRender{triggerID: trigger.ID}
 	}
 
+	plotSeries := getStateIntervalSeriesList(annotations.StateIntervals, plot.theme, limits)
+
 	for _, curveSeries := range curveSeriesList {
 		plotSeries = append(plotSeries, curveSeries)
 	}
 
+	plotSeries = append(plotSeries, getStateMarkerSeriesList(annotations.StateMarkers, plot.theme, limits)...)
+
 	thresholdSeriesList := getThresholdSeriesList(trigger, plot.theme, limits)
 	plotSeries = append(plotSeries, thresholdSeriesList...)
 
@@ -73,7 +126,6 @@ func (plot *Plot) GetRenderable(targetName string, trigger *moira.Trigger, metri
 	yAxisValuesFormatter, maxMarkLen := getYAxisValuesFormatter(limits)
 	yAxisRange := limits.getThresholdAxisRange(trigger.TriggerType)
 
-	name := fmt.Sprintf("%s - %s", targetName, trigger.Name)
 	renderable = chart.Chart{
 		Title:      sanitizeLabelName(name, plotNameLen),
 		TitleStyle: plot.theme.GetTitleStyle(),
@@ -125,3 +177,34 @@ func (plot *Plot) GetRenderable(targetName string, trigger *moira.Trigger, metri
 
 	return renderable, nil
 }
+
+// Render writes renderable to writer in given format, plots are rendered to PNG by default.
+func Render(renderable chart.Chart, format moira.PlotFormat, writer io.Writer) error {
+	switch format {
+	case moira.PlotFormatSVG:
+		return renderable.Render(escapedSVG, writer)
+	default:
+		return renderable.Render(chart.PNG, writer)
+	}
+}
+
+// escapedSVG provides SVG renderer which escapes text, go-chart writes text to SVG as is,
+// but title, legend and series names come from names of triggers and metrics.
+func escapedSVG(width, height int) (chart.Renderer, error) {
+	renderer, err := chart.SVG(width, height)
+	if err != nil {
+		return nil, err
+	}
+	return escapedSVGRenderer{Renderer: renderer}, nil
+}
+
+type escapedSVGRenderer struct {
+	chart.Renderer
+}
+
+// Text draws escaped text, text is measured unescaped as it is shown.
+func (renderer escapedSVGRenderer) Text(body string, x, y int) {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(body)) //nolint:errcheck
+	renderer.Renderer.Text(escaped.String(), x, y)
+}
@@ -698,3 +698,25 @@ func TestErrNoPointsToRender_Error(t *testing.T) {
 		}
 	})
 }
+
+func TestRenderSVG(t *testing.T) {
+	Convey("Text of SVG plot is escaped", t, func() {
+		plotTemplate, err := GetPlotTemplate("", time.UTC)
+		So(err, ShouldBeNil)
+		trigger := moira.Trigger{ID: "trigger", Name: "<script>alert(1)</script>"}
+		metricsData := []metricSource.MetricData{
+			{Name: `<a href="x">metric</a>`, StartTime: 0, StepTime: 10, StopTime: 30, Values: []float64{1, 2, 3}},
+		}
+
+		renderable, err := plotTemplate.GetRenderable("t1", &trigger, metricsData)
+		So(err, ShouldBeNil)
+		var buffer bytes.Buffer
+		err = Render(renderable, moira.PlotFormatSVG, &buffer)
+		So(err, ShouldBeNil)
+
+		So(buffer.String(), ShouldNotContainSubstring, "<script>")
+		So(buffer.String(), ShouldNotContainSubstring, "<a ")
+		So(buffer.String(), ShouldContainSubstring, "t1 - &lt;script&gt;")
+		So(buffer.String(), ShouldContainSubstring, "&lt;a href=&#34;x&#34;&gt;")
+	})
+}
@@ -0,0 +1,44 @@
+package plotting
+
+import (
+	"time"
+
+	"github.com/moira-alert/go-chart"
+	"github.com/moira-alert/moira"
+)
+
+// stateIntervalSerie is a name that indicates shaded metric state interval.
+const stateIntervalSerie = "state interval"
+
+// getStateIntervalSeriesList returns series shading the whole plot height during metric state intervals.
+// Intervals without style, such as OK ones, and intervals outside of plot limits are skipped.
+func getStateIntervalSeriesList(intervals []moira.MetricStateInterval, theme moira.PlotTheme, limits plotLimits) []chart.Series {
+	stateSeriesList := make([]chart.Series, 0)
+	for _, interval := range intervals {
+		style := theme.GetStateIntervalStyle(interval.State, interval.Maintenance)
+		if !style.Show {
+			continue
+		}
+
+		from := moira.Int64ToTime(interval.From)
+		if from.Before(limits.from) {
+			from = limits.from
+		}
+		to := moira.Int64ToTime(interval.To)
+		if to.After(limits.to) {
+			to = limits.to
+		}
+		if !from.Before(to) {
+			continue
+		}
+
+		stateSeriesList = append(stateSeriesList, chart.TimeSeries{
+			Name:    stateIntervalSerie,
+			YAxis:   chart.YAxisSecondary,
+			Style:   style,
+			XValues: []time.Time{from, to},
+			YValues: []float64{limits.highest, limits.highest},
+		})
+	}
+	return stateSeriesList
+}
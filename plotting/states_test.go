@@ -0,0 +1,50 @@
+package plotting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moira-alert/go-chart"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+)
+
+func TestGetStateIntervalSeriesList(t *testing.T) {
+	Convey("Test state interval series", t, func() {
+		theme, err := getPlotTheme(lightPlotTheme)
+		So(err, ShouldBeNil)
+
+		limits := plotLimits{
+			from:    moira.Int64ToTime(100),
+			to:      moira.Int64ToTime(200),
+			lowest:  0,
+			highest: 50,
+		}
+
+		Convey("OK intervals are not shaded", func() {
+			intervals := []moira.MetricStateInterval{{From: 100, To: 200, State: moira.StateOK}}
+			So(getStateIntervalSeriesList(intervals, theme, limits), ShouldBeEmpty)
+		})
+
+		Convey("Intervals are cut by plot limits", func() {
+			intervals := []moira.MetricStateInterval{
+				{From: 50, To: 90, State: moira.StateERROR},
+				{From: 50, To: 120, State: moira.StateERROR},
+				{From: 150, To: 250, State: moira.StateOK, Maintenance: true},
+			}
+			seriesList := getStateIntervalSeriesList(intervals, theme, limits)
+			So(seriesList, ShouldHaveLength, 2)
+
+			errorSeries := seriesList[0].(chart.TimeSeries)
+			So(errorSeries.Name, ShouldEqual, stateIntervalSerie)
+			So(errorSeries.Style, ShouldResemble, theme.GetStateIntervalStyle(moira.StateERROR, false))
+			So(errorSeries.XValues, ShouldResemble, []time.Time{moira.Int64ToTime(100), moira.Int64ToTime(120)})
+			So(errorSeries.YValues, ShouldResemble, []float64{50, 50})
+
+			maintenanceSeries := seriesList[1].(chart.TimeSeries)
+			So(maintenanceSeries.Style, ShouldResemble, theme.GetStateIntervalStyle(moira.StateOK, true))
+			So(maintenanceSeries.XValues, ShouldResemble, []time.Time{moira.Int64ToTime(150), moira.Int64ToTime(200)})
+		})
+	})
+}
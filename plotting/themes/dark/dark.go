@@ -4,6 +4,7 @@ import (
 	"github.com/golang/freetype/truetype"
 	"github.com/moira-alert/go-chart"
 	"github.com/moira-alert/go-chart/drawing"
+	"github.com/moira-alert/moira"
 )
 
 // PlotTheme implements moira.PlotTheme interface.
@@ -138,6 +139,54 @@ func (theme *PlotTheme) GetSerieStyles(curveInd int) (chart.Style, chart.Style)
 	return curveStyle, pointStyle
 }
 
+// GetStateIntervalStyle returns style of background area of metric state interval.
+func (theme *PlotTheme) GetStateIntervalStyle(state moira.State, maintenance bool) chart.Style {
+	var intervalColor string
+	switch {
+	case maintenance:
+		intervalColor = `4cb5f5`
+	case state == moira.StateERROR:
+		intervalColor = `ed2e18`
+	case state == moira.StateWARN:
+		intervalColor = `f79520`
+	case state == moira.StateNODATA:
+		intervalColor = `a9a9a9`
+	case state == moira.StateEXCEPTION:
+		intervalColor = `b05ce6`
+	default:
+		return chart.Style{Show: false}
+	}
+	return chart.Style{
+		Show:        true,
+		StrokeWidth: chart.Disabled,
+		StrokeColor: drawing.ColorTransparent,
+		FillColor:   drawing.ColorFromHex(intervalColor).WithAlpha(25), //nolint
+	}
+}
+
+// GetStateMarkerStyle returns style of vertical line drawn at the moment of state change.
+func (theme *PlotTheme) GetStateMarkerStyle(state moira.State) chart.Style {
+	var markerColor string
+	switch state {
+	case moira.StateOK:
+		markerColor = `89da59`
+	case moira.StateERROR:
+		markerColor = `ed2e18`
+	case moira.StateWARN:
+		markerColor = `f79520`
+	case moira.StateNODATA:
+		markerColor = `a9a9a9`
+	default:
+		markerColor = `b05ce6`
+	}
+	return chart.Style{
+		Show:            true,
+		StrokeWidth:     1,
+		StrokeDashArray: []float64{4, 2},                                  //nolint
+		StrokeColor:     drawing.ColorFromHex(markerColor).WithAlpha(200), //nolint
+	}
+}
+
 // GetLegendStyle returns legend style.
 func (theme *PlotTheme) GetLegendStyle() chart.Style {
 	return chart.Style{
@@ -4,6 +4,7 @@ import (
 	"github.com/golang/freetype/truetype"
 	"github.com/moira-alert/go-chart"
 	"github.com/moira-alert/go-chart/drawing"
+	"github.com/moira-alert/moira"
 )
 
 // PlotTheme implements moira.PlotTheme interface.
@@ -138,6 +139,54 @@ func (theme *PlotTheme) GetSerieStyles(curveInd int) (chart.Style, chart.Style)
 	return curveStyle, pointStyle
 }
 
+// GetStateIntervalStyle returns style of background area of metric state interval.
+func (theme *PlotTheme) GetStateIntervalStyle(state moira.State, maintenance bool) chart.Style {
+	var intervalColor string
+	switch {
+	case maintenance:
+		intervalColor = `375e97`
+	case state == moira.StateERROR:
+		intervalColor = `8b0000`
+	case state == moira.StateWARN:
+		intervalColor = `cccc00`
+	case state == moira.StateNODATA:
+		intervalColor = `808080`
+	case state == moira.StateEXCEPTION:
+		intervalColor = `4b0082`
+	default:
+		return chart.Style{Show: false}
+	}
+	return chart.Style{
+		Show:        true,
+		StrokeWidth: chart.Disabled,
+		StrokeColor: drawing.ColorTransparent,
+		FillColor:   drawing.ColorFromHex(intervalColor).WithAlpha(25), //nolint
+	}
+}
+
+// GetStateMarkerStyle returns style of vertical line drawn at the moment of state change.
+func (theme *PlotTheme) GetStateMarkerStyle(state moira.State) chart.Style {
+	var markerColor string
+	switch state {
+	case moira.StateOK:
+		markerColor = `2e8b57`
+	case moira.StateERROR:
+		markerColor = `8b0000`
+	case moira.StateWARN:
+		markerColor = `cccc00`
+	case moira.StateNODATA:
+		markerColor = `808080`
+	default:
+		markerColor = `4b0082`
+	}
+	return chart.Style{
+		Show:            true,
+		StrokeWidth:     1,
+		StrokeDashArray: []float64{4, 2},                                  //nolint
+		StrokeColor:     drawing.ColorFromHex(markerColor).WithAlpha(200), //nolint
+	}
+}
+
 // GetLegendStyle returns legend style.
 func (theme *PlotTheme) GetLegendStyle() chart.Style {
 	return chart.Style{
@@ -0,0 +1,124 @@
+package senders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+const chatResponseMaxLogLength = 512
+
+// ChatResponseError is the unsuccessful response of chat API.
+type ChatResponseError struct {
+	StatusCode int
+	Body       string
+}
+
+func (err ChatResponseError) Error() string {
+	return fmt.Sprintf("chat API responded with status %d: %s", err.StatusCode, TruncateMessage(err.Body, chatResponseMaxLogLength))
+}
+
+// ChatClient sends requests to HTTP API of chat messenger.
+type ChatClient struct {
+	client    *http.Client
+	userAgent string
+}
+
+// NewChatClient creates chat client, requests are cancelled after timeout.
+func NewChatClient(timeout time.Duration) *ChatClient {
+	return &ChatClient{
+		client:    &http.Client{Timeout: timeout},
+		userAgent: "Moira",
+	}
+}
+
+// NewJSONRequest creates request with JSON encoded body.
+func NewJSONRequest(method, url string, body interface{}) (*http.Request, error) {
+	requestBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(context.Background(), method, url, bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	return request, nil
+}
+
+// Do performs request and decodes JSON response to result if it is not nil.
+// Responses with status 403, 404 and 410 mean that chat doesn't exist or bot has no access to it,
+// such errors are returned as moira.SenderBrokenContactError. Responses with status 400, 413 and 422 mean
+// that message was rejected, such errors are returned as moira.SenderPermanentError.
+func (client *ChatClient) Do(request *http.Request, result interface{}) error {
+	request.Header.Set("User-Agent", client.userAgent)
+	response, err := client.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		responseErr := ChatResponseError{StatusCode: response.StatusCode, Body: string(body)}
+		if isBrokenContactStatus(response.StatusCode) {
+			return moira.NewSenderBrokenContactError(responseErr)
+		}
+		if isPermanentErrorStatus(response.StatusCode) {
+			return moira.NewSenderPermanentError(responseErr)
+		}
+		return responseErr
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err = json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// WrapChatError adds context to error of chat API, moira.SenderBrokenContactError and moira.SenderPermanentError
+// keep their types, so notifier doesn't retry sending.
+func WrapChatError(err error, message string) error {
+	var brokenContactErr moira.SenderBrokenContactError
+	if errors.As(err, &brokenContactErr) {
+		return moira.NewSenderBrokenContactError(fmt.Errorf("%s: %w", message, brokenContactErr.SenderError))
+	}
+	var permanentErr moira.SenderPermanentError
+	if errors.As(err, &permanentErr) {
+		return moira.NewSenderPermanentError(fmt.Errorf("%s: %w", message, permanentErr.SenderError))
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+func isBrokenContactStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusForbidden, http.StatusNotFound, http.StatusGone:
+		return true
+	default:
+		return false
+	}
+}
+
+func isPermanentErrorStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return true
+	default:
+		return false
+	}
+}
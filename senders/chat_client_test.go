@@ -0,0 +1,68 @@
+package senders
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestChatClient(t *testing.T) {
+	client := NewChatClient(time.Second)
+
+	Convey("Chat client", t, func() {
+		var statusCode int
+		var responseBody, requestBody string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requestBody = string(body)
+			w.WriteHeader(statusCode)
+			w.Write([]byte(responseBody)) //nolint
+		}))
+		defer server.Close()
+
+		request, err := NewJSONRequest(http.MethodPost, server.URL, map[string]string{"text": "message"})
+		So(err, ShouldBeNil)
+
+		Convey("Successful response is decoded", func() {
+			statusCode, responseBody = http.StatusOK, `{"id":"123"}`
+			var result struct {
+				ID string `json:"id"`
+			}
+			err = client.Do(request, &result)
+			So(err, ShouldBeNil)
+			So(result.ID, ShouldEqual, "123")
+			So(requestBody, ShouldEqual, `{"text":"message"}`)
+		})
+
+		Convey("Missing chat is broken contact", func() {
+			statusCode, responseBody = http.StatusNotFound, `{"error":"not found"}`
+			err = client.Do(request, nil)
+			So(err, ShouldHaveSameTypeAs, moira.SenderBrokenContactError{})
+			So(WrapChatError(err, "failed to send"), ShouldHaveSameTypeAs, moira.SenderBrokenContactError{})
+		})
+
+		Convey("Rejected message is permanent error", func() {
+			statusCode, responseBody = http.StatusRequestEntityTooLarge, "too large"
+			err = client.Do(request, nil)
+			So(err, ShouldHaveSameTypeAs, moira.SenderPermanentError{})
+			So(WrapChatError(err, "failed to send"), ShouldHaveSameTypeAs, moira.SenderPermanentError{})
+			var responseErr ChatResponseError
+			So(errors.As(err, &responseErr), ShouldBeTrue)
+			So(responseErr.StatusCode, ShouldEqual, http.StatusRequestEntityTooLarge)
+		})
+
+		Convey("Server error is returned as response error", func() {
+			statusCode, responseBody = http.StatusInternalServerError, "internal error"
+			err = client.Do(request, nil)
+			var responseErr ChatResponseError
+			So(errors.As(err, &responseErr), ShouldBeTrue)
+			So(responseErr, ShouldResemble, ChatResponseError{StatusCode: http.StatusInternalServerError, Body: "internal error"})
+		})
+	})
+}
@@ -0,0 +1,172 @@
+package senders
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+const (
+	chatThrottleMessageFormat = "\nPlease, %s to generate less events."
+	chatThrottleAdvice        = "fix your system or tune this trigger"
+)
+
+// ChatMarkup describes text formatting of chat messenger.
+type ChatMarkup struct {
+	Bold   func(text string) string
+	Italic func(text string) string
+	Link   func(text, url string) string
+	// CodeBlock is the fence list of events is wrapped with.
+	CodeBlock string
+	// Description converts trigger description to markup of messenger, description is used as is if it is nil.
+	Description func(desc string) string
+}
+
+// MarkdownChatMarkup is the formatting of messengers which support common markdown.
+var MarkdownChatMarkup = ChatMarkup{
+	Bold:      func(text string) string { return "**" + text + "**" },
+	Italic:    func(text string) string { return "*" + text + "*" },
+	Link:      func(text, url string) string { return fmt.Sprintf("[%s](%s)", text, url) },
+	CodeBlock: "```",
+}
+
+// ChatMessageBuilder builds notification messages of chat senders.
+// Default message consists of title with state, trigger link and tags, trigger description and list of events,
+// description and events are cut to fit the message length limit of messenger.
+type ChatMessageBuilder struct {
+	Markup   ChatMarkup
+	FrontURI string
+	Location *time.Location
+	// MaxChars limits length of message, message is not limited if it is zero.
+	MaxChars int
+	// StateEmoji returns emoji put before state in title, emoji is not used if it is nil.
+	StateEmoji func(state moira.State) string
+	// Template is populated instead of default message if contact or sender has message template.
+	Template MessageTemplate
+}
+
+// BuildContactMessage populates message template of contact or sender if it is set, otherwise builds default message.
+func (builder ChatMessageBuilder) BuildContactMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) string {
+	if message, ok := builder.Template.Render(events, contact, trigger, throttled); ok {
+		return message
+	}
+	return builder.BuildMessage(events, trigger, throttled)
+}
+
+// BuildMessage builds default message.
+func (builder ChatMessageBuilder) BuildMessage(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
+	var message strings.Builder
+	title := builder.buildTitle(events, trigger, throttled)
+
+	desc := trigger.Desc
+	if desc != "" {
+		if builder.Markup.Description != nil {
+			desc = builder.Markup.Description(desc)
+		}
+		desc += "\n"
+	}
+	descLen := len([]rune(desc))
+
+	eventsString := builder.buildEventsString(events, -1, throttled)
+	eventsStringLen := len([]rune(eventsString))
+
+	if builder.MaxChars > 0 {
+		charsLeftAfterTitle := builder.MaxChars - len([]rune(title))
+		descNewLen, eventsNewLen := CalculateMessagePartsLength(charsLeftAfterTitle, descLen, eventsStringLen)
+		if descLen != descNewLen {
+			desc = string([]rune(desc)[:descNewLen]) + "...\n"
+		}
+		if eventsNewLen != eventsStringLen {
+			eventsString = builder.buildEventsString(events, eventsNewLen, throttled)
+		}
+	}
+
+	message.WriteString(title)
+	message.WriteString(desc)
+	message.WriteString(eventsString)
+	return message.String()
+}
+
+func (builder ChatMessageBuilder) buildTitle(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
+	state := events.GetCurrentState(throttled)
+	title := ""
+	if builder.StateEmoji != nil {
+		title += builder.StateEmoji(state) + " "
+	}
+
+	title += builder.Markup.Bold(string(state))
+	triggerURI := trigger.GetTriggerURI(builder.FrontURI)
+	if triggerURI != "" {
+		title += " " + builder.Markup.Link(trigger.Name, triggerURI)
+	} else if trigger.Name != "" {
+		title += " " + trigger.Name
+	}
+
+	tags := trigger.GetTags()
+	if tags != "" {
+		title += " " + tags
+	}
+
+	title += "\n"
+	return title
+}
+
+// buildEventsString builds the string from moira events and limits it to charsForEvents.
+// If charsForEvents is negative buildEventsString does not limit the events string.
+func (builder ChatMessageBuilder) buildEventsString(events moira.NotificationEvents, charsForEvents int, throttled bool) string {
+	charsForThrottleMsg := 0
+	throttleMsg := fmt.Sprintf(chatThrottleMessageFormat, builder.Markup.Italic(chatThrottleAdvice))
+	if throttled {
+		charsForThrottleMsg = len([]rune(throttleMsg))
+	}
+	charsLeftForEvents := charsForEvents - charsForThrottleMsg
+
+	codeBlock := builder.Markup.CodeBlock
+	eventsString := codeBlock
+	var tailString string
+
+	eventsLenLimitReached := false
+	eventsPrinted := 0
+	for _, event := range events {
+		line := fmt.Sprintf(
+			"\n%s: %s = %s (%s to %s)",
+			event.FormatTimestamp(builder.Location, moira.DefaultTimeFormat),
+			event.Metric,
+			event.GetMetricsValues(moira.DefaultNotificationSettings),
+			event.OldState,
+			event.State,
+		)
+		if msg := event.CreateMessage(builder.Location); len(msg) > 0 {
+			line += fmt.Sprintf(". %s", msg)
+		}
+
+		tailString = fmt.Sprintf("\n...and %d more events.", len(events)-eventsPrinted)
+		tailStringLen := len([]rune(codeBlock)) + len([]rune(tailString))
+		if !(charsForEvents < 0) && (len([]rune(eventsString))+len([]rune(line)) > charsLeftForEvents-tailStringLen) {
+			eventsLenLimitReached = true
+			break
+		}
+
+		eventsString += line
+		eventsPrinted++
+	}
+	eventsString += "\n"
+	eventsString += codeBlock
+
+	if eventsLenLimitReached {
+		eventsString += tailString
+	}
+
+	if throttled {
+		eventsString += throttleMsg
+	}
+
+	return eventsString
+}
+
+// PlotFileName returns name of file plot of trigger is attached to message with.
+func PlotFileName(triggerID string, plot []byte) string {
+	return fmt.Sprintf("%s.%s", triggerID, moira.GetPlotFormat(plot))
+}
@@ -0,0 +1,95 @@
+package senders
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestChatMessageBuilder(t *testing.T) {
+	value := float64(123)
+	event := moira.NotificationEvent{
+		Values:    map[string]float64{"t1": value},
+		Timestamp: 150000000,
+		Metric:    "Metric",
+		OldState:  moira.StateOK,
+		State:     moira.StateERROR,
+	}
+	trigger := moira.TriggerData{
+		ID:   "TriggerID",
+		Name: "Name",
+		Tags: []string{"tag1", "tag2"},
+		Desc: "Description",
+	}
+	builder := ChatMessageBuilder{
+		Markup:   MarkdownChatMarkup,
+		FrontURI: "http://moira.url",
+		Location: time.UTC,
+	}
+
+	Convey("Build chat message", t, func() {
+		Convey("Message with link, tags and description", func() {
+			actual := builder.BuildMessage(moira.NotificationEvents{event}, trigger, false)
+			expected := "**ERROR** [Name](http://moira.url/trigger/TriggerID) [tag1][tag2]\n" +
+				"Description\n" +
+				"```\n" +
+				"02:40 (GMT+00:00): Metric = 123 (OK to ERROR)\n" +
+				"```"
+			So(actual, ShouldEqual, expected)
+		})
+
+		Convey("Throttled message with custom markup and emoji", func() {
+			customBuilder := builder
+			customBuilder.Markup = ChatMarkup{
+				Bold:      func(text string) string { return "*" + text + "*" },
+				Italic:    func(text string) string { return "_" + text + "_" },
+				Link:      func(text, url string) string { return "<" + url + "|" + text + ">" },
+				CodeBlock: "```",
+			}
+			customBuilder.StateEmoji = func(state moira.State) string { return ":" + string(state) + ":" }
+			actual := customBuilder.BuildMessage(moira.NotificationEvents{event}, moira.TriggerData{Name: "Name"}, true)
+			expected := ":ERROR: *ERROR* Name\n" +
+				"```\n" +
+				"02:40 (GMT+00:00): Metric = 123 (OK to ERROR)\n" +
+				"```\n" +
+				"Please, _fix your system or tune this trigger_ to generate less events."
+			So(actual, ShouldEqual, expected)
+		})
+
+		Convey("Description is converted to markup of messenger", func() {
+			customBuilder := builder
+			customBuilder.Markup.Description = strings.ToUpper
+			actual := customBuilder.BuildMessage(moira.NotificationEvents{event}, trigger, false)
+			So(actual, ShouldStartWith, "**ERROR** [Name](http://moira.url/trigger/TriggerID) [tag1][tag2]\nDESCRIPTION\n```")
+		})
+
+		Convey("Long message is cut to max chars", func() {
+			limitedBuilder := builder
+			limitedBuilder.MaxChars = 500
+			events := make(moira.NotificationEvents, 0, 20)
+			for i := 0; i < 20; i++ {
+				events = append(events, event)
+			}
+			actual := limitedBuilder.BuildMessage(events, moira.TriggerData{Desc: strings.Repeat("a", 1000)}, false)
+			So(len([]rune(actual)), ShouldBeLessThanOrEqualTo, 500)
+			So(actual, ShouldContainSubstring, "...\n")
+			So(actual, ShouldContainSubstring, "more events.")
+		})
+
+		Convey("Contact template is used instead of default message", func() {
+			contact := moira.ContactData{MessageTemplate: "{{ .Trigger.Name }} is {{ .State }}"}
+			actual := builder.BuildContactMessage(moira.NotificationEvents{event}, contact, trigger, false)
+			So(actual, ShouldEqual, "Name is ERROR")
+		})
+	})
+}
+
+func TestPlotFileName(t *testing.T) {
+	Convey("Plot file name has extension of plot format", t, func() {
+		So(PlotFileName("triggerID", []byte("\x89PNG\r\n\x1a\n")), ShouldEqual, "triggerID.png")
+		So(PlotFileName("triggerID", []byte("<svg></svg>")), ShouldEqual, "triggerID.svg")
+	})
+}
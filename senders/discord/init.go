@@ -7,6 +7,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/mitchellh/mapstructure"
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
 	"github.com/moira-alert/moira/worker"
 )
 
@@ -22,16 +23,17 @@ type config struct {
 	ContactType string `mapstructure:"contact_type"`
 	Token       string `mapstructure:"token"`
 	FrontURI    string `mapstructure:"front_uri"`
+	// MessageTemplate is a Go template of messages, it is used for contacts without their own template.
+	MessageTemplate string `mapstructure:"message_template"`
 }
 
 // Sender implements moira sender interface for discord.
 type Sender struct {
-	DataBase  moira.Database
-	logger    moira.Logger
-	location  *time.Location
-	session   *discordgo.Session
-	frontURI  string
-	botUserID string
+	DataBase       moira.Database
+	logger         moira.Logger
+	session        *discordgo.Session
+	messageBuilder senders.ChatMessageBuilder
+	botUserID      string
 }
 
 // Init reads the yaml config.
@@ -50,9 +52,7 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 		return fmt.Errorf("error creating discord session: %w", err)
 	}
 
-	sender.logger = logger
-	sender.frontURI = cfg.FrontURI
-	sender.location = location
+	sender.initMessageBuilder(cfg, logger, location)
 
 	handleMsg := func(s *discordgo.Session, m *discordgo.MessageCreate) {
 		channel, err := s.Channel(m.ChannelID)
@@ -77,6 +77,34 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	return nil
 }
 
+// InitMessageBuilder reads settings required to build messages without connecting to discord.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to discord config: %w", err)
+	}
+	sender.initMessageBuilder(cfg, logger, location)
+	return nil
+}
+
+func (sender *Sender) initMessageBuilder(cfg config, logger moira.Logger, location *time.Location) {
+	sender.logger = logger
+	sender.messageBuilder = senders.ChatMessageBuilder{
+		Markup:   discordMarkup,
+		FrontURI: cfg.FrontURI,
+		Location: location,
+		MaxChars: messageMaxCharacters,
+		Template: senders.MessageTemplate{
+			Template: cfg.MessageTemplate,
+			FrontURI: cfg.FrontURI,
+			Location: location,
+			MaxChars: messageMaxCharacters,
+			Logger:   logger,
+		},
+	}
+}
+
 func (sender *Sender) runBot(contactType string) {
 	err := sender.session.Open()
 	if err != nil {
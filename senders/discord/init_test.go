@@ -43,10 +43,10 @@ func TestInit(t *testing.T) {
 				"front_uri": "http://moira.uri",
 			}
 			sender.Init(senderSettings, logger, location, "15:04") //nolint
-			So(sender.frontURI, ShouldResemble, "http://moira.uri")
+			So(sender.messageBuilder.FrontURI, ShouldResemble, "http://moira.uri")
 			So(sender.session.Token, ShouldResemble, "Bot 123")
 			So(sender.logger, ShouldResemble, logger)
-			So(sender.location, ShouldResemble, location)
+			So(sender.messageBuilder.Location, ShouldResemble, location)
 		})
 	})
 }
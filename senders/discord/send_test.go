@@ -6,12 +6,18 @@ import (
 	"time"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
 func TestBuildMessage(t *testing.T) {
 	location, _ := time.LoadLocation("UTC")
-	sender := Sender{location: location, frontURI: "http://moira.url"}
+	sender := Sender{messageBuilder: senders.ChatMessageBuilder{
+		Markup:   discordMarkup,
+		FrontURI: "http://moira.url",
+		Location: location,
+		MaxChars: messageMaxCharacters,
+	}}
 
 	Convey("Build Moira Message tests", t, func() {
 		event := moira.NotificationEvent{
@@ -39,29 +45,21 @@ some text **bold text**
 some other text _italic text_`
 
 		Convey("Print moira message with one event", func() {
-			actual := sender.buildMessage([]moira.NotificationEvent{event}, trigger, false)
-			expected := "NODATA Trigger Name [tag1][tag2] (1)\n" + desc + `
-
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-
-http://moira.url/trigger/TriggerID
-`
+			actual := sender.messageBuilder.BuildMessage([]moira.NotificationEvent{event}, trigger, false)
+			expected := "**NODATA** [Trigger Name](http://moira.url/trigger/TriggerID) [tag1][tag2]\n" + desc + "\n" +
+				"```\n02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)\n```"
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message with empty triggerID, but with trigger Name", func() {
-			actual := sender.buildMessage([]moira.NotificationEvent{event}, moira.TriggerData{Name: "Name"}, false)
-			expected := `NODATA Name  (1)
-
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)`
+			actual := sender.messageBuilder.BuildMessage([]moira.NotificationEvent{event}, moira.TriggerData{Name: "Name"}, false)
+			expected := "**NODATA** Name\n```\n02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)\n```"
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message with empty trigger", func() {
-			actual := sender.buildMessage([]moira.NotificationEvent{event}, moira.TriggerData{}, false)
-			expected := `NODATA   (1)
-
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)`
+			actual := sender.messageBuilder.BuildMessage([]moira.NotificationEvent{event}, moira.TriggerData{}, false)
+			expected := "**NODATA**\n```\n02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)\n```"
 			So(actual, ShouldResemble, expected)
 		})
 
@@ -70,161 +68,80 @@ http://moira.url/trigger/TriggerID
 			event.MessageEventInfo = &moira.EventInfo{Interval: &interval}
 			event.TriggerID = ""
 			trigger.ID = ""
-			actual := sender.buildMessage([]moira.NotificationEvent{event}, trigger, false)
-			expected := "NODATA Trigger Name [tag1][tag2] (1)\n" + desc + `
-
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA). This metric has been in bad state for more than 24 hours - please, fix.`
+			actual := sender.messageBuilder.BuildMessage([]moira.NotificationEvent{event}, trigger, false)
+			expected := "**NODATA** Trigger Name [tag1][tag2]\n" + desc + "\n" +
+				"```\n02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA). This metric has been in bad state for more than 24 hours - please, fix.\n```"
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message with one event and throttled", func() {
-			actual := sender.buildMessage([]moira.NotificationEvent{event}, trigger, true)
-			expected := "NODATA Trigger Name [tag1][tag2] (1)\n" + desc + `
-
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-
-http://moira.url/trigger/TriggerID
-
-Please, fix your system or tune this trigger to generate less events.`
+			actual := sender.messageBuilder.BuildMessage([]moira.NotificationEvent{event}, trigger, true)
+			expected := "**NODATA** [Trigger Name](http://moira.url/trigger/TriggerID) [tag1][tag2]\n" + desc + "\n" +
+				"```\n02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)\n```\n" +
+				"Please, *fix your system or tune this trigger* to generate less events."
 			So(actual, ShouldResemble, expected)
 		})
 
-		eventLine := "\n02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)"
+		eventLine := "02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)\n"
 		oneEventLineLen := len([]rune(eventLine))
 		// Events list with chars less than half the message limit
 		var shortEvents moira.NotificationEvents
-		var shortEventsString string
 		for i := 0; i < (messageMaxCharacters/2-200)/oneEventLineLen; i++ {
 			shortEvents = append(shortEvents, event)
-			shortEventsString += eventLine
 		}
 		// Events list with chars greater than half the message limit
 		var longEvents moira.NotificationEvents
-		var longEventsString string
 		for i := 0; i < (messageMaxCharacters/2+200)/oneEventLineLen; i++ {
 			longEvents = append(longEvents, event)
-			longEventsString += eventLine
 		}
 		longDesc := strings.Repeat("a", messageMaxCharacters/2+79)
 
 		Convey("Print moira message with desc + events < msgLimit", func() {
-			actual := sender.buildMessage(shortEvents, moira.TriggerData{Desc: longDesc}, false)
-			expected := "NODATA   (12)\n" + longDesc + "\n" + shortEventsString
+			actual := sender.messageBuilder.BuildMessage(shortEvents, moira.TriggerData{Desc: longDesc}, false)
+			expected := "**NODATA**\n" + longDesc + "\n```\n" + strings.Repeat(eventLine, len(shortEvents)) + "```"
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message desc > msgLimit/2", func() {
 			var events moira.NotificationEvents
-			var eventsString string
 			for i := 0; i < (messageMaxCharacters/2-10)/oneEventLineLen; i++ {
 				events = append(events, event)
-				eventsString += eventLine
 			}
-			actual := sender.buildMessage(events, moira.TriggerData{Desc: longDesc}, false)
-			expected := `NODATA   (15)
-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa...
-
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)`
-
+			actual := sender.messageBuilder.BuildMessage(events, moira.TriggerData{Desc: longDesc}, false)
+			expected := "**NODATA**\n" + strings.Repeat("a", 997) + "...\n```\n" + strings.Repeat(eventLine, len(events)) + "```"
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message events string > msgLimit/2", func() {
 			desc := strings.Repeat("a", messageMaxCharacters/2-100)
-			actual := sender.buildMessage(longEvents, moira.TriggerData{Desc: desc}, false)
-			expected := `NODATA   (18)
-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa
-
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-
-...and 2 more events.`
-
+			actual := sender.messageBuilder.BuildMessage(longEvents, moira.TriggerData{Desc: desc}, false)
+			expected := "**NODATA**\n" + desc + "\n```\n" + strings.Repeat(eventLine, 16) + "```\n...and 2 more events."
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message with both desc and events > msgLimit/2", func() {
-			actual := sender.buildMessage(longEvents, moira.TriggerData{Desc: longDesc}, false)
-			expected := `NODATA   (18)
-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa...
-
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-02:40 (GMT+00:00): Metric name = 97.4458331200185 (OK to NODATA)
-
-...and 4 more events.`
-
+			actual := sender.messageBuilder.BuildMessage(longEvents, moira.TriggerData{Desc: longDesc}, false)
+			expected := "**NODATA**\n" + strings.Repeat("a", 984) + "...\n```\n" + strings.Repeat(eventLine, 14) + "```\n...and 4 more events."
 			So(actual, ShouldResemble, expected)
 		})
 	})
 }
 
-func TestBuildDescription(t *testing.T) {
-	location, _ := time.LoadLocation("UTC")
-	sender := Sender{location: location, frontURI: "http://moira.url"}
+func TestDiscordMarkupDescription(t *testing.T) {
 	Convey("Build desc tests", t, func() {
-		trigger := moira.TriggerData{
-			Desc: `# header1
+		desc := `# header1
 some text **bold text**
 ## header 2
-some other text _italic text_`,
-		}
+some other text _italic text_`
 
 		discordCompatibleMD := `**header1**
 some text **bold text**
 **header 2**
-some other text _italic text_
-`
-
-		Convey("Build empty desc", func() {
-			actual := sender.buildDescription(moira.TriggerData{Desc: ""})
-			expected := ""
-			So(actual, ShouldResemble, expected)
-		})
+some other text _italic text_`
 
 		Convey("Build desc with headers and bold", func() {
-			actual := sender.buildDescription(trigger)
-			expected := discordCompatibleMD
-			So(actual, ShouldResemble, expected)
+			actual := discordMarkup.Description(desc)
+			So(actual, ShouldResemble, discordCompatibleMD)
 		})
 	})
 }
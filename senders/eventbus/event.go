@@ -0,0 +1,81 @@
+package eventbus
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+// eventVersion is the version of event schema, it is increased on incompatible changes.
+const eventVersion = 1
+
+// Event is the notification published to message bus.
+type Event struct {
+	Version int `json:"version"`
+	// ID is the same for all delivery attempts of notification, consumers use it to skip duplicates.
+	ID        string                   `json:"id"`
+	Timestamp int64                    `json:"timestamp"`
+	State     moira.State              `json:"state"`
+	Throttled bool                     `json:"throttled"`
+	Trigger   EventTrigger             `json:"trigger"`
+	Contact   EventContact             `json:"contact"`
+	Events    moira.NotificationEvents `json:"events"`
+}
+
+// EventTrigger is the trigger notification is sent for.
+type EventTrigger struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Desc    string   `json:"desc,omitempty"`
+	Tags    []string `json:"tags"`
+	Targets []string `json:"targets,omitempty"`
+	URI     string   `json:"uri,omitempty"`
+}
+
+// EventContact is the contact notification is sent to.
+type EventContact struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	User  string `json:"user,omitempty"`
+	Team  string `json:"team,omitempty"`
+}
+
+func (sender *Sender) buildEvent(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) Event {
+	return Event{
+		Version:   eventVersion,
+		ID:        buildEventID(events, contact, trigger),
+		Timestamp: time.Now().Unix(),
+		State:     events.GetCurrentState(throttled),
+		Throttled: throttled,
+		Trigger: EventTrigger{
+			ID:      trigger.ID,
+			Name:    trigger.Name,
+			Desc:    trigger.Desc,
+			Tags:    trigger.Tags,
+			Targets: trigger.Targets,
+			URI:     trigger.GetTriggerURI(sender.frontURI),
+		},
+		Contact: EventContact{
+			ID:    contact.ID,
+			Type:  contact.Type,
+			Value: contact.Value,
+			User:  contact.User,
+			Team:  contact.Team,
+		},
+		Events: events,
+	}
+}
+
+// buildEventID hashes contact, trigger and events, so id doesn't change when notification is rescheduled.
+func buildEventID(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n", contact.ID, trigger.ID)
+	for _, event := range events {
+		fmt.Fprintf(hash, "%d %s %s %s\n", event.Timestamp, event.Metric, event.OldState, event.State)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
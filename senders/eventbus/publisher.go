@@ -0,0 +1,26 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+)
+
+// Backends of message bus.
+const (
+	BackendRedisStreams = "redis_streams"
+)
+
+// publisher publishes events to message bus. Events with the same key are delivered in order of publishing.
+// Publish returns after bus acknowledges event, so event is delivered at least once if it returns no error.
+type publisher interface {
+	Publish(ctx context.Context, topic, key string, payload []byte) error
+}
+
+func newPublisher(cfg config) (publisher, error) {
+	switch cfg.Backend {
+	case "", BackendRedisStreams:
+		return newRedisStreamsPublisher(cfg.Redis)
+	default:
+		return nil, fmt.Errorf("unsupported message bus backend '%s', use %s", cfg.Backend, BackendRedisStreams)
+	}
+}
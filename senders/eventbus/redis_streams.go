@@ -0,0 +1,64 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const defaultStreamMaxLen = 100_000
+
+// Structure that represents the Redis connection in the YAML file.
+type redisConfig struct {
+	// Addrs is comma separated list of Redis addresses.
+	Addrs      string `mapstructure:"addrs"`
+	MasterName string `mapstructure:"master_name"`
+	Username   string `mapstructure:"username"`
+	Password   string `mapstructure:"password"`
+	DB         int    `mapstructure:"db"`
+	// MaxLen is the approximate count of events stream is trimmed to.
+	MaxLen int64 `mapstructure:"max_len"`
+}
+
+// redisStreamsPublisher appends events to Redis streams, key is stored in field of stream entry.
+type redisStreamsPublisher struct {
+	client redis.UniversalClient
+	maxLen int64
+}
+
+func newRedisStreamsPublisher(cfg redisConfig) (*redisStreamsPublisher, error) {
+	if cfg.Addrs == "" {
+		return nil, fmt.Errorf("can not read redis addrs from config")
+	}
+
+	maxLen := cfg.MaxLen
+	if maxLen == 0 {
+		maxLen = defaultStreamMaxLen
+	}
+
+	return &redisStreamsPublisher{
+		client: redis.NewUniversalClient(&redis.UniversalOptions{
+			Addrs:      strings.Split(cfg.Addrs, ","),
+			MasterName: cfg.MasterName,
+			Username:   cfg.Username,
+			Password:   cfg.Password,
+			DB:         cfg.DB,
+		}),
+		maxLen: maxLen,
+	}, nil
+}
+
+// Publish appends event to stream, stream is ordered, so events of the same key keep their order.
+func (publisher *redisStreamsPublisher) Publish(ctx context.Context, topic, key string, payload []byte) error {
+	return publisher.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: topic,
+		MaxLen: publisher.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"key":   key,
+			"event": payload,
+		},
+	}).Err()
+}
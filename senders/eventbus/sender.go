@@ -0,0 +1,171 @@
+// Package eventbus is Moira sender which publishes notifications as versioned JSON events to message bus,
+// so other systems can store alerts or react to them asynchronously.
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/moira-alert/moira"
+)
+
+const (
+	formatJSON            = "json"
+	defaultTopic          = "moira-notifications"
+	defaultPublishTimeout = 5 * time.Second
+	// reservedTopicPrefix is the prefix of Redis keys used by Moira itself, topics must not overwrite them.
+	reservedTopicPrefix = "moira-"
+	maxTopicLength      = 249
+)
+
+var topicRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// Structure that represents the message bus configuration in the YAML file.
+type config struct {
+	// Backend is the type of message bus, only redis_streams is supported now.
+	Backend string `mapstructure:"backend"`
+	// Format is the encoding of events, only json is supported now.
+	Format string `mapstructure:"format"`
+	// Topic is used for contacts with empty value, otherwise value of contact is the topic.
+	Topic string `mapstructure:"topic"`
+	// TopicPrefix is prepended to topics of all contacts, it is required,
+	// so contacts can not publish to keys outside of namespace of message bus.
+	TopicPrefix    string      `mapstructure:"topic_prefix"`
+	PublishTimeout string      `mapstructure:"publish_timeout"`
+	FrontURI       string      `mapstructure:"front_uri"`
+	Redis          redisConfig `mapstructure:"redis"`
+}
+
+// Sender publishes notifications to message bus. Events are keyed by trigger id, so events of trigger keep their order.
+// Failed publishing is returned as error, so notifier reschedules notification and event is delivered at least once,
+// consumers should skip duplicates by event id.
+type Sender struct {
+	publisher      publisher
+	topic          string
+	topicPrefix    string
+	publishTimeout time.Duration
+	frontURI       string
+	logger         moira.Logger
+}
+
+// Init configures Sender.
+func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	cfg, err := sender.readConfig(senderSettings, logger)
+	if err != nil {
+		return err
+	}
+
+	sender.publishTimeout = defaultPublishTimeout
+	if cfg.PublishTimeout != "" {
+		if sender.publishTimeout, err = time.ParseDuration(cfg.PublishTimeout); err != nil {
+			return fmt.Errorf("failed to parse publish_timeout: %w", err)
+		}
+	}
+
+	if sender.publisher, err = newPublisher(cfg); err != nil {
+		return fmt.Errorf("failed to configure message bus: %w", err)
+	}
+	return nil
+}
+
+// InitMessageBuilder reads settings required to build events without connecting to message bus.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	_, err := sender.readConfig(senderSettings, logger)
+	return err
+}
+
+func (sender *Sender) readConfig(senderSettings interface{}, logger moira.Logger) (config, error) {
+	var cfg config
+	if err := mapstructure.Decode(senderSettings, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to decode senderSettings to eventbus config: %w", err)
+	}
+
+	if cfg.Format != "" && cfg.Format != formatJSON {
+		return cfg, fmt.Errorf("unsupported event format '%s', use %s", cfg.Format, formatJSON)
+	}
+
+	sender.topic = cfg.Topic
+	if sender.topic == "" {
+		sender.topic = defaultTopic
+	}
+	if cfg.TopicPrefix == "" {
+		return cfg, fmt.Errorf("topic_prefix can not be empty")
+	}
+	if err := validateTopic(cfg.TopicPrefix + sender.topic); err != nil {
+		return cfg, fmt.Errorf("invalid topic_prefix or topic: %w", err)
+	}
+	sender.topicPrefix = cfg.TopicPrefix
+	sender.frontURI = cfg.FrontURI
+	sender.logger = logger
+	return cfg, nil
+}
+
+// BuildMessage builds event without publishing it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	topic, err := sender.getTopic(contact)
+	if err != nil {
+		return moira.NotificationPayload{}, err
+	}
+	payload, err := json.Marshal(sender.buildEvent(events, contact, trigger, throttled))
+	if err != nil {
+		return moira.NotificationPayload{}, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return moira.NotificationPayload{
+		ContentType: "application/json",
+		Title:       topic,
+		Body:        string(payload),
+	}, nil
+}
+
+// SendEvents publishes notification to topic of contact, plots are not published.
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
+	topic, err := sender.getTopic(contact)
+	if err != nil {
+		return moira.NewSenderBrokenContactError(err)
+	}
+
+	payload, err := json.Marshal(sender.buildEvent(events, contact, trigger, throttled))
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sender.publishTimeout)
+	defer cancel()
+
+	if err = sender.publisher.Publish(ctx, topic, trigger.ID, payload); err != nil {
+		return fmt.Errorf("failed to publish %s event to topic %s: %w", trigger.ID, topic, err)
+	}
+	return nil
+}
+
+// getTopic routes contact to topic set in its value or to default topic of sender.
+func (sender *Sender) getTopic(contact moira.ContactData) (string, error) {
+	if contact.Value == "" {
+		return sender.topicPrefix + sender.topic, nil
+	}
+
+	topic := sender.topicPrefix + contact.Value
+	if err := validateTopic(topic); err != nil {
+		return "", fmt.Errorf("invalid topic of contact value '%s': %w", contact.Value, err)
+	}
+	return topic, nil
+}
+
+// validateTopic checks that topic is valid name of stream and is not key of Moira itself.
+func validateTopic(topic string) error {
+	if len(topic) > maxTopicLength {
+		return fmt.Errorf("topic is longer than %d characters", maxTopicLength)
+	}
+	if !topicRegexp.MatchString(topic) {
+		return fmt.Errorf("topic can contain only latin letters, digits, '.', '_' and '-'")
+	}
+	if strings.HasPrefix(topic, reservedTopicPrefix) {
+		return fmt.Errorf("topic can not start with %s", reservedTopicPrefix)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type publishedEvent struct {
+	topic   string
+	key     string
+	payload []byte
+}
+
+type fakePublisher struct {
+	published []publishedEvent
+	err       error
+}
+
+func (publisher *fakePublisher) Publish(_ context.Context, topic, key string, payload []byte) error {
+	if publisher.err != nil {
+		return publisher.err
+	}
+	publisher.published = append(publisher.published, publishedEvent{topic: topic, key: key, payload: payload})
+	return nil
+}
+
+func TestInit(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+
+	Convey("Init eventbus sender", t, func() {
+		sender := &Sender{}
+
+		Convey("With redis streams from yaml", func() {
+			settings := map[string]interface{}{
+				"topic_prefix": "alerts.",
+				"redis":        map[interface{}]interface{}{"addrs": "localhost:6379", "max_len": 10},
+			}
+			err := sender.Init(settings, logger, time.UTC, "")
+			So(err, ShouldBeNil)
+			So(sender.topic, ShouldEqual, defaultTopic)
+			So(sender.publishTimeout, ShouldEqual, defaultPublishTimeout)
+			So(sender.publisher.(*redisStreamsPublisher).maxLen, ShouldEqual, 10)
+		})
+
+		Convey("Without redis addrs", func() {
+			err := sender.Init(map[string]interface{}{"topic_prefix": "alerts."}, logger, time.UTC, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("With unsupported backend", func() {
+			err := sender.Init(map[string]interface{}{"topic_prefix": "alerts.", "backend": "kafka"}, logger, time.UTC, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("With unsupported format", func() {
+			err := sender.Init(map[string]interface{}{"topic_prefix": "alerts.", "format": "protobuf", "redis": map[string]interface{}{"addrs": "localhost:6379"}}, logger, time.UTC, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Without topic prefix", func() {
+			err := sender.Init(map[string]interface{}{"redis": map[string]interface{}{"addrs": "localhost:6379"}}, logger, time.UTC, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("With topic prefix of moira keys", func() {
+			err := sender.Init(map[string]interface{}{"topic_prefix": "moira-", "redis": map[string]interface{}{"addrs": "localhost:6379"}}, logger, time.UTC, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("With invalid topic prefix", func() {
+			err := sender.Init(map[string]interface{}{"topic_prefix": "alerts:", "redis": map[string]interface{}{"addrs": "localhost:6379"}}, logger, time.UTC, "")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	events := moira.NotificationEvents{{Timestamp: 100, Metric: "metric", OldState: moira.StateOK, State: moira.StateERROR}}
+	trigger := moira.TriggerData{ID: "triggerID", Name: "Name", Tags: []string{"tag"}}
+
+	Convey("Publish events", t, func() {
+		publisher := &fakePublisher{}
+		sender := &Sender{publisher: publisher, publishTimeout: time.Second}
+		_, err := sender.readConfig(map[string]interface{}{"topic_prefix": "alerts.", "front_uri": "http://moira.url"}, logger)
+		So(err, ShouldBeNil)
+
+		Convey("Event is published to topic of contact with trigger key", func() {
+			contact := moira.ContactData{ID: "contactID", Type: "eventbus", Value: "team-a", SigningSecret: "secret"}
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(publisher.published, ShouldHaveLength, 1)
+			So(publisher.published[0].topic, ShouldEqual, "alerts.team-a")
+			So(publisher.published[0].key, ShouldEqual, "triggerID")
+
+			var event Event
+			So(json.Unmarshal(publisher.published[0].payload, &event), ShouldBeNil)
+			So(event.Version, ShouldEqual, eventVersion)
+			So(event.ID, ShouldEqual, buildEventID(events, contact, trigger))
+			So(event.State, ShouldEqual, moira.StateERROR)
+			So(event.Trigger, ShouldResemble, EventTrigger{ID: "triggerID", Name: "Name", Tags: []string{"tag"}, URI: "http://moira.url/trigger/triggerID"})
+			So(event.Contact, ShouldResemble, EventContact{ID: "contactID", Type: "eventbus", Value: "team-a"})
+			So(string(publisher.published[0].payload), ShouldNotContainSubstring, "secret")
+		})
+
+		Convey("Contact without value is routed to default topic", func() {
+			err = sender.SendEvents(events, moira.ContactData{}, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(publisher.published[0].topic, ShouldEqual, "alerts."+defaultTopic)
+		})
+
+		Convey("Contact with invalid topic is broken", func() {
+			for _, value := range []string{"team a", "team:a", "../moira", strings.Repeat("a", maxTopicLength)} {
+				err = sender.SendEvents(events, moira.ContactData{Value: value}, trigger, nil, false)
+				So(err, ShouldHaveSameTypeAs, moira.SenderBrokenContactError{})
+			}
+			So(publisher.published, ShouldBeEmpty)
+		})
+
+		Convey("Topic of contact can not be moira key", func() {
+			_, err = sender.readConfig(map[string]interface{}{"topic_prefix": "moira"}, logger)
+			So(err, ShouldBeNil)
+			err = sender.SendEvents(events, moira.ContactData{Value: "-trigger-lock:triggerID"}, trigger, nil, false)
+			So(err, ShouldHaveSameTypeAs, moira.SenderBrokenContactError{})
+			err = sender.SendEvents(events, moira.ContactData{Value: "-notifier-state"}, trigger, nil, false)
+			So(err, ShouldHaveSameTypeAs, moira.SenderBrokenContactError{})
+			So(publisher.published, ShouldBeEmpty)
+		})
+
+		Convey("Failed publishing is returned for reschedule", func() {
+			publisher.err = errors.New("connection refused")
+			err = sender.SendEvents(events, moira.ContactData{}, trigger, nil, false)
+			So(err, ShouldNotBeNil)
+			So(err, ShouldNotHaveSameTypeAs, moira.SenderBrokenContactError{})
+		})
+	})
+}
+
+func TestBuildEventID(t *testing.T) {
+	Convey("Event id depends on contact, trigger and events only", t, func() {
+		events := moira.NotificationEvents{{Timestamp: 100, Metric: "metric", State: moira.StateERROR}}
+		contact := moira.ContactData{ID: "contactID"}
+		trigger := moira.TriggerData{ID: "triggerID"}
+
+		id := buildEventID(events, contact, trigger)
+		So(buildEventID(events, contact, trigger), ShouldEqual, id)
+		So(buildEventID(events, moira.ContactData{ID: "other"}, trigger), ShouldNotEqual, id)
+		So(buildEventID(moira.NotificationEvents{{Timestamp: 200, Metric: "metric", State: moira.StateERROR}}, contact, trigger), ShouldNotEqual, id)
+	})
+}
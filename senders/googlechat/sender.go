@@ -0,0 +1,179 @@
+// Package googlechat is Moira sender for Google Chat spaces, it posts messages via incoming webhooks.
+package googlechat
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const (
+	messageMaxCharacters = 4_000
+	requestTimeout       = 30 * time.Second
+	webhookURLPrefix     = "https://chat.googleapis.com/"
+	plotsCardID          = "plots"
+)
+
+var markup = senders.ChatMarkup{
+	Bold:      func(text string) string { return "*" + text + "*" },
+	Italic:    func(text string) string { return "_" + text + "_" },
+	Link:      func(text, url string) string { return fmt.Sprintf("<%s|%s>", url, text) },
+	CodeBlock: "```",
+}
+
+// Structure that represents the Google Chat configuration in the YAML file.
+type config struct {
+	FrontURI string `mapstructure:"front_uri"`
+	// MessageTemplate is a Go template of messages, it is used for contacts without their own template.
+	MessageTemplate string `mapstructure:"message_template"`
+}
+
+// Sender posts messages to Google Chat spaces, contact value is incoming webhook URL of space.
+// Incoming webhooks can't upload files, so plots are attached as card images if image store is configured.
+type Sender struct {
+	ImageStores          map[string]moira.ImageStore
+	imageStore           moira.ImageStore
+	imageStoreConfigured bool
+	webhookURLPrefix     string
+	messageBuilder       senders.ChatMessageBuilder
+	client               *senders.ChatClient
+	logger               moira.Logger
+}
+
+type message struct {
+	Text    string `json:"text"`
+	CardsV2 []card `json:"cardsV2,omitempty"`
+}
+
+type card struct {
+	CardID string      `json:"cardId"`
+	Card   cardContent `json:"card"`
+}
+
+type cardContent struct {
+	Sections []cardSection `json:"sections"`
+}
+
+type cardSection struct {
+	Widgets []cardWidget `json:"widgets"`
+}
+
+type cardWidget struct {
+	Image cardImage `json:"image"`
+}
+
+type cardImage struct {
+	ImageURL string `json:"imageUrl"`
+	AltText  string `json:"altText"`
+}
+
+// Init configures Sender.
+func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	if err := sender.InitMessageBuilder(senderSettings, logger, location, dateTimeFormat); err != nil {
+		return err
+	}
+
+	_, sender.imageStore, sender.imageStoreConfigured = senders.ReadImageStoreConfig(senderSettings, sender.ImageStores, logger)
+	sender.webhookURLPrefix = webhookURLPrefix
+	sender.client = senders.NewChatClient(requestTimeout)
+	return nil
+}
+
+// InitMessageBuilder reads settings required to build messages without connecting to Google Chat.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	if err := mapstructure.Decode(senderSettings, &cfg); err != nil {
+		return fmt.Errorf("failed to decode senderSettings to googlechat config: %w", err)
+	}
+
+	sender.logger = logger
+	sender.messageBuilder = senders.ChatMessageBuilder{
+		Markup:   markup,
+		FrontURI: cfg.FrontURI,
+		Location: location,
+		MaxChars: messageMaxCharacters,
+		Template: senders.MessageTemplate{
+			Template: cfg.MessageTemplate,
+			FrontURI: cfg.FrontURI,
+			Location: location,
+			MaxChars: messageMaxCharacters,
+			Logger:   logger,
+		},
+	}
+	return nil
+}
+
+// BuildMessage builds Google Chat message without sending it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	return moira.NotificationPayload{
+		ContentType: "text/plain",
+		Body:        sender.messageBuilder.BuildContactMessage(events, contact, trigger, throttled),
+	}, nil
+}
+
+// SendEvents implements Sender interface Send.
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
+	if err := sender.validateWebhookURL(contact.Value); err != nil {
+		return moira.NewSenderBrokenContactError(err)
+	}
+
+	msg := message{
+		Text:    sender.messageBuilder.BuildContactMessage(events, contact, trigger, throttled),
+		CardsV2: sender.buildPlotsCards(plots, trigger.ID),
+	}
+
+	request, err := senders.NewJSONRequest(http.MethodPost, contact.Value, msg)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	if err = sender.client.Do(request, nil); err != nil {
+		return senders.WrapChatError(err, fmt.Sprintf("failed to send %s event message to Google Chat", trigger.ID))
+	}
+	return nil
+}
+
+// buildPlotsCards stores plots in image store and attaches them to message as card images.
+func (sender *Sender) buildPlotsCards(plots [][]byte, triggerID string) []card {
+	if len(plots) == 0 || !sender.imageStoreConfigured {
+		return nil
+	}
+
+	widgets := make([]cardWidget, 0, len(plots))
+	for _, plot := range plots {
+		imageURL, err := sender.imageStore.StoreImage(plot)
+		if err != nil {
+			sender.logger.Warning().
+				String(moira.LogFieldNameTriggerID, triggerID).
+				Error(err).
+				Msg("Failed to store plot for Google Chat")
+			continue
+		}
+		widgets = append(widgets, cardWidget{Image: cardImage{ImageURL: imageURL, AltText: "Plot"}})
+	}
+	if len(widgets) == 0 {
+		return nil
+	}
+
+	return []card{
+		{
+			CardID: plotsCardID,
+			Card:   cardContent{Sections: []cardSection{{Widgets: widgets}}},
+		},
+	}
+}
+
+func (sender *Sender) validateWebhookURL(webhookURL string) error {
+	if _, err := url.Parse(webhookURL); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(webhookURL, sender.webhookURLPrefix) {
+		return fmt.Errorf("%s is an invalid google chat webhook url", webhookURL)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package googlechat
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	events := moira.NotificationEvents{{Metric: "metric", OldState: moira.StateOK, State: moira.StateERROR}}
+	trigger := moira.TriggerData{ID: "triggerID", Name: "Name"}
+
+	Convey("Send events to Google Chat", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		imageStore := mock_moira_alert.NewMockImageStore(mockCtrl)
+		imageStore.EXPECT().IsEnabled().Return(true)
+
+		var sentMessage message
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/spaces/missing/messages" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			json.Unmarshal(body, &sentMessage) //nolint
+			w.Write([]byte(`{}`))              //nolint
+		}))
+		defer server.Close()
+
+		sender := &Sender{ImageStores: map[string]moira.ImageStore{"s3": imageStore}}
+		settings := map[string]interface{}{"front_uri": "http://moira.url", "image_store": "s3"}
+		err := sender.Init(settings, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+		sender.webhookURLPrefix = server.URL
+
+		Convey("Message with plot card is sent", func() {
+			imageStore.EXPECT().StoreImage([]byte("plot")).Return("http://images/plot.png", nil)
+			err = sender.SendEvents(events, moira.ContactData{Value: server.URL + "/v1/spaces/space/messages"}, trigger, [][]byte{[]byte("plot")}, false)
+			So(err, ShouldBeNil)
+			So(sentMessage.Text, ShouldStartWith, "*ERROR* <http://moira.url/trigger/triggerID|Name>\n```")
+			So(sentMessage.CardsV2, ShouldHaveLength, 1)
+			So(sentMessage.CardsV2[0].Card.Sections[0].Widgets[0].Image.ImageURL, ShouldEqual, "http://images/plot.png")
+		})
+
+		Convey("Deleted webhook is broken contact", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: server.URL + "/v1/spaces/missing/messages"}, trigger, nil, false)
+			So(err, ShouldHaveSameTypeAs, moira.SenderBrokenContactError{})
+		})
+
+		Convey("Webhook of other service is broken contact", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "https://example.com/webhook"}, trigger, nil, false)
+			So(err, ShouldHaveSameTypeAs, moira.SenderBrokenContactError{})
+		})
+	})
+}
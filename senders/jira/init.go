@@ -0,0 +1,226 @@
+// Package jira is Moira sender which opens Jira issues for problems of triggers, comments them while problem lasts
+// and resolves them when trigger recovers.
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const (
+	messenger                = "jira"
+	requestTimeout           = 30 * time.Second
+	defaultAPIVersion        = 2
+	defaultIssueType         = "Task"
+	defaultResolveTransition = "Done"
+	defaultIssueTTL          = "720h"
+	descriptionMaxCharacters = 30_000
+)
+
+// Structure that represents the Jira configuration in the YAML file.
+type config struct {
+	URL string `mapstructure:"url"`
+	// APIVersion is version of Jira REST API, 2 or 3. Version 3 is available in Jira Cloud only.
+	APIVersion int `mapstructure:"api_version"`
+	// User is set for basic authentication with API token in Jira Cloud,
+	// token is sent as bearer personal access token if user is empty.
+	User     string `mapstructure:"user"`
+	Token    string `mapstructure:"token"`
+	FrontURI string `mapstructure:"front_uri"`
+	// ResolveTransition is the name of workflow transition issue is resolved with when trigger recovers.
+	ResolveTransition string `mapstructure:"resolve_transition"`
+	// IssueTTL is the period key of issue is kept since the last notification.
+	IssueTTL string `mapstructure:"issue_ttl"`
+	// MessageTemplate is a Go template of issue description and comments, it is used for contacts without their own template.
+	MessageTemplate string `mapstructure:"message_template"`
+	// Default settings of issues, they can be overridden by contact.
+	Project        string                 `mapstructure:"project"`
+	IssueType      string                 `mapstructure:"issue_type"`
+	Labels         []string               `mapstructure:"labels"`
+	LabelsFromTags bool                   `mapstructure:"labels_from_tags"`
+	Fields         map[string]interface{} `mapstructure:"fields"`
+}
+
+// issueSettings describe issues created for contact. Contact value is either key of project
+// or JSON object with settings, e.g. {"project": "OPS", "issue_type": "Incident", "fields": {"priority": {"name": "High"}}}.
+// String values of fields are Go templates populated with notification data.
+type issueSettings struct {
+	Project        string                 `json:"project"`
+	IssueType      string                 `json:"issue_type"`
+	Labels         []string               `json:"labels"`
+	LabelsFromTags *bool                  `json:"labels_from_tags"`
+	Fields         map[string]interface{} `json:"fields"`
+}
+
+// Sender creates, comments and resolves Jira issues.
+type Sender struct {
+	DataBase          moira.Database
+	url               string
+	apiVersion        int
+	user              string
+	token             string
+	resolveTransition string
+	defaults          issueSettings
+	issues            *senders.MessageThreads
+	messageBuilder    senders.ChatMessageBuilder
+	frontURI          string
+	location          *time.Location
+	client            *senders.ChatClient
+	logger            moira.Logger
+}
+
+// Init configures Sender.
+func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	cfg, err := sender.readConfig(senderSettings, logger, location)
+	if err != nil {
+		return err
+	}
+
+	if cfg.URL == "" {
+		return fmt.Errorf("can not read Jira url from config")
+	}
+	if cfg.Token == "" {
+		return fmt.Errorf("can not read Jira token from config")
+	}
+
+	issueTTL := cfg.IssueTTL
+	if issueTTL == "" {
+		issueTTL = defaultIssueTTL
+	}
+	if sender.issues, err = senders.NewMessageThreads(sender.DataBase, messenger, senders.ThreadModeTrigger, issueTTL); err != nil {
+		return fmt.Errorf("failed to configure Jira issues storage: %w", err)
+	}
+
+	sender.url = fmt.Sprintf("%s/rest/api/%d", strings.TrimSuffix(cfg.URL, "/"), cfg.APIVersion)
+	sender.user = cfg.User
+	sender.token = cfg.Token
+	sender.resolveTransition = cfg.ResolveTransition
+	if sender.resolveTransition == "" {
+		sender.resolveTransition = defaultResolveTransition
+	}
+	sender.client = senders.NewChatClient(requestTimeout)
+	return nil
+}
+
+// InitMessageBuilder reads settings required to build issues without connecting to Jira.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	_, err := sender.readConfig(senderSettings, logger, location)
+	return err
+}
+
+func (sender *Sender) readConfig(senderSettings interface{}, logger moira.Logger, location *time.Location) (config, error) {
+	var cfg config
+	if err := mapstructure.Decode(senderSettings, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to decode senderSettings to jira config: %w", err)
+	}
+
+	switch cfg.APIVersion {
+	case 0:
+		cfg.APIVersion = defaultAPIVersion
+	case 2, 3: //nolint:gomnd
+	default:
+		return cfg, fmt.Errorf("unsupported Jira api_version %d, use 2 or 3", cfg.APIVersion)
+	}
+
+	markup := wikiMarkup
+	if cfg.APIVersion != defaultAPIVersion {
+		markup = plainMarkup
+	}
+
+	sender.logger = logger
+	sender.location = location
+	sender.frontURI = cfg.FrontURI
+	sender.apiVersion = cfg.APIVersion
+	sender.defaults = issueSettings{
+		Project:        cfg.Project,
+		IssueType:      cfg.IssueType,
+		Labels:         cfg.Labels,
+		LabelsFromTags: &cfg.LabelsFromTags,
+	}
+	if cfg.Fields != nil {
+		sender.defaults.Fields = fromYAMLValue(cfg.Fields).(map[string]interface{})
+	}
+	if sender.defaults.IssueType == "" {
+		sender.defaults.IssueType = defaultIssueType
+	}
+	sender.messageBuilder = senders.ChatMessageBuilder{
+		Markup:   markup,
+		FrontURI: cfg.FrontURI,
+		Location: location,
+		MaxChars: descriptionMaxCharacters,
+		Template: senders.MessageTemplate{
+			Template: cfg.MessageTemplate,
+			FrontURI: cfg.FrontURI,
+			Location: location,
+			MaxChars: descriptionMaxCharacters,
+			Logger:   logger,
+		},
+	}
+	return cfg, nil
+}
+
+// getIssueSettings merges issue settings of contact with default settings of sender.
+func (sender *Sender) getIssueSettings(contact moira.ContactData) (issueSettings, error) {
+	settings := issueSettings{Project: strings.TrimSpace(contact.Value)}
+	if strings.HasPrefix(settings.Project, "{") {
+		settings = issueSettings{}
+		if err := json.Unmarshal([]byte(contact.Value), &settings); err != nil {
+			return settings, fmt.Errorf("failed to parse Jira contact settings: %w", err)
+		}
+	}
+
+	if settings.Project == "" {
+		settings.Project = sender.defaults.Project
+	}
+	if settings.Project == "" {
+		return settings, fmt.Errorf("project is set neither in contact nor in sender settings")
+	}
+	if settings.IssueType == "" {
+		settings.IssueType = sender.defaults.IssueType
+	}
+	if settings.LabelsFromTags == nil {
+		settings.LabelsFromTags = sender.defaults.LabelsFromTags
+	}
+	settings.Labels = append(append([]string{}, sender.defaults.Labels...), settings.Labels...)
+
+	fields := make(map[string]interface{}, len(sender.defaults.Fields)+len(settings.Fields))
+	for name, value := range sender.defaults.Fields {
+		fields[name] = value
+	}
+	for name, value := range settings.Fields {
+		fields[name] = value
+	}
+	settings.Fields = fields
+	return settings, nil
+}
+
+// fromYAMLValue converts maps with interface keys produced by yaml to maps that can be marshaled to JSON.
+func fromYAMLValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			result[fmt.Sprint(key)] = fromYAMLValue(item)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			result[key] = fromYAMLValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, item := range typed {
+			result[i] = fromYAMLValue(item)
+		}
+		return result
+	}
+	return value
+}
@@ -0,0 +1,93 @@
+package jira
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInit(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+
+	Convey("Init jira sender", t, func() {
+		sender := &Sender{}
+
+		Convey("Without url", func() {
+			err := sender.Init(map[string]interface{}{"token": "token"}, logger, time.UTC, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Without token", func() {
+			err := sender.Init(map[string]interface{}{"url": "https://jira.example.com"}, logger, time.UTC, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("With unsupported api version", func() {
+			err := sender.Init(map[string]interface{}{"url": "https://jira.example.com", "token": "token", "api_version": 1}, logger, time.UTC, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("With default settings", func() {
+			err := sender.Init(map[string]interface{}{"url": "https://jira.example.com/", "token": "token"}, logger, time.UTC, "")
+			So(err, ShouldBeNil)
+			So(sender.url, ShouldEqual, "https://jira.example.com/rest/api/2")
+			So(sender.resolveTransition, ShouldEqual, defaultResolveTransition)
+			So(sender.defaults.IssueType, ShouldEqual, defaultIssueType)
+		})
+	})
+}
+
+func TestGetIssueSettings(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	sender := &Sender{}
+	settings := map[string]interface{}{
+		"url":              "https://jira.example.com",
+		"token":            "token",
+		"issue_type":       "Incident",
+		"labels":           []string{"moira"},
+		"labels_from_tags": true,
+		"fields": map[interface{}]interface{}{
+			"priority": map[interface{}]interface{}{"name": "High"},
+		},
+	}
+
+	Convey("Get issue settings of contact", t, func() {
+		err := sender.Init(settings, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+
+		Convey("Contact with project key uses sender settings", func() {
+			actual, err := sender.getIssueSettings(moira.ContactData{Value: "OPS"})
+			So(err, ShouldBeNil)
+			So(actual.Project, ShouldEqual, "OPS")
+			So(actual.IssueType, ShouldEqual, "Incident")
+			So(actual.Labels, ShouldResemble, []string{"moira"})
+			So(*actual.LabelsFromTags, ShouldBeTrue)
+			So(actual.Fields, ShouldResemble, map[string]interface{}{"priority": map[string]interface{}{"name": "High"}})
+		})
+
+		Convey("Contact with settings overrides sender settings", func() {
+			value := `{"project": "DEV", "issue_type": "Bug", "labels": ["prod"], "labels_from_tags": false, "fields": {"priority": {"name": "Low"}, "components": [{"name": "api"}]}}`
+			actual, err := sender.getIssueSettings(moira.ContactData{Value: value})
+			So(err, ShouldBeNil)
+			So(actual.Project, ShouldEqual, "DEV")
+			So(actual.IssueType, ShouldEqual, "Bug")
+			So(actual.Labels, ShouldResemble, []string{"moira", "prod"})
+			So(*actual.LabelsFromTags, ShouldBeFalse)
+			So(actual.Fields["priority"], ShouldResemble, map[string]interface{}{"name": "Low"})
+			So(actual.Fields["components"], ShouldResemble, []interface{}{map[string]interface{}{"name": "api"}})
+		})
+
+		Convey("Contact with invalid settings", func() {
+			_, err := sender.getIssueSettings(moira.ContactData{Value: `{"project": `})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Contact without project", func() {
+			_, err := sender.getIssueSettings(moira.ContactData{Value: `{"issue_type": "Bug"}`})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,348 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const summaryMaxCharacters = 255
+
+// wikiMarkup is the text formatting of Jira REST API v2.
+var wikiMarkup = senders.ChatMarkup{
+	Bold:      func(text string) string { return "*" + text + "*" },
+	Italic:    func(text string) string { return "_" + text + "_" },
+	Link:      func(text, url string) string { return fmt.Sprintf("[%s|%s]", text, url) },
+	CodeBlock: "{noformat}",
+}
+
+// plainMarkup is used with Jira REST API v3, text is converted to paragraphs of Atlassian document format there.
+var plainMarkup = senders.ChatMarkup{
+	Bold:   func(text string) string { return text },
+	Italic: func(text string) string { return text },
+	Link:   func(text, url string) string { return strings.TrimSpace(text + " " + url) },
+}
+
+type issueResponse struct {
+	Key string `json:"key"`
+}
+
+type transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   struct {
+		Name string `json:"name"`
+	} `json:"to"`
+}
+
+type transitionsResponse struct {
+	Transitions []transition `json:"transitions"`
+}
+
+// documentNode is a node of Atlassian document format used by Jira REST API v3.
+type documentNode struct {
+	Type    string         `json:"type"`
+	Version int            `json:"version,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Content []documentNode `json:"content,omitempty"`
+}
+
+// BuildMessage builds issue description without sending it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	contentType := "text/plain"
+	if sender.apiVersion == defaultAPIVersion {
+		contentType = "text/x-jira-wiki"
+	}
+	return moira.NotificationPayload{
+		ContentType: contentType,
+		Title:       buildSummary(events, trigger, throttled),
+		Body:        sender.messageBuilder.BuildContactMessage(events, contact, trigger, throttled),
+	}, nil
+}
+
+// SendEvents opens issue when trigger goes to ERROR or NODATA, comments open issue on subsequent events
+// and resolves it when neither trigger nor any of its metrics is in problem state.
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
+	settings, err := sender.getIssueSettings(contact)
+	if err != nil {
+		return moira.NewSenderBrokenContactError(err)
+	}
+
+	issueKey := sender.issues.GetKey(events, contact, trigger)
+	// Notifications of trigger are sent by parallel senders, issue is locked so only one of them opens it
+	unlock, err := sender.issues.Lock(issueKey)
+	if err != nil {
+		return fmt.Errorf("failed to lock Jira issue of trigger %s: %w", trigger.ID, err)
+	}
+	defer unlock()
+
+	issue, err := sender.issues.Get(issueKey)
+	if err != nil {
+		return fmt.Errorf("failed to get Jira issue of trigger %s: %w", trigger.ID, err)
+	}
+
+	message := sender.messageBuilder.BuildContactMessage(events, contact, trigger, throttled)
+	if issue != nil {
+		err = sender.addComment(issue.MessageID, message)
+		if getResponseStatus(err) == http.StatusNotFound {
+			sender.logger.Info().
+				String(moira.LogFieldNameTriggerID, trigger.ID).
+				String("issue", issue.MessageID).
+				Msg("Jira issue was deleted")
+			if err = sender.issues.Close(issueKey); err != nil {
+				return err
+			}
+			issue = nil
+		} else if err != nil {
+			return senders.WrapChatError(err, fmt.Sprintf("failed to comment Jira issue %s", issue.MessageID))
+		}
+	}
+
+	if issue == nil {
+		state := events.GetCurrentState(throttled)
+		if state != moira.StateERROR && state != moira.StateNODATA {
+			return nil
+		}
+		createdIssueKey, createErr := sender.createIssue(settings, events, contact, trigger, throttled, message)
+		if createErr != nil {
+			return senders.WrapChatError(createErr, fmt.Sprintf("failed to create Jira issue for trigger %s", trigger.ID))
+		}
+		// Issue is already created, so error is not returned to avoid opening one more issue on retry
+		sender.saveIssue(issueKey, senders.MessageThread{MessageID: createdIssueKey}, trigger.ID)
+		sender.attachPlots(createdIssueKey, trigger.ID, plots)
+		return nil
+	}
+
+	sender.attachPlots(issue.MessageID, trigger.ID, plots)
+
+	incidentState, err := sender.issues.GetIncidentState(events, trigger, throttled)
+	if err != nil {
+		sender.logger.Warning().
+			String(moira.LogFieldNameTriggerID, trigger.ID).
+			String("issue", issue.MessageID).
+			Error(err).
+			Msg("Failed to get state of trigger, Jira issue is left open")
+	}
+	if err == nil && incidentState == moira.StateOK {
+		if err = sender.resolveIssue(issue.MessageID); err != nil {
+			return senders.WrapChatError(err, fmt.Sprintf("failed to resolve Jira issue %s", issue.MessageID))
+		}
+		return sender.issues.Close(issueKey)
+	}
+	sender.saveIssue(issueKey, *issue, trigger.ID)
+	return nil
+}
+
+// saveIssue remembers open issue of trigger, error is only logged because the notification is already posted to issue.
+func (sender *Sender) saveIssue(issueKey string, issue senders.MessageThread, triggerID string) {
+	if err := sender.issues.Save(issueKey, issue); err != nil {
+		sender.logger.Error().
+			String(moira.LogFieldNameTriggerID, triggerID).
+			String("issue", issue.MessageID).
+			Error(err).
+			Msg("Failed to save Jira issue, next notification of trigger opens new issue")
+	}
+}
+
+func (sender *Sender) attachPlots(issueKey, triggerID string, plots [][]byte) {
+	for _, plot := range plots {
+		if err := sender.attachPlot(issueKey, triggerID, plot); err != nil {
+			sender.logger.Warning().
+				String(moira.LogFieldNameTriggerID, triggerID).
+				String("issue", issueKey).
+				Error(err).
+				Msg("Failed to attach plot to Jira issue")
+		}
+	}
+}
+
+func (sender *Sender) createIssue(settings issueSettings, events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool, message string) (string, error) {
+	fields := make(map[string]interface{}, len(settings.Fields)+5) //nolint:gomnd
+	for name, value := range settings.Fields {
+		fields[name] = sender.populateField(value, events, contact, trigger, throttled)
+	}
+	fields["project"] = map[string]string{"key": settings.Project}
+	fields["issuetype"] = map[string]string{"name": settings.IssueType}
+	fields["summary"] = buildSummary(events, trigger, throttled)
+	fields["description"] = sender.formatText(message)
+	if labels := buildLabels(settings, trigger); len(labels) > 0 {
+		fields["labels"] = labels
+	}
+
+	request, err := senders.NewJSONRequest(http.MethodPost, sender.url+"/issue", map[string]interface{}{"fields": fields})
+	if err != nil {
+		return "", err
+	}
+	sender.authorize(request)
+
+	var response issueResponse
+	if err = sender.client.Do(request, &response); err != nil {
+		return "", err
+	}
+	return response.Key, nil
+}
+
+func (sender *Sender) addComment(issueKey, message string) error {
+	request, err := senders.NewJSONRequest(http.MethodPost, sender.issueURL(issueKey)+"/comment", map[string]interface{}{"body": sender.formatText(message)})
+	if err != nil {
+		return err
+	}
+	sender.authorize(request)
+	return sender.client.Do(request, nil)
+}
+
+// resolveIssue performs workflow transition configured in resolve_transition, transition is matched by its name or name of target status.
+func (sender *Sender) resolveIssue(issueKey string) error {
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, sender.issueURL(issueKey)+"/transitions", nil)
+	if err != nil {
+		return err
+	}
+	sender.authorize(request)
+
+	var response transitionsResponse
+	if err = sender.client.Do(request, &response); err != nil {
+		return err
+	}
+
+	transitionID := ""
+	for _, available := range response.Transitions {
+		if strings.EqualFold(available.Name, sender.resolveTransition) || strings.EqualFold(available.To.Name, sender.resolveTransition) {
+			transitionID = available.ID
+			break
+		}
+	}
+	if transitionID == "" {
+		return fmt.Errorf("transition '%s' is not available for issue", sender.resolveTransition)
+	}
+
+	request, err = senders.NewJSONRequest(http.MethodPost, sender.issueURL(issueKey)+"/transitions", map[string]interface{}{
+		"transition": map[string]string{"id": transitionID},
+	})
+	if err != nil {
+		return err
+	}
+	sender.authorize(request)
+	return sender.client.Do(request, nil)
+}
+
+func (sender *Sender) attachPlot(issueKey, triggerID string, plot []byte) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", senders.PlotFileName(triggerID, plot))
+	if err != nil {
+		return err
+	}
+	if _, err = part.Write(plot); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, sender.issueURL(issueKey)+"/attachments", &body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	request.Header.Set("X-Atlassian-Token", "no-check")
+	sender.authorize(request)
+	return sender.client.Do(request, nil)
+}
+
+// populateField populates string values of fields as Go templates, other values are sent as is.
+func (sender *Sender) populateField(value interface{}, events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) interface{} {
+	tmpl, ok := value.(string)
+	if !ok || !strings.Contains(tmpl, "{{") {
+		return value
+	}
+
+	fieldTemplate := senders.MessageTemplate{
+		Template: tmpl,
+		FrontURI: sender.frontURI,
+		Location: sender.location,
+		Logger:   sender.logger,
+	}
+	contact.MessageTemplate = ""
+	if populated, ok := fieldTemplate.Render(events, contact, trigger, throttled); ok {
+		return populated
+	}
+	return value
+}
+
+// formatText returns wiki markup text for REST API v2 and Atlassian document for v3.
+func (sender *Sender) formatText(text string) interface{} {
+	if sender.apiVersion == defaultAPIVersion {
+		return text
+	}
+
+	paragraphs := make([]documentNode, 0)
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		paragraphs = append(paragraphs, documentNode{
+			Type:    "paragraph",
+			Content: []documentNode{{Type: "text", Text: line}},
+		})
+	}
+	return documentNode{Type: "doc", Version: 1, Content: paragraphs}
+}
+
+func (sender *Sender) issueURL(issueKey string) string {
+	return sender.url + "/issue/" + issueKey
+}
+
+func (sender *Sender) authorize(request *http.Request) {
+	if sender.user != "" {
+		request.SetBasicAuth(sender.user, sender.token)
+		return
+	}
+	request.Header.Set("Authorization", "Bearer "+sender.token)
+}
+
+func buildSummary(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
+	summary := string(events.GetCurrentState(throttled))
+	if trigger.Name != "" {
+		summary += " " + trigger.Name
+	}
+	if tags := trigger.GetTags(); tags != "" {
+		summary += " " + tags
+	}
+	return senders.TruncateMessage(summary, summaryMaxCharacters)
+}
+
+// buildLabels joins labels of settings and tags of trigger, spaces are not allowed in Jira labels, so they are replaced with underscores.
+func buildLabels(settings issueSettings, trigger moira.TriggerData) []string {
+	labels := append([]string{}, settings.Labels...)
+	if settings.LabelsFromTags != nil && *settings.LabelsFromTags {
+		labels = append(labels, trigger.Tags...)
+	}
+
+	result := make([]string, 0, len(labels))
+	seen := make(map[string]struct{}, len(labels))
+	for _, label := range labels {
+		label = strings.Join(strings.Fields(label), "_")
+		if _, ok := seen[label]; ok || label == "" {
+			continue
+		}
+		seen[label] = struct{}{}
+		result = append(result, label)
+	}
+	return result
+}
+
+// getResponseStatus returns status of unsuccessful response of Jira API, zero is returned for other errors.
+func getResponseStatus(err error) int {
+	var responseErr senders.ChatResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.StatusCode
+	}
+	return 0
+}
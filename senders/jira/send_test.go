@@ -0,0 +1,296 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// jiraStandIn is a local stand-in of Jira REST API which records requests.
+type jiraStandIn struct {
+	mutex    sync.Mutex
+	requests map[string][]map[string]interface{}
+	deleted  map[string]bool
+}
+
+func (jira *jiraStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	jira.mutex.Lock()
+	defer jira.mutex.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var body map[string]interface{}
+	if r.Header.Get("Content-Type") == "application/json" {
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &body) //nolint
+	}
+	route := r.Method + " " + r.URL.Path
+	jira.requests[route] = append(jira.requests[route], body)
+
+	switch route {
+	case "POST /rest/api/2/issue":
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"10000","key":"OPS-1"}`)) //nolint
+	case "POST /rest/api/2/issue/OPS-1/comment", "POST /rest/api/2/issue/OPS-1/attachments":
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`)) //nolint
+	case "GET /rest/api/2/issue/OPS-1/transitions":
+		w.Write([]byte(`{"transitions":[{"id":"11","name":"In Progress","to":{"name":"In Progress"}},{"id":"31","name":"Resolve","to":{"name":"Done"}}]}`)) //nolint
+	case "POST /rest/api/2/issue/OPS-1/transitions":
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errorMessages":["Issue does not exist"]}`)) //nolint
+	}
+}
+
+// memoryLocks are in-memory locks which block like database locks.
+type memoryLocks struct {
+	mutex sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func (locks *memoryLocks) NewLock(name string, _ time.Duration) moira.Lock {
+	locks.mutex.Lock()
+	defer locks.mutex.Unlock()
+	if locks.locks[name] == nil {
+		locks.locks[name] = &sync.Mutex{}
+	}
+	return &memoryLock{mutex: locks.locks[name]}
+}
+
+type memoryLock struct {
+	mutex *sync.Mutex
+}
+
+func (lock *memoryLock) Acquire(<-chan struct{}) (<-chan struct{}, error) {
+	lock.mutex.Lock()
+	return make(chan struct{}), nil
+}
+
+func (lock *memoryLock) Release() {
+	lock.mutex.Unlock()
+}
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	contact := moira.ContactData{ID: "contactID", Value: "OPS"}
+	trigger := moira.TriggerData{ID: "triggerID", Name: "Name", Tags: []string{"prod", "web server"}}
+	const issueKey = "contactID:triggerID"
+	const storedIssue = `{"channel_id":"","message_id":"OPS-1","message":""}`
+
+	Convey("Send events to Jira", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+		lock := mock_moira_alert.NewMockLock(mockCtrl)
+		dataBase.EXPECT().NewLock("moira-message-thread:jira:"+issueKey, gomock.Any()).Return(lock).AnyTimes()
+		lock.EXPECT().Acquire(gomock.Any()).Return(nil, nil).AnyTimes()
+		lock.EXPECT().Release().AnyTimes()
+
+		standIn := &jiraStandIn{requests: make(map[string][]map[string]interface{})}
+		server := httptest.NewServer(standIn)
+		defer server.Close()
+
+		sender := &Sender{DataBase: dataBase}
+		settings := map[string]interface{}{
+			"url":                server.URL,
+			"token":              "token",
+			"front_uri":          "http://moira.url",
+			"resolve_transition": "done",
+			"labels_from_tags":   true,
+			"fields":             map[string]interface{}{"customfield_100": "{{ .Trigger.Name }}"},
+		}
+		err := sender.Init(settings, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+
+		Convey("Problem opens issue", func() {
+			events := moira.NotificationEvents{{Metric: "metric", OldState: moira.StateOK, State: moira.StateERROR}}
+			dataBase.EXPECT().GetMessageThread(messenger, issueKey).Return("", database.ErrNil)
+			dataBase.EXPECT().SetMessageThread(messenger, issueKey, storedIssue, 720*time.Hour).Return(nil)
+
+			err = sender.SendEvents(events, contact, trigger, [][]byte{[]byte("plot")}, false)
+			So(err, ShouldBeNil)
+			So(standIn.requests["POST /rest/api/2/issue"], ShouldHaveLength, 1)
+			fields := standIn.requests["POST /rest/api/2/issue"][0]["fields"].(map[string]interface{})
+			So(fields["project"], ShouldResemble, map[string]interface{}{"key": "OPS"})
+			So(fields["issuetype"], ShouldResemble, map[string]interface{}{"name": defaultIssueType})
+			So(fields["summary"], ShouldEqual, "ERROR Name [prod][web server]")
+			So(fields["labels"], ShouldResemble, []interface{}{"prod", "web_server"})
+			So(fields["customfield_100"], ShouldEqual, "Name")
+			So(fields["description"], ShouldStartWith, "*ERROR* [Name|http://moira.url/trigger/triggerID]")
+			So(standIn.requests["POST /rest/api/2/issue/OPS-1/attachments"], ShouldHaveLength, 1)
+		})
+
+		Convey("Warning without open issue is skipped", func() {
+			events := moira.NotificationEvents{{Metric: "metric", OldState: moira.StateOK, State: moira.StateWARN}}
+			dataBase.EXPECT().GetMessageThread(messenger, issueKey).Return("", database.ErrNil)
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(standIn.requests, ShouldBeEmpty)
+		})
+
+		Convey("Subsequent event comments issue", func() {
+			events := moira.NotificationEvents{{Metric: "metric", OldState: moira.StateERROR, State: moira.StateNODATA}}
+			dataBase.EXPECT().GetMessageThread(messenger, issueKey).Return(storedIssue, nil)
+			dataBase.EXPECT().GetTriggerLastCheck(trigger.ID).Return(moira.CheckData{Metrics: map[string]moira.MetricState{"metric": {State: moira.StateNODATA}}}, nil)
+			dataBase.EXPECT().SetMessageThread(messenger, issueKey, storedIssue, 720*time.Hour).Return(nil)
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(standIn.requests["POST /rest/api/2/issue/OPS-1/comment"], ShouldHaveLength, 1)
+			So(standIn.requests["POST /rest/api/2/issue"], ShouldBeEmpty)
+		})
+
+		Convey("Recovery resolves issue", func() {
+			events := moira.NotificationEvents{{Metric: "metric", OldState: moira.StateERROR, State: moira.StateOK}}
+			dataBase.EXPECT().GetMessageThread(messenger, issueKey).Return(storedIssue, nil)
+			dataBase.EXPECT().GetTriggerLastCheck(trigger.ID).Return(moira.CheckData{State: moira.StateOK, Metrics: map[string]moira.MetricState{"metric": {State: moira.StateOK}}}, nil)
+			dataBase.EXPECT().RemoveMessageThread(messenger, issueKey).Return(nil)
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(standIn.requests["POST /rest/api/2/issue/OPS-1/comment"], ShouldHaveLength, 1)
+			So(standIn.requests["POST /rest/api/2/issue/OPS-1/transitions"], ShouldResemble, []map[string]interface{}{
+				{"transition": map[string]interface{}{"id": "31"}},
+			})
+		})
+
+		Convey("Recovery of one metric does not resolve issue while other metrics are in problem state", func() {
+			events := moira.NotificationEvents{{Metric: "metric", OldState: moira.StateERROR, State: moira.StateOK}}
+			dataBase.EXPECT().GetMessageThread(messenger, issueKey).Return(storedIssue, nil)
+			dataBase.EXPECT().GetTriggerLastCheck(trigger.ID).Return(moira.CheckData{State: moira.StateOK, Metrics: map[string]moira.MetricState{
+				"metric":  {State: moira.StateOK},
+				"metric2": {State: moira.StateERROR},
+			}}, nil)
+			dataBase.EXPECT().SetMessageThread(messenger, issueKey, storedIssue, 720*time.Hour).Return(nil)
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(standIn.requests["POST /rest/api/2/issue/OPS-1/comment"], ShouldHaveLength, 1)
+			So(standIn.requests["POST /rest/api/2/issue/OPS-1/transitions"], ShouldBeEmpty)
+		})
+
+		Convey("Failed save of created issue does not fail sending", func() {
+			events := moira.NotificationEvents{{Metric: "metric", OldState: moira.StateOK, State: moira.StateERROR}}
+			dataBase.EXPECT().GetMessageThread(messenger, issueKey).Return("", database.ErrNil)
+			dataBase.EXPECT().SetMessageThread(messenger, issueKey, storedIssue, 720*time.Hour).Return(errors.New("connection refused"))
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(standIn.requests["POST /rest/api/2/issue"], ShouldHaveLength, 1)
+		})
+
+		Convey("Deleted issue is replaced with new one", func() {
+			events := moira.NotificationEvents{{Metric: "metric", OldState: moira.StateOK, State: moira.StateERROR}}
+			dataBase.EXPECT().GetMessageThread(messenger, issueKey).Return(`{"message_id":"OPS-0"}`, nil)
+			dataBase.EXPECT().RemoveMessageThread(messenger, issueKey).Return(nil)
+			dataBase.EXPECT().SetMessageThread(messenger, issueKey, storedIssue, 720*time.Hour).Return(nil)
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(standIn.requests["POST /rest/api/2/issue/OPS-0/comment"], ShouldHaveLength, 1)
+			So(standIn.requests["POST /rest/api/2/issue"], ShouldHaveLength, 1)
+		})
+
+		Convey("Contact without project is broken", func() {
+			events := moira.NotificationEvents{{Metric: "metric", State: moira.StateERROR}}
+			err = sender.SendEvents(events, moira.ContactData{Value: "{}"}, trigger, nil, false)
+			So(err, ShouldHaveSameTypeAs, moira.SenderBrokenContactError{})
+		})
+	})
+}
+
+func TestSendEventsConcurrently(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test", true)
+	contact := moira.ContactData{ID: "contactID", Value: "OPS"}
+	trigger := moira.TriggerData{ID: "triggerID", Name: "Name"}
+	const issueKey = "contactID:triggerID"
+	const sendersCount = 16
+
+	Convey("Concurrent notifications of trigger open one issue", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+		locks := &memoryLocks{locks: make(map[string]*sync.Mutex)}
+		dataBase.EXPECT().NewLock(gomock.Any(), gomock.Any()).DoAndReturn(locks.NewLock).AnyTimes()
+		var issuesMutex sync.Mutex
+		issues := make(map[string]string)
+		dataBase.EXPECT().GetMessageThread(messenger, issueKey).DoAndReturn(func(_, key string) (string, error) {
+			issuesMutex.Lock()
+			defer issuesMutex.Unlock()
+			if issue, ok := issues[key]; ok {
+				return issue, nil
+			}
+			return "", database.ErrNil
+		}).AnyTimes()
+		dataBase.EXPECT().SetMessageThread(messenger, issueKey, gomock.Any(), gomock.Any()).DoAndReturn(func(_, key, issue string, _ time.Duration) error {
+			issuesMutex.Lock()
+			defer issuesMutex.Unlock()
+			issues[key] = issue
+			return nil
+		}).AnyTimes()
+		dataBase.EXPECT().GetTriggerLastCheck(trigger.ID).Return(moira.CheckData{State: moira.StateERROR}, nil).AnyTimes()
+
+		standIn := &jiraStandIn{requests: make(map[string][]map[string]interface{})}
+		server := httptest.NewServer(standIn)
+		defer server.Close()
+
+		sender := &Sender{DataBase: dataBase}
+		err := sender.Init(map[string]interface{}{"url": server.URL, "token": "token"}, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+
+		events := moira.NotificationEvents{{Metric: "metric", OldState: moira.StateOK, State: moira.StateERROR}}
+		errs := make(chan error, sendersCount)
+		var wg sync.WaitGroup
+		for i := 0; i < sendersCount; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- sender.SendEvents(events, contact, trigger, nil, false)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			So(err, ShouldBeNil)
+		}
+		standIn.mutex.Lock()
+		defer standIn.mutex.Unlock()
+		So(standIn.requests["POST /rest/api/2/issue"], ShouldHaveLength, 1)
+		So(standIn.requests["POST /rest/api/2/issue/OPS-1/comment"], ShouldHaveLength, sendersCount-1)
+	})
+}
+
+func TestFormatText(t *testing.T) {
+	Convey("Text of REST API v3 is converted to Atlassian document", t, func() {
+		sender := &Sender{apiVersion: 3}
+		actual := sender.formatText("title\n\nevent")
+		So(actual, ShouldResemble, documentNode{
+			Type:    "doc",
+			Version: 1,
+			Content: []documentNode{
+				{Type: "paragraph", Content: []documentNode{{Type: "text", Text: "title"}}},
+				{Type: "paragraph", Content: []documentNode{{Type: "text", Text: "event"}}},
+			},
+		})
+	})
+}
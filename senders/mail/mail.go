@@ -45,15 +45,21 @@ type Sender struct {
 
 // Init read yaml config.
 func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
-	err := sender.fillSettings(senderSettings, logger, location, dateTimeFormat)
+	err := sender.InitMessageBuilder(senderSettings, logger, location, dateTimeFormat)
 	if err != nil {
 		return err
 	}
-	sender.TemplateName, sender.Template, err = parseTemplate(sender.TemplateFile)
+	err = sender.tryDial()
+	return err
+}
+
+// InitMessageBuilder reads settings and template required to build messages without connecting to smtp server.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	err := sender.fillSettings(senderSettings, logger, location, dateTimeFormat)
 	if err != nil {
 		return err
 	}
-	err = sender.tryDial()
+	sender.TemplateName, sender.Template, err = parseTemplate(sender.TemplateFile)
 	return err
 }
 
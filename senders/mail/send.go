@@ -1,6 +1,7 @@
 package mail
 
 import (
+	"bytes"
 	"crypto/tls"
 	"fmt"
 	"html/template"
@@ -12,6 +13,7 @@ import (
 	"github.com/russross/blackfriday/v2"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
 	"gopkg.in/gomail.v2"
 )
 
@@ -43,21 +45,73 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 	return sender.dialAndSend(message)
 }
 
+// BuildMessage builds html body and subject of mail without sending it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	body := bytes.Buffer{}
+	if err := sender.writeBody(&body, events, contact, trigger, throttled, getPlotCID(plots)); err != nil {
+		return moira.NotificationPayload{}, err
+	}
+	return moira.NotificationPayload{
+		ContentType: "text/html",
+		Title:       buildSubject(events, trigger, throttled),
+		Body:        body.String(),
+	}, nil
+}
+
 func (sender *Sender) makeMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) *gomail.Message {
+	m := gomail.NewMessage()
+	m.SetHeader("From", sender.From)
+	m.SetHeader("To", contact.Value)
+	m.SetHeader("Subject", buildSubject(events, trigger, throttled))
+
+	for i, plot := range plots {
+		plot := plot
+		m.Embed(plotCID(i, plot), gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(plot)
+			return err
+		}))
+	}
+
+	plotCID := getPlotCID(plots)
+	m.AddAlternativeWriter("text/html", func(w io.Writer) error {
+		return sender.writeBody(w, events, contact, trigger, throttled, plotCID)
+	})
+
+	return m
+}
+
+func buildSubject(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
 	state := events.GetCurrentState(throttled)
+	return fmt.Sprintf("%s %s %s (%d)", state, trigger.Name, trigger.GetTags(), len(events))
+}
 
-	tags := trigger.GetTags()
+// writeBody writes html body populated from message template of contact if it is set, otherwise mail template is used.
+func (sender *Sender) writeBody(w io.Writer, events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool, plotCID string) error {
+	messageTemplate := senders.MessageTemplate{
+		FrontURI:   sender.FrontURI,
+		Location:   sender.location,
+		TimeFormat: sender.dateTimeFormat,
+		HTML:       true,
+		Logger:     sender.logger,
+	}
+	if body, ok := messageTemplate.Render(events, contact, trigger, throttled); ok {
+		_, err := io.WriteString(w, body)
+		return err
+	}
 
-	subject := fmt.Sprintf("%s %s %s (%d)", state, trigger.Name, tags, len(events))
+	return sender.Template.ExecuteTemplate(w, sender.TemplateName, sender.buildTemplateData(events, trigger, throttled, plotCID))
+}
 
+func (sender *Sender) buildTemplateData(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool, plotCID string) triggerData {
 	templateData := triggerData{
 		Link:         trigger.GetTriggerURI(sender.FrontURI),
 		Description:  formatDescription(trigger.Desc),
 		Throttled:    throttled,
 		TriggerName:  trigger.Name,
-		Tags:         tags,
-		TriggerState: state,
+		Tags:         trigger.GetTags(),
+		TriggerState: events.GetCurrentState(throttled),
 		Items:        make([]*templateRow, 0, len(events)),
+		PlotCID:      plotCID,
 	}
 
 	for _, event := range events {
@@ -73,27 +127,19 @@ func (sender *Sender) makeMessage(events moira.NotificationEvents, contact moira
 		})
 	}
 
-	m := gomail.NewMessage()
-	m.SetHeader("From", sender.From)
-	m.SetHeader("To", contact.Value)
-	m.SetHeader("Subject", subject)
-
-	if len(plots) > 0 {
-		for i, plot := range plots {
-			plotCID := fmt.Sprintf("plot-t%d.png", i)
-			templateData.PlotCID = plotCID
-			m.Embed(plotCID, gomail.SetCopyFunc(func(w io.Writer) error {
-				_, err := w.Write(plot)
-				return err
-			}))
-		}
-	}
+	return templateData
+}
 
-	m.AddAlternativeWriter("text/html", func(w io.Writer) error {
-		return sender.Template.ExecuteTemplate(w, sender.TemplateName, templateData)
-	})
+func plotCID(index int, plot []byte) string {
+	return fmt.Sprintf("plot-t%d.%s", index, moira.GetPlotFormat(plot))
+}
 
-	return m
+// getPlotCID returns content id of the last plot which is shown in mail template, it is empty if there are no plots.
+func getPlotCID(plots [][]byte) string {
+	if len(plots) == 0 {
+		return ""
+	}
+	return plotCID(len(plots)-1, plots[len(plots)-1])
 }
 
 func formatDescription(desc string) template.HTML {
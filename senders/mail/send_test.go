@@ -59,6 +59,19 @@ some other text _italics text_`,
 		So(messageStr.String(), ShouldContainSubstring, "<em>italics text</em>")
 		So(messageStr.String(), ShouldContainSubstring, "<strong>bold text</strong>")
 	})
+
+	Convey("Make message with contact message template", t, func() {
+		contact := contact
+		contact.MessageTemplate = "<h1>{{ .Trigger.Name }}</h1><p>{{ len .Events }} events</p>"
+
+		message := sender.makeMessage(generateTestEvents(10, trigger.ID), contact, trigger, nil, false)
+
+		messageStr := new(bytes.Buffer)
+		_, err := message.WriteTo(messageStr)
+		So(err, ShouldBeNil)
+		So(messageStr.String(), ShouldContainSubstring, "<h1>test trigger 1</h1><p>10 events</p>")
+		So(messageStr.String(), ShouldNotContainSubstring, "<strong>bold text</strong>")
+	})
 }
 
 func generateTestEvents(n int, subscriptionID string) []moira.NotificationEvent {
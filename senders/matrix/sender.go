@@ -0,0 +1,221 @@
+// Package matrix is Moira sender for Matrix rooms, it posts messages via client-server API of homeserver.
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mitchellh/mapstructure"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+	"github.com/russross/blackfriday/v2"
+)
+
+const (
+	messageMaxCharacters = 16_000
+	requestTimeout       = 30 * time.Second
+	htmlFormat           = "org.matrix.custom.html"
+	roomAliasPrefix      = "#"
+)
+
+// Structure that represents the Matrix configuration in the YAML file.
+type config struct {
+	URL         string `mapstructure:"url"`
+	AccessToken string `mapstructure:"access_token"`
+	FrontURI    string `mapstructure:"front_uri"`
+	// MessageTemplate is a Go template of messages, it is used for contacts without their own template.
+	MessageTemplate string `mapstructure:"message_template"`
+}
+
+// Sender posts messages to Matrix rooms, contact value is room id (!room:server) or room alias (#room:server).
+// Bot account the access token belongs to must be joined to the room.
+type Sender struct {
+	url            string
+	accessToken    string
+	messageBuilder senders.ChatMessageBuilder
+	client         *senders.ChatClient
+	logger         moira.Logger
+}
+
+type textMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+type imageMessage struct {
+	MsgType string    `json:"msgtype"`
+	Body    string    `json:"body"`
+	URL     string    `json:"url"`
+	Info    imageInfo `json:"info"`
+}
+
+type imageInfo struct {
+	MimeType string `json:"mimetype"`
+	Size     int    `json:"size"`
+}
+
+// Init configures Sender.
+func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	if err := mapstructure.Decode(senderSettings, &cfg); err != nil {
+		return fmt.Errorf("failed to decode senderSettings to matrix config: %w", err)
+	}
+
+	if cfg.URL == "" {
+		return fmt.Errorf("can not read Matrix url from config")
+	}
+	if cfg.AccessToken == "" {
+		return fmt.Errorf("can not read Matrix access_token from config")
+	}
+
+	sender.url = strings.TrimSuffix(cfg.URL, "/")
+	sender.accessToken = cfg.AccessToken
+	sender.client = senders.NewChatClient(requestTimeout)
+	sender.initMessageBuilder(cfg, logger, location)
+	return nil
+}
+
+// InitMessageBuilder reads settings required to build messages without connecting to Matrix.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	if err := mapstructure.Decode(senderSettings, &cfg); err != nil {
+		return fmt.Errorf("failed to decode senderSettings to matrix config: %w", err)
+	}
+	sender.initMessageBuilder(cfg, logger, location)
+	return nil
+}
+
+func (sender *Sender) initMessageBuilder(cfg config, logger moira.Logger, location *time.Location) {
+	sender.logger = logger
+	sender.messageBuilder = senders.ChatMessageBuilder{
+		Markup:   senders.MarkdownChatMarkup,
+		FrontURI: cfg.FrontURI,
+		Location: location,
+		MaxChars: messageMaxCharacters,
+		Template: senders.MessageTemplate{
+			Template: cfg.MessageTemplate,
+			FrontURI: cfg.FrontURI,
+			Location: location,
+			MaxChars: messageMaxCharacters,
+			Logger:   logger,
+		},
+	}
+}
+
+// BuildMessage builds Matrix message without sending it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	return moira.NotificationPayload{
+		ContentType: "text/markdown",
+		Body:        sender.messageBuilder.BuildContactMessage(events, contact, trigger, throttled),
+	}, nil
+}
+
+// SendEvents implements Sender interface Send.
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
+	roomID, err := sender.getRoomID(contact.Value)
+	if err != nil {
+		return senders.WrapChatError(err, fmt.Sprintf("failed to resolve Matrix room %s", contact.Value))
+	}
+
+	message := sender.messageBuilder.BuildContactMessage(events, contact, trigger, throttled)
+	err = sender.sendEvent(roomID, textMessage{
+		MsgType:       "m.text",
+		Body:          message,
+		Format:        htmlFormat,
+		FormattedBody: string(blackfriday.Run([]byte(message))),
+	})
+	if err != nil {
+		return senders.WrapChatError(err, fmt.Sprintf("failed to send %s event message to Matrix room %s", trigger.ID, contact.Value))
+	}
+
+	for _, plot := range plots {
+		if err = sender.sendPlot(roomID, trigger.ID, plot); err != nil {
+			sender.logger.Warning().
+				String(moira.LogFieldNameTriggerID, trigger.ID).
+				String("contact_value", contact.Value).
+				Error(err).
+				Msg("Failed to send plot to Matrix room")
+		}
+	}
+	return nil
+}
+
+// getRoomID resolves room alias to room id, room id is returned as is.
+func (sender *Sender) getRoomID(room string) (string, error) {
+	if !strings.HasPrefix(room, roomAliasPrefix) {
+		return room, nil
+	}
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, sender.url+"/_matrix/client/v3/directory/room/"+url.PathEscape(room), nil)
+	if err != nil {
+		return "", err
+	}
+	sender.authorize(request)
+
+	var response struct {
+		RoomID string `json:"room_id"`
+	}
+	if err = sender.client.Do(request, &response); err != nil {
+		return "", err
+	}
+	return response.RoomID, nil
+}
+
+// sendEvent sends message event to room, transaction id makes retries of the same request idempotent.
+func (sender *Sender) sendEvent(roomID string, content interface{}) error {
+	transactionID, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	requestURL := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s", sender.url, url.PathEscape(roomID), transactionID)
+	request, err := senders.NewJSONRequest(http.MethodPut, requestURL, content)
+	if err != nil {
+		return err
+	}
+	sender.authorize(request)
+	return sender.client.Do(request, nil)
+}
+
+// sendPlot uploads plot to media repository of homeserver and sends it to room as image.
+func (sender *Sender) sendPlot(roomID, triggerID string, plot []byte) error {
+	filename := senders.PlotFileName(triggerID, plot)
+	contentType := moira.GetPlotFormat(plot).ContentType()
+
+	requestURL := sender.url + "/_matrix/media/v3/upload?filename=" + url.QueryEscape(filename)
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, requestURL, bytes.NewReader(plot))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", contentType)
+	sender.authorize(request)
+
+	var response struct {
+		ContentURI string `json:"content_uri"`
+	}
+	if err = sender.client.Do(request, &response); err != nil {
+		return fmt.Errorf("failed to upload plot: %w", err)
+	}
+
+	return sender.sendEvent(roomID, imageMessage{
+		MsgType: "m.image",
+		Body:    filename,
+		URL:     response.ContentURI,
+		Info: imageInfo{
+			MimeType: contentType,
+			Size:     len(plot),
+		},
+	})
+}
+
+func (sender *Sender) authorize(request *http.Request) {
+	request.Header.Set("Authorization", "Bearer "+sender.accessToken)
+}
@@ -0,0 +1,109 @@
+package matrix
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInit(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+
+	Convey("Init matrix sender", t, func() {
+		sender := &Sender{}
+
+		Convey("Without url", func() {
+			err := sender.Init(map[string]interface{}{"access_token": "token"}, logger, time.UTC, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Without access token", func() {
+			err := sender.Init(map[string]interface{}{"url": "https://matrix.org"}, logger, time.UTC, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("With full config", func() {
+			err := sender.Init(map[string]interface{}{"url": "https://matrix.org/", "access_token": "token"}, logger, time.UTC, "")
+			So(err, ShouldBeNil)
+			So(sender.url, ShouldEqual, "https://matrix.org")
+		})
+	})
+}
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	events := moira.NotificationEvents{{Metric: "metric", OldState: moira.StateOK, State: moira.StateERROR}}
+	trigger := moira.TriggerData{ID: "triggerID", Name: "Name"}
+
+	Convey("Send events to matrix room", t, func() {
+		var sentEvents []map[string]interface{}
+		var uploads []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			switch {
+			case r.URL.Path == "/_matrix/client/v3/directory/room/#alerts:server":
+				w.Write([]byte(`{"room_id":"!room:server"}`)) //nolint
+			case r.URL.Path == "/_matrix/client/v3/directory/room/#missing:server":
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errcode":"M_NOT_FOUND"}`)) //nolint
+			case r.URL.Path == "/_matrix/media/v3/upload":
+				uploads = append(uploads, r.URL.Query().Get("filename"))
+				w.Write([]byte(`{"content_uri":"mxc://server/plot"}`)) //nolint
+			case strings.HasPrefix(r.URL.Path, "/_matrix/client/v3/rooms/!room:server/send/m.room.message/"):
+				body, _ := io.ReadAll(r.Body)
+				var event map[string]interface{}
+				json.Unmarshal(body, &event) //nolint
+				sentEvents = append(sentEvents, event)
+				w.Write([]byte(`{"event_id":"$event"}`)) //nolint
+			default:
+				w.WriteHeader(http.StatusForbidden)
+			}
+		}))
+		defer server.Close()
+
+		sender := &Sender{}
+		err := sender.Init(map[string]interface{}{"url": server.URL, "access_token": "token"}, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+
+		Convey("Message and plot are sent to room resolved by alias", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "#alerts:server"}, trigger, [][]byte{[]byte("\x89PNG\r\n\x1a\n")}, false)
+			So(err, ShouldBeNil)
+			So(sentEvents, ShouldHaveLength, 2)
+			So(sentEvents[0]["msgtype"], ShouldEqual, "m.text")
+			So(sentEvents[0]["format"], ShouldEqual, htmlFormat)
+			So(sentEvents[0]["body"], ShouldStartWith, "**ERROR** [Name](/trigger/triggerID)")
+			So(sentEvents[0]["formatted_body"], ShouldContainSubstring, "<strong>ERROR</strong>")
+			So(uploads, ShouldResemble, []string{"triggerID.png"})
+			So(sentEvents[1]["msgtype"], ShouldEqual, "m.image")
+			So(sentEvents[1]["url"], ShouldEqual, "mxc://server/plot")
+		})
+
+		Convey("Message is sent to room id", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "!room:server"}, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(sentEvents, ShouldHaveLength, 1)
+		})
+
+		Convey("Missing room is broken contact", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "#missing:server"}, trigger, nil, false)
+			So(err, ShouldHaveSameTypeAs, moira.SenderBrokenContactError{})
+			So(err.Error(), ShouldContainSubstring, "404")
+		})
+
+		Convey("Room bot has no access to is broken contact", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "!other:server"}, trigger, nil, false)
+			So(err, ShouldHaveSameTypeAs, moira.SenderBrokenContactError{})
+		})
+	})
+}
@@ -11,4 +11,5 @@ type Client interface {
 	SetToken(token string)
 	CreatePost(ctx context.Context, post *model.Post) (*model.Post, *model.Response, error)
 	UploadFile(ctx context.Context, data []byte, channelId string, filename string) (*model.FileUploadResponse, *model.Response, error)
+	PatchPost(ctx context.Context, postId string, patch *model.PostPatch) (*model.Post, *model.Response, error)
 }
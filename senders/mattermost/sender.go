@@ -25,23 +25,28 @@ type config struct {
 	UseEmoji     bool              `mapstructure:"use_emoji"`
 	DefaultEmoji string            `mapstructure:"default_emoji"`
 	EmojiMap     map[string]string `mapstructure:"emoji_map"`
+	// MessageTemplate is a Go template of messages, it is used for contacts without their own template.
+	MessageTemplate string `mapstructure:"message_template"`
+	// Threads enables posting of follow-up notifications as replies to the first message of incident, trigger or metric.
+	Threads senders.ThreadMode `mapstructure:"threads"`
+	// ThreadTTL is the period thread is kept open since the last notification. Default is 24h.
+	ThreadTTL string `mapstructure:"thread_ttl"`
 }
 
 // Sender posts messages to Mattermost chat.
 // It implements moira.Sender.
 // You must call Init method before SendEvents method.
 type Sender struct {
-	frontURI      string
-	useEmoji      bool
-	emojiProvider emoji_provider.StateEmojiGetter
-	logger        moira.Logger
-	location      *time.Location
-	client        Client
+	DataBase       moira.Database
+	threads        *senders.MessageThreads
+	messageBuilder senders.ChatMessageBuilder
+	logger         moira.Logger
+	client         Client
 }
 
 const (
 	messageMaxCharacters = 4_000
-	quotas               = "```"
+	messenger            = "mattermost"
 )
 
 // Init configures Sender.
@@ -77,29 +82,87 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 		return fmt.Errorf("can not read Mattermost front_uri from config")
 	}
 
+	if sender.threads, err = senders.NewMessageThreads(sender.DataBase, messenger, cfg.Threads, cfg.ThreadTTL); err != nil {
+		return fmt.Errorf("failed to configure Mattermost threads: %w", err)
+	}
+
+	return sender.initMessageBuilder(cfg, logger, location)
+}
+
+// InitMessageBuilder reads settings required to build messages without connecting to Mattermost.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, _ string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to mattermost config: %w", err)
+	}
+	return sender.initMessageBuilder(cfg, logger, location)
+}
+
+func (sender *Sender) initMessageBuilder(cfg config, logger moira.Logger, location *time.Location) error {
 	emojiProvider, err := emoji_provider.NewEmojiProvider(cfg.DefaultEmoji, cfg.EmojiMap)
 	if err != nil {
 		return fmt.Errorf("cannot initialize mattermost sender, err: %w", err)
 	}
-	sender.emojiProvider = emojiProvider
-	sender.frontURI = cfg.FrontURI
-	sender.useEmoji = cfg.UseEmoji
-	sender.location = location
 	sender.logger = logger
+	sender.messageBuilder = senders.ChatMessageBuilder{
+		Markup:   senders.MarkdownChatMarkup,
+		FrontURI: cfg.FrontURI,
+		Location: location,
+		MaxChars: messageMaxCharacters,
+		Template: senders.MessageTemplate{
+			Template: cfg.MessageTemplate,
+			FrontURI: cfg.FrontURI,
+			Location: location,
+			MaxChars: messageMaxCharacters,
+			Logger:   logger,
+		},
+	}
+	if cfg.UseEmoji {
+		sender.messageBuilder.StateEmoji = emojiProvider.GetStateEmoji
+	}
 
 	return nil
 }
 
+// BuildMessage builds Mattermost post without sending it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	return moira.NotificationPayload{
+		ContentType: "text/markdown",
+		Body:        sender.messageBuilder.BuildContactMessage(events, contact, trigger, throttled),
+	}, nil
+}
+
 // SendEvents implements moira.Sender interface.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
-	message := sender.buildMessage(events, trigger, throttled)
+	message := sender.messageBuilder.BuildContactMessage(events, contact, trigger, throttled)
 	ctx := context.Background()
-	post, err := sender.sendMessage(ctx, message, contact.Value, trigger.ID)
+
+	if sender.threads != nil {
+		// Notifications of trigger are sent by parallel senders, thread is locked so only one of them starts it
+		unlock, err := sender.threads.Lock(sender.threads.GetKey(events, contact, trigger))
+		if err != nil {
+			return fmt.Errorf("failed to lock Mattermost thread of trigger %s: %w", trigger.ID, err)
+		}
+		defer unlock()
+	}
+
+	threadKey, thread := sender.getThread(events, contact, trigger)
+	rootID := ""
+	if thread != nil {
+		rootID = thread.MessageID
+	}
+
+	post, err := sender.sendMessage(ctx, message, contact.Value, trigger.ID, rootID)
 	if err != nil {
 		return err
 	}
+	if rootID == "" {
+		rootID = post.Id
+	}
+
 	if len(plots) > 0 {
-		err = sender.sendPlots(ctx, plots, contact.Value, post.Id, trigger.ID)
+		err = sender.sendPlots(ctx, plots, contact.Value, rootID, trigger.ID)
 		if err != nil {
 			sender.logger.Warning().
 				String("trigger_id", trigger.ID).
@@ -109,124 +172,86 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 		}
 	}
 
-	return nil
-}
-
-func (sender *Sender) buildMessage(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
-	var message strings.Builder
-	title := sender.buildTitle(events, trigger, throttled)
-	titleLen := len([]rune(title))
-
-	desc := sender.buildDescription(trigger)
-	descLen := len([]rune(desc))
-
-	eventsString := sender.buildEventsString(events, -1, throttled)
-	eventsStringLen := len([]rune(eventsString))
-
-	charsLeftAfterTitle := messageMaxCharacters - titleLen
-
-	descNewLen, eventsNewLen := senders.CalculateMessagePartsLength(charsLeftAfterTitle, descLen, eventsStringLen)
-	if descLen != descNewLen {
-		desc = desc[:descNewLen] + "...\n"
-	}
-	if eventsNewLen != eventsStringLen {
-		eventsString = sender.buildEventsString(events, eventsNewLen, throttled)
+	newThread := senders.MessageThread{ChannelID: contact.Value, MessageID: post.Id, Message: message}
+	if err = sender.updateThread(ctx, threadKey, thread, newThread, events, trigger, throttled); err != nil {
+		sender.logger.Warning().
+			String("trigger_id", trigger.ID).
+			String("contact_value", contact.Value).
+			String("contact_type", contact.Type).
+			Error(err).
+			Msg("Failed to update Mattermost thread")
 	}
 
-	message.WriteString(title)
-	message.WriteString(desc)
-	message.WriteString(eventsString)
-	return message.String()
-}
-
-func (sender *Sender) buildDescription(trigger moira.TriggerData) string {
-	desc := trigger.Desc
-	if trigger.Desc != "" {
-		desc += "\n"
-	}
-	return desc
+	return nil
 }
 
-func (sender *Sender) buildTitle(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
-	state := events.GetCurrentState(throttled)
-	title := ""
-	if sender.useEmoji {
-		title += sender.emojiProvider.GetStateEmoji(state) + " "
-	}
-
-	title += fmt.Sprintf("**%s**", state)
-	triggerURI := trigger.GetTriggerURI(sender.frontURI)
-	if triggerURI != "" {
-		title += fmt.Sprintf(" [%s](%s)", trigger.Name, triggerURI)
-	} else if trigger.Name != "" {
-		title += " " + trigger.Name
+// getThread returns key of thread the notification belongs to and root post of open incident if there is one.
+func (sender *Sender) getThread(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData) (string, *senders.MessageThread) {
+	if sender.threads == nil {
+		return "", nil
 	}
-
-	tags := trigger.GetTags()
-	if tags != "" {
-		title += " " + tags
+	threadKey := sender.threads.GetKey(events, contact, trigger)
+	thread, err := sender.threads.Get(threadKey)
+	if err != nil {
+		sender.logger.Warning().
+			String("trigger_id", trigger.ID).
+			String("contact_value", contact.Value).
+			Error(err).
+			Msg("Failed to get Mattermost thread, message is posted to channel")
 	}
-
-	title += "\n"
-	return title
+	return threadKey, thread
 }
 
-// buildEventsString builds the string from moira events and limits it to charsForEvents.
-// If n is negative buildEventsString does not limit the events string.
-func (sender *Sender) buildEventsString(events moira.NotificationEvents, charsForEvents int, throttled bool) string {
-	charsForThrottleMsg := 0
-	throttleMsg := "\nPlease, *fix your system or tune this trigger* to generate less events."
-	if throttled {
-		charsForThrottleMsg = len([]rune(throttleMsg))
+// updateThread remembers root post of new incident, shows current state in root post of open incident
+// and closes incident when it is resolved, see senders.MessageThreads.GetIncidentState.
+func (sender *Sender) updateThread(
+	ctx context.Context,
+	threadKey string,
+	thread *senders.MessageThread,
+	newThread senders.MessageThread,
+	events moira.NotificationEvents,
+	trigger moira.TriggerData,
+	throttled bool,
+) error {
+	if sender.threads == nil {
+		return nil
 	}
-	charsLeftForEvents := charsForEvents - charsForThrottleMsg
-
-	eventsString := quotas
-	var tailString string
-
-	eventsLenLimitReached := false
-	eventsPrinted := 0
-	for _, event := range events {
-		line := fmt.Sprintf(
-			"\n%s: %s = %s (%s to %s)",
-			event.FormatTimestamp(sender.location, moira.DefaultTimeFormat),
-			event.Metric,
-			event.GetMetricsValues(moira.DefaultNotificationSettings),
-			event.OldState,
-			event.State,
-		)
-		if msg := event.CreateMessage(sender.location); len(msg) > 0 {
-			line += fmt.Sprintf(". %s", msg)
-		}
-
-		tailString = fmt.Sprintf("\n...and %d more events.", len(events)-eventsPrinted)
-		tailStringLen := len([]rune(quotas)) + len([]rune(tailString))
-		if !(charsForEvents < 0) && (len([]rune(eventsString))+len([]rune(line)) > charsLeftForEvents-tailStringLen) {
-			eventsLenLimitReached = true
-			break
+	state, err := sender.threads.GetIncidentState(events, trigger, throttled)
+	if err != nil {
+		return err
+	}
+	if thread == nil {
+		if state == moira.StateOK {
+			return nil
 		}
-
-		eventsString += line
-		eventsPrinted++
+		return sender.threads.Save(threadKey, newThread)
 	}
-	eventsString += "\n"
-	eventsString += quotas
 
-	if eventsLenLimitReached {
-		eventsString += tailString
+	rootMessage := buildThreadRootMessage(thread.Message, state)
+	if _, _, err := sender.client.PatchPost(ctx, thread.MessageID, &model.PostPatch{Message: &rootMessage}); err != nil {
+		return fmt.Errorf("failed to update root post: %w", err)
 	}
-
-	if throttled {
-		eventsString += throttleMsg
+	if state == moira.StateOK {
+		return sender.threads.Close(threadKey)
 	}
+	return sender.threads.Save(threadKey, *thread)
+}
 
-	return eventsString
+// buildThreadRootMessage shows current state of incident in its root post, title of resolved incident is struck through.
+func buildThreadRootMessage(message string, state moira.State) string {
+	if state != moira.StateOK {
+		return fmt.Sprintf("Current state: **%s**\n%s", state, message)
+	}
+	title, rest, _ := strings.Cut(message, "\n")
+	return fmt.Sprintf("Current state: **%s**\n~~%s~~\n%s", state, title, rest)
 }
 
-func (sender *Sender) sendMessage(ctx context.Context, message string, contact string, triggerID string) (*model.Post, error) {
+// sendMessage creates post in channel, post is created as reply if root id is set.
+func (sender *Sender) sendMessage(ctx context.Context, message string, contact string, triggerID string, rootID string) (*model.Post, error) {
 	post := model.Post{
 		ChannelId: contact,
 		Message:   message,
+		RootId:    rootID,
 	}
 
 	sentPost, _, err := sender.client.CreatePost(ctx, &post)
@@ -240,8 +265,8 @@ func (sender *Sender) sendMessage(ctx context.Context, message string, contact s
 func (sender *Sender) sendPlots(ctx context.Context, plots [][]byte, channelID, postID, triggerID string) error {
 	var filesID []string
 
-	filename := fmt.Sprintf("%s.png", triggerID)
 	for _, plot := range plots {
+		filename := senders.PlotFileName(triggerID, plot)
 		file, _, err := sender.client.UploadFile(ctx, plot, channelID, filename)
 		if err != nil {
 			return err
@@ -10,9 +10,11 @@ import (
 	"github.com/mattermost/mattermost/server/public/model"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
 
 	"github.com/golang/mock/gomock"
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
 	mock "github.com/moira-alert/moira/mock/notifier/mattermost"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -73,6 +75,114 @@ func TestSendEvents(t *testing.T) {
 	})
 }
 
+func TestSendEventsToThread(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+
+	Convey("Given sender with trigger threads", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(ctrl)
+		client := mock.NewMockClient(ctrl)
+		sender := &Sender{DataBase: dataBase}
+		senderSettings := map[string]interface{}{
+			"url":        "qwerty",
+			"api_token":  "qwerty",
+			"front_uri":  "qwerty",
+			"threads":    "trigger",
+			"thread_ttl": "1h",
+		}
+		err := sender.Init(senderSettings, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+		sender.client = client
+
+		contact := moira.ContactData{ID: "contactID", Value: "channelID"}
+		trigger := moira.TriggerData{ID: "triggerID", Name: "Name"}
+		const storedThread = `{"channel_id":"channelID","message_id":"rootID","message":"title\nevents"}`
+		lock := mock_moira_alert.NewMockLock(ctrl)
+		dataBase.EXPECT().NewLock("moira-message-thread:mattermost:contactID:triggerID", gomock.Any()).Return(lock).AnyTimes()
+		var lockErr error
+		lock.EXPECT().Acquire(gomock.Any()).DoAndReturn(func(<-chan struct{}) (<-chan struct{}, error) { return nil, lockErr }).AnyTimes()
+		lock.EXPECT().Release().AnyTimes()
+
+		Convey("Notification is not posted if thread is not locked", func() {
+			lockErr = database.ErrLockAcquireInterrupted
+			events := moira.NotificationEvents{{Metric: "metric", State: moira.StateERROR}}
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(errors.Is(err, database.ErrLockAcquireInterrupted), ShouldBeTrue)
+		})
+
+		Convey("First notification of incident starts thread", func() {
+			events := moira.NotificationEvents{{Metric: "metric", State: moira.StateERROR}}
+			dataBase.EXPECT().GetMessageThread("mattermost", "contactID:triggerID").Return("", database.ErrNil)
+			client.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(&model.Post{Id: "rootID"}, nil, nil)
+			dataBase.EXPECT().GetTriggerLastCheck("triggerID").Return(moira.CheckData{Metrics: map[string]moira.MetricState{"metric": {State: moira.StateERROR}}}, nil)
+			dataBase.EXPECT().SetMessageThread("mattermost", "contactID:triggerID", gomock.Any(), time.Hour).Return(nil)
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Follow-up notification is posted as reply and updates root post", func() {
+			events := moira.NotificationEvents{{Metric: "metric", State: moira.StateWARN}}
+			dataBase.EXPECT().GetMessageThread("mattermost", "contactID:triggerID").Return(storedThread, nil)
+			dataBase.EXPECT().GetTriggerLastCheck("triggerID").Return(moira.CheckData{Metrics: map[string]moira.MetricState{"metric": {State: moira.StateWARN}}}, nil)
+			var reply *model.Post
+			client.EXPECT().CreatePost(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, post *model.Post) (*model.Post, *model.Response, error) {
+					reply = post
+					return &model.Post{Id: "replyID"}, nil, nil
+				})
+			var patch *model.PostPatch
+			client.EXPECT().PatchPost(gomock.Any(), "rootID", gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, postPatch *model.PostPatch) (*model.Post, *model.Response, error) {
+					patch = postPatch
+					return &model.Post{}, nil, nil
+				})
+			dataBase.EXPECT().SetMessageThread("mattermost", "contactID:triggerID", storedThread, time.Hour).Return(nil)
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(reply.RootId, ShouldEqual, "rootID")
+			So(reply.ChannelId, ShouldEqual, "channelID")
+			So(*patch.Message, ShouldEqual, "Current state: **WARN**\ntitle\nevents")
+		})
+
+		Convey("Recovery closes thread", func() {
+			events := moira.NotificationEvents{{Metric: "metric", State: moira.StateOK}}
+			dataBase.EXPECT().GetMessageThread("mattermost", "contactID:triggerID").Return(storedThread, nil)
+			client.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(&model.Post{Id: "replyID"}, nil, nil)
+			dataBase.EXPECT().GetTriggerLastCheck("triggerID").Return(moira.CheckData{State: moira.StateOK, Metrics: map[string]moira.MetricState{"metric": {State: moira.StateOK}}}, nil)
+			var patch *model.PostPatch
+			client.EXPECT().PatchPost(gomock.Any(), "rootID", gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, postPatch *model.PostPatch) (*model.Post, *model.Response, error) {
+					patch = postPatch
+					return &model.Post{}, nil, nil
+				})
+			dataBase.EXPECT().RemoveMessageThread("mattermost", "contactID:triggerID").Return(nil)
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(*patch.Message, ShouldEqual, "Current state: **OK**\n~~title~~\nevents")
+		})
+
+		Convey("Recovery of one metric does not close thread while other metrics are in problem state", func() {
+			events := moira.NotificationEvents{{Metric: "metric", State: moira.StateOK}}
+			dataBase.EXPECT().GetMessageThread("mattermost", "contactID:triggerID").Return(storedThread, nil)
+			client.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(&model.Post{Id: "replyID"}, nil, nil)
+			dataBase.EXPECT().GetTriggerLastCheck("triggerID").Return(moira.CheckData{State: moira.StateOK, Metrics: map[string]moira.MetricState{
+				"metric":  {State: moira.StateOK},
+				"metric2": {State: moira.StateNODATA},
+			}}, nil)
+			client.EXPECT().PatchPost(gomock.Any(), "rootID", gomock.Any()).Return(&model.Post{}, nil, nil)
+			dataBase.EXPECT().SetMessageThread("mattermost", "contactID:triggerID", storedThread, time.Hour).Return(nil)
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
 func TestBuildMessage(t *testing.T) {
 	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
 	sender := &Sender{}
@@ -106,7 +216,7 @@ func TestBuildMessage(t *testing.T) {
 
 		Convey("Message with one event", func() {
 			events, throttled := moira.NotificationEvents{event}, false
-			msg := sender.buildMessage(events, trigger, throttled)
+			msg := sender.messageBuilder.BuildMessage(events, trigger, throttled)
 
 			expected := "**NODATA** [Name](http://moira.url/trigger/TriggerID) [tag1][tag2]\n" +
 				shortDesc + "\n" +
@@ -117,7 +227,7 @@ func TestBuildMessage(t *testing.T) {
 
 		Convey("Message with one event and throttled", func() {
 			events, throttled := moira.NotificationEvents{event}, true
-			msg := sender.buildMessage(events, trigger, throttled)
+			msg := sender.messageBuilder.BuildMessage(events, trigger, throttled)
 
 			expected := "**NODATA** [Name](http://moira.url/trigger/TriggerID) [tag1][tag2]\n" +
 				shortDesc + "\n" +
@@ -128,7 +238,7 @@ func TestBuildMessage(t *testing.T) {
 		})
 
 		Convey("Moira message with 3 events", func() {
-			actual := sender.buildMessage([]moira.NotificationEvent{event, event, event}, trigger, false)
+			actual := sender.messageBuilder.BuildMessage([]moira.NotificationEvent{event, event, event}, trigger, false)
 			expected := "**NODATA** [Name](http://moira.url/trigger/TriggerID) [tag1][tag2]\n" +
 				shortDesc + "\n" +
 				"```\n" +
@@ -163,7 +273,7 @@ func TestBuildMessage(t *testing.T) {
 					events = append(events, event)
 				}
 
-				actual := sender.buildMessage(events, moira.TriggerData{Desc: longDesc}, false)
+				actual := sender.messageBuilder.BuildMessage(events, moira.TriggerData{Desc: longDesc}, false)
 				expected := "**NODATA**\n" +
 					strings.Repeat("a", 2100) + "\n" +
 					"```\n" +
@@ -174,7 +284,7 @@ func TestBuildMessage(t *testing.T) {
 
 			Convey("Many events. eventString > msgLimit/2", func() {
 				desc := strings.Repeat("a", lessThanHalf)
-				actual := sender.buildMessage(longEvents, moira.TriggerData{Desc: desc}, false)
+				actual := sender.messageBuilder.BuildMessage(longEvents, moira.TriggerData{Desc: desc}, false)
 				expected := "**NODATA**\n" +
 					desc + "\n" +
 					"```\n" +
@@ -184,7 +294,7 @@ func TestBuildMessage(t *testing.T) {
 			})
 
 			Convey("Long description and many events. both desc and events > msgLimit/2", func() {
-				actual := sender.buildMessage(longEvents, moira.TriggerData{Desc: longDesc}, false)
+				actual := sender.messageBuilder.BuildMessage(longEvents, moira.TriggerData{Desc: longDesc}, false)
 				expected := "**NODATA**\n" +
 					strings.Repeat("a", 1984) + "...\n" +
 					"```\n" +
@@ -0,0 +1,87 @@
+package senders
+
+import (
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/templating"
+)
+
+const truncatedMessageSuffix = "..."
+
+// MessageTemplate renders user-defined message templates of chat and mail senders.
+type MessageTemplate struct {
+	// Template is set in sender settings and is used if contact has no template of its own.
+	Template   string
+	FrontURI   string
+	Location   *time.Location
+	TimeFormat string
+	// HTML enables escaping of populated data, it should be set by senders which send html messages.
+	HTML bool
+	// MaxChars limits length of populated message, message is not limited if it is zero.
+	MaxChars int
+	Logger   moira.Logger
+}
+
+// Render populates contact or sender message template with notification data.
+// It returns false if there is no template or template can't be populated, so sender should build message on its own.
+func (messageTemplate MessageTemplate) Render(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) (string, bool) {
+	tmpl := contact.MessageTemplate
+	if tmpl == "" {
+		tmpl = messageTemplate.Template
+	}
+	if tmpl == "" {
+		return "", false
+	}
+
+	location := messageTemplate.Location
+	if location == nil {
+		location = time.UTC
+	}
+	timeFormat := messageTemplate.TimeFormat
+	if timeFormat == "" {
+		timeFormat = moira.DefaultTimeFormat
+	}
+
+	data := &templating.MessageData{
+		Trigger:   trigger.ToTemplateMessageTrigger(messageTemplate.FrontURI),
+		Contact:   *contact.ToTemplateContact(),
+		Events:    events.ToTemplateMessageEvents(location, timeFormat),
+		State:     string(events.GetCurrentState(throttled)),
+		Throttled: throttled,
+	}
+
+	populater := templating.NewMessagePopulater(data)
+	if messageTemplate.HTML {
+		populater = templating.NewHTMLMessagePopulater(data)
+	}
+
+	message, err := populater.Populate(tmpl)
+	if err != nil {
+		if messageTemplate.Logger != nil {
+			messageTemplate.Logger.Warning().
+				String(moira.LogFieldNameContactID, contact.ID).
+				String(moira.LogFieldNameTriggerID, trigger.ID).
+				Error(err).
+				Msg("Failed to populate message template, default message is used")
+		}
+		return "", false
+	}
+
+	return TruncateMessage(message, messageTemplate.MaxChars), true
+}
+
+// TruncateMessage cuts message to maxChars characters, the end of truncated message is replaced with ellipsis.
+func TruncateMessage(message string, maxChars int) string {
+	runes := []rune(message)
+	if maxChars <= 0 || len(runes) <= maxChars {
+		return message
+	}
+
+	suffixLen := len([]rune(truncatedMessageSuffix))
+	if maxChars <= suffixLen {
+		return string(runes[:maxChars])
+	}
+
+	return string(runes[:maxChars-suffixLen]) + truncatedMessageSuffix
+}
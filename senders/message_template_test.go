@@ -0,0 +1,95 @@
+package senders
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMessageTemplateRender(t *testing.T) {
+	logger, _ := logging.GetLogger("senders")
+	value := 97.0
+	events := moira.NotificationEvents{
+		{
+			Metric:    "server.hdd.used_percent",
+			Timestamp: 1590741878,
+			Value:     &value,
+			Values:    map[string]float64{"t1": value},
+			State:     moira.StateERROR,
+			OldState:  moira.StateWARN,
+		},
+	}
+	trigger := moira.TriggerData{ID: "trigger-id", Name: "<Disk>", Tags: []string{"server"}}
+	contact := moira.ContactData{ID: "contact-id", Type: "slack", Value: "#alerts"}
+
+	Convey("Test MessageTemplate Render", t, func() {
+		messageTemplate := MessageTemplate{
+			FrontURI: "https://moira.example.com",
+			Location: time.UTC,
+			Logger:   logger,
+		}
+
+		Convey("Without templates message is not rendered", func() {
+			message, ok := messageTemplate.Render(events, contact, trigger, false)
+			So(ok, ShouldBeFalse)
+			So(message, ShouldBeEmpty)
+		})
+
+		Convey("Sender template is used if contact has no template", func() {
+			messageTemplate.Template = "{{ .State }} {{ .Trigger.Name }} {{ .Trigger.URI }}" +
+				"{{ range .Events }} {{ .Time }} {{ .Metric }}={{ .ValuesString }}{{ end }}"
+
+			message, ok := messageTemplate.Render(events, contact, trigger, false)
+			So(ok, ShouldBeTrue)
+			So(message, ShouldResemble, "ERROR <Disk> https://moira.example.com/trigger/trigger-id 08:44 (GMT+00:00) server.hdd.used_percent=97")
+		})
+
+		Convey("Contact template overrides sender template", func() {
+			messageTemplate.Template = "{{ .Trigger.Name }}"
+			contact := contact
+			contact.MessageTemplate = "{{ .Contact.Value }} {{ .Throttled }}"
+
+			message, ok := messageTemplate.Render(events, contact, trigger, true)
+			So(ok, ShouldBeTrue)
+			So(message, ShouldResemble, "#alerts true")
+		})
+
+		Convey("Data is escaped in html templates", func() {
+			messageTemplate.Template = "<b>{{ .Trigger.Name }}</b>"
+			messageTemplate.HTML = true
+
+			message, ok := messageTemplate.Render(events, contact, trigger, false)
+			So(ok, ShouldBeTrue)
+			So(message, ShouldResemble, "<b>&lt;Disk&gt;</b>")
+		})
+
+		Convey("Populated message is truncated to max chars", func() {
+			messageTemplate.Template = "{{ .Trigger.Name }} is in {{ .State }} state"
+			messageTemplate.MaxChars = 12
+
+			message, ok := messageTemplate.Render(events, contact, trigger, false)
+			So(ok, ShouldBeTrue)
+			So(message, ShouldResemble, "<Disk> is...")
+		})
+
+		Convey("Message is not rendered if template can't be populated", func() {
+			messageTemplate.Template = "{{ .Trigger.Unknown }}"
+
+			message, ok := messageTemplate.Render(events, contact, trigger, false)
+			So(ok, ShouldBeFalse)
+			So(message, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestTruncateMessage(t *testing.T) {
+	Convey("Test TruncateMessage", t, func() {
+		So(TruncateMessage("short", 10), ShouldResemble, "short")
+		So(TruncateMessage("ошибка диска", 8), ShouldResemble, "ошибк...")
+		So(TruncateMessage("message", 2), ShouldResemble, "me")
+		So(TruncateMessage("message", 0), ShouldResemble, "message")
+	})
+}
@@ -0,0 +1,162 @@
+package senders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// ThreadMode defines which notifications are posted to the same thread.
+type ThreadMode string
+
+const (
+	// ThreadModeNone disables threads, each notification is a new message.
+	ThreadModeNone ThreadMode = ""
+	// ThreadModeTrigger posts notifications of trigger to one thread while incident is open,
+	// incident is resolved when neither trigger nor any of its metrics is in problem state.
+	ThreadModeTrigger ThreadMode = "trigger"
+	// ThreadModeMetric posts notifications of each metric of trigger to its own thread,
+	// notifications with events of several metrics are posted to thread of trigger.
+	ThreadModeMetric ThreadMode = "metric"
+)
+
+const (
+	defaultThreadTTL = 24 * time.Hour
+	// threadLockTTL is short because the lock is extended while it is held, waiting senders retry every third of it.
+	threadLockTTL = 3 * time.Second
+	// threadLockWaitTimeout limits waiting for the lock held by another sender.
+	threadLockWaitTimeout = time.Minute
+	threadLockPrefix      = "moira-message-thread:"
+)
+
+// MessageThread is the root message of incident, follow-up notifications are posted as replies to it.
+type MessageThread struct {
+	ChannelID string `json:"channel_id"`
+	MessageID string `json:"message_id"`
+	// Message is the text of root message, it is used to show current state of incident in root message.
+	Message string `json:"message"`
+}
+
+// MessageThreads remembers root messages of open incidents in bot storage of database.
+type MessageThreads struct {
+	database  moira.Database
+	messenger string
+	mode      ThreadMode
+	ttl       time.Duration
+}
+
+// NewMessageThreads creates message threads storage of messenger, nil is returned if threads are disabled.
+// Thread is forgotten after ttl since the last notification, default ttl is 24h.
+func NewMessageThreads(dataBase moira.Database, messenger string, mode ThreadMode, ttl string) (*MessageThreads, error) {
+	switch mode {
+	case ThreadModeNone:
+		return nil, nil
+	case ThreadModeTrigger, ThreadModeMetric:
+	default:
+		return nil, fmt.Errorf("unknown thread mode '%s', use %s or %s", mode, ThreadModeTrigger, ThreadModeMetric)
+	}
+
+	threadTTL := defaultThreadTTL
+	if ttl != "" {
+		var err error
+		if threadTTL, err = time.ParseDuration(ttl); err != nil || threadTTL <= 0 {
+			return nil, fmt.Errorf("invalid thread ttl '%s'", ttl)
+		}
+	}
+
+	return &MessageThreads{
+		database:  dataBase,
+		messenger: messenger,
+		mode:      mode,
+		ttl:       threadTTL,
+	}, nil
+}
+
+// GetKey returns key of thread the notification belongs to.
+func (threads *MessageThreads) GetKey(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData) string {
+	key := contact.ID + ":" + trigger.ID
+	if metric, ok := threads.getMetric(events); ok {
+		return key + ":" + metric
+	}
+	return key
+}
+
+// GetIncidentState returns current state of incident the notification belongs to, incident is resolved when it is OK.
+// Thread of metric follows the state of metric from events. Thread of trigger follows the most critical state
+// of trigger and its metrics by the last check, so it is not resolved while any metric is in problem state.
+func (threads *MessageThreads) GetIncidentState(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) (moira.State, error) {
+	if _, ok := threads.getMetric(events); ok {
+		return events.GetCurrentState(throttled), nil
+	}
+	checkData, err := threads.database.GetTriggerLastCheck(trigger.ID)
+	if err != nil {
+		if errors.Is(err, database.ErrNil) {
+			return events.GetCurrentState(throttled), nil
+		}
+		return "", fmt.Errorf("failed to get trigger last check: %w", err)
+	}
+	return checkData.GetWorstState(), nil
+}
+
+// getMetric returns metric of thread in metric mode if all events of notification belong to one metric.
+func (threads *MessageThreads) getMetric(events moira.NotificationEvents) (string, bool) {
+	if threads.mode != ThreadModeMetric || len(events) == 0 {
+		return "", false
+	}
+	metric := events[0].Metric
+	for _, event := range events[1:] {
+		if event.Metric != metric {
+			return "", false
+		}
+	}
+	return metric, true
+}
+
+// Lock serializes notifications of the thread between senders, so concurrent notifications of one incident
+// do not start two threads. Returned function releases the lock after thread is saved or closed.
+func (threads *MessageThreads) Lock(key string) (func(), error) {
+	lock := threads.database.NewLock(threadLockPrefix+threads.messenger+":"+key, threadLockTTL)
+	stop := make(chan struct{})
+	timer := time.AfterFunc(threadLockWaitTimeout, func() { close(stop) })
+	defer timer.Stop()
+
+	if _, err := lock.Acquire(stop); err != nil {
+		return nil, fmt.Errorf("failed to lock message thread: %w", err)
+	}
+	return lock.Release, nil
+}
+
+// Get returns root message of open incident, nil is returned if there is no open incident.
+func (threads *MessageThreads) Get(key string) (*MessageThread, error) {
+	value, err := threads.database.GetMessageThread(threads.messenger, key)
+	if err != nil {
+		if errors.Is(err, database.ErrNil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var thread MessageThread
+	if err = json.Unmarshal([]byte(value), &thread); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message thread: %w", err)
+	}
+	return &thread, nil
+}
+
+// Save remembers root message of incident until ttl passes.
+func (threads *MessageThreads) Save(key string, thread MessageThread) error {
+	value, err := json.Marshal(thread)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message thread: %w", err)
+	}
+	return threads.database.SetMessageThread(threads.messenger, key, string(value), threads.ttl)
+}
+
+// Close forgets root message of resolved incident, so the next notification starts a new thread.
+func (threads *MessageThreads) Close(key string) error {
+	return threads.database.RemoveMessageThread(threads.messenger, key)
+}
@@ -0,0 +1,176 @@
+package senders
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewMessageThreads(t *testing.T) {
+	Convey("Create message threads", t, func() {
+		Convey("Threads are disabled by default", func() {
+			threads, err := NewMessageThreads(nil, "slack", ThreadModeNone, "")
+			So(err, ShouldBeNil)
+			So(threads, ShouldBeNil)
+		})
+
+		Convey("Unknown mode", func() {
+			threads, err := NewMessageThreads(nil, "slack", "incident", "")
+			So(err, ShouldNotBeNil)
+			So(threads, ShouldBeNil)
+		})
+
+		Convey("Invalid ttl", func() {
+			_, err := NewMessageThreads(nil, "slack", ThreadModeTrigger, "day")
+			So(err, ShouldNotBeNil)
+			_, err = NewMessageThreads(nil, "slack", ThreadModeTrigger, "-1h")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Default ttl", func() {
+			threads, err := NewMessageThreads(nil, "slack", ThreadModeTrigger, "")
+			So(err, ShouldBeNil)
+			So(threads.ttl, ShouldEqual, defaultThreadTTL)
+		})
+	})
+}
+
+func TestMessageThreadsGetKey(t *testing.T) {
+	contact := moira.ContactData{ID: "contactID"}
+	trigger := moira.TriggerData{ID: "triggerID"}
+	oneMetric := moira.NotificationEvents{{Metric: "a"}, {Metric: "a"}}
+	severalMetrics := moira.NotificationEvents{{Metric: "a"}, {Metric: "b"}}
+
+	Convey("Get thread key", t, func() {
+		Convey("Trigger mode", func() {
+			threads, _ := NewMessageThreads(nil, "slack", ThreadModeTrigger, "")
+			So(threads.GetKey(oneMetric, contact, trigger), ShouldEqual, "contactID:triggerID")
+		})
+
+		Convey("Metric mode", func() {
+			threads, _ := NewMessageThreads(nil, "slack", ThreadModeMetric, "")
+			So(threads.GetKey(oneMetric, contact, trigger), ShouldEqual, "contactID:triggerID:a")
+			So(threads.GetKey(severalMetrics, contact, trigger), ShouldEqual, "contactID:triggerID")
+		})
+	})
+}
+
+func TestMessageThreadsGetIncidentState(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	trigger := moira.TriggerData{ID: "triggerID"}
+	recovered := moira.NotificationEvents{{Metric: "a", State: moira.StateOK, OldState: moira.StateERROR}}
+	lastCheck := moira.CheckData{State: moira.StateOK, Metrics: map[string]moira.MetricState{
+		"a": {State: moira.StateOK},
+		"b": {State: moira.StateWARN},
+	}}
+
+	Convey("Get incident state", t, func() {
+		Convey("Thread of trigger is open while any metric is in problem state", func() {
+			threads, _ := NewMessageThreads(dataBase, "slack", ThreadModeTrigger, "")
+			dataBase.EXPECT().GetTriggerLastCheck("triggerID").Return(lastCheck, nil)
+			state, err := threads.GetIncidentState(recovered, trigger, false)
+			So(err, ShouldBeNil)
+			So(state, ShouldEqual, moira.StateWARN)
+		})
+
+		Convey("Thread of trigger follows events if trigger has no last check", func() {
+			threads, _ := NewMessageThreads(dataBase, "slack", ThreadModeTrigger, "")
+			dataBase.EXPECT().GetTriggerLastCheck("triggerID").Return(moira.CheckData{}, database.ErrNil)
+			state, err := threads.GetIncidentState(recovered, trigger, false)
+			So(err, ShouldBeNil)
+			So(state, ShouldEqual, moira.StateOK)
+		})
+
+		Convey("Database error is returned", func() {
+			threads, _ := NewMessageThreads(dataBase, "slack", ThreadModeTrigger, "")
+			dataBase.EXPECT().GetTriggerLastCheck("triggerID").Return(moira.CheckData{}, errors.New("connection refused"))
+			_, err := threads.GetIncidentState(recovered, trigger, false)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Thread of metric follows state of metric", func() {
+			threads, _ := NewMessageThreads(dataBase, "slack", ThreadModeMetric, "")
+			state, err := threads.GetIncidentState(recovered, trigger, false)
+			So(err, ShouldBeNil)
+			So(state, ShouldEqual, moira.StateOK)
+		})
+	})
+}
+
+func TestMessageThreadsStoring(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	threads, _ := NewMessageThreads(dataBase, "slack", ThreadModeTrigger, "1h")
+	thread := MessageThread{ChannelID: "channel", MessageID: "message", Message: "text"}
+	const storedThread = `{"channel_id":"channel","message_id":"message","message":"text"}`
+
+	Convey("Store message threads", t, func() {
+		Convey("Save thread", func() {
+			dataBase.EXPECT().SetMessageThread("slack", "key", storedThread, time.Hour).Return(nil)
+			So(threads.Save("key", thread), ShouldBeNil)
+		})
+
+		Convey("Get open thread", func() {
+			dataBase.EXPECT().GetMessageThread("slack", "key").Return(storedThread, nil)
+			actual, err := threads.Get("key")
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, &thread)
+		})
+
+		Convey("Get missing thread", func() {
+			dataBase.EXPECT().GetMessageThread("slack", "key").Return("", database.ErrNil)
+			actual, err := threads.Get("key")
+			So(err, ShouldBeNil)
+			So(actual, ShouldBeNil)
+		})
+
+		Convey("Get thread with database error", func() {
+			dataBase.EXPECT().GetMessageThread("slack", "key").Return("", errors.New("connection refused"))
+			actual, err := threads.Get("key")
+			So(err, ShouldNotBeNil)
+			So(actual, ShouldBeNil)
+		})
+
+		Convey("Close thread", func() {
+			dataBase.EXPECT().RemoveMessageThread("slack", "key").Return(nil)
+			So(threads.Close("key"), ShouldBeNil)
+		})
+	})
+}
+
+func TestMessageThreadsLock(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	lock := mock_moira_alert.NewMockLock(mockCtrl)
+	threads, _ := NewMessageThreads(dataBase, "slack", ThreadModeTrigger, "1h")
+
+	Convey("Lock message thread", t, func() {
+		Convey("Lock is released by returned function", func() {
+			dataBase.EXPECT().NewLock("moira-message-thread:slack:key", threadLockTTL).Return(lock)
+			lock.EXPECT().Acquire(gomock.Any()).Return(nil, nil)
+			lock.EXPECT().Release()
+
+			release, err := threads.Lock("key")
+			So(err, ShouldBeNil)
+			release()
+		})
+
+		Convey("Lock is not acquired", func() {
+			dataBase.EXPECT().NewLock("moira-message-thread:slack:key", threadLockTTL).Return(lock)
+			lock.EXPECT().Acquire(gomock.Any()).Return(nil, database.ErrLockAcquireInterrupted)
+
+			_, err := threads.Lock("key")
+			So(errors.Is(err, database.ErrLockAcquireInterrupted), ShouldBeTrue)
+		})
+	})
+}
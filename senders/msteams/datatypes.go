@@ -113,6 +113,7 @@ type MessageCard struct {
 	Summary         string    `json:"summary"`
 	ThemeColor      string    `json:"themeColor"`
 	Title           string    `json:"title"`
-	Sections        []Section `json:"sections"`
+	Text            string    `json:"text,omitempty"`
+	Sections        []Section `json:"sections,omitempty"`
 	PotentialAction []Action  `json:"potentialAction,omitempty"`
 }
@@ -13,6 +13,7 @@ import (
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
 	"github.com/russross/blackfriday/v2"
 )
 
@@ -43,19 +44,33 @@ var headers = map[string]string{
 type config struct {
 	FrontURI  string `mapstructure:"front_uri"`
 	MaxEvents int    `mapstructure:"max_events"`
+	// MessageTemplate is a Go template of card text, it is used for contacts without their own template.
+	MessageTemplate string `mapstructure:"message_template"`
 }
 
 // Sender implements moira sender interface via MS Teams.
 type Sender struct {
-	frontURI  string
 	maxEvents int
-	logger    moira.Logger
-	location  *time.Location
-	client    *http.Client
+	// messageBuilder holds settings of messages shared with chat senders, card sections are built from events as facts.
+	messageBuilder senders.ChatMessageBuilder
+	logger         moira.Logger
+	client         *http.Client
 }
 
 // Init initialises settings required for full functionality.
 func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	if err := sender.InitMessageBuilder(senderSettings, logger, location, dateTimeFormat); err != nil {
+		return err
+	}
+
+	sender.client = &http.Client{
+		Timeout: time.Duration(30) * time.Second, //nolint
+	}
+	return nil
+}
+
+// InitMessageBuilder reads settings required to build message cards.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
 	var cfg config
 	err := mapstructure.Decode(senderSettings, &cfg)
 	if err != nil {
@@ -63,15 +78,34 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	}
 
 	sender.logger = logger
-	sender.location = location
-	sender.frontURI = cfg.FrontURI
 	sender.maxEvents = cfg.MaxEvents
-	sender.client = &http.Client{
-		Timeout: time.Duration(30) * time.Second, //nolint
+	sender.messageBuilder = senders.ChatMessageBuilder{
+		FrontURI: cfg.FrontURI,
+		Location: location,
+		Template: senders.MessageTemplate{
+			Template: cfg.MessageTemplate,
+			FrontURI: cfg.FrontURI,
+			Location: location,
+			Logger:   logger,
+		},
 	}
 	return nil
 }
 
+// BuildMessage builds message card without sending it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	messageCard := sender.buildContactMessage(events, contact, trigger, throttled)
+	body, err := json.Marshal(messageCard)
+	if err != nil {
+		return moira.NotificationPayload{}, err
+	}
+	return moira.NotificationPayload{
+		ContentType: "application/json",
+		Title:       messageCard.Title,
+		Body:        string(body),
+	}, nil
+}
+
 // SendEvents implements Sender interface Send.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
 	err := sender.isValidWebhookURL(contact.Value)
@@ -149,8 +183,18 @@ func (sender *Sender) buildMessage(events moira.NotificationEvents, trigger moir
 	}
 }
 
-func (sender *Sender) buildRequest(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) (*http.Request, error) {
+// buildContactMessage builds message card, card text is populated from message template of contact or sender if it is set.
+func (sender *Sender) buildContactMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) MessageCard {
 	messageCard := sender.buildMessage(events, trigger, throttled)
+	if text, ok := sender.messageBuilder.Template.Render(events, contact, trigger, throttled); ok {
+		messageCard.Text = text
+		messageCard.Sections = nil
+	}
+	return messageCard
+}
+
+func (sender *Sender) buildRequest(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) (*http.Request, error) {
+	messageCard := sender.buildContactMessage(events, contact, trigger, throttled)
 	requestURL := contact.Value
 	requestBody, err := json.Marshal(messageCard)
 	if err != nil {
@@ -186,7 +230,7 @@ func (sender *Sender) buildTitleAndURI(events moira.NotificationEvents, trigger
 	if tags != "" {
 		title = fmt.Sprintf("%s %s", title, tags)
 	}
-	triggerURI := trigger.GetTriggerURI(sender.frontURI)
+	triggerURI := trigger.GetTriggerURI(sender.messageBuilder.FrontURI)
 
 	return title, triggerURI
 }
@@ -203,7 +247,7 @@ func (sender *Sender) buildEventsFacts(events moira.NotificationEvents, maxEvent
 			line += fmt.Sprintf(". %s", moira.UseString(event.Message))
 		}
 		facts = append(facts, Fact{
-			Name:  event.FormatTimestamp(sender.location, moira.DefaultTimeFormat),
+			Name:  event.FormatTimestamp(sender.messageBuilder.Location, moira.DefaultTimeFormat),
 			Value: quotes + line + quotes,
 		})
 
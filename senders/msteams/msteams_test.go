@@ -1,12 +1,14 @@
 package msteams
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/moira-alert/moira"
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	"github.com/moira-alert/moira/senders"
 	. "github.com/smartystreets/goconvey/convey"
 	"gopkg.in/h2non/gock.v1"
 )
@@ -93,7 +95,7 @@ some other text _italic text_`,
 
 func TestValidWebhook(t *testing.T) {
 	location, _ := time.LoadLocation("UTC")
-	sender := Sender{location: location, frontURI: "http://moira.url"}
+	sender := Sender{messageBuilder: senders.ChatMessageBuilder{Location: location, FrontURI: "http://moira.url"}}
 	Convey("MS Teams Webhook validity", t, func() {
 		Convey("https://outlook.office.com/webhook/foo is valid", func() {
 			err := sender.isValidWebhookURL("https://outlook.office.com/webhook/foo")
@@ -108,7 +110,7 @@ func TestValidWebhook(t *testing.T) {
 
 func TestBuildMessage(t *testing.T) {
 	location, _ := time.LoadLocation("UTC")
-	sender := Sender{location: location, maxEvents: -1, frontURI: "http://moira.url"}
+	sender := Sender{maxEvents: -1, messageBuilder: senders.ChatMessageBuilder{Location: location, FrontURI: "http://moira.url"}}
 
 	Convey("Build Moira Message tests", t, func() {
 		event := moira.NotificationEvent{
@@ -439,3 +441,50 @@ some other text _italic text_`,
 		})
 	})
 }
+
+func TestBuildRequestWithMessageTemplate(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test", true)
+	location, _ := time.LoadLocation("UTC")
+	sender := Sender{}
+	_ = sender.Init(map[string]interface{}{
+		"max_events":       -1,
+		"front_uri":        "http://moira.url",
+		"message_template": "**{{ .Trigger.Name }}** {{ range .Events }}{{ .Metric }} is {{ .State }}{{ end }}",
+	}, logger, location, "")
+
+	event := moira.NotificationEvent{
+		Values:    map[string]float64{"t1": 123},
+		Timestamp: 150000000,
+		Metric:    "Metric",
+		OldState:  moira.StateOK,
+		State:     moira.StateNODATA,
+	}
+	trigger := moira.TriggerData{ID: "TriggerID", Name: "Name"}
+	contact := moira.ContactData{Value: "https://outlook.office.com/webhook/foo"}
+
+	Convey("Message template replaces card sections with text", t, func() {
+		request, err := sender.buildRequest([]moira.NotificationEvent{event}, contact, trigger, false)
+		So(err, ShouldBeNil)
+
+		card := MessageCard{}
+		err = json.NewDecoder(request.Body).Decode(&card)
+		So(err, ShouldBeNil)
+		So(card.Title, ShouldResemble, "NODATA Name")
+		So(card.Text, ShouldResemble, "**Name** Metric is NODATA")
+		So(card.Sections, ShouldBeEmpty)
+		So(card.PotentialAction, ShouldHaveLength, 1)
+	})
+
+	Convey("Contact message template overrides sender one", t, func() {
+		contact := contact
+		contact.MessageTemplate = "{{ .Contact.Value }}"
+
+		request, err := sender.buildRequest([]moira.NotificationEvent{event}, contact, trigger, false)
+		So(err, ShouldBeNil)
+
+		card := MessageCard{}
+		err = json.NewDecoder(request.Body).Decode(&card)
+		So(err, ShouldBeNil)
+		So(card.Text, ShouldResemble, contact.Value)
+	})
+}
@@ -53,8 +53,23 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 		return fmt.Errorf("error while creating opsgenie client: %w", err)
 	}
 
+	sender.initMessageBuilder(cfg, logger, location)
+	return nil
+}
+
+// InitMessageBuilder reads settings required to build opsgenie alerts without creating the client.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to opsgenie config: %w", err)
+	}
+	sender.initMessageBuilder(cfg, logger, location)
+	return nil
+}
+
+func (sender *Sender) initMessageBuilder(cfg config, logger moira.Logger, location *time.Location) {
 	sender.frontURI = cfg.FrontURI
 	sender.logger = logger
 	sender.location = location
-	return nil
 }
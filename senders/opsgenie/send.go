@@ -3,6 +3,7 @@ package opsgenie
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -27,6 +28,20 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 	return nil
 }
 
+// BuildMessage builds opsgenie alert request without sending it, plots are not uploaded to image store.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	createAlertRequest := sender.makeCreateAlertRequest(events, contact, trigger, nil, throttled)
+	body, err := json.Marshal(createAlertRequest)
+	if err != nil {
+		return moira.NotificationPayload{}, fmt.Errorf("failed to marshal opsgenie alert request: %w", err)
+	}
+	return moira.NotificationPayload{
+		ContentType: "application/json",
+		Title:       createAlertRequest.Message,
+		Body:        string(body),
+	}, nil
+}
+
 func (sender *Sender) makeCreateAlertRequest(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) *alert.CreateAlertRequest {
 	createAlertRequest := &alert.CreateAlertRequest{
 		Message:     sender.buildTitle(events, trigger, throttled),
@@ -41,3 +41,8 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	sender.location = location
 	return nil
 }
+
+// InitMessageBuilder reads settings required to build pagerduty events.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	return sender.Init(senderSettings, logger, location, dateTimeFormat)
+}
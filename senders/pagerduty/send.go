@@ -3,6 +3,7 @@ package pagerduty
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -25,6 +26,20 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 	return nil
 }
 
+// BuildMessage builds pagerduty event without sending it, plots are not uploaded to image store.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	event := sender.buildEvent(events, contact, trigger, nil, throttled)
+	body, err := json.Marshal(event)
+	if err != nil {
+		return moira.NotificationPayload{}, fmt.Errorf("failed to marshal pagerduty event: %w", err)
+	}
+	return moira.NotificationPayload{
+		ContentType: "application/json",
+		Title:       event.Payload.Summary,
+		Body:        string(body),
+	}, nil
+}
+
 func (sender *Sender) buildEvent(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) pagerduty.V2Event {
 	summary := sender.buildSummary(events, trigger, throttled)
 	details := make(map[string]interface{})
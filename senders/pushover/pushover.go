@@ -46,10 +46,35 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 		return fmt.Errorf("can not read pushover api_token from config")
 	}
 	sender.client = pushover_client.New(sender.apiToken)
+	sender.initMessageBuilder(cfg, logger, location)
+	return nil
+}
+
+// InitMessageBuilder reads settings required to build messages without pushover api token.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to pushover config: %w", err)
+	}
+	sender.initMessageBuilder(cfg, logger, location)
+	return nil
+}
+
+func (sender *Sender) initMessageBuilder(cfg config, logger moira.Logger, location *time.Location) {
 	sender.logger = logger
 	sender.frontURI = cfg.FrontURI
 	sender.location = location
-	return nil
+}
+
+// BuildMessage builds pushover message without sending it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	pushoverMessage := sender.makePushoverMessage(events, trigger, plots, throttled)
+	return moira.NotificationPayload{
+		ContentType: "text/plain",
+		Title:       pushoverMessage.Title,
+		Body:        pushoverMessage.Message,
+	}, nil
 }
 
 // SendEvents implements pushover build and send message functionality.
@@ -51,6 +51,33 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	return nil
 }
 
+// InitMessageBuilder reads settings required to build script input, script file existence is not checked.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to script config: %w", err)
+	}
+
+	sender.exec = cfg.Exec
+	sender.logger = logger
+
+	return nil
+}
+
+// BuildMessage builds command line and JSON passed to script stdin without executing it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	scriptJSON, err := buildScriptBody(events, contact, trigger, throttled)
+	if err != nil {
+		return moira.NotificationPayload{}, err
+	}
+	return moira.NotificationPayload{
+		ContentType: "application/json",
+		Title:       buildExecString(sender.exec, trigger, contact),
+		Body:        string(scriptJSON),
+	}, nil
+}
+
 // SendEvents implements Sender interface Send.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
 	scriptFile, args, scriptBody, err := sender.buildCommandData(events, contact, trigger, throttled)
@@ -88,6 +115,11 @@ func (sender *Sender) buildCommandData(events moira.NotificationEvents, contact
 	if err != nil {
 		return scriptFile, args[1:], []byte{}, err
 	}
+	scriptJSON, err := buildScriptBody(events, contact, trigger, throttled)
+	return scriptFile, args[1:], scriptJSON, err
+}
+
+func buildScriptBody(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) ([]byte, error) {
 	scriptMessage := &scriptNotification{
 		Events:    events,
 		Trigger:   trigger,
@@ -96,9 +128,9 @@ func (sender *Sender) buildCommandData(events moira.NotificationEvents, contact
 	}
 	scriptJSON, err := json.MarshalIndent(scriptMessage, "", "\t")
 	if err != nil {
-		return scriptFile, args[1:], scriptJSON, fmt.Errorf("failed marshal json: %s", err.Error())
+		return scriptJSON, fmt.Errorf("failed marshal json: %s", err.Error())
 	}
-	return scriptFile, args[1:], scriptJSON, nil
+	return scriptJSON, nil
 }
 
 func parseExec(execString string) (scriptFile string, args []string, err error) {
@@ -17,14 +17,23 @@ import (
 
 const (
 	messageMaxCharacters = 4000
+	messenger            = "slack"
 
 	// see errors https://api.slack.com/methods/chat.postMessage
 	ErrorTextChannelArchived = "is_archived"
 	ErrorTextChannelNotFound = "channel_not_found"
 	ErrorTextNotInChannel    = "not_in_channel"
-	quotes                   = "```"
 )
 
+// slackMarkup is the mrkdwn formatting of slack messages, markdown of trigger description is converted to it.
+var slackMarkup = senders.ChatMarkup{
+	Bold:        func(text string) string { return "*" + text + "*" },
+	Italic:      func(text string) string { return "_" + text + "_" },
+	Link:        func(text, url string) string { return fmt.Sprintf("<%s|%s>", url, text) },
+	CodeBlock:   "```",
+	Description: func(desc string) string { return string(slackdown.Run([]byte(desc))) },
+}
+
 // Structure that represents the Slack configuration in the YAML file.
 type config struct {
 	APIToken     string            `mapstructure:"api_token"`
@@ -32,16 +41,23 @@ type config struct {
 	FrontURI     string            `mapstructure:"front_uri"`
 	DefaultEmoji string            `mapstructure:"default_emoji"`
 	EmojiMap     map[string]string `mapstructure:"emoji_map"`
+	// MessageTemplate is a Go template of messages, it is used for contacts without their own template.
+	MessageTemplate string `mapstructure:"message_template"`
+	// Threads enables posting of follow-up notifications as replies to the first message of incident, trigger or metric.
+	Threads senders.ThreadMode `mapstructure:"threads"`
+	// ThreadTTL is the period thread is kept open since the last notification. Default is 24h.
+	ThreadTTL string `mapstructure:"thread_ttl"`
 }
 
 // Sender implements moira sender interface via slack.
 type Sender struct {
-	frontURI      string
-	useEmoji      bool
-	emojiProvider emoji_provider.StateEmojiGetter
-	logger        moira.Logger
-	location      *time.Location
-	client        *slack_client.Client
+	DataBase       moira.Database
+	threads        *senders.MessageThreads
+	useEmoji       bool
+	emojiProvider  emoji_provider.StateEmojiGetter
+	messageBuilder senders.ChatMessageBuilder
+	logger         moira.Logger
+	client         *slack_client.Client
 }
 
 // Init read yaml config.
@@ -55,6 +71,27 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	if cfg.APIToken == "" {
 		return fmt.Errorf("can not read slack api_token from config")
 	}
+	if err = sender.initMessageBuilder(cfg, logger, location); err != nil {
+		return err
+	}
+	if sender.threads, err = senders.NewMessageThreads(sender.DataBase, messenger, cfg.Threads, cfg.ThreadTTL); err != nil {
+		return fmt.Errorf("failed to configure slack threads: %w", err)
+	}
+	sender.client = slack_client.New(cfg.APIToken)
+	return nil
+}
+
+// InitMessageBuilder reads settings required to build messages without connecting to slack.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to slack config: %w", err)
+	}
+	return sender.initMessageBuilder(cfg, logger, location)
+}
+
+func (sender *Sender) initMessageBuilder(cfg config, logger moira.Logger, location *time.Location) error {
 	emojiProvider, err := emoji_provider.NewEmojiProvider(cfg.DefaultEmoji, cfg.EmojiMap)
 	if err != nil {
 		return fmt.Errorf("cannot initialize mattermost sender, err: %w", err)
@@ -62,24 +99,60 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	sender.emojiProvider = emojiProvider
 	sender.useEmoji = cfg.UseEmoji
 	sender.logger = logger
-	sender.frontURI = cfg.FrontURI
-	sender.location = location
-	sender.client = slack_client.New(cfg.APIToken)
+	sender.messageBuilder = senders.ChatMessageBuilder{
+		Markup:   slackMarkup,
+		FrontURI: cfg.FrontURI,
+		Location: location,
+		MaxChars: messageMaxCharacters,
+		Template: senders.MessageTemplate{
+			Template: cfg.MessageTemplate,
+			FrontURI: cfg.FrontURI,
+			Location: location,
+			MaxChars: messageMaxCharacters,
+			Logger:   logger,
+		},
+	}
 	return nil
 }
 
+// BuildMessage builds slack message without sending it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	return moira.NotificationPayload{
+		ContentType: "text/markdown",
+		Body:        sender.messageBuilder.BuildContactMessage(events, contact, trigger, throttled),
+	}, nil
+}
+
 // SendEvents implements Sender interface Send.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
-	message := sender.buildMessage(events, trigger, throttled)
+	message := sender.messageBuilder.BuildContactMessage(events, contact, trigger, throttled)
 	useDirectMessaging := useDirectMessaging(contact.Value)
 
 	state := events.GetCurrentState(throttled)
 	emoji := sender.emojiProvider.GetStateEmoji(state)
 
-	channelID, threadTimestamp, err := sender.sendMessage(message, contact.Value, trigger.ID, useDirectMessaging, emoji)
+	if sender.threads != nil {
+		// Notifications of trigger are sent by parallel senders, thread is locked so only one of them starts it
+		unlock, err := sender.threads.Lock(sender.threads.GetKey(events, contact, trigger))
+		if err != nil {
+			return fmt.Errorf("failed to lock slack thread of trigger %s: %w", trigger.ID, err)
+		}
+		defer unlock()
+	}
+
+	threadKey, thread := sender.getThread(events, contact, trigger)
+	channel, threadTimestamp := contact.Value, ""
+	if thread != nil {
+		channel, threadTimestamp = thread.ChannelID, thread.MessageID
+	}
+
+	channelID, messageTimestamp, err := sender.sendMessage(message, channel, trigger.ID, useDirectMessaging, emoji, threadTimestamp)
 	if err != nil {
 		return err
 	}
+	if threadTimestamp == "" {
+		threadTimestamp = messageTimestamp
+	}
 
 	if channelID != "" && len(plots) > 0 {
 		err = sender.sendPlots(plots, channelID, threadTimestamp, trigger.ID)
@@ -92,113 +165,81 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 		}
 	}
 
-	return nil
-}
-
-func (sender *Sender) buildMessage(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
-	var message strings.Builder
-
-	title := sender.buildTitle(events, trigger, throttled)
-	titleLen := len([]rune(title))
-
-	desc := sender.buildDescription(trigger)
-	descLen := len([]rune(desc))
-
-	eventsString := sender.buildEventsString(events, -1, throttled)
-	eventsStringLen := len([]rune(eventsString))
-
-	charsLeftAfterTitle := messageMaxCharacters - titleLen
-
-	descNewLen, eventsNewLen := senders.CalculateMessagePartsLength(charsLeftAfterTitle, descLen, eventsStringLen)
-
-	if descLen != descNewLen {
-		desc = desc[:descNewLen] + "...\n"
-	}
-	if eventsNewLen != eventsStringLen {
-		eventsString = sender.buildEventsString(events, eventsNewLen, throttled)
+	newThread := senders.MessageThread{ChannelID: channelID, MessageID: messageTimestamp, Message: message}
+	if err = sender.updateThread(threadKey, thread, newThread, events, trigger, throttled); err != nil {
+		sender.logger.Warning().
+			String("trigger_id", trigger.ID).
+			String("contact_value", contact.Value).
+			String("contact_type", contact.Type).
+			Error(err).
+			Msg("Failed to update slack thread")
 	}
 
-	message.WriteString(title)
-	message.WriteString(desc)
-	message.WriteString(eventsString)
-	return message.String()
+	return nil
 }
 
-func (sender *Sender) buildDescription(trigger moira.TriggerData) string {
-	desc := trigger.Desc
-	if trigger.Desc != "" {
-		desc = string(slackdown.Run([]byte(desc)))
-		desc += "\n"
+// getThread returns key of thread the notification belongs to and root message of open incident if there is one.
+func (sender *Sender) getThread(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData) (string, *senders.MessageThread) {
+	if sender.threads == nil {
+		return "", nil
+	}
+	threadKey := sender.threads.GetKey(events, contact, trigger)
+	thread, err := sender.threads.Get(threadKey)
+	if err != nil {
+		sender.logger.Warning().
+			String("trigger_id", trigger.ID).
+			String("contact_value", contact.Value).
+			Error(err).
+			Msg("Failed to get slack thread, message is posted to channel")
 	}
-	return desc
+	return threadKey, thread
 }
 
-func (sender *Sender) buildTitle(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
-	state := events.GetCurrentState(throttled)
-	title := fmt.Sprintf("*%s*", state)
-	triggerURI := trigger.GetTriggerURI(sender.frontURI)
-
-	if triggerURI != "" {
-		title += fmt.Sprintf(" <%s|%s>", triggerURI, trigger.Name)
-	} else if trigger.Name != "" {
-		title += " " + trigger.Name
+// updateThread remembers root message of new incident, shows current state in root message of open incident
+// and closes incident when it is resolved, see senders.MessageThreads.GetIncidentState.
+func (sender *Sender) updateThread(
+	threadKey string,
+	thread *senders.MessageThread,
+	newThread senders.MessageThread,
+	events moira.NotificationEvents,
+	trigger moira.TriggerData,
+	throttled bool,
+) error {
+	if sender.threads == nil {
+		return nil
 	}
-
-	tags := trigger.GetTags()
-	if tags != "" {
-		title += " " + tags
+	state, err := sender.threads.GetIncidentState(events, trigger, throttled)
+	if err != nil {
+		return err
 	}
-
-	title += "\n"
-	return title
-}
-
-// buildEventsString builds the string from moira events and limits it to charsForEvents
-// if n is negative buildEventsString does not limit the events string.
-func (sender *Sender) buildEventsString(events moira.NotificationEvents, charsForEvents int, throttled bool) string {
-	charsForThrottleMsg := 0
-	throttleMsg := "\nPlease, *fix your system or tune this trigger* to generate less events."
-	if throttled {
-		charsForThrottleMsg = len([]rune(throttleMsg))
-	}
-	charsLeftForEvents := charsForEvents - charsForThrottleMsg
-
-	var eventsString string
-	eventsString += quotes
-	var tailString string
-
-	eventsLenLimitReached := false
-	eventsPrinted := 0
-	for _, event := range events {
-		line := fmt.Sprintf("\n%s: %s = %s (%s to %s)", event.FormatTimestamp(sender.location, moira.DefaultTimeFormat), event.Metric, event.GetMetricsValues(moira.DefaultNotificationSettings), event.OldState, event.State)
-		if msg := event.CreateMessage(sender.location); len(msg) > 0 {
-			line += fmt.Sprintf(". %s", msg)
-		}
-
-		tailString = fmt.Sprintf("\n...and %d more events.", len(events)-eventsPrinted)
-		tailStringLen := len([]rune(quotes)) + len([]rune(tailString))
-		if !(charsForEvents < 0) && (len([]rune(eventsString))+len([]rune(line)) > charsLeftForEvents-tailStringLen) {
-			eventsLenLimitReached = true
-			break
+	if thread == nil {
+		if state == moira.StateOK {
+			return nil
 		}
-
-		eventsString += line
-		eventsPrinted++
+		return sender.threads.Save(threadKey, newThread)
 	}
-	eventsString += quotes
 
-	if eventsLenLimitReached {
-		eventsString += tailString
+	rootMessage := buildThreadRootMessage(thread.Message, state)
+	if _, _, _, err := sender.client.UpdateMessage(thread.ChannelID, thread.MessageID, slack_client.MsgOptionText(rootMessage, false)); err != nil {
+		return fmt.Errorf("failed to update root message: %w", err)
 	}
-
-	if throttled {
-		eventsString += throttleMsg
+	if state == moira.StateOK {
+		return sender.threads.Close(threadKey)
 	}
+	return sender.threads.Save(threadKey, *thread)
+}
 
-	return eventsString
+// buildThreadRootMessage shows current state of incident in its root message, title of resolved incident is struck through.
+func buildThreadRootMessage(message string, state moira.State) string {
+	if state != moira.StateOK {
+		return fmt.Sprintf("Current state: *%s*\n%s", state, message)
+	}
+	title, rest, _ := strings.Cut(message, "\n")
+	return fmt.Sprintf("Current state: *%s*\n~%s~\n%s", state, title, rest)
 }
 
-func (sender *Sender) sendMessage(message string, contact string, triggerID string, useDirectMessaging bool, emoji string) (string, string, error) {
+// sendMessage posts message to channel, message is posted as reply if thread timestamp is set.
+func (sender *Sender) sendMessage(message string, contact string, triggerID string, useDirectMessaging bool, emoji string, threadTimestamp string) (string, string, error) {
 	params := slack_client.PostMessageParameters{
 		Username:  "Moira",
 		AsUser:    useDirectMessaging,
@@ -210,22 +251,30 @@ func (sender *Sender) sendMessage(message string, contact string, triggerID stri
 		String("message", message).
 		Msg("Calling slack")
 
-	channelID, threadTimestamp, err := sender.client.PostMessage(contact, slack_client.MsgOptionText(message, false), slack_client.MsgOptionPostMessageParameters(params))
+	options := []slack_client.MsgOption{
+		slack_client.MsgOptionText(message, false),
+		slack_client.MsgOptionPostMessageParameters(params),
+	}
+	if threadTimestamp != "" {
+		options = append(options, slack_client.MsgOptionTS(threadTimestamp))
+	}
+
+	channelID, messageTimestamp, err := sender.client.PostMessage(contact, options...)
 	if err != nil {
 		errorText := err.Error()
 		if errorText == ErrorTextChannelArchived || errorText == ErrorTextNotInChannel ||
 			errorText == ErrorTextChannelNotFound {
-			return channelID, threadTimestamp, moira.NewSenderBrokenContactError(err)
+			return channelID, messageTimestamp, moira.NewSenderBrokenContactError(err)
 		}
-		return channelID, threadTimestamp, fmt.Errorf("failed to send %s event message to slack [%s]: %s",
+		return channelID, messageTimestamp, fmt.Errorf("failed to send %s event message to slack [%s]: %s",
 			triggerID, contact, errorText)
 	}
-	return channelID, threadTimestamp, nil
+	return channelID, messageTimestamp, nil
 }
 
 func (sender *Sender) sendPlots(plots [][]byte, channelID, threadTimestamp, triggerID string) error {
-	filename := fmt.Sprintf("%s.png", triggerID)
 	for _, plot := range plots {
+		filename := senders.PlotFileName(triggerID, plot)
 		reader := bytes.NewReader(plot)
 		uploadParameters := slack_client.UploadFileV2Parameters{
 			FileSize:        len(plot),
@@ -1,13 +1,22 @@
 package slack
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/moira-alert/moira/senders"
+	slack_client "github.com/slack-go/slack"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -65,7 +74,12 @@ func TestUseDirectMessaging(t *testing.T) {
 
 func TestBuildMessage(t *testing.T) {
 	location, _ := time.LoadLocation("UTC")
-	sender := Sender{location: location, frontURI: "http://moira.url"}
+	sender := Sender{messageBuilder: senders.ChatMessageBuilder{
+		Markup:   slackMarkup,
+		FrontURI: "http://moira.url",
+		Location: location,
+		MaxChars: messageMaxCharacters,
+	}}
 
 	Convey("Build Moira Message tests", t, func() {
 		event := moira.NotificationEvent{
@@ -95,108 +109,98 @@ some other text italic text
 `
 
 		Convey("Print moira message with one event", func() {
-			actual := sender.buildMessage([]moira.NotificationEvent{event}, trigger, false)
+			actual := sender.messageBuilder.BuildMessage([]moira.NotificationEvent{event}, trigger, false)
 			expected := "*NODATA* <http://moira.url/trigger/TriggerID|Name> [tag1][tag2]\n" + slackCompatibleMD +
-				"\n\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)```"
+				"\n\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n```"
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message with empty trigger", func() {
-			actual := sender.buildMessage([]moira.NotificationEvent{event}, moira.TriggerData{}, false)
-			expected := "*NODATA*\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)```"
+			actual := sender.messageBuilder.BuildMessage([]moira.NotificationEvent{event}, moira.TriggerData{}, false)
+			expected := "*NODATA*\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n```"
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message with one event and message", func() {
 			var interval int64 = 24
 			event.MessageEventInfo = &moira.EventInfo{Interval: &interval}
-			actual := sender.buildMessage([]moira.NotificationEvent{event}, trigger, false)
+			actual := sender.messageBuilder.BuildMessage([]moira.NotificationEvent{event}, trigger, false)
 			expected := "*NODATA* <http://moira.url/trigger/TriggerID|Name> [tag1][tag2]\n" + slackCompatibleMD +
-				"\n\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA). This metric has been in bad state for more than 24 hours - please, fix.```"
+				"\n\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA). This metric has been in bad state for more than 24 hours - please, fix.\n```"
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message with one event and throttled", func() {
-			actual := sender.buildMessage([]moira.NotificationEvent{event}, trigger, true)
+			actual := sender.messageBuilder.BuildMessage([]moira.NotificationEvent{event}, trigger, true)
 			expected := "*NODATA* <http://moira.url/trigger/TriggerID|Name> [tag1][tag2]\n" + slackCompatibleMD +
-				"\n\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)```\nPlease, *fix your system or tune this trigger* to generate less events."
+				"\n\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n```\nPlease, _fix your system or tune this trigger_ to generate less events."
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message with 6 events", func() {
-			actual := sender.buildMessage([]moira.NotificationEvent{event, event, event, event, event, event}, trigger, false)
+			actual := sender.messageBuilder.BuildMessage([]moira.NotificationEvent{event, event, event, event, event, event}, trigger, false)
 			expected := "*NODATA* <http://moira.url/trigger/TriggerID|Name> [tag1][tag2]\n" + slackCompatibleMD +
-				"\n\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)```"
+				"\n\n```\n" + strings.Repeat("02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n", 6) + "```"
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message with empty triggerID, but with trigger name", func() {
-			actual := sender.buildMessage([]moira.NotificationEvent{event}, moira.TriggerData{Name: "Name"}, false)
-			expected := "*NODATA* Name\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)```"
+			actual := sender.messageBuilder.BuildMessage([]moira.NotificationEvent{event}, moira.TriggerData{Name: "Name"}, false)
+			expected := "*NODATA* Name\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n```"
 			So(actual, ShouldResemble, expected)
 		})
 
-		eventLine := "\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)"
+		eventLine := "02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n"
 		oneEventLineLen := len([]rune(eventLine))
 		// Events list with chars less than half the message limit
 		var shortEvents moira.NotificationEvents
-		var shortEventsString string
 		for i := 0; i < (messageMaxCharacters/2-200)/oneEventLineLen; i++ {
 			shortEvents = append(shortEvents, event)
-			shortEventsString += eventLine
 		}
 		// Events list with chars greater than half the message limit
 		var longEvents moira.NotificationEvents
-		var longEventsString string
 		for i := 0; i < (messageMaxCharacters/2+200)/oneEventLineLen; i++ {
 			longEvents = append(longEvents, event)
-			longEventsString += eventLine
 		}
 		longDesc := strings.Repeat("a", messageMaxCharacters/2+100)
 
 		Convey("Print moira message with desc + events < msgLimit", func() {
-			actual := sender.buildMessage(shortEvents, moira.TriggerData{Desc: longDesc}, false)
-			expected := "*NODATA*\n" + longDesc + "\n```" + shortEventsString + "```"
+			actual := sender.messageBuilder.BuildMessage(shortEvents, moira.TriggerData{Desc: longDesc}, false)
+			expected := "*NODATA*\n" + longDesc + "\n```\n" + strings.Repeat(eventLine, len(shortEvents)) + "```"
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message desc > msgLimit/2", func() {
 			var events moira.NotificationEvents
-			var eventsString string
 			for i := 0; i < (messageMaxCharacters/2-10)/oneEventLineLen; i++ {
 				events = append(events, event)
-				eventsString += eventLine
 			}
-			actual := sender.buildMessage(events, moira.TriggerData{Desc: longDesc}, false)
-			expected := "*NODATA*\naaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa...\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)```"
+			actual := sender.messageBuilder.BuildMessage(events, moira.TriggerData{Desc: longDesc}, false)
+			expected := "*NODATA*\n" + strings.Repeat("a", 2000) + "...\n```\n" + strings.Repeat(eventLine, 42) + "```"
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message events string > msgLimit/2", func() {
 			desc := strings.Repeat("a", messageMaxCharacters/2-100)
-			actual := sender.buildMessage(longEvents, moira.TriggerData{Desc: desc}, false)
-			expected := "*NODATA*\naaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)```\n...and 3 more events."
+			actual := sender.messageBuilder.BuildMessage(longEvents, moira.TriggerData{Desc: desc}, false)
+			expected := "*NODATA*\n" + desc + "\n```\n" + strings.Repeat(eventLine, 43) + "```\n...and 3 more events."
 			So(actual, ShouldResemble, expected)
 		})
 
 		Convey("Print moira message with both desc and events > msgLimit/2", func() {
-			actual := sender.buildMessage(longEvents, moira.TriggerData{Desc: longDesc}, false)
-			expected := "*NODATA*\naaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa...\n```\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)\n02:40 (GMT+00:00): Metric = 123 (OK to NODATA)```\n...and 5 more events."
+			actual := sender.messageBuilder.BuildMessage(longEvents, moira.TriggerData{Desc: longDesc}, false)
+			expected := "*NODATA*\n" + strings.Repeat("a", 1985) + "...\n```\n" + strings.Repeat(eventLine, 41) + "```\n...and 5 more events."
 			So(actual, ShouldResemble, expected)
 		})
 	})
 }
 
-func TestBuildDescription(t *testing.T) {
-	location, _ := time.LoadLocation("UTC")
-	sender := Sender{location: location, frontURI: "http://moira.url"}
+func TestSlackMarkupDescription(t *testing.T) {
 	Convey("Build desc tests", t, func() {
-		trigger := moira.TriggerData{
-			Desc: `# header1
+		desc := `# header1
 some text **bold text**
 ## header 2
-some other text _italic text_`,
-		}
+some other text _italic text_`
 
 		slackCompatibleMD := `*header1*
 some text *bold text*
@@ -205,35 +209,124 @@ some text *bold text*
 some other text italic text
 `
 
-		Convey("Build empty desc", func() {
-			actual := sender.buildDescription(moira.TriggerData{Desc: ""})
-			expected := ""
-			So(actual, ShouldResemble, expected)
-		})
-
 		Convey("Build desc with headers and bold", func() {
-			actual := sender.buildDescription(trigger)
-			expected := slackCompatibleMD + "\n\n"
+			actual := slackMarkup.Description(desc)
+			expected := slackCompatibleMD + "\n"
 			So(actual, ShouldResemble, expected)
 		})
 	})
 
 	Convey("Build desc with lists", t, func() {
-		trigger := moira.TriggerData{
-			Desc: `
+		desc := `
 1. a
-`,
-		}
+`
 
 		expected := ` 1. a
 
-
 `
 
-		Convey("Expect buildDescription not to panic", func() {
-			actual := sender.buildDescription(trigger)
+		Convey("Expect description conversion not to panic", func() {
+			actual := slackMarkup.Description(desc)
 
 			So(actual, ShouldEqual, expected)
 		})
 	})
 }
+
+func TestSendEventsToThread(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+
+	Convey("Send events to thread", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+		requests := make(map[string][]url.Values)
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			request.ParseForm() //nolint
+			requests[request.URL.Path] = append(requests[request.URL.Path], request.PostForm)
+			writer.Header().Set("Content-Type", "application/json")
+			writer.Write([]byte(`{"ok":true,"channel":"C1","ts":"2.2"}`)) //nolint
+		}))
+		defer server.Close()
+
+		sender := Sender{DataBase: dataBase}
+		err := sender.Init(map[string]interface{}{"api_token": "123", "threads": "trigger"}, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+		sender.client = slack_client.New("123", slack_client.OptionAPIURL(server.URL+"/"))
+
+		contact := moira.ContactData{ID: "contact-id", Value: "#alerts", Type: "slack"}
+		trigger := moira.TriggerData{ID: "trigger-id", Name: "Disk usage"}
+		openThread := `{"channel_id":"C1","message_id":"1.1","message":"*ERROR* Disk usage\nevents"}`
+		lock := mock_moira_alert.NewMockLock(mockCtrl)
+		dataBase.EXPECT().NewLock("moira-message-thread:slack:contact-id:trigger-id", gomock.Any()).Return(lock).AnyTimes()
+		var lockErr error
+		lock.EXPECT().Acquire(gomock.Any()).DoAndReturn(func(<-chan struct{}) (<-chan struct{}, error) { return nil, lockErr }).AnyTimes()
+		lock.EXPECT().Release().AnyTimes()
+
+		Convey("Notification is not posted if thread is not locked", func() {
+			lockErr = database.ErrLockAcquireInterrupted
+			events := moira.NotificationEvents{{Metric: "disk", State: moira.StateERROR, OldState: moira.StateOK}}
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(errors.Is(err, database.ErrLockAcquireInterrupted), ShouldBeTrue)
+			So(requests, ShouldBeEmpty)
+		})
+
+		Convey("New incident starts thread", func() {
+			events := moira.NotificationEvents{{Metric: "disk", State: moira.StateERROR, OldState: moira.StateOK}}
+			dataBase.EXPECT().GetMessageThread(messenger, "contact-id:trigger-id").Return("", database.ErrNil)
+			dataBase.EXPECT().GetTriggerLastCheck("trigger-id").Return(moira.CheckData{Metrics: map[string]moira.MetricState{"disk": {State: moira.StateERROR}}}, nil)
+			dataBase.EXPECT().SetMessageThread(messenger, "contact-id:trigger-id", gomock.Any(), 24*time.Hour).Return(nil)
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(requests["/chat.postMessage"], ShouldHaveLength, 1)
+			So(requests["/chat.postMessage"][0].Get("channel"), ShouldEqual, "#alerts")
+			So(requests["/chat.postMessage"][0].Get("thread_ts"), ShouldBeEmpty)
+			So(requests["/chat.update"], ShouldBeEmpty)
+		})
+
+		Convey("State change of open incident is posted to thread", func() {
+			events := moira.NotificationEvents{{Metric: "disk", State: moira.StateWARN, OldState: moira.StateERROR}}
+			dataBase.EXPECT().GetMessageThread(messenger, "contact-id:trigger-id").Return(openThread, nil)
+			dataBase.EXPECT().GetTriggerLastCheck("trigger-id").Return(moira.CheckData{Metrics: map[string]moira.MetricState{"disk": {State: moira.StateWARN}}}, nil)
+			dataBase.EXPECT().SetMessageThread(messenger, "contact-id:trigger-id", openThread, 24*time.Hour).Return(nil)
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(requests["/chat.postMessage"], ShouldHaveLength, 1)
+			So(requests["/chat.postMessage"][0].Get("channel"), ShouldEqual, "C1")
+			So(requests["/chat.postMessage"][0].Get("thread_ts"), ShouldEqual, "1.1")
+			So(requests["/chat.update"], ShouldHaveLength, 1)
+			So(requests["/chat.update"][0].Get("ts"), ShouldEqual, "1.1")
+			So(requests["/chat.update"][0].Get("text"), ShouldEqual, "Current state: *WARN*\n*ERROR* Disk usage\nevents")
+		})
+
+		Convey("Recovery closes incident", func() {
+			events := moira.NotificationEvents{{Metric: "disk", State: moira.StateOK, OldState: moira.StateWARN}}
+			dataBase.EXPECT().GetMessageThread(messenger, "contact-id:trigger-id").Return(openThread, nil)
+			dataBase.EXPECT().GetTriggerLastCheck("trigger-id").Return(moira.CheckData{State: moira.StateOK, Metrics: map[string]moira.MetricState{"disk": {State: moira.StateOK}}}, nil)
+			dataBase.EXPECT().RemoveMessageThread(messenger, "contact-id:trigger-id").Return(nil)
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(requests["/chat.postMessage"][0].Get("thread_ts"), ShouldEqual, "1.1")
+			So(requests["/chat.update"][0].Get("text"), ShouldEqual, "Current state: *OK*\n~*ERROR* Disk usage~\nevents")
+		})
+
+		Convey("Recovery of one metric keeps incident open while other metrics are in problem state", func() {
+			events := moira.NotificationEvents{{Metric: "disk", State: moira.StateOK, OldState: moira.StateWARN}}
+			dataBase.EXPECT().GetMessageThread(messenger, "contact-id:trigger-id").Return(openThread, nil)
+			dataBase.EXPECT().GetTriggerLastCheck("trigger-id").Return(moira.CheckData{State: moira.StateOK, Metrics: map[string]moira.MetricState{
+				"disk":  {State: moira.StateOK},
+				"disk2": {State: moira.StateERROR},
+			}}, nil)
+			dataBase.EXPECT().SetMessageThread(messenger, "contact-id:trigger-id", openThread, 24*time.Hour).Return(nil)
+
+			err = sender.SendEvents(events, contact, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(requests["/chat.update"][0].Get("text"), ShouldEqual, "Current state: *ERROR*\n*ERROR* Disk usage\nevents")
+		})
+	})
+}
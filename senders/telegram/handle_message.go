@@ -5,11 +5,16 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/moira-alert/moira/chatops"
 	"gopkg.in/tucnak/telebot.v2"
 )
 
-// handleMessage handles incoming messages to start sending events to subscribers chats.
+// handleMessage handles incoming messages to start sending events to subscribers chats and to execute bot commands.
 func (sender *Sender) handleMessage(message *telebot.Message) error {
+	if sender.isChatOpsCommand(message) {
+		return sender.handleCommand(message)
+	}
+
 	responseMessage, err := sender.getResponseMessage(message)
 	if err != nil {
 		return err
@@ -46,3 +51,32 @@ func (sender *Sender) getResponseMessage(message *telebot.Message) (string, erro
 	}
 	return "I don't understand you :(", nil
 }
+
+// isChatOpsCommand returns true if message is bot command except /start which registers chat.
+func (sender *Sender) isChatOpsCommand(message *telebot.Message) bool {
+	if sender.commands == nil || message.Sender == nil || !chatops.IsCommand(message.Text) {
+		return false
+	}
+	return !strings.HasPrefix(message.Text, "/start")
+}
+
+// handleCommand executes bot command on behalf of moira user mapped to message sender and replies with result.
+func (sender *Sender) handleCommand(message *telebot.Message) error {
+	response, err := sender.commands.Execute(chatops.Request{
+		User: strconv.FormatInt(message.Sender.ID, 10),
+		Text: message.Text,
+	})
+	if err != nil {
+		response = chatops.Response{Text: "Failed to execute command, please try again later."}
+	}
+
+	if _, sendErr := sender.bot.Send(message.Chat, response.Text); sendErr != nil {
+		return removeTokenFromError(sendErr, sender.bot)
+	}
+	if plots := getPhotoPlots(response.Plots); len(plots) > 0 {
+		if _, sendErr := sender.bot.SendAlbum(message.Chat, prepareAlbum(plots, "")); sendErr != nil {
+			return removeTokenFromError(sendErr, sender.bot)
+		}
+	}
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira/chatops"
 	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
 	. "github.com/smartystreets/goconvey/convey"
 	"gopkg.in/tucnak/telebot.v2"
@@ -143,3 +144,33 @@ func TestGetResponseMessage(t *testing.T) {
 		})
 	})
 }
+
+func TestIsChatOpsCommand(t *testing.T) {
+	Convey("Test chat-ops command detection", t, func() {
+		user := &telebot.User{ID: 123456789, Username: "User"}
+		sender := Sender{}
+
+		Convey("Commands are disabled", func() {
+			So(sender.isChatOpsCommand(&telebot.Message{Sender: user, Text: "/status backend"}), ShouldBeFalse)
+		})
+
+		sender.commands = chatops.NewCommands(chatops.Config{Users: map[string]string{"123456789": "user"}}, nil, nil, nil, nil, nil)
+
+		Convey("Command", func() {
+			So(sender.isChatOpsCommand(&telebot.Message{Sender: user, Text: "/status backend"}), ShouldBeTrue)
+		})
+
+		Convey("Start command registers chat", func() {
+			So(sender.isChatOpsCommand(&telebot.Message{Sender: user, Text: "/start"}), ShouldBeFalse)
+			So(sender.isChatOpsCommand(&telebot.Message{Sender: user, Text: "/start@MoiraBot"}), ShouldBeFalse)
+		})
+
+		Convey("Not a command", func() {
+			So(sender.isChatOpsCommand(&telebot.Message{Sender: user, Text: "hello"}), ShouldBeFalse)
+		})
+
+		Convey("Message without sender", func() {
+			So(sender.isChatOpsCommand(&telebot.Message{Text: "/status backend"}), ShouldBeFalse)
+		})
+	})
+}
@@ -8,6 +8,10 @@ import (
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/audit"
+	"github.com/moira-alert/moira/chatops"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/moira-alert/moira/senders"
 	"github.com/moira-alert/moira/worker"
 	"gopkg.in/tucnak/telebot.v2"
 )
@@ -36,16 +40,25 @@ type config struct {
 	ContactType string `mapstructure:"contact_type"`
 	APIToken    string `mapstructure:"api_token"`
 	FrontURI    string `mapstructure:"front_uri"`
+	// MessageTemplate is a Go template of messages, it is used for contacts without their own template.
+	MessageTemplate string `mapstructure:"message_template"`
+	// ChatOpsUsers maps numeric telegram user ids to moira logins, bot commands are enabled if it is not empty.
+	// Usernames are not used because telegram users can change them. User gets the id in reply to /start in private chat with bot.
+	ChatOpsUsers map[string]string `mapstructure:"chatops_users"`
 }
 
 // Sender implements moira sender interface via telegram.
 type Sender struct {
 	DataBase moira.Database
-	logger   moira.Logger
-	apiToken string
-	frontURI string
-	bot      *telebot.Bot
-	location *time.Location
+	// MetricSourceProvider is used to render plots in responses to bot commands.
+	MetricSourceProvider *metricSource.SourceProvider
+	logger               moira.Logger
+	apiToken             string
+	frontURI             string
+	messageTemplate      senders.MessageTemplate
+	bot                  *telebot.Bot
+	location             *time.Location
+	commands             *chatops.Commands
 }
 
 func removeTokenFromError(err error, bot *telebot.Bot) error {
@@ -72,9 +85,16 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	}
 
 	sender.apiToken = cfg.APIToken
-	sender.frontURI = cfg.FrontURI
-	sender.logger = logger
-	sender.location = location
+	sender.initMessageBuilder(cfg, logger, location)
+	if len(cfg.ChatOpsUsers) > 0 {
+		// Audit records of commands are saved to database only, audit sinks are configured in API
+		auditRecorder := audit.NewRecorder(sender.DataBase, logger, nil, 0)
+		sender.commands = chatops.NewCommands(chatops.Config{
+			Users:    cfg.ChatOpsUsers,
+			FrontURI: cfg.FrontURI,
+			Location: location,
+		}, sender.DataBase, nil, sender.MetricSourceProvider, logger, auditRecorder)
+	}
 	sender.bot, err = telebot.NewBot(telebot.Settings{
 		Token:  sender.apiToken,
 		Poller: &telebot.LongPoller{Timeout: pollerTimeout},
@@ -96,6 +116,29 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	return nil
 }
 
+// InitMessageBuilder reads settings required to build messages without connecting to telegram.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to telegram config: %w", err)
+	}
+	sender.initMessageBuilder(cfg, logger, location)
+	return nil
+}
+
+func (sender *Sender) initMessageBuilder(cfg config, logger moira.Logger, location *time.Location) {
+	sender.frontURI = cfg.FrontURI
+	sender.logger = logger
+	sender.location = location
+	sender.messageTemplate = senders.MessageTemplate{
+		Template: cfg.MessageTemplate,
+		FrontURI: cfg.FrontURI,
+		Location: location,
+		Logger:   logger,
+	}
+}
+
 // runTelebot starts telegram bot and manages bot subscriptions
 // to make sure there is always only one working Poller.
 func (sender *Sender) runTelebot(contactType string) {
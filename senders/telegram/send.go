@@ -9,6 +9,7 @@ import (
 	"gopkg.in/tucnak/telebot.v2"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
 )
 
 type messageType string
@@ -33,8 +34,9 @@ var characterLimits = map[messageType]int{
 
 // SendEvents implements Sender interface Send.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
+	plots = getPhotoPlots(plots)
 	msgType := getMessageType(plots)
-	message := sender.buildMessage(events, trigger, throttled, characterLimits[msgType])
+	message := sender.buildContactMessage(events, contact, trigger, throttled, characterLimits[msgType])
 	sender.logger.Debug().
 		String("chat_id", contact.Value).
 		String("message", message).
@@ -50,6 +52,23 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 	return nil
 }
 
+// BuildMessage builds telegram message or caption of album with plots without sending it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	msgType := getMessageType(getPhotoPlots(plots))
+	return moira.NotificationPayload{
+		ContentType: "text/plain",
+		Body:        sender.buildContactMessage(events, contact, trigger, throttled, characterLimits[msgType]),
+	}, nil
+}
+
+// buildContactMessage populates message template of contact or sender if it is set, otherwise builds default message.
+func (sender *Sender) buildContactMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool, maxChars int) string {
+	if message, ok := sender.messageTemplate.Render(events, contact, trigger, throttled); ok {
+		return senders.TruncateMessage(message, maxChars)
+	}
+	return sender.buildMessage(events, trigger, throttled, maxChars)
+}
+
 func (sender *Sender) buildMessage(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool, maxChars int) string {
 	var buffer bytes.Buffer
 	state := events.GetCurrentState(throttled)
@@ -211,6 +230,17 @@ func (sender *Sender) sendAsAlbum(chat *telebot.Chat, plots [][]byte, caption st
 	return err
 }
 
+// getPhotoPlots returns plots which can be sent as photos, telegram does not show SVG images so they are skipped.
+func getPhotoPlots(plots [][]byte) [][]byte {
+	photoPlots := make([][]byte, 0, len(plots))
+	for _, plot := range plots {
+		if moira.GetPlotFormat(plot) == moira.PlotFormatPNG {
+			photoPlots = append(photoPlots, plot)
+		}
+	}
+	return photoPlots
+}
+
 func getMessageType(plots [][]byte) messageType {
 	if len(plots) > 0 {
 		return Album
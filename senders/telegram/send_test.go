@@ -171,6 +171,16 @@ func TestPrepareAlbum(t *testing.T) {
 	})
 }
 
+func TestGetPhotoPlots(t *testing.T) {
+	Convey("SVG plots are not sent as photos", t, func() {
+		pngPlot := []byte{137, 80, 78, 71}
+		svgPlot := []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`)
+
+		So(getPhotoPlots([][]byte{pngPlot, svgPlot}), ShouldResemble, [][]byte{pngPlot})
+		So(getPhotoPlots([][]byte{svgPlot}), ShouldBeEmpty)
+	})
+}
+
 func TestCheckBrokenContactError(t *testing.T) {
 	logger, _ := logging.ConfigureLog("stdout", "warn", "test", true)
 	Convey("Check broken contact error", t, func() {
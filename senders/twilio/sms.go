@@ -33,6 +33,13 @@ func (sender *twilioSenderSms) SendEvents(events moira.NotificationEvents, conta
 	return nil
 }
 
+func (sender *twilioSenderSms) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	return moira.NotificationPayload{
+		ContentType: "text/plain",
+		Body:        sender.buildMessage(events, trigger, throttled),
+	}, nil
+}
+
 func (sender *twilioSenderSms) buildMessage(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) string {
 	var message bytes.Buffer
 	state := events.GetCurrentState(throttled)
@@ -27,6 +27,7 @@ type Sender struct {
 
 type sendEventsTwilio interface {
 	SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error
+	BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error)
 }
 
 type twilioSender struct {
@@ -65,6 +66,27 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 		logger:       logger,
 		location:     location,
 	}
+	return sender.initSender(cfg, tSender)
+}
+
+// InitMessageBuilder reads settings required to build messages without twilio credentials.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to twilio config: %w", err)
+	}
+
+	tSender := twilioSender{
+		APIFromPhone: cfg.APIFromPhone,
+		logger:       logger,
+		location:     location,
+	}
+	return sender.initSender(cfg, tSender)
+}
+
+func (sender *Sender) initSender(cfg config, tSender twilioSender) error {
+	apiType := cfg.Type
 	switch apiType {
 	case "twilio sms":
 		sender.sender = &twilioSenderSms{tSender}
@@ -94,3 +116,8 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
 	return sender.sender.SendEvents(events, contact, trigger, plots, throttled)
 }
+
+// BuildMessage builds sms text or callback url of voice call without sending it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	return sender.sender.BuildMessage(events, contact, trigger, plots, throttled)
+}
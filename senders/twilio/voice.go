@@ -31,6 +31,13 @@ func (sender *twilioSenderVoice) SendEvents(events moira.NotificationEvents, con
 	return nil
 }
 
+func (sender *twilioSenderVoice) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	return moira.NotificationPayload{
+		ContentType: "text/uri-list",
+		Body:        sender.buildVoiceURL(trigger),
+	}, nil
+}
+
 func (sender *twilioSenderVoice) buildVoiceURL(trigger moira.TriggerData) string {
 	message := fmt.Sprintf("Hi! This is a notification for Moira trigger %s. Please, visit Moira web interface for details.", trigger.Name)
 	voiceURL := sender.voiceURL
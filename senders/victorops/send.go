@@ -1,6 +1,7 @@
 package victorops
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -21,6 +22,20 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 	return nil
 }
 
+// BuildMessage builds victorops alert request without sending it, plots are not uploaded to image store.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	createAlertRequest := sender.buildCreateAlertRequest(events, trigger, throttled, nil, time.Now().Unix())
+	body, err := json.Marshal(createAlertRequest)
+	if err != nil {
+		return moira.NotificationPayload{}, fmt.Errorf("failed to marshal victorops alert request: %w", err)
+	}
+	return moira.NotificationPayload{
+		ContentType: "application/json",
+		Title:       createAlertRequest.EntityDisplayName,
+		Body:        string(body),
+	}, nil
+}
+
 func (sender *Sender) buildCreateAlertRequest(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool, plots [][]byte, time int64) api.CreateAlertRequest {
 	triggerURI := trigger.GetTriggerURI(sender.frontURI)
 
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"html"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/templating"
@@ -35,8 +37,17 @@ func (sender *Sender) buildRequest(events moira.NotificationEvents, contact moir
 		request.SetBasicAuth(sender.user, sender.password)
 	}
 
+	templateContact := contact.ToTemplateContact()
 	for k, v := range sender.headers {
-		request.Header.Set(k, v)
+		value, err := populateTemplate(v, templateContact)
+		if err != nil {
+			return request, fmt.Errorf("failed to populate header %s: %w", k, err)
+		}
+		request.Header.Set(k, value)
+	}
+
+	if signingSecret := sender.getSigningSecret(contact); signingSecret != "" {
+		signRequest(request, signingSecret, time.Now().Unix(), requestBody)
 	}
 
 	sender.log.Debug().
@@ -59,13 +70,24 @@ func (sender *Sender) buildRequestBody(
 		return buildDefaultRequestBody(events, contact, trigger, plots, throttled)
 	}
 
-	webhookBodyPopulater := templating.NewWebhookBodyPopulater(contact.ToTemplateContact())
-	populatedBody, err := webhookBodyPopulater.Populate(sender.body)
+	populatedBody, err := populateTemplate(sender.body, contact.ToTemplateContact())
 	if err != nil {
 		return nil, err
 	}
 
-	return []byte(html.UnescapeString(populatedBody)), nil
+	return []byte(populatedBody), nil
+}
+
+// populateTemplate renders webhook template variables in body or header value.
+// It is not used for url, because its values are not escaped, url uses escaped ${...} variables only.
+func populateTemplate(tmpl string, contact *templating.Contact) (string, error) {
+	webhookBodyPopulater := templating.NewWebhookBodyPopulater(contact)
+	populated, err := webhookBodyPopulater.Populate(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	return html.UnescapeString(populated), nil
 }
 
 func buildDefaultRequestBody(
@@ -266,6 +266,33 @@ var testContactWithURL = moira.ContactData{
 	Team:  "contactTeam",
 }
 
+func TestBuildRequestURLIsNotTemplated(t *testing.T) {
+	Convey("Contact value can not inject url parts", t, func() {
+		contact := moira.ContactData{Type: testContactType, Value: "a/../b?c=d#e"}
+		sender := Sender{}
+
+		Convey("Variables are escaped", func() {
+			err := sender.Init(map[string]interface{}{"url": "https://example.com/${contact_value}"}, logger, location, dateTimeFormat)
+			So(err, ShouldBeNil)
+
+			request, err := sender.buildRequest(testEvents, contact, testTrigger, testPlot, testThrottled)
+			So(err, ShouldBeNil)
+			So(request.URL.EscapedPath(), ShouldEqual, "/a%2F..%2Fb%3Fc=d%23e")
+			So(request.URL.RawQuery, ShouldBeEmpty)
+			So(request.URL.Fragment, ShouldBeEmpty)
+		})
+
+		Convey("Templates are not populated", func() {
+			err := sender.Init(map[string]interface{}{"url": "https://example.com/{{ .Contact.Value }}"}, logger, location, dateTimeFormat)
+			So(err, ShouldBeNil)
+
+			request, err := sender.buildRequest(testEvents, contact, testTrigger, testPlot, testThrottled)
+			So(err, ShouldBeNil)
+			So(request.URL.Path, ShouldEqual, "/{{ .Contact.Value }}")
+		})
+	})
+}
+
 func TestBuildRequestURL_FromContactValueWithURL(t *testing.T) {
 	Convey("URL should contain variables values", t, func() {
 		actual := buildRequestURL("${contact_value}", testTrigger, testContactWithURL)
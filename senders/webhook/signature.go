@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/moira-alert/moira"
+)
+
+const (
+	// SignatureHeader contains HMAC-SHA256 of timestamp and request body in format sha256=<hex>.
+	SignatureHeader = "X-Moira-Signature"
+	// TimestampHeader contains unix time of request, it is signed together with request body.
+	TimestampHeader = "X-Moira-Timestamp"
+
+	signaturePrefix = "sha256="
+)
+
+// getSigningSecret returns signing secret of contact or signing secret of sender if contact doesn't have its own.
+func (sender *Sender) getSigningSecret(contact moira.ContactData) string {
+	if contact.SigningSecret != "" {
+		return contact.SigningSecret
+	}
+	return sender.signingSecret
+}
+
+// signRequest adds timestamp and signature headers to request.
+func signRequest(request *http.Request, secret string, timestamp int64, body []byte) {
+	timestampString := strconv.FormatInt(timestamp, 10)
+	request.Header.Set(TimestampHeader, timestampString)
+	request.Header.Set(SignatureHeader, signaturePrefix+computeSignature(secret, timestampString, body))
+}
+
+// computeSignature returns hex encoded HMAC-SHA256 of "<timestamp>.<body>" with given secret.
+func computeSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp)) //nolint
+	mac.Write([]byte("."))       //nolint
+	mac.Write(body)              //nolint
+	return hex.EncodeToString(mac.Sum(nil))
+}
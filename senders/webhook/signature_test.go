@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSignRequest(t *testing.T) {
+	Convey("Test request signature", t, func() {
+		body := []byte(`{"trigger": "triggerID"}`)
+		request, err := http.NewRequest(http.MethodPost, testURL, nil)
+		So(err, ShouldBeNil)
+
+		signRequest(request, "secret", 1590741878, body)
+
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(`1590741878.{"trigger": "triggerID"}`)) //nolint
+		So(request.Header.Get(TimestampHeader), ShouldEqual, "1590741878")
+		So(request.Header.Get(SignatureHeader), ShouldEqual, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	})
+}
+
+func TestBuildSignedRequest(t *testing.T) {
+	Convey("Test building of signed request", t, func() {
+		sender := Sender{}
+		err := sender.Init(map[string]interface{}{
+			"url":            testURL,
+			"signing_secret": "sender-secret",
+			"headers": map[string]string{
+				"X-Contact": "{{ .Contact.Type }}-{{ .Contact.Value }}",
+			},
+		}, logger, location, dateTimeFormat)
+		So(err, ShouldBeNil)
+
+		Convey("With signing secret of sender", func() {
+			request, err := sender.buildRequest(testEvents, testContact, testTrigger, testPlot, testThrottled)
+			So(err, ShouldBeNil)
+
+			body, err := io.ReadAll(request.Body)
+			So(err, ShouldBeNil)
+			timestamp := request.Header.Get(TimestampHeader)
+			So(timestamp, ShouldNotBeEmpty)
+			So(request.Header.Get(SignatureHeader), ShouldEqual, "sha256="+computeSignature("sender-secret", timestamp, body))
+			So(request.Header.Get("X-Contact"), ShouldEqual, "contactType-contactValue")
+		})
+
+		Convey("With signing secret of contact", func() {
+			contact := testContact
+			contact.SigningSecret = "contact-secret"
+			request, err := sender.buildRequest(testEvents, contact, testTrigger, testPlot, testThrottled)
+			So(err, ShouldBeNil)
+
+			body, err := io.ReadAll(request.Body)
+			So(err, ShouldBeNil)
+			So(request.Header.Get(SignatureHeader), ShouldEqual, "sha256="+computeSignature("contact-secret", request.Header.Get(TimestampHeader), body))
+		})
+
+		Convey("Without signing secret", func() {
+			sender.signingSecret = ""
+			request, err := sender.buildRequest(testEvents, moira.ContactData{}, testTrigger, testPlot, testThrottled)
+			So(err, ShouldBeNil)
+			So(request.Header.Get(SignatureHeader), ShouldBeEmpty)
+			So(request.Header.Get(TimestampHeader), ShouldBeEmpty)
+		})
+	})
+}
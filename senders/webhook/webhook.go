@@ -1,6 +1,7 @@
 package webhook
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/moira-alert/moira"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 var ErrMissingURL = errors.New("can not read url from config")
@@ -21,17 +24,30 @@ type config struct {
 	User     string            `mapstructure:"user"`
 	Password string            `mapstructure:"password"`
 	Timeout  int               `mapstructure:"timeout"`
+	// SigningSecret is a key of request signature used for contacts without own signing secret.
+	SigningSecret string `mapstructure:"signing_secret"`
+	// OAuth2 enables fetching of access token with client credentials grant.
+	OAuth2 oauth2Config `mapstructure:"oauth2"`
+}
+
+// Structure that represents the OAuth2 client credentials configuration of webhook.
+type oauth2Config struct {
+	TokenURL     string   `mapstructure:"token_url"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	Scopes       []string `mapstructure:"scopes"`
 }
 
 // Sender implements moira sender interface via webhook.
 type Sender struct {
-	url      string
-	body     string
-	user     string
-	password string
-	headers  map[string]string
-	client   *http.Client
-	log      moira.Logger
+	url           string
+	body          string
+	user          string
+	password      string
+	headers       map[string]string
+	signingSecret string
+	client        *http.Client
+	log           moira.Logger
 }
 
 // Init read yaml config.
@@ -42,6 +58,54 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 		return fmt.Errorf("failed to decode senderSettings to webhook config: %w", err)
 	}
 
+	if err = sender.initMessageBuilder(cfg, logger); err != nil {
+		return err
+	}
+
+	var timeout int
+	if cfg.Timeout != 0 {
+		timeout = cfg.Timeout
+	} else {
+		timeout = 30
+	}
+
+	sender.client = &http.Client{
+		Timeout:   time.Duration(timeout) * time.Second,
+		Transport: &http.Transport{DisableKeepAlives: true},
+	}
+
+	if cfg.OAuth2.TokenURL != "" {
+		sender.client = newOAuth2Client(cfg.OAuth2, sender.client)
+	}
+
+	return nil
+}
+
+// newOAuth2Client creates client which adds access token to requests, token is fetched with base client and cached until it expires.
+func newOAuth2Client(cfg oauth2Config, base *http.Client) *http.Client {
+	credentials := clientcredentials.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		TokenURL:     cfg.TokenURL,
+		Scopes:       cfg.Scopes,
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, base)
+	client := credentials.Client(ctx)
+	client.Timeout = base.Timeout
+	return client
+}
+
+// InitMessageBuilder reads settings required to build request body.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	err := mapstructure.Decode(senderSettings, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to decode senderSettings to webhook config: %w", err)
+	}
+	return sender.initMessageBuilder(cfg, logger)
+}
+
+func (sender *Sender) initMessageBuilder(cfg config, logger moira.Logger) error {
 	sender.url = cfg.URL
 	if sender.url == "" {
 		return ErrMissingURL
@@ -50,6 +114,7 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 	sender.body = cfg.Body
 
 	sender.user, sender.password = cfg.User, cfg.Password
+	sender.signingSecret = cfg.SigningSecret
 
 	sender.headers = map[string]string{
 		"User-Agent":   "Moira",
@@ -60,22 +125,23 @@ func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, loca
 		sender.headers[header] = value
 	}
 
-	var timeout int
-	if cfg.Timeout != 0 {
-		timeout = cfg.Timeout
-	} else {
-		timeout = 30
-	}
-
 	sender.log = logger
-	sender.client = &http.Client{
-		Timeout:   time.Duration(timeout) * time.Second,
-		Transport: &http.Transport{DisableKeepAlives: true},
-	}
-
 	return nil
 }
 
+// BuildMessage builds request body without sending it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	body, err := sender.buildRequestBody(events, contact, trigger, plots, throttled)
+	if err != nil {
+		return moira.NotificationPayload{}, err
+	}
+	return moira.NotificationPayload{
+		ContentType: sender.headers["Content-Type"],
+		Title:       buildRequestURL(sender.url, trigger, contact),
+		Body:        string(body),
+	}, nil
+}
+
 // SendEvents implements Sender interface Send.
 func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
 	request, err := sender.buildRequest(events, contact, trigger, plots, throttled)
@@ -138,6 +138,71 @@ func TestSender_SendEvents(t *testing.T) {
 	})
 }
 
+func TestSender_SendEventsWithOAuth2(t *testing.T) {
+	Convey("Send request with access token fetched by client credentials", t, func() {
+		tokenRequests := 0
+		tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenRequests++
+			user, password, _ := r.BasicAuth()
+			r.ParseForm() //nolint
+			if user != "client" || password != "secret" || r.Form.Get("grant_type") != "client_credentials" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token": "token", "token_type": "bearer", "expires_in": 3600}`)) //nolint
+		}))
+		defer tokenServer.Close()
+
+		authorizations := make([]string, 0)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorizations = append(authorizations, r.Header.Get("Authorization"))
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer ts.Close()
+
+		sender := Sender{}
+		err := sender.Init(map[string]interface{}{
+			"url": ts.URL,
+			"oauth2": map[string]interface{}{
+				"token_url":     tokenServer.URL,
+				"client_id":     "client",
+				"client_secret": "secret",
+			},
+		}, logger, location, dateTimeFormat)
+		So(err, ShouldBeNil)
+
+		err = sender.SendEvents(testEvents, testContact, testTrigger, testPlot, false)
+		So(err, ShouldBeNil)
+		err = sender.SendEvents(testEvents, testContact, testTrigger, testPlot, false)
+		So(err, ShouldBeNil)
+
+		So(authorizations, ShouldResemble, []string{"Bearer token", "Bearer token"})
+		So(tokenRequests, ShouldEqual, 1)
+	})
+}
+
+func TestSender_BuildMessage(t *testing.T) {
+	Convey("Build webhook request without sending", t, func() {
+		senderSettings := map[string]interface{}{
+			"url":  fmt.Sprintf("%s/%s", testURL, moira.VariableTriggerID),
+			"body": "{\"contact\": \"{{ .Contact.Value }}\"}",
+		}
+		sender := Sender{}
+		err := sender.InitMessageBuilder(senderSettings, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+		So(sender.client, ShouldBeNil)
+
+		payload, err := sender.BuildMessage(testEvents, testContact, testTrigger, testPlot, false)
+		So(err, ShouldBeNil)
+		So(payload, ShouldResemble, moira.NotificationPayload{
+			ContentType: "application/json",
+			Title:       fmt.Sprintf("%s/%s", testURL, url.PathEscape(testTrigger.ID)),
+			Body:        fmt.Sprintf("{\"contact\": \"%s\"}", testContact.Value),
+		})
+	})
+}
+
 func testRequestURL(r *http.Request) (int, error) {
 	actualPath := r.URL.EscapedPath()
 	expectedPath := fmt.Sprintf("/%s", url.PathEscape(testTrigger.ID))
@@ -0,0 +1,229 @@
+// Package zulip is Moira sender for Zulip streams and direct messages, it posts messages via REST API of Zulip server.
+package zulip
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const (
+	messageMaxCharacters = 9_000
+	topicMaxCharacters   = 60
+	requestTimeout       = 30 * time.Second
+	defaultTopic         = "Moira"
+	topicSeparator       = "/"
+)
+
+// brokenContactErrorCodes are codes of Zulip API errors meaning that stream or user doesn't exist or bot has no access to it.
+var brokenContactErrorCodes = []string{"STREAM_DOES_NOT_EXIST", "UNAUTHORIZED_PRINCIPAL"}
+
+// Structure that represents the Zulip configuration in the YAML file.
+type config struct {
+	URL      string `mapstructure:"url"`
+	Email    string `mapstructure:"email"`
+	APIKey   string `mapstructure:"api_key"`
+	FrontURI string `mapstructure:"front_uri"`
+	// MessageTemplate is a Go template of messages, it is used for contacts without their own template.
+	MessageTemplate string `mapstructure:"message_template"`
+}
+
+// Sender posts messages to Zulip.
+// Contact value is either comma separated emails of users to send direct message to,
+// or stream name with optional topic separated by slash, e.g. "alerts/backend".
+// Messages are posted to topic named after trigger if topic is not set.
+type Sender struct {
+	url            string
+	email          string
+	apiKey         string
+	messageBuilder senders.ChatMessageBuilder
+	client         *senders.ChatClient
+	logger         moira.Logger
+}
+
+type uploadResponse struct {
+	URI string `json:"uri"`
+}
+
+type errorResponse struct {
+	Code string `json:"code"`
+}
+
+// Init configures Sender.
+func (sender *Sender) Init(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	if err := mapstructure.Decode(senderSettings, &cfg); err != nil {
+		return fmt.Errorf("failed to decode senderSettings to zulip config: %w", err)
+	}
+
+	if cfg.URL == "" {
+		return fmt.Errorf("can not read Zulip url from config")
+	}
+	if cfg.Email == "" || cfg.APIKey == "" {
+		return fmt.Errorf("can not read Zulip email and api_key from config")
+	}
+
+	sender.url = strings.TrimSuffix(cfg.URL, "/")
+	sender.email = cfg.Email
+	sender.apiKey = cfg.APIKey
+	sender.client = senders.NewChatClient(requestTimeout)
+	sender.initMessageBuilder(cfg, logger, location)
+	return nil
+}
+
+// InitMessageBuilder reads settings required to build messages without connecting to Zulip.
+func (sender *Sender) InitMessageBuilder(senderSettings interface{}, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	var cfg config
+	if err := mapstructure.Decode(senderSettings, &cfg); err != nil {
+		return fmt.Errorf("failed to decode senderSettings to zulip config: %w", err)
+	}
+	sender.initMessageBuilder(cfg, logger, location)
+	return nil
+}
+
+func (sender *Sender) initMessageBuilder(cfg config, logger moira.Logger, location *time.Location) {
+	sender.logger = logger
+	sender.messageBuilder = senders.ChatMessageBuilder{
+		Markup:   senders.MarkdownChatMarkup,
+		FrontURI: cfg.FrontURI,
+		Location: location,
+		MaxChars: messageMaxCharacters,
+		Template: senders.MessageTemplate{
+			Template: cfg.MessageTemplate,
+			FrontURI: cfg.FrontURI,
+			Location: location,
+			MaxChars: messageMaxCharacters,
+			Logger:   logger,
+		},
+	}
+}
+
+// BuildMessage builds Zulip message without sending it.
+func (sender *Sender) BuildMessage(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) (moira.NotificationPayload, error) {
+	return moira.NotificationPayload{
+		ContentType: "text/markdown",
+		Title:       buildTopic(contact.Value, trigger),
+		Body:        sender.messageBuilder.BuildContactMessage(events, contact, trigger, throttled),
+	}, nil
+}
+
+// SendEvents implements Sender interface Send.
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, plots [][]byte, throttled bool) error {
+	message := sender.messageBuilder.BuildContactMessage(events, contact, trigger, throttled)
+	for _, plot := range plots {
+		plotURI, err := sender.uploadPlot(trigger.ID, plot)
+		if err != nil {
+			sender.logger.Warning().
+				String(moira.LogFieldNameTriggerID, trigger.ID).
+				String("contact_value", contact.Value).
+				Error(err).
+				Msg("Failed to upload plot to Zulip")
+			continue
+		}
+		message += fmt.Sprintf("\n[%s](%s)", senders.PlotFileName(trigger.ID, plot), plotURI)
+	}
+
+	if err := sender.sendMessage(contact.Value, buildTopic(contact.Value, trigger), message); err != nil {
+		return senders.WrapChatError(err, fmt.Sprintf("failed to send %s event message to Zulip [%s]", trigger.ID, contact.Value))
+	}
+	return nil
+}
+
+func (sender *Sender) sendMessage(contact, topic, message string) error {
+	form := url.Values{}
+	form.Set("content", message)
+	if isDirectMessage(contact) {
+		form.Set("type", "private")
+		form.Set("to", contact)
+	} else {
+		stream, _, _ := strings.Cut(contact, topicSeparator)
+		form.Set("type", "stream")
+		form.Set("to", stream)
+		form.Set("topic", topic)
+	}
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, sender.url+"/api/v1/messages", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth(sender.email, sender.apiKey)
+
+	return checkBrokenContactError(sender.client.Do(request, nil))
+}
+
+// uploadPlot uploads plot to Zulip server, uploaded image is previewed when its link is posted.
+func (sender *Sender) uploadPlot(triggerID string, plot []byte) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", senders.PlotFileName(triggerID, plot))
+	if err != nil {
+		return "", err
+	}
+	if _, err = part.Write(plot); err != nil {
+		return "", err
+	}
+	if err = writer.Close(); err != nil {
+		return "", err
+	}
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, sender.url+"/api/v1/user_uploads", &body)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	request.SetBasicAuth(sender.email, sender.apiKey)
+
+	var response uploadResponse
+	if err = sender.client.Do(request, &response); err != nil {
+		return "", err
+	}
+	return response.URI, nil
+}
+
+// buildTopic returns topic set in contact or trigger name, topic is cut to the Zulip limit.
+func buildTopic(contact string, trigger moira.TriggerData) string {
+	if isDirectMessage(contact) {
+		return ""
+	}
+	if _, topic, ok := strings.Cut(contact, topicSeparator); ok && topic != "" {
+		return senders.TruncateMessage(topic, topicMaxCharacters)
+	}
+	if trigger.Name != "" {
+		return senders.TruncateMessage(trigger.Name, topicMaxCharacters)
+	}
+	return defaultTopic
+}
+
+func isDirectMessage(contact string) bool {
+	return strings.Contains(contact, "@")
+}
+
+// checkBrokenContactError converts errors about missing stream or user to moira.SenderBrokenContactError.
+func checkBrokenContactError(err error) error {
+	var responseErr senders.ChatResponseError
+	if err == nil || !errors.As(err, &responseErr) || responseErr.StatusCode != http.StatusBadRequest {
+		return err
+	}
+	var response errorResponse
+	if json.Unmarshal([]byte(responseErr.Body), &response) != nil {
+		return err
+	}
+	for _, code := range brokenContactErrorCodes {
+		if response.Code == code {
+			return moira.NewSenderBrokenContactError(err)
+		}
+	}
+	return err
+}
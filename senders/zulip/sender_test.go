@@ -0,0 +1,101 @@
+package zulip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInit(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+
+	Convey("Init zulip sender", t, func() {
+		sender := &Sender{}
+
+		Convey("Without credentials", func() {
+			err := sender.Init(map[string]interface{}{"url": "https://zulip.example.com"}, logger, time.UTC, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("With full config", func() {
+			settings := map[string]interface{}{"url": "https://zulip.example.com/", "email": "bot@example.com", "api_key": "key"}
+			err := sender.Init(settings, logger, time.UTC, "")
+			So(err, ShouldBeNil)
+			So(sender.url, ShouldEqual, "https://zulip.example.com")
+		})
+	})
+}
+
+func TestBuildTopic(t *testing.T) {
+	Convey("Build topic", t, func() {
+		trigger := moira.TriggerData{Name: "Name"}
+		So(buildTopic("alerts/backend", trigger), ShouldEqual, "backend")
+		So(buildTopic("alerts", trigger), ShouldEqual, "Name")
+		So(buildTopic("alerts", moira.TriggerData{}), ShouldEqual, defaultTopic)
+		So(buildTopic("alerts", moira.TriggerData{Name: strings.Repeat("a", 100)}), ShouldHaveLength, topicMaxCharacters)
+		So(buildTopic("user@example.com", trigger), ShouldEqual, "")
+	})
+}
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "debug", "test", true)
+	events := moira.NotificationEvents{{Metric: "metric", OldState: moira.StateOK, State: moira.StateERROR}}
+	trigger := moira.TriggerData{ID: "triggerID", Name: "Name"}
+
+	Convey("Send events to Zulip", t, func() {
+		var sentMessage url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if user, password, _ := r.BasicAuth(); user != "bot@example.com" || password != "key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			switch r.URL.Path {
+			case "/api/v1/user_uploads":
+				w.Write([]byte(`{"result":"success","uri":"/user_uploads/1/plot.png"}`)) //nolint
+			case "/api/v1/messages":
+				r.ParseForm() //nolint
+				sentMessage = r.PostForm
+				if r.PostForm.Get("to") == "missing" {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"result":"error","msg":"Stream 'missing' does not exist","code":"STREAM_DOES_NOT_EXIST"}`)) //nolint
+					return
+				}
+				w.Write([]byte(`{"result":"success","id":42}`)) //nolint
+			}
+		}))
+		defer server.Close()
+
+		sender := &Sender{}
+		settings := map[string]interface{}{"url": server.URL, "email": "bot@example.com", "api_key": "key"}
+		err := sender.Init(settings, logger, time.UTC, "")
+		So(err, ShouldBeNil)
+
+		Convey("Message with plot is sent to stream topic", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "alerts/backend"}, trigger, [][]byte{[]byte("\x89PNG\r\n\x1a\n")}, false)
+			So(err, ShouldBeNil)
+			So(sentMessage.Get("type"), ShouldEqual, "stream")
+			So(sentMessage.Get("to"), ShouldEqual, "alerts")
+			So(sentMessage.Get("topic"), ShouldEqual, "backend")
+			So(sentMessage.Get("content"), ShouldEndWith, "\n[triggerID.png](/user_uploads/1/plot.png)")
+		})
+
+		Convey("Direct message is sent to users", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "user@example.com"}, trigger, nil, false)
+			So(err, ShouldBeNil)
+			So(sentMessage.Get("type"), ShouldEqual, "private")
+			So(sentMessage.Get("to"), ShouldEqual, "user@example.com")
+		})
+
+		Convey("Missing stream is broken contact", func() {
+			err = sender.SendEvents(events, moira.ContactData{Value: "missing"}, trigger, nil, false)
+			So(err, ShouldHaveSameTypeAs, moira.SenderBrokenContactError{})
+		})
+	})
+}
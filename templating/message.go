@@ -0,0 +1,105 @@
+package templating
+
+import (
+	"fmt"
+)
+
+// MessageTrigger represents a template trigger with fields allowed for use in message templates.
+type MessageTrigger struct {
+	ID          string
+	Name        string
+	Description string
+	Tags        []string
+	URI         string
+	WarnValue   float64
+	ErrorValue  float64
+}
+
+// MessageEvent represents a template event with fields allowed for use in message templates.
+type MessageEvent struct {
+	Event
+	OldState     string
+	Values       map[string]float64
+	ValuesString string
+	Time         string
+	Message      string
+}
+
+// MessageData is the data shared by message templates of all senders.
+type MessageData struct {
+	Trigger   MessageTrigger
+	Contact   Contact
+	Events    []MessageEvent
+	State     string
+	Throttled bool
+}
+
+type messagePopulater struct {
+	data     *MessageData
+	populate func(tmpl string, data any) (string, error)
+}
+
+// NewMessagePopulater creates a new populater of plain text or markdown messages with the given message data.
+func NewMessagePopulater(data *MessageData) *messagePopulater {
+	return &messagePopulater{
+		data:     data,
+		populate: populateText,
+	}
+}
+
+// NewHTMLMessagePopulater creates a new populater of html messages with the given message data, data is escaped.
+func NewHTMLMessagePopulater(data *MessageData) *messagePopulater {
+	return &messagePopulater{
+		data:     data,
+		populate: populate,
+	}
+}
+
+// Populate populates the given template with message data.
+func (templateData *messagePopulater) Populate(tmpl string) (string, error) {
+	return templateData.populate(tmpl, templateData.data)
+}
+
+// ValidateMessageTemplate checks that the given message template can be populated with sample message data.
+func ValidateMessageTemplate(tmpl string) error {
+	if _, err := NewMessagePopulater(SampleMessageData()).Populate(tmpl); err != nil {
+		return fmt.Errorf("failed to populate message template: %w", err)
+	}
+	return nil
+}
+
+// SampleMessageData returns message data used to validate and preview message templates.
+func SampleMessageData() *MessageData {
+	value := 97.0
+	return &MessageData{
+		Trigger: MessageTrigger{
+			ID:          "5ff37996-8927-4cab-8987-970e80d8e0a8",
+			Name:        "Not enough disk space left",
+			Description: "Check the size of /var/log",
+			Tags:        []string{"server", "disk"},
+			URI:         "https://moira.example.com/trigger/5ff37996-8927-4cab-8987-970e80d8e0a8",
+			WarnValue:   90,
+			ErrorValue:  95,
+		},
+		Contact: Contact{
+			Type:  "slack",
+			Value: "#alerts",
+		},
+		Events: []MessageEvent{
+			{
+				Event: Event{
+					Metric:         "server.hdd.used_percent",
+					MetricElements: []string{"server", "hdd", "used_percent"},
+					Timestamp:      1590741878,
+					Value:          &value,
+					State:          "ERROR",
+				},
+				OldState:     "WARN",
+				Values:       map[string]float64{"t1": value},
+				ValuesString: "97",
+				Time:         "08:44",
+			},
+		},
+		State: "ERROR",
+	}
+}
@@ -0,0 +1,74 @@
+package templating
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_TemplateMessage(t *testing.T) {
+	Convey("Test message populater", t, func() {
+		data := SampleMessageData()
+
+		Convey("Test plain text template", func() {
+			template := "" +
+				"{{ .State }} {{ .Trigger.Name }} [{{ join \",\" .Trigger.Tags }}]\n" +
+				"{{ range .Events }}{{ .Time }}: {{ .Metric }} = {{ .ValuesString }} ({{ .OldState }} to {{ .State }})\n{{ end }}" +
+				"{{ .Trigger.URI }}"
+			expected := "" +
+				"ERROR Not enough disk space left [server,disk]\n" +
+				"08:44: server.hdd.used_percent = 97 (WARN to ERROR)\n" +
+				"https://moira.example.com/trigger/5ff37996-8927-4cab-8987-970e80d8e0a8"
+
+			actual, err := NewMessagePopulater(data).Populate(template)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, expected)
+		})
+
+		Convey("Test plain text template does not escape data", func() {
+			data.Trigger.Name = "<b>disk</b> & more"
+
+			actual, err := NewMessagePopulater(data).Populate("{{ .Trigger.Name }}")
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, "<b>disk</b> & more")
+		})
+
+		Convey("Test html template escapes data", func() {
+			data.Trigger.Name = "<b>disk</b> & more"
+
+			actual, err := NewHTMLMessagePopulater(data).Populate("<p>{{ .Trigger.Name }}</p>")
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, "<p>&lt;b&gt;disk&lt;/b&gt; &amp; more</p>")
+		})
+
+		Convey("Test event methods are available", func() {
+			actual, err := NewMessagePopulater(data).Populate("{{ range .Events }}{{ .TimestampDecrease 60 }}{{ end }}")
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, "1590741818")
+		})
+
+		Convey("Test wrong template returns template as is", func() {
+			template := "{{ .Trigger.Unknown }}"
+
+			actual, err := NewMessagePopulater(data).Populate(template)
+			So(err, ShouldNotBeNil)
+			So(actual, ShouldResemble, template)
+		})
+	})
+}
+
+func TestValidateMessageTemplate(t *testing.T) {
+	Convey("Test ValidateMessageTemplate", t, func() {
+		Convey("Valid template", func() {
+			So(ValidateMessageTemplate("{{ .Trigger.Name }}: {{ len .Events }} events"), ShouldBeNil)
+		})
+
+		Convey("Template with syntax error", func() {
+			So(ValidateMessageTemplate("{{ .Trigger.Name "), ShouldNotBeNil)
+		})
+
+		Convey("Template with unknown field", func() {
+			So(ValidateMessageTemplate("{{ .Unknown }}"), ShouldNotBeNil)
+		})
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
+	"io"
 	"strings"
+	textTemplate "text/template"
 	"time"
 
 	"github.com/Masterminds/sprig/v3"
@@ -235,7 +237,29 @@ type Populater interface {
 	Populate(template string) (string, error)
 }
 
-func populate(tmpl string, data any) (populatedTemplate string, err error) {
+// executableTemplate is implemented by both html and text templates.
+type executableTemplate interface {
+	Execute(writer io.Writer, data any) error
+}
+
+// populate populates template escaping data for use in html.
+func populate(tmpl string, data any) (string, error) {
+	return execute(tmpl, data, func(tmpl string) (executableTemplate, error) {
+		return template.New("populate-template").Funcs(sprigFuncMap).Funcs(funcMap).Parse(tmpl)
+	})
+}
+
+// populateText populates template without escaping data, it is used for plain text and markdown messages.
+func populateText(tmpl string, data any) (string, error) {
+	return execute(tmpl, data, func(tmpl string) (executableTemplate, error) {
+		return textTemplate.New("populate-template").
+			Funcs(textTemplate.FuncMap(sprigFuncMap)).
+			Funcs(textTemplate.FuncMap(funcMap)).
+			Parse(tmpl)
+	})
+}
+
+func execute(tmpl string, data any, parse func(tmpl string) (executableTemplate, error)) (populatedTemplate string, err error) {
 	defer func() {
 		if errRecover := recover(); errRecover != nil {
 			populatedTemplate = tmpl
@@ -245,9 +269,8 @@ func populate(tmpl string, data any) (populatedTemplate string, err error) {
 
 	buffer := bytes.Buffer{}
 
-	template := template.New("populate-template").Funcs(sprigFuncMap).Funcs(funcMap)
-
-	if template, err = template.Parse(tmpl); err != nil {
+	template, err := parse(tmpl)
+	if err != nil {
 		return tmpl, err
 	}
 